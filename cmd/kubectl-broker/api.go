@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/backup"
+)
+
+var (
+	apiNamespace   string
+	apiStatefulSet string
+	apiPodName     string
+	apiUsername    string
+	apiPassword    string
+	apiData        string
+	apiInclude     bool
+)
+
+func newAPICommand() *cobra.Command {
+	var apiCmd = &cobra.Command{
+		Use:   "api METHOD PATH",
+		Short: "Send a raw request to the HiveMQ management API",
+		Long: `Send an HTTP request to the HiveMQ management API through a port-forward and
+print the raw response body. This reaches management endpoints that have no
+dedicated command yet, without setting up 'kubectl port-forward' by hand.
+
+The request goes to the API service of the StatefulSet, or with --pod directly
+to one broker pod. --username/--password and the global --api-tls,
+--api-ca-cert, --api-retries and bearer token (--api-token, --api-token-file,
+--api-oauth-*) flags apply as for the backup commands. Only
+GET, HEAD and OPTIONS requests are retried. Requests that may change state are
+not sent with the global --dry-run flag.
+
+--data sends a JSON request body; '@file' reads it from a file and '@-' from
+stdin. The command fails after printing the body when the API answers with an
+error status.
+
+Examples:
+  # List backups
+  kubectl broker api GET /api/v1/management/backups
+
+  # Show the status line and headers too
+  kubectl broker api GET /api/v1/management/backups -i
+
+  # Ask one broker pod directly
+  kubectl broker api GET /api/v1/management/backups --pod broker-1
+
+  # Send a request body from a file
+  kubectl broker api POST /api/v1/management/backups --data @request.json`,
+		Args: cobra.ExactArgs(2),
+		RunE: runAPI,
+	}
+
+	apiCmd.Flags().StringVarP(&apiNamespace, "namespace", "n", "", "Namespace (defaults to current kubectl context)")
+	apiCmd.Flags().StringVar(&apiStatefulSet, "statefulset", "", "StatefulSet whose API service receives the request (defaults to 'broker')")
+	apiCmd.Flags().StringVar(&apiPodName, "pod", "", "Send the request to this broker pod instead of the API service")
+	apiCmd.Flags().StringVar(&apiUsername, "username", "", "Optional authentication username")
+	apiCmd.Flags().StringVar(&apiPassword, "password", "", "Optional authentication password")
+	apiCmd.Flags().StringVarP(&apiData, "data", "d", "", "Request body; '@file' reads it from a file, '@-' from stdin")
+	apiCmd.Flags().BoolVarP(&apiInclude, "include", "i", false, "Print the status line and response headers before the body")
+
+	return apiCmd
+}
+
+func runAPI(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	method, err := backup.ValidateAPIRequest(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	path := args[1]
+	if err := mutuallyExclusive(apiPodName != "", "--pod", apiStatefulSet != "", "--statefulset"); err != nil {
+		return err
+	}
+
+	body, err := readAPIData(apiData)
+	if err != nil {
+		return err
+	}
+
+	resolvedNamespace, fromContext, err := resolveNamespace(apiNamespace, false)
+	if err != nil {
+		return err
+	}
+	apiNamespace = resolvedNamespace
+	if fromContext {
+		slog.Info("Using namespace from context", "namespace", apiNamespace)
+	}
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
+	}
+
+	target, dialer, err := apiDialer(ctx, k8sClient)
+	if err != nil {
+		return err
+	}
+
+	if globalFlags.DryRun && !backup.IsReadOnlyMethod(method) {
+		return renderDryRunPlan("api", fmt.Sprintf("Send %s %s (%d byte body) to the management API of %s", method, path, len(body), target))
+	}
+
+	slog.Debug("Sending management API request", "method", method, "path", path, "target", target)
+	options := backup.BackupOptions{
+		Username:       apiUsername,
+		Password:       apiPassword,
+		Auth:           apiAuth(),
+		RequestTimeout: operationTimeout(30 * time.Second),
+		TLS:            apiTLSOptions(),
+		Retry:          apiRetryPolicy(),
+	}
+	resp, err := backup.SendAPIRequest(ctx, dialer, options, method, path, body)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", method, path, err)
+	}
+
+	if err := renderAPIResponse(resp, apiInclude); err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("management API returned %s for %s %s", resp.Status, method, path)
+	}
+	return nil
+}
+
+// apiDialer reaches the management API of --pod or of the StatefulSet's API service and
+// describes the target for logs and dry-run plans
+func apiDialer(ctx context.Context, k8sClient *pkg.K8sClient) (string, pkg.PortForwardDialer, error) {
+	if apiPodName != "" {
+		pod, err := k8sClient.GetPod(ctx, apiNamespace, apiPodName)
+		if err != nil {
+			return "", nil, pkg.EnhanceError(err, fmt.Sprintf("pod %s in namespace %s", apiPodName, apiNamespace))
+		}
+		if err := pkg.ValidatePodStatus(pod); err != nil {
+			return "", nil, err
+		}
+		apiPort, err := k8sClient.DiscoverAPIPort(pod)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to discover API port: %w", err)
+		}
+		return "pod " + pod.Name, pkg.NewPodDialer(k8sClient, pod, apiPort, false), nil
+	}
+
+	statefulSet, defaulted := applyDefaultStatefulSet(apiStatefulSet)
+	if defaulted {
+		slog.Info("Using default StatefulSet", "statefulset", statefulSet)
+	}
+	service, err := k8sClient.GetAPIServiceFromStatefulSet(ctx, apiNamespace, statefulSet)
+	if err != nil {
+		return "", nil, pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", statefulSet, apiNamespace))
+	}
+	apiPort, err := k8sClient.DiscoverServiceAPIPort(service)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to discover API port: %w", err)
+	}
+	return "service " + service.Name, pkg.NewServiceDialer(k8sClient, service, apiPort), nil
+}
+
+// readAPIData returns the --data request body, reading '@file' and '@-' (stdin)
+func readAPIData(data string) ([]byte, error) {
+	source, fromFile := strings.CutPrefix(data, "@")
+	switch {
+	case data == "":
+		return nil, nil
+	case !fromFile:
+		return []byte(data), nil
+	case source == "-":
+		body, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body from stdin: %w", err)
+		}
+		return body, nil
+	default:
+		body, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w\n\nPlease either:\n- Check the file path: --data @<file>\n- Pass the body inline: --data '{\"key\":\"value\"}'", err)
+		}
+		return body, nil
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"sigs.k8s.io/yaml"
+
+	"kubectl-broker/pkg/backup"
+)
+
+// apiResponsePayload is the structured form of a raw API response; JSON bodies are embedded
+// as documents, other bodies as text
+type apiResponsePayload struct {
+	Status     string              `json:"status"`
+	StatusCode int                 `json:"statusCode"`
+	Header     map[string][]string `json:"header,omitempty"`
+	Body       any                 `json:"body,omitempty"`
+}
+
+// renderAPIResponse writes the response body unchanged to stdout, preceded by the status line
+// and headers with include
+func renderAPIResponse(resp *backup.APIResponse, include bool) error {
+	if format := currentOutputFormat(); format != "table" {
+		payload := apiResponsePayload{Status: resp.Status, StatusCode: resp.StatusCode, Header: resp.Header}
+		if json.Valid(resp.Body) {
+			payload.Body = json.RawMessage(resp.Body)
+		} else if len(resp.Body) > 0 {
+			payload.Body = string(resp.Body)
+		}
+		return writeStructuredAPIResponse(payload, format)
+	}
+
+	if include {
+		fmt.Printf("%s %s\n", resp.Proto, resp.Status)
+		names := make([]string, 0, len(resp.Header))
+		for name := range resp.Header {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			for _, value := range resp.Header[name] {
+				fmt.Printf("%s: %s\n", name, value)
+			}
+		}
+		fmt.Println()
+	}
+	if _, err := os.Stdout.Write(resp.Body); err != nil {
+		return fmt.Errorf("failed to write response body: %w", err)
+	}
+	// Keep the shell prompt on its own line after bodies without a trailing newline
+	if len(resp.Body) > 0 && resp.Body[len(resp.Body)-1] != '\n' && isTerminal(os.Stdout) {
+		fmt.Println()
+	}
+	return nil
+}
+
+func writeStructuredAPIResponse(payload apiResponsePayload, format string) error {
+	if format == "yaml" {
+		data, err := yaml.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode API response as YAML: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode API response as JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/audit"
+	"kubectl-broker/pkg/tracing"
+	"kubectl-broker/pkg/volumes"
+)
+
+var (
+	auditSince     string
+	auditNamespace string
+	auditOperation string
+)
+
+func newAuditCommand() *cobra.Command {
+	var auditCmd = &cobra.Command{
+		Use:   "audit",
+		Short: "Show and export the audit trail of destructive operations",
+		Long: `Show the audit trail of destructive operations such as volume cleanup and
+backup restore. Every such operation is recorded with the kubeconfig user and
+context, the affected resources and its outcome as a JSON line in
+~/.kubectl-broker/audit/audit.jsonl (override with KUBECTL_BROKER_AUDIT_DIR).
+
+With --audit-events, the operations additionally emit Kubernetes Events on the
+affected resources.
+
+Examples:
+  # Show the operations of the last 30 days
+  kubectl broker audit
+
+  # Export all restores of the last quarter for a compliance review
+  kubectl broker audit --since 90d --operation backup.restore --output json
+
+  # Emit Kubernetes Events while cleaning up volumes
+  kubectl broker volumes cleanup --confirm --audit-events`,
+		RunE: runAudit,
+	}
+
+	auditCmd.Flags().StringVar(&auditSince, "since", "30d", "Only include operations newer than this duration (e.g., 24h, 30d, 12w)")
+	auditCmd.Flags().StringVarP(&auditNamespace, "namespace", "n", "", "Only include operations targeting or affecting this namespace")
+	auditCmd.Flags().StringVar(&auditOperation, "operation", "", "Only include this operation (volumes.cleanup, volumes.unstick, backup.restore, backup.resume-traffic, cluster.scale, extensions.enable, extensions.disable)")
+
+	return auditCmd
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	window, err := parsePositiveDurationFlag("since", auditSince)
+	if err != nil {
+		return err
+	}
+
+	store, err := audit.NewStore("")
+	if err != nil {
+		return err
+	}
+
+	filter := audit.Filter{
+		Since:     time.Now().Add(-window),
+		Namespace: auditNamespace,
+		Operation: auditOperation,
+	}
+	records, err := store.Load(filter)
+	if err != nil {
+		return err
+	}
+
+	return displayAuditRecords(records, filter, store.Path())
+}
+
+// recordAudit completes the record with the caller's identity, appends it to the local audit
+// log and, with --audit-events, emits Kubernetes Events. Failures only warn so auditing never
+// changes the outcome of the operation.
+func recordAudit(ctx context.Context, k8sClient *pkg.K8sClient, record audit.Record) {
+	record.Timestamp = time.Now().UTC()
+	record.KubeContext, record.User = pkg.CurrentKubeIdentity()
+	if globalFlags.InCluster {
+		record.KubeContext, record.User = "in-cluster", ""
+	}
+	if current, err := user.Current(); err == nil {
+		record.LocalUser = current.Username
+	}
+	if tracing.RequestIDSent() {
+		record.RequestID = tracing.RequestID()
+	}
+
+	store, err := audit.NewStore("")
+	if err == nil {
+		err = store.Append(record)
+	}
+	if err != nil {
+		slog.Warn("Failed to record audit trail", "operation", record.Operation, "error", err)
+	}
+
+	if !globalFlags.AuditEvents || len(record.Resources) == 0 {
+		return
+	}
+	if k8sClient == nil {
+		if k8sClient, err = newK8sClient(false); err != nil {
+			slog.Warn("Failed to emit audit events", "operation", record.Operation, "error", err)
+			return
+		}
+	}
+	// The operation's context may already be cancelled or past its --timeout
+	eventCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+	if err := audit.EmitEvents(eventCtx, k8sClient.GetCoreClient(), record); err != nil {
+		slog.Warn("Failed to emit audit events", "operation", record.Operation, "error", err)
+	}
+}
+
+// volumeCleanupAuditRecord lists the deleted and failed volumes of a cleanup run
+func volumeCleanupAuditRecord(result *volumes.CleanupResult, options volumes.CleanupOptions, cleanupErr error) audit.Record {
+	record := audit.Record{
+		Operation: audit.OperationVolumeCleanup,
+		Namespace: options.Namespace,
+		Resources: []audit.Resource{},
+		Details:   map[string]string{},
+	}
+	if options.AllNamespaces {
+		record.Namespace = ""
+		record.Details["scope"] = "all-namespaces"
+	}
+	if options.Force {
+		record.Details["force"] = "true"
+	}
+	if options.Selector != "" {
+		record.Details["selector"] = options.Selector
+	}
+
+	for _, action := range result.Deleted {
+		record.Resources = append(record.Resources, auditVolumeResource(action.Type, action.Namespace, action.Name, nil))
+	}
+	for _, failure := range result.FailedDeletions {
+		record.Resources = append(record.Resources, auditVolumeResource(failure.Type, failure.Namespace, failure.Name, failure.Error))
+	}
+	record.Details["reclaimedBytes"] = fmt.Sprintf("%d", result.TotalReclaimedStorage)
+
+	record.Outcome = audit.OutcomeFor(len(result.Deleted), len(result.FailedDeletions), cleanupErr)
+	if cleanupErr != nil {
+		record.Error = cleanupErr.Error()
+	}
+	return record
+}
+
+// volumeUnstickAuditRecord records the finalizers removed from a terminating claim
+func volumeUnstickAuditRecord(options volumes.UnstickOptions, result *volumes.UnstickResult, unstickErr error) audit.Record {
+	record := audit.Record{
+		Operation: audit.OperationVolumeUnstick,
+		Namespace: options.Namespace,
+		Resources: []audit.Resource{auditVolumeResource("PersistentVolumeClaim", options.Namespace, options.PVCName, unstickErr)},
+		Outcome:   audit.OutcomeFor(0, 0, unstickErr),
+		Details:   map[string]string{"finalizers": strings.Join(result.Finalizers, ",")},
+	}
+	if result.VolumeName != "" {
+		record.Details["volume"] = result.VolumeName
+	}
+	if unstickErr != nil {
+		record.Error = unstickErr.Error()
+	}
+	return record
+}
+
+// auditVolumeResource keeps PersistentVolumes cluster-scoped; cleanup reports them with the
+// namespace of their former claim
+func auditVolumeResource(kind, namespace, name string, err error) audit.Resource {
+	resource := audit.Resource{Kind: kind, Namespace: namespace, Name: name}
+	if kind == "PersistentVolume" {
+		resource.Namespace = ""
+	}
+	if err != nil {
+		resource.Error = err.Error()
+	}
+	return resource
+}
+
+// backupRestoreAuditRecord describes a restore of the StatefulSet's cluster
+func backupRestoreAuditRecord(source, backupRef string, restoreErr error) audit.Record {
+	record := audit.Record{
+		Operation: audit.OperationBackupRestore,
+		Namespace: backupNamespace,
+		Resources: []audit.Resource{{Kind: "StatefulSet", Namespace: backupNamespace, Name: backupStatefulSetName}},
+		Outcome:   audit.OutcomeFor(0, 0, restoreErr),
+		Details:   map[string]string{"source": source, "backup": backupRef},
+	}
+	if restoreErr != nil {
+		record.Error = restoreErr.Error()
+		record.Resources[0].Error = restoreErr.Error()
+	}
+	return record
+}
+
+// withSafetyBackup records the safety backup of a restore in its audit record
+func withSafetyBackup(record audit.Record, safetyID string) audit.Record {
+	if safetyID != "" {
+		record.Details["safetyBackup"] = safetyID
+	}
+	return record
+}
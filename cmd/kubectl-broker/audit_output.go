@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"sigs.k8s.io/yaml"
+
+	"kubectl-broker/pkg/audit"
+)
+
+var auditColumns = []tableColumn{
+	{Title: "TIME", Width: 20},
+	{Title: "OPERATION", Width: 15},
+	{Title: "OUTCOME", Width: 9},
+	{Title: "USER", Width: 16},
+	{Title: "CONTEXT", Width: 16},
+	{Title: "NAMESPACE", Width: 14},
+	{Title: "RESOURCES", Width: 9},
+}
+
+func displayAuditRecords(records []audit.Record, filter audit.Filter, path string) error {
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		return writeStructuredAudit(records, filter, format)
+	default:
+		displayAuditTable(records, filter, path)
+		return nil
+	}
+}
+
+func displayAuditTable(records []audit.Record, filter audit.Filter, path string) {
+	if len(records) == 0 {
+		fmt.Printf("No audited operations since %s in %s\n", filter.Since.Local().Format(time.RFC3339), path)
+		return
+	}
+
+	renderTableHeader(auditColumns, 2)
+
+	useColors := colorOutputEnabled()
+	for _, record := range records {
+		namespace := record.Namespace
+		if namespace == "" {
+			namespace = "-"
+		}
+		fmt.Printf("%-20s  %-15s  %s  %-16s  %-16s  %-14s  %d\n",
+			record.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			truncateString(record.Operation, 15),
+			auditOutcomeColor(record.Outcome, useColors).Sprintf("%-9s", record.Outcome),
+			truncateString(orDash(record.User), 16),
+			truncateString(orDash(record.KubeContext), 16),
+			truncateString(namespace, 14),
+			len(record.Resources))
+	}
+
+	// Failed resources are what reviewers need to follow up on
+	for _, record := range records {
+		for _, resource := range record.Resources {
+			if resource.Error == "" {
+				continue
+			}
+			fmt.Printf("\n%s %s %s: %s", record.Timestamp.Local().Format("2006-01-02 15:04:05"),
+				resource.Kind, auditResourceName(resource), resource.Error)
+			if record.RequestID != "" {
+				fmt.Printf(" (request ID %s)", record.RequestID)
+			}
+		}
+	}
+	fmt.Println()
+}
+
+func auditResourceName(resource audit.Resource) string {
+	if resource.Namespace == "" {
+		return resource.Name
+	}
+	return resource.Namespace + "/" + resource.Name
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func auditOutcomeColor(outcome audit.Outcome, useColors bool) *color.Color {
+	if !useColors {
+		return color.New()
+	}
+	switch outcome {
+	case audit.OutcomeSucceeded:
+		return color.New(color.FgGreen)
+	case audit.OutcomePartial:
+		return color.New(color.FgYellow)
+	default:
+		return color.New(color.FgRed)
+	}
+}
+
+func writeStructuredAudit(records []audit.Record, filter audit.Filter, format string) error {
+	if records == nil {
+		records = []audit.Record{}
+	}
+	payload := auditStructuredOutput{
+		Since:     filter.Since.UTC(),
+		Namespace: filter.Namespace,
+		Operation: filter.Operation,
+		Records:   records,
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+
+	switch format {
+	case "yaml":
+		data, err = yaml.Marshal(payload)
+	default:
+		data, err = json.MarshalIndent(payload, "", "  ")
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to render %s output: %w", format, err)
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
+
+type auditStructuredOutput struct {
+	Since     time.Time      `json:"since"`
+	Namespace string         `json:"namespace,omitempty"`
+	Operation string         `json:"operation,omitempty"`
+	Records   []audit.Record `json:"records"`
+}
@@ -2,18 +2,26 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
 
 	"kubectl-broker/pkg"
 	"kubectl-broker/pkg/backup"
+	"kubectl-broker/pkg/logging"
 	"kubectl-broker/pkg/sidecar"
+	"kubectl-broker/pkg/transport"
 )
 
 const (
@@ -21,6 +29,9 @@ const (
 	restoreSourceManagement = "management"
 	restoreSourceRemote     = "remote"
 
+	progressFormatBar       = "bar"
+	progressFormatJSONLines = "json-lines"
+
 	backupScopeEngineManagement = "management"
 	backupScopeEngineSidecar    = "sidecar"
 )
@@ -29,34 +40,76 @@ var (
 
 	// Global backup flags
 	backupStatefulSetName string
+	backupPlatformName    string
+	backupSelector        string
 	backupNamespace       string
 	backupUsername        string
 	backupPassword        string
 	backupPodName         string
 	backupSidecarPort     int
+	backupSidecarService  string
+	backupSidecarTLS      transport.TLSOptions
+	backupProgressFormat  string
+	backupVia             string
 
 	// Create command flags
-	createDestination string
+	createDestination      string
+	createCopy             bool
+	createForceOperator    bool
+	createAllNodes         bool
+	createManifestFile     string
+	createAsync            bool
+	createNamespaces       []string
+	createAllHiveMQ        bool
+	createConcurrency      int
+	createWaitRemote       bool
+	createWaitTimeout      time.Duration
+	createRequestTimeout   time.Duration
+	createOperationTimeout time.Duration
 
 	// List command flags
 	listRemoteLimit int
+	listMaxItems    int
+	listSource      string
+	listSince       string
+	listUntil       string
+	listStatus      string
+	listSort        string
 
 	// Download command flags
 	downloadBackupID  string
 	downloadOutputDir string
 	downloadOutput    string
 	downloadLatest    bool
+	downloadSource    string
+	downloadVersion   string
+	downloadChunkSize string
+	downloadLimitRate string
+	downloadAll       bool
+	downloadSince     string
+	downloadUntil     string
+	downloadStatus    string
+	downloadWorkers   int
 
 	// Status command flags
 	statusBackupID string
 	statusLatest   bool
+	statusWait     bool
 
 	// Restore command flags
-	restoreBackupID string
-	restoreLatest   bool
-	restoreSource   string
-	restoreVersion  string
-	restoreDryRun   bool
+	restoreBackupID              string
+	restoreLatest                bool
+	restoreSource                string
+	restoreVersion               string
+	restoreIgnoreVersionMismatch bool
+	restoreSafetyBackup          bool
+	restoreNoSafetyBackup        bool
+	restoreQuiesce               bool
+	restoreReplicationTimeout    time.Duration
+
+	// Inspect command flags
+	inspectBackupID string
+	inspectFile     string
 )
 
 func newBackupCommand() *cobra.Command {
@@ -92,11 +145,22 @@ Examples:
 
 	// Add persistent flags for all subcommands
 	backupCmd.PersistentFlags().StringVar(&backupStatefulSetName, "statefulset", "", "Name of the StatefulSet to backup (defaults to 'broker')")
+	backupCmd.PersistentFlags().StringVar(&backupPlatformName, "platform", "", "Name of the HiveMQPlatform resource to target instead of --statefulset")
+	backupCmd.PersistentFlags().StringVarP(&backupSelector, "selector", "l", "", "Label selector of the StatefulSet to target instead of --statefulset, e.g. app.kubernetes.io/instance=tenant-a")
 	backupCmd.PersistentFlags().StringVarP(&backupNamespace, "namespace", "n", "", "Namespace (defaults to current kubectl context)")
 	backupCmd.PersistentFlags().StringVar(&backupUsername, "username", "", "Optional authentication username")
 	backupCmd.PersistentFlags().StringVar(&backupPassword, "password", "", "Optional authentication password")
-	backupCmd.PersistentFlags().StringVar(&backupPodName, "pod", "", "Specific pod to use when connecting to the sidecar engine")
+	backupCmd.PersistentFlags().StringVar(&backupPodName, "pod", "", "Specific pod to use when connecting to the sidecar engine or with --via pod")
 	backupCmd.PersistentFlags().IntVar(&backupSidecarPort, "sidecar-port", int(sidecar.DefaultPort), "Port exposed by the sidecar REST API")
+	backupCmd.PersistentFlags().StringVar(&backupSidecarService, "sidecar-service", "", "Reach the sidecar through this Service instead of a pod of the StatefulSet (--sidecar-port is then the service port)")
+	backupCmd.PersistentFlags().BoolVar(&backupSidecarTLS.Enabled, "sidecar-tls", false, "Use HTTPS for the sidecar REST API")
+	backupCmd.PersistentFlags().StringVar(&backupSidecarTLS.CACertFile, "sidecar-ca-cert", "", "PEM CA bundle used to verify the sidecar certificate (implies --sidecar-tls)")
+	backupCmd.PersistentFlags().StringVar(&backupSidecarTLS.ClientCertFile, "sidecar-cert", "", "PEM client certificate for sidecars that require mutual TLS (with --sidecar-key)")
+	backupCmd.PersistentFlags().StringVar(&backupSidecarTLS.ClientKeyFile, "sidecar-key", "", "PEM private key of --sidecar-cert")
+	backupCmd.PersistentFlags().StringVar(&backupSidecarTLS.ServerName, "sidecar-tls-server-name", "", "Name to verify the sidecar certificate against (defaults to <service>.<namespace>.svc with --sidecar-service)")
+	backupCmd.PersistentFlags().BoolVar(&backupSidecarTLS.InsecureSkipVerify, "sidecar-insecure-skip-verify", false, "Skip verification of the sidecar certificate (implies --sidecar-tls)")
+	backupCmd.PersistentFlags().StringVar(&backupVia, "via", viaService, "Route to the management API: service (a ready pod behind the API service) or pod (--pod, or the first ready pod of the StatefulSet)")
+	backupCmd.PersistentFlags().StringVar(&backupProgressFormat, "progress-format", progressFormatBar, "Progress output: bar, or json-lines for one JSON event per line on stderr")
 
 	// Add subcommands
 	backupCmd.AddCommand(newBackupCreateCommand())
@@ -104,7 +168,17 @@ Examples:
 	backupCmd.AddCommand(newBackupDownloadCommand())
 	backupCmd.AddCommand(newBackupStatusCommand())
 	backupCmd.AddCommand(newBackupRestoreCommand())
+	backupCmd.AddCommand(newBackupResumeTrafficCommand())
+	backupCmd.AddCommand(newBackupCloneCommand())
+	backupCmd.AddCommand(newBackupInspectCommand())
+	backupCmd.AddCommand(newBackupDiffCommand())
+	backupCmd.AddCommand(newBackupChecksumCommand())
+	backupCmd.AddCommand(newBackupPushCommand())
+	backupCmd.AddCommand(newBackupSyncCommand())
+	backupCmd.AddCommand(newBackupReportCommand())
+	backupCmd.AddCommand(newBackupExportPolicyCommand())
 	backupCmd.AddCommand(newBackupTestCommand())
+	backupCmd.AddCommand(newBackupSidecarCommand())
 
 	return backupCmd
 }
@@ -118,11 +192,72 @@ func newBackupCreateCommand() *cobra.Command {
 2. Initiate a backup operation
 3. Monitor progress until completion
 4. Display the final backup ID and size
-5. Optionally move backup directory to another location within the pod`,
-		RunE: runBackupCreate,
+5. Optionally move backup directory to another location within the pod
+
+--destination must be on another volume of the pod than the backup folder (for
+example a separately mounted backup PVC) with enough free space for the backup;
+both are checked before anything is copied. The directory is copied with
+progress reporting and the original removed afterwards, or kept with --copy.
+When the StatefulSet is managed by the HiveMQ operator, --destination also needs
+--force-operator-managed, since the operator does not expect the contents of
+its volumes to change.
+
+With --async the command returns as soon as the backup is triggered; follow it
+later with 'backup status --id <id> --wait'. Otherwise the backup status is
+polled until it completes, for at most --operation-timeout; each request to the
+management API is bounded by --request-timeout, so a hanging request is retried
+without cutting short a slow backup that is still progressing. A backup that
+exceeds --operation-timeout keeps running on the broker.
+
+When the backup sidecar is deployed, --wait-remote keeps the command running
+after the backup completed until the sidecar has uploaded it and the object is
+listed in the remote inventory, so a successful run guarantees an off-cluster
+copy. The wait is bounded by --wait-remote-timeout; use 'backup push' to upload
+immediately instead of on the sidecar's schedule.
+
+--namespaces or --all-hivemq-namespaces back up several namespaces concurrently
+(at most --concurrency at a time) and print a summary table. The command fails
+if any namespace failed.
+
+Examples:
+  # Create a backup and wait for it
+  kubectl broker backup create -n production
+
+  # Trigger a backup and check on it later
+  kubectl broker backup create -n production --async
+  kubectl broker backup status -n production --latest --wait
+
+  # Nightly backup of several tenants, eight at a time
+  kubectl broker backup create --namespaces tenant-a,tenant-b,tenant-c --concurrency 8
+
+  # Back up every namespace running HiveMQ
+  kubectl broker backup create --all-hivemq-namespaces
+
+  # Keep the backup in place and copy it to a mounted backup volume
+  kubectl broker backup create --destination /mnt/backups --copy
+
+  # Only succeed once the backup is in S3
+  kubectl broker backup create -n production --wait-remote --wait-remote-timeout 1h
+
+  # Post the result of a nightly backup to a webhook
+  kubectl broker backup create -n production --notify-webhook https://hooks.example.com/backups`,
+		RunE: withNotification("backup create", backupNotifyTarget, runBackupCreate),
 	}
 
-	createCmd.Flags().StringVar(&createDestination, "destination", "", "Pod path to move backup directory to after creation (e.g., /opt/hivemq/data/backup)")
+	createCmd.Flags().StringVar(&createDestination, "destination", "", "Pod path on another volume to move the backup directory to after creation (e.g., /mnt/backups)")
+	createCmd.Flags().BoolVar(&createCopy, "copy", false, "Copy the backup directory to --destination and keep the original")
+	createCmd.Flags().BoolVar(&createForceOperator, "force-operator-managed", false, "Allow --destination on a StatefulSet managed by the HiveMQ operator")
+	createCmd.Flags().BoolVar(&createAllNodes, "all-nodes", false, "Trigger a backup on every broker pod instead of once through the service")
+	createCmd.Flags().StringVar(&createManifestFile, "manifest-file", "", "Write the backup manifest (backup pieces per pod) to this JSON file")
+	createCmd.Flags().BoolVar(&createAsync, "async", false, "Return once the backup is triggered instead of waiting for completion")
+	createCmd.Flags().StringSliceVar(&createNamespaces, "namespaces", nil, "Back up these namespaces concurrently (comma-separated)")
+	createCmd.Flags().BoolVar(&createAllHiveMQ, "all-hivemq-namespaces", false, "Back up every namespace with a running HiveMQ StatefulSet concurrently")
+	createCmd.Flags().IntVar(&createConcurrency, "concurrency", backup.DefaultNamespaceConcurrency, "Maximum number of namespaces backed up at the same time")
+	createCmd.Flags().BoolVar(&createWaitRemote, "wait-remote", false, "Wait until the backup sidecar has uploaded the new backup to remote storage")
+	createCmd.Flags().DurationVar(&createWaitTimeout, "wait-remote-timeout", 30*time.Minute, "Maximum time to wait for the remote copy with --wait-remote")
+	createCmd.Flags().DurationVar(&createRequestTimeout, "request-timeout", backup.DefaultRequestTimeout, "Timeout of each management API request")
+	createCmd.Flags().DurationVar(&createOperationTimeout, "operation-timeout", backup.DefaultOperationDeadline, "Maximum time to wait for the backup to complete")
+	addNotifyFlags(createCmd)
 
 	return createCmd
 }
@@ -132,11 +267,35 @@ func newBackupListCommand() *cobra.Command {
 		Use:   "list",
 		Short: "List all available backups",
 		Long: `List remote backups discovered by the HiveMQ backup sidecar (S3 inventory).
-Requires the backup sidecar to be deployed alongside the broker.`,
-		RunE: runBackupList,
+Requires the backup sidecar to be deployed alongside the broker. With --source
+management the backups known to the HiveMQ management API are listed instead.
+
+Filters, sorting and --limit behave the same for both sources. Backups are
+sorted newest first unless --sort size is given.
+
+Examples:
+  # Ten largest remote backups
+  kubectl broker backup list --sort size --limit 10
+
+  # Failed management backups from the last week
+  kubectl broker backup list --source management --status failed --since 7d
+
+  # Backups created in January
+  kubectl broker backup list --since 2024-01-01 --until 2024-02-01
+
+  # Read at most 500 backups from a management API with a long history
+  kubectl broker backup list --source management --max-items 500`,
+		RunE:        runBackupList,
+		Annotations: map[string]string{csvOutputAnnotation: "true"},
 	}
 
-	listCmd.Flags().IntVar(&listRemoteLimit, "limit", 0, "Limit number of remote backups returned by the sidecar")
+	listCmd.Flags().StringVar(&listSource, "source", restoreSourceRemote, "Listing source: remote (sidecar) or management")
+	listCmd.Flags().IntVar(&listRemoteLimit, "limit", 0, "Maximum number of backups to show")
+	listCmd.Flags().StringVar(&listSince, "since", "", "Only backups created since this age or time (e.g. 7d, 2024-01-31, RFC 3339)")
+	listCmd.Flags().StringVar(&listUntil, "until", "", "Only backups created until this age or time (e.g. 24h, 2024-01-31, RFC 3339)")
+	listCmd.Flags().StringVar(&listStatus, "status", "", "Only backups with this status: completed or failed")
+	listCmd.Flags().StringVar(&listSort, "sort", backup.SortByCreated, "Sort order, descending: created or size")
+	listCmd.Flags().IntVar(&listMaxItems, "max-items", 0, "Stop following management API pagination after this many backups (0 reads all pages)")
 
 	return listCmd
 }
@@ -147,7 +306,36 @@ func newBackupDownloadCommand() *cobra.Command {
 		Short: "Download a backup file",
 		Long: `Download a backup file to local storage. You can specify a backup ID
 or use --latest to download the most recent backup. Files are saved to
-the output directory with progress indication.`,
+the output directory with progress indication.
+
+With --source remote the backup is pulled from object storage via the backup
+sidecar instead of the management API. The sidecar hands out a presigned URL
+when supported; otherwise the object is streamed through the sidecar. This
+also works for historical backups that no longer exist on the broker.
+
+Next to the archive a sha256sum-compatible <archive>.sha256 file and an
+<archive>.manifest.json with backup ID, context, namespace, creation time, size
+and source endpoint are written. Verify the archive later with
+"kubectl broker backup checksum --file <archive>".
+
+Over constrained links, --limit-rate caps the transfer rate and --chunk-size
+sets the read buffer. Progress is refreshed at most ten times per second.
+
+With --all every completed backup is downloaded into --output-dir, several at a
+time (--concurrency), each with its checksum and manifest. --since, --until and
+--status narrow the selection like in 'backup list'. A failed download does not
+stop the others; the summary table lists the result of every backup and the
+command fails if any download failed. --limit-rate applies to each download.
+
+Examples:
+  # Download the latest backup without saturating a VPN link
+  kubectl broker backup download --latest --limit-rate 10MB/s
+
+  # Larger reads for fast links
+  kubectl broker backup download --id abc123 --chunk-size 1MB
+
+  # Monthly archival of the last week's backups
+  kubectl broker backup download --all --since 7d --output-dir ./archive`,
 		RunE: runBackupDownload,
 	}
 
@@ -155,6 +343,15 @@ the output directory with progress indication.`,
 	downloadCmd.Flags().StringVar(&downloadOutputDir, "output-dir", "./backups", "Directory to save backup files")
 	downloadCmd.Flags().StringVar(&downloadOutput, "output", "", "Specific output filename (overrides automatic naming)")
 	downloadCmd.Flags().BoolVar(&downloadLatest, "latest", false, "Download the latest backup")
+	downloadCmd.Flags().StringVar(&downloadSource, "source", restoreSourceAuto, "Download source: auto, management, or remote")
+	downloadCmd.Flags().StringVar(&downloadVersion, "version", "", "Remote backup key to download when source=remote")
+	downloadCmd.Flags().StringVar(&downloadChunkSize, "chunk-size", "32KB", "Read buffer size of the download (e.g. 256KB, 1MB)")
+	downloadCmd.Flags().StringVar(&downloadLimitRate, "limit-rate", "", "Maximum download rate, e.g. 10MB/s (unlimited by default)")
+	downloadCmd.Flags().BoolVar(&downloadAll, "all", false, "Download every backup matching --since, --until and --status")
+	downloadCmd.Flags().StringVar(&downloadSince, "since", "", "With --all, only backups created since this age or time (e.g. 7d, 2024-01-31)")
+	downloadCmd.Flags().StringVar(&downloadUntil, "until", "", "With --all, only backups created until this age or time (e.g. 24h, 2024-01-31)")
+	downloadCmd.Flags().StringVar(&downloadStatus, "status", backup.ListStatusCompleted, "With --all, only backups with this status: completed or failed")
+	downloadCmd.Flags().IntVar(&downloadWorkers, "concurrency", backup.DefaultNamespaceConcurrency, "With --all, maximum number of downloads at the same time")
 
 	return downloadCmd
 }
@@ -164,12 +361,21 @@ func newBackupStatusCommand() *cobra.Command {
 		Use:   "status",
 		Short: "Check backup status",
 		Long: `Check the status of a backup operation. Shows current status,
-progress (if in progress), size, and creation time.`,
+progress (if in progress), size, and creation time.
+
+With --wait the command blocks until the backup completes or fails, showing a
+progress bar with percent, transfer rate and ETA. It exits with an error if the
+backup fails.
+
+Examples:
+  # Wait for a backup started with 'backup create --async'
+  kubectl broker backup status --id <backup-id> --wait`,
 		RunE: runBackupStatus,
 	}
 
 	statusCmd.Flags().StringVar(&statusBackupID, "id", "", "Backup ID to check")
 	statusCmd.Flags().BoolVar(&statusLatest, "latest", false, "Check status of the latest backup")
+	statusCmd.Flags().BoolVar(&statusWait, "wait", false, "Block until the backup reaches a terminal state, showing a progress bar")
 
 	return statusCmd
 }
@@ -182,19 +388,93 @@ func newBackupRestoreCommand() *cobra.Command {
 1. Connect to the broker's management API
 2. Initiate a restore operation from the specified backup
 3. Monitor progress until completion
-4. Display the final restore status`,
-		RunE: runBackupRestore,
+4. Display the final restore status
+
+Before a restore through the management API, the HiveMQ version recorded in the
+backup is compared with the broker's image version. Restores across major
+versions or onto an older broker are refused unless --ignore-version-mismatch
+is set. If either version cannot be determined, a warning is shown and the
+restore continues.
+
+Unless --no-safety-backup is given, a fresh backup of the current state is
+created through the management API before anything is restored. Its ID is
+printed together with the command that rolls the restore back. The restore is
+not started when the safety backup fails.
+
+With --quiesce a management API restore is coordinated with the client traffic
+of the StatefulSet and reported phase by phase: the client Services selecting
+the brokers lose their endpoints, the backup is restored, every broker has to
+report healthy again within --replication-timeout, and only then are the
+Services routed to the brokers again. Connections already established are not
+closed, and Services that also serve the management API keep their traffic.
+When a phase after the hold fails, the traffic stays held; end the hold with
+'backup resume-traffic' once the brokers are healthy.
+
+With the global --dry-run flag the backup is resolved and the version checked,
+then the plan is printed without creating or restoring anything. Management
+dry runs also check that the backup exists and completed and that every broker
+pod has room for it, and exit non-zero when a check fails. Remote dry runs let
+the sidecar verify the object without downloading it.
+
+Examples:
+  # Restore a specific backup
+  kubectl broker backup restore --id abc123
+
+  # Restore although the backup was created by another major version
+  kubectl broker backup restore --id abc123 --ignore-version-mismatch
+
+  # Restore without backing up the current state first
+  kubectl broker backup restore --id abc123 --no-safety-backup
+
+  # Hold client traffic until the restored cluster is healthy again
+  kubectl broker backup restore --id abc123 --quiesce --replication-timeout 15m
+
+  # Show what restoring the latest backup would do
+  kubectl broker backup restore --latest --dry-run`,
+		RunE: withNotification("backup restore", backupNotifyTarget, runBackupRestore),
 	}
 
 	restoreCmd.Flags().StringVar(&restoreBackupID, "id", "", "Backup ID to restore from")
 	restoreCmd.Flags().BoolVar(&restoreLatest, "latest", false, "Restore from the latest backup")
 	restoreCmd.Flags().StringVar(&restoreSource, "source", restoreSourceAuto, "Restore source: auto, management, or remote")
 	restoreCmd.Flags().StringVar(&restoreVersion, "version", "", "Remote backup key to restore when source=remote")
-	restoreCmd.Flags().BoolVar(&restoreDryRun, "dry-run", false, "Simulate remote restore operations without downloading data")
+	restoreCmd.Flags().BoolVar(&restoreIgnoreVersionMismatch, "ignore-version-mismatch", false, "Restore even if the backup was created by an incompatible HiveMQ version")
+	restoreCmd.Flags().BoolVar(&restoreSafetyBackup, "safety-backup", true, "Back up the current state before restoring, so the restore can be rolled back")
+	restoreCmd.Flags().BoolVar(&restoreNoSafetyBackup, "no-safety-backup", false, "Skip the pre-restore safety backup")
+	restoreCmd.Flags().BoolVar(&restoreQuiesce, "quiesce", false, "Hold client traffic during the restore until every broker reports healthy again (management API restores only)")
+	restoreCmd.Flags().DurationVar(&restoreReplicationTimeout, "replication-timeout", 10*time.Minute, "How long --quiesce waits for the brokers to report healthy after the restore")
+	addNotifyFlags(restoreCmd)
 
 	return restoreCmd
 }
 
+func newBackupInspectCommand() *cobra.Command {
+	var inspectCmd = &cobra.Command{
+		Use:   "inspect",
+		Short: "Inspect the contents of a backup without restoring it",
+		Long: `Inspect a backup without restoring it. The command lists the backup's files and
+reports the included persistence stores with their approximate sizes, the HiveMQ
+version that produced the backup and the retained message count when the backup
+metadata records them.
+
+A backup on the cluster is inspected in place on the pod that holds it; a
+downloaded archive (.tar.gz or .tar) is read locally with --file.
+
+Examples:
+  # Inspect a backup on the cluster
+  kubectl broker backup inspect --id abc123 --namespace production
+
+  # Inspect a downloaded archive
+  kubectl broker backup inspect --file ./backups/abc123.tar.gz --output json`,
+		RunE: runBackupInspect,
+	}
+
+	inspectCmd.Flags().StringVar(&inspectBackupID, "id", "", "Backup ID to inspect on the cluster")
+	inspectCmd.Flags().StringVar(&inspectFile, "file", "", "Path to a downloaded backup archive to inspect locally")
+
+	return inspectCmd
+}
+
 func newBackupTestCommand() *cobra.Command {
 	var testCmd = &cobra.Command{
 		Use:   "test",
@@ -207,90 +487,550 @@ func newBackupTestCommand() *cobra.Command {
 }
 
 // Apply intelligent defaults similar to the status command
-func applyBackupDefaults() error {
+func applyBackupDefaults(ctx context.Context) error {
+	if backupProgressFormat != progressFormatBar && backupProgressFormat != progressFormatJSONLines {
+		return fmt.Errorf("invalid --progress-format %q\n\nPlease either:\n- Show a progress bar: --progress-format bar\n- Emit machine-readable events: --progress-format json-lines", backupProgressFormat)
+	}
+
+	if err := validateVia(backupVia, "go through the API service"); err != nil {
+		return err
+	}
+
 	resolvedNamespace, fromContext, err := resolveNamespace(backupNamespace, false)
 	if err != nil {
 		return err
 	}
 	backupNamespace = resolvedNamespace
 	if fromContext {
-		fmt.Printf("Using namespace from context: %s\n", backupNamespace)
+		slog.Info("Using namespace from context", "namespace", backupNamespace)
+	}
+
+	if backupSelector != "" {
+		if err := mutuallyExclusive(true, "--selector", backupStatefulSetName != "", "--statefulset"); err != nil {
+			return err
+		}
+		if err := mutuallyExclusive(true, "--selector", backupPlatformName != "", "--platform"); err != nil {
+			return err
+		}
+		statefulSet, err := statefulSetFromSelector(ctx, backupNamespace, backupSelector)
+		if err != nil {
+			return err
+		}
+		backupStatefulSetName = statefulSet
+		slog.Info("Using StatefulSet from selector", "statefulset", backupStatefulSetName, "selector", backupSelector)
+		return nil
+	}
+
+	if backupPlatformName != "" {
+		if err := mutuallyExclusive(true, "--platform", backupStatefulSetName != "", "--statefulset"); err != nil {
+			return err
+		}
+		statefulSet, err := statefulSetFromPlatform(ctx, backupNamespace, backupPlatformName)
+		if err != nil {
+			return err
+		}
+		backupStatefulSetName = statefulSet
+		slog.Info("Using StatefulSet from HiveMQPlatform", "statefulset", backupStatefulSetName, "platform", backupPlatformName)
+		return nil
 	}
 
 	var usedDefault bool
 	backupStatefulSetName, usedDefault = applyDefaultStatefulSet(backupStatefulSetName)
 	if usedDefault {
-		fmt.Printf("Using default StatefulSet: %s\n", backupStatefulSetName)
+		slog.Info("Using default StatefulSet", "statefulset", backupStatefulSetName)
 	}
 
 	return nil
 }
 
 func runBackupCreate(cmd *cobra.Command, args []string) error {
-	if err := applyBackupDefaults(); err != nil {
+	ctx := cmd.Context()
+	if createRequestTimeout <= 0 || createOperationTimeout <= 0 {
+		return fmt.Errorf("--request-timeout and --operation-timeout must be positive, got %s and %s", createRequestTimeout, createOperationTimeout)
+	}
+	if len(createNamespaces) > 0 || createAllHiveMQ {
+		return runBackupCreateMultiNamespace(ctx)
+	}
+	if err := applyBackupDefaults(cmd.Context()); err != nil {
 		return err
 	}
 
-	fmt.Printf("Creating backup for StatefulSet %s in namespace %s\n", backupStatefulSetName, backupNamespace)
+	if createCopy && createDestination == "" {
+		return fmt.Errorf("--copy requires --destination\n\nPlease either:\n- Name the target directory: --destination <pod-path>\n- Drop --copy to keep the backup in the backup folder")
+	}
+	if createAsync {
+		if err := mutuallyExclusive(true, "--async", createAllNodes, "--all-nodes"); err != nil {
+			return err
+		}
+		if err := mutuallyExclusive(true, "--async", createDestination != "", "--destination"); err != nil {
+			return err
+		}
+		if err := mutuallyExclusive(true, "--async", createManifestFile != "", "--manifest-file"); err != nil {
+			return err
+		}
+	}
+	if createWaitRemote {
+		if err := mutuallyExclusive(true, "--wait-remote", createAsync, "--async"); err != nil {
+			return err
+		}
+		if err := mutuallyExclusive(true, "--wait-remote", createAllNodes, "--all-nodes"); err != nil {
+			return err
+		}
+		if createDestination != "" && !createCopy {
+			return fmt.Errorf("--wait-remote needs the backup to stay in the backup folder the sidecar uploads from\n\nPlease either:\n- Keep the original: --destination %s --copy\n- Drop --destination", createDestination)
+		}
+		if createWaitTimeout <= 0 {
+			return fmt.Errorf("--wait-remote-timeout must be positive, got %s", createWaitTimeout)
+		}
+	}
+
+	if !globalFlags.DryRun && !quietOutput() {
+		fmt.Printf("Creating backup for StatefulSet %s in namespace %s\n", backupStatefulSetName, backupNamespace)
+	}
 
 	// Initialize Kubernetes client
-	k8sClient, err := pkg.NewK8sClient(false)
+	k8sClient, err := newK8sClient(false)
 	if err != nil {
 		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
 	}
 
 	// Get the API service from the StatefulSet
-	service, err := k8sClient.GetAPIServiceFromStatefulSet(context.Background(), backupNamespace, backupStatefulSetName)
+	service, err := k8sClient.GetAPIServiceFromStatefulSet(ctx, backupNamespace, backupStatefulSetName)
 	if err != nil {
 		return pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", backupStatefulSetName, backupNamespace))
 	}
+	if err := checkBackupMoveAllowed(ctx, k8sClient); err != nil {
+		return err
+	}
+
+	if globalFlags.DryRun {
+		return renderDryRunPlan("backup create", backupCreatePlan(service.Name)...)
+	}
+	if quietOutput() {
+		defer discardRegularOutput()()
+	}
 
 	// Set up backup options
 	options := backup.BackupOptions{
-		Username:     backupUsername,
-		Password:     backupPassword,
-		Timeout:      5 * time.Minute,
-		PollInterval: 2 * time.Second,
-		ShowProgress: true,
-		Destination:  createDestination,
+		Username:          backupUsername,
+		Password:          backupPassword,
+		Auth:              apiAuth(),
+		RequestTimeout:    createRequestTimeout,
+		PollInterval:      2 * time.Second,
+		OperationDeadline: createOperationTimeout,
+		ShowProgress:      true,
+		Destination:       createDestination,
+		TLS:               apiTLSOptions(),
+		Retry:             apiRetryPolicy(),
+		Async:             createAsync,
+		Progress:          backupProgress(),
+	}
+	if options.Pod, err = managementPod(ctx, k8sClient); err != nil {
+		return err
+	}
+
+	if createAllNodes {
+		return runBackupCreateAllNodes(ctx, k8sClient, options)
 	}
 
 	// Create backup
-	backupInfo, err := backup.CreateBackup(context.Background(), k8sClient, service, options)
+	backupInfo, err := backup.CreateBackup(ctx, k8sClient, service, options)
 	if err != nil {
 		return fmt.Errorf("backup creation failed: %w", err)
 	}
+	noteDetail("backupId", backupInfo.ID)
+	noteDetail("status", string(backupInfo.Status))
+	if backupInfo.Size > 0 {
+		noteDetail("size", formatBytes(backupInfo.Size))
+	}
+	if quietOutput() {
+		// Printed before the optional move and upload, so scripts get the ID even if those fail
+		printResult(backupInfo.ID)
+	}
+
+	if createAsync {
+		fmt.Printf("Status: %s\n", getStatusColor(backupInfo.Status).Sprint(string(backupInfo.Status)))
+		fmt.Printf("\nFollow progress with: kubectl broker backup status -n %s --id %s --wait\n", backupNamespace, backupInfo.ID)
+		return nil
+	}
 
 	// Display results
 	fmt.Printf("Backup ID: %s\n", backupInfo.ID)
 	fmt.Printf("Status: %s\n", getStatusColor(backupInfo.Status).Sprint(string(backupInfo.Status)))
 	fmt.Printf("Size: %s | Created: %s\n", formatBytes(backupInfo.Size), backupInfo.CreatedAt.Format(time.RFC3339))
 
+	// Record which pod ended up holding the backup
+	manifest, err := backup.LocateBackup(ctx, k8sClient, backupNamespace, backupStatefulSetName, backupInfo.ID)
+	if err != nil {
+		fmt.Printf("Warning: could not build backup manifest: %v\n", err)
+	} else {
+		renderBackupManifest(manifest)
+		if err := writeBackupManifestFile(manifest); err != nil {
+			return err
+		}
+	}
+
 	// Move backup directory to destination if specified
 	if createDestination != "" {
 		fmt.Printf("\nMoving backup directory to destination...\n")
+		if manifest != nil {
+			if podName, ok := manifest.PodFor(backupInfo.ID); ok {
+				if err := backup.MoveBackupToDestinationOnPod(ctx, k8sClient, backupNamespace, podName, backupInfo.ID, createDestination, backupMoveOptions()); err != nil {
+					return fmt.Errorf("backup move failed: %w", err)
+				}
+				return waitForRemoteBackup(ctx, manifest, backupInfo.ID)
+			}
+		}
+
 		err := backup.MoveBackupToDestination(
-			context.Background(),
+			ctx,
 			k8sClient,
 			backupNamespace,
 			backupStatefulSetName,
 			backupInfo.ID,
 			createDestination,
+			backupMoveOptions(),
 		)
 		if err != nil {
 			return fmt.Errorf("backup move failed: %w", err)
 		}
 	}
 
+	return waitForRemoteBackup(ctx, manifest, backupInfo.ID)
+}
+
+// waitForRemoteBackup blocks until the sidecar of the pod holding the backup has uploaded it,
+// when --wait-remote is set
+func waitForRemoteBackup(ctx context.Context, manifest *backup.Manifest, backupID string) error {
+	if !createWaitRemote {
+		return nil
+	}
+	podName := backupPodName
+	if manifest != nil {
+		if holder, ok := manifest.PodFor(backupID); ok {
+			podName = holder
+		}
+	}
+
+	fmt.Printf("\nWaiting up to %s for the sidecar to upload backup %s to remote storage\n", createWaitTimeout, backupID)
+	bar := backup.NewProgressBar(os.Stdout, "Upload")
+	var remote *sidecar.RemoteCopy
+	err := withSidecarClientOnPod(ctx, podName, 30*time.Second, func(ctx context.Context, client *sidecar.Client) error {
+		waitCtx, cancel := context.WithTimeout(ctx, createWaitTimeout)
+		defer cancel()
+		var err error
+		remote, err = sidecar.WaitForRemoteCopy(waitCtx, client, backupID, sidecar.RemoteWaitOptions{
+			Progress: func(info sidecar.BackupInfo) { bar.Update(uploadPercent(info), info.UploadedBytes) },
+		})
+		return err
+	})
+	bar.Finish()
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil:
+		noteDetail("remote", "timed out")
+		return fmt.Errorf("backup %s was created but not uploaded within %s\n\nPlease either:\n- Upload it now: kubectl broker backup push -n %s --id %s\n- Wait longer: --wait-remote-timeout 2h", backupID, createWaitTimeout, backupNamespace, backupID)
+	case errors.Is(err, sidecar.ErrUnavailable):
+		return fmt.Errorf("backup %s was created but the backup sidecar in namespace %s cannot be reached: %w\n\nPlease either:\n- Verify the sidecar container is running: kubectl get pods -n %s\n- Check the sidecar port: --sidecar-port %d", backupID, backupNamespace, err, backupNamespace, backupSidecarPort)
+	case err != nil:
+		return fmt.Errorf("backup %s was created but its remote copy could not be confirmed: %w", backupID, err)
+	}
+
+	noteDetail("remote", remote.Key)
+	fmt.Printf("Remote copy: %s (%s, after %s)\n", remote.Key, formatBytes(remote.SizeBytes), remote.Duration.Round(time.Second))
+	return nil
+}
+
+// backupCreatePlan lists the steps of a single-namespace backup for --dry-run
+func backupCreatePlan(serviceName string) []string {
+	target := fmt.Sprintf("StatefulSet %s in namespace %s", backupStatefulSetName, backupNamespace)
+	steps := []string{fmt.Sprintf("Create a backup of %s through the management API (service %s)", target, serviceName)}
+	if createAllNodes {
+		steps[0] = fmt.Sprintf("Create a backup on every broker node of %s", target)
+	}
+	if createAsync {
+		steps = append(steps, "Return without waiting for the backup to complete")
+		return steps
+	}
+	steps = append(steps, fmt.Sprintf("Wait up to %s for the backup to complete", createOperationTimeout), "Record which pods hold the backup")
+	if createManifestFile != "" {
+		steps = append(steps, fmt.Sprintf("Write the backup manifest to %s", createManifestFile))
+	}
+	if createDestination != "" {
+		verb := "Move"
+		if createCopy {
+			verb = "Copy"
+		}
+		steps = append(steps, fmt.Sprintf("%s the backup directory to %s on the pod after checking its volume and free space", verb, createDestination))
+	}
+	if createWaitRemote {
+		steps = append(steps, fmt.Sprintf("Wait up to %s for the backup sidecar to upload the backup to remote storage", createWaitTimeout))
+	}
+	return steps
+}
+
+// runBackupCreateMultiNamespace backs up several namespaces through the worker pool
+// checkBackupMoveAllowed refuses --destination on a StatefulSet the HiveMQ operator manages
+// unless --force-operator-managed is set
+func checkBackupMoveAllowed(ctx context.Context, k8sClient *pkg.K8sClient) error {
+	if createDestination == "" {
+		return nil
+	}
+	managedBy, err := k8sClient.StatefulSetManagedBy(ctx, backupNamespace, backupStatefulSetName)
+	if err != nil || managedBy == "" {
+		return err
+	}
+	if createForceOperator {
+		slog.Warn("Moving a backup on a StatefulSet managed by the HiveMQ operator", "statefulset", backupStatefulSetName, "managedBy", managedBy)
+		return nil
+	}
+	return operatorManagedError(&pkg.OperatorManagedError{
+		Operation: "moving the backup to --destination",
+		Resources: []pkg.OperatorManagedResource{{Kind: "StatefulSet", Namespace: backupNamespace, Name: backupStatefulSetName, Reason: managedBy}},
+	})
+}
+
+func runBackupCreateMultiNamespace(ctx context.Context) error {
+	if err := mutuallyExclusive(len(createNamespaces) > 0, "--namespaces", createAllHiveMQ, "--all-hivemq-namespaces"); err != nil {
+		return err
+	}
+	scopeFlag := "--namespaces"
+	if createAllHiveMQ {
+		scopeFlag = "--all-hivemq-namespaces"
+	}
+	for _, conflict := range []struct {
+		set  bool
+		name string
+	}{
+		{backupNamespace != "", "--namespace"},
+		{backupPlatformName != "", "--platform"},
+		{backupPodName != "", "--pod"},
+		{backupVia == viaPod, "--via pod"},
+		{createAllNodes, "--all-nodes"},
+		{createDestination != "", "--destination"},
+		{createCopy, "--copy"},
+		{createManifestFile != "", "--manifest-file"},
+		{createWaitRemote, "--wait-remote"},
+	} {
+		if err := mutuallyExclusive(true, scopeFlag, conflict.set, conflict.name); err != nil {
+			return err
+		}
+	}
+	if createConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1, got %d", createConcurrency)
+	}
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
+	}
+
+	targets, err := multiNamespaceBackupTargets(ctx, k8sClient)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no running HiveMQ StatefulSets found\n\nPlease either:\n- Check installations: kubectl broker discover\n- Name namespaces explicitly: --namespaces ns1,ns2")
+	}
+
+	if globalFlags.DryRun {
+		steps := make([]string, 0, len(targets))
+		for _, target := range targets {
+			steps = append(steps, fmt.Sprintf("Create a backup of StatefulSet %s in namespace %s", target.StatefulSet, target.Namespace))
+		}
+		return renderDryRunPlan("backup create", steps...)
+	}
+
+	options := backup.BackupOptions{
+		Username:          backupUsername,
+		Password:          backupPassword,
+		Auth:              apiAuth(),
+		RequestTimeout:    createRequestTimeout,
+		PollInterval:      2 * time.Second,
+		OperationDeadline: createOperationTimeout,
+		TLS:               apiTLSOptions(),
+		Retry:             apiRetryPolicy(),
+		Async:             createAsync,
+	}
+
+	structured := currentOutputFormat() != "table"
+	if !structured {
+		fmt.Printf("Creating backups for %d StatefulSets (concurrency %d)\n", len(targets), min(createConcurrency, len(targets)))
+	}
+
+	started := time.Now()
+	results, err := backup.CreateBackupsConcurrently(ctx, k8sClient, targets, createConcurrency, options, func(result backup.NamespaceBackupResult) {
+		if structured {
+			return
+		}
+		if result.Error != nil {
+			fmt.Printf("  %s/%s: failed after %s\n", result.Namespace, result.StatefulSet, result.Duration.Round(time.Second))
+			return
+		}
+		fmt.Printf("  %s/%s: %s in %s\n", result.Namespace, result.StatefulSet, result.Backup.Status, result.Duration.Round(time.Second))
+	})
+	if err != nil {
+		return fmt.Errorf("multi-namespace backup interrupted: %w", err)
+	}
+
+	renderMultiNamespaceBackups(results, time.Since(started))
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d namespace backups failed", failed, len(results))
+	}
+	return nil
+}
+
+// multiNamespaceBackupTargets expands --namespaces or --all-hivemq-namespaces into StatefulSets
+func multiNamespaceBackupTargets(ctx context.Context, k8sClient *pkg.K8sClient) ([]backup.NamespaceTarget, error) {
+	if backupSelector != "" {
+		if err := mutuallyExclusive(true, "--selector", backupStatefulSetName != "", "--statefulset"); err != nil {
+			return nil, err
+		}
+		namespaces := []string{""}
+		if !createAllHiveMQ {
+			namespaces = uniqueNamespaces(createNamespaces)
+		}
+		return selectorBackupTargets(ctx, k8sClient, namespaces)
+	}
+
+	if !createAllHiveMQ {
+		statefulSet, _ := applyDefaultStatefulSet(backupStatefulSetName)
+		var targets []backup.NamespaceTarget
+		for _, namespace := range uniqueNamespaces(createNamespaces) {
+			targets = append(targets, backup.NamespaceTarget{Namespace: namespace, StatefulSet: statefulSet})
+		}
+		return targets, nil
+	}
+
+	return installationBackupTargets(ctx, k8sClient)
+}
+
+// installationBackupTargets discovers every running HiveMQ StatefulSet, limited to --statefulset when set
+func installationBackupTargets(ctx context.Context, k8sClient *pkg.K8sClient) ([]backup.NamespaceTarget, error) {
+	installations, err := k8sClient.DiscoverInstallations(ctx)
+	if err != nil {
+		return nil, pkg.EnhanceError(err, "HiveMQ installation discovery")
+	}
+
+	var targets []backup.NamespaceTarget
+	for _, installation := range installations {
+		if installation.Replicas == 0 {
+			continue
+		}
+		if backupStatefulSetName != "" && installation.StatefulSet != backupStatefulSetName {
+			continue
+		}
+		targets = append(targets, backup.NamespaceTarget{Namespace: installation.Namespace, StatefulSet: installation.StatefulSet})
+	}
+	return targets, nil
+}
+
+// selectorBackupTargets matches --selector against StatefulSets in the given namespaces; "" searches
+// all namespaces. StatefulSets scaled to zero are skipped.
+func selectorBackupTargets(ctx context.Context, k8sClient *pkg.K8sClient, namespaces []string) ([]backup.NamespaceTarget, error) {
+	var targets []backup.NamespaceTarget
+	for _, namespace := range namespaces {
+		matches, err := k8sClient.FindStatefulSetsBySelector(ctx, namespace, backupSelector)
+		if err != nil {
+			return nil, err
+		}
+		for _, sts := range matches {
+			if sts.Spec.Replicas != nil && *sts.Spec.Replicas == 0 {
+				continue
+			}
+			targets = append(targets, backup.NamespaceTarget{Namespace: sts.Namespace, StatefulSet: sts.Name})
+		}
+	}
+	return targets, nil
+}
+
+// uniqueNamespaces trims the --namespaces list and drops empty and repeated entries
+func uniqueNamespaces(namespaces []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, namespace := range namespaces {
+		namespace = strings.TrimSpace(namespace)
+		if namespace != "" && !seen[namespace] {
+			seen[namespace] = true
+			unique = append(unique, namespace)
+		}
+	}
+	return unique
+}
+
+func runBackupCreateAllNodes(ctx context.Context, k8sClient *pkg.K8sClient, options backup.BackupOptions) error {
+	manifest, err := backup.CreateBackupOnAllNodes(ctx, k8sClient, backupNamespace, backupStatefulSetName, options)
+	if manifest != nil {
+		fmt.Println()
+		renderBackupManifest(manifest)
+		if writeErr := writeBackupManifestFile(manifest); writeErr != nil {
+			return writeErr
+		}
+	}
+	// The backups of the other nodes are still reported and moved before an incomplete backup
+	// fails the command
+	if err != nil && !errors.Is(err, backup.ErrIncompleteBackup) {
+		return fmt.Errorf("backup creation failed: %w", err)
+	}
+	incompleteErr := err
+	if quietOutput() {
+		for _, entry := range manifest.Entries {
+			if entry.Present {
+				printResult(entry.BackupID)
+			}
+		}
+	}
+
+	if createDestination != "" {
+		fmt.Printf("\nMoving backup directories to destination...\n")
+		for _, entry := range manifest.Entries {
+			if !entry.Present {
+				continue
+			}
+			if err := backup.MoveBackupToDestinationOnPod(ctx, k8sClient, backupNamespace, entry.Pod, entry.BackupID, createDestination, backupMoveOptions()); err != nil {
+				return fmt.Errorf("backup move failed on %s: %w", entry.Pod, err)
+			}
+		}
+	}
+
+	if incompleteErr != nil {
+		return fmt.Errorf("backup creation failed: %w\n\nPlease either:\n- Check the failed pods listed in the manifest: kubectl broker status -n %s\n- Run the backup again: kubectl broker backup create --all-nodes", incompleteErr, backupNamespace)
+	}
 	return nil
 }
 
 func runBackupList(cmd *cobra.Command, args []string) error {
-	if err := applyBackupDefaults(); err != nil {
+	if err := applyBackupDefaults(cmd.Context()); err != nil {
+		return err
+	}
+
+	filter, err := backup.ParseListFilter(listSince, listUntil, listStatus, listSort, listRemoteLimit, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if listMaxItems < 0 {
+		return fmt.Errorf("--max-items must not be negative")
+	}
+
+	source, err := resolveBackupSource(listSource)
+	if err != nil {
 		return err
 	}
+	if source == restoreSourceManagement {
+		return runBackupListManagement(cmd.Context(), filter)
+	}
+	if listMaxItems > 0 {
+		return fmt.Errorf("--max-items only applies to the paginated management API\n\nPlease either:\n- List management backups: --source management --max-items %d\n- Cap the remote listing instead: --limit %d", listMaxItems, listMaxItems)
+	}
 
-	if err := runBackupListRemote(); err != nil {
+	if err := runBackupListRemote(cmd.Context(), filter); err != nil {
 		if errors.Is(err, sidecar.ErrUnavailable) {
 			return fmt.Errorf("backup list requires the HiveMQ backup sidecar to be deployed and accessible. "+
 				"Please verify the sidecar is running in namespace %s", backupNamespace)
@@ -300,15 +1040,19 @@ func runBackupList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func runBackupListRemote() error {
-	ctx := context.Background()
+func runBackupListRemote(ctx context.Context, filter backup.ListFilter) error {
+	// The sidecar can only cap the result; anything else needs the full inventory
+	serverLimit := 0
+	if filter.OnlyLimit() {
+		serverLimit = filter.Limit
+	}
+
 	err := withSidecarClient(ctx, 30*time.Second, func(ctx context.Context, client *sidecar.Client) error {
-		backups, err := client.ListRemoteBackups(ctx, listRemoteLimit)
+		backups, err := client.ListRemoteBackups(ctx, serverLimit)
 		if err != nil {
 			return fmt.Errorf("failed to list remote backups: %w", err)
 		}
-		renderRemoteBackups(backupScopeEngineSidecar, backups)
-		return nil
+		return renderRemoteBackups(backupScopeEngineSidecar, backup.FilterList(backups, filter, remoteListEntry))
 	})
 	if err != nil {
 		if errors.Is(err, sidecar.ErrUnavailable) {
@@ -319,23 +1063,123 @@ func runBackupListRemote() error {
 	return nil
 }
 
+func runBackupListManagement(ctx context.Context, filter backup.ListFilter) error {
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
+	}
+
+	service, err := k8sClient.GetAPIServiceFromStatefulSet(ctx, backupNamespace, backupStatefulSetName)
+	if err != nil {
+		return pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", backupStatefulSetName, backupNamespace))
+	}
+
+	options := backup.BackupOptions{
+		Username: backupUsername,
+		Password: backupPassword,
+		Auth:     apiAuth(),
+		TLS:      apiTLSOptions(),
+		Retry:    apiRetryPolicy(),
+		MaxItems: listMaxItems,
+	}
+	if options.Pod, err = managementPod(ctx, k8sClient); err != nil {
+		return err
+	}
+
+	backups, err := backup.ListBackups(ctx, k8sClient, service, options)
+	if err != nil {
+		return fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	backups = backup.FilterList(backups, filter, backup.ListEntryFor)
+	var placement *backupPlacement
+	if wideOutput() && len(backups) > 0 {
+		placement = collectBackupPlacement(ctx, k8sClient)
+	}
+	return renderManagementBackups(backups, placement)
+}
+
+// backupPlacement holds the --output wide details of management backups: where each backup
+// directory lives and whether the sidecar has uploaded it
+type backupPlacement struct {
+	Locations map[string]backup.BackupLocation
+	Remote    []sidecar.RemoteBackupInfo
+	Sidecar   bool // the sidecar answered, so missing remote objects mean "not synced"
+}
+
+// collectBackupPlacement gathers the wide columns; failures leave the affected columns empty
+func collectBackupPlacement(ctx context.Context, k8sClient *pkg.K8sClient) *backupPlacement {
+	placement := &backupPlacement{}
+	locations, err := backup.LocateBackups(ctx, k8sClient, backupNamespace, backupStatefulSetName)
+	if err != nil {
+		slog.Warn("Could not locate backup directories on the broker pods", "error", err)
+	}
+	placement.Locations = locations
+
+	err = withSidecarClient(ctx, 10*time.Second, func(ctx context.Context, client *sidecar.Client) error {
+		remote, err := client.ListRemoteBackups(ctx, 0)
+		if err != nil {
+			return err
+		}
+		placement.Remote, placement.Sidecar = remote, true
+		return nil
+	})
+	if err != nil {
+		slog.Debug("Sidecar sync state unavailable", "error", err)
+	}
+	return placement
+}
+
+// remoteListEntry maps an S3 object to its filter attributes; uploaded objects are complete
+func remoteListEntry(item sidecar.RemoteBackupInfo) backup.ListEntry {
+	return backup.ListEntry{
+		CreatedAt: item.LastModified,
+		SizeBytes: item.SizeBytes,
+		Status:    backup.ListStatusCompleted,
+	}
+}
+
 func runBackupDownload(cmd *cobra.Command, args []string) error {
-	if err := applyBackupDefaults(); err != nil {
+	ctx := cmd.Context()
+	if err := applyBackupDefaults(cmd.Context()); err != nil {
 		return err
 	}
 
+	source, err := resolveBackupSource(downloadSource)
+	if err != nil {
+		return err
+	}
+	transfer, err := downloadTransferOptions()
+	if err != nil {
+		return err
+	}
+	if downloadAll {
+		return runBackupDownloadAll(cmd, source, transfer)
+	}
+	for _, name := range []string{"since", "until", "status", "concurrency"} {
+		if cmd.Flags().Changed(name) {
+			return fmt.Errorf("--%s only applies to --all\n\nPlease either:\n- Download all matching backups: --all --%s %s\n- Drop --%s", name, name, cmd.Flags().Lookup(name).Value, name)
+		}
+	}
+	if source == restoreSourceRemote {
+		return runBackupDownloadRemote(cmd.Context(), transfer)
+	}
+	if downloadVersion != "" {
+		return fmt.Errorf("--version is only supported when --source remote")
+	}
+
 	if downloadBackupID == "" && !downloadLatest {
 		return fmt.Errorf("either --id or --latest must be specified\n\nPlease either:\n- Specify a backup ID: --id <backup-id>\n- Use latest backup: --latest")
 	}
 
 	// Initialize Kubernetes client
-	k8sClient, err := pkg.NewK8sClient(false)
+	k8sClient, err := newK8sClient(false)
 	if err != nil {
 		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
 	}
 
 	// Get the API service from the StatefulSet
-	service, err := k8sClient.GetAPIServiceFromStatefulSet(context.Background(), backupNamespace, backupStatefulSetName)
+	service, err := k8sClient.GetAPIServiceFromStatefulSet(ctx, backupNamespace, backupStatefulSetName)
 	if err != nil {
 		return pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", backupStatefulSetName, backupNamespace))
 	}
@@ -344,15 +1188,24 @@ func runBackupDownload(cmd *cobra.Command, args []string) error {
 	options := backup.BackupOptions{
 		Username:     backupUsername,
 		Password:     backupPassword,
+		Auth:         apiAuth(),
 		OutputDir:    downloadOutputDir,
 		OutputFile:   downloadOutput,
 		ShowProgress: true,
+		TLS:          apiTLSOptions(),
+		Retry:        apiRetryPolicy(),
+		Transfer:     transfer,
+		Progress:     backupProgress(),
+	}
+	if options.Pod, err = managementPod(ctx, k8sClient); err != nil {
+		return err
 	}
 
 	// Handle the latest backup selection
 	backupID := downloadBackupID
+	var createdAt time.Time
 	if downloadLatest {
-		backups, err := backup.ListBackups(context.Background(), k8sClient, service, options)
+		backups, err := backup.ListBackups(ctx, k8sClient, service, options)
 		if err != nil {
 			return fmt.Errorf("failed to list backups to find latest: %w", err)
 		}
@@ -360,11 +1213,16 @@ func runBackupDownload(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("no backups found")
 		}
 		backupID = backups[0].ID // Already sorted newest first
-		fmt.Printf("Using latest backup: %s\n", backupID)
+		createdAt = backups[0].CreatedAt
+		slog.Info("Using latest backup", "id", backupID)
+	} else if status, err := backup.GetBackupStatus(ctx, k8sClient, service, backupID, options); err == nil {
+		createdAt = status.CreatedAt
+	} else {
+		slog.Debug("Could not read backup creation time for the manifest", "id", backupID, "error", err)
 	}
 
 	// Download backup
-	savedPath, err := backup.DownloadBackup(context.Background(), k8sClient, service, backupID, options)
+	savedPath, err := backup.DownloadBackup(ctx, k8sClient, service, backupID, options)
 	if err != nil {
 		return fmt.Errorf("download failed: %w", err)
 	}
@@ -377,12 +1235,19 @@ func runBackupDownload(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("\nDownload completed successfully!\n")
 	fmt.Printf("Saved to: %s\n", absPath)
+	writeDownloadMetadata(savedPath, backup.ArchiveManifest{
+		BackupID:  backupID,
+		CreatedAt: createdAtOrNil(createdAt),
+		Source:    backup.ArchiveSourceManagement,
+		Endpoint:  "service/" + service.Name,
+	})
 
 	return nil
 }
 
 func runBackupStatus(cmd *cobra.Command, args []string) error {
-	if err := applyBackupDefaults(); err != nil {
+	ctx := cmd.Context()
+	if err := applyBackupDefaults(cmd.Context()); err != nil {
 		return err
 	}
 
@@ -391,13 +1256,13 @@ func runBackupStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	// Initialize Kubernetes client
-	k8sClient, err := pkg.NewK8sClient(false)
+	k8sClient, err := newK8sClient(false)
 	if err != nil {
 		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
 	}
 
 	// Get the API service from the StatefulSet
-	service, err := k8sClient.GetAPIServiceFromStatefulSet(context.Background(), backupNamespace, backupStatefulSetName)
+	service, err := k8sClient.GetAPIServiceFromStatefulSet(ctx, backupNamespace, backupStatefulSetName)
 	if err != nil {
 		return pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", backupStatefulSetName, backupNamespace))
 	}
@@ -406,101 +1271,311 @@ func runBackupStatus(cmd *cobra.Command, args []string) error {
 	options := backup.BackupOptions{
 		Username: backupUsername,
 		Password: backupPassword,
+		Auth:     apiAuth(),
+		TLS:      apiTLSOptions(),
+		Retry:    apiRetryPolicy(),
+	}
+	if options.Pod, err = managementPod(ctx, k8sClient); err != nil {
+		return err
 	}
 
 	// Handle the latest backup selection
 	backupID := statusBackupID
 	if statusLatest {
 		backupID = "latest" // Special ID handled by GetBackupStatus
-		fmt.Printf("Checking status of latest backup\n")
+		if !quietOutput() {
+			fmt.Printf("Checking status of latest backup\n")
+		}
+	}
+
+	if statusWait {
+		options.PollInterval = 2 * time.Second
+		options.ShowProgress = !quietOutput()
+		options.Progress = backupProgress()
+		status, err := backup.WaitForBackup(ctx, k8sClient, service, backupID, options)
+		if status != nil {
+			displayBackupStatus(status)
+		}
+		return err
 	}
 
 	// Get backup status
-	status, err := backup.GetBackupStatus(context.Background(), k8sClient, service, backupID, options)
+	status, err := backup.GetBackupStatus(ctx, k8sClient, service, backupID, options)
 	if err != nil {
 		return fmt.Errorf("failed to get backup status: %w", err)
 	}
 
-	// Display status
-	statusColor := getStatusColor(status.Status)
-	fmt.Printf("Backup ID: %s\n", status.ID)
-	fmt.Printf("Status: %s\n", statusColor.Sprint(string(status.Status)))
-	fmt.Printf("Created: %s\n", status.CreatedAt.Format(time.RFC3339))
-	fmt.Printf("Size: %s\n", formatBytes(status.Size))
+	displayBackupStatus(status)
+	return nil
+}
+
+func runBackupInspect(cmd *cobra.Command, args []string) error {
+	if err := mutuallyExclusive(inspectBackupID != "", "--id", inspectFile != "", "--file"); err != nil {
+		return err
+	}
+	if inspectBackupID == "" && inspectFile == "" {
+		return fmt.Errorf("either --id or --file must be specified\n\nPlease either:\n- Inspect a backup on the cluster: --id <backup-id>\n- Inspect a downloaded archive: --file <path>")
+	}
+
+	if inspectFile != "" {
+		contents, err := backup.InspectArchive(inspectFile)
+		if err != nil {
+			return err
+		}
+		renderBackupContents(contents)
+		return nil
+	}
 
-	if status.Progress > 0 && !status.Status.IsTerminal() {
-		fmt.Printf("Progress: %d%%\n", status.Progress)
+	ctx := cmd.Context()
+	if err := applyBackupDefaults(ctx); err != nil {
+		return err
 	}
 
-	if status.Message != "" {
-		fmt.Printf("Message: %s\n", status.Message)
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
 	}
 
+	podName := backupPodName
+	if podName == "" {
+		podName, err = backup.DetectBackupPod(ctx, k8sClient, backupNamespace, backupStatefulSetName, inspectBackupID)
+		if err != nil {
+			return fmt.Errorf("failed to locate backup %s: %w\n\nPlease either:\n- Check the ID with: kubectl broker backup list\n- Inspect a downloaded archive: --file <path>", inspectBackupID, err)
+		}
+	}
+	slog.Info("Inspecting backup on pod", "backup", inspectBackupID, "pod", podName)
+
+	contents, err := backup.InspectBackupOnPod(ctx, k8sClient, backupNamespace, podName, inspectBackupID)
+	if err != nil {
+		return err
+	}
+	if contents.Files == 0 {
+		return fmt.Errorf("backup %s on pod %s contains no files", inspectBackupID, podName)
+	}
+	renderBackupContents(contents)
 	return nil
 }
 
 func runBackupRestore(cmd *cobra.Command, args []string) error {
-	if err := applyBackupDefaults(); err != nil {
+	if err := mutuallyExclusive(cmd.Flags().Changed("safety-backup"), "--safety-backup", restoreNoSafetyBackup, "--no-safety-backup"); err != nil {
+		return err
+	}
+	if restoreReplicationTimeout <= 0 {
+		return fmt.Errorf("--replication-timeout must be positive")
+	}
+	if err := applyBackupDefaults(cmd.Context()); err != nil {
 		return err
 	}
 
-	source, err := resolveRestoreSource()
+	source, err := resolveBackupSource(restoreSource)
 	if err != nil {
 		return err
 	}
 
 	switch source {
 	case restoreSourceRemote:
-		return runBackupRestoreRemote()
-	default:
-		if restoreDryRun {
-			return fmt.Errorf("--dry-run is only supported when --source remote")
+		if restoreQuiesce {
+			return fmt.Errorf("--quiesce is only supported for restores through the management API\n\nPlease either:\n- Restore through the management API: --source management\n- Restore without holding client traffic: drop --quiesce")
 		}
+		return runBackupRestoreRemote(cmd.Context())
+	default:
 		if restoreVersion != "" {
 			return fmt.Errorf("--version is only supported when --source remote")
 		}
-		return runBackupRestoreManagement()
+		return runBackupRestoreManagement(cmd.Context())
 	}
 }
 
-func runBackupRestoreManagement() error {
+func runBackupRestoreManagement(ctx context.Context) error {
 	if restoreBackupID == "" && !restoreLatest {
 		return fmt.Errorf("either --id or --latest must be specified\n\nPlease either:\n- Specify a backup ID: --id <backup-id>\n- Use latest backup: --latest")
 	}
 
-	fmt.Printf("Restoring backup for StatefulSet %s in namespace %s\n", backupStatefulSetName, backupNamespace)
+	if !globalFlags.DryRun {
+		fmt.Printf("Restoring backup for StatefulSet %s in namespace %s\n", backupStatefulSetName, backupNamespace)
+	}
 
-	k8sClient, err := pkg.NewK8sClient(false)
+	k8sClient, err := newK8sClient(false)
 	if err != nil {
 		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
 	}
 
-	service, err := k8sClient.GetAPIServiceFromStatefulSet(context.Background(), backupNamespace, backupStatefulSetName)
+	service, err := k8sClient.GetAPIServiceFromStatefulSet(ctx, backupNamespace, backupStatefulSetName)
 	if err != nil {
 		return pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", backupStatefulSetName, backupNamespace))
 	}
 
 	options := backup.BackupOptions{
-		Username:     backupUsername,
-		Password:     backupPassword,
-		Timeout:      5 * time.Minute,
-		PollInterval: 2 * time.Second,
-		ShowProgress: true,
+		Username:       backupUsername,
+		Password:       backupPassword,
+		Auth:           apiAuth(),
+		RequestTimeout: operationTimeout(5 * time.Minute),
+		PollInterval:   2 * time.Second,
+		ShowProgress:   true,
+		TLS:            apiTLSOptions(),
+		Retry:          apiRetryPolicy(),
+		Progress:       backupProgress(),
+	}
+	if options.Pod, err = managementPod(ctx, k8sClient); err != nil {
+		return err
 	}
 
 	backupID := restoreBackupID
 	if restoreLatest {
-		backupID = "latest"
-		fmt.Printf("Restoring from latest backup\n")
+		backups, err := backup.ListBackups(ctx, k8sClient, service, options)
+		if err != nil {
+			return fmt.Errorf("failed to list backups to find latest: %w", err)
+		}
+		if len(backups) == 0 {
+			return fmt.Errorf("no backups found")
+		}
+		backupID = backups[0].ID // Already sorted newest first
+		if !globalFlags.DryRun {
+			fmt.Printf("Restoring from latest backup %s\n", backupID)
+		}
 	}
 
-	if err := backup.RestoreBackup(context.Background(), k8sClient, service, backupID, options); err != nil {
+	if globalFlags.DryRun {
+		return dryRunRestoreManagement(ctx, k8sClient, service, backupID, options)
+	}
+
+	if err := checkRestoreVersion(ctx, k8sClient, backupID); err != nil {
+		return err
+	}
+
+	// Created after --latest was resolved, so the safety backup is never the one restored
+	safetyID, err := createSafetyBackup(ctx, k8sClient, service, options)
+	if err != nil {
+		return err
+	}
+
+	noteDetail("backupId", backupID)
+	noteDetail("source", restoreSourceManagement)
+	if safetyID != "" {
+		noteDetail("safetyBackupId", safetyID)
+	}
+	if restoreQuiesce {
+		held, err := runQuiescedRestore(ctx, k8sClient, service, backupID, options)
+		record := withSafetyBackup(backupRestoreAuditRecord(restoreSourceManagement, backupID, err), safetyID)
+		record.Details["quiescedServices"] = strings.Join(held, ",")
+		recordAudit(ctx, k8sClient, record)
+		printRollbackCommand(safetyID)
+		return err
+	}
+	result, err := backup.RestoreBackup(ctx, k8sClient, service, backupID, options)
+	if result != nil {
+		displayRestoreResult(result)
+	}
+	recordAudit(ctx, k8sClient, withSafetyBackup(backupRestoreAuditRecord(restoreSourceManagement, backupID, err), safetyID))
+	printRollbackCommand(safetyID)
+	if err != nil {
 		return fmt.Errorf("restore failed: %w", err)
 	}
 	return nil
 }
 
-func runBackupRestoreRemote() error {
+// dryRunRestoreManagement runs the checks a management API restore depends on and prints the
+// plan without calling the restore endpoint; it fails when the restore would be refused or fail
+func dryRunRestoreManagement(ctx context.Context, k8sClient *pkg.K8sClient, service *v1.Service, backupID string, options backup.BackupOptions) error {
+	preflight, err := backup.CheckRestorePreflight(ctx, k8sClient, service, backupNamespace, backupStatefulSetName, backupID, restoreIgnoreVersionMismatch, options)
+	if err != nil {
+		return err
+	}
+	if err := renderDryRunPlanWithChecks("backup restore", preflight.Checks, restorePlan(fmt.Sprintf("Restore backup %s through the management API (service %s)", backupID, service.Name))...); err != nil {
+		return err
+	}
+	if preflight.Failed() {
+		return fmt.Errorf("restore of backup %s would fail the preflight checks\n\nPlease either:\n- Fix the failed checks and run the dry run again\n- Pick another backup: kubectl broker backup list -n %s", backupID, backupNamespace)
+	}
+	return nil
+}
+
+// backupNotifyTarget names the namespaces and StatefulSet of a backup command in notifications
+func backupNotifyTarget() (string, string) {
+	if len(createNamespaces) > 0 {
+		return strings.Join(uniqueNamespaces(createNamespaces), ","), ""
+	}
+	if createAllHiveMQ {
+		return "all-hivemq-namespaces", ""
+	}
+	return backupNamespace, backupStatefulSetName
+}
+
+// safetyBackupEnabled reports whether restores back up the current state first
+func safetyBackupEnabled() bool {
+	return restoreSafetyBackup && !restoreNoSafetyBackup
+}
+
+// createSafetyBackup backs up the current cluster state before a restore and returns its ID,
+// or an empty ID when safety backups are disabled
+func createSafetyBackup(ctx context.Context, k8sClient *pkg.K8sClient, service *v1.Service, options backup.BackupOptions) (string, error) {
+	if !safetyBackupEnabled() {
+		slog.Warn("Skipping the pre-restore safety backup; this restore cannot be rolled back")
+		return "", nil
+	}
+
+	fmt.Println("Creating safety backup of the current state before restoring")
+	options.Async = false
+	info, err := backup.CreateBackup(ctx, k8sClient, service, options)
+	if err != nil {
+		return "", fmt.Errorf("safety backup failed, restore not started: %w\n\nPlease either:\n- Check the management API: kubectl broker backup test\n- Restore without a safety backup: --no-safety-backup", err)
+	}
+	fmt.Printf("Safety backup created: %s\n", info.ID)
+	return info.ID, nil
+}
+
+// restorePlan lists the steps of a restore for --dry-run, including the safety backup
+func restorePlan(restoreStep string) []string {
+	target := fmt.Sprintf("StatefulSet %s in namespace %s", backupStatefulSetName, backupNamespace)
+	var steps []string
+	restore := restoreStep + " onto " + target
+	if safetyBackupEnabled() {
+		steps = append(steps, "Create a safety backup of the current state of "+target)
+	} else {
+		restore += " without a safety backup"
+	}
+	if restoreQuiesce {
+		return append(steps, quiescedRestorePlan(target, restore)...)
+	}
+	return append(steps, restore)
+}
+
+// printRollbackCommand shows how to undo a restore with its safety backup
+func printRollbackCommand(safetyID string) {
+	if safetyID == "" {
+		return
+	}
+	fmt.Printf("\nTo roll back this restore, run:\n  kubectl broker backup restore -n %s --statefulset %s --source management --id %s --no-safety-backup\n", backupNamespace, backupStatefulSetName, safetyID)
+}
+
+// checkRestoreVersion refuses restores of backups made by an incompatible HiveMQ version
+func checkRestoreVersion(ctx context.Context, k8sClient *pkg.K8sClient, backupID string) error {
+	check, err := backup.CheckRestoreCompatibility(ctx, k8sClient, backupNamespace, backupStatefulSetName, backupID)
+	if err != nil {
+		slog.Warn("Could not verify backup version compatibility", "backup", backupID, "error", err)
+		return nil
+	}
+	if !check.Known {
+		slog.Warn("Could not verify backup version compatibility: "+check.Reason, "backup", backupID)
+		return nil
+	}
+	if check.Compatible {
+		if check.Reason != "" {
+			slog.Warn(check.Reason, "backup", backupID)
+		} else {
+			slog.Info("Backup version matches broker", "version", check.BackupVersion)
+		}
+		return nil
+	}
+
+	if restoreIgnoreVersionMismatch {
+		slog.Warn(check.Reason+"; continuing because --ignore-version-mismatch is set", "backup", backupID)
+		return nil
+	}
+	return fmt.Errorf("refusing to restore backup %s: %s\n\nPlease either:\n- Restore onto a broker running HiveMQ %s\n- Use --ignore-version-mismatch to restore anyway", backupID, check.Reason, check.BackupVersion)
+}
+
+func runBackupRestoreRemote(ctx context.Context) error {
 	if restoreBackupID != "" {
 		return fmt.Errorf("--id is not supported when --source remote\n\nPlease either:\n- Specify a remote backup: --version <key>\n- Use latest remote backup: --latest")
 	}
@@ -520,32 +1595,241 @@ func runBackupRestoreRemote() error {
 
 	fmt.Printf("Restoring remote backup (%s) for StatefulSet %s in namespace %s\n", version, backupStatefulSetName, backupNamespace)
 
-	return withSidecarClient(context.Background(), 10*time.Minute, func(ctx context.Context, client *sidecar.Client) error {
+	var safetyID string
+	if !globalFlags.DryRun && safetyBackupEnabled() {
+		k8sClient, err := newK8sClient(false)
+		if err != nil {
+			return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
+		}
+		service, err := k8sClient.GetAPIServiceFromStatefulSet(ctx, backupNamespace, backupStatefulSetName)
+		if err != nil {
+			return fmt.Errorf("%w\n\nPlease either:\n- Make the management API reachable for the safety backup\n- Restore without a safety backup: --no-safety-backup", pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", backupStatefulSetName, backupNamespace)))
+		}
+		safetyID, err = createSafetyBackup(ctx, k8sClient, service, backup.BackupOptions{
+			Username:       backupUsername,
+			Password:       backupPassword,
+			Auth:           apiAuth(),
+			RequestTimeout: operationTimeout(5 * time.Minute),
+			PollInterval:   2 * time.Second,
+			ShowProgress:   true,
+			TLS:            apiTLSOptions(),
+			Retry:          apiRetryPolicy(),
+			Progress:       backupProgress(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return withSidecarClient(ctx, 10*time.Minute, func(ctx context.Context, client *sidecar.Client) error {
 		result, err := client.Restore(ctx, sidecar.RestoreRequest{
 			Version: version,
-			DryRun:  restoreDryRun,
+			DryRun:  globalFlags.DryRun,
 		})
+		if !globalFlags.DryRun {
+			backupRef := version
+			if err == nil && result.Key != "" {
+				backupRef = result.Key
+			}
+			recordAudit(ctx, nil, withSafetyBackup(backupRestoreAuditRecord(restoreSourceRemote, backupRef, err), safetyID))
+			noteDetail("backup", backupRef)
+			noteDetail("source", restoreSourceRemote)
+			if safetyID != "" {
+				noteDetail("safetyBackupId", safetyID)
+			}
+			printRollbackCommand(safetyID)
+		}
 		if err != nil {
 			return fmt.Errorf("remote restore failed: %w", err)
 		}
-		renderRemoteRestoreResult(backupScopeEngineSidecar, result, restoreDryRun)
+		renderRemoteRestoreResult(backupScopeEngineSidecar, result, globalFlags.DryRun)
+		if globalFlags.DryRun && result != nil && currentOutputFormat() == "table" {
+			fmt.Println()
+			return renderDryRunPlan("backup restore", restorePlan(fmt.Sprintf("Restore remote backup %s through the backup sidecar", result.Key))...)
+		}
 		return nil
 	})
 }
 
+func runBackupDownloadRemote(ctx context.Context, transfer backup.TransferOptions) error {
+	if downloadBackupID != "" {
+		return fmt.Errorf("--id is not supported when --source remote\n\nPlease either:\n- Specify a remote backup: --version <key>\n- Use latest remote backup: --latest")
+	}
+
+	if downloadLatest && downloadVersion != "" {
+		return fmt.Errorf("--latest cannot be combined with --version when --source remote\n\nPlease either:\n- Use --latest for the newest remote backup\n- Use --version <key> to target a specific object")
+	}
+
+	version := strings.TrimSpace(downloadVersion)
+	if version == "" && !downloadLatest {
+		return fmt.Errorf("either --version or --latest must be specified when --source remote\n\nPlease either:\n- Specify a remote backup: --version <key>\n- Use latest remote backup: --latest")
+	}
+	if downloadLatest {
+		version = "latest"
+	}
+
+	fmt.Printf("Downloading remote backup (%s) for StatefulSet %s in namespace %s\n", version, backupStatefulSetName, backupNamespace)
+
+	var savedPath string
+	manifest := backup.ArchiveManifest{Source: backup.ArchiveSourceRemote}
+	err := withSidecarClient(ctx, 30*time.Minute, func(ctx context.Context, client *sidecar.Client) error {
+		presigned, err := client.PresignRemoteBackup(ctx, version)
+		switch {
+		case err == nil:
+			manifest.BackupID = presigned.Key
+			manifest.Endpoint = presignedEndpoint(presigned.URL)
+			savedPath, err = downloadPresignedObject(ctx, presigned, transfer)
+			return err
+		case errors.Is(err, sidecar.ErrNotSupported):
+			fmt.Println("Sidecar does not provide presigned URLs, streaming through the sidecar")
+		default:
+			return fmt.Errorf("failed to resolve remote backup: %w", err)
+		}
+
+		object, err := client.DownloadRemoteBackup(ctx, version)
+		if err != nil {
+			return fmt.Errorf("failed to download remote backup: %w", err)
+		}
+		defer object.Body.Close()
+
+		manifest.BackupID = object.Key
+		manifest.Endpoint = fmt.Sprintf("sidecar %s/%s:%d", backupNamespace, backupStatefulSetName, backupSidecarPort)
+		savedPath, err = backup.SaveStream(ctx, object.Body, object.SizeBytes, downloadOutputDir, remoteBackupFilename(object.Key), true, transfer)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, sidecar.ErrUnavailable) {
+			return fmt.Errorf("remote download requires the HiveMQ backup sidecar: %w", err)
+		}
+		return err
+	}
+
+	absPath, err := filepath.Abs(savedPath)
+	if err != nil {
+		absPath = savedPath
+	}
+
+	fmt.Printf("\nDownload completed successfully!\n")
+	fmt.Printf("Saved to: %s\n", absPath)
+	writeDownloadMetadata(savedPath, manifest)
+
+	return nil
+}
+
+// downloadPresignedObject fetches a presigned object directly from object storage
+func downloadPresignedObject(ctx context.Context, presigned *sidecar.PresignedObject, transfer backup.TransferOptions) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, presigned.URL, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid presigned URL: %w", err)
+	}
+
+	client := &http.Client{Transport: logging.WrapTransport(nil, "s3")}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("presigned download failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("presigned download failed: object storage returned %s", resp.Status)
+	}
+
+	size := resp.ContentLength
+	if size <= 0 {
+		size = presigned.SizeBytes
+	}
+
+	return backup.SaveStream(ctx, resp.Body, size, downloadOutputDir, remoteBackupFilename(presigned.Key), true, transfer)
+}
+
+// backupProgress returns the json-lines event writer for --progress-format json-lines. Events go
+// to stderr so stdout keeps the command's regular output.
+func backupProgress() backup.ProgressFunc {
+	if backupProgressFormat == progressFormatJSONLines {
+		return backup.NewJSONLinesProgress(os.Stderr)
+	}
+	return nil
+}
+
+// managementPod returns the pod the management API is reached on with --via pod: --pod, or the
+// first ready pod of the StatefulSet. It returns nil with --via service.
+func managementPod(ctx context.Context, k8sClient *pkg.K8sClient) (*v1.Pod, error) {
+	if backupVia != viaPod {
+		return nil, nil
+	}
+	if backupPodName == "" {
+		pod, err := firstReadyPod(ctx, k8sClient, backupNamespace, backupStatefulSetName)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("Using management API of pod", "pod", pod.Name)
+		return pod, nil
+	}
+
+	pod, err := k8sClient.GetPod(ctx, backupNamespace, backupPodName)
+	if err != nil {
+		return nil, pkg.EnhanceError(err, fmt.Sprintf("pod %s in namespace %s", backupPodName, backupNamespace))
+	}
+	if err := pkg.ValidatePodStatus(pod); err != nil {
+		return nil, err
+	}
+	return pod, nil
+}
+
+// backupMoveOptions builds the options for relocating a backup to --destination
+func backupMoveOptions() backup.MoveOptions {
+	return backup.MoveOptions{
+		Copy:         createCopy,
+		ShowProgress: true,
+		Progress:     backupProgress(),
+	}
+}
+
+// downloadTransferOptions parses --chunk-size and --limit-rate
+func downloadTransferOptions() (backup.TransferOptions, error) {
+	transfer := backup.DefaultTransferOptions
+
+	chunkSize, err := backup.ParseByteSize(downloadChunkSize)
+	if err != nil || chunkSize <= 0 || chunkSize > 64<<20 {
+		return transfer, fmt.Errorf("invalid --chunk-size %q\n\nPlease either:\n- Use a size between 1B and 64MB, e.g. --chunk-size 256KB\n- Omit --chunk-size to use the default of 32KB", downloadChunkSize)
+	}
+	transfer.ChunkSize = int(chunkSize)
+	transfer.Progress = backupProgress()
+
+	if downloadLimitRate != "" {
+		if transfer.RateLimit, err = backup.ParseRate(downloadLimitRate); err != nil || transfer.RateLimit <= 0 {
+			return transfer, fmt.Errorf("invalid --limit-rate %q\n\nPlease either:\n- Use a rate per second, e.g. --limit-rate 10MB/s\n- Omit --limit-rate to download without a limit", downloadLimitRate)
+		}
+	}
+	return transfer, nil
+}
+
+// remoteBackupFilename picks the local filename for a remote object key
+func remoteBackupFilename(key string) string {
+	if downloadOutput != "" {
+		return downloadOutput
+	}
+	name := path.Base(strings.TrimSpace(key))
+	if name == "" || name == "." || name == "/" || name == "latest" {
+		return fmt.Sprintf("remote-backup-%s.backup", time.Now().Format("20060102-150405"))
+	}
+	return name
+}
+
 func runBackupTest(cmd *cobra.Command, args []string) error {
-	if err := applyBackupDefaults(); err != nil {
+	ctx := cmd.Context()
+	if err := applyBackupDefaults(cmd.Context()); err != nil {
 		return err
 	}
 
 	// Initialize Kubernetes client
-	k8sClient, err := pkg.NewK8sClient(false)
+	k8sClient, err := newK8sClient(false)
 	if err != nil {
 		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
 	}
 
 	// Get the API service from the StatefulSet
-	service, err := k8sClient.GetAPIServiceFromStatefulSet(context.Background(), backupNamespace, backupStatefulSetName)
+	service, err := k8sClient.GetAPIServiceFromStatefulSet(ctx, backupNamespace, backupStatefulSetName)
 	if err != nil {
 		return pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", backupStatefulSetName, backupNamespace))
 	}
@@ -571,10 +1855,18 @@ func runBackupTest(cmd *cobra.Command, args []string) error {
 	pf := pkg.NewPortForwarder(k8sClient.GetConfig(), k8sClient.GetRESTClient())
 
 	// Use service port forwarding to test API
-	err = pf.PerformWithServicePortForwarding(context.Background(), k8sClient, service, apiPort, localPort, func(localPort int) error {
+	err = pf.PerformWithServicePortForwarding(ctx, k8sClient, service, apiPort, localPort, func(localPort int) error {
 		// Create base URL for the backup API
-		baseURL := fmt.Sprintf("http://localhost:%d", localPort)
-		client := backup.NewClient(baseURL, backupUsername, backupPassword)
+		tlsOptions := apiTLSOptions()
+		baseURL := tlsOptions.BaseURL(localPort)
+		client, err := backup.NewClientWithConfig(baseURL, backupUsername, backupPassword, backup.ClientConfig{
+			TLS:   tlsOptions,
+			Retry: apiRetryPolicy(),
+			Auth:  apiAuth(),
+		})
+		if err != nil {
+			return err
+		}
 
 		fmt.Printf("Testing management API at: %s\n", baseURL)
 
@@ -587,7 +1879,7 @@ func runBackupTest(cmd *cobra.Command, args []string) error {
 
 		// Try to list backups to test backup endpoint specifically
 		fmt.Printf("Testing backup endpoint...\n")
-		_, err := client.ListBackups()
+		_, err = client.ListBackups()
 		if err != nil {
 			fmt.Printf("Backup endpoint test failed: %v\n", err)
 			fmt.Printf("This might mean backup functionality is not enabled on this HiveMQ instance.\n")
@@ -607,8 +1899,8 @@ func runBackupTest(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func resolveRestoreSource() (string, error) {
-	value := strings.ToLower(strings.TrimSpace(restoreSource))
+func resolveBackupSource(source string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(source))
 	switch value {
 	case "", restoreSourceAuto:
 		return restoreSourceManagement, nil
@@ -617,16 +1909,31 @@ func resolveRestoreSource() (string, error) {
 	case "remote":
 		return restoreSourceRemote, nil
 	default:
-		return "", fmt.Errorf("invalid restore source %q. Supported values: management, remote, or auto", restoreSource)
+		return "", fmt.Errorf("invalid source %q. Supported values: management, remote, or auto", source)
 	}
 }
 
 func withSidecarClient(ctx context.Context, timeout time.Duration, fn func(context.Context, *sidecar.Client) error) error {
+	return withSidecarClientOnPod(ctx, backupPodName, timeout, fn)
+}
+
+// withSidecarClientOnPod connects to the sidecar of podName, or of the first ready pod when empty.
+// With --sidecar-service the sidecar is reached through the Service and podName is ignored.
+func withSidecarClientOnPod(ctx context.Context, podName string, timeout time.Duration, fn func(context.Context, *sidecar.Client) error) error {
 	if backupSidecarPort <= 0 || backupSidecarPort > 65535 {
 		return fmt.Errorf("invalid sidecar-port %d. Port must be between 1 and 65535", backupSidecarPort)
 	}
+	if (backupSidecarTLS.ClientCertFile == "") != (backupSidecarTLS.ClientKeyFile == "") {
+		return fmt.Errorf("--sidecar-cert and --sidecar-key must be set together")
+	}
+	if backupSidecarService != "" {
+		if podName != "" {
+			slog.Debug("Ignoring pod for the sidecar connection, using the sidecar service", "pod", podName, "service", backupSidecarService)
+		}
+		podName = ""
+	}
 
-	k8sClient, err := pkg.NewK8sClient(false)
+	k8sClient, err := newK8sClient(false)
 	if err != nil {
 		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
 	}
@@ -635,15 +1942,37 @@ func withSidecarClient(ctx context.Context, timeout time.Duration, fn func(conte
 	opts := sidecar.ConnectOptions{
 		Namespace:   backupNamespace,
 		StatefulSet: backupStatefulSetName,
-		Pod:         backupPodName,
+		Pod:         podName,
+		Service:     backupSidecarService,
 		RemotePort:  int32(backupSidecarPort),
-		Timeout:     timeout,
+		TLS:         backupSidecarTLS,
+		Timeout:     operationTimeout(timeout),
+		// Restores and downloads can run for minutes; survive idle-closed or dropped forwards
+		Reconnect: true,
 	}
 	return connector.WithConnection(ctx, opts, func(client *sidecar.Client) error {
 		return fn(ctx, client)
 	})
 }
 
+// writeBackupManifestFile stores the manifest as JSON when --manifest-file is set
+func writeBackupManifestFile(manifest *backup.Manifest) error {
+	if createManifestFile == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode backup manifest: %w", err)
+	}
+	if err := os.WriteFile(createManifestFile, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write backup manifest: %w", err)
+	}
+
+	fmt.Printf("Manifest written to %s\n", createManifestFile)
+	return nil
+}
+
 // getStatusColor returns a color function for the given backup status
 func getStatusColor(status backup.BackupStatus) *color.Color {
 	switch status {
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/backup"
+)
+
+var (
+	checksumFile         string
+	checksumChecksumFile string
+)
+
+func newBackupChecksumCommand() *cobra.Command {
+	var checksumCmd = &cobra.Command{
+		Use:   "checksum",
+		Short: "Verify a downloaded backup archive against its checksum file",
+		Long: `Checksum recomputes the SHA-256 of a downloaded backup archive and compares it
+with the <archive>.sha256 file written by "backup download". The command exits
+with an error when the digests differ, e.g. after a corrupted transfer to or
+from cold storage. The checksum file uses the sha256sum format, so
+"sha256sum -c" works as well.
+
+Examples:
+  # Verify an archive next to its checksum file
+  kubectl broker backup checksum --file ./backups/backup-20250101.tar.gz
+
+  # Verify against a checksum file kept elsewhere
+  kubectl broker backup checksum --file backup.tar.gz --checksum-file /archive/backup.tar.gz.sha256`,
+		Args: cobra.NoArgs,
+		RunE: runBackupChecksum,
+	}
+
+	checksumCmd.Flags().StringVar(&checksumFile, "file", "", "Backup archive to verify")
+	checksumCmd.Flags().StringVar(&checksumChecksumFile, "checksum-file", "", "Checksum file (defaults to <file>.sha256)")
+	_ = checksumCmd.MarkFlagRequired("file")
+
+	return checksumCmd
+}
+
+func runBackupChecksum(cmd *cobra.Command, args []string) error {
+	result, err := backup.VerifyArchive(checksumFile, checksumChecksumFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w\n\nPlease either:\n- Check the archive path: --file <archive>\n- Point to the checksum file: --checksum-file <path>", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	renderChecksumResult(result)
+	if !result.OK {
+		return fmt.Errorf("checksum mismatch for %s", result.File)
+	}
+	return nil
+}
+
+// writeDownloadMetadata writes the checksum file and archive manifest of a finished download.
+// Failures are reported but do not fail the download itself.
+func writeDownloadMetadata(savedPath string, manifest backup.ArchiveManifest) {
+	manifest.Context, _ = pkg.CurrentKubeIdentity()
+	manifest.Namespace = backupNamespace
+	manifest.StatefulSet = backupStatefulSetName
+
+	written, err := backup.WriteArchiveMetadata(savedPath, manifest)
+	if err != nil {
+		slog.Warn("Could not write backup checksum and manifest", "file", savedPath, "error", err)
+		return
+	}
+	fmt.Printf("SHA-256:  %s\n", written.SHA256)
+	fmt.Printf("Manifest: %s\n", savedPath+backup.ManifestSuffix)
+}
+
+// createdAtOrNil returns nil for unknown creation times so they are omitted from manifests
+func createdAtOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// presignedEndpoint strips the signature from a presigned URL before it is recorded
+func presignedEndpoint(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "presigned URL"
+	}
+	return parsed.Scheme + "://" + parsed.Host + parsed.Path
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/backup"
+)
+
+var (
+	cloneFromNamespace string
+	cloneToNamespace   string
+	cloneToStatefulSet string
+	cloneBackupID      string
+	cloneLatest        bool
+	cloneSkipRestore   bool
+)
+
+func newBackupCloneCommand() *cobra.Command {
+	var cloneCmd = &cobra.Command{
+		Use:   "clone",
+		Short: "Copy a backup to another namespace or StatefulSet and restore it there",
+		Long: `Clone copies a backup from the broker cluster it was created on into the backup
+folder of another HiveMQ StatefulSet and restores it there, e.g. to seed a
+staging cluster with production state. This operation will:
+1. Resolve the backup (--id or --latest) in the source StatefulSet
+2. Find the pod holding the backup and a running pod of the target StatefulSet
+3. Stream the backup directory between the two pods, without a local copy
+4. Restore the backup through the target broker's management API
+
+The source is --from-namespace (defaults to --namespace or the current context)
+and --statefulset. The target StatefulSet defaults to the source's name. The
+clone is refused when the target already holds a backup with the same ID. A
+failed copy is removed from the target pod.
+
+The restore replaces the target cluster's state and no safety backup is taken;
+run "backup create" against the target first to keep it. With --skip-restore
+the backup is only copied and can be restored later with "backup restore".
+
+Examples:
+  # Restore the latest production backup into staging
+  kubectl broker backup clone --from-namespace prod --to-namespace staging --latest
+
+  # Clone a specific backup into a differently named StatefulSet
+  kubectl broker backup clone -n prod --id abc123 --to-namespace staging --to-statefulset broker-staging
+
+  # Only copy the backup, restore it later
+  kubectl broker backup clone --from-namespace prod --to-namespace staging --id abc123 --skip-restore
+
+  # Show the source and target pods without copying anything
+  kubectl broker backup clone --from-namespace prod --to-namespace staging --latest --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: runBackupClone,
+	}
+
+	cloneCmd.Flags().StringVar(&cloneFromNamespace, "from-namespace", "", "Namespace of the source StatefulSet (defaults to --namespace or the current context)")
+	cloneCmd.Flags().StringVar(&cloneToNamespace, "to-namespace", "", "Namespace of the target StatefulSet (defaults to the source namespace)")
+	cloneCmd.Flags().StringVar(&cloneToStatefulSet, "to-statefulset", "", "Target StatefulSet (defaults to the source StatefulSet)")
+	cloneCmd.Flags().StringVar(&cloneBackupID, "id", "", "Backup ID to clone")
+	cloneCmd.Flags().BoolVar(&cloneLatest, "latest", false, "Clone the latest backup of the source")
+	cloneCmd.Flags().BoolVar(&cloneSkipRestore, "skip-restore", false, "Only copy the backup into the target's backup folder")
+
+	return cloneCmd
+}
+
+func runBackupClone(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if err := mutuallyExclusive(cloneBackupID != "", "--id", cloneLatest, "--latest"); err != nil {
+		return err
+	}
+	if cloneBackupID == "" && !cloneLatest {
+		return fmt.Errorf("no backup selected\n\nPlease either:\n- Clone a specific backup: --id <backup-id>\n- Clone the newest backup: --latest")
+	}
+	if cloneFromNamespace != "" {
+		if backupNamespace != "" && backupNamespace != cloneFromNamespace {
+			return mutuallyExclusive(true, "--from-namespace", true, "--namespace")
+		}
+		backupNamespace = cloneFromNamespace
+	}
+	if err := applyBackupDefaults(ctx); err != nil {
+		return err
+	}
+
+	options := backup.CloneOptions{
+		Source:      backup.CloneEndpoint{Namespace: backupNamespace, StatefulSet: backupStatefulSetName},
+		Target:      backup.CloneEndpoint{Namespace: cloneToNamespace, StatefulSet: cloneToStatefulSet},
+		BackupID:    cloneBackupID,
+		SkipRestore: cloneSkipRestore,
+		Backup: backup.BackupOptions{
+			Username:       backupUsername,
+			Password:       backupPassword,
+			Auth:           apiAuth(),
+			RequestTimeout: operationTimeout(5 * time.Minute),
+			PollInterval:   2 * time.Second,
+			ShowProgress:   currentOutputFormat() == "table",
+			TLS:            apiTLSOptions(),
+			Retry:          apiRetryPolicy(),
+			Progress:       backupProgress(),
+		},
+	}
+	if cloneLatest {
+		options.BackupID = "latest"
+	}
+	if options.Target.Namespace == "" {
+		options.Target.Namespace = options.Source.Namespace
+	}
+	if options.Target.StatefulSet == "" {
+		options.Target.StatefulSet = options.Source.StatefulSet
+	}
+	if options.Source == options.Target {
+		return fmt.Errorf("source and target are both %s\n\nPlease either:\n- Clone into another namespace: --to-namespace <namespace>\n- Clone into another StatefulSet: --to-statefulset <name>\n- Restore in place with: kubectl broker backup restore", options.Source)
+	}
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
+	}
+
+	plan, err := backup.PlanClone(ctx, k8sClient, options)
+	if err != nil {
+		return err
+	}
+
+	if globalFlags.DryRun {
+		steps := []string{
+			fmt.Sprintf("Copy backup %s (%s) from %s/%s:%s to %s/%s:%s",
+				plan.BackupID, formatBytes(plan.SizeBytes),
+				plan.SourcePod.Namespace, plan.SourcePod.Name, plan.SourceFolder,
+				plan.TargetPod.Namespace, plan.TargetPod.Name, plan.TargetFolder),
+		}
+		if !options.SkipRestore {
+			steps = append(steps, fmt.Sprintf("Restore backup %s through the management API of pod %s/%s", plan.BackupID, plan.TargetPod.Namespace, plan.TargetPod.Name))
+		}
+		return renderDryRunPlan("backup clone", steps...)
+	}
+
+	if currentOutputFormat() == "table" {
+		fmt.Printf("Cloning backup %s from %s to %s\n", plan.BackupID, options.Source, options.Target)
+	}
+	result, err := backup.CloneBackup(ctx, k8sClient, plan, options)
+	if err != nil {
+		if result != nil {
+			return fmt.Errorf("%w\n\nPlease either:\n- Retry the restore: kubectl broker backup restore --id %s -n %s --statefulset %s\n- Check the target broker's logs: kubectl logs -n %s %s", err, result.BackupID, options.Target.Namespace, options.Target.StatefulSet, plan.TargetPod.Namespace, plan.TargetPod.Name)
+		}
+		return err
+	}
+	return renderCloneResult(result)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fatih/color"
+	"sigs.k8s.io/yaml"
+
+	"kubectl-broker/pkg/backup"
+)
+
+// renderCloneResult prints where the backup was copied and whether it was restored
+func renderCloneResult(result *backup.CloneResult) error {
+	if format := currentOutputFormat(); format != "table" {
+		return writeStructuredCloneResult(result, format)
+	}
+
+	color.Green("Backup %s cloned", result.BackupID)
+	fmt.Printf("Source:   %s:%s\n", result.SourcePod, result.SourcePath)
+	fmt.Printf("Target:   %s:%s\n", result.TargetPod, result.TargetPath)
+	fmt.Printf("Copied:   %s\n", formatBytes(result.Bytes))
+	if result.Restored {
+		fmt.Println("Restored: yes")
+	} else {
+		fmt.Println("Restored: no (run \"kubectl broker backup restore --id " + result.BackupID + "\" against the target to restore it)")
+	}
+	return nil
+}
+
+func writeStructuredCloneResult(result *backup.CloneResult, format string) error {
+	if format == "yaml" {
+		data, err := yaml.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to encode clone result as YAML: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode clone result as JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
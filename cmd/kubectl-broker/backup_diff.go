@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/backup"
+)
+
+var diffBackupIDs []string
+
+func newBackupDiffCommand() *cobra.Command {
+	var diffCmd = &cobra.Command{
+		Use:   "diff",
+		Short: "Compare two backups",
+		Long: `Compare the metadata and contents of two backups. The report shows the size
+delta, the persistence stores that were added, removed or changed, the change in
+the retained message count and whether the HiveMQ version that produced the
+backups differs.
+
+The backups are ordered by creation time, so deltas read as growth from the
+older to the newer backup. Contents are inspected in place on the pod that
+holds each backup; when a backup cannot be inspected, only its metadata from
+the management API is compared. Two nightly backups whose stores are identical
+are reported, as the newer one probably did not capture any changes.
+
+Examples:
+  # Compare two backups
+  kubectl broker backup diff --id 20250101-020000 --id 20250102-020000
+
+  # Compare as JSON
+  kubectl broker backup diff --id abc123 --id def456 --namespace production --output json`,
+		Args: cobra.NoArgs,
+		RunE: runBackupDiff,
+	}
+
+	diffCmd.Flags().StringArrayVar(&diffBackupIDs, "id", nil, "Backup ID to compare (specify twice)")
+	_ = diffCmd.MarkFlagRequired("id")
+
+	return diffCmd
+}
+
+func runBackupDiff(cmd *cobra.Command, args []string) error {
+	if len(diffBackupIDs) != 2 || diffBackupIDs[0] == diffBackupIDs[1] {
+		return fmt.Errorf("backup diff compares exactly two different backups, got %d --id values\n\nPlease either:\n- Specify two backup IDs: --id <older-id> --id <newer-id>\n- Look up the IDs with: kubectl broker backup list", len(diffBackupIDs))
+	}
+
+	ctx := cmd.Context()
+	if err := applyBackupDefaults(ctx); err != nil {
+		return err
+	}
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
+	}
+
+	service, err := k8sClient.GetAPIServiceFromStatefulSet(ctx, backupNamespace, backupStatefulSetName)
+	if err != nil {
+		return pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", backupStatefulSetName, backupNamespace))
+	}
+
+	options := backup.BackupOptions{
+		Username: backupUsername,
+		Password: backupPassword,
+		Auth:     apiAuth(),
+		TLS:      apiTLSOptions(),
+		Retry:    apiRetryPolicy(),
+	}
+	if options.Pod, err = managementPod(ctx, k8sClient); err != nil {
+		return err
+	}
+
+	sides := make([]backup.DiffSide, 0, len(diffBackupIDs))
+	for _, backupID := range diffBackupIDs {
+		side, err := diffSide(ctx, k8sClient, service, backupID, options)
+		if err != nil {
+			return err
+		}
+		sides = append(sides, side)
+	}
+
+	return renderBackupDiff(backup.CompareBackups(sides[0], sides[1]))
+}
+
+// diffSide reads the metadata of a backup and inspects its contents when it is still on a pod
+func diffSide(ctx context.Context, k8sClient *pkg.K8sClient, service *v1.Service, backupID string, options backup.BackupOptions) (backup.DiffSide, error) {
+	status, err := backup.GetBackupStatus(ctx, k8sClient, service, backupID, options)
+	if err != nil {
+		return backup.DiffSide{}, fmt.Errorf("failed to get backup %s: %w\n\nPlease either:\n- Check the ID with: kubectl broker backup list\n- Check the StatefulSet: --statefulset <name>", backupID, err)
+	}
+	side := backup.DiffSide{ID: status.ID, Status: status.Status, CreatedAt: status.CreatedAt, SizeBytes: status.Size}
+
+	podName := backupPodName
+	if podName == "" {
+		podName, err = backup.DetectBackupPod(ctx, k8sClient, backupNamespace, backupStatefulSetName, backupID)
+	}
+	if err == nil {
+		slog.Info("Inspecting backup on pod", "backup", backupID, "pod", podName)
+		side.Contents, err = backup.InspectBackupOnPod(ctx, k8sClient, backupNamespace, podName, backupID)
+		if err == nil && side.Contents.Files == 0 {
+			err = fmt.Errorf("backup %s on pod %s contains no files", backupID, podName)
+		}
+	}
+	if err != nil {
+		slog.Warn("Comparing backup metadata only", "backup", backupID, "error", err)
+		side.Contents = nil
+		side.InspectError = err.Error()
+	}
+	return side, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"kubectl-broker/pkg/backup"
+)
+
+var backupDiffStoreColumns = []tableColumn{
+	{Title: "STORE", Width: 32},
+	{Title: "CHANGE", Width: 10},
+	{Title: "FILES", Width: 12},
+	{Title: "BEFORE", Width: 10},
+	{Title: "AFTER", Width: 10},
+	{Title: "DELTA", Width: 11},
+}
+
+func renderBackupDiff(diff *backup.BackupDiff) error {
+	if format := currentOutputFormat(); format == "json" || format == "yaml" {
+		writeStructuredBackupOutput(diff, format)
+		return nil
+	}
+
+	fmt.Printf("Before: %s (%s, %s, %s)\n", diff.Before.ID, diff.Before.Status, diff.Before.CreatedAt.Format(time.RFC3339), formatBytes(diff.Before.SizeBytes))
+	fmt.Printf("After:  %s (%s, %s, %s)\n", diff.After.ID, diff.After.Status, diff.After.CreatedAt.Format(time.RFC3339), formatBytes(diff.After.SizeBytes))
+	fmt.Printf("Size delta: %s\n", formatByteDelta(diff.SizeDeltaBytes))
+
+	before, after := diff.Before.Contents, diff.After.Contents
+	if before == nil || after == nil {
+		for _, side := range []backup.DiffSide{diff.Before, diff.After} {
+			if side.InspectError != "" {
+				fmt.Printf("\nNote: contents of %s could not be inspected: %s\n", side.ID, side.InspectError)
+			}
+		}
+		return nil
+	}
+
+	version := valueOrDash(after.HiveMQVersion)
+	if diff.VersionChanged {
+		version = fmt.Sprintf("%s -> %s", valueOrDash(before.HiveMQVersion), valueOrDash(after.HiveMQVersion))
+	}
+	fmt.Printf("HiveMQ version: %s\n", version)
+	retained := "-"
+	if diff.RetainedMessagesDelta != nil {
+		retained = fmt.Sprintf("%d -> %d (%+d)", before.RetainedMessages, after.RetainedMessages, *diff.RetainedMessagesDelta)
+	}
+	fmt.Printf("Retained messages: %s\n\n", retained)
+
+	table := newTableWriter(backupDiffStoreColumns, 2)
+	table.header()
+	for _, store := range diff.Stores {
+		table.row(truncateString(store.Name, 32), store.Change,
+			fmt.Sprintf("%d -> %d", store.BeforeFiles, store.AfterFiles),
+			formatBytes(store.BeforeBytes), formatBytes(store.AfterBytes), formatByteDelta(store.DeltaBytes))
+	}
+
+	if diff.Unchanged {
+		fmt.Printf("\nNote: no store differs between the backups; %s may not have captured any changes\n", diff.After.ID)
+	}
+	return nil
+}
+
+// formatByteDelta formats a size change with an explicit sign
+func formatByteDelta(delta int64) string {
+	switch {
+	case delta > 0:
+		return "+" + formatBytes(delta)
+	case delta < 0:
+		return "-" + formatBytes(-delta)
+	}
+	return formatBytes(0)
+}
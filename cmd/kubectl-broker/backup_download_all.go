@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/backup"
+)
+
+// runBackupDownloadAll downloads every management backup matching the --all filters
+// concurrently and reports the result of each
+func runBackupDownloadAll(cmd *cobra.Command, source string, transfer backup.TransferOptions) error {
+	ctx := cmd.Context()
+
+	for _, conflict := range []struct {
+		set  bool
+		name string
+	}{
+		{downloadBackupID != "", "--id"},
+		{downloadLatest, "--latest"},
+		{downloadOutput != "", "--output"},
+		{downloadVersion != "", "--version"},
+		{source == restoreSourceRemote, "--source remote"},
+	} {
+		if err := mutuallyExclusive(true, "--all", conflict.set, conflict.name); err != nil {
+			return err
+		}
+	}
+	if downloadWorkers < 1 {
+		return fmt.Errorf("--concurrency must be at least 1, got %d", downloadWorkers)
+	}
+	filter, err := backup.ParseListFilter(downloadSince, downloadUntil, downloadStatus, backup.SortByCreated, 0, time.Now())
+	if err != nil {
+		return err
+	}
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
+	}
+	service, err := k8sClient.GetAPIServiceFromStatefulSet(ctx, backupNamespace, backupStatefulSetName)
+	if err != nil {
+		return pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", backupStatefulSetName, backupNamespace))
+	}
+
+	options := backup.BackupOptions{
+		Username:          backupUsername,
+		Password:          backupPassword,
+		Auth:              apiAuth(),
+		OutputDir:         downloadOutputDir,
+		OperationDeadline: operationTimeout(backup.DefaultDownloadTimeout),
+		TLS:               apiTLSOptions(),
+		Retry:             apiRetryPolicy(),
+		Transfer:          transfer,
+	}
+	if options.Pod, err = managementPod(ctx, k8sClient); err != nil {
+		return err
+	}
+
+	backups, err := backup.ListBackups(ctx, k8sClient, service, options)
+	if err != nil {
+		return fmt.Errorf("failed to list backups: %w", err)
+	}
+	selected := backup.FilterList(backups, filter, backup.ListEntryFor)
+	if len(selected) == 0 {
+		fmt.Printf("No backups in namespace %s match the filters (%d backups in total)\n", backupNamespace, len(backups))
+		return nil
+	}
+
+	if globalFlags.DryRun {
+		steps := make([]string, 0, len(selected))
+		for _, info := range selected {
+			steps = append(steps, fmt.Sprintf("Download backup %s (%s) into %s", info.ID, formatBytes(info.Size), downloadOutputDir))
+		}
+		return renderDryRunPlan("backup download --all", steps...)
+	}
+
+	structured := currentOutputFormat() != "table"
+	if !structured {
+		fmt.Printf("Downloading %d backups from namespace %s into %s (%d at a time)\n", len(selected), backupNamespace, downloadOutputDir, min(downloadWorkers, len(selected)))
+	}
+	started := time.Now()
+	results, err := backup.DownloadBackupsConcurrently(ctx, k8sClient, service, selected, downloadWorkers, options, func(result backup.DownloadResult) {
+		if result.Error != nil {
+			slog.Warn("Backup download failed", "id", result.Backup.ID, "error", result.Error)
+			return
+		}
+		slog.Info("Backup downloaded", "id", result.Backup.ID, "file", result.Path, "duration", result.Duration.Round(time.Millisecond))
+	})
+	if err != nil {
+		return fmt.Errorf("bulk download interrupted: %w", err)
+	}
+
+	// Checksums are computed after the transfers so hashing does not compete for bandwidth
+	kubeContext, _ := pkg.CurrentKubeIdentity()
+	for i := range results {
+		if results[i].Error != nil {
+			continue
+		}
+		_, err := backup.WriteArchiveMetadata(results[i].Path, backup.ArchiveManifest{
+			BackupID:    results[i].Backup.ID,
+			CreatedAt:   createdAtOrNil(results[i].Backup.CreatedAt),
+			Context:     kubeContext,
+			Source:      backup.ArchiveSourceManagement,
+			Endpoint:    "service/" + service.Name,
+			Namespace:   backupNamespace,
+			StatefulSet: backupStatefulSetName,
+		})
+		if err != nil {
+			slog.Warn("Could not write backup checksum and manifest", "file", results[i].Path, "error", err)
+		}
+	}
+
+	failed := renderBulkDownloads(results, time.Since(started))
+	if failed > 0 {
+		return fmt.Errorf("%d of %d backup downloads failed", failed, len(results))
+	}
+	return nil
+}
@@ -6,8 +6,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/fatih/color"
 	"sigs.k8s.io/yaml"
 
+	"kubectl-broker/pkg/backup"
 	"kubectl-broker/pkg/sidecar"
 )
 
@@ -17,18 +19,84 @@ var (
 		{Title: "SIZE", Width: 12},
 		{Title: "AGE", Width: 12},
 	}
+	driftColumns = []tableColumn{
+		{Title: "BACKUP", Width: 40},
+		{Title: "TYPE", Width: 8},
+		{Title: "STATUS", Width: 24},
+		{Title: "SIZE", Width: 12},
+		{Title: "AGE", Width: 10},
+		{Title: "ACTION", Width: 16},
+	}
+	managementBackupColumns = []tableColumn{
+		{Title: "BACKUP ID", Width: 36},
+		{Title: "STATUS", Width: 20},
+		{Title: "SIZE", Width: 12},
+		{Title: "AGE", Width: 12},
+	}
+	managementBackupWideColumns = withColumns(managementBackupColumns,
+		tableColumn{Title: "POD", Width: 16},
+		tableColumn{Title: "NODE", Width: 24},
+		tableColumn{Title: "SIDECAR", Width: 10},
+		tableColumn{Title: "PATH", Width: 40},
+	)
+	multiNamespaceBackupColumns = []tableColumn{
+		{Title: "NAMESPACE", Width: 36},
+		{Title: "STATEFULSET", Width: 16},
+		{Title: "BACKUP ID", Width: 36},
+		{Title: "STATUS", Width: 12},
+		{Title: "SIZE", Width: 10},
+		{Title: "DURATION", Width: 8},
+	}
+	bulkDownloadColumns = []tableColumn{
+		{Title: "BACKUP ID", Width: 36},
+		{Title: "CREATED", Width: 20},
+		{Title: "SIZE", Width: 10},
+		{Title: "RESULT", Width: 8},
+		{Title: "DURATION", Width: 8},
+		{Title: "FILE", Width: 40},
+	}
+	backupManifestColumns = []tableColumn{
+		{Title: "POD", Width: 24},
+		{Title: "BACKUP ID", Width: 36},
+		{Title: "PRESENT", Width: 7},
+		{Title: "SIZE", Width: 10},
+		{Title: "PATH", Width: 40},
+	}
+	backupStoreColumns = []tableColumn{
+		{Title: "STORE", Width: 36},
+		{Title: "FILES", Width: 8},
+		{Title: "SIZE", Width: 10},
+	}
 )
 
-func renderRemoteBackups(engine string, backups []sidecar.RemoteBackupInfo) {
+func renderRemoteBackups(engine string, backups []sidecar.RemoteBackupInfo) error {
+	if quietOutput() {
+		for _, item := range backups {
+			printResult(item.Key)
+		}
+		return nil
+	}
 	scope := backupScopeForEngine(engine)
 	switch currentOutputFormat() {
 	case "json":
 		writeStructuredBackupOutput(remoteBackupsPayload{Scope: scope, Items: backups}, "json")
 	case "yaml":
 		writeStructuredBackupOutput(remoteBackupsPayload{Scope: scope, Items: backups}, "yaml")
+	case "csv":
+		return writeRemoteBackupsCSV(backups)
 	default:
 		renderRemoteBackupTable(backups)
 	}
+	return nil
+}
+
+// writeRemoteBackupsCSV lists remote backups with exact sizes and UTC timestamps
+func writeRemoteBackupsCSV(backups []sidecar.RemoteBackupInfo) error {
+	w := newCSVWriter("object", "size_bytes", "last_modified")
+	for _, item := range backups {
+		w.row(item.Key, item.SizeBytes, csvTime(item.LastModified))
+	}
+	return w.flush()
 }
 
 func renderRemoteBackupTable(backups []sidecar.RemoteBackupInfo) {
@@ -37,18 +105,93 @@ func renderRemoteBackupTable(backups []sidecar.RemoteBackupInfo) {
 		return
 	}
 
-	renderTableHeader(remoteBackupColumns, 2)
+	table := newTableWriter(remoteBackupColumns, 2)
+	table.header()
 	now := time.Now()
 	for _, item := range backups {
-		age := formatRelativeAge(now.Sub(item.LastModified))
-		fmt.Printf("%-48s  %-12s  %-12s\n",
-			truncateString(item.Key, 48),
-			formatBytes(item.SizeBytes),
-			age)
+		table.row(truncateString(item.Key, 48), formatBytes(item.SizeBytes), formatRelativeAge(now.Sub(item.LastModified)))
 	}
 	fmt.Printf("\nSummary: %d remote backups\n", len(backups))
 }
 
+func renderManagementBackups(backups []backup.BackupInfo, placement *backupPlacement) error {
+	if quietOutput() {
+		for _, item := range backups {
+			printResult(item.ID)
+		}
+		return nil
+	}
+	switch currentOutputFormat() {
+	case "json":
+		writeStructuredBackupOutput(managementBackupsPayload{Scope: backupScopeForEngine(backupScopeEngineManagement), Items: backups}, "json")
+	case "yaml":
+		writeStructuredBackupOutput(managementBackupsPayload{Scope: backupScopeForEngine(backupScopeEngineManagement), Items: backups}, "yaml")
+	case "csv":
+		return writeManagementBackupsCSV(backups)
+	default:
+		renderManagementBackupTable(backups, placement)
+	}
+	return nil
+}
+
+// writeManagementBackupsCSV lists management backups with exact sizes and UTC timestamps
+func writeManagementBackupsCSV(backups []backup.BackupInfo) error {
+	w := newCSVWriter("id", "status", "size_bytes", "created_at")
+	for _, item := range backups {
+		w.row(item.ID, item.Status, item.Size, csvTime(item.CreatedAt))
+	}
+	return w.flush()
+}
+
+// renderManagementBackupTable lists backups; with placement (--output wide) it adds the pod,
+// node and path of each backup directory and its sidecar upload state
+func renderManagementBackupTable(backups []backup.BackupInfo, placement *backupPlacement) {
+	if len(backups) == 0 {
+		fmt.Println("No backups found.")
+		return
+	}
+
+	columns := managementBackupColumns
+	if placement != nil {
+		columns = managementBackupWideColumns
+	}
+	table := newTableWriter(columns, 2)
+	table.header()
+	now := time.Now()
+	for _, item := range backups {
+		cells := []any{
+			truncateString(item.ID, 36),
+			string(item.Status),
+			formatBytes(item.Size),
+			formatRelativeAge(now.Sub(item.CreatedAt)),
+		}
+		if placement != nil {
+			location := placement.Locations[item.ID]
+			cells = append(cells,
+				valueOrDash(location.Pod),
+				valueOrDash(location.Node),
+				placement.syncState(item.ID),
+				valueOrDash(location.Path))
+		}
+		table.row(cells...)
+	}
+	fmt.Printf("\nSummary: %d backups\n", len(backups))
+}
+
+// syncState reports whether the sidecar uploaded a backup: "synced", "pending", or "-" when the
+// sidecar could not be asked
+func (p *backupPlacement) syncState(backupID string) string {
+	if !p.Sidecar {
+		return "-"
+	}
+	for _, object := range p.Remote {
+		if strings.Contains(object.Key, backupID) {
+			return "synced"
+		}
+	}
+	return "pending"
+}
+
 func renderRemoteRestoreResult(engine string, result *sidecar.RestoreResult, dryRun bool) {
 	if result == nil {
 		fmt.Println("Remote restore completed.")
@@ -78,6 +221,321 @@ func renderRemoteRestoreResult(engine string, result *sidecar.RestoreResult, dry
 	}
 }
 
+func renderSidecarStatus(report sidecarStatusReport) {
+	if format := currentOutputFormat(); format != "table" {
+		writeStructuredBackupOutput(report, format)
+		return
+	}
+
+	fmt.Printf("Liveness:  %s\n", probeLabel(report.Liveness))
+	fmt.Printf("Readiness: %s\n", probeLabel(report.Readiness))
+
+	status := report.Status
+	if status == nil {
+		return
+	}
+
+	if report.Derived {
+		fmt.Println("\nSidecar has no status endpoint; state derived from its backup inventory")
+	}
+	fmt.Println()
+	fmt.Printf("Version:   %s\n", valueOrDash(status.Version))
+	bucket := status.Bucket
+	if bucket != "" && status.Prefix != "" {
+		bucket = bucket + "/" + strings.TrimPrefix(status.Prefix, "/")
+	}
+	fmt.Printf("Bucket:    %s\n", valueOrDash(bucket))
+	if status.Region != "" {
+		fmt.Printf("Region:    %s\n", status.Region)
+	}
+	if status.Endpoint != "" {
+		fmt.Printf("Endpoint:  %s\n", status.Endpoint)
+	}
+	if status.LastSync.IsZero() {
+		fmt.Println("Last sync: never")
+	} else {
+		fmt.Printf("Last sync: %s (%s ago)\n", status.LastSync.Local().Format(time.RFC3339), formatRelativeAge(time.Since(status.LastSync)))
+	}
+
+	if len(status.RecentErrors) == 0 {
+		fmt.Println("\nNo recent errors")
+		return
+	}
+	fmt.Println("\nRecent errors:")
+	for _, item := range status.RecentErrors {
+		when := "-"
+		if !item.Time.IsZero() {
+			when = item.Time.Local().Format(time.RFC3339)
+		}
+		fmt.Printf("  %s  %s\n", when, item.Message)
+	}
+}
+
+func probeLabel(probe sidecar.ProbeResult) string {
+	if probe.OK {
+		return fmt.Sprintf("OK (%dms)", probe.LatencyMS)
+	}
+	if probe.Error != "" {
+		return "FAILED: " + probe.Error
+	}
+	return "FAILED"
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func renderBackupManifest(manifest *backup.Manifest) {
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		writeStructuredBackupOutput(manifest, format)
+	default:
+		renderBackupManifestTable(manifest)
+	}
+}
+
+func renderBackupManifestTable(manifest *backup.Manifest) {
+	fmt.Printf("\nBackup manifest (cluster size: %d)\n", manifest.ClusterSize)
+	table := newTableWriter(backupManifestColumns, 2)
+	table.header()
+
+	for _, entry := range manifest.Entries {
+		present := "no"
+		if entry.Present {
+			present = "yes"
+		}
+		size := "-"
+		if entry.SizeBytes > 0 {
+			size = formatBytes(entry.SizeBytes)
+		}
+		location := entry.Path
+		if entry.Error != "" {
+			location = "error: " + entry.Error
+		}
+		backupID := entry.BackupID
+		if backupID == "" {
+			backupID = "-"
+		}
+
+		table.row(truncateString(entry.Pod, 24), truncateString(backupID, 36), present, size, location)
+	}
+
+	if !manifest.AllNodes && manifest.ClusterSize > 1 {
+		fmt.Println("\nNote: backup was created through the service on one node; use --all-nodes for a backup per pod")
+	}
+}
+
+func renderBackupContents(contents *backup.BackupContents) {
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		writeStructuredBackupOutput(contents, format)
+		return
+	}
+
+	fmt.Println()
+	if contents.BackupID != "" {
+		fmt.Printf("Backup ID: %s\n", contents.BackupID)
+	}
+	fmt.Printf("Source: %s\n", contents.Source)
+	fmt.Printf("HiveMQ version: %s\n", valueOrDash(contents.HiveMQVersion))
+	retained := "-"
+	if contents.RetainedMessages >= 0 {
+		retained = fmt.Sprintf("%d", contents.RetainedMessages)
+	}
+	fmt.Printf("Retained messages: %s\n", retained)
+	fmt.Printf("Total: %d files, %s\n\n", contents.Files, formatBytes(contents.TotalBytes))
+
+	table := newTableWriter(backupStoreColumns, 2)
+	table.header()
+	for _, store := range contents.Stores {
+		table.row(truncateString(store.Name, 36), store.Files, formatBytes(store.SizeBytes))
+	}
+
+	if contents.HiveMQVersion == "" || contents.RetainedMessages < 0 {
+		fmt.Println("\nNote: values shown as '-' are not recorded in the backup metadata")
+	}
+}
+
+type multiNamespaceBackupEntry struct {
+	Namespace   string `json:"namespace" yaml:"namespace"`
+	StatefulSet string `json:"statefulset" yaml:"statefulset"`
+	BackupID    string `json:"backupId,omitempty" yaml:"backupId,omitempty"`
+	Status      string `json:"status" yaml:"status"`
+	SizeBytes   int64  `json:"sizeBytes" yaml:"sizeBytes"`
+	DurationMS  int64  `json:"durationMs" yaml:"durationMs"`
+	Error       string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type multiNamespaceBackupPayload struct {
+	Succeeded  int                         `json:"succeeded" yaml:"succeeded"`
+	Failed     int                         `json:"failed" yaml:"failed"`
+	DurationMS int64                       `json:"durationMs" yaml:"durationMs"`
+	Items      []multiNamespaceBackupEntry `json:"items" yaml:"items"`
+}
+
+func renderMultiNamespaceBackups(results []backup.NamespaceBackupResult, elapsed time.Duration) {
+	payload := multiNamespaceBackupPayload{DurationMS: elapsed.Milliseconds(), Items: make([]multiNamespaceBackupEntry, 0, len(results))}
+	for _, result := range results {
+		entry := multiNamespaceBackupEntry{
+			Namespace:   result.Namespace,
+			StatefulSet: result.StatefulSet,
+			Status:      string(backup.StatusFailed),
+			DurationMS:  result.Duration.Milliseconds(),
+		}
+		if result.Error != nil {
+			entry.Error = result.Error.Error()
+			payload.Failed++
+		} else {
+			entry.BackupID = result.Backup.ID
+			entry.Status = string(result.Backup.Status)
+			entry.SizeBytes = result.Backup.Size
+			payload.Succeeded++
+		}
+		payload.Items = append(payload.Items, entry)
+	}
+
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		writeStructuredBackupOutput(payload, format)
+		return
+	}
+
+	fmt.Println()
+	table := newTableWriter(multiNamespaceBackupColumns, 2)
+	table.header()
+	var failures []multiNamespaceBackupEntry
+	for _, entry := range payload.Items {
+		size := "-"
+		if entry.SizeBytes > 0 {
+			size = formatBytes(entry.SizeBytes)
+		}
+		if entry.Error != "" {
+			failures = append(failures, entry)
+		}
+		statusColor := getStatusColor(backup.BackupStatus(entry.Status))
+		table.row(
+			truncateString(entry.Namespace, 36),
+			truncateString(entry.StatefulSet, 16),
+			valueOrDash(entry.BackupID),
+			statusColor.Sprint(entry.Status),
+			size,
+			(time.Duration(entry.DurationMS) * time.Millisecond).Round(time.Second))
+	}
+
+	for _, failure := range failures {
+		fmt.Printf("\n%s/%s: %s", failure.Namespace, failure.StatefulSet, failure.Error)
+	}
+	if len(failures) > 0 {
+		fmt.Println()
+	}
+	fmt.Printf("\nSummary: %d succeeded, %d failed in %s\n", payload.Succeeded, payload.Failed, elapsed.Round(time.Second))
+}
+
+type bulkDownloadEntry struct {
+	BackupID   string `json:"backupId" yaml:"backupId"`
+	CreatedAt  string `json:"createdAt" yaml:"createdAt"`
+	SizeBytes  int64  `json:"sizeBytes" yaml:"sizeBytes"`
+	File       string `json:"file,omitempty" yaml:"file,omitempty"`
+	DurationMS int64  `json:"durationMs" yaml:"durationMs"`
+	Error      string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type bulkDownloadPayload struct {
+	Succeeded  int                 `json:"succeeded" yaml:"succeeded"`
+	Failed     int                 `json:"failed" yaml:"failed"`
+	DurationMS int64               `json:"durationMs" yaml:"durationMs"`
+	Items      []bulkDownloadEntry `json:"items" yaml:"items"`
+}
+
+// renderBulkDownloads prints the result of every download of 'backup download --all' and
+// returns the number of failed downloads
+func renderBulkDownloads(results []backup.DownloadResult, elapsed time.Duration) int {
+	payload := bulkDownloadPayload{DurationMS: elapsed.Milliseconds(), Items: make([]bulkDownloadEntry, 0, len(results))}
+	for _, result := range results {
+		entry := bulkDownloadEntry{
+			BackupID:   result.Backup.ID,
+			CreatedAt:  result.Backup.CreatedAt.Format(time.RFC3339),
+			SizeBytes:  result.Backup.Size,
+			File:       result.Path,
+			DurationMS: result.Duration.Milliseconds(),
+		}
+		if result.Error != nil {
+			entry.Error = result.Error.Error()
+			payload.Failed++
+		} else {
+			payload.Succeeded++
+		}
+		payload.Items = append(payload.Items, entry)
+	}
+
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		writeStructuredBackupOutput(payload, format)
+		return payload.Failed
+	}
+
+	fmt.Println()
+	table := newTableWriter(bulkDownloadColumns, 2)
+	table.header()
+	for _, entry := range payload.Items {
+		result := color.GreenString("OK")
+		if entry.Error != "" {
+			result = color.RedString("FAILED")
+		}
+		table.row(
+			truncateString(entry.BackupID, 36),
+			entry.CreatedAt,
+			formatBytes(entry.SizeBytes),
+			result,
+			(time.Duration(entry.DurationMS) * time.Millisecond).Round(time.Second),
+			valueOrDash(entry.File))
+	}
+
+	for _, entry := range payload.Items {
+		if entry.Error != "" {
+			fmt.Printf("\n%s: %s", entry.BackupID, entry.Error)
+		}
+	}
+	if payload.Failed > 0 {
+		fmt.Println()
+	}
+	fmt.Printf("\nSummary: %d downloaded, %d failed in %s\n", payload.Succeeded, payload.Failed, elapsed.Round(time.Second))
+	return payload.Failed
+}
+
+// displayRestoreResult prints the outcome of a management API restore
+func displayRestoreResult(result *backup.RestoreResult) {
+	fmt.Printf("\nRestore ID: %s\n", result.RestoreID)
+	fmt.Printf("Backup ID: %s\n", result.BackupID)
+	fmt.Printf("Status: %s\n", getStatusColor(result.Status).Sprint(string(result.Status)))
+	if result.Message != "" {
+		fmt.Printf("Message: %s\n", result.Message)
+	}
+}
+
+func displayBackupStatus(status *backup.BackupStatusResponse) {
+	if quietOutput() {
+		printResult(string(status.Status))
+		return
+	}
+	statusColor := getStatusColor(status.Status)
+	fmt.Printf("Backup ID: %s\n", status.ID)
+	fmt.Printf("Status: %s\n", statusColor.Sprint(string(status.Status)))
+	fmt.Printf("Created: %s\n", status.CreatedAt.Format(time.RFC3339))
+	fmt.Printf("Size: %s\n", formatBytes(status.Size))
+
+	if status.Progress > 0 && !status.Status.IsTerminal() {
+		fmt.Printf("Progress: %d%%\n", status.Progress)
+	}
+
+	if status.Message != "" {
+		fmt.Printf("Message: %s\n", status.Message)
+	}
+}
+
 func restoreModeLabel(dryRun bool) string {
 	if dryRun {
 		return "dry-run"
@@ -110,6 +568,11 @@ type remoteBackupsPayload struct {
 	Items []sidecar.RemoteBackupInfo `json:"items" yaml:"items"`
 }
 
+type managementBackupsPayload struct {
+	Scope backupScope         `json:"scope" yaml:"scope"`
+	Items []backup.BackupInfo `json:"items" yaml:"items"`
+}
+
 func backupScopeForEngine(engine string) backupScope {
 	value := strings.ToLower(strings.TrimSpace(engine))
 	if value == "" {
@@ -122,6 +585,25 @@ func backupScopeForEngine(engine string) backupScope {
 	}
 }
 
+func renderPushResult(result *sidecar.PushResult) {
+	if format := currentOutputFormat(); format != "table" {
+		writeStructuredBackupOutput(struct {
+			Scope  backupScope         `json:"scope"`
+			Result *sidecar.PushResult `json:"result"`
+		}{Scope: backupScopeForEngine(backupScopeEngineSidecar), Result: result}, format)
+		return
+	}
+
+	if result.AlreadyUploaded {
+		fmt.Printf("Backup %s was already uploaded\n", result.Name)
+	} else {
+		fmt.Printf("Backup %s uploaded (%s in %s)\n", result.Name, formatBytes(result.SizeBytes), result.Duration.Round(time.Second))
+	}
+	if result.DeletedLocal {
+		fmt.Println("Local copy deleted from the pod")
+	}
+}
+
 func writeStructuredBackupOutput(payload any, format string) {
 	var (
 		data []byte
@@ -141,3 +623,84 @@ func writeStructuredBackupOutput(payload any, format string) {
 	}
 	fmt.Println(string(data))
 }
+
+func renderChecksumResult(result *backup.ChecksumResult) {
+	if format := currentOutputFormat(); format == "json" || format == "yaml" {
+		writeStructuredBackupOutput(result, format)
+		return
+	}
+
+	fmt.Printf("File:     %s (%s)\n", result.File, formatBytes(result.SizeBytes))
+	fmt.Printf("Expected: %s\n", result.Expected)
+	fmt.Printf("Actual:   %s\n", result.Actual)
+	state, stateColor := "OK", color.New(color.FgGreen)
+	if !result.OK {
+		state, stateColor = "MISMATCH", color.New(color.FgRed)
+	}
+	if !colorOutputEnabled() {
+		stateColor = color.New()
+	}
+	fmt.Printf("Checksum: %s\n", stateColor.Sprint(state))
+}
+
+// renderDriftReport prints the result of backup sync; fixed marks items whose upload was triggered
+func renderDriftReport(report *sidecar.DriftReport, fixed bool) {
+	if format := currentOutputFormat(); format != "table" {
+		writeStructuredBackupOutput(struct {
+			Scope  backupScope          `json:"scope"`
+			Report *sidecar.DriftReport `json:"report"`
+		}{Scope: backupScopeForEngine(backupScopeEngineSidecar), Report: report}, format)
+		return
+	}
+
+	if !report.SyncTriggered {
+		fmt.Println("Sidecar has no sync endpoint; compared as of its last scheduled sync")
+	}
+
+	now := time.Now()
+	if len(report.NotUploaded) > 0 {
+		fmt.Println("\nOn-pod backups missing from remote storage:")
+		table := newTableWriter(driftColumns, 2)
+		table.header()
+		for _, item := range report.NotUploaded {
+			action := "-"
+			if item.UploadTriggered {
+				action = color.GreenString("upload triggered")
+			} else if fixed {
+				action = color.RedString("not triggered")
+			}
+			table.row(truncateString(item.Name, 40), item.Type, driftStatus(item), formatBytes(item.SizeBytes), formatRelativeAge(now.Sub(item.LastModified)), action)
+		}
+	}
+	if len(report.Uploading) > 0 {
+		fmt.Println("\nUploads in progress:")
+		for _, item := range report.Uploading {
+			fmt.Printf("  %s (%s, %s)\n", item.Name, item.Status, formatBytes(item.SizeBytes))
+		}
+	}
+	if len(report.MissingLocally) > 0 {
+		fmt.Println("\nRemote objects without an on-pod backup:")
+		for _, object := range report.MissingLocally {
+			fmt.Printf("  %s (%s, %s ago)\n", object.Key, formatBytes(object.SizeBytes), formatRelativeAge(now.Sub(object.LastModified)))
+		}
+	}
+
+	summary := fmt.Sprintf("\nSummary: %d in sync, %d missing remotely, %d uploading, %d remote only", report.InSync, len(report.NotUploaded), len(report.Uploading), len(report.MissingLocally))
+	if report.HasDrift() {
+		fmt.Println(color.YellowString(summary))
+	} else {
+		fmt.Println(color.GreenString(summary))
+	}
+}
+
+// driftStatus labels a drifted backup; uploaded ones without a remote object point at a silent failure
+func driftStatus(item sidecar.DriftItem) string {
+	switch {
+	case item.Status == sidecar.BackupStateCompleted:
+		return "uploaded, object missing"
+	case item.Error != "":
+		return fmt.Sprintf("%s: %s", item.Status, truncateString(item.Error, 40))
+	default:
+		return valueOrDash(string(item.Status))
+	}
+}
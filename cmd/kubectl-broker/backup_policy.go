@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/backup"
+)
+
+var (
+	policyKind            string
+	policySchedule        string
+	policyRetention       string
+	policyKeep            int
+	policyVeleroNamespace string
+	policyStorageLocation string
+	policySnapshotClass   string
+	policyImage           string
+)
+
+func newBackupExportPolicyCommand() *cobra.Command {
+	var exportCmd = &cobra.Command{
+		Use:   "export-policy",
+		Short: "Generate Velero or VolumeSnapshot manifests for the broker volumes",
+		Long: `Export-policy prints Kubernetes manifests that implement a backup schedule for
+the volumes of a HiveMQ StatefulSet, so the same policy can be kept in GitOps
+next to the broker.
+
+The plugin fills in what it knows about the installation: the pod selector,
+the PVC names of all replicas, the broker container and the backup folder
+(read from a running pod). These are recorded as annotations on every
+manifest. A warning is logged when the backup folder is not on a persistent
+volume, since snapshots then do not contain the HiveMQ backups.
+
+--kind velero (default) emits a Velero Schedule in --velero-namespace that
+snapshots the broker pods and their volumes, with a pre-backup hook that
+flushes the file system of the broker container. --kind volumesnapshot emits
+a CronJob with its ServiceAccount, Role and RoleBinding that creates a CSI
+VolumeSnapshot per PVC and keeps the newest --keep snapshots of each.
+
+Manifests are printed as YAML documents; --output json prints a v1 List.
+Nothing is applied to the cluster.
+
+Examples:
+  # Nightly Velero schedule kept for 30 days
+  kubectl broker backup export-policy -n production > broker-backup-schedule.yaml
+
+  # Hourly CSI snapshots, keeping the last 24 per volume
+  kubectl broker backup export-policy --kind volumesnapshot --schedule "0 * * * *" --keep 24 --snapshot-class csi-snapclass
+
+  # Apply directly
+  kubectl broker backup export-policy -n production | kubectl apply -f -`,
+		Args: cobra.NoArgs,
+		RunE: runBackupExportPolicy,
+	}
+
+	exportCmd.Flags().StringVar(&policyKind, "kind", string(backup.PolicyVelero), "Manifests to generate: velero or volumesnapshot")
+	exportCmd.Flags().StringVar(&policySchedule, "schedule", "0 2 * * *", "Cron schedule of the backups")
+	exportCmd.Flags().StringVar(&policyRetention, "retention", "30d", "How long Velero keeps each backup (e.g., 72h, 30d, 4w)")
+	exportCmd.Flags().IntVar(&policyKeep, "keep", 7, "VolumeSnapshots kept per PVC with --kind volumesnapshot")
+	exportCmd.Flags().StringVar(&policyVeleroNamespace, "velero-namespace", "velero", "Namespace Velero is installed in")
+	exportCmd.Flags().StringVar(&policyStorageLocation, "storage-location", "", "Velero BackupStorageLocation (defaults to Velero's default location)")
+	exportCmd.Flags().StringVar(&policySnapshotClass, "snapshot-class", "", "VolumeSnapshotClass with --kind volumesnapshot (defaults to the cluster default)")
+	exportCmd.Flags().StringVar(&policyImage, "image", "", "kubectl image of the snapshot CronJob (defaults to bitnami/kubectl:latest)")
+
+	return exportCmd
+}
+
+func runBackupExportPolicy(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	retention, err := parseDurationFlag("retention", policyRetention)
+	if err != nil {
+		return err
+	}
+	options := backup.PolicyOptions{
+		Kind:            backup.PolicyKind(policyKind),
+		Schedule:        policySchedule,
+		Retention:       retention,
+		Keep:            policyKeep,
+		VeleroNamespace: policyVeleroNamespace,
+		StorageLocation: policyStorageLocation,
+		SnapshotClass:   policySnapshotClass,
+		Image:           policyImage,
+	}
+	if options.Kind != backup.PolicyVelero && options.Kind != backup.PolicyVolumeSnapshot {
+		return fmt.Errorf("unknown --kind %q\n\nPlease either:\n- Generate a Velero Schedule: --kind velero\n- Generate a CSI snapshot CronJob: --kind volumesnapshot", policyKind)
+	}
+	if options.Kind == backup.PolicyVelero && options.Retention <= 0 {
+		return fmt.Errorf("--retention must be longer than zero with --kind velero\n\nPlease either:\n- Keep backups for a period: --retention 30d or --retention 4w2d\n- Keep a number of snapshots instead: --kind volumesnapshot --keep 7")
+	}
+
+	if err := applyBackupDefaults(ctx); err != nil {
+		return err
+	}
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
+	}
+
+	target, err := backup.DiscoverPolicyTarget(ctx, k8sClient, backupNamespace, backupStatefulSetName)
+	if err != nil {
+		return pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", backupStatefulSetName, backupNamespace))
+	}
+
+	objects, err := backup.ExportPolicy(*target, options)
+	if err != nil {
+		return err
+	}
+	return renderPolicyManifests(objects)
+}
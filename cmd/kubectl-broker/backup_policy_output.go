@@ -0,0 +1,33 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+// renderPolicyManifests prints the manifests as YAML documents, or as a v1 List with --output json
+func renderPolicyManifests(objects []map[string]any) error {
+	if currentOutputFormat() == "json" {
+		list := map[string]any{"apiVersion": "v1", "kind": "List", "items": objects}
+		data, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode manifests as JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for i, object := range objects {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return fmt.Errorf("failed to encode manifests as YAML: %w", err)
+		}
+		if i > 0 {
+			fmt.Println("---")
+		}
+		fmt.Print(string(data))
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"kubectl-broker/pkg/backup"
+	"kubectl-broker/pkg/sidecar"
+)
+
+var (
+	pushBackupID    string
+	pushDeleteLocal bool
+)
+
+func newBackupPushCommand() *cobra.Command {
+	var pushCmd = &cobra.Command{
+		Use:   "push",
+		Short: "Upload an on-pod backup to remote storage now",
+		Long: `Push instructs the backup sidecar to upload a backup that is still stored on the
+pod to its configured S3 bucket immediately, instead of waiting for the
+sidecar's own upload schedule. The command waits until the sidecar reports the
+upload as finished and exits with an error if it fails.
+
+With --delete-local the on-pod copy is purged once the upload completed. Backups
+that were already uploaded are not uploaded again.
+
+Examples:
+  # Upload a backup right after creating it
+  kubectl broker backup push --id abc123
+
+  # Upload and free the space on the pod
+  kubectl broker backup push --id abc123 --delete-local`,
+		Args: cobra.NoArgs,
+		RunE: runBackupPush,
+	}
+
+	pushCmd.Flags().StringVar(&pushBackupID, "id", "", "Name of the local backup to upload")
+	pushCmd.Flags().BoolVar(&pushDeleteLocal, "delete-local", false, "Delete the on-pod copy after a successful upload")
+	_ = pushCmd.MarkFlagRequired("id")
+
+	return pushCmd
+}
+
+func runBackupPush(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if err := applyBackupDefaults(ctx); err != nil {
+		return err
+	}
+
+	if globalFlags.DryRun {
+		steps := []string{fmt.Sprintf("Upload backup %s from the sidecar in namespace %s to remote storage", pushBackupID, backupNamespace)}
+		if pushDeleteLocal {
+			steps = append(steps, "Delete the on-pod copy after the upload completed")
+		}
+		return renderDryRunPlan("backup push", steps...)
+	}
+
+	structured := currentOutputFormat() != "table"
+	if !structured {
+		fmt.Printf("Uploading backup %s from the sidecar in namespace %s\n", pushBackupID, backupNamespace)
+	}
+
+	var bar *backup.ProgressBar
+	if !structured {
+		bar = backup.NewProgressBar(os.Stdout, "Upload")
+	}
+	options := sidecar.PushOptions{
+		DeleteLocal: pushDeleteLocal,
+		Progress: func(info sidecar.BackupInfo) {
+			if bar != nil {
+				bar.Update(uploadPercent(info), info.UploadedBytes)
+			}
+		},
+	}
+
+	var result *sidecar.PushResult
+	err := withSidecarClient(ctx, 30*time.Second, func(ctx context.Context, client *sidecar.Client) error {
+		var pushErr error
+		result, pushErr = sidecar.PushBackup(ctx, client, pushBackupID, options)
+		return pushErr
+	})
+	if bar != nil {
+		bar.Finish()
+	}
+	switch {
+	case errors.Is(err, sidecar.ErrBackupNotFound):
+		return fmt.Errorf("%w\n\nPlease either:\n- Check the backup ID: kubectl broker backup list --source management\n- Target the pod that holds the backup: --pod <pod-name>", err)
+	case errors.Is(err, sidecar.ErrUnavailable):
+		return fmt.Errorf("cannot reach the backup sidecar in namespace %s: %w\n\nPlease either:\n- Verify the sidecar container is running: kubectl get pods -n %s\n- Check the sidecar port: --sidecar-port %d", backupNamespace, err, backupNamespace, backupSidecarPort)
+	case err != nil:
+		return err
+	}
+
+	renderPushResult(result)
+	return nil
+}
+
+// uploadPercent derives the progress of an upload; sidecars that do not report uploaded bytes
+// only move from 0 to 100 percent
+func uploadPercent(info sidecar.BackupInfo) int {
+	if info.Status == sidecar.BackupStateCompleted {
+		return 100
+	}
+	if info.SizeBytes <= 0 || info.UploadedBytes <= 0 {
+		return 0
+	}
+	return int(info.UploadedBytes * 100 / info.SizeBytes)
+}
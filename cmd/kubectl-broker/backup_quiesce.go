@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/audit"
+	"kubectl-broker/pkg/backup"
+)
+
+// quiescedRestorePhases is the number of phases printed by restore --quiesce
+const quiescedRestorePhases = 4
+
+// replicationPollInterval is how often the brokers are checked while a quiesced restore replicates
+const replicationPollInterval = 5 * time.Second
+
+func newBackupResumeTrafficCommand() *cobra.Command {
+	var resumeCmd = &cobra.Command{
+		Use:   "resume-traffic",
+		Short: "Route client Services held by an interrupted quiesced restore to the brokers again",
+		Long: `Resume-traffic ends the client traffic hold of 'backup restore --quiesce' when the
+restore did not get to resume it itself, for example because it was
+interrupted or the brokers did not report healthy in time. Every client Service
+of the StatefulSet whose selector carries the quiesce label routes to the
+brokers again. Check the brokers with 'kubectl broker status' first.
+
+Examples:
+  # Resume client traffic after a failed quiesced restore
+  kubectl broker backup resume-traffic --statefulset broker -n production
+
+  # Show which Services are still held
+  kubectl broker backup resume-traffic -n production --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: runBackupResumeTraffic,
+	}
+	return resumeCmd
+}
+
+func runBackupResumeTraffic(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if err := applyBackupDefaults(ctx); err != nil {
+		return err
+	}
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
+	}
+	services, err := k8sClient.FindClientServices(ctx, backupNamespace, backupStatefulSetName)
+	if err != nil {
+		return pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", backupStatefulSetName, backupNamespace))
+	}
+
+	var held []v1.Service
+	for _, service := range services.Client {
+		if pkg.IsQuiesced(&service) {
+			held = append(held, service)
+		}
+	}
+	if len(held) == 0 {
+		fmt.Printf("No client Services of StatefulSet %s in namespace %s are held\n", backupStatefulSetName, backupNamespace)
+		return nil
+	}
+
+	if globalFlags.DryRun {
+		steps := make([]string, 0, len(held))
+		for _, service := range held {
+			steps = append(steps, fmt.Sprintf("Route Service %s to the brokers again (held for: %s)", service.Name, service.Annotations[pkg.QuiescedReasonAnnotation]))
+		}
+		return renderDryRunPlan("backup resume-traffic", steps...)
+	}
+
+	resumed, err := resumeClientTraffic(ctx, k8sClient, held)
+	recordAudit(ctx, k8sClient, resumeTrafficAuditRecord(resumed, err))
+	return err
+}
+
+// runQuiescedRestore restores a backup with the client traffic of the StatefulSet held: its client
+// Services lose their endpoints, the backup is restored, every broker has to report healthy again
+// and only then are the Services routed to the brokers again. When a phase after the hold fails,
+// the traffic stays held so clients do not connect to a cluster in an unknown state.
+func runQuiescedRestore(ctx context.Context, k8sClient *pkg.K8sClient, service *v1.Service, backupID string, options backup.BackupOptions) ([]string, error) {
+	sts, err := k8sClient.GetStatefulSet(ctx, backupNamespace, backupStatefulSetName)
+	if err != nil {
+		return nil, err
+	}
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+
+	printRestorePhase(1, "Holding client traffic")
+	services, err := k8sClient.FindClientServices(ctx, backupNamespace, backupStatefulSetName)
+	if err != nil {
+		return nil, err
+	}
+	for _, shared := range services.Shared {
+		fmt.Printf("  Warning: Service %s also serves the management API and keeps accepting clients\n", shared.Name)
+	}
+	if len(services.Client) == 0 {
+		fmt.Println("  No client Services select the brokers; clients connecting directly to the pods are not held")
+	}
+	held := make([]string, 0, len(services.Client))
+	for i := range services.Client {
+		client := &services.Client[i]
+		if err := k8sClient.QuiesceService(ctx, client, "backup restore "+backupID); err != nil {
+			if _, resumeErr := resumeClientTraffic(context.WithoutCancel(ctx), k8sClient, services.Client); resumeErr != nil {
+				return held, fmt.Errorf("failed to hold traffic of Service %s: %w%s", client.Name, err, heldTrafficGuidance(held))
+			}
+			return nil, fmt.Errorf("failed to hold traffic of Service %s, restore not started: %w", client.Name, err)
+		}
+		held = append(held, client.Name)
+		fmt.Printf("  Service %s: endpoints removed\n", client.Name)
+	}
+
+	printRestorePhase(2, "Restoring backup "+backupID)
+	result, err := backup.RestoreBackup(ctx, k8sClient, service, backupID, options)
+	if err != nil {
+		return held, holdTraffic(ctx, held, fmt.Errorf("restore failed: %w%s", err, heldTrafficGuidance(held)))
+	}
+	displayRestoreResult(result)
+
+	printRestorePhase(3, fmt.Sprintf("Waiting up to %s for %d brokers to report healthy", restoreReplicationTimeout, replicas))
+	waitCtx, cancel := context.WithTimeout(ctx, restoreReplicationTimeout)
+	err = k8sClient.WaitForClusterReady(waitCtx, backupNamespace, backupStatefulSetName, replicas, scaleHealthOptions(), replicationPollInterval)
+	cancel()
+	if err != nil {
+		return held, holdTraffic(ctx, held, fmt.Errorf("backup %s was restored but %w%s", backupID, err, heldTrafficGuidance(held)))
+	}
+
+	printRestorePhase(4, "Resuming client traffic")
+	if _, err := resumeClientTraffic(ctx, k8sClient, services.Client); err != nil {
+		return held, fmt.Errorf("backup %s was restored but %w%s", backupID, err, heldTrafficGuidance(held))
+	}
+	return held, nil
+}
+
+// resumeClientTraffic routes held Services to the brokers again and returns the resumed names
+func resumeClientTraffic(ctx context.Context, k8sClient *pkg.K8sClient, services []v1.Service) ([]string, error) {
+	resumed := make([]string, 0, len(services))
+	for i := range services {
+		service := &services[i]
+		if err := k8sClient.ResumeService(ctx, service); err != nil {
+			return resumed, fmt.Errorf("failed to resume traffic of Service %s: %w", service.Name, err)
+		}
+		resumed = append(resumed, service.Name)
+		fmt.Printf("  Service %s: routing to the brokers again\n", service.Name)
+	}
+	return resumed, nil
+}
+
+// holdTraffic records an interrupted quiesced restore so the held Services are reported on exit
+func holdTraffic(ctx context.Context, held []string, err error) error {
+	if len(held) > 0 {
+		pkg.RecordInterruption(ctx, fmt.Sprintf("restore of StatefulSet %s (client traffic held on Services %s)", backupStatefulSetName, strings.Join(held, ", ")))
+	}
+	return err
+}
+
+// heldTrafficGuidance explains how to end the traffic hold after a failed quiesced restore
+func heldTrafficGuidance(held []string) string {
+	if len(held) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n\nClient traffic is still held on Services %s.\n\nPlease either:\n- Check the brokers: kubectl broker status --statefulset %s -n %s\n- Resume client traffic once they are healthy: kubectl broker backup resume-traffic --statefulset %s -n %s",
+		strings.Join(held, ", "), backupStatefulSetName, backupNamespace, backupStatefulSetName, backupNamespace)
+}
+
+// printRestorePhase announces a phase of a quiesced restore
+func printRestorePhase(phase int, description string) {
+	fmt.Printf("Phase %d/%d: %s\n", phase, quiescedRestorePhases, description)
+}
+
+// quiescedRestorePlan wraps the restore step of a --dry-run plan in the traffic hold
+func quiescedRestorePlan(target, restore string) []string {
+	return []string{
+		"Hold client traffic: remove the endpoints of the client Services of " + target,
+		restore,
+		fmt.Sprintf("Wait up to %s until every broker reports healthy", restoreReplicationTimeout),
+		"Route the held client Services to the brokers again",
+	}
+}
+
+// resumeTrafficAuditRecord records the Services that resume-traffic routed to the brokers again
+func resumeTrafficAuditRecord(resumed []string, resumeErr error) audit.Record {
+	record := audit.Record{
+		Operation: audit.OperationTrafficResume,
+		Namespace: backupNamespace,
+		Resources: make([]audit.Resource, 0, len(resumed)),
+		Outcome:   audit.OutcomeFor(len(resumed), 0, resumeErr),
+		Details:   map[string]string{"statefulSet": backupStatefulSetName},
+	}
+	for _, name := range resumed {
+		record.Resources = append(record.Resources, audit.Resource{Kind: "Service", Namespace: backupNamespace, Name: name})
+	}
+	if resumeErr != nil {
+		record.Error = resumeErr.Error()
+	}
+	return record
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/backup"
+)
+
+var (
+	reportAllNamespaces bool
+	reportMaxAge        string
+	reportConcurrency   int
+)
+
+func newBackupReportCommand() *cobra.Command {
+	var reportCmd = &cobra.Command{
+		Use:   "report",
+		Short: "Report the latest backup per HiveMQ namespace against a maximum age",
+		Long: `Report collects the backups of each HiveMQ StatefulSet through the management
+API and shows the newest backup, its age, size and status. Namespaces whose
+newest completed backup is older than --max-age, or that have no completed
+backup at all, are flagged and make the command exit with an error.
+
+Besides the global table, json, yaml and csv formats, --output html renders a
+standalone page for sharing with tenants or attaching to tickets.
+
+Examples:
+  # Which tenants are missing a backup from the last 24 hours?
+  kubectl broker backup report --all-namespaces
+
+  # Weekly SLA as a JSON document for monitoring
+  kubectl broker backup report --all-namespaces --max-age 7d --output json
+
+  # Shareable HTML report
+  kubectl broker backup report --all-namespaces --output html > backup-report.html`,
+		RunE:        runBackupReport,
+		Annotations: map[string]string{csvOutputAnnotation: "true"},
+	}
+
+	reportCmd.Flags().BoolVar(&reportAllNamespaces, "all-namespaces", false, "Report every namespace with a running HiveMQ StatefulSet")
+	reportCmd.Flags().StringVar(&reportMaxAge, "max-age", "24h", "Flag namespaces whose newest completed backup is older than this (e.g., 24h, 7d)")
+	reportCmd.Flags().IntVar(&reportConcurrency, "concurrency", backup.DefaultNamespaceConcurrency, "Maximum number of namespaces queried at the same time")
+
+	return reportCmd
+}
+
+func runBackupReport(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	maxAge, err := parsePositiveDurationFlag("max-age", reportMaxAge)
+	if err != nil {
+		return err
+	}
+	if reportConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1, got %d", reportConcurrency)
+	}
+	format := backupReportFormat()
+	if format == "" {
+		return fmt.Errorf("unsupported output format %q for backup report\n\nPlease either:\n- Use a structured format: --output json or --output yaml\n- Render a shareable page: --output html", globalFlags.Output)
+	}
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
+	}
+
+	targets, err := backupReportTargets(cmd, k8sClient)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no running HiveMQ StatefulSets found\n\nPlease either:\n- Check installations: kubectl broker discover\n- Report a single namespace: --namespace <name>")
+	}
+
+	options := backup.BackupOptions{
+		Username:       backupUsername,
+		Password:       backupPassword,
+		Auth:           apiAuth(),
+		RequestTimeout: operationTimeout(time.Minute),
+		TLS:            apiTLSOptions(),
+		Retry:          apiRetryPolicy(),
+	}
+	report, err := backup.CollectInventory(ctx, k8sClient, targets, reportConcurrency, maxAge, options)
+	if err != nil {
+		return fmt.Errorf("backup report interrupted: %w", err)
+	}
+
+	if err := renderBackupReport(report, format); err != nil {
+		return err
+	}
+	if violations := report.Violations(); violations > 0 {
+		return fmt.Errorf("%d of %d namespaces have no completed backup within %s", violations, len(report.Namespaces), reportMaxAge)
+	}
+	return nil
+}
+
+// backupReportTargets resolves the StatefulSets to report on: every running installation with
+// --all-namespaces, otherwise the StatefulSet selected by the regular backup flags
+func backupReportTargets(cmd *cobra.Command, k8sClient *pkg.K8sClient) ([]backup.NamespaceTarget, error) {
+	if !reportAllNamespaces {
+		if err := applyBackupDefaults(cmd.Context()); err != nil {
+			return nil, err
+		}
+		return []backup.NamespaceTarget{{Namespace: backupNamespace, StatefulSet: backupStatefulSetName}}, nil
+	}
+
+	for _, conflict := range []struct {
+		set  bool
+		name string
+	}{
+		{backupNamespace != "", "--namespace"},
+		{backupPlatformName != "", "--platform"},
+		{backupPodName != "", "--pod"},
+		{backupVia == viaPod, "--via pod"},
+	} {
+		if err := mutuallyExclusive(true, "--all-namespaces", conflict.set, conflict.name); err != nil {
+			return nil, err
+		}
+	}
+
+	if backupSelector != "" {
+		if err := mutuallyExclusive(true, "--selector", backupStatefulSetName != "", "--statefulset"); err != nil {
+			return nil, err
+		}
+		return selectorBackupTargets(cmd.Context(), k8sClient, []string{""})
+	}
+	return installationBackupTargets(cmd.Context(), k8sClient)
+}
+
+// backupReportFormat extends the global output formats with html; "" marks an unknown format
+func backupReportFormat() string {
+	switch format := strings.ToLower(strings.TrimSpace(globalFlags.Output)); format {
+	case "", "table", "wide":
+		return "table"
+	case "json", "yaml", "csv", "html":
+		return format
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+
+	"kubectl-broker/pkg/backup"
+)
+
+var backupReportColumns = []tableColumn{
+	{Title: "NAMESPACE", Width: 24},
+	{Title: "STATEFULSET", Width: 16},
+	{Title: "SLA", Width: 6},
+	{Title: "LAST COMPLETED", Width: 20},
+	{Title: "AGE", Width: 8},
+	{Title: "SIZE", Width: 10},
+	{Title: "LATEST STATUS", Width: 13},
+	{Title: "BACKUPS", Width: 7},
+}
+
+type backupReportPayload struct {
+	GeneratedAt   time.Time           `json:"generatedAt" yaml:"generatedAt"`
+	MaxAgeSeconds int64               `json:"maxAgeSeconds" yaml:"maxAgeSeconds"`
+	Namespaces    int                 `json:"namespaces" yaml:"namespaces"`
+	Violations    int                 `json:"violations" yaml:"violations"`
+	Items         []backupReportEntry `json:"items" yaml:"items"`
+}
+
+type backupReportEntry struct {
+	Namespace       string     `json:"namespace" yaml:"namespace"`
+	StatefulSet     string     `json:"statefulset" yaml:"statefulset"`
+	State           string     `json:"state" yaml:"state"`
+	Backups         int        `json:"backups" yaml:"backups"`
+	LastCompletedID string     `json:"lastCompletedId,omitempty" yaml:"lastCompletedId,omitempty"`
+	LastCompletedAt *time.Time `json:"lastCompletedAt,omitempty" yaml:"lastCompletedAt,omitempty"`
+	AgeSeconds      int64      `json:"ageSeconds,omitempty" yaml:"ageSeconds,omitempty"`
+	SizeBytes       int64      `json:"sizeBytes,omitempty" yaml:"sizeBytes,omitempty"`
+	LatestID        string     `json:"latestId,omitempty" yaml:"latestId,omitempty"`
+	LatestStatus    string     `json:"latestStatus,omitempty" yaml:"latestStatus,omitempty"`
+	LatestAt        *time.Time `json:"latestAt,omitempty" yaml:"latestAt,omitempty"`
+	Error           string     `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func newBackupReportPayload(report *backup.InventoryReport) backupReportPayload {
+	payload := backupReportPayload{
+		GeneratedAt:   report.GeneratedAt,
+		MaxAgeSeconds: int64(report.MaxAge.Seconds()),
+		Namespaces:    len(report.Namespaces),
+		Violations:    report.Violations(),
+		Items:         make([]backupReportEntry, 0, len(report.Namespaces)),
+	}
+	for _, namespace := range report.Namespaces {
+		entry := backupReportEntry{
+			Namespace:   namespace.Namespace,
+			StatefulSet: namespace.StatefulSet,
+			State:       namespace.State,
+			Backups:     namespace.Backups,
+			Error:       namespace.Error,
+		}
+		if completed := namespace.LastCompleted; completed != nil {
+			entry.LastCompletedID = completed.ID
+			entry.LastCompletedAt = &completed.CreatedAt
+			entry.AgeSeconds = int64(namespace.Age.Seconds())
+			entry.SizeBytes = completed.Size
+		}
+		if latest := namespace.Latest; latest != nil {
+			entry.LatestID = latest.ID
+			entry.LatestStatus = string(latest.Status)
+			entry.LatestAt = &latest.CreatedAt
+		}
+		payload.Items = append(payload.Items, entry)
+	}
+	return payload
+}
+
+func renderBackupReport(report *backup.InventoryReport, format string) error {
+	payload := newBackupReportPayload(report)
+	switch format {
+	case "json", "yaml":
+		writeStructuredBackupOutput(payload, format)
+		return nil
+	case "html":
+		return renderBackupReportHTML(payload)
+	case "csv":
+		return writeBackupReportCSV(payload)
+	}
+
+	renderTableHeader(backupReportColumns, 2)
+	useColors := colorOutputEnabled()
+	var failures []backupReportEntry
+	for _, entry := range payload.Items {
+		completed, age, size := "-", "-", "-"
+		if entry.LastCompletedAt != nil {
+			completed = entry.LastCompletedAt.Local().Format("2006-01-02 15:04:05")
+			age = formatRelativeAge(time.Duration(entry.AgeSeconds) * time.Second)
+			size = formatBytes(entry.SizeBytes)
+		}
+		if entry.Error != "" {
+			failures = append(failures, entry)
+		}
+		fmt.Printf("%-24s  %-16s  %s  %-20s  %-8s  %-10s  %s  %d\n",
+			truncateString(entry.Namespace, 24),
+			truncateString(entry.StatefulSet, 16),
+			backupReportStateColor(entry.State, useColors).Sprintf("%-6s", entry.State),
+			completed,
+			age,
+			size,
+			getStatusColor(backup.BackupStatus(entry.LatestStatus)).Sprintf("%-13s", valueOrDash(entry.LatestStatus)),
+			entry.Backups)
+	}
+
+	for _, failure := range failures {
+		fmt.Printf("\n%s/%s: %s", failure.Namespace, failure.StatefulSet, failure.Error)
+	}
+	if len(failures) > 0 {
+		fmt.Println()
+	}
+	fmt.Printf("\nSummary: %d of %d namespaces have a completed backup within %s\n",
+		payload.Namespaces-payload.Violations, payload.Namespaces, formatRelativeAge(report.MaxAge))
+	return nil
+}
+
+// writeBackupReportCSV writes one row per namespace with exact ages and sizes
+func writeBackupReportCSV(payload backupReportPayload) error {
+	w := newCSVWriter("namespace", "statefulset", "state", "last_completed_id", "last_completed_at", "age_seconds", "size_bytes", "latest_status", "backups", "error")
+	for _, entry := range payload.Items {
+		completedAt := ""
+		if entry.LastCompletedAt != nil {
+			completedAt = csvTime(*entry.LastCompletedAt)
+		}
+		w.row(entry.Namespace, entry.StatefulSet, entry.State, entry.LastCompletedID, completedAt,
+			entry.AgeSeconds, entry.SizeBytes, entry.LatestStatus, entry.Backups, entry.Error)
+	}
+	return w.flush()
+}
+
+func backupReportStateColor(state string, useColors bool) *color.Color {
+	if !useColors {
+		return color.New()
+	}
+	switch state {
+	case backup.ReportOK:
+		return color.New(color.FgGreen)
+	case backup.ReportStale:
+		return color.New(color.FgYellow)
+	default:
+		return color.New(color.FgRed)
+	}
+}
+
+func renderBackupReportHTML(payload backupReportPayload) error {
+	data := struct {
+		backupReportPayload
+		MaxAge string
+	}{payload, formatRelativeAge(time.Duration(payload.MaxAgeSeconds) * time.Second)}
+
+	if err := backupReportTemplate.Execute(os.Stdout, data); err != nil {
+		return fmt.Errorf("failed to render html output: %w", err)
+	}
+	return nil
+}
+
+var backupReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"timestamp": func(t *time.Time) string {
+		return t.UTC().Format("2006-01-02 15:04 UTC")
+	},
+	"age": func(seconds int64) string {
+		return formatRelativeAge(time.Duration(seconds) * time.Second)
+	},
+	"size":   formatBytes,
+	"orDash": valueOrDash,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>HiveMQ backup report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; }
+th, td { padding: 0.4em 0.8em; border-bottom: 1px solid #ddd; text-align: left; }
+th { background: #f4f4f4; }
+.OK { color: #1a7f37; font-weight: bold; }
+.STALE { color: #9a6700; font-weight: bold; }
+.NONE, .ERROR { color: #cf222e; font-weight: bold; }
+.error { color: #cf222e; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>HiveMQ backup report</h1>
+<p>Generated {{.GeneratedAt.Format "2006-01-02 15:04 UTC"}} &middot; maximum age {{.MaxAge}} &middot; {{.Violations}} of {{.Namespaces}} namespaces flagged</p>
+<table>
+<tr><th>Namespace</th><th>StatefulSet</th><th>SLA</th><th>Last completed</th><th>Age</th><th>Size</th><th>Latest status</th><th>Backups</th></tr>
+{{- range .Items}}
+<tr>
+<td>{{.Namespace}}</td><td>{{.StatefulSet}}</td><td class="{{.State}}">{{.State}}</td>
+{{- if .LastCompletedAt}}
+<td>{{timestamp .LastCompletedAt}}</td><td>{{age .AgeSeconds}}</td><td>{{size .SizeBytes}}</td>
+{{- else}}
+<td>-</td><td>-</td><td>-</td>
+{{- end}}
+<td>{{orDash .LatestStatus}}</td><td>{{.Backups}}</td>
+</tr>
+{{- if .Error}}
+<tr><td colspan="8" class="error">{{.Error}}</td></tr>
+{{- end}}
+{{- end}}
+</table>
+</body>
+</html>
+`))
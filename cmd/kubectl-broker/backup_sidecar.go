@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"kubectl-broker/pkg/sidecar"
+)
+
+// sidecarStatusReport combines probes and the sidecar's self-reported state
+type sidecarStatusReport struct {
+	Scope     backupScope         `json:"scope"`
+	Liveness  sidecar.ProbeResult `json:"liveness"`
+	Readiness sidecar.ProbeResult `json:"readiness"`
+	// Derived is set when the sidecar has no status endpoint and the state was inferred from its inventory
+	Derived bool            `json:"derived"`
+	Status  *sidecar.Status `json:"status,omitempty"`
+}
+
+func newBackupSidecarCommand() *cobra.Command {
+	var sidecarCmd = &cobra.Command{
+		Use:   "sidecar",
+		Short: "Inspect the HiveMQ backup sidecar",
+		Long: `Sidecar commands talk to the HiveMQ backup sidecar directly to diagnose
+misconfiguration without digging through container logs.`,
+	}
+
+	sidecarCmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Probe the backup sidecar and show its configuration and sync state",
+		Long: `Status probes the sidecar's liveness and readiness endpoints and reports its
+version, configured S3 bucket, last sync time and recent errors. Sidecars
+without a status endpoint are reported from their backup inventory instead.
+
+The command exits with an error when either probe fails.
+
+Examples:
+  # Check the sidecar of the default StatefulSet
+  kubectl broker backup sidecar status
+
+  # Check the sidecar on a specific pod as JSON
+  kubectl broker backup sidecar status --pod broker-1 --output json`,
+		RunE: runBackupSidecarStatus,
+	})
+
+	return sidecarCmd
+}
+
+func runBackupSidecarStatus(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if err := applyBackupDefaults(ctx); err != nil {
+		return err
+	}
+
+	report := sidecarStatusReport{Scope: backupScopeForEngine(backupScopeEngineSidecar)}
+	err := withSidecarClient(ctx, 15*time.Second, func(ctx context.Context, client *sidecar.Client) error {
+		report.Liveness = client.Liveness(ctx)
+		report.Readiness = client.Readiness(ctx)
+
+		status, err := client.Status(ctx)
+		if errors.Is(err, sidecar.ErrNotSupported) {
+			inventory, invErr := client.ListInventory(ctx)
+			if invErr != nil {
+				return fmt.Errorf("sidecar has no status endpoint and listing its inventory failed: %w", invErr)
+			}
+			status = sidecar.StatusFromInventory(inventory)
+			report.Derived = true
+		} else if err != nil {
+			return fmt.Errorf("failed to read sidecar status: %w", err)
+		}
+		report.Status = status
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, sidecar.ErrUnavailable) {
+			return fmt.Errorf("cannot reach the backup sidecar in namespace %s: %w\n\nPlease either:\n- Verify the sidecar container is running: kubectl get pods -n %s\n- Check the sidecar port: --sidecar-port %d\n- Target a specific pod: --pod <pod-name>", backupNamespace, err, backupNamespace, backupSidecarPort)
+		}
+		return err
+	}
+
+	renderSidecarStatus(report)
+
+	if !report.Liveness.OK || !report.Readiness.OK {
+		return fmt.Errorf("backup sidecar is not healthy (liveness: %s, readiness: %s)", probeLabel(report.Liveness), probeLabel(report.Readiness))
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"kubectl-broker/pkg/sidecar"
+)
+
+var (
+	syncCheck bool
+	syncFix   bool
+)
+
+func newBackupSyncCommand() *cobra.Command {
+	var syncCmd = &cobra.Command{
+		Use:   "sync",
+		Short: "Reconcile the sidecar's remote inventory and report drift",
+		Long: `Sync asks the backup sidecar to reconcile its remote inventory against the
+backups stored on the pod, then compares both sides:
+
+  - on-pod backups without a remote copy, including backups the sidecar reports
+    as uploaded although no remote object exists
+  - remote objects without an on-pod backup (expected after --delete-local)
+
+Backups the sidecar is still uploading are listed but are not drift. Sidecars
+without a sync endpoint are compared as of their last scheduled sync.
+
+With --check the command exits with an error when on-pod backups are missing
+from remote storage, so silent upload failures surface in CI or cron jobs
+instead of during a restore. --fix triggers an upload of every missing backup.
+The uploads are triggered, not awaited, so --check passes once they were triggered;
+use 'backup push' to upload a single backup and wait for it.
+
+Examples:
+  # Fail when on-pod backups never reached the bucket
+  kubectl broker backup sync --check
+
+  # Upload every on-pod backup that is missing remotely
+  kubectl broker backup sync --fix
+
+  # Drift report of a specific pod as JSON
+  kubectl broker backup sync --pod broker-1 --output json`,
+		Args: cobra.NoArgs,
+		RunE: runBackupSync,
+	}
+
+	syncCmd.Flags().BoolVar(&syncCheck, "check", false, "Exit with an error when on-pod backups are missing from remote storage")
+	syncCmd.Flags().BoolVar(&syncFix, "fix", false, "Trigger an upload of every on-pod backup missing from remote storage")
+
+	return syncCmd
+}
+
+func runBackupSync(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if err := applyBackupDefaults(ctx); err != nil {
+		return err
+	}
+
+	if globalFlags.DryRun {
+		steps := []string{fmt.Sprintf("Ask the sidecar in namespace %s to sync and compare on-pod backups with remote storage", backupNamespace)}
+		if syncFix {
+			steps = append(steps, "Trigger an upload of every on-pod backup missing from remote storage")
+		}
+		return renderDryRunPlan("backup sync", steps...)
+	}
+
+	var report *sidecar.DriftReport
+	err := withSidecarClient(ctx, 30*time.Second, func(ctx context.Context, client *sidecar.Client) error {
+		var driftErr error
+		report, driftErr = sidecar.CheckDrift(ctx, client, sidecar.DriftOptions{Fix: syncFix})
+		return driftErr
+	})
+	if report != nil {
+		renderDriftReport(report, syncFix)
+	}
+	if err != nil {
+		if errors.Is(err, sidecar.ErrUnavailable) {
+			return fmt.Errorf("cannot reach the backup sidecar in namespace %s: %w\n\nPlease either:\n- Verify the sidecar container is running: kubectl get pods -n %s\n- Check the sidecar port: --sidecar-port %d\n- Target a specific pod: --pod <pod-name>", backupNamespace, err, backupNamespace, backupSidecarPort)
+		}
+		return err
+	}
+
+	if syncCheck && report.HasDrift() && !syncFix {
+		return fmt.Errorf("%d on-pod backups are missing from remote storage\n\nPlease either:\n- Upload them: kubectl broker backup sync --fix\n- Check the sidecar's recent errors: kubectl broker backup sidecar status", len(report.NotUploaded))
+	}
+	return nil
+}
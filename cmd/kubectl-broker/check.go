@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+
+	"kubectl-broker/pkg"
+)
+
+var (
+	checkNamespace   string
+	checkStatefulSet string
+	checkReplicas    int32
+)
+
+func newCheckCommand() *cobra.Command {
+	var checkCmd = &cobra.Command{
+		Use:   "check",
+		Short: "Run preflight checks before changing a broker cluster",
+	}
+
+	var capacityCmd = &cobra.Command{
+		Use:   "capacity",
+		Short: "Check cluster headroom for a scale-up or restore",
+		Long: `Check whether the cluster has enough CPU, memory, pod slots and volume
+provisioning headroom to run the StatefulSet with the given number of replicas,
+and print a GO or NO-GO verdict. Without --replicas the current replica count is
+checked, which is the preflight for a restore.
+
+Node headroom is the allocatable capacity minus the requests of all running
+pods. Nodes that are cordoned, not ready, excluded by the nodeSelector or
+tainted without a matching toleration are skipped; required anti-affinity on
+kubernetes.io/hostname limits each node to one broker pod. New claims need an
+existing StorageClass (or enough Available volumes for classes without a
+provisioner), and ResourceQuotas in the namespace must leave room for the new
+pods and claims. The command exits with an error on NO-GO.
+
+Examples:
+  # Can the cluster run five brokers?
+  kubectl broker check capacity --statefulset broker --replicas 5
+
+  # Preflight before restoring into the current cluster size
+  kubectl broker check capacity -n production
+
+  # Machine-readable verdict for a pipeline
+  kubectl broker check capacity --replicas 5 --output json`,
+		Args: cobra.NoArgs,
+		RunE: runCheckCapacity,
+	}
+
+	capacityCmd.Flags().StringVarP(&checkNamespace, "namespace", "n", "", "Namespace (defaults to current kubectl context)")
+	capacityCmd.Flags().StringVar(&checkStatefulSet, "statefulset", "", "StatefulSet to check (defaults to 'broker')")
+	capacityCmd.Flags().Int32Var(&checkReplicas, "replicas", 0, "Target replica count (defaults to the current replicas)")
+
+	var disruptionCmd = &cobra.Command{
+		Use:   "disruption",
+		Short: "Check whether a node drain or zone outage keeps the cluster at quorum",
+		Long: `Check how the broker pods are protected against voluntary and involuntary
+disruptions and print a GO or NO-GO verdict. The check inspects:
+
+- the PodDisruptionBudgets selecting the broker pods, and whether they let more
+  brokers be evicted at once than the cluster can lose without quorum
+- required pod anti-affinity on kubernetes.io/hostname
+- how many ready brokers remain when the node or the topology.kubernetes.io/zone
+  with the most brokers fails
+
+Losing the quorum on a node drain or zone outage fails the check. A missing
+PodDisruptionBudget, brokers that may share a node and clusters in a single zone
+are warnings; without a budget a suggested manifest is printed. The command
+exits with an error on NO-GO.
+
+Examples:
+  # Check the default StatefulSet
+  kubectl broker check disruption -n production
+
+  # Apply the suggested PodDisruptionBudget
+  kubectl broker check disruption -n production --output json | jq -r .suggestedPdb | kubectl apply -f -`,
+		Args: cobra.NoArgs,
+		RunE: runCheckDisruption,
+	}
+
+	disruptionCmd.Flags().StringVarP(&checkNamespace, "namespace", "n", "", "Namespace (defaults to current kubectl context)")
+	disruptionCmd.Flags().StringVar(&checkStatefulSet, "statefulset", "", "StatefulSet to check (defaults to 'broker')")
+
+	checkCmd.AddCommand(capacityCmd)
+	checkCmd.AddCommand(disruptionCmd)
+	return checkCmd
+}
+
+func runCheckCapacity(cmd *cobra.Command, args []string) error {
+	if checkReplicas < 0 {
+		return fmt.Errorf("invalid --replicas value %d\n\nPlease either:\n- Use a positive replica count: --replicas 5\n- Omit --replicas to check the current replica count", checkReplicas)
+	}
+
+	resolvedNamespace, fromContext, err := resolveNamespace(checkNamespace, false)
+	if err != nil {
+		return err
+	}
+	checkNamespace = resolvedNamespace
+	if fromContext {
+		slog.Info("Using namespace from context", "namespace", checkNamespace)
+	}
+	statefulSet, defaulted := applyDefaultStatefulSet(checkStatefulSet)
+	if defaulted {
+		slog.Info("Using default StatefulSet", "statefulset", statefulSet)
+	}
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "Kubernetes client initialization")
+	}
+
+	report, err := k8sClient.CheckCapacity(cmd.Context(), checkNamespace, statefulSet, checkReplicas)
+	if err != nil {
+		return pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", statefulSet, checkNamespace))
+	}
+	if err := renderCapacityReport(report); err != nil {
+		return err
+	}
+
+	if report.Verdict() == pkg.CapacityNoGo {
+		return fmt.Errorf("cluster lacks capacity for %d replicas of StatefulSet %s\n\nPlease either:\n- Add nodes or free resources on the existing ones\n- Raise the ResourceQuota or create the missing StorageClass\n- Scale to fewer replicas: --replicas <count>", report.TargetReplicas, statefulSet)
+	}
+	return nil
+}
+
+func runCheckDisruption(cmd *cobra.Command, args []string) error {
+	resolvedNamespace, fromContext, err := resolveNamespace(checkNamespace, false)
+	if err != nil {
+		return err
+	}
+	checkNamespace = resolvedNamespace
+	if fromContext {
+		slog.Info("Using namespace from context", "namespace", checkNamespace)
+	}
+	statefulSet, defaulted := applyDefaultStatefulSet(checkStatefulSet)
+	if defaulted {
+		slog.Info("Using default StatefulSet", "statefulset", statefulSet)
+	}
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "Kubernetes client initialization")
+	}
+
+	report, err := k8sClient.CheckDisruption(cmd.Context(), checkNamespace, statefulSet)
+	if err != nil {
+		return pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", statefulSet, checkNamespace))
+	}
+	if err := renderDisruptionReport(report); err != nil {
+		return err
+	}
+
+	if report.Verdict() == pkg.CapacityNoGo {
+		return fmt.Errorf("a node drain or zone outage can take StatefulSet %s below its quorum of %d brokers\n\nPlease either:\n- Require pod anti-affinity on kubernetes.io/hostname and spread the brokers over zones\n- Limit evictions with a PodDisruptionBudget: maxUnavailable 1\n- Run more replicas so a lost node or zone leaves a majority", statefulSet, report.Quorum)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"sigs.k8s.io/yaml"
+
+	"kubectl-broker/pkg"
+)
+
+var capacityNodeColumns = []tableColumn{
+	{Title: "NODE", Width: 32},
+	{Title: "FREE CPU", Width: 9},
+	{Title: "FREE MEMORY", Width: 11},
+	{Title: "FREE PODS", Width: 9},
+	{Title: "FITS", Width: 4},
+	{Title: "NOTE", Width: 0},
+}
+
+var disruptionDomainColumns = []tableColumn{
+	{Title: "DOMAIN", Width: 6},
+	{Title: "NAME", Width: 32},
+	{Title: "REMAINING", Width: 9},
+	{Title: "PODS", Width: 0},
+}
+
+type capacityPayload struct {
+	*pkg.CapacityReport
+	Verdict string `json:"verdict"`
+}
+
+func renderCapacityReport(report *pkg.CapacityReport) error {
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		return writeStructuredCheck(capacityPayload{CapacityReport: report, Verdict: report.Verdict()}, format)
+	}
+
+	fmt.Printf("Capacity for StatefulSet %s in namespace %s: %d -> %d replicas, %d pods to place\n\n",
+		report.StatefulSet, report.Namespace, report.CurrentReplicas, report.TargetReplicas, report.PodsToPlace)
+
+	renderTableHeader(capacityNodeColumns, 2)
+	for _, node := range report.Nodes {
+		fmt.Printf("%-32s  %-9s  %-11s  %-9d  %-4d  %s\n",
+			truncateString(node.Name, 32),
+			node.FreeCPU.String(),
+			formatBytes(node.FreeMemory.Value()),
+			node.FreePods,
+			node.Fits,
+			node.Reason)
+	}
+
+	fmt.Println("\nChecks")
+	renderCheckList(report.Checks)
+
+	useColors := colorOutputEnabled()
+	verdict := report.Verdict()
+	if useColors {
+		if verdict == pkg.CapacityGo {
+			verdict = color.New(color.FgGreen, color.Bold).Sprint(verdict)
+		} else {
+			verdict = color.New(color.FgRed, color.Bold).Sprint(verdict)
+		}
+	}
+	fmt.Printf("\nVerdict: %s\n", verdict)
+	return nil
+}
+
+type disruptionPayload struct {
+	*pkg.DisruptionReport
+	Verdict string `json:"verdict"`
+}
+
+func renderDisruptionReport(report *pkg.DisruptionReport) error {
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		return writeStructuredCheck(disruptionPayload{DisruptionReport: report, Verdict: report.Verdict()}, format)
+	}
+
+	fmt.Printf("Disruption tolerance of StatefulSet %s in namespace %s: %d replicas, %d ready, quorum %d\n\n",
+		report.StatefulSet, report.Namespace, report.Replicas, report.ReadyPods, report.Quorum)
+
+	renderTableHeader(disruptionDomainColumns, 2)
+	row := func(kind string, domain pkg.DisruptionDomain) {
+		fmt.Printf("%-6s  %-32s  %-9d  %s\n", kind, truncateString(domain.Name, 32), domain.Remaining, strings.Join(domain.Pods, ", "))
+	}
+	for _, node := range report.Nodes {
+		row("node", node)
+	}
+	for _, zone := range report.Zones {
+		row("zone", zone)
+	}
+
+	fmt.Println("\nChecks")
+	renderCheckList(report.Checks)
+
+	if report.SuggestedPDB != "" {
+		fmt.Printf("\nSuggested PodDisruptionBudget:\n\n%s", report.SuggestedPDB)
+	}
+
+	verdict := report.Verdict()
+	if colorOutputEnabled() {
+		if verdict == pkg.CapacityGo {
+			verdict = color.New(color.FgGreen, color.Bold).Sprint(verdict)
+		} else {
+			verdict = color.New(color.FgRed, color.Bold).Sprint(verdict)
+		}
+	}
+	fmt.Printf("\nVerdict: %s\n", verdict)
+	return nil
+}
+
+// renderCheckList prints one line per check with its colored status
+func renderCheckList(checks []pkg.CapacityCheck) {
+	useColors := colorOutputEnabled()
+	for _, check := range checks {
+		status := check.Status
+		if useColors {
+			status = capacityStatusColor(check.Status).Sprint(status)
+		}
+		fmt.Printf("  - %s [%s]", check.Name, status)
+		if check.Details != "" {
+			fmt.Printf(" - %s", check.Details)
+		}
+		fmt.Println()
+	}
+}
+
+func capacityStatusColor(status string) *color.Color {
+	switch status {
+	case pkg.CapacityCheckPass:
+		return color.New(color.FgGreen)
+	case pkg.CapacityCheckWarn:
+		return color.New(color.FgYellow)
+	default:
+		return color.New(color.FgRed)
+	}
+}
+
+func writeStructuredCheck(payload any, format string) error {
+	if format == "yaml" {
+		data, err := yaml.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode check report as YAML: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode check report as JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
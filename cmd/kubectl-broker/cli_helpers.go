@@ -1,10 +1,22 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
 	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/backup"
+	"kubectl-broker/pkg/transport"
+	"kubectl-broker/pkg/units"
 )
 
 const namespaceGuidanceBase = `failed to determine default namespace: %w
@@ -13,14 +25,42 @@ Please either:
 - Set a kubectl context with namespace: kubectl config set-context --current --namespace=<namespace>
 - Specify namespace explicitly: --namespace <namespace>`
 
-// resolveNamespace returns the provided namespace or falls back to the current kubectl context.
-// The second return value indicates whether the namespace came from the context.
+// Routes accepted by --via
+const (
+	viaService = "service"
+	viaPod     = "pod"
+)
+
+// validateVia checks a --via value; defaultRoute describes what the command does without the flag
+func validateVia(value, defaultRoute string) error {
+	if value == viaService || value == viaPod {
+		return nil
+	}
+	return fmt.Errorf("invalid --via %q\n\nPlease either:\n- Forward to a ready pod behind the API service: --via service\n- Forward to a broker pod directly: --via pod\n- Omit --via to %s", value, defaultRoute)
+}
+
+// pluginNamespaceEnv carries the namespace kubectl resolved for a plugin in kubectl's plugin
+// environment, used when no --namespace flag was given
+const pluginNamespaceEnv = "KUBECTL_PLUGINS_CURRENT_NAMESPACE"
+
+// resolveNamespace returns the provided namespace or falls back to the namespace kubectl passed
+// to the plugin, then to the kubectl context (--context or the current context).
+// The second return value indicates whether the namespace was not given explicitly.
 func resolveNamespace(value string, includeAllHint bool) (string, bool, error) {
 	if value != "" {
 		return value, false, nil
 	}
+	if namespace := strings.TrimSpace(os.Getenv(pluginNamespaceEnv)); namespace != "" {
+		slog.Debug("Using namespace from kubectl plugin environment", "env", pluginNamespaceEnv, "namespace", namespace)
+		return namespace, true, nil
+	}
+
+	lookup := pkg.GetDefaultNamespace
+	if globalFlags.InCluster {
+		lookup = pkg.InClusterNamespace
+	}
 
-	namespace, err := pkg.GetDefaultNamespace()
+	namespace, err := lookup()
 	if err != nil {
 		return "", false, namespaceResolutionError(err, includeAllHint)
 	}
@@ -28,6 +68,30 @@ func resolveNamespace(value string, includeAllHint bool) (string, bool, error) {
 	return namespace, true, nil
 }
 
+// newK8sClient creates the Kubernetes client, honouring --in-cluster
+func newK8sClient(showDebug bool) (*pkg.K8sClient, error) {
+	return pkg.NewK8sClientWithOptions(pkg.K8sClientOptions{
+		ShowDebug:         showDebug,
+		InCluster:         globalFlags.InCluster,
+		CacheTTL:          globalFlags.CacheTTL,
+		Impersonate:       globalFlags.Impersonate,
+		ImpersonateGroups: globalFlags.ImpersonateGroups,
+	})
+}
+
+// configureKubeAccess validates --context, --as and --as-group and applies the context to every
+// kubeconfig lookup, including the default namespace
+func configureKubeAccess() error {
+	if err := mutuallyExclusive(globalFlags.InCluster, "--in-cluster", globalFlags.KubeContext != "", "--context"); err != nil {
+		return err
+	}
+	if len(globalFlags.ImpersonateGroups) > 0 && globalFlags.Impersonate == "" {
+		return fmt.Errorf("--as-group requires --as\n\nPlease either:\n- Name the user to impersonate: --as <user> --as-group %s\n- Drop --as-group", globalFlags.ImpersonateGroups[0])
+	}
+	pkg.SetKubeContext(globalFlags.KubeContext)
+	return nil
+}
+
 func namespaceResolutionError(err error, includeAllHint bool) error {
 	message := namespaceGuidanceBase
 	if includeAllHint {
@@ -36,13 +100,19 @@ func namespaceResolutionError(err error, includeAllHint bool) error {
 	return fmt.Errorf(message, err)
 }
 
-// applyDefaultStatefulSet ensures we always target a StatefulSet when no explicit value was supplied.
+// applyDefaultStatefulSet ensures we always target a StatefulSet when no explicit value was supplied:
+// the broker profile's workload, "broker" unless $KUBECTL_BROKER_STATEFULSET names another.
 // Returns true if the default was applied.
 func applyDefaultStatefulSet(value string) (string, bool) {
 	if value != "" {
 		return value, false
 	}
-	return "broker", true
+	return pkg.ProfileFor(pkg.ProductBroker).Workload, true
+}
+
+// operatorManagedError adds the ways forward to a refused change of resources the HiveMQ operator manages
+func operatorManagedError(err error) error {
+	return fmt.Errorf("%w\n\nPlease either:\n- Make the change through the HiveMQPlatform resource and let the operator apply it\n- Proceed anyway: --force-operator-managed", err)
 }
 
 // mutuallyExclusive ensures that only one of the provided flags is active at the same time.
@@ -53,19 +123,56 @@ func mutuallyExclusive(flagA bool, nameA string, flagB bool, nameB string) error
 	return nil
 }
 
-// currentOutputFormat returns the normalized global output format (table, json, yaml).
+// currentOutputFormat returns the normalized global output format (table, json, yaml, csv).
 func currentOutputFormat() string {
 	format := strings.ToLower(strings.TrimSpace(globalFlags.Output))
 	switch format {
-	case "", "table":
+	case "", "table", "wide":
 		return "table"
-	case "json", "yaml":
+	case "json", "yaml", "csv":
 		return format
 	default:
 		return "table"
 	}
 }
 
+// quietOutput reports whether -q/--quiet asked for the command's result value only. Commands
+// without a single result value keep their regular output.
+func quietOutput() bool {
+	return globalFlags.Quiet
+}
+
+// resultOutput is the stdout that --quiet result values are printed to, even while the regular
+// output of a command is discarded
+var resultOutput io.Writer = os.Stdout
+
+// discardRegularOutput sends stdout to the null device for the rest of a --quiet command, so
+// progress and messages of the steps it runs stay out of captured results. It returns a
+// function that restores stdout.
+func discardRegularOutput() func() {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		slog.Debug("Cannot discard regular output", "error", err)
+		return func() {}
+	}
+	stdout := os.Stdout
+	resultOutput, os.Stdout = stdout, devNull
+	return func() {
+		os.Stdout = stdout
+		_ = devNull.Close()
+	}
+}
+
+// printResult prints the single result value of a command run with --quiet
+func printResult(value string) {
+	_, _ = fmt.Fprintln(resultOutput, value)
+}
+
+// wideOutput reports whether tables should include the extra columns of --output wide
+func wideOutput() bool {
+	return strings.EqualFold(strings.TrimSpace(globalFlags.Output), "wide")
+}
+
 // colorOutputEnabled indicates whether colored CLI output should be used.
 func colorOutputEnabled() bool {
 	if globalFlags.NoColor {
@@ -73,3 +180,230 @@ func colorOutputEnabled() bool {
 	}
 	return currentOutputFormat() == "table"
 }
+
+// apiTLSOptions returns the TLS settings for management and health API calls from the global flags.
+// The APIs are reached through a port-forward on localhost, so certificates issued for the service
+// name need --api-tls-server-name to pass hostname verification.
+func apiTLSOptions() transport.TLSOptions {
+	serverName := strings.TrimSpace(globalFlags.APITLSServerName)
+	return transport.TLSOptions{
+		Enabled:            globalFlags.APITLS || serverName != "",
+		InsecureSkipVerify: globalFlags.APIInsecureSkipVerify,
+		CACertFile:         strings.TrimSpace(globalFlags.APICACert),
+		ServerName:         serverName,
+	}
+}
+
+// apiRetryPolicy returns the management API retry policy from the global flags.
+func apiRetryPolicy() backup.RetryPolicy {
+	policy := backup.DefaultRetryPolicy
+	policy.MaxRetries = globalFlags.APIRetries
+	if policy.MaxRetries < 0 {
+		policy.MaxRetries = 0
+	}
+	if globalFlags.APIRetryBackoff > 0 {
+		policy.Backoff = globalFlags.APIRetryBackoff
+	}
+	return policy
+}
+
+// apiOAuthClientSecretEnv supplies the OAuth2 client secret without putting it on the command line
+const apiOAuthClientSecretEnv = "KUBECTL_BROKER_API_OAUTH_CLIENT_SECRET"
+
+// apiTokenSource is created once per command so OAuth2 tokens are reused between requests
+var apiTokenSource backup.TokenSource
+
+// validateAPIAuth checks that at most one bearer token source is configured and that the
+// OAuth2 client-credentials flow is complete
+func validateAPIAuth() error {
+	if err := mutuallyExclusive(globalFlags.APIToken != "", "--api-token", globalFlags.APITokenFile != "", "--api-token-file"); err != nil {
+		return err
+	}
+	oauth := globalFlags.APIOAuthTokenURL != ""
+	if err := mutuallyExclusive(oauth, "--api-oauth-token-url", globalFlags.APIToken != "" || globalFlags.APITokenFile != "", "--api-token/--api-token-file"); err != nil {
+		return err
+	}
+	if !oauth {
+		if globalFlags.APIOAuthClientID != "" || globalFlags.APIOAuthClientSecret != "" || len(globalFlags.APIOAuthScopes) > 0 {
+			return fmt.Errorf("OAuth2 client settings given without a token endpoint\n\nPlease either:\n- Add the token endpoint: --api-oauth-token-url https://auth.example.com/oauth/token\n- Use a static token instead: --api-token-file <path>")
+		}
+		return nil
+	}
+	if tokenURL, err := url.Parse(globalFlags.APIOAuthTokenURL); err != nil || (tokenURL.Scheme != "https" && tokenURL.Scheme != "http") || tokenURL.Host == "" {
+		return fmt.Errorf("invalid --api-oauth-token-url %q\n\nPlease either:\n- Use an absolute URL: --api-oauth-token-url https://auth.example.com/oauth/token\n- Use a static token instead: --api-token-file <path>", globalFlags.APIOAuthTokenURL)
+	}
+	if globalFlags.APIOAuthClientID == "" || apiOAuthClientSecret() == "" {
+		return fmt.Errorf("the OAuth2 client-credentials flow needs a client ID and secret\n\nPlease either:\n- Set both: --api-oauth-client-id <id> --api-oauth-client-secret <secret>\n- Pass the secret through the environment: %s=<secret>", apiOAuthClientSecretEnv)
+	}
+	return nil
+}
+
+// apiOAuthClientSecret returns the client secret from the flag or the environment
+func apiOAuthClientSecret() string {
+	if globalFlags.APIOAuthClientSecret != "" {
+		return globalFlags.APIOAuthClientSecret
+	}
+	return os.Getenv(apiOAuthClientSecretEnv)
+}
+
+// apiAuth returns the bearer token source for management API calls from the global flags, or
+// nil to use --username/--password
+func apiAuth() backup.TokenSource {
+	if apiTokenSource != nil {
+		return apiTokenSource
+	}
+	switch {
+	case globalFlags.APIToken != "":
+		apiTokenSource = backup.StaticToken(globalFlags.APIToken)
+	case globalFlags.APITokenFile != "":
+		apiTokenSource = backup.FileToken(globalFlags.APITokenFile)
+	case globalFlags.APIOAuthTokenURL != "":
+		apiTokenSource = backup.NewClientCredentials(globalFlags.APIOAuthTokenURL, globalFlags.APIOAuthClientID, apiOAuthClientSecret(), globalFlags.APIOAuthScopes)
+	}
+	return apiTokenSource
+}
+
+// Port name overrides for custom charts, so they need not be repeated on every command
+const (
+	healthPortNameEnv = "KUBECTL_BROKER_HEALTH_PORT_NAME"
+	apiPortNameEnv    = "KUBECTL_BROKER_API_PORT_NAME"
+)
+
+// configurePortNames applies --health-port-name and --api-port-name, falling back to the
+// environment, to the port lookups of every product profile
+func configurePortNames() {
+	names := pkg.PortNames{Health: globalFlags.HealthPortName, API: globalFlags.APIPortName}
+	if names.Health == "" {
+		names.Health = os.Getenv(healthPortNameEnv)
+	}
+	if names.API == "" {
+		names.API = os.Getenv(apiPortNameEnv)
+	}
+	if names.Health != "" || names.API != "" {
+		slog.Debug("Using port name overrides", "health", names.Health, "api", names.API)
+	}
+	pkg.SetPortNames(names)
+}
+
+// configureProductDefaults applies the KUBECTL_<PRODUCT>_* environment overrides of workload
+// name, health port, endpoint and selector to every product profile. It runs before the commands
+// are built, so flag defaults and help texts show the overridden values.
+func configureProductDefaults() error {
+	for _, product := range []string{pkg.ProductBroker, pkg.ProductPulse, pkg.ProductEdge} {
+		defaults, err := pkg.ProductDefaultsFromEnv(product, os.Getenv)
+		if err == nil {
+			err = pkg.SetProductDefaults(product, defaults)
+		}
+		if err != nil {
+			prefix := pkg.ProductEnvVar(product, "")
+			return fmt.Errorf("invalid %s* environment defaults: %w\n\nPlease either:\n- Fix the value, e.g. %sPORT=9090\n- Unset the %s* variables to use the defaults of the standard Helm install", prefix, err, prefix, prefix)
+		}
+		if defaults != (pkg.ProductDefaults{}) {
+			slog.Debug("Using product default overrides", "product", product, "workload", defaults.Workload, "port", defaults.HealthPort, "endpoint", defaults.Endpoint, "selector", defaults.Selector)
+		}
+	}
+	pulseProfile = pkg.ProfileFor(pkg.ProductPulse)
+	edgeProfile = pkg.ProfileFor(pkg.ProductEdge)
+	return nil
+}
+
+// operationTimeout returns the per-operation timeout, raised to --timeout when that is longer
+// so long-running restores and downloads are only bounded by the global limit.
+func operationTimeout(defaultTimeout time.Duration) time.Duration {
+	if globalFlags.Timeout > defaultTimeout {
+		return globalFlags.Timeout
+	}
+	return defaultTimeout
+}
+
+// parseDurationFlag parses a duration flag such as --older-than 1w2d; an empty value is zero
+func parseDurationFlag(name, value string) (time.Duration, error) {
+	if strings.TrimSpace(value) == "" {
+		return 0, nil
+	}
+	duration, err := units.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("--%s: %w", name, err)
+	}
+	return duration, nil
+}
+
+// parsePositiveDurationFlag parses a duration flag that needs a value above zero, like a window
+func parsePositiveDurationFlag(name, value string) (time.Duration, error) {
+	duration, err := parseDurationFlag(name, value)
+	if err != nil {
+		return 0, err
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("--%s must be longer than zero: use %s", name, units.DurationForms)
+	}
+	return duration, nil
+}
+
+// parseSizeFlag parses a size flag such as --min-size 1Gi; an empty value is zero
+func parseSizeFlag(name, value string) (resource.Quantity, error) {
+	if strings.TrimSpace(value) == "" {
+		return resource.Quantity{}, nil
+	}
+	size, err := units.ParseSize(value)
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("--%s: %w", name, err)
+	}
+	return size, nil
+}
+
+// statefulSetFromPlatform resolves the broker StatefulSet managed by a HiveMQPlatform resource.
+func statefulSetFromPlatform(ctx context.Context, namespace, platform string) (string, error) {
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return "", pkg.EnhanceError(err, "Kubernetes client initialization")
+	}
+
+	resolved, err := k8sClient.GetPlatform(ctx, namespace, platform)
+	if err != nil {
+		return "", err
+	}
+	return resolved.StatefulSet, nil
+}
+
+// statefulSetFromSelector resolves a label selector to the single StatefulSet it matches in namespace
+func statefulSetFromSelector(ctx context.Context, namespace, selector string) (string, error) {
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return "", pkg.EnhanceError(err, "Kubernetes client initialization")
+	}
+
+	matches, err := k8sClient.FindStatefulSetsBySelector(ctx, namespace, selector)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no StatefulSet matches selector %q in namespace %s\n\nPlease either:\n- Check the labels: kubectl get statefulsets -n %s --show-labels\n- Name the StatefulSet explicitly: --statefulset <name>", selector, namespace, namespace)
+	case 1:
+		return matches[0].Name, nil
+	default:
+		names := make([]string, len(matches))
+		for i, sts := range matches {
+			names[i] = sts.Name
+		}
+		return "", fmt.Errorf("selector %q matches %d StatefulSets in namespace %s: %s\n\nPlease either:\n- Narrow the selector, e.g. app.kubernetes.io/instance=<tenant>\n- Name the StatefulSet explicitly: --statefulset <name>", selector, len(matches), namespace, strings.Join(names, ", "))
+	}
+}
+
+// firstReadyPod returns the first ready pod of a StatefulSet, for commands that need one broker
+func firstReadyPod(ctx context.Context, k8sClient *pkg.K8sClient, namespace, statefulSet string) (*v1.Pod, error) {
+	pods, err := k8sClient.GetPodsFromStatefulSet(ctx, namespace, statefulSet)
+	if err != nil {
+		return nil, pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", statefulSet, namespace))
+	}
+
+	for _, pod := range pods {
+		if pkg.ValidatePodStatus(pod) == nil {
+			return pod, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no ready pods found for StatefulSet %s in namespace %s\n\nPlease either:\n- Check pod status: kubectl get pods -n %s\n- Target a pod explicitly: --pod <pod-name>", statefulSet, namespace, namespace)
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/backup"
+	"kubectl-broker/pkg/collect"
+	"kubectl-broker/pkg/health"
+	"kubectl-broker/pkg/volumes"
+)
+
+var (
+	collectNamespace   string
+	collectStatefulSet string
+	collectArchive     string
+	collectLogLines    int64
+	collectUsername    string
+	collectPassword    string
+)
+
+func newCollectCommand() *cobra.Command {
+	var collectCmd = &cobra.Command{
+		Use:   "collect",
+		Short: "Gather a support archive with health, logs, manifests, events, volumes and backups",
+		Long: `Collect gathers the artifacts HiveMQ support asks for into one timestamped
+tar.gz archive:
+
+  health/<pod>.json            health API response of every broker pod
+  logs/<pod>/<container>.log   recent container logs (and the previous instance after a restart)
+  manifests/                   StatefulSet, pod and service YAML
+  events.yaml                  events of the namespace
+  volumes.json                 volume analysis of the namespace
+  backups.json                 backup inventory from the management API
+  manifest.json                contents and the artifacts that could not be collected
+
+Secrets are redacted before anything is written: environment variable values
+with credential-like names, last-applied-configuration annotations, and
+credential-like key=value pairs and JSON members in logs and health responses.
+An artifact that cannot be collected (e.g. the management API requires
+credentials) is listed in manifest.json instead of failing the command.
+
+Unlike other commands, --output names the archive file here.
+
+Examples:
+  # Archive for the default StatefulSet in the current namespace
+  kubectl broker collect
+
+  # Named archive for a support ticket
+  kubectl broker collect -n production --statefulset broker --output hivemq-diag.tar.gz
+
+  # More log history, with credentials for the backup inventory
+  kubectl broker collect --log-lines 10000 --username admin --password secret`,
+		Args: cobra.NoArgs,
+		RunE: runCollect,
+	}
+
+	collectCmd.Flags().StringVarP(&collectNamespace, "namespace", "n", "", "Namespace (defaults to current kubectl context)")
+	collectCmd.Flags().StringVar(&collectStatefulSet, "statefulset", "", "StatefulSet to collect (defaults to 'broker')")
+	collectCmd.Flags().StringVar(&collectArchive, "output", "", "Archive file to write (defaults to hivemq-diag-<namespace>-<timestamp>.tar.gz)")
+	collectCmd.Flags().Int64Var(&collectLogLines, "log-lines", 1000, "Log lines to collect per container (0 collects the full log)")
+	collectCmd.Flags().StringVar(&collectUsername, "username", "", "Username for the management API (backup inventory)")
+	collectCmd.Flags().StringVar(&collectPassword, "password", "", "Password for the management API (backup inventory)")
+
+	return collectCmd
+}
+
+func runCollect(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	if collectLogLines < 0 {
+		return fmt.Errorf("--log-lines must not be negative, got %d", collectLogLines)
+	}
+	resolvedNamespace, fromContext, err := resolveNamespace(collectNamespace, false)
+	if err != nil {
+		return err
+	}
+	collectNamespace = resolvedNamespace
+	if fromContext {
+		slog.Info("Using namespace from context", "namespace", collectNamespace)
+	}
+	statefulSetName, defaulted := applyDefaultStatefulSet(collectStatefulSet)
+	if defaulted {
+		slog.Info("Using default StatefulSet", "statefulset", statefulSetName)
+	}
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "Kubernetes client initialization")
+	}
+	sts, err := k8sClient.GetStatefulSet(ctx, collectNamespace, statefulSetName)
+	if err != nil {
+		return pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", statefulSetName, collectNamespace))
+	}
+
+	collectedAt := time.Now().UTC()
+	root := fmt.Sprintf("hivemq-diag-%s-%s", collectNamespace, collectedAt.Format("20060102-150405"))
+	archivePath := collectArchive
+	if archivePath == "" {
+		archivePath = root + ".tar.gz"
+	}
+	archive, err := collect.Create(archivePath, root, collect.Manifest{Namespace: collectNamespace, StatefulSet: statefulSetName, CollectedAt: collectedAt})
+	if err != nil {
+		return err
+	}
+
+	pods, err := k8sClient.GetPodsFromStatefulSet(ctx, collectNamespace, statefulSetName)
+	if err != nil {
+		archive.Fail("pods", err)
+	}
+
+	// Each step records its own failures so one unreachable API does not lose the rest
+	steps := []struct {
+		name string
+		run  func() error
+	}{
+		{"manifests", func() error { return collectManifests(ctx, k8sClient, archive, sts, pods) }},
+		{"events", func() error { return collectEvents(ctx, k8sClient, archive) }},
+		{"health", func() error { return collectHealth(ctx, k8sClient, archive, pods) }},
+		{"logs", func() error { return collectLogs(ctx, k8sClient, archive, pods) }},
+		{"volumes", func() error { return collectVolumes(ctx, k8sClient, archive) }},
+		{"backups", func() error { return collectBackups(ctx, k8sClient, archive, statefulSetName) }},
+	}
+	for _, step := range steps {
+		if ctx.Err() != nil {
+			break
+		}
+		slog.Info("Collecting", "artifact", step.name)
+		if err := step.run(); err != nil {
+			slog.Warn("Could not collect artifact", "artifact", step.name, "error", err)
+			archive.Fail(step.name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("collection interrupted, archive %s is incomplete: %w", archivePath, err)
+	}
+	return renderCollectSummary(archivePath, archive.Manifest())
+}
+
+func collectManifests(ctx context.Context, k8sClient *pkg.K8sClient, archive *collect.Archive, sts *appsv1.StatefulSet, pods []*v1.Pod) error {
+	if err := archive.AddYAML("manifests/statefulset-"+sts.Name+".yaml", collect.RedactStatefulSet(sts)); err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		if err := archive.AddYAML("manifests/pod-"+pod.Name+".yaml", collect.RedactPod(pod)); err != nil {
+			return err
+		}
+	}
+
+	services, err := k8sClient.GetCoreClient().Services(collectNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+	podLabels := labels.Set(sts.Spec.Template.Labels)
+	for i := range services.Items {
+		service := &services.Items[i]
+		if len(service.Spec.Selector) == 0 || !labels.SelectorFromSet(service.Spec.Selector).Matches(podLabels) {
+			continue
+		}
+		if err := archive.AddYAML("manifests/service-"+service.Name+".yaml", collect.RedactService(service)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func collectEvents(ctx context.Context, k8sClient *pkg.K8sClient, archive *collect.Archive) error {
+	events, err := k8sClient.GetCoreClient().Events(collectNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list events: %w", err)
+	}
+	sort.SliceStable(events.Items, func(i, j int) bool {
+		return events.Items[i].LastTimestamp.Before(&events.Items[j].LastTimestamp)
+	})
+	for i := range events.Items {
+		events.Items[i].ManagedFields = nil
+	}
+	return archive.AddYAML("events.yaml", events.Items)
+}
+
+func collectHealth(ctx context.Context, k8sClient *pkg.K8sClient, archive *collect.Archive, pods []*v1.Pod) error {
+	if len(pods) == 0 {
+		return nil
+	}
+	results, err := k8sClient.CollectConcurrentHealthChecks(ctx, pods, 0, health.HealthCheckOptions{
+		Endpoint:  "health",
+		OutputRaw: true,
+		Timeout:   10 * time.Second,
+		TLS:       apiTLSOptions(),
+	})
+	for _, result := range results {
+		if result.Error != nil {
+			archive.Fail("health/"+result.PodName, result.Error)
+			continue
+		}
+		if err := archive.Add("health/"+result.PodName+".json", collect.RedactText(result.RawJSON)); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func collectLogs(ctx context.Context, k8sClient *pkg.K8sClient, archive *collect.Archive, pods []*v1.Pod) error {
+	for _, pod := range pods {
+		statuses := append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			name := fmt.Sprintf("logs/%s/%s.log", pod.Name, status.Name)
+			logs, err := k8sClient.GetContainerLogs(ctx, pod.Namespace, pod.Name, status.Name, collectLogLines, false)
+			if err != nil {
+				archive.Fail(name, err)
+			} else if err := archive.Add(name, collect.RedactText(logs)); err != nil {
+				return err
+			}
+
+			// The crashed instance usually explains a restart better than the current one
+			if status.RestartCount == 0 {
+				continue
+			}
+			previousName := fmt.Sprintf("logs/%s/%s.previous.log", pod.Name, status.Name)
+			logs, err = k8sClient.GetContainerLogs(ctx, pod.Namespace, pod.Name, status.Name, collectLogLines, true)
+			if err != nil {
+				archive.Fail(previousName, err)
+			} else if err := archive.Add(previousName, collect.RedactText(logs)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func collectVolumes(ctx context.Context, k8sClient *pkg.K8sClient, archive *collect.Archive) error {
+	result, err := volumes.NewAnalyzer(k8sClient).AnalyzeVolumes(ctx, volumes.AnalysisOptions{Namespace: collectNamespace, ShowAll: true})
+	if err != nil {
+		return err
+	}
+	return archive.AddJSON("volumes.json", result)
+}
+
+func collectBackups(ctx context.Context, k8sClient *pkg.K8sClient, archive *collect.Archive, statefulSetName string) error {
+	service, err := k8sClient.GetAPIServiceFromStatefulSet(ctx, collectNamespace, statefulSetName)
+	if err != nil {
+		return err
+	}
+	backups, err := backup.ListBackups(ctx, k8sClient, service, backup.BackupOptions{
+		Username:       collectUsername,
+		Password:       collectPassword,
+		Auth:           apiAuth(),
+		RequestTimeout: operationTimeout(time.Minute),
+		TLS:            apiTLSOptions(),
+		Retry:          apiRetryPolicy(),
+	})
+	if err != nil {
+		return err
+	}
+	return archive.AddJSON("backups.json", backups)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+
+	"kubectl-broker/pkg/collect"
+)
+
+// renderCollectSummary reports where the archive was written and what is missing from it
+func renderCollectSummary(archivePath string, manifest collect.Manifest) error {
+	size := "unknown size"
+	if info, err := os.Stat(archivePath); err == nil {
+		size = formatBytes(info.Size())
+	}
+
+	color.Green("Support archive written to %s (%s)", archivePath, size)
+	fmt.Printf("Namespace:   %s\n", manifest.Namespace)
+	fmt.Printf("StatefulSet: %s\n", manifest.StatefulSet)
+	fmt.Printf("Files:       %d\n", len(manifest.Files))
+
+	if len(manifest.Failures) == 0 {
+		return nil
+	}
+	fmt.Println()
+	color.Yellow("%d artifacts could not be collected (listed in %s):", len(manifest.Failures), collect.ManifestFile)
+	for _, failure := range manifest.Failures {
+		fmt.Printf("  %s: %s\n", failure.Artifact, failure.Error)
+	}
+	return nil
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/backup"
+	"kubectl-broker/pkg/sidecar"
+)
+
+// completionTimeout bounds cluster lookups so a slow API server never hangs the shell
+const completionTimeout = 10 * time.Second
+
+func newCompletionCommand(ctx ProductContext) *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion [bash|zsh|fish]",
+		Short: "Generate shell completion scripts",
+		Long: fmt.Sprintf(`Generate shell completion scripts for %[1]s. Completions include live
+values from the cluster for --namespace, --statefulset, --pod, --platform, --id and
+--version.
+
+Examples:
+  # Bash (current shell)
+  source <(%[1]s completion bash)
+
+  # Zsh (install permanently)
+  %[1]s completion zsh > "${fpath[1]}/_%[1]s"
+
+  # Fish
+  %[1]s completion fish > ~/.config/fish/completions/%[1]s.fish
+
+When invoked through kubectl (kubectl 1.26+), place an executable named
+kubectl_complete-%[2]s on your PATH that runs: %[1]s __complete "$@"`, ctx.Name, strings.TrimPrefix(ctx.Name, "kubectl-")),
+		Args:                  cobra.ExactArgs(1),
+		ValidArgs:             []string{"bash", "zsh", "fish"},
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(os.Stdout, true)
+			case "zsh":
+				return root.GenZshCompletion(os.Stdout)
+			case "fish":
+				return root.GenFishCompletion(os.Stdout, true)
+			default:
+				return fmt.Errorf("unsupported shell %q\n\nPlease either:\n- Use bash: completion bash\n- Use zsh: completion zsh\n- Use fish: completion fish", args[0])
+			}
+		},
+	}
+}
+
+// registerDynamicCompletions wires cluster-backed completion functions to well-known flags
+// on every command that declares them.
+func registerDynamicCompletions(cmd *cobra.Command) {
+	completions := map[string]cobra.CompletionFunc{
+		"namespace":   completeNamespaces,
+		"statefulset": completeStatefulSets,
+		"pod":         completePods,
+		"platform":    completePlatforms,
+	}
+
+	// Backup identifiers only make sense below the backup command
+	if cmd.Name() == "backup" || (cmd.HasParent() && cmd.Parent().Name() == "backup") {
+		completions["id"] = completeBackupIDs
+		completions["version"] = completeRemoteVersions
+	}
+
+	for name, fn := range completions {
+		if cmd.LocalFlags().Lookup(name) != nil {
+			_ = cmd.RegisterFlagCompletionFunc(name, fn)
+		}
+	}
+
+	for _, child := range cmd.Commands() {
+		registerDynamicCompletions(child)
+	}
+}
+
+func completeNamespaces(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	// Broker namespaces from a recent --discover avoid listing every namespace of large clusters
+	if entry, ok := cachedDiscovery(); ok && len(entry.Namespaces) > 0 {
+		return filterCompletions(entry.NamespaceNames(), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	list, err := k8sClient.GetCoreClient().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	names := make([]string, 0, len(list.Items))
+	for _, ns := range list.Items {
+		names = append(names, ns.Name)
+	}
+	return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeStatefulSets(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	k8sClient, namespace, err := completionClient(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	if entry, ok := cachedDiscovery(); ok {
+		if cached, ok := entry.Namespace(namespace); ok && len(cached.StatefulSets) > 0 {
+			return filterCompletions(cached.StatefulSets, toComplete), cobra.ShellCompDirectiveNoFileComp
+		}
+	}
+
+	list, err := k8sClient.GetAppsClient().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	names := make([]string, 0, len(list.Items))
+	for _, sts := range list.Items {
+		names = append(names, sts.Name)
+	}
+	return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completePods(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	k8sClient, namespace, err := completionClient(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	// Narrow to the StatefulSet when one was given on the command line
+	if statefulSet := completionFlagValue(cmd, "statefulset"); statefulSet != "" {
+		pods, err := k8sClient.GetPodsFromStatefulSet(ctx, namespace, statefulSet)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		names := make([]string, 0, len(pods))
+		for _, pod := range pods {
+			names = append(names, pod.Name)
+		}
+		return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	list, err := k8sClient.GetCoreClient().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	names := make([]string, 0, len(list.Items))
+	for _, pod := range list.Items {
+		names = append(names, pod.Name)
+	}
+	return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completePlatforms(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	k8sClient, namespace, err := completionClient(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	client, err := k8sClient.GetDynamicClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	list, err := client.Resource(pkg.PlatformResource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	names := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		names = append(names, item.GetName())
+	}
+	return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeBackupIDs(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	k8sClient, namespace, err := completionClient(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	statefulSet, _ := applyDefaultStatefulSet(completionFlagValue(cmd, "statefulset"))
+	service, err := k8sClient.GetAPIServiceFromStatefulSet(ctx, namespace, statefulSet)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	backups, err := backup.ListBackups(ctx, k8sClient, service, backup.BackupOptions{
+		Username: completionFlagValue(cmd, "username"),
+		Password: completionFlagValue(cmd, "password"),
+		Auth:     apiAuth(),
+		TLS:      apiTLSOptions(),
+	})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	completions := make([]cobra.Completion, 0, len(backups))
+	for _, info := range backups {
+		if strings.HasPrefix(info.ID, toComplete) {
+			completions = append(completions, cobra.CompletionWithDesc(info.ID, fmt.Sprintf("%s %s", info.Status, info.CreatedAt.Format(time.RFC3339))))
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeRemoteVersions(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	k8sClient, namespace, err := completionClient(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	statefulSet, _ := applyDefaultStatefulSet(completionFlagValue(cmd, "statefulset"))
+	opts := sidecar.ConnectOptions{
+		Namespace:   namespace,
+		StatefulSet: statefulSet,
+		Pod:         completionFlagValue(cmd, "pod"),
+		Service:     completionFlagValue(cmd, "sidecar-service"),
+		RemotePort:  int32(backupSidecarPort),
+		TLS:         backupSidecarTLS,
+		Timeout:     completionTimeout,
+	}
+	if opts.Service != "" {
+		opts.Pod = ""
+	}
+
+	var completions []cobra.Completion
+	err = sidecar.NewConnector(k8sClient).WithConnection(ctx, opts, func(client *sidecar.Client) error {
+		backups, err := client.ListRemoteBackups(ctx, 0)
+		if err != nil {
+			return err
+		}
+		for _, info := range backups {
+			if strings.HasPrefix(info.Key, toComplete) {
+				completions = append(completions, cobra.CompletionWithDesc(info.Key, info.LastModified.Format(time.RFC3339)))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionClient builds a Kubernetes client and resolves the namespace from the partially
+// typed command line, falling back to the kubectl context.
+func completionClient(cmd *cobra.Command) (*pkg.K8sClient, string, error) {
+	namespace, _, err := resolveNamespace(completionFlagValue(cmd, "namespace"), false)
+	if err != nil {
+		return nil, "", err
+	}
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return nil, "", err
+	}
+	return k8sClient, namespace, nil
+}
+
+// completionFlagValue reads a flag already typed on the command line
+func completionFlagValue(cmd *cobra.Command, name string) string {
+	if flag := cmd.Flag(name); flag != nil {
+		return strings.TrimSpace(flag.Value.String())
+	}
+	return ""
+}
+
+func filterCompletions(values []string, toComplete string) []cobra.Completion {
+	completions := make([]cobra.Completion, 0, len(values))
+	for _, value := range values {
+		if strings.HasPrefix(value, toComplete) {
+			completions = append(completions, value)
+		}
+	}
+	return completions
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+)
+
+var (
+	configPodName     string
+	configStatefulSet string
+	configNamespace   string
+	configHiveMQHome  string
+	configDiffPod     string
+)
+
+func newConfigCommand() *cobra.Command {
+	var configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the effective HiveMQ configuration of broker pods",
+	}
+
+	var showCmd = &cobra.Command{
+		Use:   "show",
+		Short: "Show a broker's config.xml and configuration environment",
+		Long: `Show prints the effective HiveMQ configuration of a broker pod: config.xml read
+from the container, re-indented for readability, followed by the environment
+variables that influence it (HIVEMQ_*, JAVA_OPTS and every variable referenced
+by a placeholder in config.xml). Values of credential-like variables are masked.
+
+With --diff the configuration of a second pod is compared line by line, which
+reveals drift between StatefulSet pods after manual edits. The command exits
+with an error when drift is found.
+
+Examples:
+  # Show the configuration of the first ready broker pod
+  kubectl broker config show
+
+  # Show the configuration of a specific pod
+  kubectl broker config show --pod broker-0
+
+  # Compare two pods of the StatefulSet
+  kubectl broker config show --pod broker-0 --diff broker-1`,
+		Args: cobra.NoArgs,
+		RunE: runConfigShow,
+	}
+
+	showCmd.Flags().StringVar(&configPodName, "pod", "", "Pod to inspect (defaults to the first ready pod of the StatefulSet)")
+	showCmd.Flags().StringVar(&configStatefulSet, "statefulset", "", "StatefulSet to pick a pod from (defaults to 'broker')")
+	showCmd.Flags().StringVarP(&configNamespace, "namespace", "n", "", "Namespace (defaults to current kubectl context)")
+	showCmd.Flags().StringVar(&configHiveMQHome, "hivemq-home", "/opt/hivemq", "HiveMQ installation directory inside the container")
+	showCmd.Flags().StringVar(&configDiffPod, "diff", "", "Second pod to compare the configuration with")
+
+	configCmd.AddCommand(showCmd)
+	return configCmd
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if err := mutuallyExclusive(configPodName != "", "--pod", configStatefulSet != "", "--statefulset"); err != nil {
+		return err
+	}
+
+	resolvedNamespace, _, err := resolveNamespace(configNamespace, false)
+	if err != nil {
+		return err
+	}
+	configNamespace = resolvedNamespace
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "Kubernetes client initialization")
+	}
+
+	pod, err := resolveConfigPod(ctx, k8sClient, configPodName)
+	if err != nil {
+		return err
+	}
+	if configDiffPod == pod.Name {
+		return fmt.Errorf("--diff must name a different pod than %s", pod.Name)
+	}
+
+	config, err := k8sClient.GetBrokerConfig(ctx, pod, configHiveMQHome)
+	if err != nil {
+		return err
+	}
+
+	if configDiffPod == "" {
+		return renderBrokerConfig(config)
+	}
+
+	otherPod, err := resolveConfigPod(ctx, k8sClient, configDiffPod)
+	if err != nil {
+		return err
+	}
+	other, err := k8sClient.GetBrokerConfig(ctx, otherPod, configHiveMQHome)
+	if err != nil {
+		return err
+	}
+
+	diff := pkg.DiffLines(config.Lines(), other.Lines())
+	if err := renderBrokerConfigDiff(config, other, diff); err != nil {
+		return err
+	}
+	if pkg.HasChanges(diff) {
+		return fmt.Errorf("configuration drift detected between %s and %s", config.Pod, other.Pod)
+	}
+	return nil
+}
+
+// resolveConfigPod returns the named pod, or the first ready pod of the StatefulSet
+func resolveConfigPod(ctx context.Context, k8sClient *pkg.K8sClient, name string) (*v1.Pod, error) {
+	if name == "" {
+		statefulSet, _ := applyDefaultStatefulSet(configStatefulSet)
+		return firstReadyPod(ctx, k8sClient, configNamespace, statefulSet)
+	}
+
+	pod, err := k8sClient.GetPod(ctx, configNamespace, name)
+	if err != nil {
+		return nil, pkg.EnhanceError(err, fmt.Sprintf("pod %s in namespace %s", name, configNamespace))
+	}
+	return pod, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/fatih/color"
+	"sigs.k8s.io/yaml"
+
+	"kubectl-broker/pkg"
+)
+
+type brokerConfigDiffPayload struct {
+	From    string         `json:"from"`
+	To      string         `json:"to"`
+	Drift   bool           `json:"drift"`
+	Changes []pkg.DiffLine `json:"changes"`
+}
+
+func renderBrokerConfig(config *pkg.BrokerConfig) error {
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		return writeStructuredConfig(config, format)
+	}
+
+	fmt.Printf("# Pod %s, container %s\n", config.Pod, config.Container)
+	fmt.Printf("# %s\n\n", config.ConfigPath)
+	fmt.Print(config.ConfigXML)
+
+	if len(config.Env) > 0 {
+		keys := make([]string, 0, len(config.Env))
+		for key := range config.Env {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		fmt.Printf("\n# Environment\n")
+		for _, key := range keys {
+			fmt.Printf("%s=%s\n", key, config.Env[key])
+		}
+	}
+	return nil
+}
+
+// renderBrokerConfigDiff prints a unified-style diff; only changed lines are shown in
+// structured output so drift is easy to consume from scripts.
+func renderBrokerConfigDiff(from, to *pkg.BrokerConfig, diff []pkg.DiffLine) error {
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		payload := brokerConfigDiffPayload{From: from.Pod, To: to.Pod, Drift: pkg.HasChanges(diff), Changes: []pkg.DiffLine{}}
+		for _, line := range diff {
+			if line.Op != pkg.DiffEqual {
+				payload.Changes = append(payload.Changes, line)
+			}
+		}
+		return writeStructuredConfig(payload, format)
+	}
+
+	fmt.Printf("--- %s\n+++ %s\n", from.Pod, to.Pod)
+	if !pkg.HasChanges(diff) {
+		fmt.Println("\nNo configuration drift detected.")
+		return nil
+	}
+
+	useColors := colorOutputEnabled()
+	removed := color.New(color.FgRed)
+	added := color.New(color.FgGreen)
+	for _, line := range diff {
+		text := fmt.Sprintf("%s %s", line.Op, line.Text)
+		switch {
+		case line.Op == pkg.DiffRemoved && useColors:
+			removed.Println(text)
+		case line.Op == pkg.DiffAdded && useColors:
+			added.Println(text)
+		default:
+			fmt.Println(text)
+		}
+	}
+	return nil
+}
+
+func writeStructuredConfig(payload any, format string) error {
+	if format == "yaml" {
+		data, err := yaml.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode configuration as YAML: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode configuration as JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
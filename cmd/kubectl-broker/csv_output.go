@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// csvOutputAnnotation marks the commands that can write their results with --output csv;
+// other commands reject the format
+const csvOutputAnnotation = "kubectl-broker.hivemq.com/csv-output"
+
+// validateCSVOutput rejects --output csv for commands without CSV results and --no-headers
+// without --output csv
+func validateCSVOutput(cmd *cobra.Command) error {
+	csvSelected := currentOutputFormat() == "csv"
+	if globalFlags.NoHeaders && !csvSelected {
+		return fmt.Errorf("--no-headers requires --output csv\n\nPlease either:\n- Print CSV without the header row: --output csv --no-headers\n- Keep the headers: drop --no-headers")
+	}
+	if csvSelected && cmd.Annotations[csvOutputAnnotation] != "true" {
+		return fmt.Errorf("%s does not support --output csv\n\nPlease either:\n- Print structured output: --output json\n- Print CSV from a list command such as: kubectl broker backup list --output csv", cmd.CommandPath())
+	}
+	return nil
+}
+
+// csvOutput is where --output csv rows are written
+var csvOutput io.Writer = os.Stdout
+
+// csvWriter writes the rows of --output csv to csvOutput. Cells are quoted as RFC 4180 requires,
+// so values containing commas, quotes or newlines stay in their column.
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+// newCSVWriter starts CSV output with the header row, unless --no-headers is set
+func newCSVWriter(header ...string) *csvWriter {
+	w := &csvWriter{writer: csv.NewWriter(csvOutput)}
+	if !globalFlags.NoHeaders {
+		_ = w.writer.Write(header)
+	}
+	return w
+}
+
+// row writes one record; cells are formatted with fmt.Sprint
+func (w *csvWriter) row(cells ...any) {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		record[i] = fmt.Sprint(cell)
+	}
+	_ = w.writer.Write(record)
+}
+
+// flush writes buffered rows and reports the first write error
+func (w *csvWriter) flush() error {
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV output: %w", err)
+	}
+	return nil
+}
+
+// csvTime formats a timestamp for spreadsheets; the zero time is an empty cell
+func csvTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// csvList joins the values of a multi-valued cell
+func csvList(values []string) string {
+	return strings.Join(values, ";")
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// withCSVFlags sets the global output flags for a test and captures the CSV output
+func withCSVFlags(t *testing.T, output string, noHeaders bool) *bytes.Buffer {
+	t.Helper()
+	previousOutput, previousNoHeaders, previousWriter := globalFlags.Output, globalFlags.NoHeaders, csvOutput
+	t.Cleanup(func() {
+		globalFlags.Output, globalFlags.NoHeaders, csvOutput = previousOutput, previousNoHeaders, previousWriter
+	})
+
+	var buf bytes.Buffer
+	globalFlags.Output, globalFlags.NoHeaders, csvOutput = output, noHeaders, &buf
+	return &buf
+}
+
+func TestValidateCSVOutput(t *testing.T) {
+	listCmd := &cobra.Command{Use: "list", Annotations: map[string]string{csvOutputAnnotation: "true"}}
+	statusCmd := &cobra.Command{Use: "status"}
+
+	cases := []struct {
+		name      string
+		cmd       *cobra.Command
+		output    string
+		noHeaders bool
+		wantErr   string
+	}{
+		{"csv on an annotated command", listCmd, "csv", false, ""},
+		{"csv without headers", listCmd, "CSV", true, ""},
+		{"csv on a command without CSV results", statusCmd, "csv", false, "status does not support --output csv"},
+		{"table output", statusCmd, "table", false, ""},
+		{"no-headers without csv", listCmd, "json", true, "--no-headers requires --output csv"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withCSVFlags(t, tc.output, tc.noHeaders)
+			err := validateCSVOutput(tc.cmd)
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Fatalf("error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCSVWriterQuotesCells(t *testing.T) {
+	buf := withCSVFlags(t, "csv", false)
+
+	w := newCSVWriter("NAME", "NOTE", "SIZE")
+	w.row("backup,eu", "say \"hi\"\nand leave", 42)
+	if err := w.flush(); err != nil {
+		t.Fatalf("flush returned error: %v", err)
+	}
+
+	want := "NAME,NOTE,SIZE\n\"backup,eu\",\"say \"\"hi\"\"\nand leave\",42\n"
+	if buf.String() != want {
+		t.Fatalf("output = %q, want %q", buf.String(), want)
+	}
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(records) != 2 || !slices.Equal(records[1], []string{"backup,eu", "say \"hi\"\nand leave", "42"}) {
+		t.Fatalf("records = %q, want the header and the row with every cell in its column", records)
+	}
+}
+
+func TestCSVWriterNoHeaders(t *testing.T) {
+	buf := withCSVFlags(t, "csv", true)
+
+	w := newCSVWriter("NAME", "SIZE")
+	w.row("backup-1", 10)
+	if err := w.flush(); err != nil {
+		t.Fatalf("flush returned error: %v", err)
+	}
+	if buf.String() != "backup-1,10\n" {
+		t.Fatalf("output = %q, want only the row", buf.String())
+	}
+}
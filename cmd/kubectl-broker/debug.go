@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/backup"
+)
+
+var (
+	dumpPodName     string
+	dumpStatefulSet string
+	dumpNamespace   string
+	dumpContainer   string
+	dumpType        string
+	dumpOutputDir   string
+	dumpRemoteDir   string
+	dumpKeepRemote  bool
+)
+
+// dumpProgressInterval throttles progress updates while a dump is copied
+const dumpProgressInterval = 200 * time.Millisecond
+
+// dumpResult describes a dump saved on the local machine
+type dumpResult struct {
+	Namespace  string        `json:"namespace" yaml:"namespace"`
+	Pod        string        `json:"pod" yaml:"pod"`
+	Type       pkg.DumpType  `json:"type" yaml:"type"`
+	Path       string        `json:"path" yaml:"path"`
+	SizeBytes  int64         `json:"sizeBytes" yaml:"sizeBytes"`
+	RemotePath string        `json:"remotePath" yaml:"remotePath"`
+	KeptRemote bool          `json:"keptRemote" yaml:"keptRemote"`
+	Duration   time.Duration `json:"duration" yaml:"duration"`
+}
+
+func newDebugCommand() *cobra.Command {
+	var debugCmd = &cobra.Command{
+		Use:   "debug",
+		Short: "Collect JVM diagnostics from broker pods",
+		Long: `Debug collects diagnostics of the broker JVM for incident analysis.
+
+Available commands:
+  dump   Take a heap or thread dump and download it`,
+	}
+
+	debugCmd.AddCommand(newDebugDumpCommand())
+	return debugCmd
+}
+
+func newDebugDumpCommand() *cobra.Command {
+	var dumpCmd = &cobra.Command{
+		Use:   "dump",
+		Short: "Take a heap or thread dump of a broker and download it",
+		Long: `Dump triggers a heap or thread dump of the broker JVM with jcmd inside the
+broker container, downloads the file with progress and removes it from the
+container afterwards, also when the download fails or is interrupted.
+
+Without --pod the first ready pod of the StatefulSet is used. Heap dumps are
+as large as the used heap, so they are written to the data volume by default
+(--remote-dir) rather than to the container's ephemeral storage. Taking a heap
+dump pauses the broker JVM for its duration. jcmd must be available in the
+broker image.
+
+Examples:
+  # Download a thread dump of broker-0
+  kubectl broker debug dump --pod broker-0 --type thread
+
+  # Download a heap dump into ./incident-42
+  kubectl broker debug dump --pod broker-0 --type heap --output-dir ./incident-42
+
+  # Keep the heap dump in the pod, e.g. for a second download
+  kubectl broker debug dump --pod broker-1 --type heap --keep-remote`,
+		Args: cobra.NoArgs,
+		RunE: runDebugDump,
+	}
+
+	dumpCmd.Flags().StringVar(&dumpPodName, "pod", "", "Pod to dump (defaults to the first ready pod of the StatefulSet)")
+	dumpCmd.Flags().StringVar(&dumpStatefulSet, "statefulset", "", "StatefulSet to pick a pod from (defaults to 'broker')")
+	dumpCmd.Flags().StringVarP(&dumpNamespace, "namespace", "n", "", "Namespace (defaults to current kubectl context)")
+	dumpCmd.Flags().StringVarP(&dumpContainer, "container", "c", "", "Container name (defaults to the HiveMQ container)")
+	dumpCmd.Flags().StringVar(&dumpType, "type", string(pkg.DumpThread), "Dump to take: heap or thread")
+	dumpCmd.Flags().StringVar(&dumpOutputDir, "output-dir", ".", "Local directory to save the dump in")
+	dumpCmd.Flags().StringVar(&dumpRemoteDir, "remote-dir", "/opt/hivemq/data", "Directory inside the container the dump is written to")
+	dumpCmd.Flags().BoolVar(&dumpKeepRemote, "keep-remote", false, "Keep the dump file in the container after the download")
+
+	_ = dumpCmd.RegisterFlagCompletionFunc("type", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return []string{string(pkg.DumpHeap), string(pkg.DumpThread)}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return dumpCmd
+}
+
+func runDebugDump(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	if err := mutuallyExclusive(dumpPodName != "", "--pod", dumpStatefulSet != "", "--statefulset"); err != nil {
+		return err
+	}
+	kind, err := pkg.ParseDumpType(dumpType)
+	if err != nil {
+		return fmt.Errorf("%w\n\nPlease either:\n- Take a heap dump: --type heap\n- Take a thread dump: --type thread", err)
+	}
+	if !path.IsAbs(dumpRemoteDir) {
+		return fmt.Errorf("--remote-dir must be an absolute path inside the container, got %q", dumpRemoteDir)
+	}
+
+	namespace, _, err := resolveNamespace(dumpNamespace, false)
+	if err != nil {
+		return err
+	}
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "Kubernetes client initialization")
+	}
+
+	var pod *v1.Pod
+	if dumpPodName != "" {
+		if pod, err = k8sClient.GetPod(ctx, namespace, dumpPodName); err != nil {
+			return pkg.EnhanceError(err, fmt.Sprintf("pod %s in namespace %s", dumpPodName, namespace))
+		}
+	} else {
+		statefulSet, _ := applyDefaultStatefulSet(dumpStatefulSet)
+		if pod, err = firstReadyPod(ctx, k8sClient, namespace, statefulSet); err != nil {
+			return err
+		}
+	}
+	container := dumpContainer
+	if container == "" {
+		container = pkg.BrokerContainerName(pod)
+	}
+
+	fileName := pkg.DumpFileName(pod.Name, kind, time.Now())
+	result := dumpResult{
+		Namespace:  namespace,
+		Pod:        pod.Name,
+		Type:       kind,
+		Path:       filepath.Join(dumpOutputDir, fileName),
+		RemotePath: path.Join(dumpRemoteDir, fileName),
+		KeptRemote: dumpKeepRemote,
+	}
+
+	if globalFlags.DryRun {
+		steps := []string{
+			fmt.Sprintf("Write a %s dump of the broker JVM in pod %s (container %s) to %s", kind, pod.Name, container, result.RemotePath),
+			fmt.Sprintf("Download it to %s", result.Path),
+		}
+		if !dumpKeepRemote {
+			steps = append(steps, fmt.Sprintf("Remove %s from the pod", result.RemotePath))
+		}
+		return renderDryRunPlan("debug dump", steps...)
+	}
+
+	if err := os.MkdirAll(dumpOutputDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	file, err := os.Create(result.Path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", result.Path, err)
+	}
+
+	slog.Info("Taking JVM dump", "type", kind, "pod", pod.Name, "container", container, "remotePath", result.RemotePath)
+	if kind == pkg.DumpHeap {
+		fmt.Fprintf(os.Stderr, "Writing heap dump in pod %s, the broker pauses until it is complete...\n", pod.Name)
+	}
+
+	start := time.Now()
+	bar := backup.NewProgressBar(os.Stderr, "Downloading "+fileName)
+	var lastUpdate time.Time
+	result.SizeBytes, err = k8sClient.CollectJVMDump(ctx, pkg.JVMDumpOptions{
+		Namespace:  namespace,
+		Pod:        pod.Name,
+		Container:  container,
+		Type:       kind,
+		RemotePath: result.RemotePath,
+		KeepRemote: dumpKeepRemote,
+		Progress: func(written, total int64) {
+			if written < total && time.Since(lastUpdate) < dumpProgressInterval {
+				return
+			}
+			lastUpdate = time.Now()
+			bar.Update(int(written*100/max(total, 1)), written)
+		},
+	}, file)
+	bar.Finish()
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = closeErr
+	}
+	if err != nil {
+		// A truncated dump cannot be analyzed, so do not leave it behind
+		_ = os.Remove(result.Path)
+		if strings.Contains(err.Error(), "jcmd") {
+			return fmt.Errorf("%w\n\nPlease either:\n- Use a broker image that ships a JDK with jcmd\n- Check which JVMs jcmd sees: kubectl broker exec --pod %s -- jcmd -l", err, pod.Name)
+		}
+		return err
+	}
+	result.Duration = time.Since(start)
+
+	return renderDumpResult(result)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"sigs.k8s.io/yaml"
+)
+
+func renderDumpResult(result dumpResult) error {
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		return writeStructuredDumpResult(result, format)
+	}
+
+	color.Green("Saved %s dump of pod %s to %s", result.Type, result.Pod, result.Path)
+	fmt.Printf("Size: %s | Took: %s\n", formatBytes(result.SizeBytes), result.Duration.Round(time.Second))
+	if result.KeptRemote {
+		fmt.Printf("The dump is still in the pod at %s\n", result.RemotePath)
+	}
+	return nil
+}
+
+func writeStructuredDumpResult(result dumpResult, format string) error {
+	if format == "yaml" {
+		data, err := yaml.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to encode dump result as YAML: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode dump result as JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/health"
+)
+
+var (
+	discoverCloudOnly   bool
+	discoverCheckHealth bool
+)
+
+func newDiscoverCommand() *cobra.Command {
+	var discoverCmd = &cobra.Command{
+		Use:   "discover",
+		Short: "List HiveMQ installations across all namespaces",
+		Long: `Discover scans every accessible namespace for HiveMQ broker StatefulSets,
+including those managed by the HiveMQ Platform Operator, and prints namespace,
+broker version, replicas and health at a glance. UUID-style namespaces used by
+HiveMQ Cloud are marked as such.
+
+Health is derived from StatefulSet readiness unless --check-health is given, in
+which case every ready installation is probed through its health API.
+
+Examples:
+  # List all installations
+  kubectl broker discover
+
+  # Only HiveMQ Cloud namespaces, with live health checks
+  kubectl broker discover --cloud-only --check-health
+
+  # Machine-readable inventory
+  kubectl broker discover --output json`,
+		RunE: runDiscover,
+	}
+
+	discoverCmd.Flags().BoolVar(&discoverCloudOnly, "cloud-only", false, "Only list installations in HiveMQ Cloud (UUID) namespaces")
+	discoverCmd.Flags().BoolVar(&discoverCheckHealth, "check-health", false, "Probe the health API of each installation instead of using pod readiness")
+
+	return discoverCmd
+}
+
+func runDiscover(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "Kubernetes client initialization")
+	}
+
+	installations, err := k8sClient.DiscoverInstallations(ctx)
+	if err != nil {
+		return err
+	}
+
+	if discoverCloudOnly {
+		filtered := installations[:0]
+		for _, installation := range installations {
+			if installation.Cloud {
+				filtered = append(filtered, installation)
+			}
+		}
+		installations = filtered
+	}
+
+	if discoverCheckHealth {
+		for i := range installations {
+			installations[i].Health = probeInstallationHealth(ctx, k8sClient, installations[i])
+		}
+	}
+
+	return displayInstallations(installations)
+}
+
+// probeInstallationHealth runs live health checks against all pods of an installation and
+// condenses them into a single installation health value
+func probeInstallationHealth(ctx context.Context, k8sClient *pkg.K8sClient, installation pkg.Installation) string {
+	if installation.Replicas == 0 {
+		return pkg.InstallationScaled0
+	}
+
+	pods, err := k8sClient.GetPodsFromStatefulSet(ctx, installation.Namespace, installation.StatefulSet)
+	if err != nil || len(pods) == 0 {
+		return pkg.InstallationDown
+	}
+
+	results, err := k8sClient.CollectConcurrentHealthChecks(ctx, pods, 0, health.HealthCheckOptions{
+		Endpoint: "health",
+		Timeout:  10 * time.Second,
+		TLS:      apiTLSOptions(),
+	})
+	if err != nil {
+		return pkg.InstallationDown
+	}
+
+	healthy := 0
+	for _, result := range results {
+		if result.Status == "HEALTHY" {
+			healthy++
+		}
+	}
+
+	switch {
+	case healthy == len(results):
+		return pkg.InstallationHealthy
+	case healthy == 0:
+		return pkg.InstallationDown
+	default:
+		return pkg.InstallationDegraded
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fatih/color"
+	"sigs.k8s.io/yaml"
+
+	"kubectl-broker/pkg"
+)
+
+var installationColumns = []tableColumn{
+	{Title: "NAMESPACE", Width: 36},
+	{Title: "STATEFULSET", Width: 20},
+	{Title: "VERSION", Width: 12},
+	{Title: "READY", Width: 7},
+	{Title: "HEALTH", Width: 11},
+	{Title: "CLOUD", Width: 5},
+	{Title: "MANAGED BY", Width: 20},
+}
+
+func displayInstallations(installations []pkg.Installation) error {
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		return writeStructuredInstallations(installations, format)
+	default:
+		displayInstallationsTable(installations)
+		return nil
+	}
+}
+
+func displayInstallationsTable(installations []pkg.Installation) {
+	if len(installations) == 0 {
+		fmt.Println("No HiveMQ installations found. You may need to:")
+		fmt.Println("1. Check if you're connected to the right cluster")
+		fmt.Println("2. Verify your kubeconfig has access to the namespaces containing brokers")
+		return
+	}
+
+	renderTableHeader(installationColumns, 2)
+
+	useColors := colorOutputEnabled()
+	for _, installation := range installations {
+		version := installation.Version
+		if version == "" {
+			version = "-"
+		}
+		cloud := "no"
+		if installation.Cloud {
+			cloud = "yes"
+		}
+		managedBy := installation.ManagedBy
+		if managedBy == "" {
+			managedBy = "-"
+		}
+
+		fmt.Printf("%-36s  %-20s  %-12s  %-7s  %s  %-5s  %s\n",
+			truncateString(installation.Namespace, 36),
+			truncateString(installation.StatefulSet, 20),
+			truncateString(version, 12),
+			fmt.Sprintf("%d/%d", installation.ReadyReplicas, installation.Replicas),
+			installationHealthColor(installation.Health, useColors).Sprintf("%-11s", installation.Health),
+			cloud,
+			managedBy)
+	}
+
+	fmt.Printf("\nFound %d installations\n", len(installations))
+}
+
+func installationHealthColor(status string, useColors bool) *color.Color {
+	c := color.New()
+	if !useColors {
+		return c
+	}
+	switch status {
+	case pkg.InstallationHealthy:
+		c.Add(color.FgGreen)
+	case pkg.InstallationDegraded:
+		c.Add(color.FgYellow)
+	case pkg.InstallationDown:
+		c.Add(color.FgRed)
+	}
+	return c
+}
+
+func writeStructuredInstallations(installations []pkg.Installation, format string) error {
+	if installations == nil {
+		installations = []pkg.Installation{}
+	}
+	payload := map[string]interface{}{
+		"installations": installations,
+	}
+
+	if format == "yaml" {
+		data, err := yaml.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode installations as YAML: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode installations as JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/discoverycache"
+)
+
+// refreshDiscovery bypasses the discovery cache of --discover, default StatefulSets and completions
+var refreshDiscovery bool
+
+// runBrokerDiscovery prints the broker namespaces of the cluster, reusing the cached results of a
+// previous --discover within the cache TTL unless --refresh-discovery is set
+func runBrokerDiscovery(ctx context.Context, k8sClient *pkg.K8sClient) error {
+	server := k8sClient.GetConfig().Host
+	store, storeErr := discoverycache.NewStore("")
+	if storeErr == nil && !refreshDiscovery {
+		if entry, ok := store.Load(server, discoverycache.DefaultTTL); ok {
+			fmt.Printf("Using discovery results from %s ago (--refresh-discovery to scan again)\n\n", time.Since(entry.DiscoveredAt).Round(time.Second))
+			displayBrokerDiscovery(entry.Namespaces)
+			return nil
+		}
+	}
+
+	fmt.Println("Discovering broker pods across all accessible namespaces...")
+	found, err := k8sClient.DiscoverBrokers(ctx)
+	if err != nil {
+		return err
+	}
+
+	entry := discoverycache.Entry{Server: server, DiscoveredAt: time.Now().UTC()}
+	for _, namespace := range found {
+		entry.Namespaces = append(entry.Namespaces, discoverycache.Namespace{
+			Name:         namespace.Namespace,
+			StatefulSets: namespace.StatefulSets,
+			Pods:         namespace.Pods,
+			HealthPort:   namespace.HealthPort,
+			APIPort:      namespace.APIPort,
+		})
+	}
+	if storeErr == nil {
+		storeErr = store.Save(entry)
+	}
+	if storeErr != nil {
+		// The results are still printed; the next run simply discovers again
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache discovery results: %v\n", storeErr)
+	}
+
+	fmt.Println()
+	displayBrokerDiscovery(entry.Namespaces)
+	return nil
+}
+
+// displayBrokerDiscovery prints the discovered namespaces with ready-to-run status commands
+func displayBrokerDiscovery(namespaces []discoverycache.Namespace) {
+	if len(namespaces) == 0 {
+		fmt.Println("No broker resources found. You may need to:")
+		fmt.Println("1. Check if you're connected to the right cluster")
+		fmt.Println("2. Verify your kubeconfig has access to the namespaces containing brokers")
+		fmt.Println("3. Use specific pod and namespace names if brokers don't follow naming conventions")
+		return
+	}
+
+	for _, namespace := range namespaces {
+		fmt.Printf("Namespace: %s\n", namespace.Name)
+		for _, podName := range namespace.Pods {
+			fmt.Printf("  - %s\n", podName)
+		}
+		if namespace.HealthPort > 0 || namespace.APIPort > 0 {
+			fmt.Printf("  Ports:      health %s, API %s\n", portOrDash(namespace.HealthPort), portOrDash(namespace.APIPort))
+		}
+		if len(namespace.Pods) > 0 {
+			fmt.Printf("  Single pod: kubectl broker status --pod %s --namespace %s\n", namespace.Pods[0], namespace.Name)
+		} else if len(namespace.StatefulSets) > 0 {
+			fmt.Printf("  Single pod: kubectl broker status --pod %s-0 --namespace %s\n", namespace.StatefulSets[0], namespace.Name)
+		}
+		for _, statefulSet := range namespace.StatefulSets {
+			fmt.Printf("  All pods:   kubectl broker status --statefulset %s --namespace %s\n", statefulSet, namespace.Name)
+		}
+		fmt.Println()
+	}
+}
+
+func portOrDash(port int32) string {
+	if port <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d", port)
+}
+
+// cachedDiscovery returns the fresh discovery results of the current cluster, if --discover ran
+// within the cache TTL. Lookup failures are a miss so callers fall back to their live behavior.
+func cachedDiscovery() (*discoverycache.Entry, bool) {
+	if refreshDiscovery {
+		return nil, false
+	}
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return nil, false
+	}
+	store, err := discoverycache.NewStore("")
+	if err != nil {
+		return nil, false
+	}
+	return store.Load(k8sClient.GetConfig().Host, discoverycache.DefaultTTL)
+}
+
+// defaultStatefulSetFor returns the StatefulSet to target in namespace when none was given: the
+// only broker StatefulSet discovery cached for the namespace, or the "broker" default
+func defaultStatefulSetFor(namespace string) string {
+	if entry, ok := cachedDiscovery(); ok {
+		if cached, ok := entry.Namespace(namespace); ok && len(cached.StatefulSets) == 1 {
+			slog.Debug("Using StatefulSet from the discovery cache", "namespace", namespace, "statefulset", cached.StatefulSets[0])
+			return cached.StatefulSets[0]
+		}
+	}
+	statefulSet, _ := applyDefaultStatefulSet("")
+	return statefulSet
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+
+	"kubectl-broker/pkg"
+)
+
+// dryRunPlan is the structured form of the steps a mutating command skipped under --dry-run
+type dryRunPlan struct {
+	DryRun    bool                `json:"dryRun"`
+	Operation string              `json:"operation"`
+	Checks    []pkg.CapacityCheck `json:"checks,omitempty"`
+	Steps     []string            `json:"steps"`
+}
+
+// renderDryRunPlan prints what a mutating command would do and that nothing was changed
+func renderDryRunPlan(operation string, steps ...string) error {
+	return renderDryRunPlanWithChecks(operation, nil, steps...)
+}
+
+// renderDryRunPlanWithChecks prints the preflight checks a dry run verified before the plan
+func renderDryRunPlanWithChecks(operation string, checks []pkg.CapacityCheck, steps ...string) error {
+	plan := dryRunPlan{DryRun: true, Operation: operation, Checks: checks, Steps: steps}
+	if format := currentOutputFormat(); format != "table" {
+		return writeStructuredDryRunPlan(plan, format)
+	}
+
+	if len(checks) > 0 {
+		fmt.Println("Preflight checks")
+		renderCheckList(checks)
+		fmt.Println()
+	}
+
+	fmt.Printf("DRY RUN - %s would:\n", operation)
+	for i, step := range steps {
+		fmt.Printf("  %d. %s\n", i+1, step)
+	}
+	fmt.Println("\nNo changes were made. Run without --dry-run to apply.")
+	return nil
+}
+
+func writeStructuredDryRunPlan(plan dryRunPlan, format string) error {
+	if format == "yaml" {
+		data, err := yaml.Marshal(plan)
+		if err != nil {
+			return fmt.Errorf("failed to encode dry-run plan as YAML: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode dry-run plan as JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/edge"
+	"kubectl-broker/pkg/health"
+	"kubectl-broker/pkg/logging"
+)
+
+var (
+	edgeNamespace  string
+	edgePod        string
+	edgePort       int
+	edgeEndpoint   string
+	edgeOutputJSON bool
+	edgeDetailed   bool
+	edgeUsername   string
+	edgePassword   string
+)
+
+// edgeProfile holds the HiveMQ Edge port and endpoint defaults
+var edgeProfile = pkg.ProfileFor(pkg.ProductEdge)
+
+func newEdgeCommand() *cobra.Command {
+	var edgeCmd = &cobra.Command{
+		Use:   "edge",
+		Short: "HiveMQ Edge status and diagnostics",
+		Long: fmt.Sprintf(`Edge command performs health diagnostics for HiveMQ Edge gateways running on
+Kubernetes. Edge pods are found with the %s label selector
+and checked on the Edge HTTP port (named "%s", %d by default). The same commands
+are available at the top level when the plugin is invoked as kubectl-edge.`, pkg.EdgeSelector, edgeProfile.HealthPortName, edgeProfile.HealthPort),
+	}
+
+	edgeCmd.AddCommand(newEdgeStatusCommand())
+	edgeCmd.AddCommand(newEdgeAdaptersCommand())
+
+	return edgeCmd
+}
+
+func newEdgeStatusCommand() *cobra.Command {
+	var statusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Check HiveMQ Edge health status",
+		Long: `Status command checks the liveness or readiness endpoint of every HiveMQ Edge
+pod in the namespace concurrently. The port is the container port named "http",
+falling back to 8080, unless --port is given.
+
+Examples:
+  # Check Edge liveness in the current namespace
+  kubectl broker edge status
+
+  # Check readiness in a specific namespace
+  kubectl broker edge status -n edge --endpoint readiness
+
+  # Same check when invoked as kubectl-edge
+  kubectl edge status -n edge
+
+  # JSON output for external tools
+  kubectl broker edge status --json`,
+		RunE: runEdgeStatus,
+	}
+
+	statusCmd.Flags().StringVarP(&edgeNamespace, "namespace", "n", "", "Namespace (defaults to current kubectl context)")
+	statusCmd.Flags().IntVarP(&edgePort, "port", "p", 0, "Port number to use for health check (overrides auto-discovery)")
+	statusCmd.Flags().StringVar(&edgeEndpoint, "endpoint", edgeProfile.DefaultEndpoint(), "Health endpoint to query (liveness, readiness)")
+	statusCmd.Flags().BoolVar(&edgeOutputJSON, "json", false, "Output raw JSON response for external parsing")
+	statusCmd.Flags().BoolVar(&edgeDetailed, "detailed", false, "Show detailed component breakdown")
+
+	statusCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if !edgeProfile.SupportsEndpoint(edgeEndpoint) {
+			return fmt.Errorf("endpoint must be one of 'liveness' or 'readiness'")
+		}
+		return resolveEdgeNamespace(cmd.Context())
+	}
+
+	return statusCmd
+}
+
+func newEdgeAdaptersCommand() *cobra.Command {
+	var adaptersCmd = &cobra.Command{
+		Use:   "adapters",
+		Short: "List HiveMQ Edge protocol adapters and their connection state",
+		Long: `Adapters lists the protocol adapters (OPC UA, Modbus, S7, ...) of a HiveMQ Edge
+pod through the Edge REST API, together with their runtime and connection
+state. The first running Edge pod is used unless --pod is given. When the API
+requires authentication, pass --username and --password.
+
+Examples:
+  # List adapters in the current namespace
+  kubectl broker edge adapters
+
+  # Authenticate against the Edge API
+  kubectl broker edge adapters -n edge --username admin --password hivemq
+
+  # Machine-readable output
+  kubectl broker edge adapters --output json`,
+		RunE: runEdgeAdapters,
+	}
+
+	adaptersCmd.Flags().StringVarP(&edgeNamespace, "namespace", "n", "", "Namespace (defaults to current kubectl context)")
+	adaptersCmd.Flags().StringVar(&edgePod, "pod", "", "Edge pod to query (defaults to the first running pod)")
+	adaptersCmd.Flags().IntVarP(&edgePort, "port", "p", 0, "Port of the Edge REST API (overrides auto-discovery)")
+	adaptersCmd.Flags().StringVar(&edgeUsername, "username", "", "Username for the Edge REST API")
+	adaptersCmd.Flags().StringVar(&edgePassword, "password", "", "Password for the Edge REST API")
+
+	adaptersCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if edgePassword != "" && edgeUsername == "" {
+			return fmt.Errorf("--password requires --username")
+		}
+		return resolveEdgeNamespace(cmd.Context())
+	}
+
+	return adaptersCmd
+}
+
+// resolveEdgeNamespace applies the namespace of the current context when -n is not set
+func resolveEdgeNamespace(ctx context.Context) error {
+	resolvedNamespace, fromContext, err := resolveNamespace(edgeNamespace, false)
+	if err != nil {
+		return err
+	}
+	edgeNamespace = resolvedNamespace
+	if fromContext {
+		slog.Log(ctx, logging.DetailLevel(edgeDetailed && !edgeJSONOutput()), "Using namespace from context", "namespace", edgeNamespace)
+	}
+	return nil
+}
+
+func edgeJSONOutput() bool {
+	return edgeOutputJSON || currentOutputFormat() == "json"
+}
+
+func runEdgeStatus(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	showDetails := edgeDetailed && !edgeJSONOutput()
+
+	k8sClient, err := newK8sClient(showDetails)
+	if err != nil {
+		return pkg.EnhanceError(err, "Kubernetes client initialization")
+	}
+
+	pods, err := getEdgePods(ctx, k8sClient)
+	if err != nil {
+		return err
+	}
+	if showDetails {
+		fmt.Printf("Found %d Edge pods in namespace %s\n\n", len(pods), edgeNamespace)
+	}
+
+	port, err := resolveEdgePort(ctx, pods[0], edgeProfile.HealthPortFor)
+	if err != nil {
+		return err
+	}
+
+	options := health.HealthCheckOptions{
+		Endpoint:   edgeEndpoint,
+		OutputJSON: edgeJSONOutput(),
+		Detailed:   edgeDetailed,
+		Timeout:    10 * time.Second,
+		UseColors:  colorOutputEnabled() && !edgeJSONOutput(),
+		TLS:        apiTLSOptions(),
+	}
+	return k8sClient.PerformConcurrentHealthChecks(ctx, pods, port, options)
+}
+
+func runEdgeAdapters(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "Kubernetes client initialization")
+	}
+
+	pod, err := selectEdgePod(ctx, k8sClient)
+	if err != nil {
+		return err
+	}
+	port, err := resolveEdgePort(ctx, pod, edgeProfile.APIPortFor)
+	if err != nil {
+		return err
+	}
+
+	var adapters []edge.Adapter
+	tls := apiTLSOptions()
+	err = pkg.NewPodDialer(k8sClient, pod, port, false).Forward(ctx, func(localPort int) error {
+		client, err := edge.NewClient(tls.BaseURL(localPort), edge.ClientOptions{Timeout: operationTimeout(30 * time.Second), TLS: tls})
+		if err != nil {
+			return err
+		}
+		if edgeUsername != "" {
+			if err := client.Authenticate(ctx, edgeUsername, edgePassword); err != nil {
+				return err
+			}
+		}
+		adapters, err = client.ListAdapters(ctx)
+		return err
+	})
+	if errors.Is(err, edge.ErrUnauthorized) {
+		return fmt.Errorf("%w\n\nPlease either:\n- Pass the Edge credentials: --username <user> --password <password>\n- Check the users configured in the Edge config.xml", err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to query Edge pod %s: %w", pod.Name, err)
+	}
+
+	return renderEdgeAdapters(pod.Name, adapters)
+}
+
+// getEdgePods lists the Edge pods of the namespace and fails with guidance when there are none
+func getEdgePods(ctx context.Context, k8sClient *pkg.K8sClient) ([]*v1.Pod, error) {
+	pods, err := k8sClient.GetProductPods(ctx, edgeNamespace, edgeProfile)
+	if err != nil {
+		return nil, pkg.EnhanceError(err, fmt.Sprintf("failed to get Edge pods in namespace %s", edgeNamespace))
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no HiveMQ Edge pods found with label %s in namespace %s\n\nPlease either:\n- Check a different namespace: --namespace <namespace>\n- Check the pod labels: kubectl get pods -n %s --show-labels", pkg.EdgeSelector, edgeNamespace, edgeNamespace)
+	}
+	return pods, nil
+}
+
+// selectEdgePod returns --pod or the first running Edge pod
+func selectEdgePod(ctx context.Context, k8sClient *pkg.K8sClient) (*v1.Pod, error) {
+	if edgePod != "" {
+		return k8sClient.GetPod(ctx, edgeNamespace, edgePod)
+	}
+
+	pods, err := getEdgePods(ctx, k8sClient)
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods {
+		if pkg.ValidatePodStatus(pod) == nil {
+			return pod, nil
+		}
+	}
+	return nil, fmt.Errorf("no ready Edge pods found in namespace %s\n\nPlease either:\n- Check pod status: kubectl get pods -n %s -l %s\n- Target a pod explicitly: --pod <pod-name>", edgeNamespace, edgeNamespace, pkg.EdgeSelector)
+}
+
+// resolveEdgePort returns --port or the port the profile finds on the pod
+func resolveEdgePort(ctx context.Context, pod *v1.Pod, discover func(*v1.Pod) (int32, error)) (int32, error) {
+	if edgePort > 0 {
+		slog.Log(ctx, logging.DetailLevel(edgeDetailed), "Using specified port", "port", edgePort)
+		return int32(edgePort), nil
+	}
+	port, err := discover(pod)
+	if err != nil {
+		return 0, err
+	}
+	slog.Log(ctx, logging.DetailLevel(edgeDetailed), "Discovered port", "pod", pod.Name, "port", port)
+	return port, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fatih/color"
+	"sigs.k8s.io/yaml"
+
+	"kubectl-broker/pkg/edge"
+)
+
+var edgeAdapterColumns = []tableColumn{
+	{Title: "ADAPTER", Width: 24},
+	{Title: "TYPE", Width: 16},
+	{Title: "RUNTIME", Width: 10},
+	{Title: "CONNECTION", Width: 12},
+	{Title: "MESSAGE", Width: 0},
+}
+
+type edgeAdaptersPayload struct {
+	Pod      string         `json:"pod"`
+	Healthy  int            `json:"healthy"`
+	Total    int            `json:"total"`
+	Adapters []edge.Adapter `json:"adapters"`
+}
+
+func renderEdgeAdapters(podName string, adapters []edge.Adapter) error {
+	payload := edgeAdaptersPayload{Pod: podName, Total: len(adapters), Adapters: adapters}
+	for _, adapter := range adapters {
+		if adapter.Healthy() {
+			payload.Healthy++
+		}
+	}
+
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		return writeStructuredEdgeAdapters(payload, format)
+	}
+
+	if len(adapters) == 0 {
+		fmt.Printf("No protocol adapters configured on Edge pod %s\n", podName)
+		return nil
+	}
+
+	fmt.Printf("Protocol adapters of Edge pod %s\n\n", podName)
+	renderTableHeader(edgeAdapterColumns, 2)
+	useColors := colorOutputEnabled()
+	for _, adapter := range adapters {
+		runtime, connection, message := "-", "-", ""
+		if adapter.Status != nil {
+			runtime = valueOrDash(adapter.Status.Runtime)
+			connection = valueOrDash(adapter.Status.Connection)
+			message = adapter.Status.Message
+		}
+		connectionCell := fmt.Sprintf("%-12s", connection)
+		if useColors {
+			connectionCell = edgeAdapterColor(adapter).Sprint(connectionCell)
+		}
+		fmt.Printf("%-24s  %-16s  %-10s  %s  %s\n",
+			truncateString(adapter.ID, 24),
+			truncateString(adapter.Type, 16),
+			runtime,
+			connectionCell,
+			message)
+	}
+
+	fmt.Printf("\nHealthy: %d/%d adapters\n", payload.Healthy, payload.Total)
+	return nil
+}
+
+func edgeAdapterColor(adapter edge.Adapter) *color.Color {
+	if adapter.Healthy() {
+		return color.New(color.FgGreen)
+	}
+	if adapter.Status != nil && adapter.Status.Connection == edge.ConnectionError {
+		return color.New(color.FgRed)
+	}
+	return color.New(color.FgYellow)
+}
+
+func writeStructuredEdgeAdapters(payload edgeAdaptersPayload, format string) error {
+	if format == "yaml" {
+		data, err := yaml.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode adapters as YAML: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode adapters as JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
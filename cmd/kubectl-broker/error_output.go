@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/tracing"
+)
+
+// reportError prints a command failure to stderr, as a structured envelope when
+// --output json or yaml is selected so automation can parse it. Runs that reached a HiveMQ
+// API also report the request ID the broker-side logs can be searched for.
+func reportError(err error) {
+	requestID := ""
+	if tracing.RequestIDSent() {
+		requestID = tracing.RequestID()
+	}
+
+	format := currentOutputFormat()
+	if format == "table" || format == "csv" {
+		_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if requestID != "" {
+			_, _ = fmt.Fprintf(os.Stderr, "\nRequest ID: %s (%s header of the HiveMQ API requests)\n", requestID, tracing.RequestIDHeader)
+		}
+		return
+	}
+
+	envelope := pkg.NewErrorEnvelope(err)
+	envelope.Error.RequestID = requestID
+
+	var (
+		data   []byte
+		encErr error
+	)
+	if format == "yaml" {
+		data, encErr = yaml.Marshal(envelope)
+	} else {
+		data, encErr = json.MarshalIndent(envelope, "", "  ")
+		data = append(data, '\n')
+	}
+	if encErr != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return
+	}
+
+	_, _ = os.Stderr.Write(data)
+}
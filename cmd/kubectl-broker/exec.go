@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+)
+
+var (
+	execPodName     string
+	execStatefulSet string
+	execNamespace   string
+	execContainer   string
+	execHiveMQHome  string
+	heapDumpFile    string
+)
+
+// hiveMQLogLevels are the logback levels accepted by 'exec tools log-level'
+var hiveMQLogLevels = []string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR"}
+
+func newExecCommand() *cobra.Command {
+	var execCmd = &cobra.Command{
+		Use:   "exec [--pod POD] -- COMMAND [ARGS...]",
+		Short: "Run commands and HiveMQ tools inside a broker container",
+		Long: `Exec runs a command inside a broker container and streams its output. Without
+--pod the first ready pod of the StatefulSet is used, and the HiveMQ container is
+selected automatically in pods with sidecars.
+
+The 'tools' subcommand provides shortcuts for common HiveMQ diagnostics.
+
+Examples:
+  # Run a command on the first ready broker pod
+  kubectl broker exec -- ls -la /opt/hivemq/data
+
+  # Run a command on a specific pod
+  kubectl broker exec --pod broker-1 -- cat /opt/hivemq/conf/config.xml
+
+  # Switch the root log level (picked up by logback's config scan)
+  kubectl broker exec tools log-level DEBUG
+
+  # Write a heap dump to the data volume
+  kubectl broker exec tools heap-dump --pod broker-0`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExec(cmd.Context(), args, true)
+		},
+	}
+
+	execCmd.PersistentFlags().StringVar(&execPodName, "pod", "", "Pod to run the command in (defaults to the first ready pod of the StatefulSet)")
+	execCmd.PersistentFlags().StringVar(&execStatefulSet, "statefulset", "", "StatefulSet to pick a pod from (defaults to 'broker')")
+	execCmd.PersistentFlags().StringVarP(&execNamespace, "namespace", "n", "", "Namespace (defaults to current kubectl context)")
+	execCmd.PersistentFlags().StringVarP(&execContainer, "container", "c", "", "Container name (defaults to the HiveMQ container)")
+
+	execCmd.AddCommand(newExecToolsCommand())
+
+	return execCmd
+}
+
+func newExecToolsCommand() *cobra.Command {
+	var toolsCmd = &cobra.Command{
+		Use:   "tools",
+		Short: "Shortcuts for HiveMQ diagnostics inside the broker container",
+		Long: `Tools runs well-known HiveMQ diagnostics inside the broker container.
+
+Available tools:
+  log-level LEVEL   Set the root logback level (TRACE, DEBUG, INFO, WARN, ERROR)
+  thread-dump       Print a JVM thread dump of the broker
+  heap-dump         Write a JVM heap dump to the data volume
+
+Thread and heap dumps require jcmd in the broker image.`,
+	}
+
+	toolsCmd.PersistentFlags().StringVar(&execHiveMQHome, "hivemq-home", "/opt/hivemq", "HiveMQ installation directory inside the container")
+
+	toolsCmd.AddCommand(&cobra.Command{
+		Use:       "log-level LEVEL",
+		Short:     "Set the HiveMQ root log level",
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: hiveMQLogLevels,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			level := strings.ToUpper(args[0])
+			if !containsString(hiveMQLogLevels, level) {
+				return fmt.Errorf("invalid log level %q\n\nPlease either:\n- Use one of: %s\n- Example: exec tools log-level DEBUG", args[0], strings.Join(hiveMQLogLevels, ", "))
+			}
+
+			logback := path.Join(execHiveMQHome, "conf", "logback.xml")
+			script := fmt.Sprintf(`sed -i -E 's|<root level="[A-Za-z]+"|<root level="%[1]s"|' %[2]s && grep -o '<root level="[A-Z]*"' %[2]s`, level, logback)
+			return runExec(cmd.Context(), []string{"sh", "-c", script}, true)
+		},
+	})
+
+	toolsCmd.AddCommand(&cobra.Command{
+		Use:   "thread-dump",
+		Short: "Print a JVM thread dump of the broker",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExec(cmd.Context(), []string{"sh", "-c", pkg.FindHiveMQPIDScript + ` && jcmd "$PID" Thread.print`}, false)
+		},
+	})
+
+	heapDumpCmd := &cobra.Command{
+		Use:   "heap-dump",
+		Short: "Write a JVM heap dump of the broker to the data volume",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file := heapDumpFile
+			if file == "" {
+				file = path.Join(execHiveMQHome, "data", fmt.Sprintf("heap-%s.hprof", time.Now().UTC().Format("20060102-150405")))
+			}
+			return runExec(cmd.Context(), []string{"sh", "-c", pkg.FindHiveMQPIDScript + fmt.Sprintf(` && jcmd "$PID" GC.heap_dump %q`, file)}, true)
+		},
+	}
+	heapDumpCmd.Flags().StringVar(&heapDumpFile, "file", "", "Path of the heap dump inside the container (defaults to <hivemq-home>/data/heap-<timestamp>.hprof)")
+	toolsCmd.AddCommand(heapDumpCmd)
+
+	return toolsCmd
+}
+
+// runExec resolves the target pod and container and streams the command output to stdout.
+// Mutating commands, including arbitrary ones given to exec, only print their plan with --dry-run.
+func runExec(ctx context.Context, command []string, mutating bool) error {
+	if err := mutuallyExclusive(execPodName != "", "--pod", execStatefulSet != "", "--statefulset"); err != nil {
+		return err
+	}
+
+	resolvedNamespace, _, err := resolveNamespace(execNamespace, false)
+	if err != nil {
+		return err
+	}
+	execNamespace = resolvedNamespace
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "Kubernetes client initialization")
+	}
+
+	pod, err := resolveExecPod(ctx, k8sClient)
+	if err != nil {
+		return err
+	}
+
+	container := execContainer
+	if container == "" {
+		container = pkg.BrokerContainerName(pod)
+	}
+
+	if mutating && globalFlags.DryRun {
+		return renderDryRunPlan("exec", fmt.Sprintf("Run in pod %s (container %s): %s", pod.Name, container, strings.Join(command, " ")))
+	}
+
+	stream, err := k8sClient.ExecCommandStreamInContainer(ctx, execNamespace, pod.Name, container, command)
+	if err != nil {
+		return pkg.EnhanceError(err, fmt.Sprintf("exec in pod %s", pod.Name))
+	}
+	defer stream.Close()
+
+	if _, err := io.Copy(os.Stdout, stream); err != nil {
+		return fmt.Errorf("command failed in pod %s (container %s): %w", pod.Name, container, err)
+	}
+	return nil
+}
+
+// resolveExecPod returns the pod given by --pod or the first ready pod of the StatefulSet
+func resolveExecPod(ctx context.Context, k8sClient *pkg.K8sClient) (*v1.Pod, error) {
+	if execPodName != "" {
+		pod, err := k8sClient.GetPod(ctx, execNamespace, execPodName)
+		if err != nil {
+			return nil, pkg.EnhanceError(err, fmt.Sprintf("pod %s in namespace %s", execPodName, execNamespace))
+		}
+		return pod, nil
+	}
+
+	statefulSet, _ := applyDefaultStatefulSet(execStatefulSet)
+	return firstReadyPod(ctx, k8sClient, execNamespace, statefulSet)
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
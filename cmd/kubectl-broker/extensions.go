@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/audit"
+)
+
+var (
+	extensionsNamespace   string
+	extensionsStatefulSet string
+	extensionsName        string
+)
+
+func newExtensionsCommand() *cobra.Command {
+	var extensionsCmd = &cobra.Command{
+		Use:   "extensions",
+		Short: "List, enable and disable the HiveMQ extensions of all broker pods",
+		Long: `Extensions manages the HiveMQ extensions installed in the extensions folder of
+every broker pod ($HIVEMQ_EXTENSION_FOLDER, by default /opt/hivemq/extensions).
+
+HiveMQ's management API has no endpoints for the extension lifecycle, so the
+commands use the mechanism HiveMQ documents for it: a DISABLED file in an
+extension's folder stops the extension at runtime, and removing the file starts
+it again, without a broker restart.`,
+	}
+
+	extensionsCmd.PersistentFlags().StringVarP(&extensionsNamespace, "namespace", "n", "", "Namespace (defaults to current kubectl context)")
+	extensionsCmd.PersistentFlags().StringVar(&extensionsStatefulSet, "statefulset", "", "Broker StatefulSet (defaults to 'broker')")
+
+	var listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "Show the version and state of every extension per pod",
+		Long: `List shows each extension's version, read from its hivemq-extension.xml, and
+whether it is enabled on every broker pod. Extensions whose version or state
+differs between pods are flagged.
+
+Examples:
+  # List the extensions of the default StatefulSet
+  kubectl broker extensions list -n production
+
+  # Machine-readable listing
+  kubectl broker extensions list --output json`,
+		Args: cobra.NoArgs,
+		RunE: runExtensionsList,
+	}
+
+	extensionsCmd.AddCommand(listCmd)
+	extensionsCmd.AddCommand(newExtensionStateCommand(pkg.ExtensionEnabled))
+	extensionsCmd.AddCommand(newExtensionStateCommand(pkg.ExtensionDisabled))
+	return extensionsCmd
+}
+
+// newExtensionStateCommand builds the enable or disable subcommand
+func newExtensionStateCommand(state string) *cobra.Command {
+	verb, title := pkg.ExtensionVerb(state), extensionVerbTitle(state)
+	var stateCmd = &cobra.Command{
+		Use:   verb,
+		Short: fmt.Sprintf("%s an extension on all broker pods", title),
+		Long: fmt.Sprintf(`%[1]s an extension on every broker pod of the StatefulSet, one pod after
+another. All pods must be running before anything is changed. When a pod fails,
+the pods changed so far are set back to their previous state, so the extension
+does not end up %[2]s on only some brokers.
+
+Examples:
+  # %[1]s the Prometheus extension on all brokers
+  kubectl broker extensions %[3]s --name hivemq-prometheus-extension -n production
+
+  # Show the pods that would change
+  kubectl broker extensions %[3]s --name hivemq-prometheus-extension --dry-run`, title, state, verb),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExtensionState(cmd, state)
+		},
+	}
+
+	stateCmd.Flags().StringVar(&extensionsName, "name", "", "Folder name of the extension, e.g. hivemq-prometheus-extension")
+	_ = stateCmd.MarkFlagRequired("name")
+	return stateCmd
+}
+
+func runExtensionsList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	k8sClient, pods, err := extensionPods(ctx)
+	if err != nil {
+		return err
+	}
+	return renderExtensionList(k8sClient.CollectExtensions(ctx, pods))
+}
+
+func runExtensionState(cmd *cobra.Command, state string) error {
+	ctx := cmd.Context()
+	if err := pkg.ValidateExtensionName(extensionsName); err != nil {
+		return err
+	}
+
+	k8sClient, pods, err := extensionPods(ctx)
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		if pod.Status.Phase != v1.PodRunning {
+			return fmt.Errorf("pod %s is %s, so extension %s cannot be changed on all brokers\n\nPlease either:\n- Wait until all pods run: kubectl get pods -n %s\n- Check the brokers: kubectl broker status --statefulset %s -n %s",
+				pod.Name, pod.Status.Phase, extensionsName, extensionsNamespace, extensionsStatefulSet, extensionsNamespace)
+		}
+	}
+
+	if globalFlags.DryRun {
+		steps := make([]string, 0, len(pods))
+		for _, pod := range pods {
+			steps = append(steps, fmt.Sprintf("%s extension %s on pod %s", extensionVerbTitle(state), extensionsName, pod.Name))
+		}
+		steps = append(steps, "Restore the previous state on the changed pods if any pod fails")
+		return renderDryRunPlan("extensions "+pkg.ExtensionVerb(state), steps...)
+	}
+
+	change, err := k8sClient.SetExtensionState(ctx, pods, extensionsName, state)
+	if change != nil {
+		recordAudit(ctx, k8sClient, extensionAuditRecord(change, err))
+		if renderErr := renderExtensionChange(change); renderErr != nil && err == nil {
+			err = renderErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%w\n\nPlease either:\n- Check the extension name: kubectl broker extensions list -n %s\n- Check the brokers: kubectl broker status --statefulset %s -n %s", err, extensionsNamespace, extensionsStatefulSet, extensionsNamespace)
+	}
+	return nil
+}
+
+// extensionVerbTitle is the capitalized action of a state change: Enable or Disable
+func extensionVerbTitle(state string) string {
+	verb := pkg.ExtensionVerb(state)
+	return strings.ToUpper(verb[:1]) + verb[1:]
+}
+
+// extensionPods resolves the namespace and StatefulSet and returns the broker pods
+func extensionPods(ctx context.Context) (*pkg.K8sClient, []*v1.Pod, error) {
+	resolvedNamespace, fromContext, err := resolveNamespace(extensionsNamespace, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	extensionsNamespace = resolvedNamespace
+	if fromContext {
+		slog.Info("Using namespace from context", "namespace", extensionsNamespace)
+	}
+	var defaulted bool
+	extensionsStatefulSet, defaulted = applyDefaultStatefulSet(extensionsStatefulSet)
+	if defaulted {
+		slog.Info("Using default StatefulSet", "statefulset", extensionsStatefulSet)
+	}
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return nil, nil, pkg.EnhanceError(err, "Kubernetes client initialization")
+	}
+	pods, err := k8sClient.GetPodsFromStatefulSet(ctx, extensionsNamespace, extensionsStatefulSet)
+	if err != nil {
+		return nil, nil, pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", extensionsStatefulSet, extensionsNamespace))
+	}
+	if len(pods) == 0 {
+		return nil, nil, fmt.Errorf("no pods found for StatefulSet %s in namespace %s", extensionsStatefulSet, extensionsNamespace)
+	}
+	return k8sClient, pods, nil
+}
+
+// extensionAuditRecord records the pods an extension was enabled or disabled on
+func extensionAuditRecord(change *pkg.ExtensionChange, changeErr error) audit.Record {
+	operation := audit.OperationExtensionEnable
+	if change.State == pkg.ExtensionDisabled {
+		operation = audit.OperationExtensionDisable
+	}
+	record := audit.Record{
+		Operation: operation,
+		Namespace: extensionsNamespace,
+		Resources: []audit.Resource{},
+		Details:   map[string]string{"extension": change.Extension, "statefulset": extensionsStatefulSet},
+	}
+	changed, failed := 0, 0
+	for _, pod := range change.Pods {
+		if pod.Error == "" && !pod.Changed {
+			continue
+		}
+		resource := audit.Resource{Kind: "Pod", Namespace: extensionsNamespace, Name: pod.Pod, Error: pod.Error}
+		if pod.Error != "" {
+			failed++
+		} else if !pod.RolledBack {
+			changed++
+		}
+		record.Resources = append(record.Resources, resource)
+	}
+	record.Outcome = audit.OutcomeFor(changed, failed, changeErr)
+	if changeErr != nil {
+		record.Error = changeErr.Error()
+	}
+	return record
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"sigs.k8s.io/yaml"
+
+	"kubectl-broker/pkg"
+)
+
+var extensionColumns = []tableColumn{
+	{Title: "POD", Width: 24},
+	{Title: "EXTENSION", Width: 40},
+	{Title: "VERSION", Width: 12},
+	{Title: "STATE", Width: 10},
+}
+
+var extensionChangeColumns = []tableColumn{
+	{Title: "POD", Width: 24},
+	{Title: "PREVIOUS", Width: 10},
+	{Title: "RESULT", Width: 40},
+}
+
+type extensionListPayload struct {
+	Namespace   string              `json:"namespace"`
+	StatefulSet string              `json:"statefulSet"`
+	Pods        []pkg.PodExtensions `json:"pods"`
+	Drift       []string            `json:"drift"`
+}
+
+func renderExtensionList(listing []pkg.PodExtensions) error {
+	drift := pkg.ExtensionDrift(listing)
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		return writeStructuredExtensions(extensionListPayload{
+			Namespace:   extensionsNamespace,
+			StatefulSet: extensionsStatefulSet,
+			Pods:        listing,
+			Drift:       drift,
+		}, format)
+	}
+
+	fmt.Printf("Extensions of StatefulSet %s in namespace %s\n\n", extensionsStatefulSet, extensionsNamespace)
+
+	table := newTableWriter(extensionColumns, 2)
+	table.header()
+	for _, pod := range listing {
+		switch {
+		case pod.Error != "":
+			table.row(pod.Pod, "-", "-", "unknown")
+		case len(pod.Extensions) == 0:
+			table.row(pod.Pod, "no extensions installed", "-", "-")
+		}
+		for _, extension := range pod.Extensions {
+			state := extension.State
+			if state == pkg.ExtensionDisabled && colorOutputEnabled() {
+				state = color.New(color.FgYellow).Sprint(state)
+			}
+			table.row(pod.Pod, truncateString(extension.Name, 40), valueOrDash(extension.Version), state)
+		}
+	}
+
+	for _, pod := range listing {
+		if pod.Error != "" {
+			fmt.Printf("\nNote: extensions of pod %s could not be read: %s\n", pod.Pod, pod.Error)
+		}
+	}
+	if len(drift) > 0 {
+		fmt.Printf("\nWarning: version or state differs between pods: %s\n", strings.Join(drift, ", "))
+	}
+	return nil
+}
+
+func renderExtensionChange(change *pkg.ExtensionChange) error {
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		return writeStructuredExtensions(change, format)
+	}
+
+	fmt.Printf("%s extension %s in StatefulSet %s\n\n", extensionVerbTitle(change.State), change.Extension, extensionsStatefulSet)
+
+	table := newTableWriter(extensionChangeColumns, 2)
+	table.header()
+	for _, pod := range change.Pods {
+		result := change.State
+		switch {
+		case pod.Error != "":
+			result = "failed: " + pod.Error
+		case pod.RolledBack:
+			result = "rolled back to " + pod.Previous
+		case !pod.Changed:
+			result = "already " + change.State
+		}
+		table.row(pod.Pod, valueOrDash(pod.Previous), truncateString(result, 40))
+	}
+	return nil
+}
+
+func writeStructuredExtensions(payload any, format string) error {
+	if format == "yaml" {
+		data, err := yaml.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode extensions as YAML: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode extensions as JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
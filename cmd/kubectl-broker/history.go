@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/health"
+	"kubectl-broker/pkg/history"
+)
+
+var (
+	historySince         string
+	historyNamespace     string
+	historyStatefulSet   string
+	historyAllNamespaces bool
+	historyFlapThreshold int
+)
+
+func newStatusHistoryCommand() *cobra.Command {
+	var historyCmd = &cobra.Command{
+		Use:   "history",
+		Short: "Show recorded health history, flapping pods and trends",
+		Long: `Show per-pod health history recorded by 'status --record'. Each recorded run is
+stored as a JSON line in ~/.kubectl-broker/history/health.jsonl (override with
+KUBECTL_BROKER_HISTORY_DIR).
+
+The report highlights pods that flap between states and pods whose share of
+healthy checks dropped over the selected window.
+
+Examples:
+  # Record results on every run (e.g. from cron)
+  kubectl broker status --record
+
+  # Show the last 24 hours for the default StatefulSet
+  kubectl broker status history --since 24h
+
+  # Show a week of history across all namespaces as JSON
+  kubectl broker status history --since 7d --all-namespaces --output json`,
+		RunE:        runStatusHistory,
+		Annotations: map[string]string{csvOutputAnnotation: "true"},
+	}
+
+	historyCmd.Flags().StringVar(&historySince, "since", "24h", "Only include runs newer than this duration (e.g., 1h, 24h, 7d)")
+	historyCmd.Flags().StringVarP(&historyNamespace, "namespace", "n", "", "Namespace (defaults to current kubectl context)")
+	historyCmd.Flags().StringVar(&historyStatefulSet, "statefulset", "", "Only include runs for this StatefulSet")
+	historyCmd.Flags().BoolVar(&historyAllNamespaces, "all-namespaces", false, "Include runs from all namespaces")
+	historyCmd.Flags().IntVar(&historyFlapThreshold, "flap-threshold", history.DefaultFlapThreshold, "Status changes within the window that mark a pod as flapping")
+
+	return historyCmd
+}
+
+func runStatusHistory(cmd *cobra.Command, args []string) error {
+	window, err := parsePositiveDurationFlag("since", historySince)
+	if err != nil {
+		return err
+	}
+
+	filter := history.Filter{
+		Since:       time.Now().Add(-window),
+		StatefulSet: historyStatefulSet,
+	}
+	if !historyAllNamespaces {
+		resolvedNamespace, _, err := resolveNamespace(historyNamespace, true)
+		if err != nil {
+			return err
+		}
+		filter.Namespace = resolvedNamespace
+	}
+
+	store, err := history.NewStore("")
+	if err != nil {
+		return err
+	}
+
+	runs, err := store.Load(filter)
+	if err != nil {
+		return err
+	}
+
+	return displayHealthHistory(runs, history.Summarize(runs, historyFlapThreshold), filter, store.Path())
+}
+
+// recordHealthRun appends a status run to the local history. Failures only warn so
+// recording never changes the outcome of a health check.
+func recordHealthRun(statefulSet string, records []history.PodRecord) {
+	store, err := history.NewStore("")
+	if err == nil {
+		err = store.Append(history.Run{
+			Timestamp:   time.Now().UTC(),
+			Namespace:   namespace,
+			StatefulSet: statefulSet,
+			Endpoint:    endpoint,
+			Pods:        records,
+		})
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record health history: %v\n", err)
+	}
+}
+
+// historyRecordsFromResults converts concurrent health check results into history records
+func historyRecordsFromResults(results []pkg.HealthCheckResult) []history.PodRecord {
+	records := make([]history.PodRecord, 0, len(results))
+	for _, result := range results {
+		record := history.PodRecord{
+			Pod:            result.PodName,
+			Status:         result.Status,
+			ResponseTimeMs: result.ResponseTime.Milliseconds(),
+		}
+		if result.Error != nil {
+			record.Error = result.Error.Error()
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// historyRecordForPod builds a history record for single pod mode
+func historyRecordForPod(name string, parsedHealth *health.ParsedHealthData, policy health.ComponentPolicy, elapsed time.Duration, err error) history.PodRecord {
+	record := history.PodRecord{
+		Pod:            name,
+		Status:         "RESPONSE_RECEIVED",
+		ResponseTimeMs: elapsed.Milliseconds(),
+	}
+
+	switch {
+	case err != nil:
+		record.Status = "HEALTH_CHECK_FAILED"
+		record.Error = err.Error()
+	case parsedHealth != nil && health.IsHealthy(policy.OverallStatus(parsedHealth)):
+		record.Status = history.HealthyStatus
+	case parsedHealth != nil:
+		record.Status = string(policy.OverallStatus(parsedHealth))
+	}
+
+	return record
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"sigs.k8s.io/yaml"
+
+	"kubectl-broker/pkg/history"
+)
+
+var historyColumns = []tableColumn{
+	{Title: "NAMESPACE", Width: 16},
+	{Title: "POD", Width: 24},
+	{Title: "CHECKS", Width: 6},
+	{Title: "HEALTHY%", Width: 8},
+	{Title: "CHANGES", Width: 7},
+	{Title: "LAST STATUS", Width: 19},
+	{Title: "TREND", Width: 9},
+	{Title: "FLAPPING", Width: 8},
+}
+
+func displayHealthHistory(runs []history.Run, summaries []history.PodSummary, filter history.Filter, path string) error {
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		return writeStructuredHistory(runs, summaries, filter, format)
+	case "csv":
+		return writeHealthHistoryCSV(summaries)
+	default:
+		displayHealthHistoryTable(runs, summaries, filter, path)
+		return nil
+	}
+}
+
+func displayHealthHistoryTable(runs []history.Run, summaries []history.PodSummary, filter history.Filter, path string) {
+	if len(runs) == 0 {
+		fmt.Printf("No health history recorded since %s in %s\n", filter.Since.Local().Format(time.RFC3339), path)
+		fmt.Println("Record runs with: kubectl broker status --record")
+		return
+	}
+
+	fmt.Printf("Health history: %d runs from %s to %s\n\n", len(runs),
+		runs[0].Timestamp.Local().Format(time.RFC3339), runs[len(runs)-1].Timestamp.Local().Format(time.RFC3339))
+
+	renderTableHeader(historyColumns, 2)
+
+	useColors := colorOutputEnabled()
+	for _, summary := range summaries {
+		flapping := "no"
+		if summary.Flapping {
+			flapping = "yes"
+		}
+
+		fmt.Printf("%-16s  %-24s  %-6d  %-8s  %-7d  %-19s  %s  %s\n",
+			truncateString(summary.Namespace, 16),
+			truncateString(summary.Pod, 24),
+			summary.Checks,
+			fmt.Sprintf("%.0f%%", summary.HealthyPercent()),
+			summary.Transitions,
+			historyStatusColor(summary.LastStatus, useColors).Sprintf("%-19s", truncateString(summary.LastStatus, 19)),
+			historyTrendColor(summary.Trend, useColors).Sprintf("%-9s", summary.Trend),
+			flapping)
+	}
+
+	for _, summary := range summaries {
+		if len(summary.StatusCounts) <= 1 {
+			continue
+		}
+		fmt.Printf("\n%s/%s: %s", summary.Namespace, summary.Pod, formatStatusCounts(summary.StatusCounts))
+	}
+	fmt.Println()
+}
+
+// writeHealthHistoryCSV writes one row per pod summary
+func writeHealthHistoryCSV(summaries []history.PodSummary) error {
+	w := newCSVWriter("namespace", "statefulset", "pod", "checks", "healthy_percent", "transitions", "last_status", "last_seen", "trend", "flapping")
+	for _, summary := range summaries {
+		w.row(summary.Namespace, summary.StatefulSet, summary.Pod, summary.Checks, fmt.Sprintf("%.1f", summary.HealthyPercent()), summary.Transitions,
+			summary.LastStatus, csvTime(summary.LastSeen), summary.Trend, summary.Flapping)
+	}
+	return w.flush()
+}
+
+func formatStatusCounts(counts map[string]int) string {
+	statuses := make([]string, 0, len(counts))
+	for status := range counts {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	parts := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		parts = append(parts, fmt.Sprintf("%s=%d", status, counts[status]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func historyStatusColor(status string, useColors bool) *color.Color {
+	if !useColors {
+		return color.New()
+	}
+	switch status {
+	case history.HealthyStatus:
+		return color.New(color.FgGreen)
+	case "DEGRADED":
+		return color.New(color.FgYellow)
+	default:
+		return color.New(color.FgRed)
+	}
+}
+
+func historyTrendColor(trend history.Trend, useColors bool) *color.Color {
+	if !useColors {
+		return color.New()
+	}
+	switch trend {
+	case history.TrendImproving:
+		return color.New(color.FgGreen)
+	case history.TrendDegrading:
+		return color.New(color.FgRed)
+	default:
+		return color.New()
+	}
+}
+
+func writeStructuredHistory(runs []history.Run, summaries []history.PodSummary, filter history.Filter, format string) error {
+	payload := historyStructuredOutput{
+		Since:       filter.Since.UTC(),
+		Namespace:   filter.Namespace,
+		StatefulSet: filter.StatefulSet,
+		Runs:        len(runs),
+		Pods:        make([]historyPodEntry, 0, len(summaries)),
+	}
+
+	for _, summary := range summaries {
+		payload.Pods = append(payload.Pods, historyPodEntry{
+			Namespace:      summary.Namespace,
+			StatefulSet:    summary.StatefulSet,
+			Pod:            summary.Pod,
+			Checks:         summary.Checks,
+			HealthyPercent: summary.HealthyPercent(),
+			Transitions:    summary.Transitions,
+			StatusCounts:   summary.StatusCounts,
+			LastStatus:     summary.LastStatus,
+			LastSeen:       summary.LastSeen,
+			Trend:          string(summary.Trend),
+			Flapping:       summary.Flapping,
+		})
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+
+	switch format {
+	case "yaml":
+		data, err = yaml.Marshal(payload)
+	default:
+		data, err = json.MarshalIndent(payload, "", "  ")
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to render %s output: %w", format, err)
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
+
+type historyStructuredOutput struct {
+	Since       time.Time         `json:"since"`
+	Namespace   string            `json:"namespace,omitempty"`
+	StatefulSet string            `json:"statefulSet,omitempty"`
+	Runs        int               `json:"runs"`
+	Pods        []historyPodEntry `json:"pods"`
+}
+
+type historyPodEntry struct {
+	Namespace      string         `json:"namespace"`
+	StatefulSet    string         `json:"statefulSet,omitempty"`
+	Pod            string         `json:"pod"`
+	Checks         int            `json:"checks"`
+	HealthyPercent float64        `json:"healthyPercent"`
+	Transitions    int            `json:"transitions"`
+	StatusCounts   map[string]int `json:"statusCounts"`
+	LastStatus     string         `json:"lastStatus"`
+	LastSeen       time.Time      `json:"lastSeen"`
+	Trend          string         `json:"trend"`
+	Flapping       bool           `json:"flapping"`
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/health"
+)
+
+// JUnit XML as understood by Jenkins and GitLab: one suite per status run, one case per pod
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// writeJUnitReport writes the health results as JUnit XML. Pods that answered with a
+// non-UP status are failures, pods that could not be checked are errors.
+func writeJUnitReport(path, target string, results []pkg.HealthCheckResult, started time.Time) error {
+	suite := junitTestSuite{
+		Name:      fmt.Sprintf("kubectl-broker status %s/%s", namespace, target),
+		Tests:     len(results),
+		Time:      junitSeconds(time.Since(started)),
+		Timestamp: started.UTC().Format(time.RFC3339),
+	}
+
+	for _, result := range results {
+		testCase := junitTestCase{
+			Name:      result.PodName,
+			ClassName: fmt.Sprintf("%s.%s", namespace, target),
+			Time:      junitSeconds(result.ResponseTime),
+		}
+
+		switch {
+		case result.Error != nil || result.Status != "HEALTHY" && result.ParsedHealth == nil:
+			message := result.Status
+			if result.Error != nil {
+				message = result.Error.Error()
+			}
+			testCase.Error = &junitMessage{Message: message, Type: result.Status, Body: message}
+			suite.Errors++
+		case result.Status != "HEALTHY":
+			testCase.Failure = &junitMessage{
+				Message: fmt.Sprintf("pod %s reported %s", result.PodName, result.Status),
+				Type:    result.Status,
+				Body:    junitComponentReport(result.ParsedHealth),
+			}
+			suite.Failures++
+		default:
+			testCase.SystemOut = junitComponentReport(result.ParsedHealth)
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	data, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to render JUnit report: %w", err)
+	}
+
+	if err := os.WriteFile(path, append([]byte(xml.Header), append(data, '\n')...), 0o644); err != nil {
+		return fmt.Errorf("failed to write JUnit report %s: %w", path, err)
+	}
+	return nil
+}
+
+// junitComponentReport lists each component with its status, non-UP components first
+func junitComponentReport(parsed *health.ParsedHealthData) string {
+	if parsed == nil {
+		return ""
+	}
+
+	var problems, healthy []string
+	for _, component := range parsed.ComponentDetails {
+		line := fmt.Sprintf("%s: %s", component.Name, component.Status)
+		if component.Details != "" {
+			line += " (" + component.Details + ")"
+		}
+		if health.IsHealthy(component.Status) {
+			healthy = append(healthy, line)
+		} else {
+			problems = append(problems, line)
+		}
+	}
+
+	return strings.Join(append(problems, healthy...), "\n")
+}
+
+// singlePodResult adapts a single pod check to the shape used by the concurrent path
+func singlePodResult(podName string, parsedHealth *health.ParsedHealthData, policy health.ComponentPolicy, elapsed time.Duration, err error) pkg.HealthCheckResult {
+	result := pkg.HealthCheckResult{
+		PodName:      podName,
+		Status:       "RESPONSE_RECEIVED",
+		ResponseTime: elapsed,
+		ParsedHealth: parsedHealth,
+		Error:        err,
+	}
+
+	switch {
+	case err != nil:
+		result.Status = "HEALTH_CHECK_FAILED"
+	case parsedHealth != nil && health.IsHealthy(policy.OverallStatus(parsedHealth)):
+		result.Status = "HEALTHY"
+	case parsedHealth != nil:
+		result.Status = string(policy.OverallStatus(parsedHealth))
+	}
+
+	return result
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/health"
+)
+
+func TestWriteJUnitReportSeparatesFailuresFromErrors(t *testing.T) {
+	namespace = "prod"
+	t.Cleanup(func() { namespace = "" })
+
+	healthy := &health.ParsedHealthData{OverallStatus: health.StatusUP, ComponentDetails: []health.ComponentStatus{
+		{Name: "cluster", Status: health.StatusUP},
+	}}
+	degraded := &health.ParsedHealthData{OverallStatus: health.StatusDEGRADED, ComponentDetails: []health.ComponentStatus{
+		{Name: "cluster", Status: health.StatusUP},
+		{Name: "extensions", Status: health.StatusDEGRADED},
+	}}
+	policy := health.ComponentPolicy{}
+	results := []pkg.HealthCheckResult{
+		singlePodResult("broker-0", healthy, policy, 10*time.Millisecond, nil),
+		singlePodResult("broker-1", degraded, policy, 20*time.Millisecond, nil),
+		singlePodResult("broker-2", nil, policy, time.Second, errors.New("connection refused")),
+	}
+
+	path := filepath.Join(t.TempDir(), "report.xml")
+	if err := writeJUnitReport(path, "broker", results, time.Now()); err != nil {
+		t.Fatalf("writeJUnitReport returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	var report junitTestSuites
+	if err := xml.Unmarshal(data, &report); err != nil {
+		t.Fatalf("report is not valid XML: %v\n%s", err, data)
+	}
+	if len(report.Suites) != 1 {
+		t.Fatalf("expected one suite, got %d", len(report.Suites))
+	}
+	suite := report.Suites[0]
+	if suite.Tests != 3 || suite.Failures != 1 || suite.Errors != 1 {
+		t.Fatalf("suite counts tests/failures/errors = %d/%d/%d, want 3/1/1", suite.Tests, suite.Failures, suite.Errors)
+	}
+
+	cases := map[string]junitTestCase{}
+	for _, testCase := range suite.Cases {
+		if testCase.ClassName != "prod.broker" {
+			t.Errorf("%s has classname %q, want prod.broker", testCase.Name, testCase.ClassName)
+		}
+		cases[testCase.Name] = testCase
+	}
+
+	if ok := cases["broker-0"]; ok.Failure != nil || ok.Error != nil || ok.SystemOut != "cluster: UP" {
+		t.Errorf("healthy pod = %+v, want a passing case with the component report", ok)
+	}
+	if failed := cases["broker-1"]; failed.Failure == nil || failed.Error != nil || failed.Failure.Type != "DEGRADED" || failed.Failure.Body != "extensions: DEGRADED\ncluster: UP" {
+		t.Errorf("degraded pod = %+v, want a failure listing the degraded component first", failed)
+	}
+	if unreachable := cases["broker-2"]; unreachable.Error == nil || unreachable.Failure != nil || unreachable.Error.Message != "connection refused" || unreachable.Error.Type != "HEALTH_CHECK_FAILED" {
+		t.Errorf("unreachable pod = %+v, want an error with the connection failure", unreachable)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/health"
+)
+
+var (
+	licenseNamespace   string
+	licenseStatefulSet string
+	licensePort        int
+	licenseWarnWithin  string
+)
+
+func newLicenseCommand() *cobra.Command {
+	var licenseCmd = &cobra.Command{
+		Use:   "license",
+		Short: "Show and validate the HiveMQ extension licenses of all broker pods",
+		Long: `License reads the license state that enterprise extensions report in the
+health API of every broker pod: enterprise or community, trial, and the expiry
+date when the extension reports one.
+
+Warnings are shown for trial licenses, licenses expiring within --warn-within,
+pods whose license state cannot be read, and extensions whose license differs
+between pods (e.g. after a license file was updated on only some nodes). The
+command exits with an error when a license or trial has expired.
+
+Examples:
+  # Show the licenses of the default StatefulSet
+  kubectl broker license -n production
+
+  # Warn a month before a license expires
+  kubectl broker license --warn-within 30d
+
+  # Machine-readable report for monitoring
+  kubectl broker license --output json`,
+		Args: cobra.NoArgs,
+		RunE: runLicense,
+	}
+
+	licenseCmd.Flags().StringVarP(&licenseNamespace, "namespace", "n", "", "Namespace (defaults to current kubectl context)")
+	licenseCmd.Flags().StringVar(&licenseStatefulSet, "statefulset", "", "StatefulSet to check (defaults to 'broker')")
+	licenseCmd.Flags().IntVarP(&licensePort, "port", "p", 0, "Health port (overrides auto-discovery)")
+	licenseCmd.Flags().StringVar(&licenseWarnWithin, "warn-within", "14d", "Warn about licenses expiring within this period (e.g., 72h, 14d, 4w)")
+
+	return licenseCmd
+}
+
+func runLicense(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	warnWithin, err := parsePositiveDurationFlag("warn-within", licenseWarnWithin)
+	if err != nil {
+		return err
+	}
+
+	resolvedNamespace, fromContext, err := resolveNamespace(licenseNamespace, false)
+	if err != nil {
+		return err
+	}
+	licenseNamespace = resolvedNamespace
+	if fromContext {
+		slog.Info("Using namespace from context", "namespace", licenseNamespace)
+	}
+	statefulSet, defaulted := applyDefaultStatefulSet(licenseStatefulSet)
+	if defaulted {
+		slog.Info("Using default StatefulSet", "statefulset", statefulSet)
+	}
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "Kubernetes client initialization")
+	}
+
+	pods, err := k8sClient.GetPodsFromStatefulSet(ctx, licenseNamespace, statefulSet)
+	if err != nil {
+		return pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", statefulSet, licenseNamespace))
+	}
+	if len(pods) == 0 {
+		return fmt.Errorf("no pods found for StatefulSet %s in namespace %s", statefulSet, licenseNamespace)
+	}
+
+	podLicenses, err := k8sClient.CollectLicenses(ctx, pods, int32(licensePort), health.HealthCheckOptions{
+		Endpoint: "health",
+		Timeout:  10 * time.Second,
+		TLS:      apiTLSOptions(),
+	})
+	if err != nil {
+		return err
+	}
+
+	report := &pkg.LicenseReport{
+		Namespace:   licenseNamespace,
+		StatefulSet: statefulSet,
+		Pods:        podLicenses,
+		Findings:    pkg.AnalyzeLicenses(podLicenses, time.Now(), warnWithin),
+	}
+	if err := renderLicenseReport(report); err != nil {
+		return err
+	}
+
+	if report.Expired() {
+		return fmt.Errorf("expired license on StatefulSet %s in namespace %s\n\nPlease either:\n- Install a valid license file in the broker's license folder on every pod\n- Remove or disable the extensions with expired licenses", statefulSet, licenseNamespace)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fatih/color"
+	"sigs.k8s.io/yaml"
+
+	"kubectl-broker/pkg"
+)
+
+var licenseColumns = []tableColumn{
+	{Title: "POD", Width: 24},
+	{Title: "EXTENSION", Width: 40},
+	{Title: "LICENSE", Width: 26},
+	{Title: "EXPIRY", Width: 10},
+}
+
+type licensePayload struct {
+	*pkg.LicenseReport
+	Expired bool `json:"expired"`
+}
+
+func renderLicenseReport(report *pkg.LicenseReport) error {
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		return writeStructuredLicenseReport(licensePayload{LicenseReport: report, Expired: report.Expired()}, format)
+	}
+
+	fmt.Printf("Licenses of StatefulSet %s in namespace %s\n\n", report.StatefulSet, report.Namespace)
+
+	table := newTableWriter(licenseColumns, 2)
+	table.header()
+	for _, pod := range report.Pods {
+		switch {
+		case pod.Error != "":
+			table.row(pod.Pod, "-", "unknown", "-")
+		case len(pod.Licenses) == 0:
+			table.row(pod.Pod, "-", "no licensed extensions", "-")
+		}
+		for _, license := range pod.Licenses {
+			expiry := "-"
+			if license.Expiry != nil {
+				expiry = license.Expiry.Format("2006-01-02")
+			}
+			table.row(pod.Pod, truncateString(license.Extension, 40), license.Summary, expiry)
+		}
+	}
+
+	if len(report.Findings) == 0 {
+		fmt.Println("\nNo license problems found")
+		return nil
+	}
+
+	useColors := colorOutputEnabled()
+	fmt.Println("\nFindings")
+	for _, finding := range report.Findings {
+		severity := finding.Severity
+		if useColors {
+			if severity == pkg.LicenseExpired {
+				severity = color.New(color.FgRed, color.Bold).Sprint(severity)
+			} else {
+				severity = color.New(color.FgYellow).Sprint(severity)
+			}
+		}
+		subject := finding.Extension
+		if finding.Pod != "" && subject != "" {
+			subject = finding.Pod + "/" + subject
+		} else if finding.Pod != "" {
+			subject = finding.Pod
+		}
+		fmt.Printf("  - [%s] %s: %s\n", severity, subject, finding.Message)
+	}
+	return nil
+}
+
+func writeStructuredLicenseReport(payload licensePayload, format string) error {
+	if format == "yaml" {
+		data, err := yaml.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode license report as YAML: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode license report as JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -1,12 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/backup"
+	"kubectl-broker/pkg/logging"
+	"kubectl-broker/pkg/transport"
 )
 
 // ProductMode represents the invocation mode
@@ -15,23 +25,59 @@ type ProductMode int
 const (
 	ModeBroker ProductMode = iota
 	ModePulse
+	ModeEdge
 )
 
 // ProductContext holds the product mode and related configuration
 type ProductContext struct {
-	Mode ProductMode
-	Name string
+	Mode    ProductMode
+	Name    string
+	Profile pkg.ProductProfile // port and endpoint defaults of the product
 }
 
 // GlobalFlags holds global configuration flags
 type GlobalFlags struct {
-	NoColor bool
-	Output  string
+	NoColor               bool
+	Output                string
+	Quiet                 bool
+	NoHeaders             bool
+	APITLS                bool
+	APIInsecureSkipVerify bool
+	APICACert             string
+	APITLSServerName      string
+	APIRetries            int
+	APIRetryBackoff       time.Duration
+	APIToken              string
+	APITokenFile          string
+	APIOAuthTokenURL      string
+	APIOAuthClientID      string
+	APIOAuthClientSecret  string
+	APIOAuthScopes        []string
+	Timeout               time.Duration
+	InCluster             bool
+	KubeContext           string
+	Impersonate           string
+	ImpersonateGroups     []string
+	CacheTTL              time.Duration
+	Verbose               int
+	LogFormat             string
+	AuditEvents           bool
+	LocalAddress          string
+	HealthPortName        string
+	APIPortName           string
+	DryRun                bool
+	DebugHTTP             bool
+	DebugHTTPBodies       bool
+	DebugHTTPFile         string
 }
 
 var globalFlags GlobalFlags
 
 func main() {
+	// Environment overrides of the product defaults shape flag defaults, so they come first;
+	// an invalid override is reported once the command runs
+	productDefaultsErr := configureProductDefaults()
+
 	// Detect product mode based on invocation name
 	productCtx := detectProductMode()
 
@@ -44,8 +90,64 @@ func main() {
 	// Add appropriate subcommands based on mode
 	addSubcommands(rootCmd, productCtx)
 
-	if err := rootCmd.Execute(); err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	// Errors are reported by reportError; usage text would corrupt structured error output
+	rootCmd.SilenceErrors = true
+	cobra.OnInitialize(func() {
+		if currentOutputFormat() != "table" || globalFlags.Quiet {
+			rootCmd.SilenceUsage = true
+		}
+	})
+
+	// Commands read the root context via cmd.Context(); startTracing adds the command span,
+	// applyTimeout the --timeout deadline and applyDryRun the --dry-run marker
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if productDefaultsErr != nil {
+			return productDefaultsErr
+		}
+		if err := validateQuiet(); err != nil {
+			return err
+		}
+		if err := validateCSVOutput(cmd); err != nil {
+			return err
+		}
+		if err := configureLogging(); err != nil {
+			return err
+		}
+		if err := configureHTTPDebug(); err != nil {
+			return err
+		}
+		if err := validateAPIAuth(); err != nil {
+			return err
+		}
+		configurePortNames()
+		if err := configureKubeAccess(); err != nil {
+			return err
+		}
+		if err := transport.SetLocalAddress(globalFlags.LocalAddress); err != nil {
+			return fmt.Errorf("%w\n\nPlease either:\n- Bind IPv4 loopback: --local-address 127.0.0.1\n- Bind IPv6 loopback: --local-address ::1", err)
+		}
+		startTracing(cmd)
+		applyTimeout(cmd, args)
+		applyDryRun(cmd)
+		return nil
+	}
+	// Ctrl+C cancels the root context, which closes port-forwards and stops in-flight requests
+	ctx, stopInterrupt := pkg.WithInterrupt(context.Background())
+	err := rootCmd.ExecuteContext(ctx)
+	stopInterrupt()
+	if cancelTimeout != nil {
+		cancelTimeout()
+	}
+	if debugHTTPFile != nil {
+		_ = debugHTTPFile.Close()
+	}
+	finishTracing(err)
+	if pkg.Interrupted(ctx) {
+		reportError(interruptSummary(ctx))
+		os.Exit(130)
+	}
+	if err != nil {
+		reportError(timeoutGuidance(err))
 		os.Exit(1)
 	}
 }
@@ -53,13 +155,135 @@ func main() {
 // addGlobalFlags adds global flags to the root command
 func addGlobalFlags(rootCmd *cobra.Command) {
 	rootCmd.PersistentFlags().BoolVar(&globalFlags.NoColor, "no-color", false, "Disable ANSI color output")
-	rootCmd.PersistentFlags().StringVar(&globalFlags.Output, "output", "table", "Output format: table, json, yaml")
+	rootCmd.PersistentFlags().StringVar(&globalFlags.Output, "output", "table", "Output format: table, wide, json, yaml, or csv for list commands")
+	rootCmd.PersistentFlags().BoolVar(&globalFlags.NoHeaders, "no-headers", false, "Omit the header row of --output csv")
+	rootCmd.PersistentFlags().BoolVarP(&globalFlags.Quiet, "quiet", "q", false, "Print only the command's result value for scripts (e.g. the backup ID of backup create) and hide progress and info logs")
+	rootCmd.PersistentFlags().BoolVar(&globalFlags.APITLS, "api-tls", false, "Use HTTPS when calling the HiveMQ management and health APIs")
+	rootCmd.PersistentFlags().BoolVar(&globalFlags.APIInsecureSkipVerify, "api-insecure-skip-verify", false, "Skip TLS certificate verification for API calls (implies --api-tls)")
+	rootCmd.PersistentFlags().StringVar(&globalFlags.APICACert, "api-ca-cert", "", "PEM CA bundle used to verify the API certificate (implies --api-tls)")
+	rootCmd.PersistentFlags().StringVar(&globalFlags.APITLSServerName, "api-tls-server-name", "", "Name to verify the API certificate against instead of localhost, e.g. the broker service DNS name (implies --api-tls)")
+	rootCmd.PersistentFlags().BoolVar(&globalFlags.InCluster, "in-cluster", false, "Use the pod's service account instead of a kubeconfig (automatic when no kubeconfig is found in a pod)")
+	rootCmd.PersistentFlags().StringVar(&globalFlags.KubeContext, "context", "", "Kubeconfig context to use instead of the current context")
+	rootCmd.PersistentFlags().StringVar(&globalFlags.Impersonate, "as", "", "User to impersonate for Kubernetes API requests, like kubectl --as")
+	rootCmd.PersistentFlags().StringSliceVar(&globalFlags.ImpersonateGroups, "as-group", nil, "Group to impersonate for Kubernetes API requests, like kubectl --as-group (repeatable, requires --as)")
+	rootCmd.PersistentFlags().DurationVar(&globalFlags.CacheTTL, "cache-ttl", pkg.DefaultLookupCacheTTL, "Reuse StatefulSet, pod and service lookups for this long within a command; 0 disables the cache")
+	rootCmd.PersistentFlags().CountVarP(&globalFlags.Verbose, "verbose", "v", "Log debug details to stderr (kubeconfig, ports, discovery)")
+	rootCmd.PersistentFlags().StringVar(&globalFlags.LogFormat, "log-format", logging.FormatText, "Log format for stderr diagnostics: text or json")
+	rootCmd.PersistentFlags().DurationVar(&globalFlags.Timeout, "timeout", 0, "Overall time limit for the command (e.g. 30s, 2h); 0 means no limit")
+	rootCmd.PersistentFlags().IntVar(&globalFlags.APIRetries, "api-retries", backup.DefaultRetryPolicy.MaxRetries, "Retries for transient management API failures (0 disables retries)")
+	rootCmd.PersistentFlags().DurationVar(&globalFlags.APIRetryBackoff, "api-retry-backoff", backup.DefaultRetryPolicy.Backoff, "Initial backoff between management API retries (doubles per attempt, with jitter)")
+	rootCmd.PersistentFlags().StringVar(&globalFlags.APIToken, "api-token", "", "Bearer token for the management API (instead of --username/--password)")
+	rootCmd.PersistentFlags().StringVar(&globalFlags.APITokenFile, "api-token-file", "", "File with the bearer token for the management API, re-read on every request")
+	rootCmd.PersistentFlags().StringVar(&globalFlags.APIOAuthTokenURL, "api-oauth-token-url", "", "OAuth2 token endpoint for client-credentials tokens for the management API")
+	rootCmd.PersistentFlags().StringVar(&globalFlags.APIOAuthClientID, "api-oauth-client-id", "", "OAuth2 client ID (with --api-oauth-token-url)")
+	rootCmd.PersistentFlags().StringVar(&globalFlags.APIOAuthClientSecret, "api-oauth-client-secret", "", "OAuth2 client secret (with --api-oauth-token-url; defaults to $"+apiOAuthClientSecretEnv+")")
+	rootCmd.PersistentFlags().StringSliceVar(&globalFlags.APIOAuthScopes, "api-oauth-scopes", nil, "OAuth2 scopes to request (with --api-oauth-token-url)")
+	rootCmd.PersistentFlags().StringVar(&globalFlags.LocalAddress, "local-address", transport.DefaultLocalAddress, "Loopback address port-forwards bind to and API clients dial (localhost, 127.0.0.1 or ::1)")
+	rootCmd.PersistentFlags().StringVar(&globalFlags.HealthPortName, "health-port-name", "", "Container port name of the health API (default per product: health, internal-http or http; env $"+healthPortNameEnv+")")
+	rootCmd.PersistentFlags().StringVar(&globalFlags.APIPortName, "api-port-name", "", "Container and service port name of the REST API (default per product: api, internal-http or http; env $"+apiPortNameEnv+")")
+	rootCmd.PersistentFlags().BoolVar(&globalFlags.DryRun, "dry-run", false, "Print the plan of mutating operations (create, restore, push, cleanup, ...) and exit without changing anything")
+	rootCmd.PersistentFlags().BoolVar(&globalFlags.DebugHTTP, "debug-http", false, "Log every Kubernetes API and HiveMQ HTTP request (method, URL, status, duration) to stderr")
+	rootCmd.PersistentFlags().BoolVar(&globalFlags.DebugHTTPBodies, "debug-http-bodies", false, "Also log request and response bodies with secrets redacted (implies --debug-http)")
+	rootCmd.PersistentFlags().StringVar(&globalFlags.DebugHTTPFile, "debug-http-file", "", "Write the HTTP request log to this file instead of stderr (implies --debug-http)")
+	rootCmd.PersistentFlags().BoolVar(&globalFlags.AuditEvents, "audit-events", false, "Also emit Kubernetes Events on resources changed by destructive operations (cleanup, restore)")
 
 	// Note: Output format validation is handled by individual commands
 	// that use the global --output flag. Commands with their own output
 	// flags (like --json, --raw) handle their own validation.
 }
 
+// configureLogging installs the leveled stderr logger. Informational messages are
+// suppressed with --output json/yaml/csv so scripts only see warnings and errors.
+func configureLogging() error {
+	quiet := currentOutputFormat() != "table" || globalFlags.Quiet
+	logger, err := logging.New(os.Stderr, logging.LevelFor(globalFlags.Verbose, quiet), globalFlags.LogFormat)
+	if err != nil {
+		return fmt.Errorf("%w\n\nPlease either:\n- Use human-readable logs: --log-format text\n- Use machine-readable logs: --log-format json", err)
+	}
+	slog.SetDefault(logger)
+	return nil
+}
+
+// validateQuiet rejects --quiet together with an output format, which it replaces
+func validateQuiet() error {
+	if globalFlags.Quiet && (currentOutputFormat() != "table" || wideOutput()) {
+		return fmt.Errorf("--quiet cannot be used with --output %s\n\nPlease either:\n- Print only the result value: --quiet\n- Print structured output: --output %s", globalFlags.Output, globalFlags.Output)
+	}
+	return nil
+}
+
+// debugHTTPFile is the --debug-http-file log, closed once the command returned
+var debugHTTPFile *os.File
+
+// configureHTTPDebug enables the HTTP request log for --debug-http. Records are written at
+// debug level regardless of -v, in the --log-format of the other diagnostics.
+func configureHTTPDebug() error {
+	if !globalFlags.DebugHTTP && !globalFlags.DebugHTTPBodies && globalFlags.DebugHTTPFile == "" {
+		return nil
+	}
+
+	var out io.Writer = os.Stderr
+	if globalFlags.DebugHTTPFile != "" {
+		file, err := os.OpenFile(globalFlags.DebugHTTPFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return fmt.Errorf("cannot open HTTP debug log: %w\n\nPlease either:\n- Choose a writable path: --debug-http-file /tmp/kubectl-broker-http.log\n- Log to stderr instead: --debug-http", err)
+		}
+		debugHTTPFile = file
+		out = file
+	}
+
+	logger, err := logging.New(out, slog.LevelDebug, globalFlags.LogFormat)
+	if err != nil {
+		return err
+	}
+	logging.EnableHTTPDebug(logging.HTTPDebugOptions{Logger: logger, Bodies: globalFlags.DebugHTTPBodies})
+	return nil
+}
+
+// cancelTimeout releases the --timeout context once the command returned
+var cancelTimeout context.CancelFunc
+
+// applyTimeout runs after flag parsing and bounds the executing command's context by --timeout
+func applyTimeout(cmd *cobra.Command, _ []string) {
+	if globalFlags.Timeout <= 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(cmd.Context(), globalFlags.Timeout)
+	cancelTimeout = cancel
+	cmd.SetContext(ctx)
+}
+
+// applyDryRun marks the executing command's context with --dry-run, so pkg-level operations
+// refuse to mutate even where a command has no plan output of its own
+func applyDryRun(cmd *cobra.Command) {
+	if globalFlags.DryRun {
+		cmd.SetContext(pkg.WithDryRun(cmd.Context()))
+	}
+}
+
+// interruptSummary reports the signal that interrupted the command and the operations it
+// stopped, in place of the cancellation errors they returned
+func interruptSummary(ctx context.Context) error {
+	cause := context.Cause(ctx)
+	stopped := pkg.InterruptedOperations()
+	if len(stopped) == 0 {
+		return cause
+	}
+	return fmt.Errorf("%w\n\nStopped operations:\n- %s", cause, strings.Join(stopped, "\n- "))
+}
+
+// timeoutGuidance explains failures caused by the --timeout deadline
+func timeoutGuidance(err error) error {
+	if globalFlags.Timeout <= 0 {
+		return err
+	}
+	// Some layers flatten the cause with %v, so fall back to the message
+	if !errors.Is(err, context.DeadlineExceeded) && !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+		return err
+	}
+	return fmt.Errorf("%w\n\nPlease either:\n- Increase the limit: --timeout %s\n- Remove --timeout to run without an overall limit", err, globalFlags.Timeout*2)
+}
+
 // isTerminal checks if the given file is a terminal
 func isTerminal(f *os.File) bool {
 	fileInfo, err := f.Stat()
@@ -79,15 +303,26 @@ func detectProductMode() ProductContext {
 	// Check if invoked as kubectl-pulse or contains "pulse"
 	if strings.Contains(progName, "pulse") {
 		return ProductContext{
-			Mode: ModePulse,
-			Name: "kubectl-pulse",
+			Mode:    ModePulse,
+			Name:    "kubectl-pulse",
+			Profile: pkg.ProfileFor(pkg.ProductPulse),
+		}
+	}
+
+	// Check if invoked as kubectl-edge or contains "edge"
+	if strings.Contains(progName, "edge") {
+		return ProductContext{
+			Mode:    ModeEdge,
+			Name:    "kubectl-edge",
+			Profile: pkg.ProfileFor(pkg.ProductEdge),
 		}
 	}
 
 	// Default to broker mode
 	return ProductContext{
-		Mode: ModeBroker,
-		Name: "kubectl-broker",
+		Mode:    ModeBroker,
+		Name:    "kubectl-broker",
+		Profile: pkg.ProfileFor(pkg.ProductBroker),
 	}
 }
 
@@ -105,6 +340,18 @@ in checking liveness and readiness endpoints of Pulse server deployments.`,
 				cmd.Help()
 			},
 		}
+	case ModeEdge:
+		return &cobra.Command{
+			Use:   ctx.Name,
+			Short: "HiveMQ Edge diagnostics for Kubernetes",
+			Long: `kubectl-edge is a kubectl plugin that provides health diagnostics for
+HiveMQ Edge gateways running on Kubernetes. It checks the liveness and
+readiness endpoints of Edge pods and lists protocol adapters through the
+Edge REST API.`,
+			Run: func(cmd *cobra.Command, args []string) {
+				cmd.Help()
+			},
+		}
 	default: // ModeBroker
 		return &cobra.Command{
 			Use:   ctx.Name,
@@ -123,14 +370,42 @@ backup operations, and other cluster management features.`,
 func addSubcommands(rootCmd *cobra.Command, ctx ProductContext) {
 	switch ctx.Mode {
 	case ModePulse:
-		// Pulse mode: only add status command from pulse.go
+		// Pulse mode: Pulse health checks and admin API commands at the top level
 		rootCmd.AddCommand(newPulseStatusCommand())
+		rootCmd.AddCommand(newPulseInfoCommand())
+		rootCmd.AddCommand(newPulseConfigCommand())
+		rootCmd.AddCommand(newPulseDiagnosticsCommand())
+	case ModeEdge:
+		// Edge mode: Edge health checks and adapters at the top level
+		rootCmd.AddCommand(newEdgeStatusCommand())
+		rootCmd.AddCommand(newEdgeAdaptersCommand())
 	default: // ModeBroker
 		// Broker mode: add all broker commands
 		rootCmd.AddCommand(newStatusCommand())
+		rootCmd.AddCommand(newDiscoverCommand())
+		rootCmd.AddCommand(newExecCommand())
+		rootCmd.AddCommand(newDebugCommand())
+		rootCmd.AddCommand(newConfigCommand())
+		rootCmd.AddCommand(newCheckCommand())
+		rootCmd.AddCommand(newScaleCommand())
+		rootCmd.AddCommand(newLicenseCommand())
+		rootCmd.AddCommand(newExtensionsCommand())
+		rootCmd.AddCommand(newCollectCommand())
+		rootCmd.AddCommand(newAPICommand())
 		rootCmd.AddCommand(newBackupCommand())
 		rootCmd.AddCommand(newVolumesCommand())
+		rootCmd.AddCommand(newAuditCommand())
+		rootCmd.AddCommand(newVersionCommand())
 		// Also add pulse as a subcommand for backward compatibility
 		rootCmd.AddCommand(newPulseCommand())
+		rootCmd.AddCommand(newEdgeCommand())
 	}
+
+	// The binary is shared by all products, so every mode can upgrade it
+	rootCmd.AddCommand(newUpgradeCommand())
+
+	// Replace cobra's default completion command with one that documents kubectl plugin usage
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(newCompletionCommand(ctx))
+	registerDynamicCompletions(rootCmd)
 }
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/notify"
+)
+
+var (
+	notifyWebhook string
+	notifyFormat  string
+
+	// notifyDetails collects what the running operation adds to its notification, such as the
+	// backup ID or the reclaimed storage
+	notifyDetails = map[string]string{}
+)
+
+// addNotifyFlags registers --notify-webhook and --notify-format on a long-running command
+func addNotifyFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&notifyWebhook, "notify-webhook", "", "POST a summary of the operation to this URL when it finishes or fails")
+	cmd.Flags().StringVar(&notifyFormat, "notify-format", string(notify.FormatJSON), "Webhook payload: json, or slack for a Slack incoming webhook")
+}
+
+// noteDetail adds a detail such as a size or an ID to the operation's notification
+func noteDetail(key, value string) {
+	notifyDetails[key] = value
+}
+
+// withNotification wraps a command's RunE so that, with --notify-webhook, a summary of the
+// operation is posted once it returns. target reports the namespace and StatefulSet after the
+// command resolved its defaults. Dry runs are not reported and a failed delivery only warns.
+func withNotification(operation string, target func() (namespace, statefulSet string), run func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if notifyWebhook == "" || globalFlags.DryRun {
+			return run(cmd, args)
+		}
+		if err := validateNotifyFlags(); err != nil {
+			return err
+		}
+
+		startedAt := time.Now()
+		runErr := run(cmd, args)
+
+		summary := notify.Summary{
+			Operation:       operation,
+			Result:          notify.ResultSucceeded,
+			StartedAt:       startedAt.UTC(),
+			DurationSeconds: time.Since(startedAt).Seconds(),
+			Details:         notifyDetails,
+		}
+		summary.Namespace, summary.StatefulSet = target()
+		summary.KubeContext, _ = pkg.CurrentKubeIdentity()
+		if globalFlags.InCluster {
+			summary.KubeContext = "in-cluster"
+		}
+		if runErr != nil {
+			summary.Result = notify.ResultFailed
+			summary.Error = runErr.Error()
+			if pkg.Interrupted(cmd.Context()) {
+				summary.Result = notify.ResultInterrupted
+			}
+		}
+
+		// The operation's context may be cancelled or past its --timeout
+		if err := notify.Send(context.WithoutCancel(cmd.Context()), notifyWebhook, notify.Format(notifyFormat), summary); err != nil {
+			slog.Warn("Failed to send notification", "operation", operation, "error", err)
+		}
+		return runErr
+	}
+}
+
+func validateNotifyFlags() error {
+	if notifyFormat != string(notify.FormatJSON) && notifyFormat != string(notify.FormatSlack) {
+		return fmt.Errorf("invalid --notify-format %q\n\nPlease either:\n- Post the JSON summary: --notify-format json\n- Post a Slack message: --notify-format slack", notifyFormat)
+	}
+	if parsed, err := url.Parse(notifyWebhook); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid --notify-webhook URL\n\nPlease either:\n- Use an http(s) URL: --notify-webhook https://hooks.example.com/backup\n- Omit --notify-webhook to run without notifications")
+	}
+	return nil
+}
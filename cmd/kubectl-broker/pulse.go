@@ -3,7 +3,9 @@ package main
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -12,6 +14,7 @@ import (
 
 	"kubectl-broker/pkg"
 	"kubectl-broker/pkg/health"
+	"kubectl-broker/pkg/logging"
 )
 
 var (
@@ -22,6 +25,15 @@ var (
 	pulseEndpoint   string
 	pulseDiscover   bool
 	pulsePort       int
+	pulseWaitReady  bool
+)
+
+const (
+	// pulseEndpointBoth checks liveness and readiness side by side
+	pulseEndpointBoth = "both"
+
+	pulseDefaultWaitTimeout = 5 * time.Minute
+	pulseWaitInterval       = 5 * time.Second
 )
 
 func newPulseCommand() *cobra.Command {
@@ -30,11 +42,14 @@ func newPulseCommand() *cobra.Command {
 		Short: "HiveMQ Pulse server status and diagnostics",
 		Long: `Pulse command performs health diagnostics for HiveMQ Pulse servers running 
 on Kubernetes. It checks the liveness and readiness endpoints of Pulse server pods
-using the app.kubernetes.io/name=hivemq-pulse-server label selector.`,
+using the app.kubernetes.io/name=hivemq-pulse-server label selector, and queries
+the Pulse admin API for server state, configuration and diagnostics bundles.`,
 	}
 
-	// Add status subcommand
 	pulseCmd.AddCommand(newPulseStatusCommand())
+	pulseCmd.AddCommand(newPulseInfoCommand())
+	pulseCmd.AddCommand(newPulseConfigCommand())
+	pulseCmd.AddCommand(newPulseDiagnosticsCommand())
 
 	return pulseCmd
 }
@@ -45,7 +60,19 @@ func newPulseStatusCommand() *cobra.Command {
 		Short: "Check HiveMQ Pulse server health status",
 		Long: `Status command performs health diagnostics for HiveMQ Pulse servers. It discovers
 Pulse server pods using the app.kubernetes.io/name=hivemq-pulse-server label and checks
-their liveness and readiness endpoints on the internal-http port.
+their liveness and readiness endpoints on the internal-http port. All replicas
+are checked concurrently.
+
+--wait-ready polls the readiness endpoint of every replica until the number of
+ready pods matches the replicas of the owning Deployment or StatefulSet, which
+makes it usable as a post-deploy gate. The wait is bounded by --timeout
+(5 minutes when not set) and fails when the replicas do not become ready.
+
+Defaults follow the standard Pulse Helm install and can be changed for custom
+installs through the environment: KUBECTL_PULSE_SELECTOR (pod label selector),
+KUBECTL_PULSE_DEPLOYMENT (Deployment whose pods are checked when the selector
+matches none), KUBECTL_PULSE_PORT (health port when no container port is named
+internal-http) and KUBECTL_PULSE_ENDPOINT (default --endpoint).
 
 Examples:
   # Check status with current namespace context
@@ -60,6 +87,12 @@ Examples:
   # Check readiness endpoint instead of liveness
   kubectl broker pulse status --endpoint readiness
 
+  # Liveness and readiness of every replica side by side
+  kubectl broker pulse status --endpoint both
+
+  # Post-deploy gate in a pipeline
+  kubectl broker pulse status -n pulse --wait-ready --timeout 5m
+
   # Get detailed output with debug information
   kubectl broker pulse status --detailed
 
@@ -77,7 +110,8 @@ Examples:
 	statusCmd.Flags().BoolVar(&pulseOutputJSON, "json", false, "Output raw JSON response for external parsing")
 	statusCmd.Flags().BoolVar(&pulseOutputRaw, "raw", false, "Output unprocessed health response")
 	statusCmd.Flags().BoolVar(&pulseDetailed, "detailed", false, "Show detailed component breakdown")
-	statusCmd.Flags().StringVar(&pulseEndpoint, "endpoint", "liveness", "Health endpoint to query (liveness, readiness)")
+	statusCmd.Flags().StringVar(&pulseEndpoint, "endpoint", pulseProfile.DefaultEndpoint(), "Health endpoint to query (liveness, readiness, both)")
+	statusCmd.Flags().BoolVar(&pulseWaitReady, "wait-ready", false, "Block until all Pulse replicas pass readiness (bounded by --timeout, default 5m)")
 
 	// Apply intelligent defaults and validate flags
 	statusCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
@@ -86,8 +120,22 @@ Examples:
 		}
 
 		// Validate endpoint (pulse-specific)
-		if pulseEndpoint != "liveness" && pulseEndpoint != "readiness" {
-			return fmt.Errorf("endpoint must be either 'liveness' or 'readiness'")
+		if !pulseProfile.SupportsEndpoint(pulseEndpoint) && pulseEndpoint != pulseEndpointBoth {
+			return fmt.Errorf("endpoint must be one of 'liveness', 'readiness' or 'both'")
+		}
+		if pulseWaitReady {
+			if err := mutuallyExclusive(true, "--wait-ready", pulseDiscover, "--discover"); err != nil {
+				return err
+			}
+			if err := mutuallyExclusive(true, "--wait-ready", pulseOutputRaw, "--raw"); err != nil {
+				return err
+			}
+			if err := mutuallyExclusive(true, "--wait-ready", cmd.Flags().Changed("endpoint"), "--endpoint"); err != nil {
+				return err
+			}
+		}
+		if err := mutuallyExclusive(pulseEndpoint == pulseEndpointBoth, "--endpoint both", pulseOutputRaw, "--raw"); err != nil {
+			return err
 		}
 
 		if !pulseDiscover {
@@ -96,8 +144,8 @@ Examples:
 				return err
 			}
 			pulseNamespace = resolvedNamespace
-			if fromContext && !pulseOutputJSON && !pulseOutputRaw && pulseDetailed {
-				fmt.Printf("Using namespace from context: %s\n", pulseNamespace)
+			if fromContext {
+				slog.Log(cmd.Context(), logging.DetailLevel(!pulseOutputJSON && !pulseOutputRaw && pulseDetailed), "Using namespace from context", "namespace", pulseNamespace)
 			}
 		}
 		return nil
@@ -106,11 +154,11 @@ Examples:
 	return statusCmd
 }
 
-func runPulseStatus(_ *cobra.Command, _ []string) error {
-	ctx := context.Background()
+func runPulseStatus(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
 
 	// Initialize Kubernetes client
-	k8sClient, err := pkg.NewK8sClient(pulseDetailed && !pulseOutputJSON && !pulseOutputRaw)
+	k8sClient, err := newK8sClient(pulseDetailed && !pulseOutputJSON && !pulseOutputRaw)
 	if err != nil {
 		return pkg.EnhanceError(err, "Kubernetes client initialization")
 	}
@@ -124,7 +172,7 @@ func runPulseStatus(_ *cobra.Command, _ []string) error {
 }
 
 func discoverPulseServers(ctx context.Context, k8sClient *pkg.K8sClient) error {
-	labelSelector := "app.kubernetes.io/name=hivemq-pulse-server"
+	labelSelector := pulseProfile.Selector
 
 	fmt.Printf("Discovering HiveMQ Pulse servers with label: %s\n\n", labelSelector)
 
@@ -176,34 +224,23 @@ func discoverPulseServers(ctx context.Context, k8sClient *pkg.K8sClient) error {
 }
 
 func runPulseHealthCheck(ctx context.Context, k8sClient *pkg.K8sClient) error {
-	labelSelector := "app.kubernetes.io/name=hivemq-pulse-server"
-
-	if !pulseOutputJSON && !pulseOutputRaw && pulseDetailed {
+	showDetails := !pulseOutputJSON && !pulseOutputRaw && pulseDetailed
+	if showDetails {
 		fmt.Printf("Checking health of HiveMQ Pulse servers in namespace %s\n", pulseNamespace)
-		fmt.Printf("Using label selector: %s\n", labelSelector)
+		slog.Info("Using label selector", "selector", pulseProfile.Selector)
 	}
 
-	// Get all Pulse server pods using label selector via core client
-	coreClient := k8sClient.GetCoreClient()
-	podList, err := coreClient.Pods(pulseNamespace).List(ctx, metav1.ListOptions{
-		LabelSelector: labelSelector,
-	})
+	pods, err := k8sClient.GetProductPods(ctx, pulseNamespace, pulseProfile)
 	if err != nil {
 		return pkg.EnhanceError(err, fmt.Sprintf("failed to get Pulse server pods in namespace %s", pulseNamespace))
 	}
 
-	if len(podList.Items) == 0 {
-		return fmt.Errorf("no HiveMQ Pulse server pods found with label %s in namespace %s\n\nTry:\n- Using discovery mode: kubectl broker pulse status --discover\n- Checking a different namespace: kubectl broker pulse status --namespace <namespace>\n- Using broker status instead: kubectl broker status --discover", labelSelector, pulseNamespace)
+	if len(pods) == 0 {
+		return fmt.Errorf("no HiveMQ Pulse server pods found with label %s in namespace %s\n\nTry:\n- Using discovery mode: kubectl broker pulse status --discover\n- Checking a different namespace: kubectl broker pulse status --namespace <namespace>\n- Using broker status instead: kubectl broker status --discover\n- Naming the Pulse %s of a custom install: %s=<name>", pulseProfile.Selector, pulseNamespace, strings.ToLower(pulseProfile.WorkloadKind), pkg.ProductEnvVar(pkg.ProductPulse, strings.ToUpper(pulseProfile.WorkloadKind)))
 	}
 
-	if !pulseOutputJSON && !pulseOutputRaw && pulseDetailed {
-		fmt.Printf("Found %d Pulse server pods\n\n", len(podList.Items))
-	}
-
-	// Convert to slice of pod pointers for compatibility with PerformConcurrentHealthChecks
-	pods := make([]*v1.Pod, len(podList.Items))
-	for i := range podList.Items {
-		pods[i] = &podList.Items[i]
+	if showDetails {
+		fmt.Printf("Found %d Pulse server pods\n\n", len(pods))
 	}
 
 	// Create health options
@@ -214,55 +251,105 @@ func runPulseHealthCheck(ctx context.Context, k8sClient *pkg.K8sClient) error {
 		Detailed:   pulseDetailed,
 		Timeout:    10 * time.Second,
 		UseColors:  !pulseOutputJSON && !pulseOutputRaw, // Disable colors for JSON/raw output
+		TLS:        apiTLSOptions(),
+	}
+
+	healthPort, err := resolvePulsePort(ctx, pods[0])
+	if err != nil {
+		return err
 	}
 
-	// Use the internal-http port for Pulse servers
-	portName := "internal-http"
+	if pulseWaitReady {
+		return waitForPulseReady(ctx, k8sClient, healthPort, options)
+	}
 
-	// Discover the port by name instead of hardcoded port number
-	var healthPort int32
+	if pulseEndpoint == pulseEndpointBoth {
+		return checkPulseLivenessAndReadiness(ctx, k8sClient, pods, healthPort, options)
+	}
+
+	// Perform concurrent health checks
+	return k8sClient.PerformConcurrentHealthChecks(ctx, pods, healthPort, options)
+}
+
+// resolvePulsePort returns --port or the internal-http port of the pod (all replicas share the pod spec)
+func resolvePulsePort(ctx context.Context, pod *v1.Pod) (int32, error) {
+	showDetails := !pulseOutputJSON && !pulseOutputRaw && pulseDetailed
 	if pulsePort > 0 {
-		healthPort = int32(pulsePort)
-		if !pulseOutputJSON && !pulseOutputRaw && pulseDetailed {
-			fmt.Printf("Using specified port: %d\n", healthPort)
-		}
-	} else {
-		// Find the internal-http port from the first pod (assuming all pods have the same port config)
-		found := false
-		for _, container := range pods[0].Spec.Containers {
-			for _, port := range container.Ports {
-				if port.Name == portName {
-					healthPort = port.ContainerPort
-					found = true
-					break
-				}
-			}
-			if found {
-				break
-			}
-		}
+		healthPort := int32(pulsePort)
+		slog.Log(ctx, logging.DetailLevel(showDetails), "Using specified port", "port", healthPort)
+		return healthPort, nil
+	}
 
-		if !found {
-			var availablePorts []string
-			for _, container := range pods[0].Spec.Containers {
-				for _, port := range container.Ports {
-					portInfo := fmt.Sprintf("%s(%d)", port.Name, port.ContainerPort)
-					availablePorts = append(availablePorts, portInfo)
-				}
-			}
+	port, err := pulseProfile.HealthPortFor(pod)
+	if err != nil {
+		return 0, err
+	}
+	slog.Log(ctx, logging.DetailLevel(showDetails), "Discovered port", "port", port)
+	return port, nil
+}
 
-			if len(availablePorts) == 0 {
-				return fmt.Errorf("could not find port named '%s' in Pulse server pod %s\n\nNo container ports found. Use --port/-p to specify manually", portName, pods[0].Name)
-			}
+// checkPulseLivenessAndReadiness runs both probes concurrently and shows them side by side
+func checkPulseLivenessAndReadiness(ctx context.Context, k8sClient *pkg.K8sClient, pods []*v1.Pod, port int32, options health.HealthCheckOptions) error {
+	var (
+		wg                                sync.WaitGroup
+		liveness, readiness               []pkg.HealthCheckResult
+		livenessErr, readinessErr         error
+		livenessOptions, readinessOptions = options, options
+	)
+	livenessOptions.Endpoint = "liveness"
+	readinessOptions.Endpoint = "readiness"
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		liveness, livenessErr = k8sClient.CollectConcurrentHealthChecks(ctx, pods, port, livenessOptions)
+	}()
+	go func() {
+		defer wg.Done()
+		readiness, readinessErr = k8sClient.CollectConcurrentHealthChecks(ctx, pods, port, readinessOptions)
+	}()
+	wg.Wait()
+
+	if livenessErr != nil {
+		return livenessErr
+	}
+	if readinessErr != nil {
+		return readinessErr
+	}
 
-			return fmt.Errorf("could not find port named '%s' in Pulse server pod %s\n\nAvailable ports: %v\nUse --port/-p to specify manually", portName, pods[0].Name, availablePorts)
-		}
+	return renderPulseProbes(pods, liveness, readiness)
+}
 
-		if !pulseOutputJSON && !pulseOutputRaw && pulseDetailed {
-			fmt.Printf("Discovered port '%s': %d\n", portName, healthPort)
+// waitForPulseReady blocks until every desired Pulse replica passes its readiness check
+func waitForPulseReady(ctx context.Context, k8sClient *pkg.K8sClient, port int32, options health.HealthCheckOptions) error {
+	// Without --timeout the gate still gives up eventually instead of hanging a pipeline
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, pulseDefaultWaitTimeout)
+		defer cancel()
+	}
+
+	lastReady := -1
+	readiness, err := k8sClient.WaitForPulseReady(ctx, pulseNamespace, port, options, pulseWaitInterval, func(r pkg.PulseReadiness) {
+		if r.Ready != lastReady {
+			slog.Info("Waiting for Pulse replicas", "ready", fmt.Sprintf("%d/%d", r.Ready, r.Desired))
+			lastReady = r.Ready
+		}
+	})
+	if err != nil {
+		if len(readiness.Results) > 0 {
+			options.Endpoint = "readiness"
+			_ = k8sClient.DisplayHealthCheckResults(readiness.Results, options)
 		}
+		return fmt.Errorf("%w\n\nPlease either:\n- Allow more time: --timeout 10m\n- Inspect the pods: kubectl describe pods -n %s -l %s", err, pulseNamespace, pulseProfile.Selector)
 	}
 
-	// Perform concurrent health checks
-	return k8sClient.PerformConcurrentHealthChecks(ctx, pods, healthPort, options)
+	options.Endpoint = "readiness"
+	if err := k8sClient.DisplayHealthCheckResults(readiness.Results, options); err != nil {
+		return err
+	}
+	if !pulseOutputJSON && !pulseOutputRaw {
+		fmt.Printf("\nAll %d Pulse replicas are ready.\n", readiness.Desired)
+	}
+	return nil
 }
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/pulse"
+)
+
+var (
+	pulsePod            string
+	pulseConfigFile     string
+	pulseDiagnosticsDir string
+)
+
+// pulseProfile holds the HiveMQ Pulse port and endpoint defaults
+var pulseProfile = pkg.ProfileFor(pkg.ProductPulse)
+
+// addPulseAdminFlags registers the pod selection flags shared by the admin API commands
+func addPulseAdminFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&pulseNamespace, "namespace", "n", "", "Namespace (defaults to current kubectl context)")
+	cmd.Flags().StringVar(&pulsePod, "pod", "", "Pulse server pod to query (defaults to the first running pod)")
+	cmd.Flags().IntVarP(&pulsePort, "port", "p", 0, "Port of the Pulse admin API (overrides auto-discovery)")
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		resolvedNamespace, fromContext, err := resolveNamespace(pulseNamespace, false)
+		if err != nil {
+			return err
+		}
+		pulseNamespace = resolvedNamespace
+		if fromContext {
+			slog.Debug("Using namespace from context", "namespace", pulseNamespace)
+		}
+		return nil
+	}
+}
+
+func newPulseInfoCommand() *cobra.Command {
+	var infoCmd = &cobra.Command{
+		Use:   "info",
+		Short: "Show version, role and component state of a Pulse server",
+		Long: fmt.Sprintf(`Info queries the admin API of a HiveMQ Pulse server for its version, node ID,
+leadership, connected agents and the state of its internal components. The
+admin API is served on the container port named "%s"; the first running
+Pulse pod is used unless --pod is given. Bearer tokens are sent with the global
+--api-token, --api-token-file or --api-oauth-* flags.
+
+Examples:
+  # Server state in the current namespace
+  kubectl broker pulse info
+
+  # A specific replica
+  kubectl broker pulse info -n pulse --pod hivemq-pulse-server-1
+
+  # Same query when invoked as kubectl-pulse
+  kubectl pulse info -n pulse
+
+  # Machine-readable output
+  kubectl broker pulse info --output json`, pulseProfile.APIPortName),
+		RunE: runPulseInfo,
+	}
+	addPulseAdminFlags(infoCmd)
+	return infoCmd
+}
+
+func newPulseConfigCommand() *cobra.Command {
+	var configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Pulse server configuration",
+	}
+
+	var exportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "Export the effective Pulse server configuration",
+		Long: `Export prints the effective configuration of a Pulse server as YAML, as
+reported by its admin API, or writes it to --file.
+
+Examples:
+  # Print the configuration
+  kubectl broker pulse config export -n pulse
+
+  # Keep a copy for a diff after an upgrade
+  kubectl broker pulse config export -n pulse --file pulse-config.yaml`,
+		RunE: runPulseConfigExport,
+	}
+	addPulseAdminFlags(exportCmd)
+	exportCmd.Flags().StringVar(&pulseConfigFile, "file", "", "Write the configuration to this file instead of stdout")
+
+	configCmd.AddCommand(exportCmd)
+	return configCmd
+}
+
+func newPulseDiagnosticsCommand() *cobra.Command {
+	var diagnosticsCmd = &cobra.Command{
+		Use:   "diagnostics",
+		Short: "Download a Pulse server diagnostics bundle",
+		Long: `Diagnostics downloads the support bundle a Pulse server assembles (logs,
+thread dumps and configuration) as a zip archive named
+pulse-diagnostics-<pod>-<timestamp>.zip. Assembling the bundle can take a while
+on busy servers; the download is bounded by --timeout when that is longer than
+two minutes.
+
+Examples:
+  # Save the bundle to the current directory
+  kubectl broker pulse diagnostics -n pulse
+
+  # Bundle of a specific replica into a support folder
+  kubectl broker pulse diagnostics -n pulse --pod hivemq-pulse-server-1 --output-dir ./support`,
+		RunE: runPulseDiagnostics,
+	}
+	addPulseAdminFlags(diagnosticsCmd)
+	diagnosticsCmd.Flags().StringVar(&pulseDiagnosticsDir, "output-dir", ".", "Directory to save the diagnostics bundle")
+	return diagnosticsCmd
+}
+
+func runPulseInfo(cmd *cobra.Command, _ []string) error {
+	var podName string
+	var status *pulse.Status
+	err := withPulseClient(cmd.Context(), 30*time.Second, func(ctx context.Context, pod *v1.Pod, client *pulse.Client) error {
+		var err error
+		podName = pod.Name
+		status, err = client.Status(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return renderPulseInfo(podName, status)
+}
+
+func runPulseConfigExport(cmd *cobra.Command, _ []string) error {
+	out := os.Stdout
+	if pulseConfigFile != "" {
+		file, err := os.Create(pulseConfigFile)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", pulseConfigFile, err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	return withPulseClient(cmd.Context(), 30*time.Second, func(ctx context.Context, pod *v1.Pod, client *pulse.Client) error {
+		if _, err := client.ExportConfig(ctx, out); err != nil {
+			return err
+		}
+		if pulseConfigFile != "" {
+			fmt.Fprintf(os.Stderr, "Configuration of %s written to %s\n", pod.Name, pulseConfigFile)
+		}
+		return nil
+	})
+}
+
+func runPulseDiagnostics(cmd *cobra.Command, _ []string) error {
+	if err := os.MkdirAll(pulseDiagnosticsDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	return withPulseClient(cmd.Context(), operationTimeout(2*time.Minute), func(ctx context.Context, pod *v1.Pod, client *pulse.Client) error {
+		bundlePath := filepath.Join(pulseDiagnosticsDir, fmt.Sprintf("pulse-diagnostics-%s-%s.zip", pod.Name, time.Now().Format("20060102-150405")))
+		file, err := os.Create(bundlePath)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", bundlePath, err)
+		}
+		written, err := client.DiagnosticsBundle(ctx, file)
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = closeErr
+		}
+		if err != nil {
+			// A truncated archive would be useless to support, so do not leave it behind
+			if removeErr := os.Remove(bundlePath); removeErr != nil {
+				slog.Warn("Failed to remove partial diagnostics bundle", "path", bundlePath, "error", removeErr)
+			}
+			return err
+		}
+
+		fmt.Printf("Diagnostics bundle of %s saved to %s (%s)\n", pod.Name, bundlePath, formatBytes(written))
+		return nil
+	})
+}
+
+// withPulseClient port-forwards to the admin API of the selected Pulse pod and runs fn with a
+// client for it.
+func withPulseClient(ctx context.Context, timeout time.Duration, fn func(context.Context, *v1.Pod, *pulse.Client) error) error {
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "Kubernetes client initialization")
+	}
+
+	pod, err := selectPulsePod(ctx, k8sClient)
+	if err != nil {
+		return err
+	}
+	port, err := resolvePulseAPIPort(pod)
+	if err != nil {
+		return err
+	}
+
+	tls := apiTLSOptions()
+	err = pkg.NewPodDialer(k8sClient, pod, port, false).Forward(ctx, func(localPort int) error {
+		client, err := pulse.NewClient(tls.BaseURL(localPort), pulse.ClientOptions{Timeout: timeout, TLS: tls, Auth: apiAuth()})
+		if err != nil {
+			return err
+		}
+		return fn(ctx, pod, client)
+	})
+	if errors.Is(err, pulse.ErrUnauthorized) {
+		return fmt.Errorf("%w\n\nPlease either:\n- Pass a bearer token: --api-token <token> or --api-token-file <path>\n- Fetch one from your identity provider: --api-oauth-token-url <url> --api-oauth-client-id <id>", err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to query Pulse pod %s: %w", pod.Name, err)
+	}
+	return nil
+}
+
+// selectPulsePod returns --pod or the first running Pulse server pod
+func selectPulsePod(ctx context.Context, k8sClient *pkg.K8sClient) (*v1.Pod, error) {
+	if pulsePod != "" {
+		return k8sClient.GetPod(ctx, pulseNamespace, pulsePod)
+	}
+
+	pods, err := k8sClient.GetProductPods(ctx, pulseNamespace, pulseProfile)
+	if err != nil {
+		return nil, pkg.EnhanceError(err, fmt.Sprintf("failed to get Pulse server pods in namespace %s", pulseNamespace))
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no HiveMQ Pulse server pods found with label %s in namespace %s\n\nPlease either:\n- Find Pulse servers: kubectl broker pulse status --discover\n- Check a different namespace: --namespace <namespace>", pulseProfile.Selector, pulseNamespace)
+	}
+	for _, pod := range pods {
+		if pkg.ValidatePodStatus(pod) == nil {
+			return pod, nil
+		}
+	}
+	return nil, fmt.Errorf("no ready Pulse server pods found in namespace %s\n\nPlease either:\n- Check pod status: kubectl get pods -n %s -l %s\n- Target a pod explicitly: --pod <pod-name>", pulseNamespace, pulseNamespace, pulseProfile.Selector)
+}
+
+// resolvePulseAPIPort returns --port or the admin API port of the pod
+func resolvePulseAPIPort(pod *v1.Pod) (int32, error) {
+	if pulsePort > 0 {
+		slog.Debug("Using specified port", "port", pulsePort)
+		return int32(pulsePort), nil
+	}
+	port, err := pulseProfile.APIPortFor(pod)
+	if err != nil {
+		return 0, err
+	}
+	slog.Debug("Discovered admin API port", "pod", pod.Name, "port", port)
+	return port, nil
+}
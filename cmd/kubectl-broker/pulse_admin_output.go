@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fatih/color"
+	"sigs.k8s.io/yaml"
+
+	"kubectl-broker/pkg/pulse"
+)
+
+var pulseComponentColumns = []tableColumn{
+	{Title: "COMPONENT", Width: 20},
+	{Title: "STATE", Width: 10},
+	{Title: "MESSAGE", Width: 0},
+}
+
+type pulseInfoPayload struct {
+	Pod string `json:"pod"`
+	*pulse.Status
+}
+
+// renderPulseInfo prints the state a Pulse server reports through its admin API
+func renderPulseInfo(podName string, status *pulse.Status) error {
+	if format := currentOutputFormat(); format == "json" || format == "yaml" {
+		return writeStructuredPulseInfo(pulseInfoPayload{Pod: podName, Status: status}, format)
+	}
+
+	role := "follower"
+	if status.Leader {
+		role = "leader"
+	}
+	state := status.State
+	if colorOutputEnabled() {
+		state = pulseStateColor(status.State).Sprint(state)
+	}
+
+	fmt.Printf("Pulse server %s\n\n", podName)
+	fmt.Printf("Version:          %s\n", valueOrDash(status.Version))
+	fmt.Printf("Node ID:          %s\n", valueOrDash(status.NodeID))
+	fmt.Printf("State:            %s\n", state)
+	fmt.Printf("Role:             %s\n", role)
+	fmt.Printf("Started:          %s\n", valueOrDash(status.StartedAt))
+	fmt.Printf("Connected agents: %d\n", status.ConnectedAgents)
+
+	if len(status.Components) == 0 {
+		return nil
+	}
+	fmt.Println()
+	table := newTableWriter(pulseComponentColumns, 2)
+	table.header()
+	for _, component := range status.Components {
+		componentState := component.State
+		if colorOutputEnabled() {
+			componentState = pulseStateColor(component.State).Sprint(componentState)
+		}
+		table.row(truncateString(component.Name, 20), componentState, component.Message)
+	}
+	return nil
+}
+
+func pulseStateColor(state string) *color.Color {
+	switch state {
+	case pulse.StateRunning:
+		return color.New(color.FgGreen)
+	case pulse.StateStarting, pulse.StateDegraded:
+		return color.New(color.FgYellow)
+	default:
+		return color.New(color.FgRed)
+	}
+}
+
+func writeStructuredPulseInfo(payload pulseInfoPayload, format string) error {
+	if format == "yaml" {
+		data, err := yaml.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode Pulse server info as YAML: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode Pulse server info as JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
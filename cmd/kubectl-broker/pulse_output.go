@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+)
+
+type pulseProbeEntry struct {
+	Pod       string `json:"pod"`
+	Phase     string `json:"phase"`
+	Liveness  string `json:"liveness"`
+	Readiness string `json:"readiness"`
+	Error     string `json:"error,omitempty"`
+}
+
+// renderPulseProbes shows liveness and readiness per replica; results are in pod order
+func renderPulseProbes(pods []*v1.Pod, liveness, readiness []pkg.HealthCheckResult) error {
+	entries := make([]pulseProbeEntry, len(pods))
+	ready := 0
+	for i, pod := range pods {
+		entries[i] = pulseProbeEntry{
+			Pod:       pod.Name,
+			Phase:     string(pod.Status.Phase),
+			Liveness:  liveness[i].Status,
+			Readiness: readiness[i].Status,
+		}
+		if err := firstProbeError(liveness[i], readiness[i]); err != nil {
+			entries[i].Error = err.Error()
+		}
+		if readiness[i].Status == "HEALTHY" {
+			ready++
+		}
+	}
+
+	if pulseOutputJSON || currentOutputFormat() == "json" {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON results: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "POD NAME\tPHASE\tLIVENESS\tREADINESS\tDETAILS")
+	fmt.Fprintln(w, "--------\t-----\t--------\t---------\t-------")
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", entry.Pod, entry.Phase, entry.Liveness, entry.Readiness, valueOrDash(entry.Error))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nReady: %d/%d replicas\n", ready, len(pods))
+	return nil
+}
+
+func firstProbeError(results ...pkg.HealthCheckResult) error {
+	for _, result := range results {
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+	return nil
+}
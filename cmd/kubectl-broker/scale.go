@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/audit"
+	"kubectl-broker/pkg/backup"
+	"kubectl-broker/pkg/health"
+)
+
+var (
+	scaleNamespace   string
+	scaleStatefulSet string
+	scaleReplicas    int32
+	scaleWait        bool
+	scaleWaitTimeout time.Duration
+	scaleForce       bool
+	scaleUsername    string
+	scalePassword    string
+)
+
+// scalePollInterval is how often a waiting scale checks whether the cluster rebalanced
+const scalePollInterval = 10 * time.Second
+
+// scaleResult describes a finished scale of a broker StatefulSet
+type scaleResult struct {
+	*pkg.ScaleReport
+	Replicas int32         `json:"replicas"` // replica count the StatefulSet ended with
+	Duration time.Duration `json:"duration"`
+}
+
+func newScaleCommand() *cobra.Command {
+	var scaleCmd = &cobra.Command{
+		Use:   "scale",
+		Short: "Change the number of brokers after cluster health and quorum checks",
+		Long: `Scale sets the replicas of the broker StatefulSet after checking that the
+HiveMQ cluster can take the change.
+
+Before anything is changed, every current broker must report healthy. Scaling
+up additionally runs the capacity checks of 'kubectl broker check capacity'.
+Scaling down warns when the remaining brokers are no majority of the current
+cluster, or when the removed brokers (the highest ordinals) still hold queued
+messages that are not replicated to another node, as reported by the
+management API statistics of each removed pod. Failed checks stop the scale
+unless --force is given; warnings are printed and the scale continues.
+
+With --wait the replicas change one broker at a time, and after each step the
+command waits until all pods are ready and every broker reports healthy again,
+so the cluster rebalances before the next broker joins or leaves. Each step is
+bounded by --wait-timeout. With the global --dry-run flag the checks run and
+the steps are printed without changing the StatefulSet.
+
+Examples:
+  # Grow the cluster to five brokers, one at a time
+  kubectl broker scale --replicas 5 --wait
+
+  # Check a scale-down without changing anything
+  kubectl broker scale --replicas 3 -n production --dry-run
+
+  # Scale down a secured broker, waiting up to 20 minutes per step
+  kubectl broker scale --replicas 2 --wait --wait-timeout 20m --username admin --password secret`,
+		Args: cobra.NoArgs,
+		RunE: withNotification("scale", scaleNotifyTarget, runScale),
+	}
+
+	scaleCmd.Flags().StringVarP(&scaleNamespace, "namespace", "n", "", "Namespace (defaults to current kubectl context)")
+	scaleCmd.Flags().StringVar(&scaleStatefulSet, "statefulset", "", "StatefulSet to scale (defaults to 'broker')")
+	scaleCmd.Flags().Int32Var(&scaleReplicas, "replicas", 0, "Target number of brokers")
+	scaleCmd.Flags().BoolVar(&scaleWait, "wait", false, "Change one broker at a time and wait for the cluster to rebalance after each step")
+	scaleCmd.Flags().DurationVar(&scaleWaitTimeout, "wait-timeout", 10*time.Minute, "Maximum time to wait for the cluster to rebalance after each step")
+	scaleCmd.Flags().BoolVar(&scaleForce, "force", false, "Scale even when a preflight check failed")
+	scaleCmd.Flags().StringVar(&scaleUsername, "username", "", "Optional management API username for the queued message statistics")
+	scaleCmd.Flags().StringVar(&scalePassword, "password", "", "Optional management API password for the queued message statistics")
+	_ = scaleCmd.MarkFlagRequired("replicas")
+	addNotifyFlags(scaleCmd)
+
+	return scaleCmd
+}
+
+// scaleNotifyTarget names the namespace and StatefulSet of a scale in notifications
+func scaleNotifyTarget() (string, string) {
+	return scaleNamespace, scaleStatefulSet
+}
+
+func runScale(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	if scaleReplicas < 1 {
+		return fmt.Errorf("invalid --replicas value %d\n\nPlease either:\n- Keep at least one broker: --replicas 1\n- Stop the cluster deliberately: kubectl scale statefulset <name> --replicas 0", scaleReplicas)
+	}
+	if cmd.Flags().Changed("wait-timeout") && !scaleWait {
+		return fmt.Errorf("--wait-timeout requires --wait")
+	}
+	if scaleWaitTimeout <= 0 {
+		return fmt.Errorf("invalid --wait-timeout %s: must be positive", scaleWaitTimeout)
+	}
+
+	resolvedNamespace, fromContext, err := resolveNamespace(scaleNamespace, false)
+	if err != nil {
+		return err
+	}
+	scaleNamespace = resolvedNamespace
+	if fromContext {
+		slog.Info("Using namespace from context", "namespace", scaleNamespace)
+	}
+	var defaulted bool
+	scaleStatefulSet, defaulted = applyDefaultStatefulSet(scaleStatefulSet)
+	if defaulted {
+		slog.Info("Using default StatefulSet", "statefulset", scaleStatefulSet)
+	}
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "Kubernetes client initialization")
+	}
+
+	report, err := scalePreflight(ctx, k8sClient)
+	if err != nil {
+		return pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", scaleStatefulSet, scaleNamespace))
+	}
+
+	if globalFlags.DryRun {
+		if err := renderDryRunPlanWithChecks("scale", report.Checks, scalePlan(report)...); err != nil {
+			return err
+		}
+		return scaleVerdictError(report)
+	}
+
+	renderScaleChecks(report)
+	if err := scaleVerdictError(report); err != nil {
+		if !scaleForce {
+			return err
+		}
+		slog.Warn("Scaling despite failed preflight checks because --force is set", "statefulset", scaleStatefulSet)
+	}
+	if report.CurrentReplicas == report.TargetReplicas {
+		fmt.Printf("StatefulSet %s already runs %d replicas\n", scaleStatefulSet, report.TargetReplicas)
+		return nil
+	}
+
+	noteDetail("replicas", fmt.Sprintf("%d -> %d", report.CurrentReplicas, report.TargetReplicas))
+	start := time.Now()
+	replicas, err := applyScaleSteps(ctx, k8sClient, report)
+	recordAudit(ctx, k8sClient, scaleAuditRecord(report, replicas, err))
+	if err != nil {
+		return err
+	}
+
+	return renderScaleResult(scaleResult{ScaleReport: report, Replicas: replicas, Duration: time.Since(start)})
+}
+
+// scalePreflight checks the cluster health, capacity for a scale-up and quorum and queued
+// messages for a scale-down
+func scalePreflight(ctx context.Context, k8sClient *pkg.K8sClient) (*pkg.ScaleReport, error) {
+	sts, err := k8sClient.GetStatefulSet(ctx, scaleNamespace, scaleStatefulSet)
+	if err != nil {
+		return nil, err
+	}
+	current := int32(1)
+	if sts.Spec.Replicas != nil {
+		current = *sts.Spec.Replicas
+	}
+	report := pkg.NewScaleReport(scaleNamespace, scaleStatefulSet, current, scaleReplicas, scaleWait)
+
+	pods, err := k8sClient.GetPodsFromStatefulSet(ctx, scaleNamespace, scaleStatefulSet)
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no pods found for StatefulSet %s in namespace %s", scaleStatefulSet, scaleNamespace)
+	}
+	results, err := k8sClient.CollectConcurrentHealthChecks(ctx, pods, 0, scaleHealthOptions())
+	if results == nil {
+		return nil, err
+	}
+	report.CheckClusterHealth(results)
+
+	switch {
+	case report.TargetReplicas > report.CurrentReplicas:
+		capacity, err := k8sClient.CheckCapacity(ctx, scaleNamespace, scaleStatefulSet, report.TargetReplicas)
+		if err != nil {
+			return nil, err
+		}
+		report.Checks = append(report.Checks, capacity.Checks...)
+	case report.ScalingDown():
+		report.CheckQuorum()
+		report.Checks = append(report.Checks, backup.CheckQueuedMessages(ctx, k8sClient, removedPods(report, pods), scaleAPIOptions()))
+	}
+	return report, nil
+}
+
+// removedPods returns the running pods a scale-down terminates
+func removedPods(report *pkg.ScaleReport, pods []*v1.Pod) []*v1.Pod {
+	byName := make(map[string]*v1.Pod, len(pods))
+	for _, pod := range pods {
+		byName[pod.Name] = pod
+	}
+	var removed []*v1.Pod
+	for _, name := range report.RemovedPods() {
+		if pod, ok := byName[name]; ok {
+			removed = append(removed, pod)
+		}
+	}
+	return removed
+}
+
+// applyScaleSteps patches the replicas step by step and, with --wait, waits for the cluster to
+// rebalance after each step. It returns the replica count the StatefulSet was left at.
+func applyScaleSteps(ctx context.Context, k8sClient *pkg.K8sClient, report *pkg.ScaleReport) (int32, error) {
+	replicas := report.CurrentReplicas
+	for i, step := range report.Steps {
+		fmt.Fprintf(os.Stderr, "Scaling StatefulSet %s from %d to %d replicas\n", report.StatefulSet, replicas, step)
+		if err := k8sClient.ScaleStatefulSet(ctx, report.Namespace, report.StatefulSet, step); err != nil {
+			return replicas, err
+		}
+		replicas = step
+
+		if !scaleWait {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "Waiting for the cluster to rebalance with %d brokers (step %d of %d)\n", step, i+1, len(report.Steps))
+		stepCtx, cancel := context.WithTimeout(ctx, scaleWaitTimeout)
+		err := k8sClient.WaitForClusterReady(stepCtx, report.Namespace, report.StatefulSet, step, scaleHealthOptions(), scalePollInterval)
+		cancel()
+		if err != nil {
+			pkg.RecordInterruption(ctx, fmt.Sprintf("scale of StatefulSet %s (left at %d of %d replicas)", report.StatefulSet, replicas, report.TargetReplicas))
+			return replicas, fmt.Errorf("%w\n\nPlease either:\n- Check the brokers: kubectl broker status --statefulset %s -n %s\n- Resume once the cluster is healthy: kubectl broker scale --replicas %d --wait -n %s", err, report.StatefulSet, report.Namespace, report.TargetReplicas, report.Namespace)
+		}
+	}
+	return replicas, nil
+}
+
+// scalePlan lists the steps of a scale for --dry-run
+func scalePlan(report *pkg.ScaleReport) []string {
+	if report.CurrentReplicas == report.TargetReplicas {
+		return []string{fmt.Sprintf("Nothing: StatefulSet %s already runs %d replicas", report.StatefulSet, report.TargetReplicas)}
+	}
+	var steps []string
+	for _, step := range report.Steps {
+		steps = append(steps, fmt.Sprintf("Set the replicas of StatefulSet %s in namespace %s to %d", report.StatefulSet, report.Namespace, step))
+		if scaleWait {
+			steps = append(steps, fmt.Sprintf("Wait up to %s until %d brokers are ready and healthy", scaleWaitTimeout, step))
+		}
+	}
+	return steps
+}
+
+// scaleVerdictError reports failed preflight checks
+func scaleVerdictError(report *pkg.ScaleReport) error {
+	if report.Verdict() != pkg.CapacityNoGo {
+		return nil
+	}
+	return fmt.Errorf("preflight checks failed for scaling StatefulSet %s to %d replicas\n\nPlease either:\n- Fix the failed checks and run the scale again\n- Scale anyway with --force", report.StatefulSet, report.TargetReplicas)
+}
+
+// scaleHealthOptions checks the brokers quietly with the global API TLS settings
+func scaleHealthOptions() health.HealthCheckOptions {
+	return health.HealthCheckOptions{
+		Endpoint: "health",
+		Timeout:  10 * time.Second,
+		TLS:      apiTLSOptions(),
+	}
+}
+
+// scaleAPIOptions reaches the management API of a pod for its queued message statistics
+func scaleAPIOptions() backup.BackupOptions {
+	return backup.BackupOptions{
+		Username:       scaleUsername,
+		Password:       scalePassword,
+		Auth:           apiAuth(),
+		RequestTimeout: operationTimeout(30 * time.Second),
+		TLS:            apiTLSOptions(),
+		Retry:          apiRetryPolicy(),
+	}
+}
+
+// scaleAuditRecord records the replica change of a StatefulSet
+func scaleAuditRecord(report *pkg.ScaleReport, replicas int32, scaleErr error) audit.Record {
+	record := audit.Record{
+		Operation: audit.OperationClusterScale,
+		Namespace: report.Namespace,
+		Resources: []audit.Resource{{Kind: "StatefulSet", Namespace: report.Namespace, Name: report.StatefulSet}},
+		Outcome:   audit.OutcomeFor(0, 0, scaleErr),
+		Details: map[string]string{
+			"from":     strconv.Itoa(int(report.CurrentReplicas)),
+			"to":       strconv.Itoa(int(report.TargetReplicas)),
+			"replicas": strconv.Itoa(int(replicas)),
+		},
+	}
+	if scaleForce {
+		record.Details["force"] = "true"
+	}
+	if scaleErr != nil {
+		record.Error = scaleErr.Error()
+		record.Resources[0].Error = scaleErr.Error()
+	}
+	return record
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"kubectl-broker/pkg"
+)
+
+// renderScaleChecks prints the preflight checks before the scale starts; structured output
+// carries them in the result instead
+func renderScaleChecks(report *pkg.ScaleReport) {
+	if currentOutputFormat() != "table" {
+		return
+	}
+	fmt.Printf("Scale StatefulSet %s in namespace %s: %d -> %d replicas\n\n",
+		report.StatefulSet, report.Namespace, report.CurrentReplicas, report.TargetReplicas)
+	fmt.Println("Preflight checks")
+	renderCheckList(report.Checks)
+	fmt.Println()
+}
+
+func renderScaleResult(result scaleResult) error {
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		return writeStructuredScaleResult(result, format)
+	}
+
+	fmt.Printf("\nScaled StatefulSet %s from %d to %d replicas in %s\n",
+		result.StatefulSet, result.CurrentReplicas, result.Replicas, result.Duration.Round(time.Second))
+	if !scaleWait {
+		fmt.Fprintf(os.Stderr, "Pods start and stop in the background; follow them with: kubectl broker status --statefulset %s -n %s\n", result.StatefulSet, result.Namespace)
+	}
+	return nil
+}
+
+func writeStructuredScaleResult(result scaleResult, format string) error {
+	if format == "yaml" {
+		data, err := yaml.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to encode scale result as YAML: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode scale result as JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
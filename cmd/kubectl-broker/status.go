@@ -2,7 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -10,11 +14,15 @@ import (
 
 	"kubectl-broker/pkg"
 	"kubectl-broker/pkg/health"
+	"kubectl-broker/pkg/history"
+	"kubectl-broker/pkg/logging"
+	"kubectl-broker/pkg/units"
 )
 
 var (
 	statefulSetName string
 	podName         string
+	podNames        []string
 	namespace       string
 	port            int
 	discover        bool
@@ -22,58 +30,300 @@ var (
 	outputRaw       bool
 	detailed        bool
 	endpoint        string
+	endpointPath    string
+	recordHistory   bool
+	resourceLimit   = units.Percent(90)
+	platformName    string
+	statusSelector  string
+	junitFile       string
+	ignoreComps     []string
+	warnOnlyComps   []string
+	checkNetwork    bool
+	checkListeners  bool
+	statusVia       string
+	maxFailures     int
+	failFast        bool
+	healthSamples   int
+	healthSLO       time.Duration
+	strictHealth    bool
+
+	// statusPodRefs collects pods from arguments, --pod and --pods; ordinals are expanded once
+	// the StatefulSet is known
+	statusPodRefs []string
 )
 
 func newStatusCommand() *cobra.Command {
 	var statusCmd = &cobra.Command{
-		Use:   "status",
+		Use:   "status [pod...]",
 		Short: "Health diagnostics for HiveMQ broker clusters",
 		Long: `Status command performs health diagnostics for HiveMQ clusters running 
 on Kubernetes. It automates the process of checking the health status of 
-broker nodes via port-forwarding with intelligent defaults and concurrent checks.`,
-		RunE: runHealthCheck,
+broker nodes via port-forwarding with intelligent defaults and concurrent checks.
+
+Without pod arguments every pod of the StatefulSet is checked. Pods given as
+arguments, with --pod or with --pods limit the check to those pods; an ordinal
+such as :1 stands for that pod of the StatefulSet (broker-1 by default).
+
+Pods are checked concurrently and reported in StatefulSet order. With
+--max-failures (or --fail-fast) the remaining checks are skipped once that many
+pods failed. Without --timeout the run is bounded by 60s or longer for large
+StatefulSets.
+
+--check-listeners opens a port-forward to every TCP port the broker container
+declares (mqtt, mqtts, Control Center, API) and verifies it accepts
+connections; TLS listeners must complete a handshake with a certificate that
+has not expired. This catches a dead listener behind a healthy health API,
+e.g. after a failed certificate reload.
+
+Each pod is checked through its own port-forward (--via pod). With --via
+service a single forward goes to the ready pod the StatefulSet's API service
+routes to, and its health endpoint is queried once.
+
+--samples sends that many health requests to each pod through its port-forward
+and reports the p50, p95 and maximum request latency per pod; the port-forward
+setup is not included. --slo fails the command when the p95 latency of any pod
+exceeds the given duration.
+
+--discover caches the namespaces, StatefulSets and ports it finds per cluster
+for an hour in ~/.kubectl-broker/cache (or $KUBECTL_BROKER_CACHE_DIR). Later
+runs show the cached results instantly, complete --namespace and --statefulset
+from them and default to the only broker StatefulSet cached for the namespace.
+--refresh-discovery scans the cluster again.
+
+Examples:
+  # Check the default StatefulSet in the current namespace
+  kubectl broker status
+
+  # Check a single pod, or a subset of pods
+  kubectl broker status broker-0
+  kubectl broker status --pods broker-0,broker-2
+
+  # Check the second and third pod of a StatefulSet by ordinal
+  kubectl broker status --statefulset hivemq :1 :2
+
+  # Publish broker health as test results in a CI pipeline
+  kubectl broker status -n production --junit-file broker-health.xml
+
+  # Find brokers again after installing a new tenant
+  kubectl broker status --discover --refresh-discovery
+
+  # Check a tenant whose StatefulSet does not use the default name
+  kubectl broker status -n tenants --selector app.kubernetes.io/instance=tenant-a
+
+  # Ignore a metering extension and let cluster problems only degrade the result
+  kubectl broker status --ignore-component extensions.hivemq-cloud-metering-extension --warn-only-component cluster
+
+  # Also verify the headless service, DNS records and peer reachability
+  kubectl broker status -n production --check-network
+
+  # Also verify every MQTT, TLS and Control Center listener accepts connections
+  kubectl broker status -n production --check-listeners
+
+  # Quick check: one forward through the API service, one health query
+  kubectl broker status -n production --via service
+
+  # Stop checking a large cluster after the first three failed pods
+  kubectl broker status -n production --max-failures 3
+
+  # Fail when the p95 health latency of any pod over 10 requests exceeds 250ms
+  kubectl broker status -n production --samples 10 --slo 250ms`,
+		Args:              cobra.ArbitraryArgs,
+		ValidArgsFunction: completePods,
+		RunE:              runHealthCheck,
+		Annotations:       map[string]string{csvOutputAnnotation: "true"},
 	}
 
 	// Add flags
 	statusCmd.Flags().StringVar(&statefulSetName, "statefulset", "", "Name of the StatefulSet to check (defaults to 'broker')")
-	statusCmd.Flags().StringVar(&podName, "pod", "", "Name of the pod to check (for single pod mode)")
+	statusCmd.Flags().StringVar(&platformName, "platform", "", "Name of the HiveMQPlatform resource to check (HiveMQ Platform Operator)")
+	statusCmd.Flags().StringVarP(&statusSelector, "selector", "l", "", "Label selector of the StatefulSet to check, e.g. app.kubernetes.io/instance=tenant-a")
+	statusCmd.Flags().StringVar(&podName, "pod", "", "Name or StatefulSet ordinal (e.g. :1) of a pod to check instead of the whole StatefulSet")
+	statusCmd.Flags().StringSliceVar(&podNames, "pods", nil, "Names or StatefulSet ordinals of the pods to check (comma-separated, e.g. broker-0,broker-2 or :0,:2)")
 	statusCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (defaults to current kubectl context)")
 	statusCmd.Flags().IntVarP(&port, "port", "p", 0, "Port number to use for health check (overrides auto-discovery)")
 	statusCmd.Flags().BoolVar(&discover, "discover", false, "Discover available broker pods and namespaces")
+	statusCmd.Flags().BoolVar(&refreshDiscovery, "refresh-discovery", false, "Scan the cluster again instead of reusing the cached results of a previous --discover")
 
 	// Health output format flags
 	statusCmd.Flags().BoolVar(&outputJSON, "json", false, "Output raw JSON response for external parsing")
 	statusCmd.Flags().BoolVar(&outputRaw, "raw", false, "Output unprocessed health response")
 	statusCmd.Flags().BoolVar(&detailed, "detailed", false, "Show detailed component breakdown")
-	statusCmd.Flags().StringVar(&endpoint, "endpoint", "health", "Health endpoint to query (health, liveness, readiness)")
+	statusCmd.Flags().StringVar(&endpoint, "endpoint", pkg.ProfileFor(pkg.ProductBroker).DefaultEndpoint(), "Health endpoint to query (health, liveness, readiness)")
+	statusCmd.Flags().StringVar(&endpointPath, "endpoint-path", "", "Exact HTTP path of the health endpoint, e.g. /custom/health (overrides --endpoint and the path from config.xml)")
+	statusCmd.Flags().Var(&resourceLimit, "resource-threshold", "Flag pods whose CPU or memory utilization reaches this percent of limits/requests (with --detailed)")
+	statusCmd.Flags().BoolVar(&recordHistory, "record", false, "Record per-pod results in the local health history (see 'status history')")
+	statusCmd.Flags().StringVar(&junitFile, "junit-file", "", "Also write per-pod results as a JUnit XML report to this file (for CI test publishing)")
+	statusCmd.Flags().StringSliceVar(&ignoreComps, "ignore-component", nil, "Health components that never affect the overall status (e.g. extensions.hivemq-cloud-metering-extension; globs allowed)")
+	statusCmd.Flags().StringSliceVar(&warnOnlyComps, "warn-only-component", nil, "Health components that can at most degrade the overall status (e.g. cluster; globs allowed)")
+	statusCmd.Flags().StringVar(&statusVia, "via", viaPod, "Route of the health check: pod (one forward per pod) or service (one forward through the API service)")
+	statusCmd.Flags().IntVar(&maxFailures, "max-failures", 0, "Skip the remaining pods once this many health checks have failed (0 checks every pod)")
+	statusCmd.Flags().BoolVar(&failFast, "fail-fast", false, "Skip the remaining pods after the first failed health check (same as --max-failures 1)")
+	statusCmd.Flags().BoolVar(&checkNetwork, "check-network", false, "Also verify cluster discovery prerequisites: headless service, DNS records from inside a pod and peer reachability on the cluster port")
+	statusCmd.Flags().BoolVar(&checkListeners, "check-listeners", false, "Also verify that every listener port of each pod accepts connections (TLS listeners must complete a handshake)")
+	statusCmd.Flags().IntVar(&healthSamples, "samples", 1, "Health requests per pod; with more than one, p50/p95/max request latency is reported")
+	statusCmd.Flags().BoolVar(&strictHealth, "strict", false, "Fail the check of a pod whose health response contains an unrecognized status instead of counting the component as unhealthy")
+	statusCmd.Flags().DurationVar(&healthSLO, "slo", 0, "Fail when the p95 health request latency of a pod exceeds this duration (e.g. 250ms)")
+
+	statusCmd.AddCommand(newStatusHistoryCommand())
 
 	// Apply intelligent defaults and validate flags
 	statusCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
 		if err := mutuallyExclusive(outputJSON, "--json", outputRaw, "--raw"); err != nil {
 			return err
 		}
+		if err := mutuallyExclusive(junitFile != "", "--junit-file", outputRaw, "--raw"); err != nil {
+			return err
+		}
+		if err := mutuallyExclusive(endpointPath != "", "--endpoint-path", cmd.Flags().Changed("endpoint"), "--endpoint"); err != nil {
+			return err
+		}
+		for _, check := range []struct {
+			set  bool
+			name string
+		}{
+			{checkNetwork, "--check-network"},
+			{checkListeners, "--check-listeners"},
+		} {
+			if !check.set {
+				continue
+			}
+			for _, conflict := range []struct {
+				set  bool
+				name string
+			}{
+				{discover, "--discover"},
+				{outputJSON, "--json"},
+				{outputRaw, "--raw"},
+				{len(args) > 0 || podName != "" || len(podNames) > 0, "pod selection"},
+			} {
+				if err := mutuallyExclusive(true, check.name, conflict.set, conflict.name); err != nil {
+					return err
+				}
+			}
+		}
+		if quietOutput() {
+			for _, conflict := range []struct {
+				set  bool
+				name string
+			}{
+				{outputJSON, "--json"},
+				{outputRaw, "--raw"},
+				{detailed, "--detailed"},
+				{discover, "--discover"},
+				{checkNetwork, "--check-network"},
+				{checkListeners, "--check-listeners"},
+			} {
+				if err := mutuallyExclusive(true, "--quiet", conflict.set, conflict.name); err != nil {
+					return err
+				}
+			}
+		}
+		if currentOutputFormat() == "csv" {
+			for _, conflict := range []struct {
+				set  bool
+				name string
+			}{
+				{outputJSON, "--json"},
+				{outputRaw, "--raw"},
+				{detailed, "--detailed"},
+				{discover, "--discover"},
+				{checkNetwork, "--check-network"},
+				{checkListeners, "--check-listeners"},
+			} {
+				if err := mutuallyExclusive(true, "--output csv", conflict.set, conflict.name); err != nil {
+					return err
+				}
+			}
+		}
+		if err := mutuallyExclusive(failFast, "--fail-fast", cmd.Flags().Changed("max-failures"), "--max-failures"); err != nil {
+			return err
+		}
+		if healthSamples < 1 {
+			return fmt.Errorf("invalid --samples %d\n\nPlease either:\n- Measure latency over several requests: --samples 10\n- Omit the flag to send a single request", healthSamples)
+		}
+		if healthSLO < 0 {
+			return fmt.Errorf("invalid --slo %s\n\nPlease either:\n- Use a positive duration: --slo 250ms\n- Omit the flag to report latency without an objective", healthSLO)
+		}
+		if err := mutuallyExclusive(latencyRequested(), "--samples/--slo", outputRaw, "--raw"); err != nil {
+			return err
+		}
+		if err := mutuallyExclusive(latencyRequested(), "--samples/--slo", discover, "--discover"); err != nil {
+			return err
+		}
+		if maxFailures < 0 {
+			return fmt.Errorf("invalid --max-failures %d\n\nPlease either:\n- Stop after some failures: --max-failures 3\n- Omit the flag to check every pod", maxFailures)
+		}
+		if err := validateVia(statusVia, "check every pod directly"); err != nil {
+			return err
+		}
+		if statusVia == viaService {
+			for _, conflict := range []struct {
+				set  bool
+				name string
+			}{
+				{discover, "--discover"},
+				{len(args) > 0 || podName != "" || len(podNames) > 0, "pod selection"},
+			} {
+				if err := mutuallyExclusive(true, "--via service", conflict.set, conflict.name); err != nil {
+					return err
+				}
+			}
+		}
+		if endpointPath != "" && !strings.HasPrefix(endpointPath, "/") {
+			return fmt.Errorf("invalid --endpoint-path %q\n\nPlease either:\n- Use an absolute path: --endpoint-path /custom/health\n- Omit --endpoint-path to use the discovered health path", endpointPath)
+		}
+		if err := componentPolicy().Validate(); err != nil {
+			return fmt.Errorf("%w\n\nPlease either:\n- Use a component name: --ignore-component extensions.hivemq-cloud-metering-extension\n- Use a valid glob: --warn-only-component 'extensions.*'", err)
+		}
+		if resourceLimit <= 0 {
+			return fmt.Errorf("--resource-threshold must be above zero, got %.0f\n\nPlease either:\n- Use a percentage between 1 and 100: --resource-threshold 90\n- Omit the flag to use the default of 90", resourceLimit)
+		}
 
 		if !discover {
-			// Apply intelligent defaults
-			if statefulSetName == "" && podName == "" {
-				var usedDefault bool
-				statefulSetName, usedDefault = applyDefaultStatefulSet(statefulSetName)
-				if usedDefault && !outputJSON && !outputRaw && detailed {
-					fmt.Printf("Using default StatefulSet: %s\n", statefulSetName)
+			statusPodRefs = collectPodRefs(args, podName, podNames)
+
+			if platformName != "" {
+				if err := mutuallyExclusive(true, "--platform", statefulSetName != "", "--statefulset"); err != nil {
+					return err
 				}
 			}
 
-			if err := mutuallyExclusive(statefulSetName != "", "--statefulset", podName != "", "--pod"); err != nil {
-				return err
+			if statusSelector != "" {
+				for _, conflict := range []struct {
+					set  bool
+					name string
+				}{
+					{statefulSetName != "", "--statefulset"},
+					{platformName != "", "--platform"},
+				} {
+					if err := mutuallyExclusive(true, "--selector", conflict.set, conflict.name); err != nil {
+						return err
+					}
+				}
+				if err := pkg.ValidateLabelSelector(statusSelector); err != nil {
+					return err
+				}
 			}
 
+			// Apply intelligent defaults; pods given by name need no StatefulSet, ordinals do
+			needsStatefulSet := len(statusPodRefs) == 0
+			for _, ref := range statusPodRefs {
+				if _, ok := podOrdinal(ref); ok {
+					needsStatefulSet = true
+				}
+			}
 			resolvedNamespace, fromContext, err := resolveNamespace(namespace, false)
 			if err != nil {
 				return err
 			}
 			namespace = resolvedNamespace
-			if fromContext && !outputJSON && !outputRaw && detailed {
-				fmt.Printf("Using namespace from context: %s\n", namespace)
+			if fromContext {
+				slog.Log(cmd.Context(), logging.DetailLevel(shouldShowDebugInfo()), "Using namespace from context", "namespace", namespace)
+			}
+
+			if needsStatefulSet && statefulSetName == "" && platformName == "" && statusSelector == "" {
+				statefulSetName = defaultStatefulSetFor(namespace)
+				slog.Log(cmd.Context(), logging.DetailLevel(shouldShowDebugInfo()), "Using default StatefulSet", "statefulset", statefulSetName)
 			}
 		}
 		return nil
@@ -82,27 +332,175 @@ broker nodes via port-forwarding with intelligent defaults and concurrent checks
 	return statusCmd
 }
 
-func runHealthCheck(_ *cobra.Command, _ []string) error {
-	ctx := context.Background()
+func runHealthCheck(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
 
 	// 1. Initialize Kubernetes client
-	k8sClient, err := pkg.NewK8sClient(detailed && !outputJSON && !outputRaw)
+	k8sClient, err := newK8sClient(detailed && !outputJSON && !outputRaw)
 	if err != nil {
 		return pkg.EnhanceError(err, "Kubernetes client initialization")
 	}
 
 	// Handle discovery mode
 	if discover {
-		return k8sClient.DiscoverBrokers(ctx)
+		return runBrokerDiscovery(ctx, k8sClient)
 	}
 
-	// Handle StatefulSet mode (Phase 2)
-	if statefulSetName != "" {
-		return runStatefulSetHealthCheck(ctx, k8sClient)
+	// Resolve the StatefulSet by label for installs with custom names
+	if statusSelector != "" {
+		statefulSetName, err = statefulSetFromSelector(ctx, namespace, statusSelector)
+		if err != nil {
+			return err
+		}
+		slog.Log(ctx, logging.DetailLevel(shouldShowDebugInfo()), "Using StatefulSet from selector", "statefulset", statefulSetName, "selector", statusSelector)
 	}
 
-	// Handle single pod mode (Phase 1)
-	return runSinglePodHealthCheck(ctx, k8sClient)
+	// Handle pod mode: a single pod or a subset of pods
+	if len(statusPodRefs) > 0 {
+		if platformName != "" {
+			platform, err := k8sClient.GetPlatform(ctx, namespace, platformName)
+			if err != nil {
+				return err
+			}
+			statefulSetName = platform.StatefulSet
+		}
+
+		pods := expandPodRefs(statusPodRefs, statefulSetName)
+		if len(pods) == 1 {
+			podName = pods[0]
+			return runSinglePodHealthCheck(ctx, k8sClient)
+		}
+		return runPodSubsetHealthCheck(ctx, k8sClient, pods)
+	}
+
+	// Handle service mode: one forward through the API service
+	if statusVia == viaService {
+		if err := runServiceHealthCheck(ctx, k8sClient); err != nil {
+			return err
+		}
+		return runAdditionalChecks(ctx, k8sClient)
+	}
+
+	// Handle HiveMQ Platform Operator mode
+	if platformName != "" {
+		if err := runPlatformHealthCheck(ctx, k8sClient); err != nil {
+			return err
+		}
+		return runAdditionalChecks(ctx, k8sClient)
+	}
+
+	// Handle StatefulSet mode
+	if err := runStatefulSetHealthCheck(ctx, k8sClient); err != nil {
+		return err
+	}
+	return runAdditionalChecks(ctx, k8sClient)
+}
+
+// runAdditionalChecks runs --check-network and --check-listeners; both run even when one fails
+func runAdditionalChecks(ctx context.Context, k8sClient *pkg.K8sClient) error {
+	return errors.Join(runNetworkCheck(ctx, k8sClient), runListenerCheck(ctx, k8sClient))
+}
+
+// runNetworkCheck verifies the cluster discovery prerequisites when --check-network is set
+func runNetworkCheck(ctx context.Context, k8sClient *pkg.K8sClient) error {
+	if !checkNetwork {
+		return nil
+	}
+
+	report, err := k8sClient.CheckClusterNetwork(ctx, namespace, statefulSetName)
+	if err != nil {
+		return pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", statefulSetName, namespace))
+	}
+	displayNetworkReport(report)
+
+	if failed := report.Failed(); failed > 0 {
+		return fmt.Errorf("%d network checks failed for StatefulSet %s\n\nPlease either:\n- Fix the headless service named in spec.serviceName (clusterIP: None)\n- Check NetworkPolicies allowing port %d between broker pods", failed, statefulSetName, report.ClusterPort)
+	}
+	return nil
+}
+
+// runListenerCheck verifies every advertised listener of each pod when --check-listeners is set
+func runListenerCheck(ctx context.Context, k8sClient *pkg.K8sClient) error {
+	if !checkListeners {
+		return nil
+	}
+
+	report, err := k8sClient.CheckListeners(ctx, namespace, statefulSetName, pkg.DefaultListenerProbeTimeout)
+	if err != nil {
+		return pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", statefulSetName, namespace))
+	}
+	displayListenerReport(report)
+
+	if failed := report.Failed(); failed > 0 {
+		return fmt.Errorf("%d listener checks failed for StatefulSet %s\n\nPlease either:\n- Check the broker log for listener or certificate errors: kubectl logs -n %s <pod>\n- Verify the TLS keystore mounted into the pod and restart it after replacing certificates", failed, statefulSetName, namespace)
+	}
+	return nil
+}
+
+// collectPodRefs merges pod arguments, --pod and --pods in order, dropping blanks and repeats
+func collectPodRefs(args []string, pod string, pods []string) []string {
+	seen := make(map[string]bool)
+	var refs []string
+	for _, ref := range append(append(append([]string{}, args...), pod), pods...) {
+		ref = strings.TrimSpace(ref)
+		if ref != "" && !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// podOrdinal recognizes the ordinal shorthand :N for the StatefulSet's pod N. Pod names cannot
+// contain a colon, and unlike -N the shorthand is not parsed as a flag when given as an argument.
+func podOrdinal(ref string) (int, bool) {
+	digits, ok := strings.CutPrefix(ref, ":")
+	if !ok {
+		return 0, false
+	}
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return 0, false
+	}
+	ordinal, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0, false
+	}
+	return ordinal, true
+}
+
+// expandPodRefs turns ordinals into pod names of statefulSet and drops resulting duplicates
+func expandPodRefs(refs []string, statefulSet string) []string {
+	seen := make(map[string]bool)
+	var pods []string
+	for _, ref := range refs {
+		if ordinal, ok := podOrdinal(ref); ok {
+			ref = fmt.Sprintf("%s-%d", statefulSet, ordinal)
+		}
+		if !seen[ref] {
+			seen[ref] = true
+			pods = append(pods, ref)
+		}
+	}
+	return pods
+}
+
+// runPlatformHealthCheck resolves the StatefulSet from the HiveMQPlatform resource, checks its
+// pods and reports the operator's status conditions alongside the health results
+func runPlatformHealthCheck(ctx context.Context, k8sClient *pkg.K8sClient) error {
+	platform, err := k8sClient.GetPlatform(ctx, namespace, platformName)
+	if err != nil {
+		return err
+	}
+	statefulSetName = platform.StatefulSet
+
+	if err := runStatefulSetHealthCheck(ctx, k8sClient); err != nil {
+		return err
+	}
+
+	if !outputJSON && !outputRaw && !quietOutput() && currentOutputFormat() != "csv" {
+		displayPlatformConditions(platform)
+	}
+	return nil
 }
 
 func runStatefulSetHealthCheck(ctx context.Context, k8sClient *pkg.K8sClient) error {
@@ -124,6 +522,59 @@ func runStatefulSetHealthCheck(ctx context.Context, k8sClient *pkg.K8sClient) er
 		fmt.Printf("Found %d pods in StatefulSet\n\n", len(pods))
 	}
 
+	return checkPodsConcurrently(ctx, k8sClient, pods, statefulSetName)
+}
+
+// runServiceHealthCheck checks the pod the StatefulSet's API service routes to, with a single
+// port-forward, instead of every pod
+func runServiceHealthCheck(ctx context.Context, k8sClient *pkg.K8sClient) error {
+	if platformName != "" {
+		platform, err := k8sClient.GetPlatform(ctx, namespace, platformName)
+		if err != nil {
+			return err
+		}
+		statefulSetName = platform.StatefulSet
+	}
+
+	service, err := k8sClient.GetAPIServiceFromStatefulSet(ctx, namespace, statefulSetName)
+	if err != nil {
+		return pkg.EnhanceError(err, fmt.Sprintf("StatefulSet %s in namespace %s", statefulSetName, namespace))
+	}
+	pod, err := pkg.ReadyPodForService(ctx, k8sClient, service)
+	if err != nil {
+		return fmt.Errorf("%w\n\nPlease either:\n- Check every pod directly: --via pod\n- Check the service endpoints: kubectl get endpointslices -n %s -l kubernetes.io/service-name=%s", err, namespace, service.Name)
+	}
+	slog.Log(ctx, logging.DetailLevel(shouldShowDebugInfo()), "Checking health through service", "service", service.Name, "pod", pod.Name)
+
+	podName = pod.Name
+	return runSinglePodHealthCheck(ctx, k8sClient)
+}
+
+// runPodSubsetHealthCheck checks the named pods concurrently, like the pods of a StatefulSet
+func runPodSubsetHealthCheck(ctx context.Context, k8sClient *pkg.K8sClient, podNames []string) error {
+	pods := make([]*v1.Pod, 0, len(podNames))
+	for _, name := range podNames {
+		pod, err := k8sClient.GetPod(ctx, namespace, name)
+		if err != nil {
+			return pkg.EnhanceError(err, fmt.Sprintf("pod %s in namespace %s", name, namespace))
+		}
+		pods = append(pods, pod)
+	}
+
+	if !outputJSON && !outputRaw && detailed {
+		fmt.Printf("Checking health of %d pods in namespace %s\n\n", len(pods), namespace)
+	}
+
+	suite := statefulSetName
+	if suite == "" {
+		suite = namespace
+	}
+	return checkPodsConcurrently(ctx, k8sClient, pods, suite)
+}
+
+// checkPodsConcurrently runs and reports the health checks of several pods; suite names the
+// group in JUnit reports and the health history
+func checkPodsConcurrently(ctx context.Context, k8sClient *pkg.K8sClient, pods []*v1.Pod, suite string) error {
 	// Create health options
 	options := health.HealthCheckOptions{
 		Endpoint:   endpoint,
@@ -132,10 +583,50 @@ func runStatefulSetHealthCheck(ctx context.Context, k8sClient *pkg.K8sClient) er
 		Detailed:   detailed,
 		Timeout:    10 * time.Second,
 		UseColors:  !outputJSON && !outputRaw, // Disable colors for JSON/raw output
+		TLS:        apiTLSOptions(),
+		Policy:     componentPolicy(),
+		Path:       endpointPath,
+	}
+	options.MaxFailures = maxFailures
+	if failFast {
+		options.MaxFailures = 1
+	}
+	options.Samples = healthSamples
+	options.Strict = strictHealth
+
+	// Perform concurrent health checks; an aborted run still reports its partial results
+	startTime := time.Now()
+	results, checkErr := k8sClient.CollectConcurrentHealthChecks(ctx, pods, int32(port), options)
+	if results == nil {
+		return checkErr
 	}
 
-	// Perform concurrent health checks
-	return k8sClient.PerformConcurrentHealthChecks(ctx, pods, int32(port), options)
+	if junitFile != "" {
+		if err := writeJUnitReport(junitFile, suite, results, startTime); err != nil {
+			return err
+		}
+	}
+
+	if recordHistory {
+		recordHealthRun(statefulSetName, historyRecordsFromResults(results))
+	}
+
+	if quietOutput() {
+		printResult(string(clusterHealthStatus(results, options.Policy)))
+	} else if currentOutputFormat() == "csv" {
+		if err := writeHealthResultsCSV(results); err != nil {
+			return err
+		}
+	} else if err := k8sClient.DisplayHealthCheckResults(results, options); err != nil {
+		return err
+	}
+	showLatencyReport(results)
+	if checkErr != nil {
+		return checkErr
+	}
+
+	showResourceUsage(ctx, k8sClient, pods)
+	return checkLatencySLO(results)
 }
 
 func runSinglePodHealthCheck(ctx context.Context, k8sClient *pkg.K8sClient) error {
@@ -146,32 +637,98 @@ func runSinglePodHealthCheck(ctx context.Context, k8sClient *pkg.K8sClient) erro
 	}
 
 	// Discover or use specified health port
-	healthPort, err := resolveHealthPort(k8sClient, pod)
+	healthEndpoint, err := resolveHealthEndpoint(ctx, k8sClient, pod)
 	if err != nil {
 		return err
 	}
+	healthPort := healthEndpoint.Port
 
 	// Get local port and create options
 	localPort, options, err := prepareHealthCheckOptions()
 	if err != nil {
 		return err
 	}
+	options = healthEndpoint.Apply(options)
 
 	// Perform the health check
-	parsedHealth, rawJSON, err := performHealthCheck(ctx, k8sClient, pod, healthPort, localPort, options)
+	startTime := time.Now()
+	parsedHealth, rawJSON, latency, err := performHealthCheck(ctx, k8sClient, pod, healthPort, localPort, options)
+	if recordHistory {
+		recordHealthRun("", []history.PodRecord{historyRecordForPod(pod.Name, parsedHealth, options.Policy, time.Since(startTime), err)})
+	}
+	if junitFile != "" {
+		result := singlePodResult(pod.Name, parsedHealth, options.Policy, time.Since(startTime), err)
+		if reportErr := writeJUnitReport(junitFile, pod.Name, []pkg.HealthCheckResult{result}, startTime); reportErr != nil {
+			return reportErr
+		}
+	}
 	if err != nil {
 		return err
 	}
 
 	// Display results
-	return displayHealthCheckResults(pod, parsedHealth, rawJSON, options)
+	results := []pkg.HealthCheckResult{{PodName: pod.Name, Latency: latency}}
+	if currentOutputFormat() == "csv" {
+		result := singlePodResult(pod.Name, parsedHealth, options.Policy, time.Since(startTime), nil)
+		result.Latency = latency
+		if err := writeHealthResultsCSV([]pkg.HealthCheckResult{result}); err != nil {
+			return err
+		}
+	} else if err := displayHealthCheckResults(pod, parsedHealth, rawJSON, options); err != nil {
+		return err
+	}
+	showLatencyReport(results)
+
+	showResourceUsage(ctx, k8sClient, []*v1.Pod{pod})
+	return checkLatencySLO(results)
+}
+
+// latencyRequested reports whether --samples or --slo ask for a latency report
+func latencyRequested() bool {
+	return healthSamples > 1 || healthSLO > 0
+}
+
+// showLatencyReport adds the request latency per pod to table output when it was requested
+func showLatencyReport(results []pkg.HealthCheckResult) {
+	if !latencyRequested() || outputJSON || outputRaw || quietOutput() || currentOutputFormat() == "csv" {
+		return
+	}
+	displayLatencyReport(results, healthSLO)
+}
+
+// checkLatencySLO fails the command when the p95 latency of a pod exceeds --slo
+func checkLatencySLO(results []pkg.HealthCheckResult) error {
+	var exceeded []string
+	for _, result := range results {
+		if result.Latency.ExceedsSLO(healthSLO) {
+			exceeded = append(exceeded, fmt.Sprintf("%s (%s)", result.PodName, result.Latency.P95.Round(time.Millisecond)))
+		}
+	}
+	if len(exceeded) == 0 {
+		return nil
+	}
+	return fmt.Errorf("p95 health latency exceeds the SLO of %s: %s\n\nPlease either:\n- Check broker load and resource usage: kubectl broker status --detailed\n- Compare with the recorded history: kubectl broker status history", healthSLO, strings.Join(exceeded, ", "))
+}
+
+// showResourceUsage adds CPU/memory utilization from metrics.k8s.io to detailed output.
+// Missing metrics never fail the health check.
+func showResourceUsage(ctx context.Context, k8sClient *pkg.K8sClient, pods []*v1.Pod) {
+	if !detailed || outputJSON || outputRaw || len(pods) == 0 {
+		return
+	}
+
+	usage, err := k8sClient.GetPodResourceUsage(ctx, namespace, pods)
+	if err != nil {
+		fmt.Printf("\nResource usage unavailable: %v\n", err)
+		return
+	}
+
+	displayResourceUsage(pods, usage, float64(resourceLimit))
 }
 
 // getPodAndValidate retrieves and validates a pod for health checking
 func getPodAndValidate(ctx context.Context, k8sClient *pkg.K8sClient) (*v1.Pod, error) {
-	if shouldShowDebugInfo() {
-		fmt.Printf("Checking health of pod %s in namespace %s\n", podName, namespace)
-	}
+	slog.Log(ctx, logging.DetailLevel(shouldShowDebugInfo()), "Checking health of pod", "pod", podName, "namespace", namespace)
 
 	pod, err := k8sClient.GetPod(ctx, namespace, podName)
 	if err != nil {
@@ -179,33 +736,25 @@ func getPodAndValidate(ctx context.Context, k8sClient *pkg.K8sClient) (*v1.Pod,
 	}
 
 	if err := pkg.ValidatePodStatus(pod); err != nil {
-		return nil, err
+		return nil, withPodDiagnostics(ctx, k8sClient, pod, err)
 	}
 
 	return pod, nil
 }
 
-// resolveHealthPort determines the health port to use for the health check
-func resolveHealthPort(k8sClient *pkg.K8sClient, pod *v1.Pod) (int32, error) {
-	var healthPort int32
-	var err error
-
+// resolveHealthEndpoint determines the health port, and a custom health path if the broker config sets one
+func resolveHealthEndpoint(ctx context.Context, k8sClient *pkg.K8sClient, pod *v1.Pod) (*pkg.HealthEndpoint, error) {
 	if port > 0 {
-		healthPort = int32(port)
-		if shouldShowDebugInfo() {
-			fmt.Printf("Using specified port: %d\n", healthPort)
-		}
-	} else {
-		healthPort, err = k8sClient.DiscoverHealthPort(pod)
-		if err != nil {
-			return 0, err
-		}
-		if shouldShowDebugInfo() {
-			fmt.Printf("Discovered health port: %d\n", healthPort)
-		}
+		slog.Log(ctx, logging.DetailLevel(shouldShowDebugInfo()), "Using specified port", "port", port)
+		return &pkg.HealthEndpoint{Port: int32(port)}, nil
 	}
 
-	return healthPort, nil
+	healthEndpoint, err := k8sClient.DiscoverHealthEndpoint(ctx, pod)
+	if err != nil {
+		return nil, err
+	}
+	slog.Log(ctx, logging.DetailLevel(shouldShowDebugInfo()), "Discovered health port", "port", healthEndpoint.Port, "source", healthEndpoint.Source, "path", healthEndpoint.BasePath)
+	return healthEndpoint, nil
 }
 
 // prepareHealthCheckOptions creates local port and health check options
@@ -222,17 +771,36 @@ func prepareHealthCheckOptions() (int, health.HealthCheckOptions, error) {
 		Detailed:   detailed,
 		Timeout:    10 * time.Second,
 		UseColors:  !outputJSON && !outputRaw,
+		TLS:        apiTLSOptions(),
+		Policy:     componentPolicy(),
+		Path:       endpointPath,
+		Samples:    healthSamples,
+		Strict:     strictHealth,
 	}
 
 	return localPort, options, nil
 }
 
+// componentPolicy builds the component policy from --ignore-component and --warn-only-component
+func componentPolicy() health.ComponentPolicy {
+	return health.ComponentPolicy{Ignore: ignoreComps, WarnOnly: warnOnlyComps}
+}
+
 // performHealthCheck executes the health check using port forwarding
-func performHealthCheck(ctx context.Context, k8sClient *pkg.K8sClient, pod *v1.Pod, healthPort int32, localPort int, options health.HealthCheckOptions) (*health.ParsedHealthData, []byte, error) {
+func performHealthCheck(ctx context.Context, k8sClient *pkg.K8sClient, pod *v1.Pod, healthPort int32, localPort int, options health.HealthCheckOptions) (*health.ParsedHealthData, []byte, pkg.LatencyStats, error) {
 	pf := pkg.NewPortForwarder(k8sClient.GetConfig(), k8sClient.GetRESTClient())
-	parsedHealth, rawJSON, err := pf.PerformHealthCheckWithOptions(ctx, pod, healthPort, localPort, options)
+	parsedHealth, rawJSON, latencies, err := pf.PerformSampledHealthCheck(ctx, pod, healthPort, localPort, options)
 	if err != nil {
-		return nil, nil, pkg.EnhanceError(err, "health check")
+		return nil, nil, pkg.LatencyStats{}, withPodDiagnostics(ctx, k8sClient, pod, pkg.EnhanceError(err, "health check"))
+	}
+	return parsedHealth, rawJSON, pkg.NewLatencyStats(latencies), nil
+}
+
+// withPodDiagnostics appends container states or the latest warning event to a failed check,
+// so the error explains why the pod is unhealthy instead of only how the check failed
+func withPodDiagnostics(ctx context.Context, k8sClient *pkg.K8sClient, pod *v1.Pod, err error) error {
+	if summary := k8sClient.DiagnosePod(context.WithoutCancel(ctx), pod).Summary(); summary != "" {
+		return fmt.Errorf("%w\n\nPod diagnostics: %s", err, summary)
 	}
-	return parsedHealth, rawJSON, nil
+	return err
 }
@@ -2,14 +2,42 @@ package main
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/fatih/color"
 	v1 "k8s.io/api/core/v1"
 
+	"kubectl-broker/pkg"
 	"kubectl-broker/pkg/health"
 )
 
+var resourceUsageColumns = []tableColumn{
+	{Title: "POD", Width: 24},
+	{Title: "CPU", Width: 8},
+	{Title: "CPU REQ/LIM", Width: 14},
+	{Title: "CPU %", Width: 6},
+	{Title: "MEMORY", Width: 10},
+	{Title: "MEM REQ/LIM", Width: 20},
+	{Title: "MEM %", Width: 6},
+}
+
+var latencyColumns = []tableColumn{
+	{Title: "POD", Width: 24},
+	{Title: "SAMPLES", Width: 8},
+	{Title: "P50", Width: 10},
+	{Title: "P95", Width: 10},
+	{Title: "MAX", Width: 10},
+	{Title: "SLO", Width: 10},
+}
+
 // displayHealthCheckResults formats and displays the health check results
 func displayHealthCheckResults(pod *v1.Pod, parsedHealth *health.ParsedHealthData, rawJSON []byte, options health.HealthCheckOptions) error {
+	if quietOutput() {
+		printResult(string(options.Policy.OverallStatus(parsedHealth)))
+		return nil
+	}
+
 	if options.OutputRaw {
 		fmt.Print(string(rawJSON))
 		return nil
@@ -27,38 +55,78 @@ func displayHealthCheckResults(pod *v1.Pod, parsedHealth *health.ParsedHealthDat
 	return displayStandardHealthResults(parsedHealth, options)
 }
 
+// clusterHealthStatus combines the results of several pods into the single status printed with
+// --quiet; pods whose check failed count as DOWN
+func clusterHealthStatus(results []pkg.HealthCheckResult, policy health.ComponentPolicy) health.HealthStatus {
+	overall := health.StatusUP
+	for _, result := range results {
+		status := health.StatusDOWN
+		if result.ParsedHealth != nil {
+			status = policy.OverallStatus(result.ParsedHealth)
+		}
+		overall = health.WorseStatus(overall, status)
+	}
+	return overall
+}
+
 // displayDetailedHealthResults shows detailed component breakdown
 func displayDetailedHealthResults(pod *v1.Pod, parsedHealth *health.ParsedHealthData, options health.HealthCheckOptions) error {
 	fmt.Printf("Pod: %s\n", pod.Name)
 	fmt.Printf("Overall Health: %s\n", health.FormatHealthStatusWithColor(parsedHealth.OverallStatus, options.UseColors))
+	if overall := options.Policy.OverallStatus(parsedHealth); overall != parsedHealth.OverallStatus {
+		fmt.Printf("Overall Health (policy): %s\n", health.FormatHealthStatusWithColor(overall, options.UseColors))
+	}
 
 	if len(parsedHealth.ComponentDetails) > 0 {
 		fmt.Println("Components:")
 		for _, comp := range parsedHealth.ComponentDetails {
-			displayComponentDetails(comp, options.UseColors)
+			displayComponentDetails(comp, options.Policy, options.UseColors)
 		}
 	}
+	if len(parsedHealth.Unrecognized) > 0 {
+		fmt.Printf("Warning: unrecognized statuses counted as unhealthy: %s\n", strings.Join(parsedHealth.Unrecognized, ", "))
+	}
 
 	return nil
 }
 
 // displayComponentDetails shows details for a single component
-func displayComponentDetails(comp health.ComponentStatus, useColors bool) {
+func displayComponentDetails(comp health.ComponentStatus, policy health.ComponentPolicy, useColors bool) {
 	fmt.Printf("  - %s: %s", comp.Name, health.FormatHealthStatusWithColor(comp.Status, useColors))
 
 	if comp.Details != "" {
 		fmt.Printf(" (%s)", comp.Details)
 	}
+	if label := policy.Label(comp.Name); label != "" {
+		fmt.Printf(" [%s]", label)
+	}
 
 	if comp.Name == "extensions" && len(comp.SubComponents) > 0 {
-		displayExtensionDetails(comp.SubComponents, useColors)
+		displayExtensionDetails(comp.SubComponents, policy, useColors)
 	} else {
 		fmt.Println()
+		displayNestedComponentDetails(comp.SubComponents, comp.Name, 2, policy, useColors)
+	}
+}
+
+// displayNestedComponentDetails shows the component trees newer broker versions nest below components
+func displayNestedComponentDetails(components []health.ComponentStatus, parent string, depth int, policy health.ComponentPolicy, useColors bool) {
+	indent := strings.Repeat("  ", depth)
+	for _, comp := range components {
+		fmt.Printf("%s- %s: %s", indent, comp.Name, health.FormatHealthStatusWithColor(comp.Status, useColors))
+		if comp.Details != "" {
+			fmt.Printf(" (%s)", comp.Details)
+		}
+		if label := policy.Label(parent + "." + comp.Name); label != "" {
+			fmt.Printf(" [%s]", label)
+		}
+		fmt.Println()
+		displayNestedComponentDetails(comp.SubComponents, parent+"."+comp.Name, depth+1, policy, useColors)
 	}
 }
 
 // displayExtensionDetails shows individual extension details
-func displayExtensionDetails(extensions []health.ComponentStatus, useColors bool) {
+func displayExtensionDetails(extensions []health.ComponentStatus, policy health.ComponentPolicy, useColors bool) {
 	fmt.Printf(" (%d extensions)", len(extensions))
 	fmt.Println()
 
@@ -67,6 +135,9 @@ func displayExtensionDetails(extensions []health.ComponentStatus, useColors bool
 		if ext.Details != "" {
 			fmt.Printf(" (%s)", ext.Details)
 		}
+		if label := policy.Label("extensions." + ext.Name); label != "" {
+			fmt.Printf(" [%s]", label)
+		}
 		fmt.Println()
 	}
 }
@@ -74,7 +145,7 @@ func displayExtensionDetails(extensions []health.ComponentStatus, useColors bool
 // displayStandardHealthResults shows standard output format
 func displayStandardHealthResults(parsedHealth *health.ParsedHealthData, options health.HealthCheckOptions) error {
 	if parsedHealth != nil {
-		fmt.Printf("Health check successful: %s\n", health.FormatHealthStatusWithColor(parsedHealth.OverallStatus, options.UseColors))
+		fmt.Printf("Health check successful: %s\n", health.FormatHealthStatusWithColor(options.Policy.OverallStatus(parsedHealth), options.UseColors))
 		fmt.Printf("Summary: %s\n", health.GetHealthSummaryWithColor(parsedHealth, options.UseColors))
 	} else {
 		fmt.Println("Health check completed")
@@ -86,3 +157,201 @@ func displayStandardHealthResults(parsedHealth *health.ParsedHealthData, options
 func shouldShowDebugInfo() bool {
 	return !outputJSON && !outputRaw && detailed
 }
+
+// displayResourceUsage prints CPU/memory utilization per pod and flags pods over the threshold
+func displayResourceUsage(pods []*v1.Pod, usage map[string]pkg.PodResourceUsage, threshold float64) {
+	fmt.Println("\nResource usage:")
+	renderTableHeader(resourceUsageColumns, 2)
+
+	var flagged []string
+	for _, pod := range pods {
+		entry, ok := usage[pod.Name]
+		if !ok || !entry.HasMetrics {
+			fmt.Printf("%-24s  %s\n", truncateString(pod.Name, 24), "no metrics reported yet")
+			continue
+		}
+
+		cpuPercent, _ := entry.CPUPercent()
+		memPercent, _ := entry.MemoryPercent()
+		fmt.Printf("%-24s  %-8s  %-14s  %-6s  %-10s  %-20s  %-6s\n",
+			truncateString(pod.Name, 24),
+			formatMilliCPU(entry.CPUUsageMilli),
+			formatMilliCPU(entry.CPURequestMilli)+"/"+formatMilliCPU(entry.CPULimitMilli),
+			formatUtilization(entry.CPUPercent()),
+			formatBytes(entry.MemoryUsageBytes),
+			formatResourceBytes(entry.MemoryRequestBytes)+"/"+formatResourceBytes(entry.MemoryLimitBytes),
+			formatUtilization(entry.MemoryPercent()))
+
+		if entry.ExceedsThreshold(threshold) {
+			flagged = append(flagged, fmt.Sprintf("%s (cpu %.0f%%, memory %.0f%%)", pod.Name, cpuPercent, memPercent))
+		}
+	}
+
+	if len(flagged) > 0 {
+		warn := fmt.Sprintf
+		if colorOutputEnabled() {
+			warn = color.New(color.FgYellow).Sprintf
+		}
+		fmt.Println()
+		for _, pod := range flagged {
+			fmt.Println(warn("Warning: %s is at or above %.0f%% of its resources", pod, threshold))
+		}
+	}
+}
+
+func formatMilliCPU(milli int64) string {
+	if milli == 0 {
+		return "-"
+	}
+	if milli%1000 == 0 {
+		return fmt.Sprintf("%d", milli/1000)
+	}
+	return fmt.Sprintf("%dm", milli)
+}
+
+func formatResourceBytes(bytes int64) string {
+	if bytes == 0 {
+		return "-"
+	}
+	return formatBytes(bytes)
+}
+
+func formatUtilization(percent float64, ok bool) string {
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", percent)
+}
+
+// displayPlatformConditions prints the status conditions reported by the HiveMQ Platform Operator
+func displayPlatformConditions(platform *pkg.Platform) {
+	fmt.Printf("\nHiveMQPlatform %s (StatefulSet %s)", platform.Name, platform.StatefulSet)
+	if platform.State != "" {
+		fmt.Printf(": %s", platform.State)
+	}
+	fmt.Println()
+
+	if len(platform.Conditions) == 0 {
+		fmt.Println("  No status conditions reported")
+		return
+	}
+
+	useColors := colorOutputEnabled()
+	for _, condition := range platform.Conditions {
+		status := condition.Status
+		if useColors {
+			switch condition.Status {
+			case "True":
+				status = color.GreenString(status)
+			case "False":
+				status = color.RedString(status)
+			}
+		}
+
+		fmt.Printf("  - %s: %s", condition.Type, status)
+		if condition.Reason != "" {
+			fmt.Printf(" (%s)", condition.Reason)
+		}
+		if condition.Message != "" {
+			fmt.Printf(" - %s", condition.Message)
+		}
+		fmt.Println()
+	}
+}
+
+// displayNetworkReport shows the cluster discovery prerequisites checked by --check-network
+func displayNetworkReport(report *pkg.NetworkReport) {
+	fmt.Printf("\nCluster network (headless service %s, cluster port %d)\n", valueOrDash(report.HeadlessService), report.ClusterPort)
+	displayNetworkChecks(report.Checks)
+}
+
+// displayListenerReport shows the per-pod listener checks of --check-listeners
+func displayListenerReport(report *pkg.ListenerReport) {
+	fmt.Printf("\nListeners of StatefulSet %s\n", report.StatefulSet)
+	displayNetworkChecks(report.Checks)
+}
+
+func displayNetworkChecks(checks []pkg.NetworkCheck) {
+	useColors := colorOutputEnabled()
+	for _, check := range checks {
+		status := check.Status
+		if useColors {
+			switch check.Status {
+			case pkg.NetworkCheckPass:
+				status = color.GreenString(status)
+			case pkg.NetworkCheckWarn:
+				status = color.YellowString(status)
+			case pkg.NetworkCheckFail:
+				status = color.RedString(status)
+			}
+		}
+
+		fmt.Printf("  - %s %s [%s]", check.Name, check.Target, status)
+		if check.Details != "" {
+			fmt.Printf(" - %s", check.Details)
+		}
+		fmt.Println()
+	}
+}
+
+// displayLatencyReport shows the health request latency per pod; pods whose check failed have no samples
+func displayLatencyReport(results []pkg.HealthCheckResult, slo time.Duration) {
+	fmt.Println("\nHealth request latency (without port-forward setup)")
+	table := newTableWriter(latencyColumns, 2)
+	table.header()
+
+	useColors := colorOutputEnabled()
+	for _, result := range results {
+		stats := result.Latency
+		if stats.Samples == 0 {
+			table.row(result.PodName, "0", "-", "-", "-", "-")
+			continue
+		}
+
+		verdict := "-"
+		if slo > 0 {
+			verdict = "met"
+			if stats.ExceedsSLO(slo) {
+				verdict = "EXCEEDED"
+				if useColors {
+					verdict = color.RedString(verdict)
+				}
+			}
+		}
+		table.row(result.PodName, stats.Samples, formatLatency(stats.P50), formatLatency(stats.P95), formatLatency(stats.Max), verdict)
+	}
+	if slo > 0 {
+		fmt.Printf("SLO: p95 at most %s\n", slo)
+	}
+}
+
+func formatLatency(d time.Duration) string {
+	if d < 10*time.Millisecond {
+		return d.Round(10 * time.Microsecond).String()
+	}
+	return d.Round(time.Millisecond).String()
+}
+
+// writeHealthResultsCSV writes one row per checked pod; the latency columns are added when
+// --samples or --slo asked for them
+func writeHealthResultsCSV(results []pkg.HealthCheckResult) error {
+	header := []string{"pod", "status", "response_ms", "details"}
+	if latencyRequested() {
+		header = append(header, "samples", "p50_ms", "p95_ms", "max_ms")
+	}
+	w := newCSVWriter(header...)
+	for _, result := range results {
+		cells := []any{result.PodName, result.Status, result.ResponseTime.Milliseconds(), result.Details}
+		if latencyRequested() {
+			stats := result.Latency
+			cells = append(cells, stats.Samples, csvMilliseconds(stats.P50), csvMilliseconds(stats.P95), csvMilliseconds(stats.Max))
+		}
+		w.row(cells...)
+	}
+	return w.flush()
+}
+
+// csvMilliseconds formats a latency with sub-millisecond precision
+func csvMilliseconds(d time.Duration) string {
+	return fmt.Sprintf("%.1f", float64(d.Microseconds())/1000)
+}
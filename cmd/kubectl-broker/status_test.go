@@ -0,0 +1,59 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPodRefs(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		args []string
+		pod  string
+		pods []string
+		want []string
+	}{
+		{"none", nil, "", nil, nil},
+		{"names in order", []string{"broker-2"}, "broker-0", []string{"broker-1"}, []string{"broker-2", "broker-0", "broker-1"}},
+		{"blanks dropped", []string{" ", "broker-0 "}, "", []string{"", " broker-1"}, []string{"broker-0", "broker-1"}},
+		{"repeated names", []string{"broker-0", "broker-0"}, "broker-0", []string{"broker-1", "broker-0"}, []string{"broker-0", "broker-1"}},
+		{"ordinals expanded", []string{":0", ":2"}, "", nil, []string{"hivemq-0", "hivemq-2"}},
+		{"ordinal and name of the same pod", []string{":1"}, "hivemq-1", []string{":1"}, []string{"hivemq-1"}},
+		{"names mixed with ordinals", []string{"other-0"}, ":3", []string{":0", "hivemq-0"}, []string{"other-0", "hivemq-3", "hivemq-0"}},
+		{"not an ordinal", []string{":", ":x", ":-1", ":+1", "-1"}, "", nil, []string{":", ":x", ":-1", ":+1", "-1"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := expandPodRefs(collectPodRefs(tc.args, tc.pod, tc.pods), "hivemq")
+			if !slices.Equal(got, tc.want) {
+				t.Fatalf("pods = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPodOrdinal(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		ref         string
+		wantOrdinal int
+		wantOK      bool
+	}{
+		{":0", 0, true},
+		{":12", 12, true},
+		{"broker-1", 0, false},
+		{"-1", 0, false},
+		{":", 0, false},
+		{":-1", 0, false},
+		{":1a", 0, false},
+	}
+	for _, tc := range cases {
+		ordinal, ok := podOrdinal(tc.ref)
+		if ordinal != tc.wantOrdinal || ok != tc.wantOK {
+			t.Errorf("podOrdinal(%q) = %d, %v; want %d, %v", tc.ref, ordinal, ok, tc.wantOrdinal, tc.wantOK)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 type tableColumn struct {
@@ -32,3 +34,51 @@ func renderTableHeader(columns []tableColumn, padding int) {
 	fmt.Println(strings.Join(headerParts, separator))
 	fmt.Println(strings.Join(dividerParts, separator))
 }
+
+// tableWriter prints rows aligned under the header of its columns. Cells are padded by their
+// visible length, so colored cells line up in any column; the last cell of a row is not padded.
+type tableWriter struct {
+	columns []tableColumn
+	padding int
+}
+
+func newTableWriter(columns []tableColumn, padding int) *tableWriter {
+	if padding < 1 {
+		padding = 1
+	}
+	return &tableWriter{columns: columns, padding: padding}
+}
+
+// header prints the column titles and divider
+func (w *tableWriter) header() {
+	renderTableHeader(w.columns, w.padding)
+}
+
+// row prints one row; cells are formatted with fmt.Sprint
+func (w *tableWriter) row(cells ...any) {
+	parts := make([]string, len(cells))
+	for i, cell := range cells {
+		text := fmt.Sprint(cell)
+		if i < len(cells)-1 && i < len(w.columns) {
+			width := max(w.columns[i].Width, len(w.columns[i].Title))
+			if pad := width - visibleWidth(text); pad > 0 {
+				text += strings.Repeat(" ", pad)
+			}
+		}
+		parts[i] = text
+	}
+	fmt.Println(strings.Join(parts, strings.Repeat(" ", w.padding)))
+}
+
+// ansiEscape matches the color sequences written by fatih/color
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// visibleWidth returns the number of characters a cell occupies on the terminal
+func visibleWidth(text string) int {
+	return utf8.RuneCountInString(ansiEscape.ReplaceAllString(text, ""))
+}
+
+// withColumns returns base followed by extra, e.g. the additional columns of --output wide
+func withColumns(base []tableColumn, extra ...tableColumn) []tableColumn {
+	return append(append([]tableColumn(nil), base...), extra...)
+}
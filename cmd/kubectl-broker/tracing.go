@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"kubectl-broker/pkg/tracing"
+)
+
+// tracingShutdownTimeout bounds how long exporting the remaining spans may delay the exit
+const tracingShutdownTimeout = 5 * time.Second
+
+var (
+	shutdownTracing func(context.Context) error
+	commandSpan     trace.Span
+)
+
+// startTracing installs the OTLP exporter configured by the environment and opens the root
+// span of the executing command. Setup failures only warn so tracing never blocks a command.
+func startTracing(cmd *cobra.Command) {
+	shutdown, err := tracing.Setup(cmd.Context(), "kubectl-broker")
+	if err != nil {
+		slog.Warn("Tracing disabled", "error", err)
+		return
+	}
+	shutdownTracing = shutdown
+	if !tracing.Enabled() {
+		return
+	}
+
+	ctx := tracing.ContextWithEnvParent(cmd.Context())
+	ctx, commandSpan = tracing.Start(ctx, cmd.CommandPath(),
+		attribute.String("kubectl_broker.output", currentOutputFormat()),
+		attribute.Bool("kubectl_broker.in_cluster", globalFlags.InCluster))
+	cmd.SetContext(ctx)
+	slog.Debug("Tracing enabled", "traceID", commandSpan.SpanContext().TraceID().String())
+}
+
+// finishTracing ends the command span with the command's outcome and flushes pending spans
+func finishTracing(err error) {
+	if commandSpan != nil {
+		tracing.End(commandSpan, err)
+	}
+	if shutdownTracing == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+	defer cancel()
+	if shutdownErr := shutdownTracing(ctx); shutdownErr != nil {
+		slog.Warn("Failed to export traces", "error", shutdownErr)
+	}
+}
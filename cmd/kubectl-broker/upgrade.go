@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"kubectl-broker/pkg/update"
+)
+
+// upgradeRepositoryEnv points upgrades at a fork or mirror ("owner/name")
+const upgradeRepositoryEnv = "KUBECTL_BROKER_UPGRADE_REPOSITORY"
+
+var (
+	upgradeCheckOnly bool
+	upgradeVersion   string
+	upgradeForce     bool
+)
+
+// upgradeCheck is the comparison of the running plugin with a published release
+type upgradeCheck struct {
+	Current    string `json:"current" yaml:"current"`
+	Latest     string `json:"latest" yaml:"latest"`
+	Available  bool   `json:"updateAvailable" yaml:"updateAvailable"`
+	ReleaseURL string `json:"releaseUrl,omitempty" yaml:"releaseUrl,omitempty"`
+	Asset      string `json:"asset" yaml:"asset"`
+	Executable string `json:"executable,omitempty" yaml:"executable,omitempty"`
+}
+
+func newUpgradeCommand() *cobra.Command {
+	var upgradeCmd = &cobra.Command{
+		Use:   "upgrade",
+		Short: "Update the plugin binary to the latest release",
+		Long: `Upgrade checks the latest GitHub release of the plugin and, when it is newer
+than the running version, downloads the binary for this platform, verifies its
+SHA-256 against the checksums published with the release and replaces the
+running executable. The kubectl-pulse and kubectl-edge links keep pointing at
+the upgraded binary. A download that fails or does not match its checksum
+leaves the installed binary untouched.
+
+Installations managed by krew are upgraded with 'kubectl krew upgrade broker'
+instead. Development builds (version "dev") are only replaced with --force.
+Set KUBECTL_BROKER_UPGRADE_REPOSITORY=owner/name to upgrade from a fork.
+
+Examples:
+  # Is a newer version available?
+  kubectl broker upgrade --check-only
+
+  # Upgrade to the latest release
+  kubectl broker upgrade
+
+  # Install a specific release, also to downgrade
+  kubectl broker upgrade --version v1.4.0 --force`,
+		Args: cobra.NoArgs,
+		RunE: runUpgrade,
+	}
+
+	upgradeCmd.Flags().BoolVar(&upgradeCheckOnly, "check-only", false, "Only report whether a newer release is available")
+	upgradeCmd.Flags().StringVar(&upgradeVersion, "version", "", "Release tag to install instead of the latest release (e.g. v1.4.0)")
+	upgradeCmd.Flags().BoolVar(&upgradeForce, "force", false, "Install even when the release is not newer or the running binary is a development build")
+
+	return upgradeCmd
+}
+
+func runUpgrade(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	repository := update.DefaultRepository
+	if value := os.Getenv(upgradeRepositoryEnv); value != "" {
+		repository = value
+	}
+	client, err := update.NewClient(update.DefaultAPIURL, repository, operationTimeout(5*time.Minute))
+	if err != nil {
+		return err
+	}
+
+	var release *update.Release
+	if upgradeVersion != "" {
+		release, err = client.ReleaseByTag(ctx, upgradeVersion)
+	} else {
+		release, err = client.LatestRelease(ctx)
+	}
+	if errors.Is(err, update.ErrNoRelease) {
+		return fmt.Errorf("no published release %s in %s\n\nPlease either:\n- Check the available releases: https://github.com/%s/releases\n- Omit --version to install the latest release", valueOrDash(upgradeVersion), repository, repository)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up releases: %w", err)
+	}
+
+	check := upgradeCheck{
+		Current:    version,
+		Latest:     release.TagName,
+		Available:  update.Newer(version, release.TagName),
+		ReleaseURL: release.HTMLURL,
+		Asset:      update.AssetName(runtime.GOOS, runtime.GOARCH),
+	}
+	if check.Executable, err = update.Executable(); err != nil {
+		slog.Debug("Could not locate the running executable", "error", err)
+	}
+	if upgradeCheckOnly {
+		return renderUpgradeCheck(check)
+	}
+
+	if !check.Available && !upgradeForce {
+		if version == "dev" {
+			return fmt.Errorf("this is a development build, so it cannot be compared with release %s\n\nPlease either:\n- Install the release anyway: kubectl broker upgrade --force\n- Rebuild from source: make install", release.TagName)
+		}
+		fmt.Printf("kubectl-broker %s is up to date (latest release: %s)\n", version, release.TagName)
+		return nil
+	}
+	if check.Executable == "" {
+		return fmt.Errorf("cannot locate the running executable to replace it\n\nPlease either:\n- Download %s manually from %s\n- Reinstall with: make install", check.Asset, release.HTMLURL)
+	}
+	if update.ManagedByKrew(check.Executable) {
+		return fmt.Errorf("%s is managed by krew\n\nPlease either:\n- Upgrade through krew: kubectl krew upgrade broker\n- Uninstall the krew plugin before installing the binary directly", check.Executable)
+	}
+
+	asset, ok := release.Asset(check.Asset)
+	if !ok {
+		return fmt.Errorf("release %s has no binary for %s/%s (expected asset %s)", release.TagName, runtime.GOOS, runtime.GOARCH, check.Asset)
+	}
+	checksums, err := client.Checksums(ctx, release)
+	if err != nil {
+		return err
+	}
+	wantSHA256, ok := checksums[asset.Name]
+	if !ok {
+		return fmt.Errorf("%s of release %s lists no checksum for %s, refusing to install an unverified binary", update.ChecksumsAsset, release.TagName, asset.Name)
+	}
+
+	if globalFlags.DryRun {
+		return renderDryRunPlan("upgrade",
+			fmt.Sprintf("Download %s of release %s and verify SHA-256 %s", asset.Name, release.TagName, wantSHA256),
+			fmt.Sprintf("Replace %s (%s) with %s", check.Executable, version, release.TagName))
+	}
+
+	fmt.Printf("Upgrading kubectl-broker %s to %s\n", version, release.TagName)
+	slog.Info("Downloading release asset", "asset", asset.Name, "size", formatBytes(asset.Size))
+	err = update.ReplaceExecutable(check.Executable, func(w io.Writer) error {
+		return client.Download(ctx, asset, wantSHA256, w)
+	})
+	if err != nil {
+		return fmt.Errorf("upgrade failed, %s was not changed: %w", check.Executable, err)
+	}
+
+	fmt.Printf("Installed %s to %s (SHA-256 verified)\n", release.TagName, check.Executable)
+	return nil
+}
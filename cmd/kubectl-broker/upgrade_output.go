@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fatih/color"
+	"sigs.k8s.io/yaml"
+)
+
+func renderUpgradeCheck(check upgradeCheck) error {
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		return writeStructuredUpgradeCheck(check, format)
+	}
+
+	fmt.Printf("Installed:   %s\n", check.Current)
+	fmt.Printf("Latest:      %s\n", check.Latest)
+	fmt.Printf("Executable:  %s\n", valueOrDash(check.Executable))
+	if !check.Available {
+		color.Green("kubectl-broker is up to date")
+		return nil
+	}
+	color.Yellow("Update available: %s", check.ReleaseURL)
+	fmt.Println("Install it with: kubectl broker upgrade")
+	return nil
+}
+
+func writeStructuredUpgradeCheck(check upgradeCheck, format string) error {
+	if format == "yaml" {
+		data, err := yaml.Marshal(check)
+		if err != nil {
+			return fmt.Errorf("failed to encode upgrade check as YAML: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	data, err := json.MarshalIndent(check, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode upgrade check as JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/sidecar"
+)
+
+// Set at build time, e.g. go build -ldflags "-X main.version=v1.4.0 -X main.commit=$(git rev-parse --short HEAD)"
+var (
+	version = "dev"
+	commit  = ""
+)
+
+// versionProbeTimeout bounds the sidecar probe per installation
+const versionProbeTimeout = 10 * time.Second
+
+var (
+	versionClientOnly    bool
+	versionNamespace     string
+	versionAllNamespaces bool
+)
+
+// versionReport is the environment fingerprint printed by the version command
+type versionReport struct {
+	Client        clientVersion       `json:"client" yaml:"client"`
+	Server        *serverVersion      `json:"server,omitempty" yaml:"server,omitempty"`
+	Installations []componentVersions `json:"installations,omitempty" yaml:"installations,omitempty"`
+}
+
+type clientVersion struct {
+	Version   string `json:"version" yaml:"version"`
+	Commit    string `json:"commit,omitempty" yaml:"commit,omitempty"`
+	GoVersion string `json:"goVersion" yaml:"goVersion"`
+	ClientGo  string `json:"clientGo,omitempty" yaml:"clientGo,omitempty"`
+	Platform  string `json:"platform" yaml:"platform"`
+}
+
+type serverVersion struct {
+	Kubernetes string `json:"kubernetes,omitempty" yaml:"kubernetes,omitempty"`
+	Error      string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// componentVersions are the HiveMQ component versions of one installation
+type componentVersions struct {
+	Namespace    string `json:"namespace" yaml:"namespace"`
+	StatefulSet  string `json:"statefulset" yaml:"statefulset"`
+	Broker       string `json:"broker,omitempty" yaml:"broker,omitempty"`
+	Sidecar      string `json:"sidecar,omitempty" yaml:"sidecar,omitempty"`
+	SidecarError string `json:"sidecarError,omitempty" yaml:"sidecarError,omitempty"`
+}
+
+func newVersionCommand() *cobra.Command {
+	var versionCmd = &cobra.Command{
+		Use:   "version",
+		Short: "Show plugin, Kubernetes and HiveMQ component versions",
+		Long: `Version prints the plugin version and commit, the Go and client-go versions it
+was built with, the Kubernetes server version and, for every HiveMQ StatefulSet
+in the namespace, the broker version (from its image) and the version reported
+by the backup sidecar. Unreachable components are shown as "-" and do not fail
+the command, so the output can always be attached to a bug report.
+
+Examples:
+  # Everything for the current namespace
+  kubectl broker version
+
+  # Only the plugin itself, without contacting the cluster
+  kubectl broker version --client
+
+  # Component versions of every installation as JSON
+  kubectl broker version --all-namespaces --output json`,
+		Args: cobra.NoArgs,
+		RunE: runVersion,
+	}
+
+	versionCmd.Flags().BoolVar(&versionClientOnly, "client", false, "Only show the plugin version, without contacting the cluster")
+	versionCmd.Flags().StringVarP(&versionNamespace, "namespace", "n", "", "Namespace (defaults to current kubectl context)")
+	versionCmd.Flags().BoolVar(&versionAllNamespaces, "all-namespaces", false, "Show component versions of every HiveMQ installation")
+
+	return versionCmd
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if err := mutuallyExclusive(versionAllNamespaces, "--all-namespaces", versionNamespace != "", "--namespace"); err != nil {
+		return err
+	}
+
+	report := versionReport{Client: buildClientVersion()}
+	if versionClientOnly {
+		return renderVersionReport(report)
+	}
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		// A broken kubeconfig is exactly what a bug report needs to show
+		report.Server = &serverVersion{Error: err.Error()}
+		return renderVersionReport(report)
+	}
+
+	report.Server = &serverVersion{}
+	if report.Server.Kubernetes, err = k8sClient.ServerVersion(ctx); err != nil {
+		report.Server.Error = err.Error()
+		return renderVersionReport(report)
+	}
+
+	installations, err := versionInstallations(ctx, k8sClient)
+	if err != nil {
+		slog.Warn("Could not list HiveMQ installations", "error", err)
+	}
+	for _, installation := range installations {
+		report.Installations = append(report.Installations, probeComponentVersions(ctx, k8sClient, installation))
+	}
+
+	return renderVersionReport(report)
+}
+
+// buildClientVersion combines the ldflags version with the module build information
+func buildClientVersion() clientVersion {
+	info := clientVersion{
+		Version:   version,
+		Commit:    commit,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, dep := range buildInfo.Deps {
+		if dep.Path == "k8s.io/client-go" {
+			info.ClientGo = dep.Version
+		}
+	}
+	if info.Commit == "" {
+		for _, setting := range buildInfo.Settings {
+			if setting.Key == "vcs.revision" && len(setting.Value) >= 7 {
+				info.Commit = setting.Value[:7]
+			}
+		}
+	}
+	return info
+}
+
+// versionInstallations lists the installations of the selected namespace, or of all namespaces
+func versionInstallations(ctx context.Context, k8sClient *pkg.K8sClient) ([]pkg.Installation, error) {
+	if versionAllNamespaces {
+		return k8sClient.DiscoverInstallations(ctx)
+	}
+	namespace, _, err := resolveNamespace(versionNamespace, true)
+	if err != nil {
+		return nil, err
+	}
+	return k8sClient.DiscoverNamespaceInstallations(ctx, namespace)
+}
+
+// probeComponentVersions asks the backup sidecar of an installation for its version
+func probeComponentVersions(ctx context.Context, k8sClient *pkg.K8sClient, installation pkg.Installation) componentVersions {
+	versions := componentVersions{
+		Namespace:   installation.Namespace,
+		StatefulSet: installation.StatefulSet,
+		Broker:      installation.Version,
+	}
+	if installation.ReadyReplicas == 0 {
+		versions.SidecarError = "no ready pods"
+		return versions
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, versionProbeTimeout)
+	defer cancel()
+	err := sidecar.NewConnector(k8sClient).WithConnection(probeCtx, sidecar.ConnectOptions{
+		Namespace:   installation.Namespace,
+		StatefulSet: installation.StatefulSet,
+		Timeout:     versionProbeTimeout,
+	}, func(client *sidecar.Client) error {
+		status, err := client.Status(probeCtx)
+		if err != nil {
+			return err
+		}
+		versions.Sidecar = status.Version
+		return nil
+	})
+	switch {
+	case errors.Is(err, sidecar.ErrNotSupported):
+		versions.SidecarError = "sidecar predates the status endpoint"
+	case err != nil:
+		slog.Debug("Sidecar version probe failed", "namespace", installation.Namespace, "statefulset", installation.StatefulSet, "error", err)
+		versions.SidecarError = "not reachable"
+	}
+	return versions
+}
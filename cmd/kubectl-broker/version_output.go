@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+var componentVersionColumns = []tableColumn{
+	{Title: "NAMESPACE", Width: 36},
+	{Title: "STATEFULSET", Width: 20},
+	{Title: "HIVEMQ", Width: 12},
+	{Title: "SIDECAR", Width: 12},
+}
+
+func renderVersionReport(report versionReport) error {
+	if quietOutput() {
+		printResult(report.Client.Version)
+		return nil
+	}
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		return writeStructuredVersion(report, format)
+	}
+
+	client := report.Client
+	fmt.Printf("Plugin:      %s", client.Version)
+	if client.Commit != "" {
+		fmt.Printf(" (%s)", client.Commit)
+	}
+	fmt.Println()
+	fmt.Printf("Go:          %s %s\n", client.GoVersion, client.Platform)
+	fmt.Printf("client-go:   %s\n", valueOrDash(client.ClientGo))
+
+	if report.Server == nil {
+		return nil
+	}
+	if report.Server.Error != "" {
+		fmt.Printf("Kubernetes:  - (%s)\n", report.Server.Error)
+		return nil
+	}
+	fmt.Printf("Kubernetes:  %s\n", report.Server.Kubernetes)
+
+	if len(report.Installations) == 0 {
+		fmt.Println("\nNo HiveMQ installations found")
+		return nil
+	}
+
+	fmt.Println()
+	renderTableHeader(componentVersionColumns, 2)
+	for _, installation := range report.Installations {
+		sidecarVersion := valueOrDash(installation.Sidecar)
+		if installation.SidecarError != "" {
+			sidecarVersion = fmt.Sprintf("- (%s)", installation.SidecarError)
+		}
+		fmt.Printf("%-36s  %-20s  %-12s  %s\n",
+			truncateString(installation.Namespace, 36),
+			truncateString(installation.StatefulSet, 20),
+			truncateString(valueOrDash(installation.Broker), 12),
+			sidecarVersion)
+	}
+	return nil
+}
+
+func writeStructuredVersion(report versionReport, format string) error {
+	if format == "yaml" {
+		data, err := yaml.Marshal(report)
+		if err != nil {
+			return fmt.Errorf("failed to encode version as YAML: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode version as JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -2,28 +2,63 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/units"
 	"kubectl-broker/pkg/volumes"
 )
 
 var (
 	// Global volumes flags
 	volumesNamespace     string
+	volumesSelector      string
 	volumesAllNamespaces bool
 	volumesMinAge        string
 	volumesMinSize       string
-	volumesDryRun        bool
 	volumesConfirm       bool
+	volumesConfirmPhrase string
 	volumesForce         bool
 	volumesShowReleased  bool
 	volumesShowOrphaned  bool
 	volumesShowAll       bool
 	volumesShowDetailed  bool
+	volumesInclude       []string
+	volumesExclude       []string
+	volumesProtectHiveMQ bool
+	volumesForceOperator bool
+	volumesCostRates     map[string]string
+	volumesStorageClass  string
+	volumesAccessMode    string
+	volumesConcurrency   int
+	volumesExecTimeout   time.Duration
+	volumesPlanFile      string
+	volumesExportPlan    string
+
+	// Usage command flags
+	volumesUsageStatefulSet string
+	volumesUsagePath        string
+	volumesUsageWarn        = units.Percent(volumes.DefaultPodUsageOptions.WarnThreshold)
+	volumesUsageCritical    = units.Percent(volumes.DefaultPodUsageOptions.CriticalThreshold)
+	volumesUsageSkipDu      bool
+	volumesUsageWatch       bool
+	volumesUsageInterval    time.Duration
+
+	// Adopt command flags
+	volumesAdoptPV   string
+	volumesAdoptPVC  string
+	volumesAdoptWait time.Duration
+
+	// Unstick command flags
+	volumesUnstickPVC string
 )
 
 func newVolumesCommand() *cobra.Command {
@@ -55,6 +90,18 @@ Examples:
   kubectl broker volumes cleanup --all-namespaces --dry-run
   kubectl broker volumes cleanup --all-namespaces --confirm
 
+  # Live disk usage inside broker pods
+  kubectl broker volumes usage --statefulset broker
+
+  # Rebind a released volume after the namespace was recreated
+  kubectl broker volumes adopt --pv pvc-1234 --namespace hivemq --pvc-name data-broker-0
+
+  # Release a claim stuck in Terminating
+  kubectl broker volumes unstick --pvc data-broker-3 --namespace hivemq
+
+  # Per-namespace storage report for a capacity review
+  kubectl broker volumes report --all-namespaces --output html > volumes.html
+
   # Safety features
   kubectl broker volumes cleanup --older-than 30d --dry-run
   kubectl broker volumes cleanup --min-size 1Gi --confirm`,
@@ -63,13 +110,18 @@ Examples:
 	// Add persistent flags for all subcommands
 	volumesCmd.PersistentFlags().StringVarP(&volumesNamespace, "namespace", "n", "", "Namespace to operate in (defaults to current kubectl context)")
 	volumesCmd.PersistentFlags().BoolVar(&volumesAllNamespaces, "all-namespaces", false, "Operate across all namespaces in the cluster")
-	volumesCmd.PersistentFlags().StringVar(&volumesMinAge, "older-than", "", "Only show/delete volumes older than specified duration (e.g., 7d, 30d)")
-	volumesCmd.PersistentFlags().StringVar(&volumesMinSize, "min-size", "", "Only show/delete volumes larger than specified size (e.g., 1Gi, 100Mi)")
+	volumesCmd.PersistentFlags().StringVar(&volumesMinAge, "older-than", "", "Only show/delete volumes older than specified duration (e.g., 7d, 36h, 1w2d)")
+	volumesCmd.PersistentFlags().StringVarP(&volumesSelector, "selector", "l", "", "Only include volumes (list, cleanup) or the StatefulSet (usage) matching this label selector")
+	volumesCmd.PersistentFlags().StringVar(&volumesMinSize, "min-size", "", "Only show/delete volumes at least this large (e.g., 1Gi, 100Mi, 1.5GB)")
 
 	// Add subcommands
 	volumesCmd.AddCommand(newVolumesListCommand())
 	volumesCmd.AddCommand(newVolumesCleanupCommand())
 	volumesCmd.AddCommand(newVolumesDiscoverCommand())
+	volumesCmd.AddCommand(newVolumesReportCommand())
+	volumesCmd.AddCommand(newVolumesUsageCommand())
+	volumesCmd.AddCommand(newVolumesAdoptCommand())
+	volumesCmd.AddCommand(newVolumesUnstickCommand())
 
 	return volumesCmd
 }
@@ -81,14 +133,39 @@ func newVolumesListCommand() *cobra.Command {
 		Long: `List persistent volumes and persistent volume claims in the specified namespace
 or across the entire cluster. Shows volume status, size, age, and associated resources.
 
-By default, shows volumes in the current kubectl context namespace.`,
-		RunE: runVolumesList,
+By default, shows volumes in the current kubectl context namespace.
+
+Volumes that need attention are listed as problems with their root cause: claims
+Pending because of a missing StorageClass, an unmatched selector or a missing
+volume, claims stuck Terminating behind the kubernetes.io/pvc-protection
+finalizer, and volumes in phase Failed.
+
+With --cost-per-gb-month, volumes are aggregated by StorageClass and priced to
+estimate the monthly cost and the savings of a cleanup. Rates are per GB and
+month in any currency; "*" prices classes without their own rate.
+
+Examples:
+  # Orphaned and released volumes across the cluster
+  kubectl broker volumes list --all-namespaces
+
+  # Only the HiveMQ data volumes of one StorageClass
+  kubectl broker volumes list --storage-class broker-standard-1 --access-mode RWO -l app=hivemq
+
+  # Estimate monthly savings of a cleanup for EBS gp3 and io2 volumes
+  kubectl broker volumes list --all-namespaces --cost-per-gb-month 'gp3=0.08,io2=0.125,*=0.10'`,
+		RunE:        runVolumesList,
+		Annotations: map[string]string{csvOutputAnnotation: "true"},
 	}
 
 	listCmd.Flags().BoolVar(&volumesShowReleased, "released", false, "Show only released persistent volumes")
 	listCmd.Flags().BoolVar(&volumesShowOrphaned, "orphaned", false, "Show only orphaned volumes (PVCs without pods)")
 	listCmd.Flags().BoolVar(&volumesShowAll, "all", false, "Show all volumes including bound ones")
 	listCmd.Flags().BoolVar(&volumesShowDetailed, "detailed", false, "Show detailed usage information (slower, queries Node Stats API)")
+	listCmd.Flags().StringVar(&volumesStorageClass, "storage-class", "", "Only include volumes of this StorageClass")
+	listCmd.Flags().StringVar(&volumesAccessMode, "access-mode", "", "Only include volumes with this access mode (RWO, RWX, ROX, RWOP)")
+	listCmd.Flags().StringToStringVar(&volumesCostRates, "cost-per-gb-month", nil, "Price per GB and month by StorageClass for cost estimates (e.g. gp3=0.08,*=0.10)")
+	listCmd.Flags().IntVar(&volumesConcurrency, "concurrency", 0, "Maximum parallel Node Stats API requests with --detailed (0 uses the default)")
+	listCmd.Flags().DurationVar(&volumesExecTimeout, "exec-timeout", volumes.DefaultUsageCollectorOptions.Timeout, "Timeout of each Node Stats API request with --detailed")
 
 	return listCmd
 }
@@ -103,13 +180,56 @@ This operation helps reclaim storage space from deleted HiveMQ broker instances.
 By default, operates in the current kubectl context namespace. Use --all-namespaces
 for cluster-wide cleanup.
 
-IMPORTANT: Always run with --dry-run first to preview what will be deleted!`,
-		RunE: runVolumesCleanup,
+--include and --exclude take shell globs matched against volume names and
+namespaces. --protect-hivemq keeps volumes in namespaces that run a HiveMQ
+StatefulSet (scaled above zero); only --force overrides the protection.
+
+Volumes owned or labeled by the HiveMQ operator, and claims of StatefulSets it
+manages, are never deleted without --force-operator-managed: the operator may
+recreate them or stop reconciling its platform when they disappear.
+
+--confirm asks for confirmation on the terminal. In CI or other non-interactive
+environments pass --confirm-phrase with the target namespace (or 'all-namespaces'
+together with --all-namespaces) instead; the command fails rather than waiting
+for input when stdin is not a terminal.
+
+--export-plan writes the volumes of a dry run to a YAML file (JSON with a .json
+extension). Review it like any other change, then apply it with --plan: only the
+listed volumes are deleted, and the cleanup is refused when any of them was
+modified, recreated or is back in use since planning. The plan fixes namespace
+and filters, so --plan cannot be combined with them.
+
+IMPORTANT: Always run with --dry-run first to preview what will be deleted!
+
+Examples:
+  # Preview cleanup of test namespaces only
+  kubectl broker volumes cleanup --all-namespaces --include 'test-*' --dry-run
+
+  # Cluster-wide cleanup that never touches running HiveMQ installations
+  kubectl broker volumes cleanup --all-namespaces --protect-hivemq --exclude 'prod-*' --confirm
+
+  # Non-interactive cleanup in CI
+  kubectl broker volumes cleanup -n staging --confirm-phrase staging
+
+  # Export a reviewed plan, then apply exactly that plan
+  kubectl broker volumes cleanup -n staging --older-than 30d --dry-run --export-plan plan.yaml
+  kubectl broker volumes cleanup --plan plan.yaml --confirm-phrase staging
+
+  # Post a summary of the cleanup to a Slack channel
+  kubectl broker volumes cleanup -n staging --confirm-phrase staging --notify-webhook "$SLACK_WEBHOOK" --notify-format slack`,
+		RunE: withNotification("volumes cleanup", volumesNotifyTarget, runVolumesCleanup),
 	}
 
-	cleanupCmd.Flags().BoolVar(&volumesDryRun, "dry-run", false, "Preview what would be deleted without actually deleting")
-	cleanupCmd.Flags().BoolVar(&volumesConfirm, "confirm", false, "Confirm deletion (required for actual deletion)")
-	cleanupCmd.Flags().BoolVar(&volumesForce, "force", false, "Skip confirmation prompts (dangerous!)")
+	cleanupCmd.Flags().BoolVar(&volumesConfirm, "confirm", false, "Confirm deletion interactively (required for actual deletion)")
+	cleanupCmd.Flags().StringVar(&volumesConfirmPhrase, "confirm-phrase", "", "Confirm deletion without a prompt by repeating the target namespace ('all-namespaces' with --all-namespaces)")
+	cleanupCmd.Flags().BoolVar(&volumesForce, "force", false, "Skip confirmation prompts and HiveMQ protection (dangerous!)")
+	cleanupCmd.Flags().StringSliceVar(&volumesInclude, "include", nil, "Only delete volumes whose name or namespace matches one of these globs")
+	cleanupCmd.Flags().StringSliceVar(&volumesExclude, "exclude", nil, "Never delete volumes whose name or namespace matches one of these globs")
+	cleanupCmd.Flags().BoolVar(&volumesProtectHiveMQ, "protect-hivemq", false, "Keep volumes in namespaces with running HiveMQ StatefulSets (overridden by --force)")
+	cleanupCmd.Flags().BoolVar(&volumesForceOperator, "force-operator-managed", false, "Also delete volumes managed by the HiveMQ operator")
+	cleanupCmd.Flags().StringVar(&volumesExportPlan, "export-plan", "", "Write the volumes of a --dry-run to this plan file for review (YAML, or JSON with a .json extension)")
+	cleanupCmd.Flags().StringVar(&volumesPlanFile, "plan", "", "Delete exactly the volumes of a plan exported with --export-plan")
+	addNotifyFlags(cleanupCmd)
 
 	return cleanupCmd
 }
@@ -120,15 +240,160 @@ func newVolumesDiscoverCommand() *cobra.Command {
 		Short: "Discover and analyze volume usage patterns",
 		Long: `Discover persistent volumes and claims across the cluster and analyze 
 storage usage patterns. Provides insights into total storage usage, 
-reclaimable space, and volume distribution by namespace.`,
+reclaimable space, and volume distribution by namespace and StorageClass.
+
+Examples:
+  # Cluster-wide summary with estimated monthly savings
+  kubectl broker volumes discover --cost-per-gb-month gp3=0.08,standard=0.04`,
 		RunE: runVolumesDiscover,
 	}
 
+	discoverCmd.Flags().StringToStringVar(&volumesCostRates, "cost-per-gb-month", nil, "Price per GB and month by StorageClass for cost estimates (e.g. gp3=0.08,*=0.10)")
+
 	return discoverCmd
 }
 
+func newVolumesReportCommand() *cobra.Command {
+	var reportCmd = &cobra.Command{
+		Use:   "report",
+		Short: "Aggregate volumes per namespace for capacity reviews",
+		Long: `Report aggregates the analyzed volumes per namespace: claims in total, bound
+and orphaned claims, released volumes, the storage a cleanup would reclaim and
+whether the namespace belongs to a HiveMQ installation. Namespaces with the
+most reclaimable storage are listed first.
+
+Besides the global table, wide, json and yaml formats, --output csv writes a
+spreadsheet-friendly table and --output html renders a standalone page for
+capacity review meetings.
+
+Examples:
+  # Reclaimable storage per namespace across the cluster
+  kubectl broker volumes report --all-namespaces
+
+  # Import into a spreadsheet
+  kubectl broker volumes report --all-namespaces --output csv > volumes.csv
+
+  # Shareable HTML report of volumes older than a month
+  kubectl broker volumes report --all-namespaces --older-than 30d --output html > volumes.html`,
+		RunE:        runVolumesReport,
+		Annotations: map[string]string{csvOutputAnnotation: "true"},
+	}
+
+	return reportCmd
+}
+
+func newVolumesUsageCommand() *cobra.Command {
+	var usageCmd = &cobra.Command{
+		Use:   "usage",
+		Short: "Show live disk usage inside broker pods",
+		Long: `Show live disk usage of the broker data volume by running df and du inside
+each pod of the StatefulSet concurrently. Pods above the warning or critical
+threshold are highlighted.
+
+The data mount is detected from the pod's PVC-backed volume mounts and falls
+back to /opt/hivemq/data.
+
+Examples:
+  # Usage for the default broker StatefulSet
+  kubectl broker volumes usage
+
+  # Custom thresholds and JSON output
+  kubectl broker volumes usage --statefulset broker --warn-threshold 70 --critical-threshold 85 --output json
+
+  # Usage for a tenant StatefulSet selected by label
+  kubectl broker volumes usage --selector app.kubernetes.io/instance=tenant-a
+
+  # Sample every 30s and show growth per hour and days until the volumes are full
+  kubectl broker volumes usage --watch --interval 30s`,
+		RunE: runVolumesUsage,
+	}
+
+	usageCmd.Flags().StringVar(&volumesUsageStatefulSet, "statefulset", "", "Broker StatefulSet to inspect (defaults to 'broker')")
+	usageCmd.Flags().StringVar(&volumesUsagePath, "path", "", "Data mount path inside the pods (auto-detected if omitted)")
+	usageCmd.Flags().Var(&volumesUsageWarn, "warn-threshold", "Usage percentage that marks a pod as WARNING (e.g., 70 or 70%)")
+	usageCmd.Flags().Var(&volumesUsageCritical, "critical-threshold", "Usage percentage that marks a pod as CRITICAL (e.g., 85 or 85%)")
+	usageCmd.Flags().BoolVar(&volumesUsageSkipDu, "no-du", false, "Skip the du scan of the data directory (faster on large volumes)")
+	usageCmd.Flags().IntVar(&volumesConcurrency, "concurrency", 0, "Maximum parallel pod execs (0 uses the default)")
+	usageCmd.Flags().DurationVar(&volumesExecTimeout, "exec-timeout", volumes.DefaultPodUsageOptions.Timeout, "Timeout of each pod exec")
+	usageCmd.Flags().BoolVarP(&volumesUsageWatch, "watch", "w", false, "Sample repeatedly and show growth (delta, rate per hour, projected days until full) until interrupted")
+	usageCmd.Flags().DurationVar(&volumesUsageInterval, "interval", 30*time.Second, "Time between samples with --watch")
+
+	return usageCmd
+}
+
+func newVolumesAdoptCommand() *cobra.Command {
+	var adoptCmd = &cobra.Command{
+		Use:   "adopt",
+		Short: "Rebind a released volume to a new claim",
+		Long: `Rebind a released persistent volume to a new persistent volume claim so its
+data can be used again, e.g. after a namespace was deleted and recreated.
+
+The PV's claimRef is pointed at the new claim, a PVC with matching size,
+access modes and storage class is created, and the command waits until the
+claim is Bound. Only volumes with reclaim policy Retain are adopted, since
+Delete volumes may be removed by the provisioner in the meantime.
+
+Create the claim before scaling up the StatefulSet so the broker pod picks
+up the adopted volume instead of provisioning a new one.
+
+Examples:
+  # Preview the adoption
+  kubectl broker volumes adopt --pv pvc-1234 --namespace hivemq --pvc-name data-broker-0 --dry-run
+
+  # Rebind and wait up to five minutes for the claim to bind
+  kubectl broker volumes adopt --pv pvc-1234 --namespace hivemq --pvc-name data-broker-0 --wait 5m`,
+		RunE: runVolumesAdopt,
+	}
+
+	adoptCmd.Flags().StringVar(&volumesAdoptPV, "pv", "", "Released persistent volume to adopt (required)")
+	adoptCmd.Flags().StringVar(&volumesAdoptPVC, "pvc-name", "", "Name of the claim to create, e.g. data-broker-0 (required)")
+	adoptCmd.Flags().DurationVar(&volumesAdoptWait, "wait", volumes.DefaultAdoptBindTimeout, "How long to wait for the claim to bind")
+	_ = adoptCmd.MarkFlagRequired("pv")
+	_ = adoptCmd.MarkFlagRequired("pvc-name")
+
+	return adoptCmd
+}
+
+func newVolumesUnstickCommand() *cobra.Command {
+	var unstickCmd = &cobra.Command{
+		Use:   "unstick",
+		Short: "Remove the finalizers of a claim stuck in Terminating",
+		Long: `Remove the finalizers of a persistent volume claim that was deleted but stays
+in Terminating. The claim must already be deleted and no running pod may still
+mount it; otherwise the kubernetes.io/pvc-protection finalizer is doing its job
+and the pods have to go first.
+
+Only kubernetes.io/pvc-protection is removed. Finalizers of other controllers,
+e.g. for volume snapshots or CSI storage, may still guard data; claims carrying
+them are refused unless --force is given, and the finalizers stay in place.
+
+Once the finalizers are gone Kubernetes removes the claim, and its volume is
+reclaimed according to the volume's reclaim policy.
+
+Examples:
+  # Check whether the claim can be released
+  kubectl broker volumes unstick --pvc data-broker-3 --namespace hivemq --dry-run
+
+  # Remove the finalizers
+  kubectl broker volumes unstick --pvc data-broker-3 --namespace hivemq`,
+		RunE: runVolumesUnstick,
+	}
+
+	unstickCmd.Flags().StringVar(&volumesUnstickPVC, "pvc", "", "Terminating persistent volume claim to release (required)")
+	unstickCmd.Flags().BoolVar(&volumesForce, "force", false, "Release the claim although finalizers of other controllers remain (they are kept)")
+	_ = unstickCmd.MarkFlagRequired("pvc")
+
+	return unstickCmd
+}
+
 // Apply intelligent defaults similar to status and backup commands
 func applyVolumesDefaults() error {
+	if volumesSelector != "" {
+		if err := pkg.ValidateLabelSelector(volumesSelector); err != nil {
+			return err
+		}
+	}
+
 	if volumesNamespace == "" && !volumesAllNamespaces {
 		resolvedNamespace, fromContext, err := resolveNamespace(volumesNamespace, true)
 		if err != nil {
@@ -136,7 +401,7 @@ func applyVolumesDefaults() error {
 		}
 		volumesNamespace = resolvedNamespace
 		if fromContext {
-			fmt.Printf("Using namespace from context: %s\n", volumesNamespace)
+			slog.Info("Using namespace from context", "namespace", volumesNamespace)
 		}
 	}
 
@@ -147,9 +412,22 @@ func runVolumesList(cmd *cobra.Command, args []string) error {
 	if err := applyVolumesDefaults(); err != nil {
 		return err
 	}
+	minAge, minSize, err := volumeFilters()
+	if err != nil {
+		return err
+	}
+
+	costRates, err := volumes.ParseCostRates(volumesCostRates)
+	if err != nil {
+		return err
+	}
+	accessMode, err := volumes.ParseAccessMode(volumesAccessMode)
+	if err != nil {
+		return err
+	}
 
 	// Initialize Kubernetes client
-	k8sClient, err := pkg.NewK8sClient(false)
+	k8sClient, err := newK8sClient(false)
 	if err != nil {
 		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
 	}
@@ -161,17 +439,24 @@ func runVolumesList(cmd *cobra.Command, args []string) error {
 	options := volumes.AnalysisOptions{
 		Namespace:     volumesNamespace,
 		AllNamespaces: volumesAllNamespaces,
-		MinAge:        parseMinAge(volumesMinAge),
-		MinSize:       volumesMinSize,
+		MinAge:        minAge,
+		MinSize:       minSize,
 		ShowReleased:  volumesShowReleased,
 		ShowOrphaned:  volumesShowOrphaned,
 		ShowAll:       volumesShowAll,
 		ShowDetailed:  volumesShowDetailed,
 		UseColors:     colorOutputEnabled(),
+		Selector:      volumesSelector,
+		StorageClass:  volumesStorageClass,
+		AccessMode:    accessMode,
+		CostRates:     costRates,
+
+		UsageConcurrency: volumesConcurrency,
+		UsageTimeout:     volumesExecTimeout,
 	}
 
 	// Perform analysis
-	ctx := context.Background()
+	ctx := cmd.Context()
 	result, err := analyzer.AnalyzeVolumes(ctx, options)
 	if err != nil {
 		return fmt.Errorf("volume analysis failed: %w", err)
@@ -182,21 +467,53 @@ func runVolumesList(cmd *cobra.Command, args []string) error {
 }
 
 func runVolumesCleanup(cmd *cobra.Command, args []string) error {
+	plan, err := loadCleanupPlan(cmd)
+	if err != nil {
+		return err
+	}
 	if err := applyVolumesDefaults(); err != nil {
 		return err
 	}
+	if volumesExportPlan != "" && !globalFlags.DryRun {
+		return fmt.Errorf("--export-plan records the volumes of a dry run\n\nPlease either:\n- Add --dry-run to export the plan\n- Drop --export-plan")
+	}
 
 	// Validate flags
-	if !volumesDryRun && !volumesConfirm && !volumesForce {
-		return fmt.Errorf("cleanup requires either --dry-run, --confirm, or --force flag\n\nPlease either:\n- Preview changes: --dry-run\n- Confirm deletion: --confirm\n- Force deletion: --force")
+	confirmed := volumesConfirmPhrase != ""
+	if !globalFlags.DryRun && !volumesConfirm && !confirmed && !volumesForce {
+		return fmt.Errorf("cleanup requires either --dry-run, --confirm, --confirm-phrase, or --force flag\n\nPlease either:\n- Preview changes: --dry-run\n- Confirm deletion: --confirm\n- Confirm without a prompt: --confirm-phrase %s\n- Force deletion: --force", cleanupConfirmPhrase())
 	}
 
 	if err := mutuallyExclusive(volumesConfirm, "--confirm", volumesForce, "--force"); err != nil {
 		return err
 	}
+	if err := mutuallyExclusive(confirmed, "--confirm-phrase", volumesForce, "--force"); err != nil {
+		return err
+	}
+
+	if confirmed && volumesConfirmPhrase != cleanupConfirmPhrase() {
+		return fmt.Errorf("confirmation phrase %q does not match the cleanup target\n\nPlease either:\n- Repeat the target: --confirm-phrase %s\n- Preview changes first: --dry-run", volumesConfirmPhrase, cleanupConfirmPhrase())
+	}
+
+	// The prompt would block forever (or read EOF) without a terminal
+	if !globalFlags.DryRun && !volumesForce && !confirmed && !isTerminal(os.Stdin) {
+		return fmt.Errorf("cannot prompt for cleanup confirmation: stdin is not a terminal\n\nPlease either:\n- Confirm without a prompt: --confirm-phrase %s\n- Preview changes: --dry-run", cleanupConfirmPhrase())
+	}
+
+	if err := volumes.ValidatePatterns(append(append([]string{}, volumesInclude...), volumesExclude...)); err != nil {
+		return err
+	}
+	minAge, minSize, err := volumeFilters()
+	if err != nil {
+		return err
+	}
+
+	if volumesProtectHiveMQ && volumesForce {
+		fmt.Println("WARNING: --force overrides --protect-hivemq; volumes of running HiveMQ installations may be deleted.")
+	}
 
 	// Initialize Kubernetes client
-	k8sClient, err := pkg.NewK8sClient(false)
+	k8sClient, err := newK8sClient(false)
 	if err != nil {
 		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
 	}
@@ -208,16 +525,49 @@ func runVolumesCleanup(cmd *cobra.Command, args []string) error {
 	options := volumes.CleanupOptions{
 		Namespace:     volumesNamespace,
 		AllNamespaces: volumesAllNamespaces,
-		MinAge:        parseMinAge(volumesMinAge),
-		MinSize:       volumesMinSize,
-		DryRun:        volumesDryRun,
+		MinAge:        minAge,
+		MinSize:       minSize,
+		DryRun:        globalFlags.DryRun,
 		Force:         volumesForce,
 		UseColors:     true,
+		Include:       volumesInclude,
+		Exclude:       volumesExclude,
+		ProtectHiveMQ: volumesProtectHiveMQ,
+		Confirmed:     confirmed,
+		Selector:      volumesSelector,
+
+		ForceOperatorManaged: volumesForceOperator,
 	}
 
 	// Perform cleanup
-	ctx := context.Background()
-	result, err := cleaner.CleanupVolumes(ctx, options)
+	ctx := cmd.Context()
+	var result *volumes.CleanupResult
+	if plan != nil {
+		result, err = cleaner.ApplyCleanupPlan(ctx, plan, options)
+	} else {
+		result, err = cleaner.CleanupVolumes(ctx, options)
+	}
+	// Only runs that reached the delete phase changed anything worth auditing
+	if !options.DryRun && result != nil && (len(result.Deleted) > 0 || len(result.FailedDeletions) > 0 || err != nil) {
+		record := volumeCleanupAuditRecord(result, options, err)
+		if plan != nil {
+			record.Details["plan"] = volumesPlanFile
+		}
+		recordAudit(ctx, k8sClient, record)
+	}
+	if result != nil {
+		noteDetail("deleted", fmt.Sprint(len(result.Deleted)))
+		noteDetail("failed", fmt.Sprint(len(result.FailedDeletions)))
+		noteDetail("reclaimed", formatBytes(result.TotalReclaimedStorage))
+	}
+	var managed *pkg.OperatorManagedError
+	if errors.As(err, &managed) {
+		return operatorManagedError(err)
+	}
+	var drift *volumes.PlanDriftError
+	if errors.As(err, &drift) {
+		return fmt.Errorf("%w\n\nPlease either:\n- Export and review a new plan: --dry-run --export-plan <file>\n- Inspect the changed volumes: kubectl broker volumes list", err)
+	}
 	if err != nil {
 		return fmt.Errorf("volume cleanup failed: %w", err)
 	}
@@ -225,12 +575,118 @@ func runVolumesCleanup(cmd *cobra.Command, args []string) error {
 	// Display results
 	displayCleanupResults(result, options)
 
+	if volumesExportPlan != "" {
+		if result.Plan == nil {
+			fmt.Println("\nNo volumes to plan; no plan file written.")
+		} else {
+			if err := volumes.WriteCleanupPlan(volumesExportPlan, result.Plan); err != nil {
+				return err
+			}
+			fmt.Printf("\nPlan with %d volumes written to %s; apply it with --plan %s\n", len(result.Plan.Volumes), volumesExportPlan, volumesExportPlan)
+		}
+	}
+
 	return nil
 }
 
+// loadCleanupPlan reads --plan and takes the cleanup scope from it, so the confirmation phrase
+// matches the planned namespace
+func loadCleanupPlan(cmd *cobra.Command) (*volumes.CleanupPlan, error) {
+	if volumesPlanFile == "" {
+		return nil, nil
+	}
+	for _, name := range []string{"namespace", "all-namespaces", "selector", "older-than", "min-size", "include", "exclude", "export-plan"} {
+		if cmd.Flags().Changed(name) {
+			return nil, fmt.Errorf("--plan fixes the volumes to delete and cannot be combined with --%s\n\nPlease either:\n- Drop --%s\n- Export a new plan with it: --dry-run --export-plan <file>", name, name)
+		}
+	}
+
+	plan, err := volumes.ReadCleanupPlan(volumesPlanFile)
+	if err != nil {
+		return nil, err
+	}
+	volumesNamespace = plan.Namespace
+	volumesAllNamespaces = plan.AllNamespaces
+	return plan, nil
+}
+
+func runVolumesAdopt(cmd *cobra.Command, args []string) error {
+	if volumesAllNamespaces {
+		return fmt.Errorf("volumes adopt creates a claim in a single namespace\n\nPlease either:\n- Drop --all-namespaces\n- Specify namespace explicitly: --namespace <namespace>")
+	}
+	if err := applyVolumesDefaults(); err != nil {
+		return err
+	}
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
+	}
+
+	options := volumes.AdoptOptions{
+		PVName:      volumesAdoptPV,
+		Namespace:   volumesNamespace,
+		PVCName:     volumesAdoptPVC,
+		DryRun:      globalFlags.DryRun,
+		BindTimeout: volumesAdoptWait,
+	}
+
+	result, err := volumes.NewAdopter(k8sClient).AdoptVolume(cmd.Context(), options)
+	if err != nil {
+		return pkg.EnhanceError(err, "volume adoption failed")
+	}
+
+	return displayAdoptResult(result)
+}
+
+func runVolumesUnstick(cmd *cobra.Command, args []string) error {
+	if volumesAllNamespaces {
+		return fmt.Errorf("volumes unstick releases a claim in a single namespace\n\nPlease either:\n- Drop --all-namespaces\n- Specify namespace explicitly: --namespace <namespace>")
+	}
+	if err := applyVolumesDefaults(); err != nil {
+		return err
+	}
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
+	}
+
+	options := volumes.UnstickOptions{
+		Namespace: volumesNamespace,
+		PVCName:   volumesUnstickPVC,
+		DryRun:    globalFlags.DryRun,
+		Force:     volumesForce,
+	}
+
+	result, err := volumes.NewUnsticker(k8sClient).Unstick(cmd.Context(), options)
+	// Only runs that reached the patch changed anything worth auditing
+	if !options.DryRun && result != nil {
+		recordAudit(cmd.Context(), k8sClient, volumeUnstickAuditRecord(options, result, err))
+	}
+	if err != nil {
+		return pkg.EnhanceError(err, "failed to release volume claim")
+	}
+
+	return displayUnstickResult(result)
+}
+
+// cleanupConfirmPhrase returns the phrase --confirm-phrase must repeat for the current target
+func cleanupConfirmPhrase() string {
+	if volumesAllNamespaces {
+		return "all-namespaces"
+	}
+	return volumesNamespace
+}
+
 func runVolumesDiscover(cmd *cobra.Command, args []string) error {
+	costRates, err := volumes.ParseCostRates(volumesCostRates)
+	if err != nil {
+		return err
+	}
+
 	// Initialize Kubernetes client
-	k8sClient, err := pkg.NewK8sClient(false)
+	k8sClient, err := newK8sClient(false)
 	if err != nil {
 		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
 	}
@@ -243,12 +699,14 @@ func runVolumesDiscover(cmd *cobra.Command, args []string) error {
 		AllNamespaces: true,
 		ShowAll:       true,
 		UseColors:     true,
+		Selector:      volumesSelector,
+		CostRates:     costRates,
 	}
 
 	fmt.Println("Discovering volumes across cluster...")
 
 	// Perform cluster-wide analysis
-	ctx := context.Background()
+	ctx := cmd.Context()
 	result, err := analyzer.AnalyzeVolumes(ctx, options)
 	if err != nil {
 		return fmt.Errorf("volume discovery failed: %w", err)
@@ -260,30 +718,163 @@ func runVolumesDiscover(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// Helper functions for parsing and display
+func runVolumesReport(cmd *cobra.Command, args []string) error {
+	format := volumesReportFormat()
+	if format == "" {
+		return fmt.Errorf("unsupported output format %q for volumes report\n\nPlease either:\n- Use a structured format: --output json or --output yaml\n- Export for a review: --output csv or --output html", globalFlags.Output)
+	}
+	if err := applyVolumesDefaults(); err != nil {
+		return err
+	}
+	minAge, minSize, err := volumeFilters()
+	if err != nil {
+		return err
+	}
+
+	k8sClient, err := newK8sClient(false)
+	if err != nil {
+		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
+	}
+
+	options := volumes.AnalysisOptions{
+		Namespace:     volumesNamespace,
+		AllNamespaces: volumesAllNamespaces,
+		MinAge:        minAge,
+		MinSize:       minSize,
+		ShowAll:       true,
+		Selector:      volumesSelector,
+	}
+	result, err := volumes.NewAnalyzer(k8sClient).AnalyzeVolumes(cmd.Context(), options)
+	if err != nil {
+		return fmt.Errorf("volume analysis failed: %w", err)
+	}
+
+	return renderVolumesReport(result, options, format)
+}
+
+// volumesReportFormat extends the global output formats with csv and html; "" marks an unknown format
+func volumesReportFormat() string {
+	switch format := strings.ToLower(strings.TrimSpace(globalFlags.Output)); format {
+	case "", "table", "wide":
+		return "table"
+	case "json", "yaml", "csv", "html":
+		return format
+	default:
+		return ""
+	}
+}
 
-func parseMinAge(ageStr string) time.Duration {
-	if ageStr == "" {
-		return 0
+func runVolumesUsage(cmd *cobra.Command, args []string) error {
+	if volumesAllNamespaces {
+		return fmt.Errorf("volumes usage operates on a single StatefulSet\n\nPlease either:\n- Drop --all-namespaces\n- Specify namespace explicitly: --namespace <namespace>")
+	}
+	if volumesUsageWarn > volumesUsageCritical {
+		return fmt.Errorf("--warn-threshold (%.0f) must not exceed --critical-threshold (%.0f)", volumesUsageWarn, volumesUsageCritical)
+	}
+	if volumesUsageWatch && volumesUsageInterval <= 0 {
+		return fmt.Errorf("--interval must be positive, got %s", volumesUsageInterval)
+	}
+	if cmd.Flags().Changed("interval") && !volumesUsageWatch {
+		return fmt.Errorf("--interval only applies to repeated sampling\n\nPlease either:\n- Add --watch to sample every %s\n- Drop --interval for a single snapshot", volumesUsageInterval)
+	}
+	if err := applyVolumesDefaults(); err != nil {
+		return err
 	}
 
-	duration, err := time.ParseDuration(ageStr)
+	statefulSet, _ := applyDefaultStatefulSet(volumesUsageStatefulSet)
+	if volumesSelector != "" {
+		if err := mutuallyExclusive(true, "--selector", volumesUsageStatefulSet != "", "--statefulset"); err != nil {
+			return err
+		}
+		resolved, err := statefulSetFromSelector(cmd.Context(), volumesNamespace, volumesSelector)
+		if err != nil {
+			return err
+		}
+		statefulSet = resolved
+		slog.Info("Using StatefulSet from selector", "statefulset", statefulSet, "selector", volumesSelector)
+	}
+
+	k8sClient, err := newK8sClient(false)
 	if err != nil {
-		// Try parsing with common suffixes
-		if len(ageStr) > 0 {
-			switch ageStr[len(ageStr)-1] {
-			case 'd':
-				if days, parseErr := time.ParseDuration(ageStr[:len(ageStr)-1] + "h"); parseErr == nil {
-					return days * 24
-				}
-			case 'w':
-				if weeks, parseErr := time.ParseDuration(ageStr[:len(ageStr)-1] + "h"); parseErr == nil {
-					return weeks * 24 * 7
-				}
+		return pkg.EnhanceError(err, "failed to initialize Kubernetes client")
+	}
+
+	options := volumes.DefaultPodUsageOptions
+	options.Namespace = volumesNamespace
+	options.StatefulSet = statefulSet
+	options.MountPath = volumesUsagePath
+	options.WarnThreshold = float64(volumesUsageWarn)
+	options.CriticalThreshold = float64(volumesUsageCritical)
+	options.SkipDu = volumesUsageSkipDu
+	options.Concurrency = volumesConcurrency
+	options.Timeout = volumesExecTimeout
+
+	collector := volumes.NewPodUsageCollector(k8sClient)
+	if volumesUsageWatch {
+		return watchVolumesUsage(cmd.Context(), collector, options, volumesUsageInterval)
+	}
+	results, err := collector.CollectStatefulSetUsage(cmd.Context(), options)
+	if err != nil {
+		return pkg.EnhanceError(err, "failed to collect pod disk usage")
+	}
+
+	return displayPodUsage(results, options)
+}
+
+// watchVolumesUsage samples the StatefulSet's disk usage every interval until ctx ends and
+// prints each sample with the growth since the previous and the first sample
+func watchVolumesUsage(ctx context.Context, collector *volumes.PodUsageCollector, options volumes.PodUsageOptions, interval time.Duration) error {
+	tracker := volumes.NewUsageTracker()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for first := true; ; first = false {
+		if !first {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+			}
+			if currentOutputFormat() == "table" {
+				fmt.Println()
 			}
 		}
-		return 0
+
+		sampledAt := time.Now()
+		results, err := collector.CollectStatefulSetUsage(ctx, options)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// Pods may be rescheduled between samples; keep watching
+			slog.Warn("Failed to sample pod disk usage", "statefulset", options.StatefulSet, "error", err)
+			continue
+		}
+		if err := displayPodUsageSample(results, tracker.Observe(sampledAt, results), options, sampledAt); err != nil {
+			return err
+		}
 	}
+}
 
-	return duration
+// Helper functions for parsing and display
+
+// volumeFilters parses --older-than and --min-size
+func volumeFilters() (time.Duration, resource.Quantity, error) {
+	minAge, err := parseDurationFlag("older-than", volumesMinAge)
+	if err != nil {
+		return 0, resource.Quantity{}, err
+	}
+	minSize, err := parseSizeFlag("min-size", volumesMinSize)
+	if err != nil {
+		return 0, resource.Quantity{}, err
+	}
+	return minAge, minSize, nil
+}
+
+// volumesNotifyTarget names the namespace of a volumes command in notifications
+func volumesNotifyTarget() (string, string) {
+	if volumesAllNamespaces {
+		return "all-namespaces", ""
+	}
+	return volumesNamespace, ""
 }
@@ -3,9 +3,13 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/yaml"
 
@@ -30,6 +34,12 @@ var (
 		{Title: "STATUS", Width: 11},
 		{Title: "NAMESPACE", Width: 9},
 	}
+	// volumeWideColumns are appended by --output wide
+	volumeWideColumns = []tableColumn{
+		{Title: "STORAGECLASS", Width: 16},
+		{Title: "ACCESS MODES", Width: 12},
+		{Title: "PODS", Width: 20},
+	}
 )
 
 func displayVolumesList(result *volumes.AnalysisResult, options volumes.AnalysisOptions) error {
@@ -38,12 +48,54 @@ func displayVolumesList(result *volumes.AnalysisResult, options volumes.Analysis
 		return writeStructuredVolumesOutput(result, options, "json")
 	case "yaml":
 		return writeStructuredVolumesOutput(result, options, "yaml")
+	case "csv":
+		return writeVolumesListCSV(result, options)
 	default:
 		displayVolumesListTable(result, options)
 		return nil
 	}
 }
 
+// writeVolumesListCSV lists the volumes of the table with exact sizes; usage cells are empty
+// for volumes without usage data
+func writeVolumesListCSV(result *volumes.AnalysisResult, options volumes.AnalysisOptions) error {
+	w := newCSVWriter("name", "kind", "status", "namespace", "size_bytes", "storage_class", "access_modes", "created_at", "pods", "used_bytes", "available_bytes")
+	accessModes := func(modes []v1.PersistentVolumeAccessMode) string {
+		names := make([]string, 0, len(modes))
+		for _, mode := range modes {
+			names = append(names, string(mode))
+		}
+		return csvList(names)
+	}
+
+	for _, pv := range result.ReleasedPVs {
+		size := pv.Spec.Capacity["storage"]
+		namespace := ""
+		if pv.Spec.ClaimRef != nil {
+			namespace = pv.Spec.ClaimRef.Namespace
+		}
+		w.row(pv.Name, "PersistentVolume", "RELEASED", namespace, size.Value(), pv.Spec.StorageClassName,
+			accessModes(pv.Spec.AccessModes), csvTime(pv.CreationTimestamp.Time), "", "", "")
+	}
+	for _, pvc := range result.OrphanedPVCs {
+		size := pvc.Spec.Resources.Requests["storage"]
+		w.row(pvc.Name, "PersistentVolumeClaim", "ORPHANED", pvc.Namespace, size.Value(), pvcStorageClass(pvc),
+			accessModes(pvc.Spec.AccessModes), csvTime(pvc.CreationTimestamp.Time), "", "", "")
+	}
+	if options.ShowAll || (!options.ShowReleased && !options.ShowOrphaned) {
+		for _, volume := range result.BoundVolumes {
+			size := volume.PVC.Spec.Resources.Requests["storage"]
+			used, available := "", ""
+			if volume.Usage != nil {
+				used, available = fmt.Sprint(volume.Usage.UsedBytes), fmt.Sprint(volume.Usage.AvailableBytes)
+			}
+			w.row(volume.PVC.Name, "PersistentVolumeClaim", "BOUND", volume.Namespace, size.Value(), pvcStorageClass(volume.PVC),
+				accessModes(volume.PVC.Spec.AccessModes), csvTime(volume.PVC.CreationTimestamp.Time), csvList(volume.AssociatedPods), used, available)
+		}
+	}
+	return w.flush()
+}
+
 func displayVolumesListTable(result *volumes.AnalysisResult, options volumes.AnalysisOptions) {
 	totalVolumes := len(result.ReleasedPVs) + len(result.OrphanedPVCs) + len(result.BoundVolumes)
 
@@ -53,95 +105,55 @@ func displayVolumesListTable(result *volumes.AnalysisResult, options volumes.Ana
 		} else {
 			fmt.Printf("No volumes found in namespace: %s\n", options.Namespace)
 		}
+		displayVolumeProblems(result.Problems, options.UseColors)
 		return
 	}
 
+	columns := volumeCompactColumns
 	if options.ShowDetailed {
-		renderTableHeader(volumeDetailedColumns, 2)
-	} else {
-		renderTableHeader(volumeCompactColumns, 2)
+		columns = volumeDetailedColumns
+	}
+	wide := wideOutput()
+	if wide {
+		columns = withColumns(columns, volumeWideColumns...)
+	}
+	table := newTableWriter(columns, 2)
+	table.header()
+
+	// row prints the columns shared by all volume kinds; usage is nil outside detailed mode
+	row := func(name string, size resource.Quantity, usage []string, age time.Duration, status, namespace, storageClass string, accessModes []v1.PersistentVolumeAccessMode, pods []string) {
+		statusCell := getVolumeStatusColor(status, options.UseColors).Sprint(status)
+		cells := []any{truncateString(name, 40), formatStorageSize(size)}
+		if options.ShowDetailed {
+			cells = append(cells, usage[0], usage[1], usage[2])
+		}
+		cells = append(cells, formatDuration(age), statusCell, namespace)
+		if wide {
+			cells = append(cells, valueOrDash(storageClass), formatAccessModes(accessModes), valueOrDash(strings.Join(pods, ",")))
+		}
+		table.row(cells...)
 	}
+	noUsage := []string{"-", "-", "-"}
 
 	for _, pv := range result.ReleasedPVs {
 		age := time.Since(pv.CreationTimestamp.Time).Round(24 * time.Hour)
-		statusColor := getVolumeStatusColor("RELEASED", options.UseColors)
 		namespace := ""
 		if pv.Spec.ClaimRef != nil {
 			namespace = pv.Spec.ClaimRef.Namespace
 		}
-
-		if options.ShowDetailed {
-			used, available, usagePercent := "-", "-", "-"
-
-			fmt.Printf("%-40s  %-7s  %-7s  %-7s  %-6s  %-7s  %s  %s\n",
-				truncateString(pv.Name, 40),
-				formatStorageSize(pv.Spec.Capacity["storage"]),
-				used,
-				available,
-				usagePercent,
-				formatDuration(age),
-				statusColor.Sprint("RELEASED"),
-				namespace)
-		} else {
-			fmt.Printf("%-40s  %-7s  %-7s  %s  %s\n",
-				truncateString(pv.Name, 40),
-				formatStorageSize(pv.Spec.Capacity["storage"]),
-				formatDuration(age),
-				statusColor.Sprint("RELEASED"),
-				namespace)
-		}
+		row(pv.Name, pv.Spec.Capacity["storage"], noUsage, age, "RELEASED", namespace, pv.Spec.StorageClassName, pv.Spec.AccessModes, nil)
 	}
 
 	for _, pvc := range result.OrphanedPVCs {
 		age := time.Since(pvc.CreationTimestamp.Time).Round(24 * time.Hour)
-		statusColor := getVolumeStatusColor("ORPHANED", options.UseColors)
-
-		if options.ShowDetailed {
-			used, available, usagePercent := "-", "-", "-"
-
-			fmt.Printf("%-40s  %-7s  %-7s  %-7s  %-6s  %-7s  %s  %s\n",
-				truncateString(pvc.Name, 40),
-				formatStorageSize(pvc.Spec.Resources.Requests["storage"]),
-				used,
-				available,
-				usagePercent,
-				formatDuration(age),
-				statusColor.Sprint("ORPHANED"),
-				pvc.Namespace)
-		} else {
-			fmt.Printf("%-40s  %-7s  %-7s  %s  %s\n",
-				truncateString(pvc.Name, 40),
-				formatStorageSize(pvc.Spec.Resources.Requests["storage"]),
-				formatDuration(age),
-				statusColor.Sprint("ORPHANED"),
-				pvc.Namespace)
-		}
+		row(pvc.Name, pvc.Spec.Resources.Requests["storage"], noUsage, age, "ORPHANED", pvc.Namespace, pvcStorageClass(pvc), pvc.Spec.AccessModes, nil)
 	}
 
 	if options.ShowAll || (!options.ShowReleased && !options.ShowOrphaned) {
 		for _, volume := range result.BoundVolumes {
-			statusColor := getVolumeStatusColor("BOUND", options.UseColors)
-
-			if options.ShowDetailed {
-				used, available, usagePercent := formatUsageInfo(volume.Usage)
-
-				fmt.Printf("%-40s  %-7s  %-7s  %-7s  %-6s  %-7s  %s  %s\n",
-					truncateString(volume.PVC.Name, 40),
-					formatStorageSize(volume.PVC.Spec.Resources.Requests["storage"]),
-					used,
-					available,
-					usagePercent,
-					formatDuration(volume.Age),
-					statusColor.Sprint("BOUND"),
-					volume.Namespace)
-			} else {
-				fmt.Printf("%-40s  %-7s  %-7s  %s  %s\n",
-					truncateString(volume.PVC.Name, 40),
-					formatStorageSize(volume.PVC.Spec.Resources.Requests["storage"]),
-					formatDuration(volume.Age),
-					statusColor.Sprint("BOUND"),
-					volume.Namespace)
-			}
+			used, available, usagePercent := formatUsageInfo(volume.Usage)
+			row(volume.PVC.Name, volume.PVC.Spec.Resources.Requests["storage"], []string{used, available, usagePercent},
+				volume.Age, "BOUND", volume.Namespace, pvcStorageClass(volume.PVC), volume.PVC.Spec.AccessModes, volume.AssociatedPods)
 		}
 	}
 
@@ -158,6 +170,93 @@ func displayVolumesListTable(result *volumes.AnalysisResult, options volumes.Ana
 	if result.TotalReclaimableStorage > 0 {
 		fmt.Printf("Total reclaimable storage: %s\n", formatBytes(result.TotalReclaimableStorage))
 	}
+
+	if len(options.CostRates) > 0 {
+		fmt.Println()
+		displayStorageClassCosts(result)
+	}
+
+	displayVolumeProblems(result.Problems, options.UseColors)
+}
+
+var volumeProblemColumns = []tableColumn{
+	{Title: "KIND", Width: 4},
+	{Title: "NAME", Width: 40},
+	{Title: "NAMESPACE", Width: 12},
+	{Title: "STATE", Width: 11},
+	{Title: "AGE", Width: 7},
+}
+
+// displayVolumeProblems lists stuck volumes with their root cause and remediation below each row
+func displayVolumeProblems(problems []volumes.VolumeProblem, useColors bool) {
+	if len(problems) == 0 {
+		return
+	}
+
+	fmt.Printf("\nProblems: %d volumes need attention\n\n", len(problems))
+	renderTableHeader(volumeProblemColumns, 2)
+	for _, problem := range problems {
+		kind := "PVC"
+		if problem.Kind == "PersistentVolume" {
+			kind = "PV"
+		}
+		stateCell := fmt.Sprintf("%-11s", problem.State)
+		if useColors {
+			stateCell = volumeProblemColor(problem.State).Sprint(stateCell)
+		}
+		fmt.Printf("%-4s  %-40s  %-12s  %s  %s\n",
+			kind,
+			truncateString(problem.Name, 40),
+			truncateString(valueOrDash(problem.Namespace), 12),
+			stateCell,
+			formatDuration(problem.Age.Round(time.Minute)))
+		fmt.Printf("      Cause: %s\n", problem.Reason)
+		fmt.Printf("      Fix:   %s\n", problem.Remediation)
+	}
+}
+
+func volumeProblemColor(state string) *color.Color {
+	if state == volumes.ProblemFailed {
+		return color.New(color.FgRed, color.Bold)
+	}
+	return color.New(color.FgYellow, color.Bold)
+}
+
+var storageClassCostColumns = []tableColumn{
+	{Title: "STORAGE CLASS", Width: 20},
+	{Title: "VOLUMES", Width: 7},
+	{Title: "TOTAL", Width: 9},
+	{Title: "RECLAIMABLE", Width: 11},
+	{Title: "RATE/GB", Width: 7},
+	{Title: "COST/MONTH", Width: 10},
+	{Title: "SAVINGS/MONTH", Width: 13},
+}
+
+// displayStorageClassCosts renders the per-StorageClass aggregation; classes without a rate show "-"
+func displayStorageClassCosts(result *volumes.AnalysisResult) {
+	renderTableHeader(storageClassCostColumns, 2)
+	priced := false
+	for _, entry := range result.StorageClasses {
+		rate, cost, savings := "-", "-", "-"
+		if entry.Priced {
+			priced = true
+			rate = fmt.Sprintf("%.3f", entry.CostPerGBMonth)
+			cost = fmt.Sprintf("%.2f", entry.MonthlyCost)
+			savings = fmt.Sprintf("%.2f", entry.MonthlySavings)
+		}
+		fmt.Printf("%-20s  %-7d  %-9s  %-11s  %-7s  %-10s  %s\n",
+			truncateString(entry.StorageClass, 20),
+			entry.Volumes,
+			formatBytes(entry.TotalBytes),
+			formatBytes(entry.ReclaimableBytes),
+			rate,
+			cost,
+			savings)
+	}
+	if priced {
+		fmt.Printf("\nEstimated monthly cost: %.2f, estimated savings from cleanup: %.2f\n",
+			result.EstimatedMonthlyCost, result.EstimatedMonthlySavings)
+	}
 }
 
 func writeStructuredVolumesOutput(result *volumes.AnalysisResult, options volumes.AnalysisOptions, format string) error {
@@ -204,6 +303,33 @@ func buildVolumeListStructuredOutput(result *volumes.AnalysisResult, options vol
 		output.Scope.Namespace = ""
 	}
 
+	output.StorageClasses = make([]storageClassEntry, 0, len(result.StorageClasses))
+	for _, entry := range result.StorageClasses {
+		output.StorageClasses = append(output.StorageClasses, storageClassEntry{
+			StorageClass:       entry.StorageClass,
+			Volumes:            entry.Volumes,
+			TotalBytes:         entry.TotalBytes,
+			ReclaimableVolumes: entry.ReclaimableVolumes,
+			ReclaimableBytes:   entry.ReclaimableBytes,
+		})
+	}
+	if len(options.CostRates) > 0 {
+		output.Costs = buildVolumeCostsOutput(result, options.CostRates)
+	}
+
+	output.Problems = make([]volumeProblemEntry, 0, len(result.Problems))
+	for _, problem := range result.Problems {
+		output.Problems = append(output.Problems, volumeProblemEntry{
+			Kind:        problem.Kind,
+			Name:        problem.Name,
+			Namespace:   problem.Namespace,
+			State:       problem.State,
+			Age:         formatDuration(problem.Age.Round(time.Minute)),
+			Reason:      problem.Reason,
+			Remediation: problem.Remediation,
+		})
+	}
+
 	for _, pv := range result.ReleasedPVs {
 		sizeQuantity := pv.Spec.Capacity["storage"]
 		entry := volumeEntry{
@@ -269,6 +395,9 @@ func displayCleanupResults(result *volumes.CleanupResult, options volumes.Cleanu
 		fmt.Printf("- Released PVs eligible: %d\n", result.PlannedReleasedPVs)
 		fmt.Printf("- Orphaned PVCs eligible: %d\n", result.PlannedOrphanedPVCs)
 		fmt.Printf("- Total storage reclaimable: %s\n", formatBytes(result.PlannedReclaimedStorage))
+		if len(result.Protected) > 0 {
+			fmt.Printf("- Protected HiveMQ volumes skipped: %d\n", len(result.Protected))
+		}
 		fmt.Printf("\nUse --confirm to proceed with deletion.\n")
 		return
 	}
@@ -284,6 +413,9 @@ func displayCleanupResults(result *volumes.CleanupResult, options volumes.Cleanu
 	}
 	fmt.Printf("- Orphaned PVCs deleted: %d/%d\n", result.DeletedOrphanedPVCs, result.PlannedOrphanedPVCs)
 	fmt.Printf("- Storage reclaimed: %s\n", formatBytes(result.TotalReclaimedStorage))
+	if len(result.Protected) > 0 {
+		fmt.Printf("- Protected HiveMQ volumes skipped: %d\n", len(result.Protected))
+	}
 
 	if len(result.FailedDeletions) > 0 {
 		fmt.Printf("- Failed deletions: %d\n", len(result.FailedDeletions))
@@ -311,7 +443,19 @@ func displayDiscoverySummary(result *volumes.AnalysisResult) {
 		fmt.Printf("Total reclaimable storage: %s\n", formatBytes(result.TotalReclaimableStorage))
 	}
 
-	fmt.Printf("\nNamespaces with orphaned volumes: %d\n", len(result.NamespaceStats))
+	fmt.Printf("\nNamespaces with orphaned volumes: %d\n", volumes.ReclaimableNamespaces(result))
+
+	if len(result.StorageClasses) > 0 {
+		fmt.Printf("\nStorage classes\n---------------\n")
+		displayStorageClassCosts(result)
+	}
+
+	if len(result.Recommendations) > 0 {
+		fmt.Printf("\nRecommendations\n---------------\n")
+		for _, recommendation := range result.Recommendations {
+			fmt.Printf("- %s\n", recommendation)
+		}
+	}
 }
 
 func getVolumeStatusColor(status string, useColors bool) *color.Color {
@@ -331,6 +475,33 @@ func getVolumeStatusColor(status string, useColors bool) *color.Color {
 	}
 }
 
+// pvcStorageClass returns the StorageClass of a claim, including the legacy beta annotation
+func pvcStorageClass(pvc *v1.PersistentVolumeClaim) string {
+	if pvc.Spec.StorageClassName != nil {
+		return *pvc.Spec.StorageClassName
+	}
+	return pvc.Annotations[v1.BetaStorageClassAnnotation]
+}
+
+// formatAccessModes abbreviates access modes the way kubectl get pvc does
+func formatAccessModes(modes []v1.PersistentVolumeAccessMode) string {
+	short := map[v1.PersistentVolumeAccessMode]string{
+		v1.ReadWriteOnce:    "RWO",
+		v1.ReadOnlyMany:     "ROX",
+		v1.ReadWriteMany:    "RWX",
+		v1.ReadWriteOncePod: "RWOP",
+	}
+	parts := make([]string, 0, len(modes))
+	for _, mode := range modes {
+		if abbreviation, ok := short[mode]; ok {
+			parts = append(parts, abbreviation)
+		} else {
+			parts = append(parts, string(mode))
+		}
+	}
+	return valueOrDash(strings.Join(parts, ","))
+}
+
 func truncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s
@@ -379,13 +550,71 @@ func formatUsageInfo(usage *volumes.VolumeUsage) (string, string, string) {
 }
 
 type volumeListStructuredOutput struct {
-	Scope                  volumeScope   `json:"scope"`
-	Released               []volumeEntry `json:"released"`
-	Orphaned               []volumeEntry `json:"orphaned"`
-	Bound                  []volumeEntry `json:"bound,omitempty"`
-	Summary                volumeSummary `json:"summary"`
-	TotalReclaimableBytes  int64         `json:"totalReclaimableBytes"`
-	TotalReclaimableString string        `json:"totalReclaimable"`
+	Scope                  volumeScope          `json:"scope"`
+	Released               []volumeEntry        `json:"released"`
+	Orphaned               []volumeEntry        `json:"orphaned"`
+	Bound                  []volumeEntry        `json:"bound,omitempty"`
+	Summary                volumeSummary        `json:"summary"`
+	TotalReclaimableBytes  int64                `json:"totalReclaimableBytes"`
+	TotalReclaimableString string               `json:"totalReclaimable"`
+	StorageClasses         []storageClassEntry  `json:"storageClasses"`
+	Costs                  *volumeCostsOutput   `json:"costs,omitempty"`
+	Problems               []volumeProblemEntry `json:"problems"`
+}
+
+type volumeProblemEntry struct {
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace,omitempty"`
+	State       string `json:"state"`
+	Age         string `json:"age"`
+	Reason      string `json:"reason"`
+	Remediation string `json:"remediation"`
+}
+
+type storageClassEntry struct {
+	StorageClass       string `json:"storageClass"`
+	Volumes            int    `json:"volumes"`
+	TotalBytes         int64  `json:"totalBytes"`
+	ReclaimableVolumes int    `json:"reclaimableVolumes"`
+	ReclaimableBytes   int64  `json:"reclaimableBytes"`
+}
+
+type volumeCostsOutput struct {
+	Rates                   map[string]float64  `json:"ratesPerGBMonth"`
+	EstimatedMonthlyCost    float64             `json:"estimatedMonthlyCost"`
+	EstimatedMonthlySavings float64             `json:"estimatedMonthlySavings"`
+	StorageClasses          []storageClassCosts `json:"storageClasses"`
+	UnpricedStorageClasses  []string            `json:"unpricedStorageClasses,omitempty"`
+}
+
+type storageClassCosts struct {
+	StorageClass   string  `json:"storageClass"`
+	CostPerGBMonth float64 `json:"costPerGBMonth"`
+	MonthlyCost    float64 `json:"monthlyCost"`
+	MonthlySavings float64 `json:"monthlySavings"`
+}
+
+func buildVolumeCostsOutput(result *volumes.AnalysisResult, rates volumes.CostRates) *volumeCostsOutput {
+	costs := &volumeCostsOutput{
+		Rates:                   rates,
+		EstimatedMonthlyCost:    result.EstimatedMonthlyCost,
+		EstimatedMonthlySavings: result.EstimatedMonthlySavings,
+		StorageClasses:          []storageClassCosts{},
+	}
+	for _, entry := range result.StorageClasses {
+		if !entry.Priced {
+			costs.UnpricedStorageClasses = append(costs.UnpricedStorageClasses, entry.StorageClass)
+			continue
+		}
+		costs.StorageClasses = append(costs.StorageClasses, storageClassCosts{
+			StorageClass:   entry.StorageClass,
+			CostPerGBMonth: entry.CostPerGBMonth,
+			MonthlyCost:    entry.MonthlyCost,
+			MonthlySavings: entry.MonthlySavings,
+		})
+	}
+	return costs
 }
 
 type volumeScope struct {
@@ -421,3 +650,372 @@ func quantityToBytes(quantity resource.Quantity) int64 {
 	q := quantity
 	return q.Value()
 }
+
+var podUsageColumns = []tableColumn{
+	{Title: "POD", Width: 24},
+	{Title: "SIZE", Width: 9},
+	{Title: "USED", Width: 9},
+	{Title: "AVAIL", Width: 9},
+	{Title: "USE%", Width: 5},
+	{Title: "DATA", Width: 9},
+	{Title: "STATUS", Width: 8},
+}
+
+// podUsageTrendColumns are added in watch mode, before STATUS
+var podUsageTrendColumns = []tableColumn{
+	{Title: "DELTA", Width: 10},
+	{Title: "RATE/H", Width: 10},
+	{Title: "FULL IN", Width: 8},
+}
+
+func displayPodUsage(results []volumes.PodDiskUsage, options volumes.PodUsageOptions) error {
+	return displayPodUsageSample(results, nil, options, time.Time{})
+}
+
+// displayPodUsageSample renders one sample of 'volumes usage'; trends and sampledAt are set in
+// watch mode, where every sample is printed as its own table or document
+func displayPodUsageSample(results []volumes.PodDiskUsage, trends []volumes.UsageTrend, options volumes.PodUsageOptions, sampledAt time.Time) error {
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		return writeStructuredPodUsage(results, trends, options, sampledAt, format)
+	default:
+		displayPodUsageTable(results, trends, options, sampledAt)
+		return nil
+	}
+}
+
+func displayPodUsageTable(results []volumes.PodDiskUsage, trends []volumes.UsageTrend, options volumes.PodUsageOptions, sampledAt time.Time) {
+	if !sampledAt.IsZero() {
+		fmt.Printf("[%s] ", sampledAt.Format("15:04:05"))
+	}
+	fmt.Printf("Disk usage for StatefulSet %s in namespace %s (warn %.0f%%, critical %.0f%%)\n\n",
+		options.StatefulSet, options.Namespace, options.WarnThreshold, options.CriticalThreshold)
+
+	columns := podUsageColumns
+	if trends != nil {
+		columns = append(slices.Clone(columns[:len(columns)-1]), podUsageTrendColumns...)
+		columns = append(columns, podUsageColumns[len(podUsageColumns)-1])
+	}
+	renderTableHeader(columns, 2)
+
+	var failures []volumes.PodDiskUsage
+	for i, usage := range results {
+		statusColor := getPodUsageStatusColor(usage.Status, colorOutputEnabled())
+		trend := ""
+		if trends != nil {
+			trend = formatUsageTrend(trends[i], usage.Error == nil)
+		}
+		if usage.Error != nil {
+			failures = append(failures, usage)
+			fmt.Printf("%-24s  %-9s  %-9s  %-9s  %-5s  %-9s  %s%s\n",
+				truncateString(usage.PodName, 24), "-", "-", "-", "-", "-", trend, statusColor.Sprint(usage.Status))
+			continue
+		}
+
+		data := "-"
+		if usage.DataBytes >= 0 {
+			data = formatBytes(usage.DataBytes)
+		}
+
+		fmt.Printf("%-24s  %-9s  %-9s  %-9s  %-5s  %-9s  %s%s\n",
+			truncateString(usage.PodName, 24),
+			formatBytes(usage.SizeBytes),
+			formatBytes(usage.UsedBytes),
+			formatBytes(usage.AvailableBytes),
+			fmt.Sprintf("%.0f%%", usage.UsagePercent),
+			data,
+			trend,
+			statusColor.Sprint(usage.Status))
+	}
+
+	for _, failure := range failures {
+		fmt.Printf("\n%s: %v", failure.PodName, failure.Error)
+	}
+	if len(failures) > 0 {
+		fmt.Println()
+	}
+}
+
+// formatUsageTrend renders the DELTA, RATE/H and FULL IN cells including their separators
+func formatUsageTrend(trend volumes.UsageTrend, sampled bool) string {
+	delta, rate, fullIn := "-", "-", "-"
+	if sampled && trend.Samples > 1 {
+		delta = formatSignedBytes(trend.DeltaBytes)
+		rate = formatSignedBytes(int64(math.Round(trend.BytesPerHour)))
+		if trend.Growing() {
+			fullIn = formatDays(trend.DaysUntilFull)
+		}
+	}
+	return fmt.Sprintf("%-10s  %-10s  %-8s  ", delta, rate, fullIn)
+}
+
+func formatSignedBytes(bytes int64) string {
+	switch {
+	case bytes > 0:
+		return "+" + formatBytes(bytes)
+	case bytes < 0:
+		return "-" + formatBytes(-bytes)
+	default:
+		return "0 B"
+	}
+}
+
+// formatDays renders a days-until-full projection; far-off projections are capped
+func formatDays(days float64) string {
+	switch {
+	case days >= 365:
+		return ">1y"
+	case days*24 < 1:
+		return "<1h"
+	case days < 1:
+		return fmt.Sprintf("%.0fh", days*24)
+	default:
+		return fmt.Sprintf("%.1fd", days)
+	}
+}
+
+func writeStructuredPodUsage(results []volumes.PodDiskUsage, trends []volumes.UsageTrend, options volumes.PodUsageOptions, sampledAt time.Time, format string) error {
+	payload := podUsageStructuredOutput{
+		Namespace:         options.Namespace,
+		StatefulSet:       options.StatefulSet,
+		WarnThreshold:     options.WarnThreshold,
+		CriticalThreshold: options.CriticalThreshold,
+		Pods:              make([]podUsageEntry, 0, len(results)),
+	}
+	if !sampledAt.IsZero() {
+		payload.SampledAt = &sampledAt
+	}
+
+	for i, usage := range results {
+		entry := podUsageEntry{
+			Pod:            usage.PodName,
+			MountPath:      usage.MountPath,
+			Filesystem:     usage.Filesystem,
+			SizeBytes:      usage.SizeBytes,
+			UsedBytes:      usage.UsedBytes,
+			AvailableBytes: usage.AvailableBytes,
+			UsagePercent:   usage.UsagePercent,
+			Status:         usage.Status,
+		}
+		if usage.DataBytes >= 0 {
+			dataBytes := usage.DataBytes
+			entry.DataBytes = &dataBytes
+		}
+		if usage.Error != nil {
+			entry.Error = usage.Error.Error()
+		}
+		if trends != nil && usage.Error == nil && trends[i].Samples > 1 {
+			trend := trends[i]
+			entry.Trend = &podUsageTrendEntry{Samples: trend.Samples, DeltaBytes: trend.DeltaBytes, BytesPerHour: math.Round(trend.BytesPerHour)}
+			if trend.Growing() {
+				days := math.Round(trend.DaysUntilFull*10) / 10
+				entry.Trend.DaysUntilFull = &days
+			}
+		}
+		payload.Pods = append(payload.Pods, entry)
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+
+	switch format {
+	case "yaml":
+		data, err = yaml.Marshal(payload)
+	default:
+		data, err = json.MarshalIndent(payload, "", "  ")
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to render %s output: %w", format, err)
+	}
+
+	if format == "yaml" && !sampledAt.IsZero() {
+		// Watch mode prints a stream of documents
+		fmt.Println("---")
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func getPodUsageStatusColor(status string, useColors bool) *color.Color {
+	if !useColors {
+		return color.New()
+	}
+
+	switch status {
+	case volumes.PodUsageOK:
+		return color.New(color.FgGreen, color.Bold)
+	case volumes.PodUsageWarning:
+		return color.New(color.FgYellow, color.Bold)
+	default:
+		return color.New(color.FgRed, color.Bold)
+	}
+}
+
+type podUsageStructuredOutput struct {
+	Namespace         string          `json:"namespace"`
+	StatefulSet       string          `json:"statefulSet"`
+	WarnThreshold     float64         `json:"warnThreshold"`
+	CriticalThreshold float64         `json:"criticalThreshold"`
+	SampledAt         *time.Time      `json:"sampledAt,omitempty"`
+	Pods              []podUsageEntry `json:"pods"`
+}
+
+type podUsageEntry struct {
+	Pod            string  `json:"pod"`
+	MountPath      string  `json:"mountPath"`
+	Filesystem     string  `json:"filesystem,omitempty"`
+	SizeBytes      int64   `json:"sizeBytes"`
+	UsedBytes      int64   `json:"usedBytes"`
+	AvailableBytes int64   `json:"availableBytes"`
+	UsagePercent   float64 `json:"usagePercent"`
+	DataBytes      *int64  `json:"dataBytes,omitempty"`
+	Status         string  `json:"status"`
+	Error          string  `json:"error,omitempty"`
+
+	Trend *podUsageTrendEntry `json:"trend,omitempty"`
+}
+
+// podUsageTrendEntry is the growth of a pod's volume in 'volumes usage --watch'
+type podUsageTrendEntry struct {
+	Samples       int      `json:"samples"`
+	DeltaBytes    int64    `json:"deltaBytes"`
+	BytesPerHour  float64  `json:"bytesPerHour"`
+	DaysUntilFull *float64 `json:"daysUntilFull,omitempty"`
+}
+
+type adoptStructuredOutput struct {
+	PV            string   `json:"pv"`
+	Namespace     string   `json:"namespace"`
+	PVC           string   `json:"pvc"`
+	PreviousClaim string   `json:"previousClaim,omitempty"`
+	Size          string   `json:"size"`
+	StorageClass  string   `json:"storageClass,omitempty"`
+	AccessModes   []string `json:"accessModes"`
+	DryRun        bool     `json:"dryRun"`
+	Bound         bool     `json:"bound"`
+}
+
+func displayAdoptResult(result *volumes.AdoptResult) error {
+	payload := adoptStructuredOutput{
+		PV:            result.PVName,
+		Namespace:     result.Namespace,
+		PVC:           result.PVCName,
+		PreviousClaim: result.PreviousClaim,
+		Size:          result.Size.String(),
+		StorageClass:  result.StorageClass,
+		AccessModes:   make([]string, 0, len(result.AccessModes)),
+		DryRun:        result.DryRun,
+		Bound:         result.Bound,
+	}
+	for _, mode := range result.AccessModes {
+		payload.AccessModes = append(payload.AccessModes, string(mode))
+	}
+
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		var (
+			data []byte
+			err  error
+		)
+		if format == "yaml" {
+			data, err = yaml.Marshal(payload)
+		} else {
+			data, err = json.MarshalIndent(payload, "", "  ")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to render %s output: %w", format, err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	previous := result.PreviousClaim
+	if previous == "" {
+		previous = "-"
+	}
+	storageClass := result.StorageClass
+	if storageClass == "" {
+		storageClass = "-"
+	}
+
+	if result.DryRun {
+		fmt.Println("DRY RUN - no changes made")
+	}
+	fmt.Printf("Persistent volume: %s (%s, %s, %s)\n", result.PVName, payload.Size, storageClass, strings.Join(payload.AccessModes, ","))
+	fmt.Printf("Previous claim:    %s\n", previous)
+	fmt.Printf("New claim:         %s/%s\n", result.Namespace, result.PVCName)
+
+	if result.DryRun {
+		fmt.Println("\nRun without --dry-run to patch the volume's claimRef and create the claim.")
+		return nil
+	}
+	if result.Bound {
+		fmt.Printf("\nClaim %s/%s is bound to %s.\n", result.Namespace, result.PVCName, result.PVName)
+	}
+	return nil
+}
+
+type unstickStructuredOutput struct {
+	Namespace  string   `json:"namespace"`
+	PVC        string   `json:"pvc"`
+	Volume     string   `json:"volume,omitempty"`
+	Finalizers []string `json:"finalizers"`
+	Remaining  []string `json:"remaining,omitempty"`
+	DryRun     bool     `json:"dryRun"`
+}
+
+func displayUnstickResult(result *volumes.UnstickResult) error {
+	payload := unstickStructuredOutput{
+		Namespace:  result.Namespace,
+		PVC:        result.PVCName,
+		Volume:     result.VolumeName,
+		Finalizers: result.Finalizers,
+		Remaining:  result.Remaining,
+		DryRun:     result.DryRun,
+	}
+
+	switch format := currentOutputFormat(); format {
+	case "json", "yaml":
+		var (
+			data []byte
+			err  error
+		)
+		if format == "yaml" {
+			data, err = yaml.Marshal(payload)
+		} else {
+			data, err = json.MarshalIndent(payload, "", "  ")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to render %s output: %w", format, err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if result.DryRun {
+		fmt.Println("DRY RUN - no changes made")
+	}
+	fmt.Printf("Claim:      %s/%s\n", result.Namespace, result.PVCName)
+	fmt.Printf("Volume:     %s\n", valueOrDash(result.VolumeName))
+	fmt.Printf("Finalizers: %s\n", strings.Join(result.Finalizers, ", "))
+	if len(result.Remaining) > 0 {
+		fmt.Printf("Remaining:  %s\n", strings.Join(result.Remaining, ", "))
+	}
+
+	if result.DryRun {
+		fmt.Println("\nNo running pod uses the claim. Run without --dry-run to remove the finalizer.")
+		return nil
+	}
+	if len(result.Remaining) > 0 {
+		fmt.Printf("\nRemoved the finalizer; claim %s/%s is deleted once its other controllers release it.\n", result.Namespace, result.PVCName)
+		return nil
+	}
+	fmt.Printf("\nRemoved the finalizer; Kubernetes now deletes claim %s/%s.\n", result.Namespace, result.PVCName)
+	if result.VolumeName != "" {
+		fmt.Printf("Volume %s is reclaimed according to its reclaim policy.\n", result.VolumeName)
+	}
+	return nil
+}
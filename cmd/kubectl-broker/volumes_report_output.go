@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"sigs.k8s.io/yaml"
+
+	"kubectl-broker/pkg/volumes"
+)
+
+var (
+	volumesReportColumns = []tableColumn{
+		{Title: "NAMESPACE", Width: 36},
+		{Title: "PVCS", Width: 4},
+		{Title: "BOUND", Width: 5},
+		{Title: "ORPHANED", Width: 8},
+		{Title: "RELEASED", Width: 8},
+		{Title: "RECLAIMABLE", Width: 11},
+		{Title: "HIVEMQ", Width: 6},
+	}
+	// volumesReportWideColumns are appended by --output wide
+	volumesReportWideColumns = []tableColumn{
+		{Title: "HIVEMQ VOLUMES", Width: 14},
+		{Title: "NAMESPACE STATE", Width: 15},
+	}
+)
+
+type volumesReportPayload struct {
+	GeneratedAt      time.Time           `json:"generatedAt" yaml:"generatedAt"`
+	Scope            string              `json:"scope" yaml:"scope"`
+	Namespaces       int                 `json:"namespaces" yaml:"namespaces"`
+	ReclaimableBytes int64               `json:"reclaimableBytes" yaml:"reclaimableBytes"`
+	Items            []volumesReportItem `json:"items" yaml:"items"`
+}
+
+type volumesReportItem struct {
+	Namespace        string `json:"namespace" yaml:"namespace"`
+	TotalPVCs        int    `json:"totalPVCs" yaml:"totalPVCs"`
+	BoundPVCs        int    `json:"boundPVCs" yaml:"boundPVCs"`
+	OrphanedPVCs     int    `json:"orphanedPVCs" yaml:"orphanedPVCs"`
+	ReleasedPVs      int    `json:"releasedPVs" yaml:"releasedPVs"`
+	ReclaimableBytes int64  `json:"reclaimableBytes" yaml:"reclaimableBytes"`
+	HiveMQ           bool   `json:"hivemq" yaml:"hivemq"`
+	HiveMQVolumes    int    `json:"hivemqVolumes" yaml:"hivemqVolumes"`
+	NamespaceExists  bool   `json:"namespaceExists" yaml:"namespaceExists"`
+}
+
+func newVolumesReportPayload(result *volumes.AnalysisResult, options volumes.AnalysisOptions) volumesReportPayload {
+	scope := options.Namespace
+	if options.AllNamespaces {
+		scope = "all-namespaces"
+	}
+	report := volumes.NamespaceReport(result)
+	payload := volumesReportPayload{
+		GeneratedAt: time.Now().UTC(),
+		Scope:       scope,
+		Namespaces:  len(report),
+		Items:       make([]volumesReportItem, 0, len(report)),
+	}
+	for _, stats := range report {
+		payload.ReclaimableBytes += stats.TotalReclaimable
+		payload.Items = append(payload.Items, volumesReportItem{
+			Namespace:        stats.Namespace,
+			TotalPVCs:        stats.TotalPVCs,
+			BoundPVCs:        stats.BoundPVCs,
+			OrphanedPVCs:     stats.OrphanedPVCs,
+			ReleasedPVs:      stats.ReleasedPVs,
+			ReclaimableBytes: stats.TotalReclaimable,
+			HiveMQ:           stats.HasHiveMQ(),
+			HiveMQVolumes:    stats.HiveMQVolumes,
+			NamespaceExists:  stats.NamespaceExists,
+		})
+	}
+	return payload
+}
+
+func renderVolumesReport(result *volumes.AnalysisResult, options volumes.AnalysisOptions, format string) error {
+	payload := newVolumesReportPayload(result, options)
+	switch format {
+	case "json", "yaml":
+		return writeStructuredVolumesReport(payload, format)
+	case "csv":
+		return writeVolumesReportCSV(payload)
+	case "html":
+		return renderVolumesReportHTML(payload)
+	}
+
+	if len(payload.Items) == 0 {
+		if options.AllNamespaces {
+			fmt.Println("No volumes found across cluster.")
+		} else {
+			fmt.Printf("No volumes found in namespace: %s\n", options.Namespace)
+		}
+		return nil
+	}
+
+	columns := volumesReportColumns
+	wide := wideOutput()
+	if wide {
+		columns = withColumns(columns, volumesReportWideColumns...)
+	}
+	table := newTableWriter(columns, 2)
+	table.header()
+	useColors := colorOutputEnabled()
+	for _, item := range payload.Items {
+		reclaimable := "-"
+		if item.ReclaimableBytes > 0 {
+			reclaimable = reclaimableColor(useColors).Sprint(formatBytes(item.ReclaimableBytes))
+		}
+		cells := []any{
+			truncateString(item.Namespace, 36),
+			item.TotalPVCs,
+			item.BoundPVCs,
+			item.OrphanedPVCs,
+			item.ReleasedPVs,
+			reclaimable,
+			yesNo(item.HiveMQ),
+		}
+		if wide {
+			cells = append(cells, item.HiveMQVolumes, namespaceState(item.NamespaceExists))
+		}
+		table.row(cells...)
+	}
+
+	fmt.Printf("\nSummary: %s reclaimable in %d of %d namespaces\n",
+		formatBytes(payload.ReclaimableBytes), volumes.ReclaimableNamespaces(result), payload.Namespaces)
+	return nil
+}
+
+func reclaimableColor(useColors bool) *color.Color {
+	if !useColors {
+		return color.New()
+	}
+	return color.New(color.FgYellow)
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+// namespaceState tells namespaces that still exist from those only referenced by released volumes
+func namespaceState(exists bool) string {
+	if exists {
+		return "active"
+	}
+	return "deleted"
+}
+
+func writeStructuredVolumesReport(payload volumesReportPayload, format string) error {
+	var (
+		data []byte
+		err  error
+	)
+
+	switch format {
+	case "yaml":
+		data, err = yaml.Marshal(payload)
+	default:
+		data, err = json.MarshalIndent(payload, "", "  ")
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to render %s output: %w", format, err)
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
+
+func writeVolumesReportCSV(payload volumesReportPayload) error {
+	w := newCSVWriter("namespace", "total_pvcs", "bound_pvcs", "orphaned_pvcs", "released_pvs", "reclaimable_bytes", "hivemq", "hivemq_volumes", "namespace_exists")
+	for _, item := range payload.Items {
+		w.row(item.Namespace, item.TotalPVCs, item.BoundPVCs, item.OrphanedPVCs, item.ReleasedPVs,
+			item.ReclaimableBytes, item.HiveMQ, item.HiveMQVolumes, item.NamespaceExists)
+	}
+	return w.flush()
+}
+
+func renderVolumesReportHTML(payload volumesReportPayload) error {
+	if err := volumesReportTemplate.Execute(os.Stdout, payload); err != nil {
+		return fmt.Errorf("failed to render html output: %w", err)
+	}
+	return nil
+}
+
+var volumesReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"size":  formatBytes,
+	"yesNo": yesNo,
+	"state": namespaceState,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>HiveMQ volume report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; }
+th, td { padding: 0.4em 0.8em; border-bottom: 1px solid #ddd; text-align: left; }
+th { background: #f4f4f4; }
+td.number { text-align: right; }
+.reclaimable { color: #9a6700; font-weight: bold; }
+.deleted { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>HiveMQ volume report</h1>
+<p>Generated {{.GeneratedAt.Format "2006-01-02 15:04 UTC"}} &middot; {{.Scope}} &middot; {{size .ReclaimableBytes}} reclaimable in {{.Namespaces}} namespaces</p>
+<table>
+<tr><th>Namespace</th><th>PVCs</th><th>Bound</th><th>Orphaned</th><th>Released</th><th>Reclaimable</th><th>HiveMQ</th><th>Namespace state</th></tr>
+{{- range .Items}}
+<tr>
+<td>{{.Namespace}}</td><td class="number">{{.TotalPVCs}}</td><td class="number">{{.BoundPVCs}}</td><td class="number">{{.OrphanedPVCs}}</td><td class="number">{{.ReleasedPVs}}</td>
+{{- if .ReclaimableBytes}}
+<td class="number reclaimable">{{size .ReclaimableBytes}}</td>
+{{- else}}
+<td class="number">-</td>
+{{- end}}
+<td>{{yesNo .HiveMQ}}</td><td class="{{state .NamespaceExists}}">{{state .NamespaceExists}}</td>
+</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
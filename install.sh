@@ -8,6 +8,7 @@ set -e
 INSTALL_DIR="$HOME/.kubectl-broker"
 BINARY_NAME="kubectl-broker"
 PULSE_LINK="kubectl-pulse"
+EDGE_LINK="kubectl-edge"
 SHELL_RC=""
 
 echo "Installing kubectl-broker and kubectl-pulse as kubectl plugins..."
@@ -33,6 +34,8 @@ chmod +x "$INSTALL_DIR/$BINARY_NAME"
 echo "Creating symlink for kubectl-pulse..."
 ln -sf "$BINARY_NAME" "$INSTALL_DIR/$PULSE_LINK"
 echo "Created symlink: $PULSE_LINK -> $BINARY_NAME"
+ln -sf "$BINARY_NAME" "$INSTALL_DIR/$EDGE_LINK"
+echo "Created symlink: $EDGE_LINK -> $BINARY_NAME"
 
 # Detect shell and RC file
 if [ -n "$ZSH_VERSION" ]; then
@@ -79,12 +82,19 @@ else
     exit 1
 fi
 
+if "$INSTALL_DIR/$EDGE_LINK" --help > /dev/null 2>&1; then
+    echo "kubectl-edge symlink is working"
+else
+    echo "kubectl-edge symlink test failed"
+    exit 1
+fi
+
 echo ""
 echo "Installation complete!"
 echo ""
 echo "Next steps:"
 echo "1. Restart your terminal or run: source $SHELL_RC"
-echo "2. Verify installation: kubectl plugin list | grep -E '(broker|pulse)'"
+echo "2. Verify installation: kubectl plugin list | grep -E '(broker|pulse|edge)'"
 echo "3. Test the plugins: kubectl broker --help && kubectl pulse --help"
 echo "4. Discover HiveMQ brokers: kubectl broker status --discover"
 echo "5. Discover HiveMQ Pulse servers: kubectl pulse status --discover"
@@ -99,4 +109,8 @@ echo "Pulse usage examples:"
 echo "   kubectl pulse status                                        # Check Pulse servers"
 echo "   kubectl pulse status --discover                             # Find all Pulse servers"
 echo "   kubectl pulse status --namespace pulse-namespace"
-echo "   kubectl pulse status --endpoint readiness"
\ No newline at end of file
+echo "   kubectl pulse status --endpoint readiness"
+echo ""
+echo "Edge usage examples:"
+echo "   kubectl edge status --namespace edge                        # Check Edge gateways"
+echo "   kubectl edge adapters --namespace edge                      # List protocol adapters"
\ No newline at end of file
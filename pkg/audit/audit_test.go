@@ -0,0 +1,84 @@
+package audit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestStoreAppendAndLoadFiltersRecords(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore returned error: %v", err)
+	}
+
+	now := time.Now().UTC()
+	records := []Record{
+		{Timestamp: now.Add(-48 * time.Hour), Operation: OperationBackupRestore, Namespace: "prod", Outcome: OutcomeSucceeded},
+		{Timestamp: now.Add(-time.Hour), Operation: OperationVolumeCleanup, Outcome: OutcomePartial,
+			Resources: []Resource{{Kind: "PersistentVolumeClaim", Namespace: "prod", Name: "data-broker-3"}}},
+		{Timestamp: now.Add(-time.Hour), Operation: OperationBackupRestore, Namespace: "staging", Outcome: OutcomeFailed},
+	}
+	for _, record := range records {
+		if err := store.Append(record); err != nil {
+			t.Fatalf("Append returned error: %v", err)
+		}
+	}
+
+	loaded, err := store.Load(Filter{Since: now.Add(-24 * time.Hour), Namespace: "prod"})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].Operation != OperationVolumeCleanup {
+		t.Fatalf("expected the cleanup touching prod, got %+v", loaded)
+	}
+
+	loaded, err = store.Load(Filter{Operation: OperationBackupRestore})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("expected both restores, got %+v", loaded)
+	}
+}
+
+func TestOutcomeFor(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		succeeded, failed int
+		err               error
+		want              Outcome
+	}{
+		{succeeded: 3, want: OutcomeSucceeded},
+		{succeeded: 2, failed: 1, want: OutcomePartial},
+		{failed: 1, want: OutcomeFailed},
+		{err: errors.New("restore failed"), want: OutcomeFailed},
+	}
+	for _, tc := range cases {
+		if got := OutcomeFor(tc.succeeded, tc.failed, tc.err); got != tc.want {
+			t.Errorf("OutcomeFor(%d, %d, %v) = %s, want %s", tc.succeeded, tc.failed, tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestNewEventPlacesClusterScopedObjectsInDefaultNamespace(t *testing.T) {
+	t.Parallel()
+
+	record := Record{Operation: OperationVolumeCleanup, User: "alice", KubeContext: "prod", Outcome: OutcomePartial}
+	event := newEvent(record, Resource{Kind: "PersistentVolume", Name: "pvc-123", Error: "forbidden"})
+
+	if event.Namespace != clusterEventNamespace || event.InvolvedObject.Namespace != "" {
+		t.Errorf("unexpected namespaces: event %q, object %q", event.Namespace, event.InvolvedObject.Namespace)
+	}
+	if event.Reason != "KubectlBrokerVolumesCleanup" || event.Type != v1.EventTypeWarning {
+		t.Errorf("unexpected reason or type: %s %s", event.Reason, event.Type)
+	}
+	if event.Message != "volumes.cleanup by alice via context prod: failed: forbidden" {
+		t.Errorf("unexpected message: %s", event.Message)
+	}
+}
@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// eventSource identifies the plugin as reporting component of emitted Events
+const eventSource = "kubectl-broker"
+
+// clusterEventNamespace holds Events about cluster-scoped objects, as kubectl does
+const clusterEventNamespace = metav1.NamespaceDefault
+
+// EmitEvents creates one Kubernetes Event per affected resource so the operation is visible
+// with 'kubectl get events' next to the objects it changed. Deleted objects keep their Events
+// until the cluster's event TTL expires.
+func EmitEvents(ctx context.Context, events corev1client.EventsGetter, record Record) error {
+	var errs []error
+	for _, resource := range record.Resources {
+		event := newEvent(record, resource)
+		if _, err := events.Events(event.Namespace).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", resource.Kind, resource.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func newEvent(record Record, resource Resource) *v1.Event {
+	namespace := resource.Namespace
+	if namespace == "" {
+		namespace = clusterEventNamespace
+	}
+
+	eventType := v1.EventTypeNormal
+	if record.Outcome != OutcomeSucceeded || resource.Error != "" {
+		eventType = v1.EventTypeWarning
+	}
+
+	timestamp := metav1.NewTime(record.Timestamp)
+	return &v1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "kubectl-broker-",
+			Namespace:    namespace,
+		},
+		InvolvedObject: v1.ObjectReference{
+			Kind:      resource.Kind,
+			Namespace: resource.Namespace,
+			Name:      resource.Name,
+		},
+		Reason:              eventReason(record.Operation),
+		Message:             eventMessage(record, resource),
+		Type:                eventType,
+		Source:              v1.EventSource{Component: eventSource},
+		ReportingController: eventSource,
+		ReportingInstance:   record.LocalUser,
+		FirstTimestamp:      timestamp,
+		LastTimestamp:       timestamp,
+		Count:               1,
+	}
+}
+
+// eventReason turns an operation like "volumes.cleanup" into "KubectlBrokerVolumesCleanup"
+func eventReason(operation string) string {
+	var reason strings.Builder
+	reason.WriteString("KubectlBroker")
+	for _, part := range strings.FieldsFunc(operation, func(r rune) bool { return r == '.' || r == '-' }) {
+		reason.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return reason.String()
+}
+
+func eventMessage(record Record, resource Resource) string {
+	message := fmt.Sprintf("%s by %s via context %s", record.Operation, orUnknown(record.User), orUnknown(record.KubeContext))
+	switch {
+	case resource.Error != "":
+		message += ": failed: " + resource.Error
+	case record.Error != "":
+		message += ": " + strings.ToLower(string(record.Outcome)) + ": " + record.Error
+	default:
+		message += ": " + strings.ToLower(string(record.Outcome))
+	}
+	return message
+}
+
+func orUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
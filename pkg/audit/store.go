@@ -0,0 +1,155 @@
+package audit
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"time"
+
+	"kubectl-broker/pkg/statedir"
+)
+
+// DirEnvVar overrides the default audit directory
+const DirEnvVar = "KUBECTL_BROKER_AUDIT_DIR"
+
+const auditFileName = "audit.jsonl"
+
+// Operations recorded in the audit trail
+const (
+	OperationVolumeCleanup = "volumes.cleanup"
+	OperationBackupRestore = "backup.restore"
+	OperationVolumeUnstick = "volumes.unstick"
+	OperationClusterScale  = "cluster.scale"
+	OperationTrafficResume = "backup.resume-traffic"
+
+	OperationExtensionEnable  = "extensions.enable"
+	OperationExtensionDisable = "extensions.disable"
+)
+
+// Outcome summarizes how a destructive operation ended
+type Outcome string
+
+const (
+	OutcomeSucceeded Outcome = "SUCCEEDED"
+	OutcomePartial   Outcome = "PARTIAL"
+	OutcomeFailed    Outcome = "FAILED"
+)
+
+// Resource is a Kubernetes object affected by an operation. Namespace is empty for
+// cluster-scoped objects such as PersistentVolumes.
+type Resource struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Record is a single audited invocation of a destructive operation
+type Record struct {
+	Timestamp   time.Time         `json:"timestamp"`
+	Operation   string            `json:"operation"`
+	User        string            `json:"user,omitempty"`      // kubeconfig user of the context
+	LocalUser   string            `json:"localUser,omitempty"` // operating system account running the plugin
+	KubeContext string            `json:"kubeContext,omitempty"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Resources   []Resource        `json:"resources"`
+	Outcome     Outcome           `json:"outcome"`
+	Error       string            `json:"error,omitempty"`
+	RequestID   string            `json:"requestId,omitempty"` // X-Request-Id of the HiveMQ API requests
+	Details     map[string]string `json:"details,omitempty"`
+}
+
+// OutcomeFor derives the outcome from the number of affected and failed resources
+// and the error that ended the operation
+func OutcomeFor(succeeded, failed int, err error) Outcome {
+	switch {
+	case err == nil && failed == 0:
+		return OutcomeSucceeded
+	case succeeded > 0:
+		return OutcomePartial
+	default:
+		return OutcomeFailed
+	}
+}
+
+// Filter selects records when loading the audit trail
+type Filter struct {
+	Since     time.Time
+	Namespace string
+	Operation string
+}
+
+// Store persists audit records as JSON lines in a local directory
+type Store struct {
+	dir string
+}
+
+// DefaultDir returns the audit directory, honoring KUBECTL_BROKER_AUDIT_DIR
+func DefaultDir() (string, error) {
+	return statedir.Dir(DirEnvVar, "audit", "the audit log")
+}
+
+// NewStore creates a store rooted at dir (DefaultDir when empty)
+func NewStore(dir string) (*Store, error) {
+	if dir == "" {
+		var err error
+		dir, err = DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Path returns the file backing the store
+func (s *Store) Path() string {
+	return filepath.Join(s.dir, auditFileName)
+}
+
+// Append records an operation at the end of the audit log
+func (s *Store) Append(record Record) error {
+	return s.file().Append(record)
+}
+
+// file is private to the user: records name clusters, users and affected resources
+func (s *Store) file() statedir.JSONLines {
+	return statedir.JSONLines{Path: s.Path(), Label: "audit log", DirMode: 0o700, FileMode: 0o600}
+}
+
+// Load returns the records matching the filter in chronological order.
+// Malformed lines are skipped so a partially written record does not break the export.
+func (s *Store) Load(filter Filter) ([]Record, error) {
+	var records []Record
+	err := s.file().Scan(func(line []byte) {
+		var record Record
+		if err := json.Unmarshal(line, &record); err != nil {
+			return
+		}
+		if !filter.Since.IsZero() && record.Timestamp.Before(filter.Since) {
+			return
+		}
+		if filter.Namespace != "" && !record.touchesNamespace(filter.Namespace) {
+			return
+		}
+		if filter.Operation != "" && record.Operation != filter.Operation {
+			return
+		}
+		records = append(records, record)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// touchesNamespace reports whether the operation targeted the namespace or affected a resource in it
+func (r Record) touchesNamespace(namespace string) bool {
+	if r.Namespace == namespace {
+		return true
+	}
+	for _, resource := range r.Resources {
+		if resource.Namespace == namespace {
+			return true
+		}
+	}
+	return false
+}
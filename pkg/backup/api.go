@@ -0,0 +1,89 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"kubectl-broker/pkg"
+)
+
+// APIResponse is the unmodified answer of a raw management API request
+type APIResponse struct {
+	Proto      string      `json:"proto"`
+	Status     string      `json:"status"` // status line text, e.g. "200 OK"
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+}
+
+// ValidateAPIRequest normalizes the method and checks that path is an absolute API path
+func ValidateAPIRequest(method, path string) (string, error) {
+	method = strings.ToUpper(strings.TrimSpace(method))
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+	default:
+		return "", fmt.Errorf("unsupported HTTP method %q\n\nPlease either:\n- Use one of GET, HEAD, POST, PUT, PATCH, DELETE, OPTIONS\n- Example: kubectl broker api GET /api/v1/management/backups", method)
+	}
+	if !strings.HasPrefix(path, "/") {
+		return "", fmt.Errorf("API path %q must start with '/'\n\nPlease either:\n- Pass the path from the server root: /api/v1/management/backups\n- Check the management API reference for the endpoint path", path)
+	}
+	return method, nil
+}
+
+// IsReadOnlyMethod reports whether requests with method do not change server state
+func IsReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// SendAPIRequest performs a raw request against the management API reached through dialer,
+// honoring the credentials, TLS and retry settings of options. Only read-only requests are
+// retried, and requests that may change state are refused in a dry-run context. Error statuses
+// are returned as a response, not as an error.
+func SendAPIRequest(ctx context.Context, dialer pkg.PortForwardDialer, options BackupOptions, method, path string, body []byte) (*APIResponse, error) {
+	if !IsReadOnlyMethod(method) {
+		if err := pkg.GuardMutation(ctx, method, path); err != nil {
+			return nil, err
+		}
+	}
+
+	var response *APIResponse
+	err := newManagementEngine(dialer, options).withClient(ctx, func(client *Client) error {
+		if options.RequestTimeout > 0 {
+			client.SetTimeout(options.RequestTimeout)
+		}
+		if !IsReadOnlyMethod(method) {
+			client.SetRetryPolicy(RetryPolicy{})
+		}
+
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		resp, err := client.makeRequest(method, path, reader)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+		response = &APIResponse{
+			Proto:      resp.Proto,
+			Status:     resp.Status,
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       data,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
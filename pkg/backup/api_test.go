@@ -0,0 +1,66 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"kubectl-broker/pkg"
+)
+
+func TestSendAPIRequest(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "admin" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if r.Method == http.MethodPost && string(body) != `{"x":1}` {
+			t.Errorf("unexpected body: %s", body)
+		}
+		w.Header().Set("X-Test", r.Method+" "+r.URL.RequestURI())
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte(`{"raw":true}`))
+	}))
+	defer server.Close()
+
+	var forwards int
+	options := BackupOptions{Username: "admin", Password: "secret"}
+	resp, err := SendAPIRequest(context.Background(), localDialer(t, server.URL, &forwards), options, http.MethodPost, "/api/v1/custom?limit=1", []byte(`{"x":1}`))
+	if err != nil {
+		t.Fatalf("SendAPIRequest() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusTeapot || string(resp.Body) != `{"raw":true}` {
+		t.Fatalf("unexpected response: %d %s", resp.StatusCode, resp.Body)
+	}
+	if got := resp.Header.Get("X-Test"); got != "POST /api/v1/custom?limit=1" {
+		t.Errorf("request reached server as %q", got)
+	}
+
+	dryRun := pkg.WithDryRun(context.Background())
+	if _, err := SendAPIRequest(dryRun, localDialer(t, server.URL, &forwards), options, http.MethodDelete, "/api/v1/custom", nil); !errors.Is(err, pkg.ErrDryRun) {
+		t.Errorf("DELETE in dry run = %v, want ErrDryRun", err)
+	}
+	if _, err := SendAPIRequest(dryRun, localDialer(t, server.URL, &forwards), options, http.MethodGet, "/api/v1/custom", nil); err != nil {
+		t.Errorf("GET in dry run = %v, want nil", err)
+	}
+}
+
+func TestValidateAPIRequest(t *testing.T) {
+	t.Parallel()
+
+	if method, err := ValidateAPIRequest("get", "/api/v1/management/backups"); err != nil || method != http.MethodGet {
+		t.Errorf("ValidateAPIRequest(get) = %q, %v", method, err)
+	}
+	if _, err := ValidateAPIRequest("FETCH", "/api"); err == nil {
+		t.Error("expected an error for an unknown method")
+	}
+	if _, err := ValidateAPIRequest("GET", "api/v1"); err == nil {
+		t.Error("expected an error for a relative path")
+	}
+}
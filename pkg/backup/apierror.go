@@ -0,0 +1,129 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/tracing"
+)
+
+// maxErrorBody bounds how much of an error response is read
+const maxErrorBody = 64 << 10
+
+// APIError is a failed management API request with the error details the broker returned
+type APIError struct {
+	StatusCode int
+	Title      string
+	Detail     string
+	RequestID  string
+	// Body is the raw response when it carried no structured error
+	Body string
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "HTTP %d", e.StatusCode)
+	switch {
+	case e.Title != "" && e.Detail != "":
+		fmt.Fprintf(&b, ": %s: %s", e.Title, e.Detail)
+	case e.Title != "" || e.Detail != "":
+		fmt.Fprintf(&b, ": %s", e.Title+e.Detail)
+	case e.Body != "":
+		fmt.Fprintf(&b, ": %s", e.Body)
+	default:
+		fmt.Fprintf(&b, " %s", http.StatusText(e.StatusCode))
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request ID %s)", e.RequestID)
+	}
+	return b.String()
+}
+
+// AsAPIError returns the APIError in err's chain
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	ok := errors.As(err, &apiErr)
+	return apiErr, ok
+}
+
+// IsStatus reports whether err is an APIError with the given HTTP status
+func IsStatus(err error, status int) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.StatusCode == status
+}
+
+// ParseAPIError builds an APIError from a response status, body and request ID header. Every
+// entry of an errors list is kept; their titles and details are joined in order.
+func ParseAPIError(status int, body []byte, requestID string) *APIError {
+	apiErr := &APIError{StatusCode: status, RequestID: requestID}
+
+	var payload ErrorResponse
+	if err := json.Unmarshal(body, &payload); err != nil {
+		apiErr.Body = strings.TrimSpace(string(body))
+		return apiErr
+	}
+	if payload.RequestID != "" {
+		apiErr.RequestID = payload.RequestID
+	}
+
+	var titles, details []string
+	for _, item := range payload.Errors {
+		titles = appendDistinct(titles, item.Title)
+		details = appendDistinct(details, item.Detail)
+	}
+	titles = appendDistinct(titles, payload.Title)
+	details = appendDistinct(details, payload.Detail)
+	apiErr.Title = strings.Join(titles, "; ")
+	apiErr.Detail = strings.Join(details, "; ")
+
+	if apiErr.Title == "" && apiErr.Detail == "" {
+		switch {
+		case payload.Message != "":
+			apiErr.Detail = payload.Message
+		case payload.Error != "":
+			apiErr.Detail = payload.Error
+		default:
+			apiErr.Body = strings.TrimSpace(string(body))
+		}
+	}
+	return apiErr
+}
+
+func appendDistinct(values []string, value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return values
+	}
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// handleErrorResponse reads a non-success response into an APIError wrapped in the AppError
+// taxonomy, so callers can match on the status and the server's explanation reaches the user
+func (c *Client) handleErrorResponse(resp *http.Response) error {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if err != nil {
+		return fmt.Errorf("HTTP %d: failed to read error response: %w", resp.StatusCode, err)
+	}
+	// Brokers that do not echo a request ID are still correlated by the one the plugin sent
+	requestID := resp.Header.Get(tracing.RequestIDHeader)
+	if requestID == "" && resp.Request != nil {
+		requestID = resp.Request.Header.Get(tracing.RequestIDHeader)
+	}
+	apiErr := ParseAPIError(resp.StatusCode, body, requestID)
+
+	resource := ""
+	if resp.Request != nil && resp.Request.URL != nil {
+		resource = resp.Request.Method + " " + resp.Request.URL.Path
+	}
+	return pkg.NewAPIError("management_api", resource, apiErr)
+}
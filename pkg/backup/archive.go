@@ -0,0 +1,148 @@
+package backup
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Suffixes of the metadata files written next to a downloaded archive
+const (
+	ChecksumSuffix = ".sha256"
+	ManifestSuffix = ".manifest.json"
+)
+
+// Download sources recorded in an archive manifest
+const (
+	ArchiveSourceManagement = "management"
+	ArchiveSourceRemote     = "remote"
+)
+
+// ArchiveManifest describes a downloaded backup archive, so it can be identified and verified
+// after it has been moved to cold storage
+type ArchiveManifest struct {
+	BackupID     string     `json:"backupId"`
+	Archive      string     `json:"archive"`
+	Context      string     `json:"context,omitempty"`
+	Namespace    string     `json:"namespace"`
+	StatefulSet  string     `json:"statefulSet"`
+	CreatedAt    *time.Time `json:"createdAt,omitempty"`
+	DownloadedAt time.Time  `json:"downloadedAt"`
+	SizeBytes    int64      `json:"sizeBytes"`
+	SHA256       string     `json:"sha256"`
+	Source       string     `json:"source"`
+	Endpoint     string     `json:"endpoint"`
+}
+
+// ChecksumResult is the outcome of verifying an archive against its checksum file
+type ChecksumResult struct {
+	File         string `json:"file"`
+	ChecksumFile string `json:"checksumFile"`
+	Expected     string `json:"expected"`
+	Actual       string `json:"actual"`
+	SizeBytes    int64  `json:"sizeBytes"`
+	OK           bool   `json:"ok"`
+}
+
+// WriteArchiveMetadata hashes the archive and writes a sha256sum-compatible checksum file and a
+// JSON manifest next to it. Archive, size, checksum and download time of manifest are filled in.
+func WriteArchiveMetadata(archivePath string, manifest ArchiveManifest) (*ArchiveManifest, error) {
+	sum, size, err := FileSHA256(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest.Archive = filepath.Base(archivePath)
+	manifest.SizeBytes = size
+	manifest.SHA256 = sum
+	if manifest.DownloadedAt.IsZero() {
+		manifest.DownloadedAt = time.Now().UTC()
+	}
+
+	checksumLine := fmt.Sprintf("%s  %s\n", sum, manifest.Archive)
+	if err := os.WriteFile(archivePath+ChecksumSuffix, []byte(checksumLine), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write checksum file: %w", err)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode archive manifest: %w", err)
+	}
+	if err := os.WriteFile(archivePath+ManifestSuffix, append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write archive manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// VerifyArchive recomputes the SHA-256 of an archive and compares it with the checksum file,
+// which defaults to the sibling <archive>.sha256
+func VerifyArchive(archivePath, checksumPath string) (*ChecksumResult, error) {
+	if checksumPath == "" {
+		checksumPath = archivePath + ChecksumSuffix
+	}
+	expected, err := ReadChecksumFile(checksumPath)
+	if err != nil {
+		return nil, err
+	}
+	actual, size, err := FileSHA256(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	return &ChecksumResult{
+		File:         archivePath,
+		ChecksumFile: checksumPath,
+		Expected:     expected,
+		Actual:       actual,
+		SizeBytes:    size,
+		OK:           expected == actual,
+	}, nil
+}
+
+// FileSHA256 returns the hex SHA-256 digest and size of a file
+func FileSHA256(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// ReadChecksumFile reads the digest from a sha256sum-style file ("<hex>  <name>")
+func ReadChecksumFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open checksum file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		digest := strings.ToLower(fields[0])
+		if _, err := hex.DecodeString(digest); err != nil || len(digest) != sha256.Size*2 {
+			return "", fmt.Errorf("checksum file %s does not contain a SHA-256 digest", path)
+		}
+		return digest, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read checksum file: %w", err)
+	}
+	return "", fmt.Errorf("checksum file %s is empty", path)
+}
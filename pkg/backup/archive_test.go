@@ -0,0 +1,61 @@
+package backup
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteArchiveMetadataAndVerify(t *testing.T) {
+	t.Parallel()
+
+	archive := filepath.Join(t.TempDir(), "backup-1.tar.gz")
+	if err := os.WriteFile(archive, []byte("backup payload"), 0644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+
+	manifest, err := WriteArchiveMetadata(archive, ArchiveManifest{BackupID: "b1", Namespace: "hivemq", Source: ArchiveSourceManagement})
+	if err != nil {
+		t.Fatalf("WriteArchiveMetadata returned error: %v", err)
+	}
+	if manifest.SizeBytes != 14 || len(manifest.SHA256) != 64 || manifest.Archive != "backup-1.tar.gz" {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+
+	line, err := os.ReadFile(archive + ChecksumSuffix)
+	if err != nil || string(line) != manifest.SHA256+"  backup-1.tar.gz\n" {
+		t.Fatalf("unexpected checksum file %q: %v", line, err)
+	}
+	var stored ArchiveManifest
+	data, _ := os.ReadFile(archive + ManifestSuffix)
+	if err := json.Unmarshal(data, &stored); err != nil || stored.SHA256 != manifest.SHA256 || stored.BackupID != "b1" {
+		t.Fatalf("unexpected stored manifest %+v: %v", stored, err)
+	}
+
+	result, err := VerifyArchive(archive, "")
+	if err != nil || !result.OK {
+		t.Fatalf("expected matching checksum, got %+v: %v", result, err)
+	}
+
+	if err := os.WriteFile(archive, []byte("tampered payload"), 0644); err != nil {
+		t.Fatalf("failed to modify archive: %v", err)
+	}
+	result, err = VerifyArchive(archive, "")
+	if err != nil || result.OK || result.Expected == result.Actual {
+		t.Fatalf("expected mismatch, got %+v: %v", result, err)
+	}
+}
+
+func TestReadChecksumFileRejectsGarbage(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "x.sha256")
+	if err := os.WriteFile(path, []byte("not-a-digest  x.tar.gz\n"), 0644); err != nil {
+		t.Fatalf("failed to write checksum file: %v", err)
+	}
+	if _, err := ReadChecksumFile(path); err == nil || !strings.Contains(err.Error(), "SHA-256") {
+		t.Fatalf("expected digest error, got %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"kubectl-broker/pkg/logging"
+	"kubectl-broker/pkg/tracing"
+)
+
+// tokenRefreshMargin renews OAuth2 tokens this long before they expire
+const tokenRefreshMargin = 30 * time.Second
+
+// TokenSource supplies the bearer token sent to the management API instead of basic auth,
+// e.g. when the REST API sits behind an auth proxy or the Enterprise Security Extension
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// invalidatingSource is implemented by token sources that can drop a rejected token so the
+// next request fetches a fresh one
+type invalidatingSource interface {
+	Invalidate()
+}
+
+// StaticToken sends the same bearer token with every request
+type StaticToken string
+
+// Token returns the token
+func (t StaticToken) Token(context.Context) (string, error) {
+	return string(t), nil
+}
+
+// FileToken reads the bearer token from a file on every request, so tokens rotated by a
+// sidecar or a projected volume are picked up
+type FileToken string
+
+// Token returns the trimmed file content
+func (f FileToken) Token(context.Context) (string, error) {
+	data, err := os.ReadFile(string(f))
+	if err != nil {
+		return "", fmt.Errorf("failed to read API token file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("API token file %s is empty", f)
+	}
+	return token, nil
+}
+
+// ClientCredentials fetches bearer tokens with the OAuth2 client-credentials grant and reuses
+// them until shortly before they expire
+type ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+
+	httpClient *http.Client
+	mu         sync.Mutex
+	token      string
+	expiry     time.Time // zero for tokens without expires_in
+}
+
+// NewClientCredentials creates an OAuth2 client-credentials token source
+func NewClientCredentials(tokenURL, clientID, clientSecret string, scopes []string) *ClientCredentials {
+	return &ClientCredentials{
+		TokenURL:     tokenURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       scopes,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: tracing.WrapTransport(logging.WrapTransport(nil, "oauth2"), "oauth2"),
+		},
+	}
+}
+
+// tokenResponse is the token endpoint answer of RFC 6749 section 5.1
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// Token returns the cached token or requests a new one when it is about to expire
+func (c *ClientCredentials) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && (c.expiry.IsZero() || time.Until(c.expiry) > tokenRefreshMargin) {
+		return c.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(c.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	// Client credentials go into the Authorization header, which the HTTP debug log never prints
+	req.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request to %s failed: %w", c.TokenURL, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint %s returned %s: %s", c.TokenURL, resp.Status, strings.TrimSpace(logging.RedactBody(string(body))))
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("token endpoint %s returned no access_token", c.TokenURL)
+	}
+	if token.TokenType != "" && !strings.EqualFold(token.TokenType, "bearer") {
+		return "", fmt.Errorf("token endpoint %s returned unsupported token type %q", c.TokenURL, token.TokenType)
+	}
+
+	c.token = token.AccessToken
+	c.expiry = time.Time{}
+	if token.ExpiresIn > 0 {
+		c.expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return c.token, nil
+}
+
+// Invalidate drops the cached token, e.g. after the management API rejected it
+func (c *ClientCredentials) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = ""
+}
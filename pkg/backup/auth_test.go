@@ -0,0 +1,82 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClientCredentialsCachesTokenAndRefreshesAfterRejection(t *testing.T) {
+	t.Parallel()
+
+	var issued int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "kubectl-broker" || secret != "s3cret" {
+			t.Errorf("unexpected client credentials %q/%q", id, secret)
+		}
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("scope") != "backup restore" {
+			t.Errorf("unexpected token request form %v", r.PostForm)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, atomic.AddInt32(&issued, 1))
+	}))
+	defer tokenServer.Close()
+
+	// The API rejects the first token once, as if it had been revoked
+	var rejected int32
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer token-1" && atomic.AddInt32(&rejected, 1) == 2 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"items":[{"id":%q,"state":"COMPLETED"}]}`, r.Header.Get("Authorization"))
+	}))
+	defer apiServer.Close()
+
+	source := NewClientCredentials(tokenServer.URL, "kubectl-broker", "s3cret", []string{"backup", "restore"})
+	client, err := NewClientWithConfig(apiServer.URL, "admin", "ignored", ClientConfig{Auth: source})
+	if err != nil {
+		t.Fatalf("NewClientWithConfig returned error: %v", err)
+	}
+	client.WithContext(context.Background())
+
+	for _, want := range []string{"Bearer token-1", "Bearer token-2", "Bearer token-2"} {
+		list, err := client.ListBackups()
+		if err != nil {
+			t.Fatalf("ListBackups returned error: %v", err)
+		}
+		if got := list.Items[0].ID; got != want {
+			t.Fatalf("expected request with %q, got %q", want, got)
+		}
+	}
+	if got := atomic.LoadInt32(&issued); got != 2 {
+		t.Fatalf("expected 2 tokens to be issued, got %d", got)
+	}
+}
+
+func TestFileTokenRejectsEmptyFile(t *testing.T) {
+	t.Parallel()
+
+	path := t.TempDir() + "/token"
+	if _, err := FileToken(path).Token(context.Background()); err == nil {
+		t.Fatal("expected an error for a missing token file")
+	}
+	if err := os.WriteFile(path, []byte("  \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FileToken(path).Token(context.Background()); err == nil {
+		t.Fatal("expected an error for an empty token file")
+	}
+	if err := os.WriteFile(path, []byte("abc\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if token, err := FileToken(path).Token(context.Background()); err != nil || token != "abc" {
+		t.Fatalf("expected token abc, got %q (%v)", token, err)
+	}
+}
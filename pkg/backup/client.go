@@ -2,13 +2,19 @@ package backup
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
+
+	"kubectl-broker/pkg/logging"
+	"kubectl-broker/pkg/tracing"
+	"kubectl-broker/pkg/transport"
 )
 
 // Client represents an HTTP client for HiveMQ backup API operations
@@ -17,18 +23,47 @@ type Client struct {
 	baseURL    string
 	username   string
 	password   string
+	auth       TokenSource
+	retry      RetryPolicy
+	ctx        context.Context
+}
+
+// ClientConfig configures a management API client
+type ClientConfig struct {
+	Timeout time.Duration        // per-request timeout (defaults to 30s)
+	TLS     transport.TLSOptions // TLS settings for the management API
+	Retry   RetryPolicy          // retry behaviour for transient failures
+	Auth    TokenSource          // bearer token instead of basic auth (optional)
 }
 
 // NewClient creates a new backup API client
 func NewClient(baseURL, username, password string) *Client {
 	return &Client{
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second, // Default timeout for status checks
+			Timeout:   30 * time.Second, // Default timeout for status checks
+			Transport: tracing.WrapTransport(logging.WrapTransport(nil, "hivemq"), "hivemq"),
 		},
 		baseURL:  strings.TrimRight(baseURL, "/"),
 		username: username,
 		password: password,
+		ctx:      context.Background(),
+	}
+}
+
+// NewClientWithConfig creates a backup API client with timeout, TLS and retry settings
+func NewClientWithConfig(baseURL, username, password string, config ClientConfig) (*Client, error) {
+	client := NewClient(baseURL, username, password)
+	if config.Timeout > 0 {
+		client.SetTimeout(config.Timeout)
+	}
+	if config.TLS.IsEnabled() {
+		if err := client.ConfigureTLS(config.TLS); err != nil {
+			return nil, err
+		}
 	}
+	client.retry = config.Retry
+	client.auth = config.Auth
+	return client, nil
 }
 
 // SetTimeout configures the HTTP client timeout
@@ -36,23 +71,100 @@ func (c *Client) SetTimeout(timeout time.Duration) {
 	c.httpClient.Timeout = timeout
 }
 
-// makeRequest performs an HTTP request with authentication if configured
+// SetRetryPolicy configures retries for transient request failures
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.retry = policy
+}
+
+// WithContext binds requests and retry waits to ctx
+func (c *Client) WithContext(ctx context.Context) *Client {
+	if ctx != nil {
+		c.ctx = ctx
+	}
+	return c
+}
+
+// ConfigureTLS replaces the HTTP transport so requests negotiate TLS with the given settings
+func (c *Client) ConfigureTLS(opts transport.TLSOptions) error {
+	httpClient, err := transport.NewHTTPClient(c.httpClient.Timeout, opts)
+	if err != nil {
+		return fmt.Errorf("failed to configure TLS: %w", err)
+	}
+	c.httpClient = httpClient
+	return nil
+}
+
+// makeRequest performs an HTTP request with authentication if configured.
+// Connection errors and transient HTTP statuses are retried according to the retry policy;
+// requests that may change state are only retried when the connection could not be established.
 func (c *Client) makeRequest(method, path string, body io.Reader) (*http.Response, error) {
 	url := fmt.Sprintf("%s%s", c.baseURL, path)
 
-	req, err := http.NewRequest(method, url, body)
+	// Buffer the body so it can be replayed on retries
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = io.ReadAll(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.doRequest(method, url, payload, body != nil)
+		if err == nil && resp.StatusCode == http.StatusUnauthorized {
+			resp, err = c.retryWithFreshToken(resp, method, url, payload, body != nil)
+		}
+		if attempt >= c.retry.MaxRetries {
+			return resp, err
+		}
+
+		if err == nil && !isRetryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+		if !isRetryableAttempt(method, err) {
+			return resp, err
+		}
+		if err != nil && c.ctx.Err() != nil {
+			return nil, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if waitErr := sleepContext(c.ctx, c.retry.delay(attempt)); waitErr != nil {
+			return nil, fmt.Errorf("request cancelled while retrying: %w", waitErr)
+		}
+	}
+}
+
+// doRequest performs a single HTTP request attempt
+func (c *Client) doRequest(method, url string, payload []byte, hasBody bool) (*http.Response, error) {
+	var body io.Reader
+	if hasBody {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(c.ctx, method, url, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Add authentication header if credentials are provided
-	if c.username != "" && c.password != "" {
+	// Add authentication header if credentials are provided; a token source replaces basic auth
+	if c.auth != nil {
+		token, err := c.auth.Token(c.ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to obtain API token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else if c.username != "" && c.password != "" {
 		auth := base64.StdEncoding.EncodeToString([]byte(c.username + ":" + c.password))
 		req.Header.Set("Authorization", "Basic "+auth)
 	}
 
-	// Set content type for POST requests
-	if method == "POST" && body != nil {
+	// Request bodies of the management API are JSON
+	if hasBody {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
@@ -64,6 +176,19 @@ func (c *Client) makeRequest(method, path string, body io.Reader) (*http.Respons
 	return resp, nil
 }
 
+// retryWithFreshToken repeats a request rejected with 401 once with a new token when the
+// token source caches tokens, e.g. an OAuth2 token revoked before its expiry
+func (c *Client) retryWithFreshToken(resp *http.Response, method, url string, payload []byte, hasBody bool) (*http.Response, error) {
+	source, ok := c.auth.(invalidatingSource)
+	if !ok {
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	source.Invalidate()
+	return c.doRequest(method, url, payload, hasBody)
+}
+
 // CreateBackup initiates a new backup operation
 func (c *Client) CreateBackup() (*BackupResponse, error) {
 	resp, err := c.makeRequest("POST", "/api/v1/management/backups", nil)
@@ -95,24 +220,62 @@ func (c *Client) CreateBackup() (*BackupResponse, error) {
 	return &backupResp, nil
 }
 
-// ListBackups retrieves all available backups
+// ListBackups retrieves all available backups, following pagination cursors
 func (c *Client) ListBackups() (*BackupListResponse, error) {
-	resp, err := c.makeRequest("GET", "/api/v1/management/backups", nil)
+	return c.ListBackupsUpTo(0)
+}
+
+// ListBackupsUpTo follows pagination cursors until every page was read or maxItems backups
+// were collected (0 for no limit). The cap applies in API order, before any sorting.
+func (c *Client) ListBackupsUpTo(maxItems int) (*BackupListResponse, error) {
+	all := &BackupListResponse{}
+	seen := make(map[string]bool)
+	cursor := ""
+	for {
+		page, next, err := c.ListBackupsPage(cursor)
+		if err != nil {
+			return nil, err
+		}
+		all.Items = append(all.Items, page.Items...)
+
+		if maxItems > 0 && len(all.Items) >= maxItems {
+			all.Truncated = len(all.Items) > maxItems || next != ""
+			all.Items = all.Items[:maxItems]
+			return all, nil
+		}
+		// A server repeating a cursor would otherwise be followed forever
+		if next == "" || seen[next] {
+			return all, nil
+		}
+		seen[next] = true
+		cursor = next
+	}
+}
+
+// ListBackupsPage retrieves a single page of backups starting at cursor ("" for the first
+// page) and returns the cursor of the next page, which is empty on the last page
+func (c *Client) ListBackupsPage(cursor string) (*BackupListResponse, string, error) {
+	path := "/api/v1/management/backups"
+	if cursor != "" {
+		path += "?cursor=" + url.QueryEscape(cursor)
+	}
+
+	resp, err := c.makeRequest("GET", path, nil)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, c.handleErrorResponse(resp)
+		return nil, "", c.handleErrorResponse(resp)
 	}
 
 	var listResp BackupListResponse
 	if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
-		return nil, fmt.Errorf("failed to decode backup list response: %w", err)
+		return nil, "", fmt.Errorf("failed to decode backup list response: %w", err)
 	}
 
-	return &listResp, nil
+	return &listResp, listResp.NextCursor(), nil
 }
 
 // GetBackupStatus retrieves the status of a specific backup
@@ -140,9 +303,11 @@ func (c *Client) GetBackupStatus(backupID string) (*BackupStatusResponse, error)
 
 // DownloadBackup downloads a backup file and returns the response for streaming
 func (c *Client) DownloadBackup(backupID string) (*http.Response, error) {
-	// Set longer timeout for downloads
+	// Set longer timeout for downloads unless a longer one was configured
 	originalTimeout := c.httpClient.Timeout
-	c.httpClient.Timeout = 10 * time.Minute
+	if originalTimeout < 10*time.Minute {
+		c.httpClient.Timeout = 10 * time.Minute
+	}
 	defer func() {
 		c.httpClient.Timeout = originalTimeout
 	}()
@@ -169,9 +334,8 @@ func (c *Client) DownloadBackup(backupID string) (*http.Response, error) {
 			return resp, nil
 		}
 
-		// Close non-success response
-		resp.Body.Close()
 		lastErr = c.handleErrorResponse(resp)
+		resp.Body.Close()
 
 		// If not 404, don't try other endpoints
 		if resp.StatusCode != http.StatusNotFound {
@@ -212,30 +376,6 @@ func (c *Client) RestoreBackup(backupID string) (*RestoreResponse, error) {
 	return &restoreResp, nil
 }
 
-// handleErrorResponse processes error responses from the API
-func (c *Client) handleErrorResponse(resp *http.Response) error {
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("HTTP %d: failed to read error response", resp.StatusCode)
-	}
-
-	var errorResp ErrorResponse
-	if err := json.Unmarshal(body, &errorResp); err != nil {
-		// If we can't parse the error JSON, return the raw response
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
-	if errorResp.Message != "" {
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, errorResp.Message)
-	}
-
-	if errorResp.Error != "" {
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, errorResp.Error)
-	}
-
-	return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
-}
-
 // TestConnection tests if the HiveMQ management API is available
 func (c *Client) TestConnection() error {
 	// Test the backup endpoint specifically instead of the root management endpoint
@@ -251,7 +391,7 @@ func (c *Client) TestConnection() error {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("management API returned HTTP %d", resp.StatusCode)
+		return fmt.Errorf("management API check failed: %w", c.handleErrorResponse(resp))
 	}
 
 	return nil
@@ -0,0 +1,247 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"kubectl-broker/pkg"
+)
+
+func TestMakeRequestRetriesTransientStatus(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"backup":{"id":"b1","state":"COMPLETED"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithConfig(server.URL, "", "", ClientConfig{
+		Retry: RetryPolicy{MaxRetries: 3, Backoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("NewClientWithConfig returned error: %v", err)
+	}
+
+	status, err := client.GetBackupStatus("b1")
+	if err != nil {
+		t.Fatalf("GetBackupStatus returned error: %v", err)
+	}
+	if status.ID != "b1" {
+		t.Fatalf("unexpected backup id %q", status.ID)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestMakeRequestDoesNotRetryPOST(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		// The broker may have started the restore before the gateway gave up
+		w.WriteHeader(http.StatusGatewayTimeout)
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithConfig(server.URL, "", "", ClientConfig{
+		Retry: RetryPolicy{MaxRetries: 3, Backoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("NewClientWithConfig returned error: %v", err)
+	}
+
+	if _, err := client.RestoreBackup("b1"); err == nil {
+		t.Fatal("expected RestoreBackup to fail")
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected a single restore request, got %d", got)
+	}
+}
+
+func TestIsRetryableAttemptRetriesUnsentPOST(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	_ = listener.Close()
+
+	_, dialErr := http.Post("http://"+addr, "application/json", nil)
+	if dialErr == nil {
+		t.Fatal("expected the request to a closed port to fail")
+	}
+	if !isRetryableAttempt(http.MethodPost, dialErr) {
+		t.Errorf("POST that never connected must be retried: %v", dialErr)
+	}
+	if isRetryableAttempt(http.MethodPost, io.ErrUnexpectedEOF) {
+		t.Error("POST that may have been sent must not be retried")
+	}
+	if !isRetryableAttempt(http.MethodGet, io.ErrUnexpectedEOF) {
+		t.Error("GET must be retried")
+	}
+}
+
+func TestMakeRequestStopsRetryingOnCancel(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client, err := NewClientWithConfig(server.URL, "", "", ClientConfig{
+		Retry: RetryPolicy{MaxRetries: 10, Backoff: time.Second, MaxBackoff: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("NewClientWithConfig returned error: %v", err)
+	}
+
+	start := time.Now()
+	_, err = client.WithContext(ctx).ListBackups()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("retries ignored cancellation, took %s", elapsed)
+	}
+}
+
+func TestListBackupsFollowsCursors(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]string{
+		"":   `{"items":[{"id":"b1"},{"id":"b2"}],"_links":{"next":"/api/v1/management/backups?cursor=c2"}}`,
+		"c2": `{"items":[{"id":"b3"}],"_links":{"next":"/api/v1/management/backups?cursor=c3"}}`,
+		"c3": `{"items":[{"id":"b4"}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Query().Get("cursor")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "", "")
+
+	all, err := client.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups returned error: %v", err)
+	}
+	if len(all.Items) != 4 || all.Items[3].ID != "b4" || all.Truncated {
+		t.Fatalf("expected all four backups, got %+v", all)
+	}
+
+	capped, err := client.ListBackupsUpTo(3)
+	if err != nil {
+		t.Fatalf("ListBackupsUpTo returned error: %v", err)
+	}
+	if len(capped.Items) != 3 || !capped.Truncated {
+		t.Fatalf("expected three backups and truncation, got %+v", capped)
+	}
+
+	page, next, err := client.ListBackupsPage("c2")
+	if err != nil || next != "c3" || len(page.Items) != 1 {
+		t.Fatalf("unexpected page: %+v next=%q err=%v", page, next, err)
+	}
+}
+
+func TestHandleErrorResponseParsesErrorPayload(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-42")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"errors":[{"title":"Restore not possible","detail":"A backup is currently in progress"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithConfig(server.URL, "", "", ClientConfig{})
+	if err != nil {
+		t.Fatalf("NewClientWithConfig returned error: %v", err)
+	}
+
+	_, err = client.RestoreBackup("b1")
+	want := "HTTP 409: Restore not possible: A backup is currently in progress (request ID req-42)"
+	if err == nil || err.Error() != want {
+		t.Fatalf("RestoreBackup error = %v, want %q", err, want)
+	}
+	if !IsStatus(err, http.StatusConflict) {
+		t.Fatalf("expected a 409 APIError in %v", err)
+	}
+	if envelope := pkg.NewErrorEnvelope(err); envelope.Error.Type != pkg.ErrTypeAPI || envelope.Error.Resource != "POST /api/v1/management/restores" {
+		t.Fatalf("unexpected envelope: %+v", envelope.Error)
+	}
+}
+
+func TestParseAPIError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"title":"Backup not found","detail":"No backup with id b1","requestId":"abc"}`, "HTTP 404: Backup not found: No backup with id b1 (request ID abc)"},
+		{`{"errors":[{"detail":"first"},{"detail":"second"}]}`, "HTTP 404: first; second"},
+		{`{"message":"legacy message"}`, "HTTP 404: legacy message"},
+		{`not json`, "HTTP 404: not json"},
+		{``, "HTTP 404 Not Found"},
+	}
+	for _, tt := range tests {
+		if got := ParseAPIError(http.StatusNotFound, []byte(tt.body), "").Error(); got != tt.want {
+			t.Errorf("ParseAPIError(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestWaitForBackupCompletionStopsAtOperationDeadline(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"backup":{"id":"b1","state":"IN_PROGRESS","progress":40}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "", "")
+	options := BackupOptions{PollInterval: 5 * time.Millisecond, OperationDeadline: 50 * time.Millisecond}
+
+	start := time.Now()
+	_, err := waitForBackupCompletion(context.Background(), client, "b1", options)
+	if err == nil || !strings.Contains(err.Error(), "did not finish within 50ms") {
+		t.Fatalf("expected operation deadline error, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("wait ignored the operation deadline, took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := waitForBackupCompletion(ctx, client, "b1", BackupOptions{PollInterval: time.Hour}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation error, got: %v", err)
+	}
+}
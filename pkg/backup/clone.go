@@ -0,0 +1,237 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+)
+
+// cloneProgressInterval limits how often transfer progress is reported
+const cloneProgressInterval = 500 * time.Millisecond
+
+// CloneEndpoint is the StatefulSet a backup is cloned from or into
+type CloneEndpoint struct {
+	Namespace   string
+	StatefulSet string
+}
+
+func (e CloneEndpoint) String() string {
+	return e.Namespace + "/" + e.StatefulSet
+}
+
+// CloneOptions configures CloneBackup
+type CloneOptions struct {
+	Source      CloneEndpoint
+	Target      CloneEndpoint
+	BackupID    string        // backup to clone; "latest" selects the newest backup of the source
+	SkipRestore bool          // only copy the backup into the target's backup folder
+	Backup      BackupOptions // management API settings for resolving "latest" and the restore
+}
+
+// CloneResult describes a cloned backup
+type CloneResult struct {
+	BackupID   string `json:"backupId"`
+	SourcePod  string `json:"sourcePod"`
+	SourcePath string `json:"sourcePath"`
+	TargetPod  string `json:"targetPod"`
+	TargetPath string `json:"targetPath"`
+	Bytes      int64  `json:"bytes"`
+	Restored   bool   `json:"restored"`
+}
+
+// ClonePlan is the source and target of a clone, resolved without changing anything
+type ClonePlan struct {
+	BackupID     string
+	SourcePod    *v1.Pod
+	SourceFolder string
+	TargetPod    *v1.Pod
+	TargetFolder string
+	SizeBytes    int64
+}
+
+// PlanClone resolves the backup ID, the pod holding the backup in the source StatefulSet and
+// the running target pod it is copied to, and checks that the target does not have it yet
+func PlanClone(ctx context.Context, k8sClient *pkg.K8sClient, options CloneOptions) (*ClonePlan, error) {
+	backupID := options.BackupID
+	if backupID == "latest" {
+		service, err := k8sClient.GetAPIServiceFromStatefulSet(ctx, options.Source.Namespace, options.Source.StatefulSet)
+		if err != nil {
+			return nil, err
+		}
+		engine, err := newServiceEngine(k8sClient, service, options.Backup)
+		if err != nil {
+			return nil, err
+		}
+		if backupID, err = engine.resolveID(ctx, backupID); err != nil {
+			return nil, err
+		}
+	}
+
+	sourcePodName, err := DetectBackupPod(ctx, k8sClient, options.Source.Namespace, options.Source.StatefulSet, backupID)
+	if err != nil {
+		return nil, fmt.Errorf("backup %s not found in %s: %w", backupID, options.Source, err)
+	}
+	sourcePod, err := k8sClient.GetPod(ctx, options.Source.Namespace, sourcePodName)
+	if err != nil {
+		return nil, err
+	}
+	sourceFolder, err := GetBackupFolder(ctx, k8sClient, sourcePod.Namespace, sourcePod.Name)
+	if err != nil {
+		return nil, err
+	}
+	output, err := k8sClient.ExecCommand(ctx, sourcePod.Namespace, sourcePod.Name, []string{"du", "-sk", path.Join(sourceFolder, backupID)})
+	if err != nil {
+		return nil, fmt.Errorf("cannot determine the size of backup %s on pod %s: %w", backupID, sourcePod.Name, err)
+	}
+	sizeKB, err := parseDuKilobytes(output)
+	if err != nil {
+		return nil, err
+	}
+
+	targetPod, err := runningPod(ctx, k8sClient, options.Target)
+	if err != nil {
+		return nil, err
+	}
+	targetFolder, err := GetBackupFolder(ctx, k8sClient, targetPod.Namespace, targetPod.Name)
+	if err != nil {
+		return nil, err
+	}
+	targetDir := path.Join(targetFolder, backupID)
+	if exists, _ := directoryExistsOnPod(ctx, k8sClient, targetPod.Namespace, targetPod.Name, targetDir); exists {
+		return nil, fmt.Errorf("backup %s already exists at %s on pod %s\n\nPlease either:\n- Restore it directly: kubectl broker backup restore --id %s -n %s --statefulset %s\n- Remove it from the target pod and clone again", backupID, targetDir, targetPod.Name, backupID, options.Target.Namespace, options.Target.StatefulSet)
+	}
+
+	return &ClonePlan{
+		BackupID:     backupID,
+		SourcePod:    sourcePod,
+		SourceFolder: sourceFolder,
+		TargetPod:    targetPod,
+		TargetFolder: targetFolder,
+		SizeBytes:    sizeKB * 1024,
+	}, nil
+}
+
+// runningPod returns the first running pod of the StatefulSet
+func runningPod(ctx context.Context, k8sClient *pkg.K8sClient, endpoint CloneEndpoint) (*v1.Pod, error) {
+	pods, err := k8sClient.GetStatefulSetPods(ctx, endpoint.Namespace, endpoint.StatefulSet)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pods {
+		if pods[i].Status.Phase == v1.PodRunning {
+			return &pods[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no running pod found in StatefulSet %s", endpoint)
+}
+
+// CloneBackup copies the planned backup directory from the source pod into the backup folder
+// of the target pod, streaming a tar archive between two pod execs, and restores it there
+// through the target pod's management API unless options.SkipRestore is set. A failed copy is
+// removed from the target pod.
+func CloneBackup(ctx context.Context, k8sClient *pkg.K8sClient, plan *ClonePlan, options CloneOptions) (*CloneResult, error) {
+	source, target := plan.SourcePod, plan.TargetPod
+	result := &CloneResult{
+		BackupID:   plan.BackupID,
+		SourcePod:  source.Namespace + "/" + source.Name,
+		SourcePath: path.Join(plan.SourceFolder, plan.BackupID),
+		TargetPod:  target.Namespace + "/" + target.Name,
+		TargetPath: path.Join(plan.TargetFolder, plan.BackupID),
+	}
+	if err := pkg.GuardMutation(ctx, "clone", fmt.Sprintf("backup %s into %s", plan.BackupID, options.Target)); err != nil {
+		return nil, err
+	}
+
+	bytes, err := transferBackupDirectory(ctx, k8sClient, plan, options.Backup.progressSink("Clone"))
+	if err != nil {
+		// Do not leave a partial backup that a later restore could pick up
+		cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		if _, rmErr := k8sClient.ExecCommandInContainer(cleanupCtx, target.Namespace, target.Name, pkg.BrokerContainerName(target), []string{"rm", "-rf", result.TargetPath}); rmErr != nil {
+			pkg.RecordInterruption(ctx, fmt.Sprintf("clone of backup %s (partial copy left at %s on pod %s)", plan.BackupID, result.TargetPath, target.Name))
+			return nil, fmt.Errorf("copying backup %s to pod %s failed and the partial copy at %s could not be removed: %w", plan.BackupID, target.Name, result.TargetPath, err)
+		}
+		pkg.RecordInterruption(ctx, fmt.Sprintf("clone of backup %s (partial copy removed from pod %s)", plan.BackupID, target.Name))
+		return nil, fmt.Errorf("copying backup %s to pod %s failed: %w", plan.BackupID, target.Name, err)
+	}
+	result.Bytes = bytes
+
+	if options.SkipRestore {
+		return result, nil
+	}
+	apiPort, err := k8sClient.DiscoverAPIPort(target)
+	if err != nil {
+		return result, fmt.Errorf("backup copied to %s but the management API port of pod %s is unknown: %w", result.TargetPath, target.Name, err)
+	}
+	engine := NewEngine(pkg.NewPodDialer(k8sClient, target, apiPort, false), options.Backup)
+	if _, err := engine.Restore(ctx, plan.BackupID); err != nil {
+		return result, fmt.Errorf("backup copied to %s but the restore failed: %w", result.TargetPath, err)
+	}
+	result.Restored = true
+	return result, nil
+}
+
+// transferBackupDirectory pipes "tar -c" on the source pod into "tar -x" on the target pod and
+// returns the number of bytes streamed
+func transferBackupDirectory(ctx context.Context, k8sClient *pkg.K8sClient, plan *ClonePlan, progress ProgressFunc) (int64, error) {
+	source, target := plan.SourcePod, plan.TargetPod
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	reader, writer := io.Pipe()
+	sourceDone := make(chan error, 1)
+	go func() {
+		err := k8sClient.ExecCommandWithIO(ctx, source.Namespace, source.Name, pkg.BrokerContainerName(source),
+			[]string{"tar", "-C", plan.SourceFolder, "-cf", "-", plan.BackupID}, nil, writer)
+		writer.CloseWithError(err)
+		sourceDone <- err
+	}()
+
+	counter := &countingReader{reader: reader, total: plan.SizeBytes, backupID: plan.BackupID, progress: progress}
+	targetErr := k8sClient.ExecCommandWithIO(ctx, target.Namespace, target.Name, pkg.BrokerContainerName(target),
+		[]string{"tar", "-C", plan.TargetFolder, "-xf", "-"}, counter, io.Discard)
+	if targetErr != nil {
+		cancel()
+		reader.CloseWithError(targetErr)
+	}
+	sourceErr := <-sourceDone
+
+	switch {
+	case sourceErr != nil && targetErr == nil:
+		err := fmt.Errorf("reading from pod %s: %w", source.Name, sourceErr)
+		progress.emit(ProgressEvent{Phase: PhaseClone, BackupID: plan.BackupID, Done: true, Error: err.Error()})
+		return 0, err
+	case targetErr != nil:
+		err := fmt.Errorf("writing to pod %s: %w", target.Name, targetErr)
+		progress.emit(ProgressEvent{Phase: PhaseClone, BackupID: plan.BackupID, Done: true, Error: err.Error()})
+		return 0, err
+	}
+	progress.emit(ProgressEvent{Phase: PhaseClone, BackupID: plan.BackupID, Percent: 100, Bytes: counter.bytes, TotalBytes: plan.SizeBytes, Done: true})
+	return counter.bytes, nil
+}
+
+// countingReader reports the bytes read through it as PhaseClone progress
+type countingReader struct {
+	reader   io.Reader
+	bytes    int64
+	total    int64
+	backupID string
+	progress ProgressFunc
+	last     time.Time
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.bytes += int64(n)
+	if now := time.Now(); now.Sub(r.last) >= cloneProgressInterval {
+		r.last = now
+		r.progress.emit(ProgressEvent{Phase: PhaseClone, BackupID: r.backupID, Percent: copyPercent(r.bytes, r.total), Bytes: r.bytes, TotalBytes: r.total})
+	}
+	return n, err
+}
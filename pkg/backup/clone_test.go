@@ -0,0 +1,33 @@
+package backup
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCountingReaderReportsProgress(t *testing.T) {
+	t.Parallel()
+
+	var events []ProgressEvent
+	reader := &countingReader{
+		reader:   strings.NewReader(strings.Repeat("x", 2048)),
+		total:    4096,
+		backupID: "b1",
+		progress: func(event ProgressEvent) { events = append(events, event) },
+	}
+
+	n, err := io.Copy(io.Discard, reader)
+	if err != nil || n != 2048 {
+		t.Fatalf("io.Copy() = %d, %v", n, err)
+	}
+	if reader.bytes != 2048 {
+		t.Errorf("bytes = %d, want 2048", reader.bytes)
+	}
+	if len(events) == 0 {
+		t.Fatal("expected a progress event for the first read")
+	}
+	if events[0].Phase != PhaseClone || events[0].BackupID != "b1" || events[0].TotalBytes != 4096 {
+		t.Errorf("event = %+v", events[0])
+	}
+}
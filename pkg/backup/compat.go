@@ -0,0 +1,92 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"kubectl-broker/pkg"
+)
+
+// VersionCheck is the result of comparing the HiveMQ version of a backup with the running broker
+type VersionCheck struct {
+	BackupVersion string
+	BrokerVersion string
+	Compatible    bool   // false when the restore must be refused
+	Known         bool   // false when either version could not be determined
+	Reason        string // explanation for refusals and warnings
+}
+
+// CheckVersionCompatibility decides whether a backup may be restored onto a broker. Restores
+// across major versions and onto an older broker are refused; an older minor version is
+// allowed with a warning. Unknown versions cannot be judged and are allowed.
+func CheckVersionCompatibility(backupVersion, brokerVersion string) VersionCheck {
+	check := VersionCheck{BackupVersion: backupVersion, BrokerVersion: brokerVersion, Compatible: true}
+
+	backupMajor, backupMinor, okBackup := parseMajorMinor(backupVersion)
+	brokerMajor, brokerMinor, okBroker := parseMajorMinor(brokerVersion)
+	switch {
+	case !okBackup:
+		check.Reason = "the backup does not record a HiveMQ version"
+		return check
+	case !okBroker:
+		check.Reason = fmt.Sprintf("the broker version %q cannot be determined from its image", brokerVersion)
+		return check
+	}
+	check.Known = true
+
+	switch {
+	case backupMajor != brokerMajor:
+		check.Compatible = false
+		check.Reason = fmt.Sprintf("backup was created by HiveMQ %s but the broker runs %s; restores across major versions are not supported", backupVersion, brokerVersion)
+	case backupMinor > brokerMinor:
+		check.Compatible = false
+		check.Reason = fmt.Sprintf("backup was created by HiveMQ %s, which is newer than the broker version %s", backupVersion, brokerVersion)
+	case backupMinor < brokerMinor:
+		check.Reason = fmt.Sprintf("backup was created by HiveMQ %s and will be migrated to %s on restore", backupVersion, brokerVersion)
+	}
+	return check
+}
+
+// CheckRestoreCompatibility compares the HiveMQ version recorded in a backup on the cluster with
+// the version of the StatefulSet's broker image
+func CheckRestoreCompatibility(ctx context.Context, k8sClient *pkg.K8sClient, namespace, statefulSetName, backupID string) (VersionCheck, error) {
+	brokerVersion, err := k8sClient.GetBrokerVersion(ctx, namespace, statefulSetName)
+	if err != nil {
+		return VersionCheck{}, err
+	}
+
+	podName, err := DetectBackupPod(ctx, k8sClient, namespace, statefulSetName, backupID)
+	if err != nil {
+		return VersionCheck{}, err
+	}
+	contents, err := InspectBackupOnPod(ctx, k8sClient, namespace, podName, backupID)
+	if err != nil {
+		return VersionCheck{}, err
+	}
+
+	return CheckVersionCompatibility(contents.HiveMQVersion, brokerVersion), nil
+}
+
+// parseMajorMinor extracts the major and minor version from tags like "4.28.0", "v4.28" or "4.28.0-k8s"
+func parseMajorMinor(version string) (int, int, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if cut := strings.IndexAny(version, "-+_"); cut >= 0 {
+		version = version[:cut]
+	}
+
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
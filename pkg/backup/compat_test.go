@@ -0,0 +1,28 @@
+package backup
+
+import "testing"
+
+func TestCheckVersionCompatibility(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		backup, broker string
+		compatible     bool
+		known          bool
+	}{
+		{"4.28.0", "4.28.3", true, true},
+		{"4.28.0", "4.40.0", true, true},
+		{"4.40.0", "4.28.0", false, true},
+		{"3.4.7", "4.28.0", false, true},
+		{"v4.28", "4.28.0-k8s", true, true},
+		{"", "4.28.0", true, false},
+		{"4.28.0", "latest", true, false},
+	}
+	for _, tc := range cases {
+		check := CheckVersionCompatibility(tc.backup, tc.broker)
+		if check.Compatible != tc.compatible || check.Known != tc.known {
+			t.Errorf("CheckVersionCompatibility(%q, %q) = compatible %v known %v, want %v %v (%s)",
+				tc.backup, tc.broker, check.Compatible, check.Known, tc.compatible, tc.known, check.Reason)
+		}
+	}
+}
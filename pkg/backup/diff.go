@@ -0,0 +1,116 @@
+package backup
+
+import (
+	"sort"
+	"time"
+)
+
+// Store changes between two backups
+const (
+	StoreAdded     = "added"
+	StoreRemoved   = "removed"
+	StoreChanged   = "changed"
+	StoreUnchanged = "unchanged"
+)
+
+// DiffSide is one of the two backups being compared. Contents is nil when the backup
+// could not be inspected, e.g. because it is no longer on a pod.
+type DiffSide struct {
+	ID           string          `json:"id" yaml:"id"`
+	Status       BackupStatus    `json:"status" yaml:"status"`
+	CreatedAt    time.Time       `json:"createdAt" yaml:"createdAt"`
+	SizeBytes    int64           `json:"sizeBytes" yaml:"sizeBytes"`
+	Contents     *BackupContents `json:"contents,omitempty" yaml:"contents,omitempty"`
+	InspectError string          `json:"inspectError,omitempty" yaml:"inspectError,omitempty"`
+}
+
+// StoreDiff compares one persistence store of two backups
+type StoreDiff struct {
+	Name        string `json:"name" yaml:"name"`
+	Change      string `json:"change" yaml:"change"`
+	BeforeFiles int    `json:"beforeFiles" yaml:"beforeFiles"`
+	AfterFiles  int    `json:"afterFiles" yaml:"afterFiles"`
+	BeforeBytes int64  `json:"beforeBytes" yaml:"beforeBytes"`
+	AfterBytes  int64  `json:"afterBytes" yaml:"afterBytes"`
+	DeltaBytes  int64  `json:"deltaBytes" yaml:"deltaBytes"`
+}
+
+// BackupDiff compares an older backup (Before) with a newer one (After)
+type BackupDiff struct {
+	Before         DiffSide    `json:"before" yaml:"before"`
+	After          DiffSide    `json:"after" yaml:"after"`
+	SizeDeltaBytes int64       `json:"sizeDeltaBytes" yaml:"sizeDeltaBytes"`
+	Stores         []StoreDiff `json:"stores,omitempty" yaml:"stores,omitempty"`
+	// RetainedMessagesDelta is nil unless both backups record a retained message count
+	RetainedMessagesDelta *int64 `json:"retainedMessagesDelta,omitempty" yaml:"retainedMessagesDelta,omitempty"`
+	VersionChanged        bool   `json:"versionChanged" yaml:"versionChanged"`
+	// Unchanged is set when the contents of both backups were compared and no store differs
+	Unchanged bool `json:"unchanged" yaml:"unchanged"`
+}
+
+// CompareBackups compares two backups, ordered by creation time so deltas read as growth
+func CompareBackups(a, b DiffSide) *BackupDiff {
+	if b.CreatedAt.Before(a.CreatedAt) {
+		a, b = b, a
+	}
+	diff := &BackupDiff{Before: a, After: b, SizeDeltaBytes: b.SizeBytes - a.SizeBytes}
+	if a.Contents == nil || b.Contents == nil {
+		return diff
+	}
+
+	before := storesByName(a.Contents.Stores)
+	after := storesByName(b.Contents.Stores)
+	names := make([]string, 0, len(before)+len(after))
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	diff.Unchanged = true
+	for _, name := range names {
+		old, hadOld := before[name]
+		current, hasNew := after[name]
+		store := StoreDiff{
+			Name:        name,
+			BeforeFiles: old.Files,
+			AfterFiles:  current.Files,
+			BeforeBytes: old.SizeBytes,
+			AfterBytes:  current.SizeBytes,
+			DeltaBytes:  current.SizeBytes - old.SizeBytes,
+		}
+		switch {
+		case !hadOld:
+			store.Change = StoreAdded
+		case !hasNew:
+			store.Change = StoreRemoved
+		case old.Files != current.Files || old.SizeBytes != current.SizeBytes:
+			store.Change = StoreChanged
+		default:
+			store.Change = StoreUnchanged
+		}
+		if store.Change != StoreUnchanged {
+			diff.Unchanged = false
+		}
+		diff.Stores = append(diff.Stores, store)
+	}
+
+	if a.Contents.RetainedMessages >= 0 && b.Contents.RetainedMessages >= 0 {
+		delta := b.Contents.RetainedMessages - a.Contents.RetainedMessages
+		diff.RetainedMessagesDelta = &delta
+	}
+	diff.VersionChanged = a.Contents.HiveMQVersion != b.Contents.HiveMQVersion
+	return diff
+}
+
+func storesByName(stores []StoreSummary) map[string]StoreSummary {
+	byName := make(map[string]StoreSummary, len(stores))
+	for _, store := range stores {
+		byName[store.Name] = store
+	}
+	return byName
+}
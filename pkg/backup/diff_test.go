@@ -0,0 +1,83 @@
+package backup
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompareBackupsOrdersByCreationAndDiffsStores(t *testing.T) {
+	t.Parallel()
+
+	older := DiffSide{
+		ID:        "b1",
+		CreatedAt: time.Date(2025, 1, 1, 2, 0, 0, 0, time.UTC),
+		SizeBytes: 1000,
+		Contents: &BackupContents{
+			HiveMQVersion:    "4.28.0",
+			RetainedMessages: 40,
+			Stores: []StoreSummary{
+				{Name: "retained_messages", Files: 2, SizeBytes: 600},
+				{Name: "client_sessions", Files: 1, SizeBytes: 300},
+				{Name: "queued_messages", Files: 1, SizeBytes: 100},
+			},
+		},
+	}
+	newer := DiffSide{
+		ID:        "b2",
+		CreatedAt: older.CreatedAt.Add(24 * time.Hour),
+		SizeBytes: 1500,
+		Contents: &BackupContents{
+			HiveMQVersion:    "4.29.0",
+			RetainedMessages: 45,
+			Stores: []StoreSummary{
+				{Name: "retained_messages", Files: 3, SizeBytes: 900},
+				{Name: "client_sessions", Files: 1, SizeBytes: 300},
+				{Name: "subscriptions", Files: 1, SizeBytes: 300},
+			},
+		},
+	}
+
+	diff := CompareBackups(newer, older)
+	if diff.Before.ID != "b1" || diff.After.ID != "b2" {
+		t.Fatalf("expected b1 before b2, got %s before %s", diff.Before.ID, diff.After.ID)
+	}
+	if diff.SizeDeltaBytes != 500 {
+		t.Errorf("SizeDeltaBytes = %d, want 500", diff.SizeDeltaBytes)
+	}
+	if diff.RetainedMessagesDelta == nil || *diff.RetainedMessagesDelta != 5 {
+		t.Errorf("RetainedMessagesDelta = %v, want 5", diff.RetainedMessagesDelta)
+	}
+	if !diff.VersionChanged || diff.Unchanged {
+		t.Errorf("VersionChanged = %t, Unchanged = %t; want true, false", diff.VersionChanged, diff.Unchanged)
+	}
+
+	want := map[string]string{
+		"client_sessions":   StoreUnchanged,
+		"queued_messages":   StoreRemoved,
+		"retained_messages": StoreChanged,
+		"subscriptions":     StoreAdded,
+	}
+	if len(diff.Stores) != len(want) {
+		t.Fatalf("stores = %+v, want %d entries", diff.Stores, len(want))
+	}
+	for _, store := range diff.Stores {
+		if store.Change != want[store.Name] {
+			t.Errorf("store %s change = %s, want %s", store.Name, store.Change, want[store.Name])
+		}
+	}
+}
+
+func TestCompareBackupsWithoutContents(t *testing.T) {
+	t.Parallel()
+
+	contents := &BackupContents{RetainedMessages: -1, Stores: []StoreSummary{{Name: "retained_messages", Files: 1, SizeBytes: 10}}}
+	diff := CompareBackups(DiffSide{ID: "b1", SizeBytes: 10, Contents: contents}, DiffSide{ID: "b2", SizeBytes: 10, Contents: contents})
+	if !diff.Unchanged || diff.RetainedMessagesDelta != nil {
+		t.Errorf("identical contents: Unchanged = %t, RetainedMessagesDelta = %v", diff.Unchanged, diff.RetainedMessagesDelta)
+	}
+
+	diff = CompareBackups(DiffSide{ID: "b1", SizeBytes: 10}, DiffSide{ID: "b2", SizeBytes: 10, Contents: contents})
+	if diff.Unchanged || len(diff.Stores) != 0 {
+		t.Errorf("missing contents should not be reported as unchanged: %+v", diff)
+	}
+}
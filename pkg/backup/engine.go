@@ -0,0 +1,256 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+)
+
+// BackupEngine runs backup and restore operations against one HiveMQ management API. It is the
+// entry point for tools that embed the backup workflow without the CLI; every call opens its
+// own connection through the engine's PortForwardDialer.
+type BackupEngine interface {
+	// Create triggers a backup and, unless BackupOptions.Async is set, waits for it to finish
+	Create(ctx context.Context) (*BackupInfo, error)
+	// List returns the backups sorted newest first
+	List(ctx context.Context) ([]BackupInfo, error)
+	// Status returns the current state of a backup; id may be "latest"
+	Status(ctx context.Context, id string) (*BackupStatusResponse, error)
+	// Wait blocks until the backup reaches a terminal state; id may be "latest"
+	Wait(ctx context.Context, id string) (*BackupStatusResponse, error)
+	// Download saves the backup archive and returns the written path
+	Download(ctx context.Context, id string) (string, error)
+	// Restore restores the cluster from a backup and waits for completion; id may be "latest"
+	Restore(ctx context.Context, id string) (*RestoreResult, error)
+}
+
+// NewEngine creates a BackupEngine that reaches the management API through dialer
+func NewEngine(dialer pkg.PortForwardDialer, options BackupOptions) BackupEngine {
+	return newManagementEngine(dialer, options)
+}
+
+// managementEngine implements BackupEngine on top of the management API client
+type managementEngine struct {
+	dialer  pkg.PortForwardDialer
+	options BackupOptions
+}
+
+func newManagementEngine(dialer pkg.PortForwardDialer, options BackupOptions) *managementEngine {
+	return &managementEngine{dialer: dialer, options: options}
+}
+
+// newServiceEngine discovers the management API port of the Service and forwards to it, or to
+// options.Pod when the caller routes around the service
+func newServiceEngine(k8sClient *pkg.K8sClient, service *v1.Service, options BackupOptions) (*managementEngine, error) {
+	if options.Pod != nil {
+		apiPort, err := k8sClient.DiscoverAPIPort(options.Pod)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover API port of pod %s: %w", options.Pod.Name, err)
+		}
+		return newManagementEngine(pkg.NewPodDialer(k8sClient, options.Pod, apiPort, false), options), nil
+	}
+	apiPort, err := k8sClient.DiscoverServiceAPIPort(service)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover API port: %w", err)
+	}
+	return newManagementEngine(pkg.NewServiceDialer(k8sClient, service, apiPort), options), nil
+}
+
+// withClient opens a connection and invokes fn with a management API client bound to it
+func (e *managementEngine) withClient(ctx context.Context, fn func(*Client) error) error {
+	return e.dialer.Forward(ctx, func(localPort int) error {
+		client, err := newManagementClient(ctx, localPort, e.options)
+		if err != nil {
+			return err
+		}
+		return fn(client)
+	})
+}
+
+func (e *managementEngine) Create(ctx context.Context) (*BackupInfo, error) {
+	if err := pkg.GuardMutation(ctx, "create", "backup"); err != nil {
+		return nil, err
+	}
+	var info *BackupInfo
+	err := e.withClient(ctx, func(client *Client) error {
+		if e.options.RequestTimeout > 0 {
+			client.SetTimeout(e.options.RequestTimeout)
+		}
+		created, err := createAndWait(ctx, client, e.options)
+		if err != nil {
+			return err
+		}
+		info = created
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (e *managementEngine) List(ctx context.Context) ([]BackupInfo, error) {
+	var backups []BackupInfo
+	err := e.withClient(ctx, func(client *Client) error {
+		listResp, err := client.ListBackupsUpTo(e.options.MaxItems)
+		if err != nil {
+			return fmt.Errorf("failed to list backups: %w", err)
+		}
+		if listResp.Truncated {
+			slog.Warn("More backups are available than were read", "maxItems", e.options.MaxItems)
+		}
+
+		// Sort backups by creation time (newest first)
+		sort.Slice(listResp.Items, func(i, j int) bool {
+			return listResp.Items[i].CreatedAt.After(listResp.Items[j].CreatedAt)
+		})
+
+		backups = listResp.Items
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return backups, nil
+}
+
+func (e *managementEngine) Status(ctx context.Context, id string) (*BackupStatusResponse, error) {
+	id, err := e.resolveID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var status *BackupStatusResponse
+	err = e.withClient(ctx, func(client *Client) error {
+		statusResp, err := client.GetBackupStatus(id)
+		if err != nil {
+			return fmt.Errorf("failed to get backup status: %w", err)
+		}
+		status = statusResp
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func (e *managementEngine) Wait(ctx context.Context, id string) (*BackupStatusResponse, error) {
+	id, err := e.resolveID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var status *BackupStatusResponse
+	err = e.withClient(ctx, func(client *Client) error {
+		var waitErr error
+		status, waitErr = waitForBackupCompletion(ctx, client, id, e.options)
+		return waitErr
+	})
+	return status, err
+}
+
+func (e *managementEngine) Download(ctx context.Context, id string) (string, error) {
+	var savedPath string
+	err := e.withClient(ctx, func(client *Client) error {
+		resp, err := client.DownloadBackup(id)
+		if err != nil {
+			return fmt.Errorf("failed to download backup: %w", err)
+		}
+		defer resp.Body.Close()
+
+		filename := extractFilenameFromResponse(resp, id)
+		if e.options.OutputFile != "" {
+			filename = e.options.OutputFile
+		}
+
+		transfer := e.options.Transfer
+		if transfer.Progress == nil {
+			transfer.Progress = e.options.Progress
+		}
+		savedPath, err = SaveStream(ctx, resp.Body, resp.ContentLength, e.options.OutputDir, filename, e.options.ShowProgress, transfer)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return savedPath, nil
+}
+
+func (e *managementEngine) Restore(ctx context.Context, id string) (*RestoreResult, error) {
+	id, err := e.resolveID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return e.restore(ctx, id)
+}
+
+// restore restores a resolved backup ID
+func (e *managementEngine) restore(ctx context.Context, backupID string) (*RestoreResult, error) {
+	if err := pkg.GuardMutation(ctx, "restore", "backup "+backupID); err != nil {
+		return nil, err
+	}
+	var result *RestoreResult
+	err := e.withClient(ctx, func(client *Client) error {
+		if e.options.RequestTimeout > 0 {
+			client.SetTimeout(e.options.RequestTimeout)
+		}
+
+		// Test connection first
+		if err := client.TestConnection(); err != nil {
+			return fmt.Errorf("management API connection failed: %w", err)
+		}
+
+		restoreResp, err := client.RestoreBackup(backupID)
+		if err != nil {
+			return fmt.Errorf("failed to initiate restore: %w", err)
+		}
+
+		e.options.Progress.emit(ProgressEvent{Phase: PhaseRestore, BackupID: backupID, Message: "restore " + restoreResp.ID + " initiated"})
+		if e.options.ShowProgress && e.options.Progress == nil {
+			fmt.Printf("Waiting for restore %s to complete...", restoreResp.ID)
+		}
+
+		if err := waitForRestoreCompletion(client, backupID, e.options); err != nil {
+			pkg.RecordInterruption(ctx, fmt.Sprintf("wait for restore of backup %s (the restore continues on the broker)", backupID))
+			return err
+		}
+		if e.options.ShowProgress && e.options.Progress == nil {
+			fmt.Println()
+		}
+
+		status, err := client.GetBackupStatus(backupID)
+		if err != nil {
+			return fmt.Errorf("failed to get final restore status: %w", err)
+		}
+
+		result = &RestoreResult{
+			RestoreID: restoreResp.ID,
+			BackupID:  backupID,
+			Status:    status.Status,
+			Message:   status.Message,
+		}
+		return nil
+	})
+	return result, err
+}
+
+// resolveID maps "latest" to the ID of the newest backup
+func (e *managementEngine) resolveID(ctx context.Context, id string) (string, error) {
+	if id != "latest" {
+		return id, nil
+	}
+	backups, err := e.List(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list backups to find latest: %w", err)
+	}
+	if len(backups) == 0 {
+		return "", fmt.Errorf("no backups found")
+	}
+	return backups[0].ID, nil // Already sorted newest first
+}
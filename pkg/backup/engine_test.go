@@ -0,0 +1,113 @@
+package backup
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"kubectl-broker/pkg"
+)
+
+// localDialer forwards to the port of a test server and counts the opened connections
+func localDialer(t *testing.T, serverURL string, forwards *int) pkg.PortForwardDialer {
+	t.Helper()
+	parsed, err := url.Parse(serverURL)
+	if err != nil {
+		t.Fatalf("invalid server URL: %v", err)
+	}
+	port, err := strconv.Atoi(parsed.Port())
+	if err != nil {
+		t.Fatalf("invalid server port: %v", err)
+	}
+	return pkg.PortForwardDialerFunc(func(ctx context.Context, fn func(localPort int) error) error {
+		*forwards++
+		return fn(port)
+	})
+}
+
+func TestEngineStatusResolvesLatest(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/management/backups":
+			_, _ = w.Write([]byte(`{"items":[{"id":"old","createdAt":"2025-01-01T00:00:00Z"},{"id":"new","createdAt":"2025-01-02T00:00:00Z"}]}`))
+		case "/api/v1/management/backups/new":
+			_, _ = w.Write([]byte(`{"backup":{"id":"new","state":"COMPLETED","bytes":42}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	var forwards int
+	engine := NewEngine(localDialer(t, server.URL, &forwards), BackupOptions{})
+
+	status, err := engine.Status(context.Background(), "latest")
+	if err != nil {
+		t.Fatalf("Status returned error: %v", err)
+	}
+	if status.ID != "new" || status.Status != StatusCompleted || status.Size != 42 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+	if forwards != 2 {
+		t.Fatalf("expected one connection for the list and one for the status, got %d", forwards)
+	}
+}
+
+func TestEngineListSortsNewestFirst(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"items":[{"id":"a","createdAt":"2025-01-01T00:00:00Z"},{"id":"c","createdAt":"2025-01-03T00:00:00Z"},{"id":"b","createdAt":"2025-01-02T00:00:00Z"}]}`))
+	}))
+	defer server.Close()
+
+	var forwards int
+	backups, err := NewEngine(localDialer(t, server.URL, &forwards), BackupOptions{}).List(context.Background())
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(backups) != 3 || backups[0].ID != "c" || backups[2].ID != "a" {
+		t.Fatalf("unexpected order: %+v", backups)
+	}
+}
+
+func TestEngineRestoreReturnsResult(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/management/backups":
+			_, _ = w.Write([]byte(`{"items":[]}`))
+		case "/api/v1/management/restores":
+			_, _ = w.Write([]byte(`{"id":"r1","status":"RESTORE_IN_PROGRESS","backupId":"b1"}`))
+		case "/api/v1/management/backups/b1":
+			_, _ = w.Write([]byte(`{"backup":{"id":"b1","state":"RESTORE_COMPLETED","message":"restored 3 nodes"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	var events []ProgressEvent
+	var forwards int
+	engine := NewEngine(localDialer(t, server.URL, &forwards), BackupOptions{
+		Progress: func(event ProgressEvent) { events = append(events, event) },
+	})
+
+	result, err := engine.Restore(context.Background(), "b1")
+	if err != nil {
+		t.Fatalf("Restore returned error: %v", err)
+	}
+	want := RestoreResult{RestoreID: "r1", BackupID: "b1", Status: StatusRestoreCompleted, Message: "restored 3 nodes"}
+	if *result != want {
+		t.Fatalf("result = %+v, want %+v", *result, want)
+	}
+	if len(events) == 0 || events[0].Phase != PhaseRestore || !events[len(events)-1].Done {
+		t.Fatalf("unexpected progress events: %+v", events)
+	}
+}
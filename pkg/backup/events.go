@@ -0,0 +1,71 @@
+package backup
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Phases reported in progress events
+const (
+	PhaseCreate   = "create"   // backup triggered on the broker
+	PhaseBackup   = "backup"   // broker is writing the backup
+	PhaseDownload = "download" // archive is streamed to disk
+	PhaseRestore  = "restore"  // broker is restoring a backup
+	PhaseMove     = "move"     // backup directory is moved or copied to --destination on the pod
+	PhaseClone    = "clone"    // backup directory is streamed to a pod of another StatefulSet
+)
+
+// ProgressEvent is a structured progress update of a long-running backup operation. The CLI
+// progress bar and the json-lines output both render these events.
+type ProgressEvent struct {
+	Time       time.Time `json:"time"`
+	Phase      string    `json:"phase"`
+	BackupID   string    `json:"backupId,omitempty"`
+	Percent    int       `json:"percent"`              // 0-100; 0 while the API reports no progress
+	Bytes      int64     `json:"bytes,omitempty"`      // bytes written or transferred so far
+	TotalBytes int64     `json:"totalBytes,omitempty"` // expected bytes when known
+	Message    string    `json:"message,omitempty"`
+	Done       bool      `json:"done,omitempty"` // last event of the phase
+	Error      string    `json:"error,omitempty"`
+}
+
+// ProgressFunc receives progress events on the goroutine running the operation
+type ProgressFunc func(ProgressEvent)
+
+// emit timestamps and delivers an event; a nil ProgressFunc drops it
+func (fn ProgressFunc) emit(event ProgressEvent) {
+	if fn == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	fn(event)
+}
+
+// NewJSONLinesProgress writes each event as one JSON object per line, for automation that
+// follows long backups
+func NewJSONLinesProgress(out io.Writer) ProgressFunc {
+	var mu sync.Mutex
+	encoder := json.NewEncoder(out)
+	return func(event ProgressEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = encoder.Encode(event)
+	}
+}
+
+// progressSink returns the Progress callback, or a progress bar labeled label on stdout when
+// only ShowProgress is set
+func (o BackupOptions) progressSink(label string) ProgressFunc {
+	if o.Progress != nil {
+		return o.Progress
+	}
+	if o.ShowProgress {
+		return NewProgressBar(os.Stdout, label).Handle
+	}
+	return nil
+}
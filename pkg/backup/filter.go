@@ -0,0 +1,146 @@
+package backup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"kubectl-broker/pkg/units"
+)
+
+// List sort keys and status filters accepted by ParseListFilter
+const (
+	SortByCreated = "created"
+	SortBySize    = "size"
+
+	ListStatusCompleted = "completed"
+	ListStatusFailed    = "failed"
+)
+
+// ListFilter narrows and orders backup listings. The zero value keeps every entry,
+// sorted newest first.
+type ListFilter struct {
+	Since  time.Time // Only entries created at or after this time
+	Until  time.Time // Only entries created at or before this time
+	Status string    // ListStatusCompleted, ListStatusFailed or empty for all
+	Limit  int       // Maximum number of entries returned (0 for no limit)
+	SortBy string    // SortByCreated (default) or SortBySize, both descending
+}
+
+// ListEntry holds the attributes a ListFilter looks at. Status is normalized to
+// ListStatusCompleted, ListStatusFailed or empty for entries still in progress.
+type ListEntry struct {
+	CreatedAt time.Time
+	SizeBytes int64
+	Status    string
+}
+
+// ParseListFilter validates list flags. since and until accept RFC 3339 timestamps,
+// dates (2006-01-02) or ages relative to now such as 36h, 7d or 1w2d.
+func ParseListFilter(since, until, status, sortBy string, limit int, now time.Time) (ListFilter, error) {
+	filter := ListFilter{Limit: limit}
+
+	if limit < 0 {
+		return filter, fmt.Errorf("invalid limit %d\n\nPlease either:\n- Use a positive number: --limit 20\n- Omit --limit to list all backups", limit)
+	}
+
+	var err error
+	if filter.Since, err = parseListTime(since, now); err != nil {
+		return filter, fmt.Errorf("invalid --since value %q\n\nPlease either:\n- Use an age: --since 7d or --since 1w2d\n- Use a date or timestamp: --since 2024-01-31", since)
+	}
+	if filter.Until, err = parseListTime(until, now); err != nil {
+		return filter, fmt.Errorf("invalid --until value %q\n\nPlease either:\n- Use an age: --until 24h or --until 1d12h\n- Use a date or timestamp: --until 2024-01-31T12:00:00Z", until)
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && filter.Until.Before(filter.Since) {
+		return filter, fmt.Errorf("--until (%s) is before --since (%s)", filter.Until.Format(time.RFC3339), filter.Since.Format(time.RFC3339))
+	}
+
+	switch value := strings.ToLower(strings.TrimSpace(status)); value {
+	case "", ListStatusCompleted, ListStatusFailed:
+		filter.Status = value
+	default:
+		return filter, fmt.Errorf("invalid status %q. Supported values: %s, %s", status, ListStatusCompleted, ListStatusFailed)
+	}
+
+	switch value := strings.ToLower(strings.TrimSpace(sortBy)); value {
+	case "", SortByCreated:
+		filter.SortBy = SortByCreated
+	case SortBySize:
+		filter.SortBy = SortBySize
+	default:
+		return filter, fmt.Errorf("invalid sort key %q. Supported values: %s, %s", sortBy, SortByCreated, SortBySize)
+	}
+
+	return filter, nil
+}
+
+// OnlyLimit reports whether the filter does nothing but cap the number of entries,
+// in which case the limit can be passed on to the server.
+func (f ListFilter) OnlyLimit() bool {
+	return f.Since.IsZero() && f.Until.IsZero() && f.Status == "" && (f.SortBy == "" || f.SortBy == SortByCreated)
+}
+
+// FilterList applies the filter to items, using entry to read each item's attributes.
+// The input slice is not modified.
+func FilterList[T any](items []T, filter ListFilter, entry func(T) ListEntry) []T {
+	result := make([]T, 0, len(items))
+	for _, item := range items {
+		e := entry(item)
+		if !filter.Since.IsZero() && e.CreatedAt.Before(filter.Since) {
+			continue
+		}
+		if !filter.Until.IsZero() && e.CreatedAt.After(filter.Until) {
+			continue
+		}
+		if filter.Status != "" && e.Status != filter.Status {
+			continue
+		}
+		result = append(result, item)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := entry(result[i]), entry(result[j])
+		if filter.SortBy == SortBySize && a.SizeBytes != b.SizeBytes {
+			return a.SizeBytes > b.SizeBytes
+		}
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result
+}
+
+// ListEntryFor maps a management API backup to its filter attributes
+func ListEntryFor(info BackupInfo) ListEntry {
+	entry := ListEntry{CreatedAt: info.CreatedAt, SizeBytes: info.Size}
+	switch info.Status {
+	case StatusCompleted, StatusRestoreCompleted, StatusRestoreInProgress, StatusRestoreFailed:
+		// Restore states describe the last restore; the backup itself is complete
+		entry.Status = ListStatusCompleted
+	case StatusFailed:
+		entry.Status = ListStatusFailed
+	}
+	return entry
+}
+
+func parseListTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+
+	age, err := units.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return now.Add(-age), nil
+}
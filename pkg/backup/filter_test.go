@@ -0,0 +1,64 @@
+package backup
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilterListAppliesRangeStatusSortAndLimit(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	backups := []BackupInfo{
+		{ID: "old", Status: StatusCompleted, CreatedAt: now.Add(-30 * 24 * time.Hour), Size: 900},
+		{ID: "failed", Status: StatusFailed, CreatedAt: now.Add(-2 * 24 * time.Hour), Size: 10},
+		{ID: "small", Status: StatusCompleted, CreatedAt: now.Add(-24 * time.Hour), Size: 100},
+		{ID: "large", Status: StatusCompleted, CreatedAt: now.Add(-3 * 24 * time.Hour), Size: 500},
+		{ID: "running", Status: StatusInProgress, CreatedAt: now.Add(-time.Hour), Size: 0},
+	}
+
+	filter, err := ParseListFilter("7d", "", "completed", "size", 2, now)
+	if err != nil {
+		t.Fatalf("ParseListFilter returned error: %v", err)
+	}
+
+	got := FilterList(backups, filter, ListEntryFor)
+	if len(got) != 2 || got[0].ID != "large" || got[1].ID != "small" {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+
+	filter, err = ParseListFilter("", "2024-03-09", "", "", 0, now)
+	if err != nil {
+		t.Fatalf("ParseListFilter returned error: %v", err)
+	}
+
+	got = FilterList(backups, filter, ListEntryFor)
+	want := []string{"failed", "large", "old"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %+v", want, got)
+	}
+	for i := range want {
+		if got[i].ID != want[i] {
+			t.Fatalf("expected %v newest first, got %+v", want, got)
+		}
+	}
+}
+
+func TestParseListFilterRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	cases := map[string]func() error{
+		"since":  func() error { _, err := ParseListFilter("yesterday", "", "", "", 0, now); return err },
+		"range":  func() error { _, err := ParseListFilter("1d", "7d", "", "", 0, now); return err },
+		"status": func() error { _, err := ParseListFilter("", "", "running", "", 0, now); return err },
+		"sort":   func() error { _, err := ParseListFilter("", "", "", "name", 0, now); return err },
+		"limit":  func() error { _, err := ParseListFilter("", "", "", "", -1, now); return err },
+	}
+
+	for name, parse := range cases {
+		if parse() == nil {
+			t.Errorf("expected %s to be rejected", name)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"kubectl-broker/pkg"
+)
+
+// maxMetadataSize bounds the metadata files that are read while inspecting a backup
+const maxMetadataSize = 64 * 1024
+
+// rootStore groups files that sit directly in the backup root, usually metadata
+const rootStore = "(root)"
+
+// ArchiveEntry is a single file in a backup, relative to the backup root
+type ArchiveEntry struct {
+	Path string
+	Size int64
+}
+
+// StoreSummary aggregates the files of one top-level folder, typically a persistence store
+type StoreSummary struct {
+	Name      string `json:"name" yaml:"name"`
+	Files     int    `json:"files" yaml:"files"`
+	SizeBytes int64  `json:"sizeBytes" yaml:"sizeBytes"`
+}
+
+// BackupContents describes what a backup contains without restoring it
+type BackupContents struct {
+	BackupID         string            `json:"backupId,omitempty" yaml:"backupId,omitempty"`
+	Source           string            `json:"source" yaml:"source"`
+	HiveMQVersion    string            `json:"hivemqVersion,omitempty" yaml:"hivemqVersion,omitempty"`
+	RetainedMessages int64             `json:"retainedMessages" yaml:"retainedMessages"` // -1 when not recorded
+	Files            int               `json:"files" yaml:"files"`
+	TotalBytes       int64             `json:"totalBytes" yaml:"totalBytes"`
+	Stores           []StoreSummary    `json:"stores" yaml:"stores"`
+	Metadata         map[string]string `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+// InspectArchive lists a downloaded backup archive (tar or tar.gz) and reads its metadata
+func InspectArchive(archivePath string) (*BackupContents, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup archive: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	reader := bufio.NewReader(file)
+	var stream io.Reader = reader
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzip stream of %s: %w", archivePath, err)
+		}
+		defer func() { _ = gz.Close() }()
+		stream = gz
+	}
+
+	var entries []ArchiveEntry
+	metadata := make(map[string]string)
+	archive := tar.NewReader(stream)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backup archive %s: %w", archivePath, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		entries = append(entries, ArchiveEntry{Path: header.Name, Size: header.Size})
+		if isMetadataFile(header.Name) && header.Size <= maxMetadataSize {
+			content, err := io.ReadAll(io.LimitReader(archive, maxMetadataSize))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s from backup archive: %w", header.Name, err)
+			}
+			mergeMetadata(metadata, header.Name, content)
+		}
+	}
+
+	contents := SummarizeEntries(stripCommonRoot(entries), metadata)
+	contents.Source = filepath.Base(archivePath)
+	return contents, nil
+}
+
+// InspectBackupOnPod inspects a backup directory on a broker pod via exec
+func InspectBackupOnPod(ctx context.Context, k8sClient *pkg.K8sClient, namespace, podName, backupID string) (*BackupContents, error) {
+	backupFolder, err := GetBackupFolder(ctx, k8sClient, namespace, podName)
+	if err != nil {
+		return nil, err
+	}
+	backupDir := path.Join(backupFolder, backupID)
+
+	// find + du -k lists every file with its size in KiB on both busybox and coreutils images
+	output, err := k8sClient.ExecCommand(ctx, namespace, podName, []string{"find", backupDir, "-type", "f", "-exec", "du", "-k", "{}", "+"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backup %s on pod %s: %w", backupID, podName, err)
+	}
+	entries := parseDuOutput(output, backupDir)
+
+	metadata := make(map[string]string)
+	for _, entry := range entries {
+		if !isMetadataFile(entry.Path) || entry.Size > maxMetadataSize {
+			continue
+		}
+		content, err := k8sClient.ExecCommand(ctx, namespace, podName, []string{"cat", path.Join(backupDir, entry.Path)})
+		if err != nil {
+			continue
+		}
+		mergeMetadata(metadata, entry.Path, []byte(content))
+	}
+
+	contents := SummarizeEntries(entries, metadata)
+	contents.BackupID = backupID
+	contents.Source = fmt.Sprintf("pod %s:%s", podName, backupDir)
+	return contents, nil
+}
+
+// SummarizeEntries groups files into stores and extracts the version and retained message count
+func SummarizeEntries(entries []ArchiveEntry, metadata map[string]string) *BackupContents {
+	contents := &BackupContents{RetainedMessages: -1, Stores: []StoreSummary{}}
+	stores := make(map[string]*StoreSummary)
+	for _, entry := range entries {
+		name := rootStore
+		if dir, _, found := strings.Cut(entry.Path, "/"); found {
+			name = dir
+		}
+		store, ok := stores[name]
+		if !ok {
+			store = &StoreSummary{Name: name}
+			stores[name] = store
+		}
+		store.Files++
+		store.SizeBytes += entry.Size
+		contents.Files++
+		contents.TotalBytes += entry.Size
+	}
+
+	for _, store := range stores {
+		contents.Stores = append(contents.Stores, *store)
+	}
+	sort.Slice(contents.Stores, func(i, j int) bool {
+		return contents.Stores[i].SizeBytes > contents.Stores[j].SizeBytes
+	})
+
+	if len(metadata) > 0 {
+		contents.Metadata = metadata
+	}
+	contents.HiveMQVersion = metadataVersion(metadata)
+	if count, ok := metadataRetainedCount(metadata); ok {
+		contents.RetainedMessages = count
+	}
+	return contents
+}
+
+// stripCommonRoot removes the backup ID folder that archives wrap around the backup contents
+func stripCommonRoot(entries []ArchiveEntry) []ArchiveEntry {
+	cleaned := make([]ArchiveEntry, 0, len(entries))
+	root := ""
+	for _, entry := range entries {
+		name := strings.TrimPrefix(path.Clean(entry.Path), "./")
+		dir, _, found := strings.Cut(name, "/")
+		if !found || (root != "" && dir != root) {
+			root = "-"
+		} else if root == "" {
+			root = dir
+		}
+		cleaned = append(cleaned, ArchiveEntry{Path: name, Size: entry.Size})
+	}
+	if root == "" || root == "-" {
+		return cleaned
+	}
+
+	for i := range cleaned {
+		cleaned[i].Path = strings.TrimPrefix(cleaned[i].Path, root+"/")
+	}
+	return cleaned
+}
+
+// parseDuOutput turns "du -k" lines into entries relative to root
+func parseDuOutput(output, root string) []ArchiveEntry {
+	var entries []ArchiveEntry
+	for _, line := range strings.Split(output, "\n") {
+		sizeField, filePath, found := strings.Cut(strings.TrimSpace(line), "\t")
+		if !found {
+			continue
+		}
+		kb, err := strconv.ParseInt(strings.TrimSpace(sizeField), 10, 64)
+		if err != nil {
+			continue
+		}
+		relative := strings.TrimPrefix(strings.TrimPrefix(filePath, root), "/")
+		if relative == "" {
+			continue
+		}
+		entries = append(entries, ArchiveEntry{Path: relative, Size: kb * 1024})
+	}
+	return entries
+}
+
+// isMetadataFile reports whether a file likely holds backup metadata rather than store data
+func isMetadataFile(name string) bool {
+	base := strings.ToLower(path.Base(name))
+	switch path.Ext(base) {
+	case ".properties", ".json", ".info":
+		return true
+	}
+	return strings.Contains(base, "metadata") || strings.Contains(base, "manifest")
+}
+
+// mergeMetadata parses JSON objects or key=value / key: value lines into metadata
+func mergeMetadata(metadata map[string]string, name string, content []byte) {
+	if strings.EqualFold(path.Ext(name), ".json") {
+		var document map[string]any
+		if err := json.Unmarshal(content, &document); err == nil {
+			flattenMetadata(metadata, "", document)
+			return
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		separator := strings.IndexAny(line, "=:")
+		if separator <= 0 {
+			continue
+		}
+		metadata[strings.TrimSpace(line[:separator])] = strings.TrimSpace(line[separator+1:])
+	}
+}
+
+func flattenMetadata(metadata map[string]string, prefix string, document map[string]any) {
+	for key, value := range document {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			flattenMetadata(metadata, key, v)
+		case []any:
+			// Lists are not needed to judge compatibility
+		default:
+			metadata[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// metadataVersion finds the HiveMQ version recorded in the metadata
+func metadataVersion(metadata map[string]string) string {
+	for _, key := range []string{"hivemq.version", "hivemq-version", "hivemqVersion", "hivemq_version", "broker.version", "version"} {
+		for candidate, value := range metadata {
+			if strings.EqualFold(candidate, key) && value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// metadataRetainedCount finds a retained message count in the metadata
+func metadataRetainedCount(metadata map[string]string) (int64, bool) {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		lower := strings.ToLower(key)
+		if !strings.Contains(lower, "retained") || !(strings.Contains(lower, "count") || strings.Contains(lower, "messages")) {
+			continue
+		}
+		if count, err := strconv.ParseInt(strings.TrimSpace(metadata[key]), 10, 64); err == nil {
+			return count, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,88 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInspectArchiveSummarizesStores(t *testing.T) {
+	t.Parallel()
+
+	archivePath := filepath.Join(t.TempDir(), "b1.tar.gz")
+	file, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatalf("create archive: %v", err)
+	}
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	files := map[string]string{
+		"b1/backup.properties":                    "hivemq.version=4.28.0\nretained.messages.count=42\n",
+		"b1/retained_messages/data-0.db":          "0123456789",
+		"b1/retained_messages/data-1.db":          "01234",
+		"b1/client_sessions/sessions.db":          "abc",
+		"b1/subscriptions/nested/subscription.db": "x",
+	}
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("write header: %v", err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatalf("write content: %v", err)
+		}
+	}
+	for _, closer := range []interface{ Close() error }{tw, gz, file} {
+		if err := closer.Close(); err != nil {
+			t.Fatalf("close archive: %v", err)
+		}
+	}
+
+	contents, err := InspectArchive(archivePath)
+	if err != nil {
+		t.Fatalf("InspectArchive: %v", err)
+	}
+
+	if contents.HiveMQVersion != "4.28.0" || contents.RetainedMessages != 42 {
+		t.Errorf("unexpected metadata: version=%q retained=%d", contents.HiveMQVersion, contents.RetainedMessages)
+	}
+	if contents.Files != 5 {
+		t.Errorf("expected 5 files, got %d", contents.Files)
+	}
+
+	want := map[string]StoreSummary{
+		"retained_messages": {Name: "retained_messages", Files: 2, SizeBytes: 15},
+		"client_sessions":   {Name: "client_sessions", Files: 1, SizeBytes: 3},
+		"subscriptions":     {Name: "subscriptions", Files: 1, SizeBytes: 1},
+		rootStore:           {Name: rootStore, Files: 1, SizeBytes: int64(len(files["b1/backup.properties"]))},
+	}
+	if len(contents.Stores) != len(want) {
+		t.Fatalf("expected %d stores, got %+v", len(want), contents.Stores)
+	}
+	for _, store := range contents.Stores {
+		if store != want[store.Name] {
+			t.Errorf("store %s = %+v, want %+v", store.Name, store, want[store.Name])
+		}
+	}
+	for i := 1; i < len(contents.Stores); i++ {
+		if contents.Stores[i].SizeBytes > contents.Stores[i-1].SizeBytes {
+			t.Errorf("expected stores sorted by size, got %+v", contents.Stores)
+		}
+	}
+}
+
+func TestParseDuOutput(t *testing.T) {
+	t.Parallel()
+
+	output := "4\t/opt/hivemq/backup/b1/metadata.json\n128\t/opt/hivemq/backup/b1/retained_messages/data.db\ngarbage\n"
+	entries := parseDuOutput(output, "/opt/hivemq/backup/b1")
+
+	contents := SummarizeEntries(entries, map[string]string{"hivemqVersion": "4.30.1"})
+	if contents.TotalBytes != 132*1024 || contents.Files != 2 {
+		t.Errorf("unexpected totals: %d files, %d bytes", contents.Files, contents.TotalBytes)
+	}
+	if contents.HiveMQVersion != "4.30.1" || contents.RetainedMessages != -1 {
+		t.Errorf("unexpected metadata: version=%q retained=%d", contents.HiveMQVersion, contents.RetainedMessages)
+	}
+}
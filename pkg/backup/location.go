@@ -0,0 +1,61 @@
+package backup
+
+import (
+	"context"
+	"log/slog"
+	"path"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+)
+
+// BackupLocation is the pod and directory holding a backup created by the management API
+type BackupLocation struct {
+	Pod  string `json:"pod"`
+	Node string `json:"node,omitempty"`
+	Path string `json:"path"`
+}
+
+// LocateBackups lists the backup folder of every running pod of the StatefulSet once and maps
+// each backup ID found there to its location. Pods that cannot be listed are skipped.
+func LocateBackups(ctx context.Context, k8sClient *pkg.K8sClient, namespace, statefulSetName string) (map[string]BackupLocation, error) {
+	pods, err := k8sClient.GetStatefulSetPods(ctx, namespace, statefulSetName)
+	if err != nil {
+		return nil, err
+	}
+
+	locations := make(map[string]BackupLocation)
+	for _, pod := range pods {
+		if pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		folder, err := GetBackupFolder(ctx, k8sClient, namespace, pod.Name)
+		if err != nil {
+			continue
+		}
+		output, err := k8sClient.ExecCommand(ctx, namespace, pod.Name, []string{"ls", "-1", folder})
+		if err != nil {
+			slog.Debug("Cannot list backup folder", "pod", pod.Name, "folder", folder, "error", err)
+			continue
+		}
+		for _, id := range parseBackupFolderListing(output) {
+			if _, seen := locations[id]; !seen {
+				locations[id] = BackupLocation{Pod: pod.Name, Node: pod.Spec.NodeName, Path: path.Join(folder, id)}
+			}
+		}
+	}
+	return locations, nil
+}
+
+// parseBackupFolderListing returns the entries of "ls -1" output
+func parseBackupFolderListing(output string) []string {
+	var entries []string
+	for _, line := range strings.Split(output, "\n") {
+		if entry := strings.TrimSpace(line); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
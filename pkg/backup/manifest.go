@@ -0,0 +1,179 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+)
+
+// ManifestEntry maps a backup piece to the pod that holds it
+type ManifestEntry struct {
+	Pod       string       `json:"pod"`
+	BackupID  string       `json:"backupId"`
+	Status    BackupStatus `json:"status,omitempty"`
+	Path      string       `json:"path,omitempty"`
+	SizeBytes int64        `json:"sizeBytes,omitempty"`
+	Present   bool         `json:"present"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// Manifest describes where the pieces of a backup live across the cluster
+type Manifest struct {
+	Namespace   string          `json:"namespace"`
+	StatefulSet string          `json:"statefulSet"`
+	ClusterSize int             `json:"clusterSize"`
+	AllNodes    bool            `json:"allNodes"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	Entries     []ManifestEntry `json:"entries"`
+}
+
+// PodFor returns the pod holding the given backup, if the manifest located exactly one
+func (m *Manifest) PodFor(backupID string) (string, bool) {
+	pod := ""
+	for _, entry := range m.Entries {
+		if entry.BackupID != backupID || !entry.Present {
+			continue
+		}
+		if pod != "" {
+			return "", false
+		}
+		pod = entry.Pod
+	}
+	return pod, pod != ""
+}
+
+// ErrIncompleteBackup is returned by CreateBackupOnAllNodes when the backup failed on some
+// nodes; the returned manifest lists the backups that were created
+var ErrIncompleteBackup = errors.New("backup is incomplete")
+
+// CreateBackupOnAllNodes triggers a backup on every running pod of the StatefulSet by
+// port-forwarding to each pod's management API directly, one node at a time. A backup that
+// failed on any node returns the manifest with an error wrapping ErrIncompleteBackup.
+func CreateBackupOnAllNodes(ctx context.Context, k8sClient *pkg.K8sClient, namespace, statefulSetName string, options BackupOptions) (*Manifest, error) {
+	pods, err := k8sClient.GetStatefulSetPods(ctx, namespace, statefulSetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get StatefulSet pods: %w", err)
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no pods found in StatefulSet %s", statefulSetName)
+	}
+
+	manifest := &Manifest{
+		Namespace:   namespace,
+		StatefulSet: statefulSetName,
+		ClusterSize: len(pods),
+		AllNodes:    true,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	var failed []string
+	for i := range pods {
+		pod := &pods[i]
+		if options.ShowProgress {
+			fmt.Printf("\n[%d/%d] Creating backup on %s\n", i+1, len(pods), pod.Name)
+		}
+
+		entry := ManifestEntry{Pod: pod.Name}
+		info, err := createBackupOnPod(ctx, k8sClient, pod, options)
+		if err != nil {
+			failed = append(failed, pod.Name)
+			entry.Error = err.Error()
+			manifest.Entries = append(manifest.Entries, entry)
+			if options.ShowProgress {
+				fmt.Printf("Backup on %s failed: %v\n", pod.Name, err)
+			}
+			continue
+		}
+
+		entry.BackupID = info.ID
+		entry.Status = info.Status
+		entry.SizeBytes = info.Size
+		locateOnPod(ctx, k8sClient, namespace, pod.Name, &entry)
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+
+	switch {
+	case len(failed) == len(pods):
+		return manifest, fmt.Errorf("backup failed on all %d nodes", len(failed))
+	case len(failed) > 0:
+		return manifest, fmt.Errorf("%w: failed on %d of %d nodes (%s)", ErrIncompleteBackup, len(failed), len(pods), strings.Join(failed, ", "))
+	}
+	return manifest, nil
+}
+
+// LocateBackup builds a manifest for an existing backup by checking which pods hold its directory
+func LocateBackup(ctx context.Context, k8sClient *pkg.K8sClient, namespace, statefulSetName, backupID string) (*Manifest, error) {
+	pods, err := k8sClient.GetStatefulSetPods(ctx, namespace, statefulSetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get StatefulSet pods: %w", err)
+	}
+
+	manifest := &Manifest{
+		Namespace:   namespace,
+		StatefulSet: statefulSetName,
+		ClusterSize: len(pods),
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	for _, pod := range pods {
+		entry := ManifestEntry{Pod: pod.Name, BackupID: backupID}
+		if pod.Status.Phase != v1.PodRunning {
+			entry.Error = fmt.Sprintf("pod is %s", pod.Status.Phase)
+		} else {
+			locateOnPod(ctx, k8sClient, namespace, pod.Name, &entry)
+		}
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+
+	return manifest, nil
+}
+
+// createBackupOnPod creates a backup through a direct port-forward to the pod's management API
+func createBackupOnPod(ctx context.Context, k8sClient *pkg.K8sClient, pod *v1.Pod, options BackupOptions) (*BackupInfo, error) {
+	if err := pkg.ValidatePodStatus(pod); err != nil {
+		return nil, err
+	}
+
+	apiPort, err := k8sClient.DiscoverAPIPort(pod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover API port: %w", err)
+	}
+
+	return newManagementEngine(pkg.NewPodDialer(k8sClient, pod, apiPort, false), options).Create(ctx)
+}
+
+// locateOnPod fills in the path, presence and on-disk size of a backup directory on a pod
+func locateOnPod(ctx context.Context, k8sClient *pkg.K8sClient, namespace, podName string, entry *ManifestEntry) {
+	backupFolder, err := GetBackupFolder(ctx, k8sClient, namespace, podName)
+	if err != nil {
+		entry.Error = err.Error()
+		return
+	}
+
+	backupDir := filepath.Join(backupFolder, entry.BackupID)
+	exists, _ := directoryExistsOnPod(ctx, k8sClient, namespace, podName, backupDir)
+	if !exists {
+		return
+	}
+
+	entry.Present = true
+	entry.Path = backupDir
+
+	output, err := k8sClient.ExecCommand(ctx, namespace, podName, []string{"du", "-sk", backupDir})
+	if err != nil {
+		return
+	}
+	if fields := strings.Fields(output); len(fields) > 0 {
+		if kb, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+			entry.SizeBytes = kb * 1024
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package backup
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"kubectl-broker/pkg"
+)
+
+// moveProgressInterval is how often the size of the copied directory is sampled on the pod
+const moveProgressInterval = 2 * time.Second
+
+// copiedPrefix marks the progress lines printed by copyWithProgressScript
+const copiedPrefix = "copied-kb "
+
+// copyWithProgressScript copies $1 into the directory $2 with cp -a and prints the size copied
+// so far every $3 seconds, so large backups do not move silently. It exits with cp's status.
+const copyWithProgressScript = `src="$1"; dst="$2/${1##*/}"; interval="$3"
+cp -a "$src" "$2/" &
+pid=$!
+while kill -0 "$pid" 2>/dev/null; do
+	echo "` + copiedPrefix + `$(du -sk "$dst" 2>/dev/null | cut -f1)"
+	sleep "$interval"
+done
+wait "$pid"`
+
+// MoveOptions controls how a backup directory is relocated on its pod
+type MoveOptions struct {
+	Copy         bool         // keep the original in the backup folder
+	ShowProgress bool         // render a progress bar when Progress is nil
+	Progress     ProgressFunc // receives PhaseMove events, e.g. for --progress-format json-lines
+}
+
+func (o MoveOptions) verb() string {
+	if o.Copy {
+		return "copy"
+	}
+	return "move"
+}
+
+// volumeUsage is one filesystem line of "df -Pk"
+type volumeUsage struct {
+	Filesystem  string
+	AvailableKB int64
+	MountPoint  string
+}
+
+// checkDestinationVolume refuses destinations on the volume that holds the backup folder and
+// destinations without enough free space, and returns the size of the backup in bytes
+func checkDestinationVolume(ctx context.Context, k8sClient *pkg.K8sClient, namespace, podName, backupDir, destination string) (int64, error) {
+	output, err := k8sClient.ExecCommand(ctx, namespace, podName, []string{"df", "-Pk", backupDir, destination})
+	if err != nil {
+		return 0, fmt.Errorf("cannot determine the volumes of %s and %s on pod %s: %w", backupDir, destination, podName, err)
+	}
+	volumes, err := parseDf(output)
+	if err != nil {
+		return 0, err
+	}
+	if len(volumes) != 2 {
+		return 0, fmt.Errorf("unexpected df output for %s and %s: %q", backupDir, destination, output)
+	}
+	source, target := volumes[0], volumes[1]
+	if source.Filesystem == target.Filesystem && source.MountPoint == target.MountPoint {
+		return 0, fmt.Errorf("destination %s is on the same volume as the backup (%s mounted at %s); a backup there is lost together with that volume\n\nPlease either:\n- Mount a separate volume into the pod and use it as --destination\n- Omit --destination to keep the backup in the backup folder", destination, target.Filesystem, target.MountPoint)
+	}
+
+	output, err = k8sClient.ExecCommand(ctx, namespace, podName, []string{"du", "-sk", backupDir})
+	if err != nil {
+		return 0, fmt.Errorf("cannot determine the size of %s on pod %s: %w", backupDir, podName, err)
+	}
+	sizeKB, err := parseDuKilobytes(output)
+	if err != nil {
+		return 0, err
+	}
+	if sizeKB > target.AvailableKB {
+		return 0, fmt.Errorf("destination %s has %s free but the backup needs %s\n\nPlease either:\n- Free space on the volume mounted at %s\n- Choose a destination on a larger volume", destination, formatBytes(target.AvailableKB*1024), formatBytes(sizeKB*1024), target.MountPoint)
+	}
+	return sizeKB * 1024, nil
+}
+
+// MoveBackupDirectoryWithinPod copies a backup directory of size bytes to another volume of the
+// same pod, reporting progress while it copies, and removes the original unless options.Copy
+// is set. A failed copy leaves the original untouched.
+func MoveBackupDirectoryWithinPod(ctx context.Context, k8sClient *pkg.K8sClient, namespace, podName, backupID, backupFolder, destination string, size int64, options MoveOptions) (err error) {
+	backupDir := filepath.Join(backupFolder, backupID)
+	destinationDir := filepath.Join(destination, backupID)
+	action, label := "Moving", "Move"
+	if options.Copy {
+		action, label = "Copying", "Copy"
+	}
+
+	fmt.Printf("%s backup directory from %s to %s on pod %s (%s)\n", action, backupDir, destinationDir, podName, formatBytes(size))
+
+	progress := BackupOptions{ShowProgress: options.ShowProgress, Progress: options.Progress}.progressSink(label)
+	defer func() {
+		if err != nil {
+			progress.emit(ProgressEvent{Phase: PhaseMove, BackupID: backupID, Done: true, Error: err.Error()})
+		}
+	}()
+
+	command := []string{"sh", "-c", copyWithProgressScript, "sh", backupDir, destination, strconv.Itoa(int(moveProgressInterval.Seconds()))}
+	stream, err := k8sClient.ExecCommandStream(ctx, namespace, podName, command)
+	if err != nil {
+		return fmt.Errorf("failed to start copy on pod %s: %w", podName, err)
+	}
+	defer stream.Close()
+
+	var output []string
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		line := scanner.Text()
+		value, ok := strings.CutPrefix(line, copiedPrefix)
+		if !ok {
+			output = append(output, line)
+			continue
+		}
+		if copiedKB, parseErr := strconv.ParseInt(strings.TrimSpace(value), 10, 64); parseErr == nil {
+			copied := copiedKB * 1024
+			progress.emit(ProgressEvent{Phase: PhaseMove, BackupID: backupID, Percent: copyPercent(copied, size), Bytes: copied, TotalBytes: size})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		pkg.RecordInterruption(ctx, fmt.Sprintf("%s of %s to %s on pod %s (original unchanged, partial copy may remain)", options.verb(), backupDir, destinationDir, podName))
+		if len(output) > 0 {
+			err = fmt.Errorf("%w: %s", err, strings.Join(output, "; "))
+		}
+		return fmt.Errorf("copying %s failed; the original is unchanged and a partial copy may remain at %s: %w", backupDir, destinationDir, err)
+	}
+
+	if exists, _ := directoryExistsOnPod(ctx, k8sClient, namespace, podName, destinationDir); !exists {
+		return fmt.Errorf("failed to verify copied directory: %s not found", destinationDir)
+	}
+	progress.emit(ProgressEvent{Phase: PhaseMove, BackupID: backupID, Percent: 100, Bytes: size, TotalBytes: size, Done: true})
+
+	if !options.Copy {
+		if _, err := k8sClient.ExecCommand(ctx, namespace, podName, []string{"rm", "-rf", backupDir}); err != nil {
+			return fmt.Errorf("backup copied to %s but removing the original %s failed: %w", destinationDir, backupDir, err)
+		}
+		if exists, _ := directoryExistsOnPod(ctx, k8sClient, namespace, podName, backupDir); exists {
+			return fmt.Errorf("move failed: source directory still exists at %s", backupDir)
+		}
+	}
+
+	if options.Copy {
+		fmt.Printf("Successfully copied backup directory to %s (original kept at %s)\n", destinationDir, backupDir)
+	} else {
+		fmt.Printf("Successfully moved backup directory to %s\n", destinationDir)
+	}
+	return nil
+}
+
+// copyPercent estimates the progress of a copy; it stays below 100 until cp finished, since
+// du counts allocated blocks rather than file sizes
+func copyPercent(copied, total int64) int {
+	if total <= 0 || copied <= 0 {
+		return 0
+	}
+	return int(min(copied*100/total, 99))
+}
+
+// parseDf parses "df -Pk" output into one entry per filesystem line
+func parseDf(output string) ([]volumeUsage, error) {
+	var volumes []volumeUsage
+	for i, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Fields(line)
+		if i == 0 && len(fields) > 0 && fields[0] == "Filesystem" {
+			continue
+		}
+		if len(fields) < 6 {
+			return nil, fmt.Errorf("unexpected df output line: %q", line)
+		}
+		available, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected available space in df output line %q: %w", line, err)
+		}
+		volumes = append(volumes, volumeUsage{
+			Filesystem:  fields[0],
+			AvailableKB: available,
+			MountPoint:  strings.Join(fields[5:], " "),
+		})
+	}
+	return volumes, nil
+}
+
+// parseDuKilobytes reads the size from "du -sk" output
+func parseDuKilobytes(output string) (int64, error) {
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("empty du output")
+	}
+	size, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected du output %q: %w", output, err)
+	}
+	return size, nil
+}
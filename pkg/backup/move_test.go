@@ -0,0 +1,58 @@
+package backup
+
+import "testing"
+
+func TestParseDf(t *testing.T) {
+	t.Parallel()
+
+	output := `Filesystem     1024-blocks     Used Available Capacity Mounted on
+/dev/sdb          10255636  8123456   2115796      80% /opt/hivemq/backup
+/dev/sdc          51475068  1048576  47788428       3% /mnt/backup volume
+`
+	volumes, err := parseDf(output)
+	if err != nil {
+		t.Fatalf("parseDf() error = %v", err)
+	}
+	if len(volumes) != 2 {
+		t.Fatalf("parseDf() returned %d volumes, want 2", len(volumes))
+	}
+	want := volumeUsage{Filesystem: "/dev/sdc", AvailableKB: 47788428, MountPoint: "/mnt/backup volume"}
+	if volumes[1] != want {
+		t.Errorf("volumes[1] = %+v, want %+v", volumes[1], want)
+	}
+
+	if _, err := parseDf("Filesystem 1024-blocks\n/dev/sdb 10 5"); err == nil {
+		t.Error("expected an error for truncated df output")
+	}
+}
+
+func TestParseDuKilobytes(t *testing.T) {
+	t.Parallel()
+
+	size, err := parseDuKilobytes("2097152\t/opt/hivemq/backup/20250819-143025\n")
+	if err != nil || size != 2097152 {
+		t.Fatalf("parseDuKilobytes() = %d, %v; want 2097152", size, err)
+	}
+	if _, err := parseDuKilobytes(""); err == nil {
+		t.Error("expected an error for empty du output")
+	}
+}
+
+func TestCopyPercent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		copied, total int64
+		want          int
+	}{
+		{0, 1000, 0},
+		{500, 1000, 50},
+		{1200, 1000, 99}, // du rounds up to whole blocks
+		{100, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := copyPercent(tt.copied, tt.total); got != tt.want {
+			t.Errorf("copyPercent(%d, %d) = %d, want %d", tt.copied, tt.total, got, tt.want)
+		}
+	}
+}
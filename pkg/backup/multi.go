@@ -0,0 +1,189 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+)
+
+// DefaultNamespaceConcurrency is the number of namespaces backed up at the same time
+const DefaultNamespaceConcurrency = 4
+
+// DefaultDownloadTimeout bounds each download of a bulk download when options.OperationDeadline is unset
+const DefaultDownloadTimeout = time.Hour
+
+// NamespaceTarget is a HiveMQ StatefulSet to back up in a multi-namespace run
+type NamespaceTarget struct {
+	Namespace   string `json:"namespace"`
+	StatefulSet string `json:"statefulset"`
+}
+
+// NamespaceBackupResult is the outcome of one target in a multi-namespace run
+type NamespaceBackupResult struct {
+	NamespaceTarget
+	Backup   *BackupInfo   `json:"backup,omitempty"`
+	Duration time.Duration `json:"duration"`
+	Error    error         `json:"-"`
+}
+
+// CreateBackupsConcurrently creates a backup for every target through the shared worker pool,
+// running at most concurrency backups at once. Per-target failures are reported in the results,
+// which are sorted by namespace. onDone, when set, is called as each target finishes.
+func CreateBackupsConcurrently(ctx context.Context, k8sClient *pkg.K8sClient, targets []NamespaceTarget, concurrency int, options BackupOptions, onDone func(NamespaceBackupResult)) ([]NamespaceBackupResult, error) {
+	// Progress bars of concurrent backups would overwrite each other
+	options.ShowProgress = false
+
+	results := make([]NamespaceBackupResult, len(targets))
+	var report sync.Mutex
+	finish := func(result NamespaceBackupResult) {
+		if onDone == nil {
+			return
+		}
+		report.Lock()
+		defer report.Unlock()
+		onDone(result)
+	}
+
+	// The per-job deadline must cover connecting plus waiting for the backup to finish
+	deadline := options.OperationDeadline
+	if deadline <= 0 {
+		deadline = DefaultOperationDeadline
+	}
+	err := forEachTarget(ctx, k8sClient, len(targets), concurrency, deadline+time.Minute, func(taskCtx context.Context, i int) error {
+		results[i] = NamespaceBackupResult{NamespaceTarget: targets[i]}
+		started := time.Now()
+		results[i].Backup, results[i].Error = createNamespaceBackup(taskCtx, k8sClient, targets[i], options)
+		results[i].Duration = time.Since(started)
+		finish(results[i])
+		return results[i].Error
+	}, func(i int, err error) {
+		results[i] = NamespaceBackupResult{NamespaceTarget: targets[i], Error: err}
+		finish(results[i])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Namespace != results[j].Namespace {
+			return results[i].Namespace < results[j].Namespace
+		}
+		return results[i].StatefulSet < results[j].StatefulSet
+	})
+	return results, nil
+}
+
+// DownloadResult is the outcome of one backup in a bulk download
+type DownloadResult struct {
+	Backup   BackupInfo    `json:"backup"`
+	Path     string        `json:"path,omitempty"`
+	Duration time.Duration `json:"duration"`
+	Error    error         `json:"-"`
+}
+
+// DownloadBackupsConcurrently downloads every backup through the shared worker pool, at most
+// concurrency at once, each over its own port-forward and bounded by options.OperationDeadline. Per-backup failures are reported in the
+// results, which keep the order of backups. onDone, when set, is called as each download finishes.
+func DownloadBackupsConcurrently(ctx context.Context, k8sClient *pkg.K8sClient, service *v1.Service, backups []BackupInfo, concurrency int, options BackupOptions, onDone func(DownloadResult)) ([]DownloadResult, error) {
+	// Progress output of concurrent downloads would interleave, and every file keeps its own name
+	options.ShowProgress = false
+	options.Progress = nil
+	options.Transfer.Progress = nil
+	options.OutputFile = ""
+
+	results := make([]DownloadResult, len(backups))
+	var report sync.Mutex
+	finish := func(result DownloadResult) {
+		if onDone == nil {
+			return
+		}
+		report.Lock()
+		defer report.Unlock()
+		onDone(result)
+	}
+
+	jobTimeout := options.OperationDeadline
+	if jobTimeout <= 0 {
+		jobTimeout = DefaultDownloadTimeout
+	}
+	err := forEachTarget(ctx, k8sClient, len(backups), concurrency, jobTimeout, func(taskCtx context.Context, i int) error {
+		results[i] = DownloadResult{Backup: backups[i]}
+		started := time.Now()
+		results[i].Path, results[i].Error = DownloadBackup(taskCtx, k8sClient, service, backups[i].ID, options)
+		results[i].Duration = time.Since(started)
+		finish(results[i])
+		return results[i].Error
+	}, func(i int, err error) {
+		results[i] = DownloadResult{Backup: backups[i], Error: err}
+		finish(results[i])
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func createNamespaceBackup(ctx context.Context, k8sClient *pkg.K8sClient, target NamespaceTarget, options BackupOptions) (*BackupInfo, error) {
+	service, err := k8sClient.GetAPIServiceFromStatefulSet(ctx, target.Namespace, target.StatefulSet)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := CreateBackup(ctx, k8sClient, service, options)
+	if err != nil {
+		return nil, fmt.Errorf("backup creation failed: %w", err)
+	}
+	return info, nil
+}
+
+// forEachTarget runs task for targets 0..count-1 through the shared worker pool, at most
+// concurrency at once, and waits for all of them. Targets the pool rejects are passed to
+// rejected instead. Only cancellation of ctx is returned as an error.
+func forEachTarget(ctx context.Context, k8sClient *pkg.K8sClient, count, concurrency int, jobTimeout time.Duration, task func(ctx context.Context, i int) error, rejected func(i int, err error)) error {
+	if concurrency <= 0 {
+		concurrency = DefaultNamespaceConcurrency
+	}
+
+	config := pkg.DefaultWorkerPoolConfig()
+	config.MaxWorkers = min(concurrency, count)
+	config.QueueSize = count
+	config.RequestTimeout = jobTimeout
+
+	wp := pkg.NewWorkerPoolWithContext(ctx, k8sClient, config)
+	wp.Start()
+	defer func() {
+		_ = wp.Stop()
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		job := func(taskCtx context.Context) error {
+			defer wg.Done()
+			return task(taskCtx, i)
+		}
+		if err := wp.SubmitTask(job); err != nil {
+			wg.Done()
+			rejected(i, err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -4,14 +4,13 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
-	"github.com/fatih/color"
 	v1 "k8s.io/api/core/v1"
 
 	"kubectl-broker/pkg"
@@ -19,386 +18,209 @@ import (
 
 // CreateBackup performs a backup operation using the API service with progress feedback
 func CreateBackup(ctx context.Context, k8sClient *pkg.K8sClient, service *v1.Service, options BackupOptions) (*BackupInfo, error) {
-	// Skip verbose service details
-
-	// Discover the API port for the service
-	apiPort, err := k8sClient.DiscoverServiceAPIPort(service)
+	engine, err := newServiceEngine(k8sClient, service, options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to discover API port: %w", err)
+		return nil, err
 	}
+	return engine.Create(ctx)
+}
 
-	// Get a random local port for port-forwarding
-	localPort, err := pkg.GetRandomPort()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get random port: %w", err)
+// createAndWait triggers a backup on the connected node and waits until it reaches a terminal state
+func createAndWait(ctx context.Context, client *Client, options BackupOptions) (*BackupInfo, error) {
+	// Test connection first
+	if err := client.TestConnection(); err != nil {
+		return nil, fmt.Errorf("management API connection failed: %w", err)
 	}
 
-	// Set up port forwarding
-	pf := pkg.NewPortForwarder(k8sClient.GetConfig(), k8sClient.GetRESTClient())
-
-	// Create base URL for the backup API
-	baseURL := fmt.Sprintf("http://localhost:%d", localPort)
-	client := NewClient(baseURL, options.Username, options.Password)
-	client.SetTimeout(options.Timeout)
-
-	var finalBackupInfo *BackupInfo
-
-	// Use service port forwarding for backup operations
-	err = pf.PerformWithServicePortForwarding(ctx, k8sClient, service, apiPort, localPort, func(localPort int) error {
-		// Test connection first
-		if err := client.TestConnection(); err != nil {
-			return fmt.Errorf("management API connection failed: %w", err)
-		}
-
-		backupResp, err := client.CreateBackup()
-		if err != nil {
-			return fmt.Errorf("failed to create backup: %w", err)
-		}
-
-		if options.ShowProgress {
-			fmt.Printf("Backup created: %s\n", backupResp.Backup.ID)
-			fmt.Printf("Waiting for completion...")
-		}
-
-		// Poll for completion
-		if err := waitForBackupCompletion(client, backupResp.Backup.ID, options); err != nil {
-			return err
-		}
-
-		// Get final backup info
-		status, err := client.GetBackupStatus(backupResp.Backup.ID)
-		if err != nil {
-			return fmt.Errorf("failed to get final backup status: %w", err)
-		}
+	backupResp, err := client.CreateBackup()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup: %w", err)
+	}
 
-		finalBackupInfo = &BackupInfo{
-			ID:        status.ID,
-			Status:    status.Status,
-			CreatedAt: status.CreatedAt,
-			Size:      status.Size,
-		}
+	if options.ShowProgress {
+		fmt.Printf("Backup created: %s\n", backupResp.Backup.ID)
+	}
+	options.Progress.emit(ProgressEvent{Phase: PhaseCreate, BackupID: backupResp.Backup.ID, Message: string(backupResp.Backup.State), Done: true})
 
-		return nil
-	})
+	if options.Async {
+		return &BackupInfo{
+			ID:        backupResp.Backup.ID,
+			Status:    backupResp.Backup.State,
+			CreatedAt: backupResp.Backup.CreatedAt,
+		}, nil
+	}
 
+	// Poll for completion
+	status, err := waitForBackupCompletion(ctx, client, backupResp.Backup.ID, options)
 	if err != nil {
+		pkg.RecordInterruption(ctx, fmt.Sprintf("wait for backup %[1]s (creation continues on the broker; check with 'kubectl broker backup status --id %[1]s')", backupResp.Backup.ID))
 		return nil, err
 	}
 
-	return finalBackupInfo, nil
+	return &BackupInfo{
+		ID:        status.ID,
+		Status:    status.Status,
+		CreatedAt: status.CreatedAt,
+		Size:      status.Size,
+	}, nil
 }
 
 // ListBackups retrieves and formats all available backups using the API service
 func ListBackups(ctx context.Context, k8sClient *pkg.K8sClient, service *v1.Service, options BackupOptions) ([]BackupInfo, error) {
-	// Discover the API port for the service
-	apiPort, err := k8sClient.DiscoverServiceAPIPort(service)
-	if err != nil {
-		return nil, fmt.Errorf("failed to discover API port: %w", err)
-	}
-
-	// Get a random local port for port-forwarding
-	localPort, err := pkg.GetRandomPort()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get random port: %w", err)
-	}
-
-	// Set up port forwarding
-	pf := pkg.NewPortForwarder(k8sClient.GetConfig(), k8sClient.GetRESTClient())
-
-	// Create base URL for the backup API
-	baseURL := fmt.Sprintf("http://localhost:%d", localPort)
-	client := NewClient(baseURL, options.Username, options.Password)
-
-	var backups []BackupInfo
-
-	// Use service port forwarding for backup operations
-	err = pf.PerformWithServicePortForwarding(ctx, k8sClient, service, apiPort, localPort, func(localPort int) error {
-		// List backups
-		listResp, err := client.ListBackups()
-		if err != nil {
-			return fmt.Errorf("failed to list backups: %w", err)
-		}
-
-		// Sort backups by creation time (newest first)
-		sort.Slice(listResp.Items, func(i, j int) bool {
-			return listResp.Items[i].CreatedAt.After(listResp.Items[j].CreatedAt)
-		})
-
-		backups = listResp.Items
-		return nil
-	})
-
+	engine, err := newServiceEngine(k8sClient, service, options)
 	if err != nil {
 		return nil, err
 	}
-
-	return backups, nil
+	return engine.List(ctx)
 }
 
 // DownloadBackup downloads a backup file to the specified location using the API service
 func DownloadBackup(ctx context.Context, k8sClient *pkg.K8sClient, service *v1.Service, backupID string, options BackupOptions) (string, error) {
 	if options.ShowProgress {
-		fmt.Printf("Downloading backup %s using service %s...\n", backupID, service.Name)
-	}
-
-	// Discover the API port for the service
-	apiPort, err := k8sClient.DiscoverServiceAPIPort(service)
-	if err != nil {
-		return "", fmt.Errorf("failed to discover API port: %w", err)
-	}
-
-	// Get a random local port for port-forwarding
-	localPort, err := pkg.GetRandomPort()
-	if err != nil {
-		return "", fmt.Errorf("failed to get random port: %w", err)
-	}
-
-	// Set up port forwarding
-	pf := pkg.NewPortForwarder(k8sClient.GetConfig(), k8sClient.GetRESTClient())
-
-	// Create base URL for the backup API
-	baseURL := fmt.Sprintf("http://localhost:%d", localPort)
-	client := NewClient(baseURL, options.Username, options.Password)
-
-	var savedPath string
-
-	// Use service port forwarding for backup operations
-	err = pf.PerformWithServicePortForwarding(ctx, k8sClient, service, apiPort, localPort, func(localPort int) error {
-		// Download backup
-		resp, err := client.DownloadBackup(backupID)
-		if err != nil {
-			return fmt.Errorf("failed to download backup: %w", err)
-		}
-		defer resp.Body.Close()
-
-		// Determine filename
-		filename := extractFilenameFromResponse(resp, backupID)
-		if options.OutputFile != "" {
-			filename = options.OutputFile
-		}
-
-		// Ensure output directory exists
-		outputDir := options.OutputDir
-		if outputDir == "" {
-			outputDir = "./backups"
-		}
-
-		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			return fmt.Errorf("failed to create output directory: %w", err)
-		}
-
-		savedPath = filepath.Join(outputDir, filename)
-
-		// Create output file
-		file, err := os.Create(savedPath)
-		if err != nil {
-			return fmt.Errorf("failed to create output file: %w", err)
-		}
-		defer file.Close()
-
-		// Stream response to file with progress indication
-		if options.ShowProgress {
-			contentLength := resp.ContentLength
-			if contentLength > 0 {
-				return copyWithProgress(file, resp.Body, contentLength, filename)
-			}
+		route := "service " + service.Name
+		if options.Pod != nil {
+			route = "pod " + options.Pod.Name
 		}
+		fmt.Printf("Downloading backup %s using %s...\n", backupID, route)
+	}
 
-		_, err = io.Copy(file, resp.Body)
-		if err != nil {
-			return fmt.Errorf("failed to save backup file: %w", err)
-		}
-
-		return nil
-	})
-
+	engine, err := newServiceEngine(k8sClient, service, options)
 	if err != nil {
 		return "", err
 	}
-
-	return savedPath, nil
+	return engine.Download(ctx, backupID)
 }
 
 // GetBackupStatus retrieves the current status of a backup operation using the API service
 func GetBackupStatus(ctx context.Context, k8sClient *pkg.K8sClient, service *v1.Service, backupID string, options BackupOptions) (*BackupStatusResponse, error) {
-	// Handle "latest" backup ID
-	if backupID == "latest" {
-		backups, err := ListBackups(ctx, k8sClient, service, options)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list backups to find latest: %w", err)
-		}
-		if len(backups) == 0 {
-			return nil, fmt.Errorf("no backups found")
-		}
-		backupID = backups[0].ID // Already sorted newest first
-	}
-
-	// Discover the API port for the service
-	apiPort, err := k8sClient.DiscoverServiceAPIPort(service)
+	engine, err := newServiceEngine(k8sClient, service, options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to discover API port: %w", err)
+		return nil, err
 	}
+	return engine.Status(ctx, backupID)
+}
 
-	// Get a random local port for port-forwarding
-	localPort, err := pkg.GetRandomPort()
+// WaitForBackup blocks until the backup reaches a terminal state, rendering a progress bar
+// when options.ShowProgress is set. backupID may be "latest".
+func WaitForBackup(ctx context.Context, k8sClient *pkg.K8sClient, service *v1.Service, backupID string, options BackupOptions) (*BackupStatusResponse, error) {
+	engine, err := newServiceEngine(k8sClient, service, options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get random port: %w", err)
+		return nil, err
 	}
+	return engine.Wait(ctx, backupID)
+}
 
-	// Set up port forwarding
-	pf := pkg.NewPortForwarder(k8sClient.GetConfig(), k8sClient.GetRESTClient())
-
-	// Create base URL for the backup API
-	baseURL := fmt.Sprintf("http://localhost:%d", localPort)
-	client := NewClient(baseURL, options.Username, options.Password)
-
-	var status *BackupStatusResponse
-
-	// Use service port forwarding for backup operations
-	err = pf.PerformWithServicePortForwarding(ctx, k8sClient, service, apiPort, localPort, func(localPort int) error {
-		// Get backup status
-		statusResp, err := client.GetBackupStatus(backupID)
-		if err != nil {
-			return fmt.Errorf("failed to get backup status: %w", err)
-		}
-
-		status = statusResp
-		return nil
-	})
-
+// RestoreBackup performs a restore operation using the API service with progress feedback
+func RestoreBackup(ctx context.Context, k8sClient *pkg.K8sClient, service *v1.Service, backupID string, options BackupOptions) (*RestoreResult, error) {
+	engine, err := newServiceEngine(k8sClient, service, options)
 	if err != nil {
 		return nil, err
 	}
 
-	return status, nil
-}
-
-// RestoreBackup performs a restore operation using the API service with progress feedback
-func RestoreBackup(ctx context.Context, k8sClient *pkg.K8sClient, service *v1.Service, backupID string, options BackupOptions) error {
-	// Handle "latest" backup ID
 	if backupID == "latest" {
-		backups, err := ListBackups(ctx, k8sClient, service, options)
-		if err != nil {
-			return fmt.Errorf("failed to list backups to find latest: %w", err)
+		if backupID, err = engine.resolveID(ctx, backupID); err != nil {
+			return nil, err
 		}
-		if len(backups) == 0 {
-			return fmt.Errorf("no backups found")
-		}
-		backupID = backups[0].ID // Already sorted newest first
-		fmt.Printf("Using latest backup: %s\n", backupID)
+		slog.Info("Using latest backup", "id", backupID)
 	}
 
 	fmt.Printf("Restoring from backup %s for service %s\n", backupID, service.Name)
+	return engine.restore(ctx, backupID)
+}
 
-	// Discover the API port for the service
-	apiPort, err := k8sClient.DiscoverServiceAPIPort(service)
-	if err != nil {
-		return fmt.Errorf("failed to discover API port: %w", err)
-	}
-
-	// Get a random local port for port-forwarding
-	localPort, err := pkg.GetRandomPort()
+// newManagementClient creates a management API client for a port-forwarded local port
+func newManagementClient(ctx context.Context, localPort int, options BackupOptions) (*Client, error) {
+	client, err := NewClientWithConfig(options.TLS.BaseURL(localPort), options.Username, options.Password, ClientConfig{
+		TLS:   options.TLS,
+		Retry: options.Retry,
+		Auth:  options.Auth,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get random port: %w", err)
+		return nil, err
 	}
+	return client.WithContext(ctx), nil
+}
 
-	// Set up port forwarding
-	pf := pkg.NewPortForwarder(k8sClient.GetConfig(), k8sClient.GetRESTClient())
-
-	// Create base URL for the backup API
-	baseURL := fmt.Sprintf("http://localhost:%d", localPort)
-	client := NewClient(baseURL, options.Username, options.Password)
-	client.SetTimeout(options.Timeout)
-
-	// Use service port forwarding for restore operations
-	err = pf.PerformWithServicePortForwarding(ctx, k8sClient, service, apiPort, localPort, func(localPort int) error {
-		// Test connection first
-		if err := client.TestConnection(); err != nil {
-			return fmt.Errorf("management API connection failed: %w", err)
-		}
-
-		// Initiate restore
-		restoreResp, err := client.RestoreBackup(backupID)
-		if err != nil {
-			return fmt.Errorf("failed to initiate restore: %w", err)
-		}
-
-		if options.ShowProgress {
-			fmt.Printf("Restore operation initiated: %s\n", restoreResp.ID)
-			fmt.Printf("Waiting for completion...")
-		}
-
-		// Poll for completion
-		if err := waitForRestoreCompletion(client, backupID, options); err != nil {
-			return err
-		}
-
-		// Get final restore status
-		status, err := client.GetBackupStatus(backupID)
+// waitForBackupCompletion polls the backup status until it reaches a terminal state and
+// returns the final status. It stops when ctx ends or after options.OperationDeadline, while
+// each status request is only bounded by the client's request timeout.
+func waitForBackupCompletion(ctx context.Context, client *Client, backupID string, options BackupOptions) (status *BackupStatusResponse, err error) {
+	progress := options.progressSink("Backup")
+	defer func() {
 		if err != nil {
-			return fmt.Errorf("failed to get final restore status: %w", err)
+			progress.emit(ProgressEvent{Phase: PhaseBackup, BackupID: backupID, Done: true, Error: err.Error()})
 		}
+	}()
 
-		// Display results
-		statusColor := getStatusColor(status.Status)
-		fmt.Printf("\nRestore ID: %s\n", restoreResp.ID)
-		fmt.Printf("Backup ID: %s\n", backupID)
-		fmt.Printf("Status: %s\n", statusColor.Sprint(string(status.Status)))
-
-		if status.Message != "" {
-			fmt.Printf("Message: %s\n", status.Message)
-		}
-
-		return nil
-	})
-
-	if err != nil {
-		return err
+	if options.OperationDeadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, options.OperationDeadline,
+			fmt.Errorf("backup %s did not finish within %s; it continues on the broker", backupID, options.OperationDeadline))
+		defer cancel()
 	}
+	defer client.WithContext(client.ctx)
+	client = client.WithContext(ctx)
 
-	return nil
-}
-
-// waitForBackupCompletion polls the backup status until completion
-func waitForBackupCompletion(client *Client, backupID string, options BackupOptions) error {
 	for {
-		status, err := client.GetBackupStatus(backupID)
+		if ctx.Err() != nil {
+			return nil, context.Cause(ctx)
+		}
+		status, err = client.GetBackupStatus(backupID)
 		if err != nil {
-			return fmt.Errorf("failed to check backup status: %w", err)
+			if ctx.Err() != nil {
+				return nil, context.Cause(ctx)
+			}
+			return nil, fmt.Errorf("failed to check backup status: %w", err)
 		}
 
-		if options.ShowProgress {
-			if status.Progress > 0 {
-				fmt.Printf(" %d%%", status.Progress)
-			} else {
-				fmt.Printf(".")
-			}
+		percent := status.Progress
+		if status.Status.IsSuccess() {
+			percent = 100
 		}
+		progress.emit(ProgressEvent{
+			Phase:    PhaseBackup,
+			BackupID: backupID,
+			Percent:  percent,
+			Bytes:    status.Size,
+			Message:  string(status.Status),
+			Done:     status.Status.IsSuccess(),
+		})
 
 		if status.Status.IsTerminal() {
-			if status.Status.IsSuccess() {
-				if options.ShowProgress {
-					fmt.Printf(" done\n\n")
-				}
-				return nil
-			} else {
-				return fmt.Errorf("backup failed with status: %s", status.Status)
+			if !status.Status.IsSuccess() {
+				return status, fmt.Errorf("backup failed with status: %s", status.Status)
 			}
+			return status, nil
 		}
 
-		time.Sleep(options.PollInterval)
+		if err = sleepContext(ctx, options.PollInterval); err != nil {
+			return nil, context.Cause(ctx)
+		}
 	}
 }
 
 // waitForRestoreCompletion polls the backup status until restore completion
-func waitForRestoreCompletion(client *Client, backupID string, options BackupOptions) error {
+func waitForRestoreCompletion(client *Client, backupID string, options BackupOptions) (err error) {
+	defer func() {
+		if err != nil {
+			options.Progress.emit(ProgressEvent{Phase: PhaseRestore, BackupID: backupID, Done: true, Error: err.Error()})
+		}
+	}()
+
 	for {
 		status, err := client.GetBackupStatus(backupID)
 		if err != nil {
 			return fmt.Errorf("failed to check restore status: %w", err)
 		}
 
-		if options.ShowProgress {
+		done := status.Status.IsSuccess() || status.Status == StatusRestoreCompleted
+		if options.Progress != nil {
+			percent := status.Progress
+			if done {
+				percent = 100
+			}
+			options.Progress.emit(ProgressEvent{Phase: PhaseRestore, BackupID: backupID, Percent: percent, Message: string(status.Status), Done: done})
+		} else if options.ShowProgress {
 			if status.Progress > 0 {
 				fmt.Printf(" %d%%", status.Progress)
 			} else {
@@ -409,7 +231,7 @@ func waitForRestoreCompletion(client *Client, backupID string, options BackupOpt
 		// Check if restore operation is complete
 		if status.Status.IsTerminal() {
 			if status.Status.IsSuccess() {
-				if options.ShowProgress {
+				if options.ShowProgress && options.Progress == nil {
 					fmt.Printf(" done")
 				}
 				return nil
@@ -420,7 +242,7 @@ func waitForRestoreCompletion(client *Client, backupID string, options BackupOpt
 
 		// Check specifically for restore statuses
 		if status.Status == StatusRestoreCompleted {
-			if options.ShowProgress {
+			if options.ShowProgress && options.Progress == nil {
 				fmt.Printf(" done")
 			}
 			return nil
@@ -430,10 +252,49 @@ func waitForRestoreCompletion(client *Client, backupID string, options BackupOpt
 			return fmt.Errorf("restore failed")
 		}
 
-		time.Sleep(options.PollInterval)
+		if err := sleepContext(client.ctx, options.PollInterval); err != nil {
+			return err
+		}
 	}
 }
 
+// SaveStream writes a backup stream to outputDir/filename, showing progress when the size is known.
+// It returns the path of the written file; a partially written file is removed on failure.
+func SaveStream(ctx context.Context, src io.Reader, contentLength int64, outputDir, filename string, showProgress bool, transfer TransferOptions) (string, error) {
+	if outputDir == "" {
+		outputDir = "./backups"
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	savedPath := filepath.Join(outputDir, filename)
+
+	file, err := os.Create(savedPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	// Stream response to file with progress indication
+	err = copyWithProgress(file, src, contentLength, filename, showProgress, transfer)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = closeErr
+	}
+	if err != nil {
+		// A truncated archive would look like a valid download to later restores
+		if removeErr := os.Remove(savedPath); removeErr != nil {
+			slog.Warn("Failed to remove partial download", "path", savedPath, "error", removeErr)
+			pkg.RecordInterruption(ctx, fmt.Sprintf("download to %s (partial file left behind)", savedPath))
+		} else {
+			pkg.RecordInterruption(ctx, fmt.Sprintf("download to %s (partial file removed)", savedPath))
+		}
+		return "", fmt.Errorf("failed to save backup file: %w", err)
+	}
+
+	return savedPath, nil
+}
+
 // extractFilenameFromResponse extracts filename from Content-Disposition header or generates one
 func extractFilenameFromResponse(resp *http.Response, backupID string) string {
 	contentDisp := resp.Header.Get("Content-Disposition")
@@ -456,60 +317,6 @@ func extractFilenameFromResponse(resp *http.Response, backupID string) string {
 	return fmt.Sprintf("backup-%s-%s.tar.gz", backupID[:8], timestamp)
 }
 
-// copyWithProgress copies data with progress indication
-func copyWithProgress(dst io.Writer, src io.Reader, contentLength int64, filename string) error {
-	buf := make([]byte, 32*1024)
-	var written int64
-
-	for {
-		nr, er := src.Read(buf)
-		if nr > 0 {
-			nw, ew := dst.Write(buf[0:nr])
-			if nw > 0 {
-				written += int64(nw)
-			}
-			if ew != nil {
-				return ew
-			}
-			if nr != nw {
-				return io.ErrShortWrite
-			}
-
-			// Show progress
-			if contentLength > 0 {
-				percent := float64(written) / float64(contentLength) * 100
-				fmt.Printf("\rDownloading %s: %.1f%% (%s/%s)",
-					filename,
-					percent,
-					formatBytes(written),
-					formatBytes(contentLength))
-			}
-		}
-		if er != nil {
-			if er != io.EOF {
-				return er
-			}
-			break
-		}
-	}
-	fmt.Println() // New line after progress
-	return nil
-}
-
-// getStatusColor returns a color function for the given backup status
-func getStatusColor(status BackupStatus) *color.Color {
-	switch status {
-	case StatusCompleted, StatusRestoreCompleted:
-		return color.New(color.FgGreen, color.Bold)
-	case StatusFailed, StatusRestoreFailed:
-		return color.New(color.FgRed, color.Bold)
-	case StatusInProgress, StatusRestoreInProgress:
-		return color.New(color.FgYellow, color.Bold)
-	default:
-		return color.New(color.FgWhite)
-	}
-}
-
 // formatBytes converts bytes to human-readable format
 func formatBytes(bytes int64) string {
 	if bytes == 0 {
@@ -628,61 +435,24 @@ func directoryExistsOnPod(ctx context.Context, k8sClient *pkg.K8sClient, namespa
 	return err == nil, nil
 }
 
-// MoveBackupDirectoryWithinPod moves a backup directory to another location within the same pod
-func MoveBackupDirectoryWithinPod(ctx context.Context, k8sClient *pkg.K8sClient, namespace, podName, backupID, backupFolder, destination string) error {
-	backupDir := filepath.Join(backupFolder, backupID)
-	destinationDir := filepath.Join(destination, backupID)
-
-	fmt.Printf("Moving backup directory from %s to %s on pod %s\n", backupDir, destinationDir, podName)
-
-	// Use kubectl exec to move the directory within the pod
-	cmd := []string{"mv", backupDir, destinationDir}
-	output, err := k8sClient.ExecCommand(ctx, namespace, podName, cmd)
-	if err != nil {
-		return fmt.Errorf("failed to move backup directory within pod: %w", err)
-	}
-
-	// Verify the move was successful by checking if destination exists and source doesn't
-	cmd = []string{"test", "-d", destinationDir}
-	_, err = k8sClient.ExecCommand(ctx, namespace, podName, cmd)
-	if err != nil {
-		return fmt.Errorf("failed to verify moved directory: destination not found")
-	}
-
-	// Verify source directory no longer exists
-	cmd = []string{"test", "-d", backupDir}
-	_, err = k8sClient.ExecCommand(ctx, namespace, podName, cmd)
-	if err == nil {
-		return fmt.Errorf("move failed: source directory still exists at %s", backupDir)
-	}
-
-	// Get directory size for confirmation
-	cmd = []string{"du", "-sh", destinationDir}
-	sizeOutput, err := k8sClient.ExecCommand(ctx, namespace, podName, cmd)
-	if err == nil {
-		sizeOutput = strings.TrimSpace(sizeOutput)
-		if parts := strings.Fields(sizeOutput); len(parts) > 0 {
-			fmt.Printf("Successfully moved backup directory to %s (size: %s)\n", destinationDir, parts[0])
-		}
-	} else {
-		fmt.Printf("Successfully moved backup directory to %s\n", destinationDir)
-	}
-
-	if output != "" {
-		fmt.Printf("Move output: %s\n", output)
-	}
-
-	return nil
-}
-
 // MoveBackupToDestination orchestrates the complete backup move process within the pod
-func MoveBackupToDestination(ctx context.Context, k8sClient *pkg.K8sClient, namespace, statefulSetName, backupID, destination string) error {
+func MoveBackupToDestination(ctx context.Context, k8sClient *pkg.K8sClient, namespace, statefulSetName, backupID, destination string, options MoveOptions) error {
 	// Find which pod has the backup
 	podName, err := DetectBackupPod(ctx, k8sClient, namespace, statefulSetName, backupID)
 	if err != nil {
 		return fmt.Errorf("backup pod detection failed: %w", err)
 	}
 
+	return MoveBackupToDestinationOnPod(ctx, k8sClient, namespace, podName, backupID, destination, options)
+}
+
+// MoveBackupToDestinationOnPod moves (or with options.Copy copies) a backup directory on a
+// known pod, e.g. one taken from a manifest
+func MoveBackupToDestinationOnPod(ctx context.Context, k8sClient *pkg.K8sClient, namespace, podName, backupID, destination string, options MoveOptions) error {
+	if err := pkg.GuardMutation(ctx, options.verb(), "backup "+backupID); err != nil {
+		return err
+	}
+
 	// Validate destination on the pod for move operation
 	if err := ValidateDestinationForMoveOnPod(ctx, k8sClient, namespace, podName, destination, backupID); err != nil {
 		return fmt.Errorf("destination validation failed: %w", err)
@@ -694,9 +464,14 @@ func MoveBackupToDestination(ctx context.Context, k8sClient *pkg.K8sClient, name
 		return fmt.Errorf("failed to get backup folder: %w", err)
 	}
 
-	// Move the backup directory within the pod
-	if err := MoveBackupDirectoryWithinPod(ctx, k8sClient, namespace, podName, backupID, backupFolder, destination); err != nil {
-		return fmt.Errorf("directory move failed: %w", err)
+	// Refuse destinations on the backup volume or without room for the backup
+	size, err := checkDestinationVolume(ctx, k8sClient, namespace, podName, filepath.Join(backupFolder, backupID), destination)
+	if err != nil {
+		return fmt.Errorf("destination validation failed: %w", err)
+	}
+
+	if err := MoveBackupDirectoryWithinPod(ctx, k8sClient, namespace, podName, backupID, backupFolder, destination, size, options); err != nil {
+		return fmt.Errorf("directory %s failed: %w", options.verb(), err)
 	}
 
 	return nil
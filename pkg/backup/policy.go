@@ -0,0 +1,313 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+)
+
+// PolicyKind selects the manifests generated by ExportPolicy
+type PolicyKind string
+
+const (
+	// PolicyVelero generates a Velero Schedule for the broker pods and their volumes
+	PolicyVelero PolicyKind = "velero"
+	// PolicyVolumeSnapshot generates a CronJob creating CSI VolumeSnapshots of the broker volumes
+	PolicyVolumeSnapshot PolicyKind = "volumesnapshot"
+)
+
+// Label and annotation keys set on exported manifests
+const (
+	managedByLabel          = "app.kubernetes.io/managed-by"
+	instanceLabel           = "app.kubernetes.io/instance"
+	claimLabel              = "kubectl-broker/claim"
+	statefulSetAnnotation   = "kubectl-broker/statefulset"
+	claimsAnnotation        = "kubectl-broker/claims"
+	backupFolderAnnotation  = "kubectl-broker/backup-folder"
+	backupVolumeAnnotation  = "kubectl-broker/backup-volume"
+	defaultSnapshotJobImage = "bitnami/kubectl:latest"
+)
+
+// PolicyTarget is what the plugin knows about the storage of one broker installation
+type PolicyTarget struct {
+	Namespace       string
+	StatefulSet     string
+	Selector        map[string]string // pod selector of the StatefulSet
+	BrokerContainer string
+	Claims          []string // PVCs of all replicas, from the volume claim templates
+	BackupFolder    string
+	BackupVolume    string // claim template holding the backup folder; "" when it is not on a PVC
+}
+
+// PolicyOptions configures the exported backup policy
+type PolicyOptions struct {
+	Kind            PolicyKind
+	Schedule        string        // cron expression
+	Retention       time.Duration // Velero backup TTL
+	Keep            int           // VolumeSnapshots kept per claim by the CronJob
+	VeleroNamespace string
+	StorageLocation string // Velero BackupStorageLocation; "" uses Velero's default
+	SnapshotClass   string // VolumeSnapshotClass; "" uses the cluster default
+	Image           string // kubectl image of the snapshot CronJob
+}
+
+// DiscoverPolicyTarget reads the StatefulSet's selector, volume claims and the volume holding
+// the HiveMQ backup folder
+func DiscoverPolicyTarget(ctx context.Context, k8sClient *pkg.K8sClient, namespace, statefulSetName string) (*PolicyTarget, error) {
+	sts, err := k8sClient.GetStatefulSet(ctx, namespace, statefulSetName)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &v1.Pod{ObjectMeta: sts.Spec.Template.ObjectMeta, Spec: sts.Spec.Template.Spec}
+	target := &PolicyTarget{
+		Namespace:       namespace,
+		StatefulSet:     statefulSetName,
+		BrokerContainer: pkg.BrokerContainerName(template),
+		Claims:          statefulSetClaims(sts),
+		BackupFolder:    "/opt/hivemq/backup",
+	}
+	if sts.Spec.Selector != nil {
+		target.Selector = sts.Spec.Selector.MatchLabels
+	}
+
+	// The folder is configurable per pod, so ask a running replica
+	if pods, err := k8sClient.GetStatefulSetPods(ctx, namespace, statefulSetName); err == nil {
+		for _, pod := range pods {
+			if pod.Status.Phase == v1.PodRunning {
+				if folder, err := GetBackupFolder(ctx, k8sClient, namespace, pod.Name); err == nil {
+					target.BackupFolder = folder
+				}
+				break
+			}
+		}
+	}
+
+	target.BackupVolume = backupFolderClaim(sts, target.BrokerContainer, target.BackupFolder)
+	if target.BackupVolume == "" {
+		slog.Warn("Backup folder is not on a persistent volume; volume snapshots will not contain HiveMQ backups",
+			"statefulset", statefulSetName, "backupFolder", target.BackupFolder)
+	}
+	return target, nil
+}
+
+// statefulSetClaims returns the PVC names the StatefulSet controller creates for its replicas
+func statefulSetClaims(sts *appsv1.StatefulSet) []string {
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+	var claims []string
+	for _, template := range sts.Spec.VolumeClaimTemplates {
+		for ordinal := range replicas {
+			claims = append(claims, fmt.Sprintf("%s-%s-%d", template.Name, sts.Name, ordinal))
+		}
+	}
+	return claims
+}
+
+// backupFolderClaim returns the claim template mounted at the deepest mount point containing
+// the backup folder, or "" when that mount is not a claim template
+func backupFolderClaim(sts *appsv1.StatefulSet, container, folder string) string {
+	templates := make(map[string]bool, len(sts.Spec.VolumeClaimTemplates))
+	for _, template := range sts.Spec.VolumeClaimTemplates {
+		templates[template.Name] = true
+	}
+
+	var volume, mountPath string
+	for _, c := range sts.Spec.Template.Spec.Containers {
+		if c.Name != container {
+			continue
+		}
+		for _, mount := range c.VolumeMounts {
+			mounted := path.Clean(mount.MountPath)
+			if (folder == mounted || strings.HasPrefix(folder, strings.TrimSuffix(mounted, "/")+"/")) && len(mounted) > len(mountPath) {
+				volume, mountPath = mount.Name, mounted
+			}
+		}
+	}
+	if templates[volume] {
+		return volume
+	}
+	return ""
+}
+
+// ExportPolicy returns the manifests implementing the backup policy for target, ready to be
+// encoded as YAML or JSON
+func ExportPolicy(target PolicyTarget, options PolicyOptions) ([]map[string]any, error) {
+	if len(strings.Fields(options.Schedule)) != 5 && !strings.HasPrefix(options.Schedule, "@") {
+		return nil, fmt.Errorf("invalid schedule %q: expected a cron expression with five fields", options.Schedule)
+	}
+
+	switch options.Kind {
+	case PolicyVelero:
+		if options.Retention <= 0 {
+			return nil, fmt.Errorf("retention must be positive, got %s", options.Retention)
+		}
+		return []map[string]any{veleroSchedule(target, options)}, nil
+	case PolicyVolumeSnapshot:
+		if len(target.Claims) == 0 {
+			return nil, fmt.Errorf("StatefulSet %s has no volume claim templates to snapshot", target.StatefulSet)
+		}
+		if options.Keep < 1 {
+			return nil, fmt.Errorf("keep must be at least 1, got %d", options.Keep)
+		}
+		return snapshotCronJob(target, options), nil
+	default:
+		return nil, fmt.Errorf("unknown policy kind %q", options.Kind)
+	}
+}
+
+// policyMetadata builds metadata recording the plugin's view of the installation
+func policyMetadata(name, namespace string, target PolicyTarget) map[string]any {
+	annotations := map[string]any{
+		statefulSetAnnotation:  target.Namespace + "/" + target.StatefulSet,
+		claimsAnnotation:       strings.Join(target.Claims, ","),
+		backupFolderAnnotation: target.BackupFolder,
+	}
+	if target.BackupVolume != "" {
+		annotations[backupVolumeAnnotation] = target.BackupVolume
+	}
+	return map[string]any{
+		"name":      name,
+		"namespace": namespace,
+		"labels": map[string]any{
+			managedByLabel: "kubectl-broker",
+			instanceLabel:  target.StatefulSet,
+		},
+		"annotations": annotations,
+	}
+}
+
+func veleroSchedule(target PolicyTarget, options PolicyOptions) map[string]any {
+	selector := map[string]any{}
+	for key, value := range target.Selector {
+		selector[key] = value
+	}
+
+	template := map[string]any{
+		"includedNamespaces": []any{target.Namespace},
+		"labelSelector":      map[string]any{"matchLabels": selector},
+		"snapshotVolumes":    true,
+		"ttl":                options.Retention.String(),
+		"hooks": map[string]any{
+			"resources": []any{map[string]any{
+				"name":               "flush-" + target.StatefulSet,
+				"includedNamespaces": []any{target.Namespace},
+				"labelSelector":      map[string]any{"matchLabels": selector},
+				// Flush written backup files to the volume before it is snapshotted
+				"pre": []any{map[string]any{"exec": map[string]any{
+					"container": target.BrokerContainer,
+					"command":   []any{"/bin/sh", "-c", "sync"},
+					"onError":   "Continue",
+					"timeout":   "30s",
+				}}},
+			}},
+		},
+	}
+	if options.StorageLocation != "" {
+		template["storageLocation"] = options.StorageLocation
+	}
+
+	return map[string]any{
+		"apiVersion": "velero.io/v1",
+		"kind":       "Schedule",
+		"metadata":   policyMetadata(target.Namespace+"-"+target.StatefulSet, options.VeleroNamespace, target),
+		"spec": map[string]any{
+			"schedule": options.Schedule,
+			"template": template,
+		},
+	}
+}
+
+// snapshotScript creates one VolumeSnapshot per claim and prunes all but the newest $KEEP
+const snapshotScript = `set -eu
+stamp=$(date -u +%Y%m%d%H%M%S)
+for claim in $CLAIMS; do
+  {
+    echo "apiVersion: snapshot.storage.k8s.io/v1"
+    echo "kind: VolumeSnapshot"
+    echo "metadata:"
+    echo "  name: ${claim}-${stamp}"
+    echo "  labels:"
+    echo "    ` + managedByLabel + `: kubectl-broker"
+    echo "    ` + instanceLabel + `: ${STATEFULSET}"
+    echo "    ` + claimLabel + `: ${claim}"
+    echo "spec:"
+    if [ -n "${SNAPSHOT_CLASS}" ]; then echo "  volumeSnapshotClassName: ${SNAPSHOT_CLASS}"; fi
+    echo "  source:"
+    echo "    persistentVolumeClaimName: ${claim}"
+  } | kubectl create -f -
+  kubectl get volumesnapshots -l "` + claimLabel + `=${claim}" --sort-by=.metadata.creationTimestamp -o name \
+    | head -n "-${KEEP}" | xargs -r kubectl delete
+done`
+
+func snapshotCronJob(target PolicyTarget, options PolicyOptions) []map[string]any {
+	name := target.StatefulSet + "-volume-snapshots"
+	image := options.Image
+	if image == "" {
+		image = defaultSnapshotJobImage
+	}
+	metadata := func() map[string]any { return policyMetadata(name, target.Namespace, target) }
+
+	serviceAccount := map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ServiceAccount",
+		"metadata":   metadata(),
+	}
+	role := map[string]any{
+		"apiVersion": "rbac.authorization.k8s.io/v1",
+		"kind":       "Role",
+		"metadata":   metadata(),
+		"rules": []any{map[string]any{
+			"apiGroups": []any{"snapshot.storage.k8s.io"},
+			"resources": []any{"volumesnapshots"},
+			"verbs":     []any{"create", "get", "list", "delete"},
+		}},
+	}
+	binding := map[string]any{
+		"apiVersion": "rbac.authorization.k8s.io/v1",
+		"kind":       "RoleBinding",
+		"metadata":   metadata(),
+		"roleRef":    map[string]any{"apiGroup": "rbac.authorization.k8s.io", "kind": "Role", "name": name},
+		"subjects":   []any{map[string]any{"kind": "ServiceAccount", "name": name, "namespace": target.Namespace}},
+	}
+
+	env := []any{
+		map[string]any{"name": "CLAIMS", "value": strings.Join(target.Claims, " ")},
+		map[string]any{"name": "STATEFULSET", "value": target.StatefulSet},
+		map[string]any{"name": "SNAPSHOT_CLASS", "value": options.SnapshotClass},
+		map[string]any{"name": "KEEP", "value": fmt.Sprint(options.Keep)},
+	}
+	cronJob := map[string]any{
+		"apiVersion": "batch/v1",
+		"kind":       "CronJob",
+		"metadata":   metadata(),
+		"spec": map[string]any{
+			"schedule":          options.Schedule,
+			"concurrencyPolicy": "Forbid",
+			"jobTemplate": map[string]any{"spec": map[string]any{
+				"backoffLimit": 2,
+				"template": map[string]any{"spec": map[string]any{
+					"serviceAccountName": name,
+					"restartPolicy":      "OnFailure",
+					"containers": []any{map[string]any{
+						"name":    "snapshot",
+						"image":   image,
+						"command": []any{"/bin/sh", "-c", snapshotScript},
+						"env":     env,
+					}},
+				}},
+			}},
+		},
+	}
+	return []map[string]any{serviceAccount, role, binding, cronJob}
+}
@@ -0,0 +1,93 @@
+package backup
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStatefulSetClaimsAndBackupFolder(t *testing.T) {
+	t.Parallel()
+
+	replicas := int32(2)
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+			VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+				{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "backup"}},
+			},
+			Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: []v1.Container{{
+				Name: "hivemq",
+				VolumeMounts: []v1.VolumeMount{
+					{Name: "data", MountPath: "/opt/hivemq"},
+					{Name: "backup", MountPath: "/opt/hivemq/backup/"},
+					{Name: "config", MountPath: "/opt/hivemq/conf"},
+				},
+			}}}},
+		},
+	}
+
+	want := []string{"data-broker-0", "data-broker-1", "backup-broker-0", "backup-broker-1"}
+	if claims := statefulSetClaims(sts); !slices.Equal(claims, want) {
+		t.Errorf("statefulSetClaims() = %v, want %v", claims, want)
+	}
+	if volume := backupFolderClaim(sts, "hivemq", "/opt/hivemq/backup"); volume != "backup" {
+		t.Errorf("backupFolderClaim() = %q, want the deepest mount", volume)
+	}
+	if volume := backupFolderClaim(sts, "hivemq", "/opt/hivemq/conf/backup"); volume != "" {
+		t.Errorf("backupFolderClaim() = %q for a non-PVC mount, want empty", volume)
+	}
+	if volume := backupFolderClaim(sts, "hivemq", "/opt/hivemq-backup"); volume != "" {
+		t.Errorf("backupFolderClaim() = %q for a sibling path, want empty", volume)
+	}
+}
+
+func TestExportPolicy(t *testing.T) {
+	t.Parallel()
+
+	target := PolicyTarget{
+		Namespace:       "tenant-a",
+		StatefulSet:     "broker",
+		Selector:        map[string]string{"app": "hivemq"},
+		BrokerContainer: "hivemq",
+		Claims:          []string{"data-broker-0"},
+		BackupFolder:    "/opt/hivemq/backup",
+	}
+
+	objects, err := ExportPolicy(target, PolicyOptions{Kind: PolicyVelero, Schedule: "0 2 * * *", Retention: 720 * time.Hour, VeleroNamespace: "velero"})
+	if err != nil {
+		t.Fatalf("ExportPolicy(velero) error = %v", err)
+	}
+	if len(objects) != 1 || objects[0]["kind"] != "Schedule" {
+		t.Fatalf("ExportPolicy(velero) = %v, want one Schedule", objects)
+	}
+	spec := objects[0]["spec"].(map[string]any)
+	if ttl := spec["template"].(map[string]any)["ttl"]; ttl != "720h0m0s" {
+		t.Errorf("ttl = %v, want 720h0m0s", ttl)
+	}
+
+	objects, err = ExportPolicy(target, PolicyOptions{Kind: PolicyVolumeSnapshot, Schedule: "@daily", Keep: 7})
+	if err != nil {
+		t.Fatalf("ExportPolicy(volumesnapshot) error = %v", err)
+	}
+	var kinds []string
+	for _, object := range objects {
+		kinds = append(kinds, object["kind"].(string))
+	}
+	if !slices.Equal(kinds, []string{"ServiceAccount", "Role", "RoleBinding", "CronJob"}) {
+		t.Errorf("kinds = %v", kinds)
+	}
+
+	if _, err := ExportPolicy(target, PolicyOptions{Kind: PolicyVelero, Schedule: "daily", Retention: time.Hour}); err == nil {
+		t.Error("expected an error for an invalid schedule")
+	}
+	if _, err := ExportPolicy(target, PolicyOptions{Kind: PolicyVolumeSnapshot, Schedule: "@daily"}); err == nil {
+		t.Error("expected an error for keep 0")
+	}
+}
@@ -0,0 +1,129 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+)
+
+// RestorePreflight is the outcome of the checks run before a management API restore, without
+// changing anything on the cluster
+type RestorePreflight struct {
+	BackupID  string              `json:"backupId"`
+	Status    BackupStatus        `json:"status,omitempty"`
+	SizeBytes int64               `json:"sizeBytes,omitempty"`
+	Checks    []pkg.CapacityCheck `json:"checks"`
+}
+
+// Failed reports whether a check failed, so the restore would be refused or fail
+func (p *RestorePreflight) Failed() bool {
+	for _, check := range p.Checks {
+		if check.Status == pkg.CapacityCheckFail {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RestorePreflight) add(name, status, details string) {
+	p.Checks = append(p.Checks, pkg.CapacityCheck{Name: name, Status: status, Details: details})
+}
+
+// CheckRestorePreflight verifies that a backup can be restored through the management API: it
+// exists and completed, its HiveMQ version is compatible with the broker, and every running
+// broker pod has room for its size next to the current data. ignoreVersionMismatch downgrades
+// an incompatible version to a warning, as --ignore-version-mismatch does for the restore.
+func CheckRestorePreflight(ctx context.Context, k8sClient *pkg.K8sClient, service *v1.Service, namespace, statefulSetName, backupID string, ignoreVersionMismatch bool, options BackupOptions) (*RestorePreflight, error) {
+	preflight := &RestorePreflight{BackupID: backupID}
+
+	status, err := GetBackupStatus(ctx, k8sClient, service, backupID, options)
+	switch {
+	case IsStatus(err, http.StatusNotFound):
+		preflight.add("Backup", pkg.CapacityCheckFail, fmt.Sprintf("backup %s does not exist", backupID))
+		return preflight, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get the status of backup %s: %w", backupID, err)
+	}
+	preflight.Status = status.Status
+	preflight.SizeBytes = status.Size
+	if status.Status == StatusCompleted {
+		preflight.add("Backup", pkg.CapacityCheckPass, fmt.Sprintf("completed, %s, created %s", formatBytes(status.Size), status.CreatedAt.Format("2006-01-02 15:04:05")))
+	} else {
+		preflight.add("Backup", pkg.CapacityCheckFail, fmt.Sprintf("backup is %s; only completed backups can be restored", status.Status))
+	}
+
+	preflight.addVersionCheck(ctx, k8sClient, namespace, statefulSetName, backupID, ignoreVersionMismatch)
+	preflight.addDiskChecks(ctx, k8sClient, namespace, statefulSetName)
+	return preflight, nil
+}
+
+func (p *RestorePreflight) addVersionCheck(ctx context.Context, k8sClient *pkg.K8sClient, namespace, statefulSetName, backupID string, ignoreVersionMismatch bool) {
+	check, err := CheckRestoreCompatibility(ctx, k8sClient, namespace, statefulSetName, backupID)
+	switch {
+	case err != nil:
+		p.add("Version", pkg.CapacityCheckWarn, fmt.Sprintf("cannot verify: %v", err))
+	case !check.Known:
+		p.add("Version", pkg.CapacityCheckWarn, "cannot verify: "+check.Reason)
+	case !check.Compatible && ignoreVersionMismatch:
+		p.add("Version", pkg.CapacityCheckWarn, check.Reason+" (allowed by --ignore-version-mismatch)")
+	case !check.Compatible:
+		p.add("Version", pkg.CapacityCheckFail, check.Reason)
+	case check.Reason != "":
+		p.add("Version", pkg.CapacityCheckWarn, check.Reason)
+	default:
+		p.add("Version", pkg.CapacityCheckPass, fmt.Sprintf("backup and broker run HiveMQ %s", check.BrokerVersion))
+	}
+}
+
+// addDiskChecks compares the free space of the volume holding the backup folder on every
+// running broker pod with the backup size, which the restore writes next to the current data
+func (p *RestorePreflight) addDiskChecks(ctx context.Context, k8sClient *pkg.K8sClient, namespace, statefulSetName string) {
+	pods, err := k8sClient.GetStatefulSetPods(ctx, namespace, statefulSetName)
+	if err != nil {
+		p.add("Disk space", pkg.CapacityCheckWarn, fmt.Sprintf("cannot list pods: %v", err))
+		return
+	}
+
+	checked := 0
+	var short []string
+	for _, pod := range pods {
+		if pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		folder, err := GetBackupFolder(ctx, k8sClient, namespace, pod.Name)
+		if err != nil {
+			p.add("Disk space", pkg.CapacityCheckWarn, fmt.Sprintf("%s: cannot locate the backup folder: %v", pod.Name, err))
+			continue
+		}
+		output, err := k8sClient.ExecCommand(ctx, namespace, pod.Name, []string{"df", "-Pk", folder})
+		if err != nil {
+			p.add("Disk space", pkg.CapacityCheckWarn, fmt.Sprintf("%s: cannot determine free space of %s: %v", pod.Name, folder, err))
+			continue
+		}
+		usage, err := parseDf(output)
+		if err != nil || len(usage) != 1 {
+			p.add("Disk space", pkg.CapacityCheckWarn, fmt.Sprintf("%s: unexpected df output for %s", pod.Name, folder))
+			continue
+		}
+		checked++
+		if available := usage[0].AvailableKB * 1024; available < p.SizeBytes {
+			short = append(short, fmt.Sprintf("%s has %s free", pod.Name, formatBytes(available)))
+		}
+	}
+
+	switch {
+	case len(short) > 0:
+		p.add("Disk space", pkg.CapacityCheckFail, fmt.Sprintf("the backup needs %s but %s", formatBytes(p.SizeBytes), strings.Join(short, ", ")))
+	case checked == 0:
+		p.add("Disk space", pkg.CapacityCheckWarn, "no running broker pod to check")
+	case p.SizeBytes == 0:
+		p.add("Disk space", pkg.CapacityCheckWarn, "backup size unknown, free space not compared")
+	default:
+		p.add("Disk space", pkg.CapacityCheckPass, fmt.Sprintf("%d pods have at least %s free", checked, formatBytes(p.SizeBytes)))
+	}
+}
@@ -0,0 +1,123 @@
+package backup
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// progressBarWidth is the number of cells between the brackets
+const progressBarWidth = 30
+
+// ProgressBar renders polled backup progress as a single updating line with percent,
+// transferred size, transfer rate and ETA. On non-terminal writers it prints a plain
+// line whenever the percentage advances by at least 10 points, so CI logs stay readable.
+type ProgressBar struct {
+	out         io.Writer
+	label       string
+	interactive bool
+	start       time.Time
+	startSize   int64
+	startPct    int
+	lastPrinted int
+	rendered    bool
+	now         func() time.Time
+}
+
+// NewProgressBar creates a progress bar writing to out
+func NewProgressBar(out io.Writer, label string) *ProgressBar {
+	return &ProgressBar{
+		out:         out,
+		label:       label,
+		interactive: isTerminalWriter(out),
+		lastPrinted: -1,
+		now:         time.Now,
+	}
+}
+
+// Update renders the latest polled state. percent may be 0 when the API reports no progress.
+func (p *ProgressBar) Update(percent int, size int64) {
+	now := p.now()
+	percent = min(max(percent, 0), 100)
+	if p.start.IsZero() {
+		p.start = now
+		p.startSize = size
+		p.startPct = percent
+	}
+
+	line := p.format(percent, size, now.Sub(p.start))
+	if p.interactive {
+		fmt.Fprintf(p.out, "\r%s", line)
+		p.rendered = true
+		return
+	}
+
+	if p.lastPrinted < 0 || percent >= p.lastPrinted+10 || percent == 100 && p.lastPrinted != 100 {
+		fmt.Fprintln(p.out, line)
+		p.lastPrinted = percent
+	}
+}
+
+// Handle renders a progress event, finishing the line with the last event of a phase
+func (p *ProgressBar) Handle(event ProgressEvent) {
+	if event.Error == "" {
+		p.Update(event.Percent, event.Bytes)
+	}
+	if event.Done || event.Error != "" {
+		p.Finish()
+	}
+}
+
+// Finish terminates the updating line
+func (p *ProgressBar) Finish() {
+	if p.interactive && p.rendered {
+		fmt.Fprintln(p.out)
+	}
+	p.rendered = false
+}
+
+func (p *ProgressBar) format(percent int, size int64, elapsed time.Duration) string {
+	filled := percent * progressBarWidth / 100
+	bar := strings.Repeat("=", filled)
+	if filled < progressBarWidth {
+		bar += ">" + strings.Repeat(" ", progressBarWidth-filled-1)
+	}
+
+	parts := []string{fmt.Sprintf("%s [%s] %3d%%", p.label, bar, percent)}
+	if size > 0 {
+		parts = append(parts, formatBytes(size))
+	}
+	if seconds := elapsed.Seconds(); seconds >= 1 && size > p.startSize {
+		parts = append(parts, fmt.Sprintf("%s/s", formatBytes(int64(float64(size-p.startSize)/seconds))))
+	}
+	parts = append(parts, "ETA "+formatETA(percent, percent-p.startPct, elapsed))
+
+	return strings.Join(parts, "  ")
+}
+
+// formatETA extrapolates the remaining time from the progress observed since the bar started,
+// which also works when waiting on a backup that was already running
+func formatETA(percent, advanced int, elapsed time.Duration) string {
+	if percent >= 100 {
+		return "0s"
+	}
+	if advanced <= 0 || elapsed < time.Second {
+		return "--"
+	}
+	remaining := time.Duration(float64(elapsed) * float64(100-percent) / float64(advanced))
+	return remaining.Round(time.Second).String()
+}
+
+func isTerminalWriter(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,37 @@
+package backup
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressBarReportsRateAndETA(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	bar := NewProgressBar(&out, "Backup")
+
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bar.now = func() time.Time { return clock }
+
+	bar.Update(20, 10*1024*1024)
+	clock = clock.Add(10 * time.Second)
+	bar.Update(25, 15*1024*1024) // below the 10 point step, not printed
+	clock = clock.Add(10 * time.Second)
+	bar.Update(40, 30*1024*1024)
+	bar.Finish()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines on a non-terminal writer, got %q", out.String())
+	}
+	if !strings.Contains(lines[0], "[======>") || !strings.Contains(lines[0], "ETA --") {
+		t.Fatalf("unexpected first line %q", lines[0])
+	}
+	// 20 MiB in 20s and 20 points in 20s leaves 60 points, i.e. one minute
+	if !strings.Contains(lines[1], " 40%") || !strings.Contains(lines[1], "1.0 MB/s") || !strings.Contains(lines[1], "ETA 1m0s") {
+		t.Fatalf("unexpected second line %q", lines[1])
+	}
+}
@@ -0,0 +1,116 @@
+package backup
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"kubectl-broker/pkg"
+)
+
+// DefaultReportMaxAge is the backup age after which an inventory report flags a namespace
+const DefaultReportMaxAge = 24 * time.Hour
+
+// Inventory report states of a namespace
+const (
+	ReportOK       = "OK"    // newest completed backup is within the SLA
+	ReportStale    = "STALE" // newest completed backup is older than the SLA
+	ReportNoBackup = "NONE"  // no completed backup exists
+	ReportError    = "ERROR" // backups could not be listed
+)
+
+// NamespaceReport is the backup inventory of one HiveMQ StatefulSet
+type NamespaceReport struct {
+	NamespaceTarget
+	State         string
+	Backups       int
+	Latest        *BackupInfo   // newest backup in any state
+	LastCompleted *BackupInfo   // newest backup the SLA is measured against
+	Age           time.Duration // age of LastCompleted
+	Error         string
+}
+
+// InventoryReport is the backup inventory across HiveMQ namespaces
+type InventoryReport struct {
+	GeneratedAt time.Time
+	MaxAge      time.Duration
+	Namespaces  []NamespaceReport
+}
+
+// Violations counts the namespaces that do not meet the SLA
+func (r *InventoryReport) Violations() int {
+	violations := 0
+	for _, entry := range r.Namespaces {
+		if entry.State != ReportOK {
+			violations++
+		}
+	}
+	return violations
+}
+
+// CollectInventory lists the backups of every target concurrently and evaluates them against
+// maxAge. Per-target failures are reported with state ERROR; the entries are sorted by namespace.
+func CollectInventory(ctx context.Context, k8sClient *pkg.K8sClient, targets []NamespaceTarget, concurrency int, maxAge time.Duration, options BackupOptions) (*InventoryReport, error) {
+	reports := make([]NamespaceReport, len(targets))
+	collect := func(taskCtx context.Context, i int) error {
+		backups, err := listNamespaceBackups(taskCtx, k8sClient, targets[i], options)
+		if err != nil {
+			reports[i] = NamespaceReport{NamespaceTarget: targets[i], State: ReportError, Error: err.Error()}
+			return err
+		}
+		reports[i] = EvaluateInventory(targets[i], backups, maxAge, time.Now())
+		return nil
+	}
+	rejected := func(i int, err error) {
+		reports[i] = NamespaceReport{NamespaceTarget: targets[i], State: ReportError, Error: err.Error()}
+	}
+
+	timeout := options.RequestTimeout
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	if err := forEachTarget(ctx, k8sClient, len(targets), concurrency, timeout, collect, rejected); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(reports, func(i, j int) bool {
+		if reports[i].Namespace != reports[j].Namespace {
+			return reports[i].Namespace < reports[j].Namespace
+		}
+		return reports[i].StatefulSet < reports[j].StatefulSet
+	})
+	return &InventoryReport{GeneratedAt: time.Now().UTC(), MaxAge: maxAge, Namespaces: reports}, nil
+}
+
+// EvaluateInventory finds the newest backup and the newest completed backup of a target and
+// checks the latter against maxAge
+func EvaluateInventory(target NamespaceTarget, backups []BackupInfo, maxAge time.Duration, now time.Time) NamespaceReport {
+	report := NamespaceReport{NamespaceTarget: target, State: ReportNoBackup, Backups: len(backups)}
+	for i := range backups {
+		info := &backups[i]
+		if report.Latest == nil || info.CreatedAt.After(report.Latest.CreatedAt) {
+			report.Latest = info
+		}
+		if info.Status == StatusCompleted && (report.LastCompleted == nil || info.CreatedAt.After(report.LastCompleted.CreatedAt)) {
+			report.LastCompleted = info
+		}
+	}
+
+	if report.LastCompleted == nil {
+		return report
+	}
+	report.Age = now.Sub(report.LastCompleted.CreatedAt)
+	report.State = ReportOK
+	if report.Age > maxAge {
+		report.State = ReportStale
+	}
+	return report
+}
+
+func listNamespaceBackups(ctx context.Context, k8sClient *pkg.K8sClient, target NamespaceTarget, options BackupOptions) ([]BackupInfo, error) {
+	service, err := k8sClient.GetAPIServiceFromStatefulSet(ctx, target.Namespace, target.StatefulSet)
+	if err != nil {
+		return nil, err
+	}
+	return ListBackups(ctx, k8sClient, service, options)
+}
@@ -0,0 +1,33 @@
+package backup
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvaluateInventoryMeasuresNewestCompletedBackup(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	target := NamespaceTarget{Namespace: "tenant-a", StatefulSet: "broker"}
+	backups := []BackupInfo{
+		{ID: "old", Status: StatusCompleted, CreatedAt: now.Add(-72 * time.Hour)},
+		{ID: "recent", Status: StatusCompleted, CreatedAt: now.Add(-30 * time.Hour)},
+		{ID: "failed", Status: StatusFailed, CreatedAt: now.Add(-time.Hour)},
+	}
+
+	report := EvaluateInventory(target, backups, 24*time.Hour, now)
+	if report.State != ReportStale || report.Backups != 3 {
+		t.Fatalf("expected 3 backups flagged STALE, got %+v", report)
+	}
+	if report.Latest.ID != "failed" || report.LastCompleted.ID != "recent" || report.Age != 30*time.Hour {
+		t.Fatalf("unexpected latest %q, last completed %q, age %s", report.Latest.ID, report.LastCompleted.ID, report.Age)
+	}
+
+	if report := EvaluateInventory(target, backups, 48*time.Hour, now); report.State != ReportOK {
+		t.Fatalf("expected OK within 48h, got %s", report.State)
+	}
+	if report := EvaluateInventory(target, backups[2:], 48*time.Hour, now); report.State != ReportNoBackup || report.LastCompleted != nil {
+		t.Fatalf("expected NONE without completed backups, got %+v", report)
+	}
+}
@@ -0,0 +1,88 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy configures how transient management API failures are retried
+type RetryPolicy struct {
+	MaxRetries int           // additional attempts after the first request (0 disables retries)
+	Backoff    time.Duration // initial delay, doubled after every attempt
+	MaxBackoff time.Duration // upper bound for a single delay
+}
+
+// DefaultRetryPolicy retries a few times to ride out port-forward warm-up
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	Backoff:    500 * time.Millisecond,
+	MaxBackoff: 10 * time.Second,
+}
+
+// delay returns the backoff for the given zero-based attempt with up to 50% jitter
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = DefaultRetryPolicy.Backoff
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultRetryPolicy.MaxBackoff
+	}
+
+	d := backoff
+	for i := 0; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+
+	// Jitter spreads retries from concurrent commands against the same forward
+	jitter := time.Duration(rand.Int63n(int64(d)/2 + 1))
+	return d/2 + jitter
+}
+
+// isRetryableStatus reports whether an HTTP status indicates a transient failure
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryableAttempt reports whether a failed attempt may be repeated. Read-only requests are
+// always safe to repeat; other requests, such as the POSTs that create a backup or start a
+// restore, only when the connection failed before the request was sent, because the broker may
+// already have acted on a request that timed out or got a transient status.
+func isRetryableAttempt(method string, err error) bool {
+	if IsReadOnlyMethod(method) {
+		return true
+	}
+	return err != nil && isDialError(err)
+}
+
+// isDialError reports whether err happened while connecting, before anything was sent
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// sleepContext waits for d or until the context is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,97 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+)
+
+// statisticsPath serves the message statistics of the broker node answering the request
+const statisticsPath = "/api/v1/management/statistics"
+
+// NodeStatistics are the queued message counters of one broker node
+type NodeStatistics struct {
+	QueuedMessages int64 `json:"queuedMessages"`
+	// UnreplicatedQueuedMessages are queued on this node only and lost when it is removed
+	UnreplicatedQueuedMessages int64 `json:"unreplicatedQueuedMessages"`
+}
+
+// GetNodeStatistics retrieves the message statistics of the node the client is connected to
+func (c *Client) GetNodeStatistics() (*NodeStatistics, error) {
+	resp, err := c.makeRequest("GET", statisticsPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp)
+	}
+
+	var statistics NodeStatistics
+	if err := json.NewDecoder(resp.Body).Decode(&statistics); err != nil {
+		return nil, fmt.Errorf("failed to decode statistics response: %w", err)
+	}
+	return &statistics, nil
+}
+
+// GetNodeStatistics reads the message statistics of a broker pod through its own management API
+// port, since every node only reports the messages it holds itself
+func GetNodeStatistics(ctx context.Context, k8sClient *pkg.K8sClient, pod *v1.Pod, options BackupOptions) (*NodeStatistics, error) {
+	options.Pod = pod
+	engine, err := newServiceEngine(k8sClient, nil, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var statistics *NodeStatistics
+	err = engine.withClient(ctx, func(client *Client) error {
+		statistics, err = client.GetNodeStatistics()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return statistics, nil
+}
+
+// CheckQueuedMessages verifies that the pods removed by a scale-down hold no queued messages
+// that are not replicated to another node. Brokers without the statistics endpoint cannot be
+// verified, which is a warning rather than a failure.
+func CheckQueuedMessages(ctx context.Context, k8sClient *pkg.K8sClient, pods []*v1.Pod, options BackupOptions) pkg.CapacityCheck {
+	check := pkg.CapacityCheck{Name: "Queued messages"}
+
+	var holding, unknown []string
+	var unreplicated int64
+	for _, pod := range pods {
+		statistics, err := GetNodeStatistics(ctx, k8sClient, pod, options)
+		switch {
+		case IsStatus(err, http.StatusNotFound):
+			unknown = append(unknown, pod.Name+" (statistics not available on this broker version)")
+		case err != nil:
+			unknown = append(unknown, fmt.Sprintf("%s (%v)", pod.Name, err))
+		case statistics.UnreplicatedQueuedMessages > 0:
+			unreplicated += statistics.UnreplicatedQueuedMessages
+			holding = append(holding, fmt.Sprintf("%s holds %d", pod.Name, statistics.UnreplicatedQueuedMessages))
+		}
+	}
+
+	switch {
+	case len(holding) > 0:
+		check.Status = pkg.CapacityCheckWarn
+		check.Details = fmt.Sprintf("%d unreplicated queued messages would be lost: %s", unreplicated, strings.Join(holding, ", "))
+	case len(unknown) > 0:
+		check.Status = pkg.CapacityCheckWarn
+		check.Details = "cannot verify: " + strings.Join(unknown, ", ")
+	default:
+		check.Status = pkg.CapacityCheckPass
+		check.Details = fmt.Sprintf("the %d removed brokers hold no unreplicated queued messages", len(pods))
+	}
+	return check
+}
@@ -0,0 +1,150 @@
+package backup
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTransferOptions copy in 32 KiB chunks without a rate limit and refresh progress at 10 Hz
+var DefaultTransferOptions = TransferOptions{
+	ChunkSize:        32 * 1024,
+	ProgressInterval: 100 * time.Millisecond,
+}
+
+// TransferOptions tune how backup archives are streamed to disk
+type TransferOptions struct {
+	ChunkSize        int           // read buffer size in bytes (0 uses the default)
+	RateLimit        int64         // maximum bytes per second (0 for unlimited)
+	ProgressInterval time.Duration // minimum time between progress updates (0 uses the default)
+	Progress         ProgressFunc  // receives download events instead of the printed progress (optional)
+}
+
+// withDefaults fills unset fields from DefaultTransferOptions
+func (o TransferOptions) withDefaults() TransferOptions {
+	if o.ChunkSize <= 0 {
+		o.ChunkSize = DefaultTransferOptions.ChunkSize
+	}
+	if o.ProgressInterval <= 0 {
+		o.ProgressInterval = DefaultTransferOptions.ProgressInterval
+	}
+	return o
+}
+
+// ParseByteSize parses sizes like "512", "64KB", "1MiB" or "2G". Suffixes are 1024-based like
+// curl's, whether or not they carry the "i".
+func ParseByteSize(value string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	s = strings.TrimSuffix(s, "B")
+	s = strings.TrimSuffix(s, "I")
+
+	multiplier := int64(1)
+	if n := len(s); n > 0 {
+		switch s[n-1] {
+		case 'K':
+			multiplier = 1 << 10
+		case 'M':
+			multiplier = 1 << 20
+		case 'G':
+			multiplier = 1 << 30
+		}
+		if multiplier > 1 {
+			s = s[:n-1]
+		}
+	}
+
+	number, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid size %q: use a number with an optional K, M or G suffix, e.g. 512KB", value)
+	}
+	return int64(number * float64(multiplier)), nil
+}
+
+// ParseRate parses a transfer rate like "10MB/s" or "500K"; the "/s" suffix is optional
+func ParseRate(value string) (int64, error) {
+	trimmed := strings.TrimSpace(value)
+	trimmed = strings.TrimSuffix(strings.TrimSuffix(trimmed, "/s"), "/S")
+	rate, err := ParseByteSize(trimmed)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate %q: use a size per second, e.g. 10MB/s", value)
+	}
+	return rate, nil
+}
+
+// copyWithProgress copies src to dst in ChunkSize reads, sleeping as needed to stay below
+// RateLimit. At most once per ProgressInterval it emits a download event to options.Progress,
+// or prints progress when contentLength is known.
+func copyWithProgress(dst io.Writer, src io.Reader, contentLength int64, filename string, showProgress bool, options TransferOptions) (err error) {
+	options = options.withDefaults()
+	defer func() {
+		if err != nil {
+			options.Progress.emit(ProgressEvent{Phase: PhaseDownload, Message: filename, Done: true, Error: err.Error()})
+		}
+	}()
+	buf := make([]byte, options.ChunkSize)
+	var written int64
+	start := time.Now()
+	var lastProgress time.Time
+	progress := options.Progress != nil || (showProgress && contentLength > 0)
+
+	printProgress := func() {
+		if options.Progress != nil {
+			event := ProgressEvent{Phase: PhaseDownload, Bytes: written, TotalBytes: max(contentLength, 0), Message: filename}
+			if contentLength > 0 {
+				event.Percent = int(written * 100 / contentLength)
+			}
+			options.Progress.emit(event)
+			return
+		}
+		percent := float64(written) / float64(contentLength) * 100
+		fmt.Printf("\rDownloading %s: %.1f%% (%s/%s)",
+			filename,
+			percent,
+			formatBytes(written),
+			formatBytes(contentLength))
+	}
+
+	for {
+		nr, er := src.Read(buf)
+		if nr > 0 {
+			nw, ew := dst.Write(buf[0:nr])
+			if nw > 0 {
+				written += int64(nw)
+			}
+			if ew != nil {
+				return ew
+			}
+			if nr != nw {
+				return io.ErrShortWrite
+			}
+
+			if options.RateLimit > 0 {
+				// Sleep until the average rate since the start is back under the limit
+				expected := time.Duration(float64(written) / float64(options.RateLimit) * float64(time.Second))
+				if ahead := expected - time.Since(start); ahead > 0 {
+					time.Sleep(ahead)
+				}
+			}
+
+			if progress && time.Since(lastProgress) >= options.ProgressInterval {
+				printProgress()
+				lastProgress = time.Now()
+			}
+		}
+		if er != nil {
+			if er != io.EOF {
+				return er
+			}
+			break
+		}
+	}
+	if options.Progress != nil {
+		options.Progress.emit(ProgressEvent{Phase: PhaseDownload, Percent: 100, Bytes: written, TotalBytes: written, Message: filename, Done: true})
+	} else if progress {
+		printProgress()
+		fmt.Println() // New line after progress
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package backup
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRate(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]int64{
+		"10MB/s":  10 << 20,
+		"512k":    512 << 10,
+		"1.5MiB":  3 << 19,
+		"2G/s":    2 << 30,
+		"4096":    4096,
+		" 64KB ":  64 << 10,
+		"100B/s":  100,
+		"0.5KB/s": 512,
+	}
+	for input, want := range cases {
+		got, err := ParseRate(input)
+		if err != nil || got != want {
+			t.Errorf("ParseRate(%q) = %d, %v; want %d", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"", "fast", "-1MB/s", "10TB/s"} {
+		if _, err := ParseRate(input); err == nil {
+			t.Errorf("ParseRate(%q) should fail", input)
+		}
+	}
+}
+
+func TestCopyWithProgressHonoursRateLimit(t *testing.T) {
+	t.Parallel()
+
+	payload := strings.Repeat("x", 4096)
+	var out bytes.Buffer
+	start := time.Now()
+	err := copyWithProgress(&out, strings.NewReader(payload), 0, "b.tar.gz", false, TransferOptions{ChunkSize: 1024, RateLimit: 16 * 1024})
+	if err != nil {
+		t.Fatalf("copyWithProgress returned error: %v", err)
+	}
+	if out.String() != payload {
+		t.Fatalf("copied %d bytes, want %d", out.Len(), len(payload))
+	}
+	// 4 KiB at 16 KiB/s takes at least 250ms
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Fatalf("copy finished after %v, rate limit not applied", elapsed)
+	}
+}
+
+func TestCopyWithProgressEmitsJSONLines(t *testing.T) {
+	t.Parallel()
+
+	payload := strings.Repeat("x", 4096)
+	var out, events bytes.Buffer
+	options := TransferOptions{ChunkSize: 1024, Progress: NewJSONLinesProgress(&events)}
+	if err := copyWithProgress(&out, strings.NewReader(payload), int64(len(payload)), "b.tar.gz", true, options); err != nil {
+		t.Fatalf("copyWithProgress returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(events.String()), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("expected a progress and a final event, got %q", events.String())
+	}
+	var last ProgressEvent
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &last); err != nil {
+		t.Fatalf("final event is not JSON: %v", err)
+	}
+	if last.Phase != PhaseDownload || !last.Done || last.Percent != 100 || last.Bytes != int64(len(payload)) || last.Time.IsZero() {
+		t.Fatalf("unexpected final event: %+v", last)
+	}
+}
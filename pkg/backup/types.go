@@ -1,6 +1,13 @@
 package backup
 
-import "time"
+import (
+	"net/url"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg/transport"
+)
 
 // BackupStatus represents the status of backup operations from HiveMQ
 type BackupStatus string
@@ -47,7 +54,29 @@ type BackupInfo struct {
 
 // BackupListResponse represents the response when listing backups
 type BackupListResponse struct {
-	Items []BackupInfo `json:"items"`
+	Items []BackupInfo     `json:"items"`
+	Links *PaginationLinks `json:"_links,omitempty"`
+
+	// Truncated is set by ListBackupsUpTo when backups beyond the cap were not read
+	Truncated bool `json:"-"`
+}
+
+// PaginationLinks holds the cursor link of paginated management API collections
+type PaginationLinks struct {
+	Next string `json:"next,omitempty"` // e.g. /api/v1/management/backups?cursor=...
+}
+
+// NextCursor returns the cursor of the next page, or "" on the last page. Links without a
+// cursor query parameter are treated as the last page.
+func (r *BackupListResponse) NextCursor() string {
+	if r.Links == nil || r.Links.Next == "" {
+		return ""
+	}
+	next, err := url.Parse(r.Links.Next)
+	if err != nil {
+		return ""
+	}
+	return next.Query().Get("cursor")
 }
 
 // BackupStatusResponse represents the response when checking backup status
@@ -72,32 +101,71 @@ type RestoreResponse struct {
 	BackupID string       `json:"backupId"`
 }
 
-// ErrorResponse represents error responses from the HiveMQ API
+// RestoreResult is the outcome of a completed restore
+type RestoreResult struct {
+	RestoreID string       `json:"restoreId"`
+	BackupID  string       `json:"backupId"`
+	Status    BackupStatus `json:"status"`
+	Message   string       `json:"message,omitempty"`
+}
+
+// ErrorResponse represents error responses from the HiveMQ API. Current brokers send a list of
+// errors ({"errors":[{"title":...,"detail":...}]}), older ones a single error or message field.
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-	Code    string `json:"code,omitempty"`
+	Errors    []ErrorItem `json:"errors,omitempty"`
+	Title     string      `json:"title,omitempty"`
+	Detail    string      `json:"detail,omitempty"`
+	RequestID string      `json:"requestId,omitempty"`
+	Error     string      `json:"error"`
+	Message   string      `json:"message"`
+	Code      string      `json:"code,omitempty"`
+}
+
+// ErrorItem is one entry of the errors list of an ErrorResponse
+type ErrorItem struct {
+	Title  string `json:"title,omitempty"`
+	Detail string `json:"detail,omitempty"`
 }
 
+// Default bounds of backup operations
+const (
+	DefaultRequestTimeout    = time.Minute
+	DefaultOperationDeadline = time.Hour
+)
+
 // BackupOptions configures how backup operations are performed
 type BackupOptions struct {
-	Username     string        // optional authentication username
-	Password     string        // optional authentication password
-	OutputDir    string        // directory to save backup files
-	OutputFile   string        // specific output filename override
-	Timeout      time.Duration // timeout for backup operations
-	PollInterval time.Duration // interval for status polling
-	ShowProgress bool          // show progress indicators
-	Destination  string        // local destination path for copying backup files from pods
+	Username   string      // optional authentication username
+	Password   string      // optional authentication password
+	Auth       TokenSource // bearer token for the management API, replaces Username/Password (optional)
+	OutputDir  string      // directory to save backup files
+	OutputFile string      // specific output filename override
+	// RequestTimeout bounds each management API request
+	RequestTimeout time.Duration
+	PollInterval   time.Duration // interval for status polling
+	// OperationDeadline bounds waiting for a backup to finish; 0 waits until the context ends
+	OperationDeadline time.Duration
+	ShowProgress      bool                 // show progress indicators
+	Async             bool                 // return as soon as the backup is triggered instead of waiting
+	Destination       string               // local destination path for copying backup files from pods
+	TLS               transport.TLSOptions // TLS settings for the management API
+	Retry             RetryPolicy          // retry behaviour for transient management API failures
+	MaxItems          int                  // stop following list pagination after this many backups (0 for all)
+	Transfer          TransferOptions      // chunk size, rate limit and progress rate of downloads
+	Progress          ProgressFunc         // receives progress events instead of the progress bar (optional)
+	Pod               *v1.Pod              // reach the management API on this pod instead of a ready pod behind the service (optional)
 }
 
 // DefaultBackupOptions provides sensible defaults for backup operations
 var DefaultBackupOptions = BackupOptions{
-	Username:     "",
-	Password:     "",
-	OutputDir:    "./backups",
-	OutputFile:   "",
-	Timeout:      5 * time.Minute,
-	PollInterval: 2 * time.Second,
-	ShowProgress: true,
+	Username:          "",
+	Password:          "",
+	OutputDir:         "./backups",
+	OutputFile:        "",
+	RequestTimeout:    DefaultRequestTimeout,
+	PollInterval:      2 * time.Second,
+	OperationDeadline: DefaultOperationDeadline,
+	ShowProgress:      true,
+	Retry:             DefaultRetryPolicy,
+	Transfer:          DefaultTransferOptions,
 }
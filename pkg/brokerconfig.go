@@ -0,0 +1,241 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// maskedValue replaces environment values that look like credentials
+const maskedValue = "********"
+
+// configPlaceholder matches HiveMQ environment substitutions such as ${ENV:NAME} or ${NAME}
+var configPlaceholder = regexp.MustCompile(`\$\{(?:ENV:)?([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// BrokerConfig is the effective HiveMQ configuration of one broker pod
+type BrokerConfig struct {
+	Pod        string            `json:"pod"`
+	Container  string            `json:"container"`
+	ConfigPath string            `json:"configPath"`
+	ConfigXML  string            `json:"configXml"`
+	Env        map[string]string `json:"env,omitempty"`
+}
+
+// GetBrokerConfig reads config.xml from the broker container and collects the environment
+// variables that influence it: HIVEMQ_* and JAVA_OPTS, plus every variable referenced by a
+// placeholder in config.xml. Values of credential-like variables are masked.
+func (k *K8sClient) GetBrokerConfig(ctx context.Context, pod *v1.Pod, hivemqHome string) (*BrokerConfig, error) {
+	container := BrokerContainerName(pod)
+	configPath := path.Join(hivemqHome, "conf", "config.xml")
+
+	raw, err := k.ExecCommandInContainer(ctx, pod.Namespace, pod.Name, container, []string{"cat", configPath})
+	if err != nil {
+		return nil, NewKubernetesError("read_broker_config", pod.Name, err)
+	}
+
+	formatted, err := FormatXML(raw)
+	if err != nil {
+		// Show the file as-is rather than failing on a config the broker may still accept
+		formatted = raw
+	}
+
+	envOutput, err := k.ExecCommandInContainer(ctx, pod.Namespace, pod.Name, container, []string{"env"})
+	if err != nil {
+		return nil, NewKubernetesError("read_broker_env", pod.Name, err)
+	}
+
+	return &BrokerConfig{
+		Pod:        pod.Name,
+		Container:  container,
+		ConfigPath: configPath,
+		ConfigXML:  formatted,
+		Env:        configEnv(envOutput, raw),
+	}, nil
+}
+
+// Lines returns the configuration followed by the relevant environment, one entry per line,
+// in a stable order suitable for diffing.
+func (c *BrokerConfig) Lines() []string {
+	lines := strings.Split(strings.TrimRight(c.ConfigXML, "\n"), "\n")
+
+	keys := make([]string, 0, len(c.Env))
+	for key := range c.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("env %s=%s", key, c.Env[key]))
+	}
+	return lines
+}
+
+// configEnv filters `env` output down to variables that affect the broker configuration
+func configEnv(envOutput, configXML string) map[string]string {
+	referenced := make(map[string]bool)
+	for _, match := range configPlaceholder.FindAllStringSubmatch(configXML, -1) {
+		referenced[match[1]] = true
+	}
+
+	env := make(map[string]string)
+	for _, line := range strings.Split(envOutput, "\n") {
+		key, value, ok := strings.Cut(strings.TrimRight(line, "\r"), "=")
+		if !ok || key == "" {
+			continue
+		}
+		if !referenced[key] && !strings.HasPrefix(key, "HIVEMQ_") && key != "JAVA_OPTS" {
+			continue
+		}
+		if isSecretName(key) {
+			value = maskedValue
+		}
+		env[key] = value
+	}
+	return env
+}
+
+func isSecretName(key string) bool {
+	upper := strings.ToUpper(key)
+	for _, marker := range []string{"PASSWORD", "SECRET", "TOKEN", "CREDENTIAL", "PRIVATE_KEY", "ACCESS_KEY"} {
+		if strings.Contains(upper, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatXML re-indents an XML document with two spaces, dropping insignificant whitespace
+// so that files differing only in formatting compare equal.
+func FormatXML(document string) (string, error) {
+	decoder := xml.NewDecoder(strings.NewReader(document))
+	var buf bytes.Buffer
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+
+	for {
+		// RawToken keeps prefixes as written; Token would rewrite them into namespace URLs
+		token, err := decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid XML: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) == 0 {
+				continue
+			}
+			token = xml.CharData(bytes.TrimSpace(t))
+		case xml.StartElement:
+			t.Name = flattenName(t.Name)
+			attrs := make([]xml.Attr, len(t.Attr))
+			for i, attr := range t.Attr {
+				attrs[i] = xml.Attr{Name: flattenName(attr.Name), Value: attr.Value}
+			}
+			t.Attr = attrs
+			token = t
+		case xml.EndElement:
+			t.Name = flattenName(t.Name)
+			token = t
+		case xml.ProcInst:
+			// The encoder only accepts the declaration as the very first token
+			if t.Target == "xml" {
+				continue
+			}
+		}
+
+		if err := encoder.EncodeToken(xml.CopyToken(token)); err != nil {
+			return "", fmt.Errorf("failed to format XML: %w", err)
+		}
+	}
+
+	if err := encoder.Flush(); err != nil {
+		return "", fmt.Errorf("failed to format XML: %w", err)
+	}
+	return buf.String() + "\n", nil
+}
+
+// flattenName turns prefix:local into a plain local name so the encoder writes it unchanged
+func flattenName(name xml.Name) xml.Name {
+	if name.Space == "" {
+		return name
+	}
+	return xml.Name{Local: name.Space + ":" + name.Local}
+}
+
+// DiffOp marks a line in a DiffLines result
+type DiffOp string
+
+const (
+	DiffEqual   DiffOp = " "
+	DiffRemoved DiffOp = "-"
+	DiffAdded   DiffOp = "+"
+)
+
+// DiffLine is one line of a line-based diff
+type DiffLine struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// DiffLines computes a minimal line diff from a to b using the longest common subsequence
+func DiffLines(a, b []string) []DiffLine {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diff := make([]DiffLine, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, DiffLine{Op: DiffEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, DiffLine{Op: DiffRemoved, Text: a[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Op: DiffAdded, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, DiffLine{Op: DiffRemoved, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, DiffLine{Op: DiffAdded, Text: b[j]})
+	}
+	return diff
+}
+
+// HasChanges reports whether a diff contains added or removed lines
+func HasChanges(diff []DiffLine) bool {
+	for _, line := range diff {
+		if line.Op != DiffEqual {
+			return true
+		}
+	}
+	return false
+}
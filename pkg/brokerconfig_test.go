@@ -0,0 +1,55 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatXMLIgnoresWhitespaceDifferences(t *testing.T) {
+	t.Parallel()
+
+	compact := `<?xml version="1.0"?><hivemq xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:noNamespaceSchemaLocation="config.xsd"><listeners><tcp-listener><port>1883</port></tcp-listener></listeners></hivemq>`
+	spaced := "<?xml version=\"1.0\"?>\n<hivemq xmlns:xsi=\"http://www.w3.org/2001/XMLSchema-instance\"\n        xsi:noNamespaceSchemaLocation=\"config.xsd\">\n    <listeners>\n\t<tcp-listener>\n   <port> 1883 </port>\n</tcp-listener>\n    </listeners>\n</hivemq>\n"
+
+	a, err := FormatXML(compact)
+	if err != nil {
+		t.Fatalf("FormatXML returned error: %v", err)
+	}
+	b, err := FormatXML(spaced)
+	if err != nil {
+		t.Fatalf("FormatXML returned error: %v", err)
+	}
+	if a != b {
+		t.Fatalf("expected identical output, got\n%s\nvs\n%s", a, b)
+	}
+	if !strings.Contains(a, `<hivemq xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:noNamespaceSchemaLocation="config.xsd">`) ||
+		!strings.Contains(a, "\n    <tcp-listener>") {
+		t.Fatalf("expected nested indentation, got\n%s", a)
+	}
+
+	if _, err := FormatXML("<hivemq><listeners></hivemq>"); err == nil {
+		t.Fatal("expected malformed XML to be rejected")
+	}
+}
+
+func TestDiffLinesAndConfigEnv(t *testing.T) {
+	t.Parallel()
+
+	diff := DiffLines([]string{"a", "b", "c"}, []string{"a", "x", "c", "d"})
+	var got []string
+	for _, line := range diff {
+		got = append(got, string(line.Op)+line.Text)
+	}
+	want := "| a|-b|+x| c|+d"
+	if strings.Join(append([]string{""}, got...), "|") != want {
+		t.Fatalf("unexpected diff %q", got)
+	}
+	if HasChanges(DiffLines([]string{"a"}, []string{"a"})) {
+		t.Fatal("identical input must not report changes")
+	}
+
+	env := configEnv("PATH=/bin\nHIVEMQ_LOG_LEVEL=DEBUG\nDB_PASSWORD=hunter2\nCLUSTER_PORT=7800\n", `<port>${ENV:CLUSTER_PORT}</port><pw>${DB_PASSWORD}</pw>`)
+	if len(env) != 3 || env["HIVEMQ_LOG_LEVEL"] != "DEBUG" || env["CLUSTER_PORT"] != "7800" || env["DB_PASSWORD"] != maskedValue {
+		t.Fatalf("unexpected env %v", env)
+	}
+}
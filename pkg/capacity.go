@@ -0,0 +1,481 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Outcomes of a single capacity check
+const (
+	CapacityCheckPass = "PASS"
+	CapacityCheckWarn = "WARN"
+	CapacityCheckFail = "FAIL"
+)
+
+// Verdicts of a capacity report
+const (
+	CapacityGo   = "GO"
+	CapacityNoGo = "NO-GO"
+)
+
+// noProvisioner marks StorageClasses whose volumes have to be created by hand
+const noProvisioner = "kubernetes.io/no-provisioner"
+
+const defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+// CapacityCheck is the outcome of one headroom check
+type CapacityCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Details string `json:"details,omitempty"`
+}
+
+// NodeHeadroom is what a node has left for additional broker pods
+type NodeHeadroom struct {
+	Name       string            `json:"name"`
+	FreeCPU    resource.Quantity `json:"freeCpu"`
+	FreeMemory resource.Quantity `json:"freeMemory"`
+	FreePods   int64             `json:"freePods"`
+	Fits       int64             `json:"fits"`             // additional broker pods the node can take
+	Reason     string            `json:"reason,omitempty"` // why the node takes no broker pods
+}
+
+// CapacityReport tells whether the cluster can run a StatefulSet at the target replica count
+type CapacityReport struct {
+	Namespace       string            `json:"namespace"`
+	StatefulSet     string            `json:"statefulSet"`
+	CurrentReplicas int32             `json:"currentReplicas"`
+	TargetReplicas  int32             `json:"targetReplicas"`
+	PodsToPlace     int32             `json:"podsToPlace"` // target pods not yet scheduled on a node
+	PodCPU          resource.Quantity `json:"podCpu"`
+	PodMemory       resource.Quantity `json:"podMemory"`
+	Nodes           []NodeHeadroom    `json:"nodes"`
+	Checks          []CapacityCheck   `json:"checks"`
+}
+
+// Verdict is CapacityNoGo as soon as one check failed
+func (r *CapacityReport) Verdict() string {
+	for _, check := range r.Checks {
+		if check.Status == CapacityCheckFail {
+			return CapacityNoGo
+		}
+	}
+	return CapacityGo
+}
+
+func (r *CapacityReport) add(name, status, details string) {
+	r.Checks = append(r.Checks, CapacityCheck{Name: name, Status: status, Details: details})
+}
+
+// CheckCapacity verifies that the cluster has CPU, memory, pod and volume headroom to run the
+// StatefulSet with the target replicas (0 keeps the current count, e.g. before a restore).
+// Node headroom is allocatable minus the requests of all non-terminated pods; nodeSelector,
+// taints and one-pod-per-node anti-affinity are honoured, other affinity rules are not.
+func (k *K8sClient) CheckCapacity(ctx context.Context, namespace, statefulSetName string, replicas int32) (*CapacityReport, error) {
+	sts, err := k.GetStatefulSet(ctx, namespace, statefulSetName)
+	if err != nil {
+		return nil, NewKubernetesError("get_statefulset", statefulSetName, err)
+	}
+	pods, err := k.GetPodsFromStatefulSet(ctx, namespace, statefulSetName)
+	if err != nil {
+		return nil, err
+	}
+
+	template := sts.Spec.Template
+	cpu, memory := PodRequests(&template.Spec)
+	report := &CapacityReport{
+		Namespace:       namespace,
+		StatefulSet:     statefulSetName,
+		CurrentReplicas: 1,
+		PodCPU:          cpu,
+		PodMemory:       memory,
+	}
+	if sts.Spec.Replicas != nil {
+		report.CurrentReplicas = *sts.Spec.Replicas
+	}
+	report.TargetReplicas = report.CurrentReplicas
+	if replicas > 0 {
+		report.TargetReplicas = replicas
+	}
+
+	hostsBroker := make(map[string]bool)
+	scheduled := int32(0)
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" {
+			hostsBroker[pod.Spec.NodeName] = true
+			if ordinal, ok := statefulSetOrdinal(pod.Name, statefulSetName); ok && ordinal < int(report.TargetReplicas) {
+				scheduled++
+			}
+		}
+	}
+	report.PodsToPlace = max(report.TargetReplicas-scheduled, 0)
+
+	if cpu.IsZero() || memory.IsZero() {
+		report.add("Pod requests", CapacityCheckWarn, "pod template lacks CPU or memory requests; only the pod count per node is verified")
+	} else {
+		report.add("Pod requests", CapacityCheckPass, fmt.Sprintf("%s CPU, %s memory per pod", cpu.String(), memory.String()))
+	}
+
+	if err := k.checkNodeHeadroom(ctx, report, &template, hostsBroker); err != nil {
+		return nil, err
+	}
+	if err := k.checkVolumeHeadroom(ctx, report, sts.Spec.VolumeClaimTemplates); err != nil {
+		return nil, err
+	}
+	if err := k.checkQuotaHeadroom(ctx, report, sts.Spec.VolumeClaimTemplates); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// checkNodeHeadroom computes the free resources of every schedulable node and whether the
+// pods to place fit
+func (k *K8sClient) checkNodeHeadroom(ctx context.Context, report *CapacityReport, template *v1.PodTemplateSpec, hostsBroker map[string]bool) error {
+	nodes, err := k.coreClient.Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return NewKubernetesError("list_nodes", "nodes", err)
+	}
+	running, err := k.coreClient.Pods("").List(ctx, metav1.ListOptions{FieldSelector: "status.phase!=Succeeded,status.phase!=Failed"})
+	if err != nil {
+		return NewKubernetesError("list_pods", "all namespaces", err)
+	}
+
+	usedCPU := make(map[string]*resource.Quantity)
+	usedMemory := make(map[string]*resource.Quantity)
+	podCount := make(map[string]int64)
+	for i := range running.Items {
+		pod := &running.Items[i]
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		cpu, memory := PodRequests(&pod.Spec)
+		if usedCPU[pod.Spec.NodeName] == nil {
+			usedCPU[pod.Spec.NodeName] = resource.NewQuantity(0, resource.DecimalSI)
+			usedMemory[pod.Spec.NodeName] = resource.NewQuantity(0, resource.BinarySI)
+		}
+		usedCPU[pod.Spec.NodeName].Add(cpu)
+		usedMemory[pod.Spec.NodeName].Add(memory)
+		podCount[pod.Spec.NodeName]++
+	}
+
+	onePerNode := OneBrokerPerNode(template)
+	selector := labels.SelectorFromSet(template.Spec.NodeSelector)
+	var capacity int64
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		headroom := NodeHeadroom{Name: node.Name}
+
+		headroom.FreeCPU = node.Status.Allocatable.Cpu().DeepCopy()
+		headroom.FreeMemory = node.Status.Allocatable.Memory().DeepCopy()
+		if used := usedCPU[node.Name]; used != nil {
+			headroom.FreeCPU.Sub(*used)
+			headroom.FreeMemory.Sub(*usedMemory[node.Name])
+		}
+		headroom.FreePods = node.Status.Allocatable.Pods().Value() - podCount[node.Name]
+
+		switch {
+		case node.Spec.Unschedulable:
+			headroom.Reason = "cordoned"
+		case !nodeReady(node):
+			headroom.Reason = "not ready"
+		case !selector.Matches(labels.Set(node.Labels)):
+			headroom.Reason = "does not match nodeSelector"
+		case untoleratedTaint(node, template.Spec.Tolerations) != "":
+			headroom.Reason = "taint " + untoleratedTaint(node, template.Spec.Tolerations)
+		case onePerNode && hostsBroker[node.Name]:
+			headroom.Reason = "already runs a broker pod (anti-affinity)"
+		default:
+			headroom.Fits = PodsFitting(headroom.FreeCPU, headroom.FreeMemory, headroom.FreePods, report.PodCPU, report.PodMemory)
+			if onePerNode && headroom.Fits > 1 {
+				headroom.Fits = 1
+			}
+			if headroom.Fits == 0 {
+				headroom.Reason = "insufficient CPU, memory or pod slots"
+			}
+		}
+		capacity += headroom.Fits
+		report.Nodes = append(report.Nodes, headroom)
+	}
+
+	const name = "Node headroom"
+	switch {
+	case report.PodsToPlace == 0:
+		report.add(name, CapacityCheckPass, fmt.Sprintf("all %d target pods are already scheduled", report.TargetReplicas))
+	case capacity >= int64(report.PodsToPlace):
+		report.add(name, CapacityCheckPass, fmt.Sprintf("room for %d pods, %d to place", capacity, report.PodsToPlace))
+	default:
+		details := fmt.Sprintf("room for %d pods, %d to place", capacity, report.PodsToPlace)
+		if onePerNode {
+			details += "; anti-affinity allows one broker pod per node"
+		}
+		report.add(name, CapacityCheckFail, details)
+	}
+	return nil
+}
+
+// checkVolumeHeadroom verifies that the claims of new pods can be provisioned
+func (k *K8sClient) checkVolumeHeadroom(ctx context.Context, report *CapacityReport, templates []v1.PersistentVolumeClaim) error {
+	if len(templates) == 0 {
+		return nil
+	}
+	classes, err := k.storage.StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		slog.Debug("Skipping StorageClass checks", "error", err)
+		report.add("Volumes", CapacityCheckWarn, fmt.Sprintf("cannot list StorageClasses: %v", err))
+		return nil
+	}
+
+	for i := range templates {
+		claimTemplate := &templates[i]
+		name := "Volumes " + claimTemplate.Name
+
+		missing, err := k.missingClaims(ctx, report, claimTemplate.Name)
+		if err != nil {
+			return err
+		}
+		class := claimStorageClass(claimTemplate, classes.Items)
+		switch {
+		case missing == 0:
+			report.add(name, CapacityCheckPass, "all claims exist")
+		case class == nil:
+			report.add(name, CapacityCheckFail, fmt.Sprintf("%d claims needed but StorageClass %s does not exist", missing, storageClassLabel(claimTemplate)))
+		case class.Provisioner == noProvisioner:
+			available, err := k.availableVolumes(ctx, class.Name, claimTemplate.Spec.Resources.Requests[v1.ResourceStorage])
+			if err != nil {
+				return err
+			}
+			if available < missing {
+				report.add(name, CapacityCheckFail, fmt.Sprintf("%d claims needed but StorageClass %s has no provisioner and only %d matching volumes are available", missing, class.Name, available))
+				continue
+			}
+			report.add(name, CapacityCheckPass, fmt.Sprintf("%d claims bind to %d available volumes of %s", missing, available, class.Name))
+		default:
+			report.add(name, CapacityCheckPass, fmt.Sprintf("%d claims provisioned by %s (StorageClass %s)", missing, class.Provisioner, class.Name))
+		}
+	}
+	return nil
+}
+
+// missingClaims counts the claims of the template that the target pods still need
+func (k *K8sClient) missingClaims(ctx context.Context, report *CapacityReport, templateName string) (int, error) {
+	missing := 0
+	for ordinal := int32(0); ordinal < report.TargetReplicas; ordinal++ {
+		claimName := fmt.Sprintf("%s-%s-%d", templateName, report.StatefulSet, ordinal)
+		_, err := k.coreClient.PersistentVolumeClaims(report.Namespace).Get(ctx, claimName, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			missing++
+		case err != nil:
+			return 0, NewKubernetesError("get_persistent_volume_claim", claimName, err)
+		}
+	}
+	return missing, nil
+}
+
+// availableVolumes counts Available PVs of the class large enough for the request
+func (k *K8sClient) availableVolumes(ctx context.Context, className string, request resource.Quantity) (int, error) {
+	pvs, err := k.coreClient.PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, NewKubernetesError("list_persistent_volumes", className, err)
+	}
+	available := 0
+	for _, pv := range pvs.Items {
+		if pv.Status.Phase != v1.VolumeAvailable || pv.Spec.StorageClassName != className {
+			continue
+		}
+		if size, ok := pv.Spec.Capacity[v1.ResourceStorage]; ok && size.Cmp(request) >= 0 {
+			available++
+		}
+	}
+	return available, nil
+}
+
+// checkQuotaHeadroom verifies the namespace's ResourceQuotas leave room for the pods to place
+// and their new claims
+func (k *K8sClient) checkQuotaHeadroom(ctx context.Context, report *CapacityReport, templates []v1.PersistentVolumeClaim) error {
+	quotas, err := k.coreClient.ResourceQuotas(report.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return NewKubernetesError("list_resource_quotas", report.Namespace, err)
+	}
+	if len(quotas.Items) == 0 {
+		return nil
+	}
+
+	newPods := int64(report.TargetReplicas - report.CurrentReplicas)
+	if newPods <= 0 {
+		report.add("Resource quota", CapacityCheckPass, "no additional pods or claims")
+		return nil
+	}
+
+	needed := v1.ResourceList{
+		v1.ResourcePods:                   *resource.NewQuantity(newPods, resource.DecimalSI),
+		v1.ResourceRequestsCPU:            multiplyQuantity(report.PodCPU, newPods),
+		v1.ResourceRequestsMemory:         multiplyQuantity(report.PodMemory, newPods),
+		v1.ResourcePersistentVolumeClaims: *resource.NewQuantity(newPods*int64(len(templates)), resource.DecimalSI),
+	}
+	storage := resource.NewQuantity(0, resource.BinarySI)
+	for i := range templates {
+		request := multiplyQuantity(templates[i].Spec.Resources.Requests[v1.ResourceStorage], newPods)
+		storage.Add(request)
+		if className := templates[i].Spec.StorageClassName; className != nil && *className != "" {
+			key := v1.ResourceName(*className + ".storageclass.storage.k8s.io/requests.storage")
+			perClass := needed[key]
+			perClass.Add(request)
+			needed[key] = perClass
+		}
+	}
+	needed[v1.ResourceRequestsStorage] = *storage
+
+	var exceeded []string
+	for _, quota := range quotas.Items {
+		for name, hard := range quota.Status.Hard {
+			want, ok := needed[name]
+			if !ok {
+				continue
+			}
+			left := hard.DeepCopy()
+			left.Sub(quota.Status.Used[name])
+			if left.Cmp(want) < 0 {
+				exceeded = append(exceeded, fmt.Sprintf("%s %s (needs %s, %s left)", quota.Name, name, want.String(), left.String()))
+			}
+		}
+	}
+	if len(exceeded) > 0 {
+		report.add("Resource quota", CapacityCheckFail, strings.Join(exceeded, "; "))
+		return nil
+	}
+	report.add("Resource quota", CapacityCheckPass, fmt.Sprintf("%d quotas leave room for %d pods", len(quotas.Items), newPods))
+	return nil
+}
+
+// PodRequests returns the CPU and memory requests the scheduler reserves for a pod: the sum
+// of its containers, at least the largest init container, plus the pod overhead
+func PodRequests(spec *v1.PodSpec) (cpu, memory resource.Quantity) {
+	cpu = *resource.NewQuantity(0, resource.DecimalSI)
+	memory = *resource.NewQuantity(0, resource.BinarySI)
+	for _, container := range spec.Containers {
+		cpu.Add(*container.Resources.Requests.Cpu())
+		memory.Add(*container.Resources.Requests.Memory())
+	}
+	for _, container := range spec.InitContainers {
+		if initCPU := container.Resources.Requests.Cpu(); initCPU.Cmp(cpu) > 0 {
+			cpu = initCPU.DeepCopy()
+		}
+		if initMemory := container.Resources.Requests.Memory(); initMemory.Cmp(memory) > 0 {
+			memory = initMemory.DeepCopy()
+		}
+	}
+	cpu.Add(*spec.Overhead.Cpu())
+	memory.Add(*spec.Overhead.Memory())
+	return cpu, memory
+}
+
+// PodsFitting returns how many pods with the given requests fit into the free resources; zero
+// requests only limit by the free pod slots
+func PodsFitting(freeCPU, freeMemory resource.Quantity, freePods int64, cpu, memory resource.Quantity) int64 {
+	fits := freePods
+	if cpu.MilliValue() > 0 && freeCPU.MilliValue()/cpu.MilliValue() < fits {
+		fits = freeCPU.MilliValue() / cpu.MilliValue()
+	}
+	if memory.Value() > 0 && freeMemory.Value()/memory.Value() < fits {
+		fits = freeMemory.Value() / memory.Value()
+	}
+	return max(fits, 0)
+}
+
+// OneBrokerPerNode reports whether required pod anti-affinity on the hostname keeps pods of
+// the template apart
+func OneBrokerPerNode(template *v1.PodTemplateSpec) bool {
+	affinity := template.Spec.Affinity
+	if affinity == nil || affinity.PodAntiAffinity == nil {
+		return false
+	}
+	for _, term := range affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+		if term.TopologyKey != v1.LabelHostname {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(term.LabelSelector)
+		if err == nil && selector.Matches(labels.Set(template.Labels)) {
+			return true
+		}
+	}
+	return false
+}
+
+// statefulSetOrdinal parses the ordinal of a pod named <statefulset>-<ordinal>
+func statefulSetOrdinal(podName, statefulSetName string) (int, bool) {
+	suffix, ok := strings.CutPrefix(podName, statefulSetName+"-")
+	if !ok {
+		return 0, false
+	}
+	ordinal, err := strconv.Atoi(suffix)
+	return ordinal, err == nil
+}
+
+func nodeReady(node *v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// untoleratedTaint returns the first NoSchedule or NoExecute taint the tolerations do not cover
+func untoleratedTaint(node *v1.Node, tolerations []v1.Toleration) string {
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == v1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return taint.ToString()
+		}
+	}
+	return ""
+}
+
+// claimStorageClass resolves the StorageClass of a claim template, falling back to the default
+func claimStorageClass(claim *v1.PersistentVolumeClaim, classes []storagev1.StorageClass) *storagev1.StorageClass {
+	for i := range classes {
+		class := &classes[i]
+		if claim.Spec.StorageClassName != nil {
+			if class.Name == *claim.Spec.StorageClassName {
+				return class
+			}
+			continue
+		}
+		if class.Annotations[defaultStorageClassAnnotation] == "true" {
+			return class
+		}
+	}
+	return nil
+}
+
+func storageClassLabel(claim *v1.PersistentVolumeClaim) string {
+	if claim.Spec.StorageClassName == nil {
+		return "(default)"
+	}
+	return *claim.Spec.StorageClassName
+}
+
+func multiplyQuantity(quantity resource.Quantity, factor int64) resource.Quantity {
+	return *resource.NewMilliQuantity(quantity.MilliValue()*factor, quantity.Format)
+}
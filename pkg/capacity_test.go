@@ -0,0 +1,97 @@
+package pkg
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodRequests(t *testing.T) {
+	t.Parallel()
+
+	requests := func(cpu, memory string) v1.ResourceRequirements {
+		return v1.ResourceRequirements{Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpu),
+			v1.ResourceMemory: resource.MustParse(memory),
+		}}
+	}
+	spec := &v1.PodSpec{
+		Containers: []v1.Container{
+			{Name: "hivemq", Resources: requests("2", "4Gi")},
+			{Name: "sidecar", Resources: requests("100m", "128Mi")},
+		},
+		// The init container needs more memory but less CPU than the app containers together
+		InitContainers: []v1.Container{{Name: "init", Resources: requests("500m", "8Gi")}},
+		Overhead:       v1.ResourceList{v1.ResourceCPU: resource.MustParse("50m")},
+	}
+
+	cpu, memory := PodRequests(spec)
+	if got := cpu.MilliValue(); got != 2150 {
+		t.Errorf("cpu = %dm, want 2150m", got)
+	}
+	if want := resource.MustParse("8Gi"); memory.Cmp(want) != 0 {
+		t.Errorf("memory = %s, want %s", memory.String(), want.String())
+	}
+}
+
+func TestPodsFitting(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		freeCPU    string
+		freeMemory string
+		freePods   int64
+		cpu        string
+		memory     string
+		want       int64
+	}{
+		{"limited by memory", "8", "10Gi", 110, "2", "4Gi", 2},
+		{"limited by cpu", "3", "64Gi", 110, "2", "4Gi", 1},
+		{"limited by pod slots", "32", "64Gi", 1, "2", "4Gi", 1},
+		{"overcommitted node", "-1", "10Gi", 110, "2", "4Gi", 0},
+		{"no requests", "0", "0", 5, "0", "0", 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := PodsFitting(resource.MustParse(tt.freeCPU), resource.MustParse(tt.freeMemory), tt.freePods,
+				resource.MustParse(tt.cpu), resource.MustParse(tt.memory))
+			if got != tt.want {
+				t.Errorf("PodsFitting() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOneBrokerPerNode(t *testing.T) {
+	t.Parallel()
+
+	template := func(topologyKey string, matchLabels map[string]string) *v1.PodTemplateSpec {
+		spec := &v1.PodTemplateSpec{}
+		spec.Labels = map[string]string{"app": "hivemq", "role": "broker"}
+		spec.Spec.Affinity = &v1.Affinity{PodAntiAffinity: &v1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{{
+				TopologyKey:   topologyKey,
+				LabelSelector: &metav1.LabelSelector{MatchLabels: matchLabels},
+			}},
+		}}
+		return spec
+	}
+
+	if !OneBrokerPerNode(template(v1.LabelHostname, map[string]string{"app": "hivemq"})) {
+		t.Error("expected hostname anti-affinity on own labels to spread pods")
+	}
+	if OneBrokerPerNode(template(v1.LabelTopologyZone, map[string]string{"app": "hivemq"})) {
+		t.Error("zone anti-affinity must not limit pods per node")
+	}
+	if OneBrokerPerNode(template(v1.LabelHostname, map[string]string{"app": "other"})) {
+		t.Error("anti-affinity against other pods must not limit broker pods per node")
+	}
+	if OneBrokerPerNode(&v1.PodTemplateSpec{}) {
+		t.Error("template without affinity must not limit pods per node")
+	}
+}
@@ -0,0 +1,133 @@
+// Package collect assembles the support archive HiveMQ support asks for: health responses,
+// logs, Kubernetes manifests, events, volume analysis and backup inventory of one installation.
+package collect
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// ManifestFile is the name of the archive's table of contents
+const ManifestFile = "manifest.json"
+
+// Manifest describes what an archive contains and which artifacts could not be collected
+type Manifest struct {
+	Namespace   string    `json:"namespace"`
+	StatefulSet string    `json:"statefulSet"`
+	CollectedAt time.Time `json:"collectedAt"`
+	Files       []string  `json:"files"`
+	Failures    []Failure `json:"failures,omitempty"`
+	Redaction   string    `json:"redaction"`
+}
+
+// Failure records an artifact that could not be collected
+type Failure struct {
+	Artifact string `json:"artifact"`
+	Error    string `json:"error"`
+}
+
+// Archive writes a gzip-compressed tar archive whose entries share a top-level directory, so
+// unpacking it never scatters files into the current directory
+type Archive struct {
+	file     *os.File
+	gzip     *gzip.Writer
+	tar      *tar.Writer
+	root     string
+	modTime  time.Time
+	manifest Manifest
+}
+
+// Create starts an archive at path with entries below root
+func Create(filePath, root string, manifest Manifest) (*Archive, error) {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive: %w", err)
+	}
+	gz := gzip.NewWriter(file)
+	if manifest.CollectedAt.IsZero() {
+		manifest.CollectedAt = time.Now().UTC()
+	}
+	manifest.Redaction = RedactionNote
+	return &Archive{
+		file:     file,
+		gzip:     gz,
+		tar:      tar.NewWriter(gz),
+		root:     root,
+		modTime:  manifest.CollectedAt,
+		manifest: manifest,
+	}, nil
+}
+
+// Add writes one file; name is relative to the archive root
+func (a *Archive) Add(name string, data []byte) error {
+	header := &tar.Header{
+		Name:    path.Join(a.root, name),
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: a.modTime,
+	}
+	if err := a.tar.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	if _, err := a.tar.Write(data); err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	a.manifest.Files = append(a.manifest.Files, name)
+	return nil
+}
+
+// AddJSON writes value as indented JSON
+func (a *Archive) AddJSON(name string, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	return a.Add(name, append(data, '\n'))
+}
+
+// AddYAML writes value as YAML
+func (a *Archive) AddYAML(name string, value any) error {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	return a.Add(name, data)
+}
+
+// Fail records an artifact that could not be collected in the manifest
+func (a *Archive) Fail(artifact string, err error) {
+	a.manifest.Failures = append(a.manifest.Failures, Failure{Artifact: artifact, Error: err.Error()})
+}
+
+// Manifest returns the table of contents written so far
+func (a *Archive) Manifest() Manifest {
+	return a.manifest
+}
+
+// Close writes the manifest and flushes the archive; the file is removed when that fails
+func (a *Archive) Close() error {
+	manifest := a.manifest
+	manifest.Files = append(append([]string(nil), manifest.Files...), ManifestFile)
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err == nil {
+		err = a.Add(ManifestFile, append(data, '\n'))
+	}
+	for _, closer := range []io.Closer{a.tar, a.gzip, a.file} {
+		if closeErr := closer.Close(); err == nil && closeErr != nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		_ = os.Remove(a.file.Name())
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package collect
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestArchiveWritesFilesBelowRootWithManifest(t *testing.T) {
+	t.Parallel()
+
+	archivePath := filepath.Join(t.TempDir(), "diag.tar.gz")
+	archive, err := Create(archivePath, "hivemq-diag-prod", Manifest{Namespace: "prod", StatefulSet: "broker"})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if err := archive.Add("logs/broker-0/hivemq.log", []byte("started\n")); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+	archive.Fail("backups.json", errors.New("the management API rejected the credentials"))
+	if err := archive.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	entries := readArchive(t, archivePath)
+	if got := string(entries["hivemq-diag-prod/logs/broker-0/hivemq.log"]); got != "started\n" {
+		t.Fatalf("unexpected log entry %q (entries: %v)", got, entries)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(entries["hivemq-diag-prod/"+ManifestFile], &manifest); err != nil {
+		t.Fatalf("failed to decode manifest: %v", err)
+	}
+	if len(manifest.Files) != 2 || manifest.Files[1] != ManifestFile || len(manifest.Failures) != 1 || manifest.Redaction == "" {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+}
+
+func TestRedactPodRemovesCredentials(t *testing.T) {
+	t.Parallel()
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "broker-0",
+			Annotations:   map[string]string{lastAppliedAnnotation: `{"env":[{"name":"HIVEMQ_ADMIN_PASSWORD","value":"hunter2"}]}`},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+		},
+		Spec: v1.PodSpec{Containers: []v1.Container{{
+			Name: "hivemq",
+			Env: []v1.EnvVar{
+				{Name: "HIVEMQ_ADMIN_PASSWORD", Value: "hunter2"},
+				{Name: "HIVEMQ_CLUSTER_TRANSPORT_TYPE", Value: "TCP"},
+			},
+		}}},
+	}
+
+	redacted := RedactPod(pod)
+	if env := redacted.Spec.Containers[0].Env; env[0].Value != redactedValue || env[1].Value != "TCP" {
+		t.Fatalf("unexpected env after redaction: %+v", env)
+	}
+	if redacted.Annotations[lastAppliedAnnotation] != redactedValue || redacted.ManagedFields != nil {
+		t.Fatalf("expected annotation and managed fields to be removed: %+v", redacted.ObjectMeta)
+	}
+	if pod.Spec.Containers[0].Env[0].Value != "hunter2" {
+		t.Fatal("RedactPod modified the original pod")
+	}
+}
+
+func readArchive(t *testing.T, archivePath string) map[string][]byte {
+	t.Helper()
+	file, err := os.Open(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := map[string][]byte{}
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return entries
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(header.Name, "..") {
+			t.Fatalf("unexpected entry name %s", header.Name)
+		}
+		if entries[header.Name], err = io.ReadAll(reader); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
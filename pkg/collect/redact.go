@@ -0,0 +1,67 @@
+package collect
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"kubectl-broker/pkg/logging"
+)
+
+// RedactionNote is recorded in the manifest so support knows which values were removed
+const RedactionNote = "environment variable values with credential-like names, last-applied-configuration annotations, and credential-like key=value pairs and JSON members in logs and health responses are replaced with REDACTED"
+
+// redactedValue replaces removed values
+const redactedValue = "REDACTED"
+
+// lastAppliedAnnotation holds the full manifest kubectl applied, including any inline secrets
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// RedactPod returns a copy of pod without managed fields and credential values
+func RedactPod(pod *v1.Pod) *v1.Pod {
+	redacted := pod.DeepCopy()
+	redactObjectMeta(&redacted.ObjectMeta)
+	redactPodSpec(&redacted.Spec)
+	return redacted
+}
+
+// RedactStatefulSet returns a copy of sts without managed fields and credential values
+func RedactStatefulSet(sts *appsv1.StatefulSet) *appsv1.StatefulSet {
+	redacted := sts.DeepCopy()
+	redactObjectMeta(&redacted.ObjectMeta)
+	redactObjectMeta(&redacted.Spec.Template.ObjectMeta)
+	redactPodSpec(&redacted.Spec.Template.Spec)
+	return redacted
+}
+
+// RedactService returns a copy of service without managed fields
+func RedactService(service *v1.Service) *v1.Service {
+	redacted := service.DeepCopy()
+	redactObjectMeta(&redacted.ObjectMeta)
+	return redacted
+}
+
+// RedactText hides credential values in logs and API responses
+func RedactText(data []byte) []byte {
+	return []byte(logging.RedactText(string(data)))
+}
+
+func redactObjectMeta(meta *metav1.ObjectMeta) {
+	meta.ManagedFields = nil
+	if _, ok := meta.Annotations[lastAppliedAnnotation]; ok {
+		meta.Annotations[lastAppliedAnnotation] = redactedValue
+	}
+}
+
+func redactPodSpec(spec *v1.PodSpec) {
+	for _, containers := range [][]v1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			for j := range containers[i].Env {
+				env := &containers[i].Env[j]
+				if env.Value != "" && logging.SensitiveName(env.Name) {
+					env.Value = redactedValue
+				}
+			}
+		}
+	}
+}
@@ -1,11 +1,13 @@
 package pkg
 
 import (
+	"container/heap"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 	"sync"
 	"text/tabwriter"
 	"time"
@@ -26,10 +28,14 @@ type HealthCheckResult struct {
 	Error        error
 	ParsedHealth *health.ParsedHealthData
 	RawJSON      []byte
+	Diagnostics  *PodDiagnostics
+	Latency      LatencyStats // health request latencies, without the port-forward setup
 }
 
-// WorkerPoolConfig configures the worker pool for concurrent operations
+// WorkerPoolConfig configures the worker pool for concurrent operations.
+// The pool starts with MinWorkers and grows up to MaxWorkers while jobs are queued.
 type WorkerPoolConfig struct {
+	MinWorkers      int
 	MaxWorkers      int
 	QueueSize       int
 	RequestTimeout  time.Duration
@@ -39,6 +45,7 @@ type WorkerPoolConfig struct {
 // DefaultWorkerPoolConfig returns sensible defaults for the worker pool
 func DefaultWorkerPoolConfig() WorkerPoolConfig {
 	return WorkerPoolConfig{
+		MinWorkers:      1,
 		MaxWorkers:      min(runtime.NumCPU()*2, 10), // Limit to reasonable concurrency
 		QueueSize:       100,
 		RequestTimeout:  30 * time.Second,
@@ -54,52 +61,170 @@ func min(a, b int) int {
 	return b
 }
 
+// defaultCollectTimeout is the minimum bound of concurrent health checks when the caller sets no deadline
+const defaultCollectTimeout = 60 * time.Second
+
+// Statuses of pods whose health check did not run to completion
+const (
+	StatusSkipped  = "SKIPPED"
+	StatusTimedOut = "TIMED_OUT"
+)
+
+// JobPriority orders queued jobs; higher priorities are picked up first
+type JobPriority int
+
+const (
+	PriorityNormal JobPriority = 0
+	PriorityHigh   JobPriority = 10
+)
+
+// WorkerPoolStats is a snapshot of the worker pool counters
+type WorkerPoolStats struct {
+	Workers   int `json:"workers"`
+	Queued    int `json:"queued"`
+	InFlight  int `json:"inFlight"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
 // HealthCheckJob represents a health check job for the worker pool
 type HealthCheckJob struct {
-	Index   int
-	Pod     *v1.Pod
-	Port    int32
-	Options health.HealthCheckOptions
-	Result  chan<- HealthCheckResult
+	Index    int // position of the pod in the input, reported back with the result
+	Pod      *v1.Pod
+	Port     int32
+	Options  health.HealthCheckOptions
+	Priority JobPriority
+	Result   chan<- IndexedHealthCheckResult
 }
 
-// WorkerPool manages concurrent health check operations
+// IndexedHealthCheckResult is the result of a HealthCheckJob together with the job's Index
+type IndexedHealthCheckResult struct {
+	Index  int
+	Result HealthCheckResult
+}
+
+// TaskFunc is a generic unit of work executed by the worker pool.
+// The context carries the pool's per-job RequestTimeout; a non-nil error counts as failed in Stats.
+type TaskFunc func(ctx context.Context) error
+
+// poolJob is implemented by everything the worker pool can execute
+type poolJob interface {
+	run(ctx context.Context, wp *WorkerPool) error
+}
+
+// run performs the health check and reports the result on the job's channel
+func (job HealthCheckJob) run(ctx context.Context, wp *WorkerPool) error {
+	result := wp.k8sClient.performSinglePodHealthCheckWithContext(ctx, job.Pod, job.Port, job.Options)
+
+	select {
+	case job.Result <- IndexedHealthCheckResult{Index: job.Index, Result: result}:
+	case <-wp.ctx.Done():
+	}
+	return result.Error
+}
+
+// run executes the task
+func (task TaskFunc) run(ctx context.Context, _ *WorkerPool) error {
+	return task(ctx)
+}
+
+// queuedJob is a job waiting in the priority queue
+type queuedJob struct {
+	job      poolJob
+	priority JobPriority
+	seq      uint64
+}
+
+// jobQueue is a max-heap on priority that keeps FIFO order within a priority
+type jobQueue []queuedJob
+
+func (q jobQueue) Len() int { return len(q) }
+
+func (q jobQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q jobQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *jobQueue) Push(x any) { *q = append(*q, x.(queuedJob)) }
+
+func (q *jobQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// WorkerPool manages concurrent health check operations and generic tasks
 type WorkerPool struct {
-	workers   int
-	jobs      chan HealthCheckJob
-	results   chan HealthCheckResult
 	k8sClient *K8sClient
 	ctx       context.Context
 	cancel    context.CancelFunc
 	wg        sync.WaitGroup
 	config    WorkerPoolConfig
+
+	mu        sync.Mutex
+	cond      *sync.Cond
+	queue     jobQueue
+	seq       uint64
+	started   bool
+	closed    bool
+	workers   int
+	idle      int
+	inFlight  int
+	completed int
+	failed    int
 }
 
 // NewWorkerPool creates a new worker pool for health checks
 func NewWorkerPool(k8sClient *K8sClient, config WorkerPoolConfig) *WorkerPool {
-	ctx, cancel := context.WithCancel(context.Background())
-	return &WorkerPool{
-		workers:   config.MaxWorkers,
-		jobs:      make(chan HealthCheckJob, config.QueueSize),
-		results:   make(chan HealthCheckResult, config.QueueSize),
+	return NewWorkerPoolWithContext(context.Background(), k8sClient, config)
+}
+
+// NewWorkerPoolWithContext creates a worker pool whose jobs are cancelled together with parent
+func NewWorkerPoolWithContext(parent context.Context, k8sClient *K8sClient, config WorkerPoolConfig) *WorkerPool {
+	if config.MaxWorkers < 1 {
+		config.MaxWorkers = 1
+	}
+	if config.MinWorkers < 0 {
+		config.MinWorkers = 0
+	}
+	if config.MinWorkers > config.MaxWorkers {
+		config.MinWorkers = config.MaxWorkers
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	wp := &WorkerPool{
 		k8sClient: k8sClient,
 		ctx:       ctx,
 		cancel:    cancel,
 		config:    config,
 	}
+	wp.cond = sync.NewCond(&wp.mu)
+	return wp
 }
 
-// Start initializes and starts the worker pool
+// Start initializes the minimum number of workers; more are added on demand
 func (wp *WorkerPool) Start() {
-	for i := 0; i < wp.workers; i++ {
-		wp.wg.Add(1)
-		go wp.worker()
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	wp.started = true
+	for wp.workers < wp.config.MinWorkers {
+		wp.spawnWorkerLocked()
 	}
+	wp.scaleLocked()
 }
 
-// Stop gracefully shuts down the worker pool
+// Stop gracefully shuts down the worker pool after the queued jobs have run
 func (wp *WorkerPool) Stop() error {
-	close(wp.jobs)
+	wp.mu.Lock()
+	wp.closed = true
+	wp.cond.Broadcast()
+	wp.mu.Unlock()
 
 	// Wait for workers to finish with timeout
 	done := make(chan struct{})
@@ -112,56 +237,144 @@ func (wp *WorkerPool) Stop() error {
 	case <-done:
 		return nil
 	case <-time.After(wp.config.ShutdownTimeout):
-		wp.cancel() // Force cancellation if timeout exceeded
+		// Force cancellation if timeout exceeded and wake idle workers
+		wp.cancel()
+		wp.mu.Lock()
+		wp.cond.Broadcast()
+		wp.mu.Unlock()
 		return NewConfigurationError("worker_pool_shutdown", "timeout exceeded during worker pool shutdown")
 	}
 }
 
+// Stats returns a snapshot of the pool counters
+func (wp *WorkerPool) Stats() WorkerPoolStats {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	return WorkerPoolStats{
+		Workers:   wp.workers,
+		Queued:    len(wp.queue),
+		InFlight:  wp.inFlight,
+		Completed: wp.completed,
+		Failed:    wp.failed,
+	}
+}
+
 // SubmitJob submits a health check job to the worker pool
 func (wp *WorkerPool) SubmitJob(job HealthCheckJob) error {
-	select {
-	case wp.jobs <- job:
-		return nil
-	case <-wp.ctx.Done():
+	return wp.submit(job, job.Priority)
+}
+
+// SubmitTask submits a generic task to the worker pool
+func (wp *WorkerPool) SubmitTask(task TaskFunc) error {
+	return wp.submit(task, PriorityNormal)
+}
+
+// SubmitTaskWithPriority submits a generic task ahead of lower priority work
+func (wp *WorkerPool) SubmitTaskWithPriority(task TaskFunc, priority JobPriority) error {
+	return wp.submit(task, priority)
+}
+
+func (wp *WorkerPool) submit(job poolJob, priority JobPriority) error {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if wp.closed || wp.ctx.Err() != nil {
 		return NewConfigurationError("worker_pool_submit", "worker pool is shutting down")
-	default:
+	}
+	if wp.config.QueueSize > 0 && len(wp.queue) >= wp.config.QueueSize {
 		return NewConfigurationError("worker_pool_submit", "job queue is full")
 	}
+
+	wp.seq++
+	heap.Push(&wp.queue, queuedJob{job: job, priority: priority, seq: wp.seq})
+	wp.scaleLocked()
+	wp.cond.Signal()
+	return nil
 }
 
-// worker processes health check jobs
+// scaleLocked adds workers while queued jobs outnumber idle workers, up to MaxWorkers.
+// The caller must hold wp.mu.
+func (wp *WorkerPool) scaleLocked() {
+	if !wp.started {
+		return
+	}
+	for len(wp.queue) > wp.idle && wp.workers < wp.config.MaxWorkers {
+		wp.spawnWorkerLocked()
+	}
+}
+
+// spawnWorkerLocked starts a worker goroutine. The caller must hold wp.mu.
+func (wp *WorkerPool) spawnWorkerLocked() {
+	wp.workers++
+	wp.idle++
+	wp.wg.Add(1)
+	go wp.worker()
+}
+
+// worker processes queued jobs in priority order. Workers above MinWorkers exit
+// once the queue is drained so the pool shrinks back when demand drops.
 func (wp *WorkerPool) worker() {
 	defer wp.wg.Done()
 
+	wp.mu.Lock()
 	for {
-		select {
-		case job, ok := <-wp.jobs:
-			if !ok {
-				return // Channel closed, worker should exit
-			}
+		for len(wp.queue) == 0 && !wp.closed && wp.ctx.Err() == nil && wp.workers <= wp.config.MinWorkers {
+			wp.cond.Wait()
+		}
+		if wp.ctx.Err() != nil || len(wp.queue) == 0 {
+			// Cancelled, shut down with an empty queue, or surplus worker with nothing to do
+			wp.workers--
+			wp.idle--
+			wp.mu.Unlock()
+			return
+		}
 
-			// Create context with timeout for this specific job
-			jobCtx, cancel := context.WithTimeout(wp.ctx, wp.config.RequestTimeout)
-			result := wp.k8sClient.performSinglePodHealthCheckWithContext(jobCtx, job.Pod, job.Port, job.Options)
-			cancel()
+		item := heap.Pop(&wp.queue).(queuedJob)
+		wp.idle--
+		wp.inFlight++
+		wp.mu.Unlock()
 
-			// Send result back
-			select {
-			case job.Result <- result:
-			case <-wp.ctx.Done():
-				return
-			}
+		// Create context with timeout for this specific job
+		jobCtx, cancel := context.WithTimeout(wp.ctx, wp.config.RequestTimeout)
+		err := item.job.run(jobCtx, wp)
+		cancel()
 
-		case <-wp.ctx.Done():
-			return
+		wp.mu.Lock()
+		wp.inFlight--
+		wp.idle++
+		if err != nil {
+			wp.failed++
+		} else {
+			wp.completed++
 		}
 	}
 }
 
 // PerformConcurrentHealthChecks performs health checks on multiple pods concurrently using a worker pool
 func (k *K8sClient) PerformConcurrentHealthChecks(ctx context.Context, pods []*v1.Pod, portOverride int32, options health.HealthCheckOptions) error {
+	results, err := k.CollectConcurrentHealthChecks(ctx, pods, portOverride, options)
+	if results == nil {
+		return err
+	}
+
+	// Display results in tabular format, including partial results of an aborted run
+	if displayErr := k.DisplayHealthCheckResults(results, options); displayErr != nil {
+		return displayErr
+	}
+	return err
+}
+
+// CollectConcurrentHealthChecks runs health checks on multiple pods concurrently and returns
+// the results in pod order without displaying them.
+//
+// A pod whose check cannot be queued is reported as failed instead of aborting the run. Once
+// options.MaxFailures checks have failed the remaining checks are cancelled and reported as
+// SKIPPED; when the collection times out the unfinished checks are reported as TIMED_OUT. In
+// both cases the partial results are returned together with an error.
+func (k *K8sClient) CollectConcurrentHealthChecks(ctx context.Context, pods []*v1.Pod, portOverride int32, options health.HealthCheckOptions) ([]HealthCheckResult, error) {
 	if len(pods) == 0 {
-		return NewValidationError("health_check", "", "no pods provided for health check")
+		return nil, NewValidationError("health_check", "", "no pods provided for health check")
 	}
 
 	// Use worker pool for better resource management
@@ -170,8 +383,14 @@ func (k *K8sClient) PerformConcurrentHealthChecks(ctx context.Context, pods []*v
 	if len(pods) < config.MaxWorkers {
 		config.MaxWorkers = len(pods)
 	}
+	// Queue every pod at once; the pool bounds the concurrency
+	config.QueueSize = max(config.QueueSize, len(pods))
+	// Every additional latency sample may take up to the request timeout
+	if options.Samples > 1 {
+		config.RequestTimeout += time.Duration(options.Samples-1) * options.Timeout
+	}
 
-	wp := NewWorkerPool(k, config)
+	wp := NewWorkerPoolWithContext(ctx, k, config)
 	wp.Start()
 	defer func() {
 		if err := wp.Stop(); err != nil {
@@ -180,9 +399,11 @@ func (k *K8sClient) PerformConcurrentHealthChecks(ctx context.Context, pods []*v
 		}
 	}()
 
-	// Create results channel and slice
+	// Results are written into the slot of their pod so the output keeps the input order
 	results := make([]HealthCheckResult, len(pods))
-	resultsChan := make(chan HealthCheckResult, len(pods))
+	done := make([]bool, len(pods))
+	resultsChan := make(chan IndexedHealthCheckResult, len(pods))
+	completedCount, failedCount := 0, 0
 
 	// Submit jobs to worker pool
 	for i, pod := range pods {
@@ -193,38 +414,80 @@ func (k *K8sClient) PerformConcurrentHealthChecks(ctx context.Context, pods []*v
 			Options: options,
 			Result:  resultsChan,
 		}
+		// Pods that are not ready are checked first so problems surface early
+		if ValidatePodStatus(pod) != nil {
+			job.Priority = PriorityHigh
+		}
 
 		if err := wp.SubmitJob(job); err != nil {
-			// If we can't submit job, return error wrapped with context
-			return fmt.Errorf("failed to submit health check job for pod %s: %w", pod.Name, err)
+			results[i] = HealthCheckResult{
+				PodName: pod.Name,
+				Status:  "SUBMIT_FAILED",
+				Error:   fmt.Errorf("failed to submit health check job for pod %s: %w", pod.Name, err),
+				Details: err.Error(),
+			}
+			done[i] = true
+			completedCount++
+			failedCount++
 		}
 	}
 
-	// Collect results with timeout
-	timeout := time.After(60 * time.Second) // Overall operation timeout
-	completedCount := 0
+	// Without a caller deadline (e.g. --timeout) the collection is bounded by a default that
+	// grows with the number of pods each worker has to check
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, collectTimeout(len(pods), config))
+		defer cancel()
+	}
 
 	for completedCount < len(pods) {
+		if options.MaxFailures > 0 && failedCount >= options.MaxFailures {
+			wp.cancel()
+			markUnfinished(pods, results, done, StatusSkipped, fmt.Sprintf("skipped after %d failed checks", failedCount))
+			return results, NewHealthCheckError("concurrent_health_check", fmt.Sprintf("%d pods", len(pods)),
+				fmt.Errorf("stopped after %d failed checks (max failures %d), %d/%d checks completed", failedCount, options.MaxFailures, completedCount, len(pods)))
+		}
+
 		select {
-		case result := <-resultsChan:
-			// Find the correct index for this result
-			for i, pod := range pods {
-				if pod.Name == result.PodName {
-					results[i] = result
-					break
-				}
-			}
+		case indexed := <-resultsChan:
+			results[indexed.Index] = indexed.Result
+			done[indexed.Index] = true
 			completedCount++
-		case <-timeout:
-			return NewHealthCheckError("concurrent_health_check", fmt.Sprintf("%d pods", len(pods)),
-				fmt.Errorf("operation timed out after 60 seconds, completed %d/%d checks", completedCount, len(pods)))
+			if indexed.Result.Error != nil {
+				failedCount++
+			}
 		case <-ctx.Done():
-			return NewHealthCheckError("concurrent_health_check", fmt.Sprintf("%d pods", len(pods)), ctx.Err())
+			wp.cancel()
+			markUnfinished(pods, results, done, StatusTimedOut, "health check did not finish in time")
+			return results, NewHealthCheckError("concurrent_health_check", fmt.Sprintf("%d pods", len(pods)),
+				fmt.Errorf("completed %d/%d checks: %w", completedCount, len(pods), ctx.Err()))
 		}
 	}
 
-	// Display results in tabular format
-	return k.displayHealthCheckResults(results, options)
+	return results, nil
+}
+
+// collectTimeout bounds a collection without caller deadline: every worker gets the request
+// timeout for each pod it has to check, but never less than defaultCollectTimeout
+func collectTimeout(pods int, config WorkerPoolConfig) time.Duration {
+	workers := max(config.MaxWorkers, 1)
+	rounds := (pods + workers - 1) / workers
+	return max(defaultCollectTimeout, time.Duration(rounds)*config.RequestTimeout)
+}
+
+// markUnfinished reports every pod without a result with the given status
+func markUnfinished(pods []*v1.Pod, results []HealthCheckResult, done []bool, status, details string) {
+	for i, pod := range pods {
+		if done[i] {
+			continue
+		}
+		results[i] = HealthCheckResult{
+			PodName: pod.Name,
+			Status:  status,
+			Error:   NewHealthCheckError("health_check", pod.Name, fmt.Errorf("%s", details)),
+			Details: details,
+		}
+	}
 }
 
 // performSinglePodHealthCheckWithContext performs a health check on a single pod with better context handling
@@ -249,6 +512,7 @@ func (k *K8sClient) performSinglePodHealthCheckWithContext(ctx context.Context,
 		result.Status = "POD_NOT_READY"
 		result.Error = err
 		result.Details = err.Error()
+		k.attachPodDiagnostics(ctx, pod, &result)
 		return result
 	}
 
@@ -258,13 +522,16 @@ func (k *K8sClient) performSinglePodHealthCheckWithContext(ctx context.Context,
 	if portOverride > 0 {
 		healthPort = portOverride
 	} else {
-		healthPort, err = k.DiscoverHealthPort(pod)
+		var endpoint *HealthEndpoint
+		endpoint, err = k.DiscoverHealthEndpoint(ctx, pod)
 		if err != nil {
 			result.Status = "PORT_DISCOVERY_FAILED"
 			result.Error = NewKubernetesError("discover_health_port", pod.Name, err)
 			result.Details = err.Error()
 			return result
 		}
+		healthPort = endpoint.Port
+		options = endpoint.Apply(options)
 	}
 	result.HealthPort = healthPort
 
@@ -282,13 +549,15 @@ func (k *K8sClient) performSinglePodHealthCheckWithContext(ctx context.Context,
 	startTime := time.Now()
 	pf := NewPortForwarder(k.config, k.restClient)
 
-	parsedHealth, rawJSON, err := pf.PerformHealthCheckWithOptions(ctx, pod, healthPort, localPort, options)
+	parsedHealth, rawJSON, latencies, err := pf.PerformSampledHealthCheck(ctx, pod, healthPort, localPort, options)
 	result.ResponseTime = time.Since(startTime)
+	result.Latency = NewLatencyStats(latencies)
 
 	if err != nil {
 		result.Status = "HEALTH_CHECK_FAILED"
 		result.Error = NewHealthCheckError("perform_health_check", pod.Name, err)
 		result.Details = err.Error()
+		k.attachPodDiagnostics(ctx, pod, &result)
 		return result
 	}
 
@@ -298,10 +567,10 @@ func (k *K8sClient) performSinglePodHealthCheckWithContext(ctx context.Context,
 
 	// Set status based on parsed health data with improved logic
 	if parsedHealth != nil {
-		if health.IsHealthy(parsedHealth.OverallStatus) {
+		if overall := options.Policy.OverallStatus(parsedHealth); health.IsHealthy(overall) {
 			result.Status = "HEALTHY"
 		} else {
-			result.Status = string(parsedHealth.OverallStatus)
+			result.Status = string(overall)
 		}
 		result.Details = health.GetHealthSummaryWithColor(parsedHealth, options.UseColors)
 	} else {
@@ -325,6 +594,7 @@ func (k *K8sClient) performSinglePodHealthCheck(ctx context.Context, pod *v1.Pod
 		result.Status = "POD_NOT_READY"
 		result.Error = err
 		result.Details = err.Error()
+		k.attachPodDiagnostics(ctx, pod, &result)
 		return result
 	}
 
@@ -334,13 +604,16 @@ func (k *K8sClient) performSinglePodHealthCheck(ctx context.Context, pod *v1.Pod
 	if portOverride > 0 {
 		healthPort = portOverride
 	} else {
-		healthPort, err = k.DiscoverHealthPort(pod)
+		var endpoint *HealthEndpoint
+		endpoint, err = k.DiscoverHealthEndpoint(ctx, pod)
 		if err != nil {
 			result.Status = "PORT_DISCOVERY_FAILED"
 			result.Error = err
 			result.Details = err.Error()
 			return result
 		}
+		healthPort = endpoint.Port
+		options = endpoint.Apply(options)
 	}
 	result.HealthPort = healthPort
 
@@ -369,6 +642,7 @@ func (k *K8sClient) performSinglePodHealthCheck(ctx context.Context, pod *v1.Pod
 		result.Status = "HEALTH_CHECK_FAILED"
 		result.Error = err
 		result.Details = err.Error()
+		k.attachPodDiagnostics(ctx, pod, &result)
 		return result
 	}
 
@@ -377,11 +651,11 @@ func (k *K8sClient) performSinglePodHealthCheck(ctx context.Context, pod *v1.Pod
 	result.RawJSON = rawJSON
 
 	// Set status based on parsed health data
-	if parsedHealth != nil && health.IsHealthy(parsedHealth.OverallStatus) {
+	if parsedHealth != nil && health.IsHealthy(options.Policy.OverallStatus(parsedHealth)) {
 		result.Status = "HEALTHY"
 		result.Details = health.GetHealthSummaryWithColor(parsedHealth, options.UseColors)
 	} else if parsedHealth != nil {
-		result.Status = string(parsedHealth.OverallStatus)
+		result.Status = string(options.Policy.OverallStatus(parsedHealth))
 		result.Details = health.GetHealthSummaryWithColor(parsedHealth, options.UseColors)
 	} else {
 		// Raw output mode
@@ -392,8 +666,8 @@ func (k *K8sClient) performSinglePodHealthCheck(ctx context.Context, pod *v1.Pod
 	return result
 }
 
-// displayHealthCheckResults displays the results in a formatted table
-func (k *K8sClient) displayHealthCheckResults(results []HealthCheckResult, options health.HealthCheckOptions) error {
+// DisplayHealthCheckResults displays the results in the format selected by the options
+func (k *K8sClient) DisplayHealthCheckResults(results []HealthCheckResult, options health.HealthCheckOptions) error {
 	// Handle JSON output mode
 	if options.OutputJSON {
 		return k.displayJSONResults(results)
@@ -435,6 +709,14 @@ func (k *K8sClient) displayJSONResults(results []HealthCheckResult) error {
 					jsonResult["details"] = details
 				}
 			}
+			if result.Latency.Samples > 1 {
+				jsonResult["latency"] = map[string]interface{}{
+					"samples": result.Latency.Samples,
+					"p50Ms":   durationMilliseconds(result.Latency.P50),
+					"p95Ms":   durationMilliseconds(result.Latency.P95),
+					"maxMs":   durationMilliseconds(result.Latency.Max),
+				}
+			}
 
 			jsonResults = append(jsonResults, jsonResult)
 		}
@@ -469,6 +751,9 @@ func (k *K8sClient) displayDetailedResults(results []HealthCheckResult, options
 
 		if result.ParsedHealth != nil {
 			fmt.Printf("Overall Health: %s\n", health.FormatHealthStatusWithColor(result.ParsedHealth.OverallStatus, options.UseColors))
+			if overall := options.Policy.OverallStatus(result.ParsedHealth); overall != result.ParsedHealth.OverallStatus {
+				fmt.Printf("Overall Health (policy): %s\n", health.FormatHealthStatusWithColor(overall, options.UseColors))
+			}
 			if len(result.ParsedHealth.ComponentDetails) > 0 {
 				fmt.Println("Components:")
 				for _, comp := range result.ParsedHealth.ComponentDetails {
@@ -476,6 +761,9 @@ func (k *K8sClient) displayDetailedResults(results []HealthCheckResult, options
 					if comp.Details != "" {
 						fmt.Printf(" (%s)", comp.Details)
 					}
+					if label := options.Policy.Label(comp.Name); label != "" {
+						fmt.Printf(" [%s]", label)
+					}
 
 					// Special handling for extensions - show individual extensions
 					if comp.Name == "extensions" && len(comp.SubComponents) > 0 {
@@ -486,21 +774,79 @@ func (k *K8sClient) displayDetailedResults(results []HealthCheckResult, options
 							if ext.Details != "" {
 								fmt.Printf(" (%s)", ext.Details)
 							}
+							if label := options.Policy.Label(comp.Name + "." + ext.Name); label != "" {
+								fmt.Printf(" [%s]", label)
+							}
 							fmt.Println()
 						}
 					} else {
 						fmt.Println()
+						displayNestedComponents(comp.SubComponents, comp.Name, 2, options)
 					}
 				}
 			}
+			if len(result.ParsedHealth.Unrecognized) > 0 {
+				fmt.Printf("Warning: unrecognized statuses counted as unhealthy: %s\n", strings.Join(result.ParsedHealth.Unrecognized, ", "))
+			}
 		} else if result.Error != nil {
 			fmt.Printf("Error: %v\n", result.Error)
 		}
+		if result.Diagnostics != nil {
+			displayPodDiagnostics(result.Diagnostics)
+		}
 		fmt.Println()
 	}
 	return nil
 }
 
+// displayNestedComponents prints the component trees newer broker versions nest below components
+func displayNestedComponents(components []health.ComponentStatus, parent string, depth int, options health.HealthCheckOptions) {
+	indent := strings.Repeat("  ", depth)
+	for _, comp := range components {
+		fmt.Printf("%s- %s: %s", indent, comp.Name, health.FormatHealthStatusWithColor(comp.Status, options.UseColors))
+		if comp.Details != "" {
+			fmt.Printf(" (%s)", comp.Details)
+		}
+		if label := options.Policy.Label(parent + "." + comp.Name); label != "" {
+			fmt.Printf(" [%s]", label)
+		}
+		fmt.Println()
+		displayNestedComponents(comp.SubComponents, parent+"."+comp.Name, depth+1, options)
+	}
+}
+
+// displayPodDiagnostics prints container states and recent events of a failed pod
+func displayPodDiagnostics(diagnostics *PodDiagnostics) {
+	if len(diagnostics.Containers) > 0 {
+		fmt.Println("Containers:")
+		for _, container := range diagnostics.Containers {
+			fmt.Printf("  - %s: %s", container.Name, container.State)
+			if container.Reason != "" {
+				fmt.Printf(" (%s)", container.Reason)
+			}
+			fmt.Printf(", ready=%t, restarts=%d", container.Ready, container.RestartCount)
+			if container.LastTerminationReason != "" {
+				fmt.Printf(", last termination: %s (exit code %d)", container.LastTerminationReason, container.LastExitCode)
+			}
+			fmt.Println()
+		}
+	}
+	if len(diagnostics.Events) > 0 {
+		fmt.Println("Recent Events:")
+		for _, event := range diagnostics.Events {
+			age := "-"
+			if !event.LastSeen.IsZero() {
+				age = time.Since(event.LastSeen).Round(time.Second).String() + " ago"
+			}
+			fmt.Printf("  - %s %s (%s): %s", event.Type, event.Reason, age, event.Message)
+			if event.Count > 1 {
+				fmt.Printf(" (x%d)", event.Count)
+			}
+			fmt.Println()
+		}
+	}
+}
+
 // displayTabularResults shows results in tabular format
 func (k *K8sClient) displayTabularResults(results []HealthCheckResult, options health.HealthCheckOptions) error {
 	// Create tabwriter for formatted output
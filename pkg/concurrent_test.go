@@ -0,0 +1,203 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+
+	"kubectl-broker/pkg/health"
+)
+
+func testPoolConfig(minWorkers, maxWorkers int) WorkerPoolConfig {
+	return WorkerPoolConfig{
+		MinWorkers:      minWorkers,
+		MaxWorkers:      maxWorkers,
+		QueueSize:       10,
+		RequestTimeout:  time.Second,
+		ShutdownTimeout: time.Second,
+	}
+}
+
+func TestWorkerPoolRunsHigherPriorityFirst(t *testing.T) {
+	t.Parallel()
+
+	wp := NewWorkerPool(nil, testPoolConfig(1, 1))
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string, err error) TaskFunc {
+		return func(context.Context) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return err
+		}
+	}
+
+	// Jobs are queued before Start so the single worker sees all of them at once
+	for _, submit := range []error{
+		wp.SubmitTask(record("normal-1", nil)),
+		wp.SubmitTask(record("normal-2", errors.New("boom"))),
+		wp.SubmitTaskWithPriority(record("high", nil), PriorityHigh),
+	} {
+		if submit != nil {
+			t.Fatalf("submit failed: %v", submit)
+		}
+	}
+
+	wp.Start()
+	if err := wp.Stop(); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+
+	want := []string{"high", "normal-1", "normal-2"}
+	if len(order) != len(want) {
+		t.Fatalf("expected %d jobs to run, got %v", len(want), order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("expected order %v, got %v", want, order)
+		}
+	}
+
+	stats := wp.Stats()
+	if stats.Completed != 2 || stats.Failed != 1 || stats.Queued != 0 || stats.InFlight != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestWorkerPoolScalesWithQueueDepth(t *testing.T) {
+	t.Parallel()
+
+	wp := NewWorkerPool(nil, testPoolConfig(1, 3))
+	wp.Start()
+
+	release := make(chan struct{})
+	var started sync.WaitGroup
+	started.Add(3)
+	for i := 0; i < 4; i++ {
+		i := i
+		err := wp.SubmitTask(func(context.Context) error {
+			if i < 3 {
+				started.Done()
+			}
+			<-release
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	started.Wait()
+
+	stats := wp.Stats()
+	if stats.Workers != 3 || stats.InFlight != 3 || stats.Queued != 1 {
+		t.Fatalf("expected pool to scale to max workers, got %+v", stats)
+	}
+
+	close(release)
+	if err := wp.Stop(); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+
+	stats = wp.Stats()
+	if stats.Workers != 0 || stats.Completed != 4 {
+		t.Fatalf("unexpected stats after stop: %+v", stats)
+	}
+}
+
+// notReadyPods returns pending pods whose health checks fail without a port-forward
+func notReadyPods(n int) []*v1.Pod {
+	pods := make([]*v1.Pod, n)
+	for i := range pods {
+		pods[i] = &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("broker-%d", i), Namespace: "hivemq"},
+			Status:     v1.PodStatus{Phase: v1.PodPending},
+		}
+	}
+	return pods
+}
+
+// eventsClient returns a client whose event lists are empty, as used by the pod diagnosis
+func eventsClient(t *testing.T) *K8sClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"kind":"EventList","apiVersion":"v1","items":[]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	coreClient, err := corev1client.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return &K8sClient{coreClient: coreClient}
+}
+
+func TestCollectConcurrentHealthChecksKeepsPodOrder(t *testing.T) {
+	t.Parallel()
+
+	pods := notReadyPods(12)
+	results, err := eventsClient(t).CollectConcurrentHealthChecks(context.Background(), pods, 0, health.HealthCheckOptions{Endpoint: "health"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, result := range results {
+		if result.PodName != pods[i].Name || result.Status != "POD_NOT_READY" {
+			t.Fatalf("result %d: expected %s POD_NOT_READY, got %s %s", i, pods[i].Name, result.PodName, result.Status)
+		}
+	}
+}
+
+func TestCollectConcurrentHealthChecksStopsAtMaxFailures(t *testing.T) {
+	t.Parallel()
+
+	pods := notReadyPods(40)
+	results, err := eventsClient(t).CollectConcurrentHealthChecks(context.Background(), pods, 0, health.HealthCheckOptions{Endpoint: "health", MaxFailures: 1})
+	if err == nil {
+		t.Fatal("expected an error once the failure limit is reached")
+	}
+	if len(results) != len(pods) {
+		t.Fatalf("expected a result for every pod, got %d", len(results))
+	}
+	skipped := 0
+	for i, result := range results {
+		if result.PodName != pods[i].Name {
+			t.Fatalf("result %d belongs to %s, expected %s", i, result.PodName, pods[i].Name)
+		}
+		if result.Status == StatusSkipped {
+			skipped++
+		}
+	}
+	if skipped == 0 {
+		t.Fatalf("expected skipped pods after the first failure, got %+v", results)
+	}
+}
+
+func TestCollectTimeoutGrowsWithPodCount(t *testing.T) {
+	t.Parallel()
+
+	config := WorkerPoolConfig{MaxWorkers: 10, RequestTimeout: 30 * time.Second}
+	for _, tc := range []struct {
+		pods int
+		want time.Duration
+	}{
+		{pods: 3, want: defaultCollectTimeout},
+		{pods: 20, want: defaultCollectTimeout},
+		{pods: 25, want: 90 * time.Second},
+	} {
+		if got := collectTimeout(tc.pods, config); got != tc.want {
+			t.Errorf("collectTimeout(%d) = %v, want %v", tc.pods, got, tc.want)
+		}
+	}
+}
@@ -2,29 +2,40 @@ package pkg
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"slices"
 	"strings"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
 )
 
-// DiscoverBrokers finds potential HiveMQ broker pods across all accessible namespaces
-func (k *K8sClient) DiscoverBrokers(ctx context.Context) error {
-	fmt.Println("Discovering broker pods across all accessible namespaces...")
+// BrokerNamespace is a namespace in which DiscoverBrokers found broker pods or StatefulSets
+type BrokerNamespace struct {
+	Namespace    string
+	StatefulSets []string
+	Pods         []string
+	// HealthPort and APIPort are read from the first broker pod; zero when it declares none
+	HealthPort int32
+	APIPort    int32
+}
 
-	// Get all namespaces
+// DiscoverBrokers finds potential HiveMQ broker pods across all accessible namespaces. When no
+// pod looks like a broker, StatefulSets with "broker" in their name are reported instead.
+func (k *K8sClient) DiscoverBrokers(ctx context.Context) ([]BrokerNamespace, error) {
 	namespaces, err := k.coreClient.Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to list namespaces: %w", err)
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
 	}
+	slog.Debug("Checking namespaces for broker pods", "namespaces", len(namespaces.Items))
 
-	fmt.Printf("Checking %d namespaces...\n\n", len(namespaces.Items))
-
-	foundBrokers := false
-
+	var found []BrokerNamespace
 	for _, ns := range namespaces.Items {
 		// Skip system namespaces
-		if strings.HasPrefix(ns.Name, "kube-") || strings.HasPrefix(ns.Name, "kubernetes-") {
+		if isSystemNamespace(ns.Name) {
 			continue
 		}
 
@@ -34,63 +45,55 @@ func (k *K8sClient) DiscoverBrokers(ctx context.Context) error {
 			// Skip namespaces we can't access
 			continue
 		}
-
-		brokerPods := []string{}
-		for _, pod := range pods.Items {
-			if isBrokerPod(pod.Name, pod.Labels) {
-				brokerPods = append(brokerPods, pod.Name)
-			}
+		if namespace, ok := brokerNamespace(ns.Name, pods.Items); ok {
+			found = append(found, namespace)
 		}
+	}
+	if len(found) > 0 {
+		return found, nil
+	}
 
-		if len(brokerPods) > 0 {
-			foundBrokers = true
-			fmt.Printf("Namespace: %s\n", ns.Name)
-			for _, podName := range brokerPods {
-				fmt.Printf("  - %s\n", podName)
-			}
-			fmt.Printf("  Single pod: kubectl broker --pod %s --namespace %s\n", brokerPods[0], ns.Name)
-			if len(brokerPods) > 1 {
-				fmt.Printf("  All pods:   kubectl broker --statefulset broker --namespace %s\n", ns.Name)
+	for _, ns := range namespaces.Items {
+		if isSystemNamespace(ns.Name) {
+			continue
+		}
+		statefulSets, err := k.appsClient.StatefulSets(ns.Name).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			continue
+		}
+		namespace := BrokerNamespace{Namespace: ns.Name}
+		for _, sts := range statefulSets.Items {
+			if strings.Contains(strings.ToLower(sts.Name), "broker") {
+				namespace.StatefulSets = append(namespace.StatefulSets, sts.Name)
 			}
-			fmt.Println()
+		}
+		if len(namespace.StatefulSets) > 0 {
+			found = append(found, namespace)
 		}
 	}
+	return found, nil
+}
 
-	if !foundBrokers {
-		fmt.Println("No broker pods found. Looking for StatefulSets named 'broker'...")
-
-		for _, ns := range namespaces.Items {
-			if strings.HasPrefix(ns.Name, "kube-") || strings.HasPrefix(ns.Name, "kubernetes-") {
-				continue
-			}
-
-			statefulSets, err := k.appsClient.StatefulSets(ns.Name).List(ctx, metav1.ListOptions{})
-			if err != nil {
-				continue
-			}
-
-			for _, sts := range statefulSets.Items {
-				if strings.Contains(strings.ToLower(sts.Name), "broker") {
-					foundBrokers = true
-					fmt.Printf("StatefulSet: %s (namespace: %s)\n", sts.Name, ns.Name)
-					if sts.Status.Replicas > 0 {
-						fmt.Printf("  Example pod: %s-0\n", sts.Name)
-						fmt.Printf("  Single pod: kubectl broker --pod %s-0 --namespace %s\n", sts.Name, ns.Name)
-						fmt.Printf("  All pods:   kubectl broker --statefulset %s --namespace %s\n\n", sts.Name, ns.Name)
-					}
-				}
+// brokerNamespace collects the broker pods of a namespace and the StatefulSets owning them
+func brokerNamespace(name string, pods []v1.Pod) (BrokerNamespace, bool) {
+	namespace := BrokerNamespace{Namespace: name}
+	for i := range pods {
+		pod := &pods[i]
+		if !isBrokerPod(pod.Name, pod.Labels) {
+			continue
+		}
+		if len(namespace.Pods) == 0 {
+			namespace.HealthPort, _ = ProfileFor(ProductBroker).HealthPortFor(pod)
+			namespace.APIPort, _ = ProfileFor(ProductBroker).APIPortFor(pod)
+		}
+		namespace.Pods = append(namespace.Pods, pod.Name)
+		for _, owner := range pod.OwnerReferences {
+			if owner.Kind == "StatefulSet" && !slices.Contains(namespace.StatefulSets, owner.Name) {
+				namespace.StatefulSets = append(namespace.StatefulSets, owner.Name)
 			}
 		}
 	}
-
-	if !foundBrokers {
-		fmt.Println("No broker resources found. You may need to:")
-		fmt.Println("1. Check if you're connected to the right cluster")
-		fmt.Println("2. Verify your kubeconfig has access to the namespaces containing brokers")
-		fmt.Println("3. Use specific pod and namespace names if brokers don't follow naming conventions")
-	}
-
-	return nil
+	return namespace, len(namespace.Pods) > 0
 }
 
 // isBrokerPod checks if a pod looks like a HiveMQ broker based on name and labels
@@ -114,3 +117,165 @@ func isBrokerPod(name string, labels map[string]string) bool {
 
 	return false
 }
+
+// Installation describes a HiveMQ broker installation found during discovery
+type Installation struct {
+	Namespace     string `json:"namespace"`
+	StatefulSet   string `json:"statefulSet"`
+	Cloud         bool   `json:"cloud"`
+	ManagedBy     string `json:"managedBy,omitempty"`
+	Image         string `json:"image,omitempty"`
+	Version       string `json:"version,omitempty"`
+	Replicas      int32  `json:"replicas"`
+	ReadyReplicas int32  `json:"readyReplicas"`
+	Health        string `json:"health"`
+}
+
+// Installation health derived from StatefulSet readiness (or live checks when requested)
+const (
+	InstallationHealthy  = "HEALTHY"
+	InstallationDegraded = "DEGRADED"
+	InstallationDown     = "DOWN"
+	InstallationScaled0  = "SCALED_DOWN"
+)
+
+// hiveMQPlatformKind is the kind of the HiveMQ Platform Operator custom resource
+const hiveMQPlatformKind = "HiveMQPlatform"
+
+// IsCloudNamespace reports whether a namespace follows the UUID pattern used by HiveMQ Cloud,
+// e.g. 07379b05-4e05-46bf-b5d3-b4441252a8d1
+func IsCloudNamespace(namespace string) bool {
+	return len(namespace) == 36 && namespace[8] == '-' && namespace[13] == '-' &&
+		namespace[18] == '-' && namespace[23] == '-'
+}
+
+// DiscoverInstallations scans all accessible namespaces for HiveMQ StatefulSets, including
+// those owned by a HiveMQPlatform operator resource. Namespaces that cannot be read are skipped.
+func (k *K8sClient) DiscoverInstallations(ctx context.Context) ([]Installation, error) {
+	namespaces, err := k.coreClient.Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, NewKubernetesError("list_namespaces", "", err)
+	}
+
+	var installations []Installation
+	for _, ns := range namespaces.Items {
+		if isSystemNamespace(ns.Name) {
+			continue
+		}
+		found, err := k.DiscoverNamespaceInstallations(ctx, ns.Name)
+		if err != nil {
+			continue
+		}
+		installations = append(installations, found...)
+	}
+
+	return installations, nil
+}
+
+// DiscoverNamespaceInstallations lists the HiveMQ StatefulSets of a single namespace
+func (k *K8sClient) DiscoverNamespaceInstallations(ctx context.Context, namespace string) ([]Installation, error) {
+	statefulSets, err := k.appsClient.StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, NewKubernetesError("list_statefulsets", namespace, err)
+	}
+
+	var installations []Installation
+	for _, sts := range statefulSets.Items {
+		managedBy := ""
+		for _, owner := range sts.OwnerReferences {
+			if owner.Kind == hiveMQPlatformKind {
+				managedBy = fmt.Sprintf("%s/%s", owner.Kind, owner.Name)
+			}
+		}
+
+		image := hiveMQImage(sts.Spec.Template.Spec.Containers)
+		if managedBy == "" && image == "" && !isBrokerPod(sts.Name, sts.Spec.Template.Labels) {
+			continue
+		}
+
+		replicas := int32(1)
+		if sts.Spec.Replicas != nil {
+			replicas = *sts.Spec.Replicas
+		}
+
+		installations = append(installations, Installation{
+			Namespace:     namespace,
+			StatefulSet:   sts.Name,
+			Cloud:         IsCloudNamespace(namespace),
+			ManagedBy:     managedBy,
+			Image:         image,
+			Version:       imageTag(image),
+			Replicas:      replicas,
+			ReadyReplicas: sts.Status.ReadyReplicas,
+			Health:        readinessHealth(replicas, sts.Status.ReadyReplicas),
+		})
+	}
+
+	return installations, nil
+}
+
+// GetBrokerVersion returns the HiveMQ version of a StatefulSet as given by its broker image tag,
+// or an empty string when no HiveMQ image is configured
+func (k *K8sClient) GetBrokerVersion(ctx context.Context, namespace, statefulSetName string) (string, error) {
+	sts, err := k.GetStatefulSet(ctx, namespace, statefulSetName)
+	if err != nil {
+		return "", err
+	}
+	return imageTag(hiveMQImage(sts.Spec.Template.Spec.Containers)), nil
+}
+
+// ServerVersion returns the Kubernetes API server version, e.g. v1.31.2
+func (k *K8sClient) ServerVersion(ctx context.Context) (string, error) {
+	body, err := k.coreClient.RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+	if err != nil {
+		return "", NewKubernetesError("get_server_version", "", err)
+	}
+	var info version.Info
+	if err := json.Unmarshal(body, &info); err != nil {
+		return "", fmt.Errorf("failed to decode server version: %w", err)
+	}
+	return info.GitVersion, nil
+}
+
+func isSystemNamespace(name string) bool {
+	return strings.HasPrefix(name, "kube-") || strings.HasPrefix(name, "kubernetes-")
+}
+
+// hiveMQImage returns the first container image that looks like a HiveMQ broker image
+func hiveMQImage(containers []v1.Container) string {
+	for _, container := range containers {
+		if strings.Contains(strings.ToLower(container.Image), "hivemq") {
+			return container.Image
+		}
+	}
+	return ""
+}
+
+// imageTag extracts the tag from an image reference, ignoring registry ports and digests
+func imageTag(image string) string {
+	if image == "" {
+		return ""
+	}
+	ref := image
+	if at := strings.Index(ref, "@"); at >= 0 {
+		ref = ref[:at]
+	}
+	lastSlash := strings.LastIndex(ref, "/")
+	if colon := strings.LastIndex(ref, ":"); colon > lastSlash {
+		return ref[colon+1:]
+	}
+	return "latest"
+}
+
+func readinessHealth(replicas, ready int32) string {
+	switch {
+	case replicas == 0:
+		return InstallationScaled0
+	case ready >= replicas:
+		return InstallationHealthy
+	case ready == 0:
+		return InstallationDown
+	default:
+		return InstallationDegraded
+	}
+}
@@ -0,0 +1,70 @@
+package pkg
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestImageTag(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"hivemq/hivemq4:4.28.0":                     "4.28.0",
+		"registry.local:5000/hivemq/hivemq4":        "latest",
+		"registry.local:5000/hivemq/hivemq4:4.9.1":  "4.9.1",
+		"hivemq/hivemq4:4.28.0@sha256:abcdef012345": "4.28.0",
+		"": "",
+	}
+
+	for image, want := range cases {
+		if got := imageTag(image); got != want {
+			t.Errorf("imageTag(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
+
+func TestIsCloudNamespace(t *testing.T) {
+	t.Parallel()
+
+	if !IsCloudNamespace("07379b05-4e05-46bf-b5d3-b4441252a8d1") {
+		t.Error("expected UUID namespace to be detected as HiveMQ Cloud")
+	}
+	if IsCloudNamespace("hivemq-production") {
+		t.Error("expected regular namespace not to be detected as HiveMQ Cloud")
+	}
+}
+
+func TestBrokerNamespaceCollectsPodsStatefulSetsAndPorts(t *testing.T) {
+	t.Parallel()
+
+	pod := func(name, owner string, ports ...v1.ContainerPort) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: owner}}},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "hivemq", Ports: ports}}},
+		}
+	}
+	health := v1.ContainerPort{Name: "health", ContainerPort: 9090}
+	api := v1.ContainerPort{Name: "api", ContainerPort: 8081}
+
+	got, ok := brokerNamespace("tenants", []v1.Pod{
+		pod("hivemq-a-0", "hivemq-a", health, api),
+		pod("postgres-0", "postgres"),
+		pod("hivemq-a-1", "hivemq-a", health, api),
+		pod("hivemq-b-0", "hivemq-b", health, api),
+	})
+	if !ok {
+		t.Fatal("brokerNamespace() found no brokers")
+	}
+	if len(got.Pods) != 3 || len(got.StatefulSets) != 2 || got.StatefulSets[0] != "hivemq-a" || got.StatefulSets[1] != "hivemq-b" {
+		t.Errorf("brokerNamespace() = %+v, want 3 pods of hivemq-a and hivemq-b", got)
+	}
+	if got.HealthPort != 9090 || got.APIPort != 8081 {
+		t.Errorf("ports = %d/%d, want 9090/8081", got.HealthPort, got.APIPort)
+	}
+
+	if _, ok := brokerNamespace("databases", []v1.Pod{pod("postgres-0", "postgres")}); ok {
+		t.Error("brokerNamespace() reported a namespace without broker pods")
+	}
+}
@@ -0,0 +1,146 @@
+package discoverycache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"kubectl-broker/pkg/statedir"
+)
+
+// DirEnvVar overrides the default cache directory
+const DirEnvVar = "KUBECTL_BROKER_CACHE_DIR"
+
+// DefaultTTL is how long discovery results are reused before the cluster is scanned again
+const DefaultTTL = time.Hour
+
+const cacheFileName = "discovery.json"
+
+// Namespace is a namespace in which discovery found broker pods or StatefulSets
+type Namespace struct {
+	Name         string   `json:"name"`
+	StatefulSets []string `json:"statefulSets,omitempty"`
+	Pods         []string `json:"pods,omitempty"`
+	HealthPort   int32    `json:"healthPort,omitempty"`
+	APIPort      int32    `json:"apiPort,omitempty"`
+}
+
+// Entry holds the discovery results of one cluster
+type Entry struct {
+	Server       string      `json:"server"`
+	DiscoveredAt time.Time   `json:"discoveredAt"`
+	Namespaces   []Namespace `json:"namespaces"`
+}
+
+// Fresh reports whether the entry is younger than ttl at now
+func (e *Entry) Fresh(now time.Time, ttl time.Duration) bool {
+	return ttl > 0 && now.Sub(e.DiscoveredAt) < ttl
+}
+
+// Namespace returns the cached results of a namespace
+func (e *Entry) Namespace(name string) (Namespace, bool) {
+	for _, namespace := range e.Namespaces {
+		if namespace.Name == name {
+			return namespace, true
+		}
+	}
+	return Namespace{}, false
+}
+
+// NamespaceNames returns the names of the cached namespaces
+func (e *Entry) NamespaceNames() []string {
+	names := make([]string, 0, len(e.Namespaces))
+	for _, namespace := range e.Namespaces {
+		names = append(names, namespace.Name)
+	}
+	return names
+}
+
+// Store persists discovery results keyed by the API server URL in a single JSON file
+type Store struct {
+	dir string
+}
+
+// DefaultDir returns the cache directory, honoring KUBECTL_BROKER_CACHE_DIR
+func DefaultDir() (string, error) {
+	return statedir.Dir(DirEnvVar, "cache", "the discovery cache")
+}
+
+// NewStore creates a store rooted at dir (DefaultDir when empty)
+func NewStore(dir string) (*Store, error) {
+	if dir == "" {
+		var err error
+		dir, err = DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Path returns the file backing the store
+func (s *Store) Path() string {
+	return filepath.Join(s.dir, cacheFileName)
+}
+
+// Load returns the entry of server when it is younger than ttl. A missing, expired or
+// unreadable cache is a miss, never an error, so callers simply discover again.
+func (s *Store) Load(server string, ttl time.Duration) (*Entry, bool) {
+	entries, err := s.read()
+	if err != nil {
+		return nil, false
+	}
+	for i := range entries {
+		if entries[i].Server == server && entries[i].Fresh(time.Now(), ttl) {
+			return &entries[i], true
+		}
+	}
+	return nil, false
+}
+
+// Save replaces the entry of entry.Server, keeping the entries of other clusters
+func (s *Store) Save(entry Entry) error {
+	entries, err := s.read()
+	if err != nil {
+		// A corrupt cache is rewritten rather than blocking discovery
+		entries = nil
+	}
+	entries = slices.DeleteFunc(entries, func(existing Entry) bool { return existing.Server == entry.Server })
+	entries = append(entries, entry)
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory %s: %w", s.dir, err)
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode discovery cache: %w", err)
+	}
+	// Write to a temporary file first so a concurrent reader never sees a partial cache
+	tmp := s.Path() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write discovery cache: %w", err)
+	}
+	if err := os.Rename(tmp, s.Path()); err != nil {
+		return fmt.Errorf("failed to write discovery cache: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) read() ([]Entry, error) {
+	data, err := os.ReadFile(s.Path())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read discovery cache: %w", err)
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode discovery cache: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,75 @@
+package discoverycache
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestStoreKeepsEntriesPerServer(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore returned error: %v", err)
+	}
+	if _, ok := store.Load("https://prod:6443", DefaultTTL); ok {
+		t.Fatal("Load() hit on an empty cache")
+	}
+
+	now := time.Now().UTC()
+	prod := Entry{Server: "https://prod:6443", DiscoveredAt: now, Namespaces: []Namespace{
+		{Name: "tenant-a", StatefulSets: []string{"hivemq"}, Pods: []string{"hivemq-0"}, HealthPort: 9090, APIPort: 8081},
+	}}
+	stale := Entry{Server: "https://staging:6443", DiscoveredAt: now.Add(-2 * DefaultTTL)}
+	for _, entry := range []Entry{prod, stale} {
+		if err := store.Save(entry); err != nil {
+			t.Fatalf("Save returned error: %v", err)
+		}
+	}
+
+	got, ok := store.Load(prod.Server, DefaultTTL)
+	if !ok {
+		t.Fatal("Load() missed a fresh entry")
+	}
+	namespace, ok := got.Namespace("tenant-a")
+	if !ok || namespace.StatefulSets[0] != "hivemq" || namespace.HealthPort != 9090 {
+		t.Errorf("Namespace(tenant-a) = %+v, %v", namespace, ok)
+	}
+	if _, ok := store.Load(stale.Server, DefaultTTL); ok {
+		t.Error("Load() returned an expired entry")
+	}
+	if _, ok := store.Load(prod.Server, 0); ok {
+		t.Error("Load() with a zero TTL returned an entry")
+	}
+
+	// Saving a cluster again replaces its entry
+	prod.Namespaces = nil
+	if err := store.Save(prod); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	if got, _ := store.Load(prod.Server, DefaultTTL); got == nil || len(got.Namespaces) != 0 {
+		t.Errorf("Load() after replacing = %+v, want no namespaces", got)
+	}
+}
+
+func TestStoreTreatsCorruptCacheAsMiss(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore returned error: %v", err)
+	}
+	if err := os.WriteFile(store.Path(), []byte(`[{"server":`), 0o644); err != nil {
+		t.Fatalf("write cache: %v", err)
+	}
+	if _, ok := store.Load("https://prod:6443", DefaultTTL); ok {
+		t.Error("Load() hit on a corrupt cache")
+	}
+	if err := store.Save(Entry{Server: "https://prod:6443", DiscoveredAt: time.Now()}); err != nil {
+		t.Fatalf("Save over a corrupt cache returned error: %v", err)
+	}
+	if _, ok := store.Load("https://prod:6443", DefaultTTL); !ok {
+		t.Error("Load() missed the entry saved over a corrupt cache")
+	}
+}
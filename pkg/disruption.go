@@ -0,0 +1,297 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+// DisruptionDomain is a node or zone whose failure takes down the broker pods it hosts
+type DisruptionDomain struct {
+	Name      string   `json:"name"`
+	Pods      []string `json:"pods"`
+	Remaining int32    `json:"remaining"` // ready brokers left when the domain fails
+}
+
+// DisruptionReport tells whether a node drain or zone outage keeps a broker cluster at quorum
+type DisruptionReport struct {
+	Namespace            string             `json:"namespace"`
+	StatefulSet          string             `json:"statefulSet"`
+	Replicas             int32              `json:"replicas"`
+	ReadyPods            int32              `json:"readyPods"`
+	Quorum               int32              `json:"quorum"`
+	PodDisruptionBudgets []string           `json:"podDisruptionBudgets"`
+	Nodes                []DisruptionDomain `json:"nodes"`
+	Zones                []DisruptionDomain `json:"zones"`
+	Checks               []CapacityCheck    `json:"checks"`
+	// SuggestedPDB is a PodDisruptionBudget manifest for StatefulSets that have none
+	SuggestedPDB string `json:"suggestedPdb,omitempty"`
+}
+
+// Verdict is CapacityNoGo as soon as one check failed
+func (r *DisruptionReport) Verdict() string {
+	for _, check := range r.Checks {
+		if check.Status == CapacityCheckFail {
+			return CapacityNoGo
+		}
+	}
+	return CapacityGo
+}
+
+func (r *DisruptionReport) add(name, status, details string) {
+	r.Checks = append(r.Checks, CapacityCheck{Name: name, Status: status, Details: details})
+}
+
+// CheckDisruption inspects the PodDisruptionBudgets, pod anti-affinity and node and zone
+// placement of the broker pods. Nodes that cannot be read count as having no zone.
+func (k *K8sClient) CheckDisruption(ctx context.Context, namespace, statefulSetName string) (*DisruptionReport, error) {
+	sts, err := k.GetStatefulSet(ctx, namespace, statefulSetName)
+	if err != nil {
+		return nil, NewKubernetesError("get_statefulset", statefulSetName, err)
+	}
+	pods, err := k.GetPodsFromStatefulSet(ctx, namespace, statefulSetName)
+	if err != nil {
+		return nil, err
+	}
+	budgets, err := k.policy.PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, NewKubernetesError("list_poddisruptionbudgets", namespace, err)
+	}
+
+	zones := make(map[string]string)
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		if _, seen := zones[pod.Spec.NodeName]; seen {
+			continue
+		}
+		zones[pod.Spec.NodeName] = ""
+		node, err := k.coreClient.Nodes().Get(ctx, pod.Spec.NodeName, metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+		zones[pod.Spec.NodeName] = node.Labels[v1.LabelTopologyZone]
+	}
+
+	return EvaluateDisruption(sts, pods, zones, budgets.Items), nil
+}
+
+// EvaluateDisruption checks a StatefulSet against node drains and zone outages. zones maps the
+// nodes of the pods to their topology.kubernetes.io/zone label.
+func EvaluateDisruption(sts *appsv1.StatefulSet, pods []*v1.Pod, zones map[string]string, budgets []policyv1.PodDisruptionBudget) *DisruptionReport {
+	report := &DisruptionReport{
+		Namespace:            sts.Namespace,
+		StatefulSet:          sts.Name,
+		Replicas:             1,
+		PodDisruptionBudgets: []string{},
+		Nodes:                []DisruptionDomain{},
+		Zones:                []DisruptionDomain{},
+	}
+	if sts.Spec.Replicas != nil {
+		report.Replicas = *sts.Spec.Replicas
+	}
+	report.Quorum = QuorumSize(report.Replicas)
+
+	podLabels := labels.Set(sts.Spec.Template.Labels)
+	var matching []policyv1.PodDisruptionBudget
+	for _, budget := range budgets {
+		selector, err := metav1.LabelSelectorAsSelector(budget.Spec.Selector)
+		if err != nil || selector.Empty() || !selector.Matches(podLabels) {
+			continue
+		}
+		matching = append(matching, budget)
+		report.PodDisruptionBudgets = append(report.PodDisruptionBudgets, budget.Name)
+	}
+	report.checkBudgets(sts, matching)
+	report.checkAntiAffinity(&sts.Spec.Template)
+
+	byNode := make(map[string][]*v1.Pod)
+	byZone := make(map[string][]*v1.Pod)
+	unlabeled := false
+	for _, pod := range pods {
+		if podReady(pod) {
+			report.ReadyPods++
+		}
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		byNode[pod.Spec.NodeName] = append(byNode[pod.Spec.NodeName], pod)
+		if zone := zones[pod.Spec.NodeName]; zone != "" {
+			byZone[zone] = append(byZone[zone], pod)
+		} else {
+			unlabeled = true
+		}
+	}
+	report.Nodes = report.domains(byNode)
+	report.Zones = report.domains(byZone)
+
+	report.checkNodeLoss()
+	report.checkZoneLoss(unlabeled)
+	return report
+}
+
+// checkBudgets verifies that exactly one PodDisruptionBudget limits voluntary evictions to
+// what the cluster survives
+func (r *DisruptionReport) checkBudgets(sts *appsv1.StatefulSet, budgets []policyv1.PodDisruptionBudget) {
+	const name = "PodDisruptionBudget"
+	switch len(budgets) {
+	case 0:
+		r.add(name, CapacityCheckWarn, "no PodDisruptionBudget selects the broker pods; a node drain may evict several brokers at once")
+		r.SuggestedPDB = SuggestedPodDisruptionBudget(sts)
+		return
+	case 1:
+	default:
+		names := make([]string, 0, len(budgets))
+		for _, budget := range budgets {
+			names = append(names, budget.Name)
+		}
+		r.add(name, CapacityCheckWarn, fmt.Sprintf("%s all select the broker pods; the eviction API refuses pods with more than one budget, so drains stall", strings.Join(names, ", ")))
+		return
+	}
+
+	budget := budgets[0]
+	unavailable := budgetMaxUnavailable(&budget, r.Replicas)
+	switch {
+	case r.Replicas-unavailable < r.Quorum:
+		r.add(name, CapacityCheckFail, fmt.Sprintf("%s lets %d of %d brokers be evicted at once, below the quorum of %d", budget.Name, unavailable, r.Replicas, r.Quorum))
+	case unavailable <= 0:
+		r.add(name, CapacityCheckWarn, fmt.Sprintf("%s allows no evictions; node drains block until it is relaxed", budget.Name))
+	default:
+		r.add(name, CapacityCheckPass, fmt.Sprintf("%s lets %d of %d brokers be evicted at once (%d disruptions allowed now)", budget.Name, unavailable, r.Replicas, budget.Status.DisruptionsAllowed))
+	}
+}
+
+// checkAntiAffinity reports whether the scheduler keeps brokers on separate nodes
+func (r *DisruptionReport) checkAntiAffinity(template *v1.PodTemplateSpec) {
+	const name = "Pod anti-affinity"
+	if OneBrokerPerNode(template) {
+		r.add(name, CapacityCheckPass, "required anti-affinity on "+v1.LabelHostname+" keeps one broker per node")
+		return
+	}
+	if affinity := template.Spec.Affinity; affinity != nil && affinity.PodAntiAffinity != nil {
+		for _, term := range affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			if term.PodAffinityTerm.TopologyKey == v1.LabelHostname {
+				r.add(name, CapacityCheckWarn, "anti-affinity on "+v1.LabelHostname+" is only preferred; brokers may share a node")
+				return
+			}
+		}
+	}
+	r.add(name, CapacityCheckWarn, "no anti-affinity on "+v1.LabelHostname+"; brokers may share a node")
+}
+
+// checkNodeLoss fails when losing the node with the most brokers leaves fewer than the quorum
+func (r *DisruptionReport) checkNodeLoss() {
+	const name = "Node drain"
+	if len(r.Nodes) == 0 {
+		r.add(name, CapacityCheckWarn, "no broker pod is scheduled on a node")
+		return
+	}
+	worst := r.Nodes[0]
+	if worst.Remaining < r.Quorum {
+		r.add(name, CapacityCheckFail, fmt.Sprintf("losing node %s takes down %s, leaving %d of %d brokers below the quorum of %d", worst.Name, strings.Join(worst.Pods, ", "), worst.Remaining, r.Replicas, r.Quorum))
+		return
+	}
+	r.add(name, CapacityCheckPass, fmt.Sprintf("losing any single node leaves at least %d ready brokers (quorum %d)", worst.Remaining, r.Quorum))
+}
+
+// checkZoneLoss fails when losing the zone with the most brokers leaves fewer than the quorum.
+// A cluster in a single zone is only warned about, since spreading it needs more zones.
+func (r *DisruptionReport) checkZoneLoss(unlabeled bool) {
+	const name = "Zone outage"
+	switch {
+	case len(r.Zones) == 0:
+		r.add(name, CapacityCheckWarn, "the broker nodes carry no "+v1.LabelTopologyZone+" label; zone outages cannot be assessed")
+		return
+	case len(r.Zones) == 1:
+		r.add(name, CapacityCheckWarn, fmt.Sprintf("all brokers run in zone %s; the cluster does not survive an outage of that zone", r.Zones[0].Name))
+		return
+	}
+	worst := r.Zones[0]
+	details := ""
+	if unlabeled {
+		details = "; some broker nodes have no zone label"
+	}
+	if worst.Remaining < r.Quorum {
+		r.add(name, CapacityCheckFail, fmt.Sprintf("losing zone %s takes down %d brokers, leaving %d below the quorum of %d%s", worst.Name, len(worst.Pods), worst.Remaining, r.Quorum, details))
+		return
+	}
+	r.add(name, CapacityCheckPass, fmt.Sprintf("losing any single zone leaves at least %d ready brokers (quorum %d)%s", worst.Remaining, r.Quorum, details))
+}
+
+// domains summarizes the pods per node or zone, the domain with the most brokers first
+func (r *DisruptionReport) domains(pods map[string][]*v1.Pod) []DisruptionDomain {
+	domains := make([]DisruptionDomain, 0, len(pods))
+	for name, hosted := range pods {
+		domain := DisruptionDomain{Name: name, Remaining: r.ReadyPods}
+		for _, pod := range hosted {
+			domain.Pods = append(domain.Pods, pod.Name)
+			if podReady(pod) {
+				domain.Remaining--
+			}
+		}
+		sort.Strings(domain.Pods)
+		domains = append(domains, domain)
+	}
+	sort.Slice(domains, func(i, j int) bool {
+		if domains[i].Remaining != domains[j].Remaining {
+			return domains[i].Remaining < domains[j].Remaining
+		}
+		return domains[i].Name < domains[j].Name
+	})
+	return domains
+}
+
+// budgetMaxUnavailable is the number of pods a PodDisruptionBudget lets be evicted at once
+func budgetMaxUnavailable(budget *policyv1.PodDisruptionBudget, replicas int32) int32 {
+	if budget.Spec.MaxUnavailable != nil {
+		unavailable, err := intstr.GetScaledValueFromIntOrPercent(budget.Spec.MaxUnavailable, int(replicas), true)
+		if err == nil {
+			return int32(unavailable)
+		}
+	}
+	if budget.Spec.MinAvailable != nil {
+		available, err := intstr.GetScaledValueFromIntOrPercent(budget.Spec.MinAvailable, int(replicas), true)
+		if err == nil {
+			return max(replicas-int32(available), 0)
+		}
+	}
+	return 0
+}
+
+// SuggestedPodDisruptionBudget renders a PodDisruptionBudget that lets one broker be evicted at
+// a time, selecting the pods by the StatefulSet's selector
+func SuggestedPodDisruptionBudget(sts *appsv1.StatefulSet) string {
+	manifest := map[string]any{
+		"apiVersion": "policy/v1",
+		"kind":       "PodDisruptionBudget",
+		"metadata":   map[string]any{"name": sts.Name, "namespace": sts.Namespace},
+		"spec": map[string]any{
+			"maxUnavailable": 1,
+			"selector":       sts.Spec.Selector,
+		},
+	}
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func podReady(pod *v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func disruptionFixture(replicas int32, nodes ...string) (*appsv1.StatefulSet, []*v1.Pod) {
+	podLabels := map[string]string{"app": "hivemq"}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "production"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+			Template: v1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: podLabels}},
+		},
+	}
+	pods := make([]*v1.Pod, 0, len(nodes))
+	for i, node := range nodes {
+		pods = append(pods, &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "broker-" + string(rune('0'+i)), Labels: podLabels},
+			Spec:       v1.PodSpec{NodeName: node},
+			Status:     v1.PodStatus{Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}},
+		})
+	}
+	return sts, pods
+}
+
+func disruptionCheck(t *testing.T, report *DisruptionReport, name string) CapacityCheck {
+	t.Helper()
+	for _, check := range report.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("check %q missing from %+v", name, report.Checks)
+	return CapacityCheck{}
+}
+
+func TestEvaluateDisruption(t *testing.T) {
+	t.Parallel()
+
+	// Two of three brokers share node-a, so losing it breaks the quorum of two
+	sts, pods := disruptionFixture(3, "node-a", "node-a", "node-b")
+	zones := map[string]string{"node-a": "eu-1a", "node-b": "eu-1b"}
+	report := EvaluateDisruption(sts, pods, zones, nil)
+
+	if report.Quorum != 2 || report.ReadyPods != 3 {
+		t.Fatalf("quorum = %d, ready = %d, want 2 and 3", report.Quorum, report.ReadyPods)
+	}
+	if check := disruptionCheck(t, report, "Node drain"); check.Status != CapacityCheckFail || !strings.Contains(check.Details, "node-a") {
+		t.Errorf("node drain check = %+v, want a failure naming node-a", check)
+	}
+	if check := disruptionCheck(t, report, "Zone outage"); check.Status != CapacityCheckFail {
+		t.Errorf("zone outage check = %+v, want a failure", check)
+	}
+	if check := disruptionCheck(t, report, "PodDisruptionBudget"); check.Status != CapacityCheckWarn {
+		t.Errorf("budget check = %+v, want a warning", check)
+	}
+	if !strings.Contains(report.SuggestedPDB, "maxUnavailable: 1") || !strings.Contains(report.SuggestedPDB, "app: hivemq") {
+		t.Errorf("suggested PDB lacks maxUnavailable or selector:\n%s", report.SuggestedPDB)
+	}
+	if report.Verdict() != CapacityNoGo {
+		t.Errorf("verdict = %s, want %s", report.Verdict(), CapacityNoGo)
+	}
+
+	// Spread over three nodes and zones with a budget of one eviction at a time
+	sts, pods = disruptionFixture(3, "node-a", "node-b", "node-c")
+	zones = map[string]string{"node-a": "eu-1a", "node-b": "eu-1b", "node-c": "eu-1c"}
+	budget := policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker-pdb"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: &intstr.IntOrString{Type: intstr.String, StrVal: "60%"},
+			Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "hivemq"}},
+		},
+	}
+	report = EvaluateDisruption(sts, pods, zones, []policyv1.PodDisruptionBudget{budget})
+	if report.Verdict() != CapacityGo || report.SuggestedPDB != "" {
+		t.Errorf("verdict = %s with checks %+v, want %s without a suggested PDB", report.Verdict(), report.Checks, CapacityGo)
+	}
+	if check := disruptionCheck(t, report, "PodDisruptionBudget"); !strings.Contains(check.Details, "lets 1 of 3") {
+		t.Errorf("budget check = %+v, want one eviction allowed", check)
+	}
+}
+
+func TestBudgetMaxUnavailable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		spec   policyv1.PodDisruptionBudgetSpec
+		expect int32
+	}{
+		{"max unavailable count", policyv1.PodDisruptionBudgetSpec{MaxUnavailable: &intstr.IntOrString{IntVal: 2}}, 2},
+		{"max unavailable percent rounds up", policyv1.PodDisruptionBudgetSpec{MaxUnavailable: &intstr.IntOrString{Type: intstr.String, StrVal: "10%"}}, 1},
+		{"min available count", policyv1.PodDisruptionBudgetSpec{MinAvailable: &intstr.IntOrString{IntVal: 5}}, 0},
+		{"min available above replicas", policyv1.PodDisruptionBudgetSpec{MinAvailable: &intstr.IntOrString{IntVal: 9}}, 0},
+		{"no limit", policyv1.PodDisruptionBudgetSpec{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := budgetMaxUnavailable(&policyv1.PodDisruptionBudget{Spec: tt.spec}, 5); got != tt.expect {
+				t.Errorf("budgetMaxUnavailable() = %d, want %d", got, tt.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrDryRun is returned by mutating operations called with a dry-run context
+var ErrDryRun = errors.New("dry run: no changes made")
+
+type dryRunKey struct{}
+
+// WithDryRun marks the context as a dry run, in which mutating operations refuse to change anything
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// IsDryRun reports whether the context is a dry run
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// GuardMutation returns ErrDryRun for a dry-run context. Operations that change the cluster or
+// a broker call it right before the change, so a command that misses its own dry-run handling
+// fails instead of mutating.
+func GuardMutation(ctx context.Context, op, resource string) error {
+	if !IsDryRun(ctx) {
+		return nil
+	}
+	return fmt.Errorf("%s %s: %w", op, resource, ErrDryRun)
+}
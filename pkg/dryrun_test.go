@@ -0,0 +1,25 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGuardMutation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	if err := GuardMutation(ctx, "delete_pvc", "data-broker-0"); err != nil {
+		t.Fatalf("GuardMutation() without dry run = %v, want nil", err)
+	}
+
+	dryRun := WithDryRun(ctx)
+	if !IsDryRun(dryRun) || IsDryRun(ctx) {
+		t.Fatal("dry run must only be set on the derived context")
+	}
+	err := GuardMutation(dryRun, "delete_pvc", "data-broker-0")
+	if !errors.Is(err, ErrDryRun) {
+		t.Fatalf("GuardMutation() in dry run = %v, want ErrDryRun", err)
+	}
+}
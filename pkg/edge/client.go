@@ -0,0 +1,115 @@
+package edge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"kubectl-broker/pkg/transport"
+)
+
+const (
+	defaultTimeout    = 30 * time.Second
+	authenticatePath  = "/api/v1/auth/authenticate"
+	adaptersPath      = "/api/v1/management/protocol-adapters/adapters"
+	bearerTokenPrefix = "Bearer "
+)
+
+// ClientOptions configure the HTTP client.
+type ClientOptions struct {
+	Timeout time.Duration
+	TLS     transport.TLSOptions
+}
+
+// Client talks to the REST API of a HiveMQ Edge instance.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+}
+
+// NewClient builds a Client for the provided base URL.
+func NewClient(baseURL string, opts ClientOptions) (*Client, error) {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	httpClient, err := transport.NewHTTPClient(timeout, opts.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure TLS: %w", err)
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+	}, nil
+}
+
+// Authenticate exchanges credentials for the bearer token used by later requests. Edge
+// instances without authentication accept requests without a token.
+func (c *Client) Authenticate(ctx context.Context, username, password string) error {
+	payload, err := json.Marshal(authenticateRequest{UserName: username, Password: password})
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials: %w", err)
+	}
+	var resp authenticateResponse
+	if err := c.do(ctx, http.MethodPost, authenticatePath, bytes.NewReader(payload), &resp); err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+	if resp.Token == "" {
+		return fmt.Errorf("authentication failed: no token in response")
+	}
+	c.token = resp.Token
+	return nil
+}
+
+// ListAdapters returns the configured protocol adapters with their runtime status.
+func (c *Client) ListAdapters(ctx context.Context) ([]Adapter, error) {
+	var resp adapterList
+	if err := c.do(ctx, http.MethodGet, adaptersPath, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list protocol adapters: %w", err)
+	}
+	return resp.Items, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body io.Reader, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", bearerTokenPrefix+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("%w (HTTP %d)", ErrUnauthorized, resp.StatusCode)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package edge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListAdaptersWithAuthentication(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case authenticatePath:
+			var req authenticateRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UserName != "admin" || req.Password != "hivemq" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(authenticateResponse{Token: "t0ken"})
+		case adaptersPath:
+			if r.Header.Get("Authorization") != "Bearer t0ken" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"items":[
+				{"id":"plc-1","type":"opcua","status":{"connection":"CONNECTED","runtime":"STARTED"}},
+				{"id":"sim","type":"simulation","status":{"connection":"STATELESS","runtime":"STARTED"}},
+				{"id":"modbus-2","type":"modbus","status":{"connection":"ERROR","runtime":"STARTED","message":"timeout"}}
+			]}`))
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	if _, err := client.ListAdapters(context.Background()); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized without a token, got %v", err)
+	}
+	if err := client.Authenticate(context.Background(), "admin", "hivemq"); err != nil {
+		t.Fatalf("Authenticate returned error: %v", err)
+	}
+
+	adapters, err := client.ListAdapters(context.Background())
+	if err != nil {
+		t.Fatalf("ListAdapters returned error: %v", err)
+	}
+	if len(adapters) != 3 {
+		t.Fatalf("expected 3 adapters, got %+v", adapters)
+	}
+	healthy := []bool{true, true, false}
+	for i, adapter := range adapters {
+		if adapter.Healthy() != healthy[i] {
+			t.Errorf("adapter %s: expected healthy=%v", adapter.ID, healthy[i])
+		}
+	}
+}
+
+func TestAuthenticateRejectsWrongCredentials(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	if err := client.Authenticate(context.Background(), "admin", "wrong"); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package edge
+
+import "errors"
+
+// ErrUnauthorized indicates the Edge API rejected the request's credentials.
+var ErrUnauthorized = errors.New("the Edge API rejected the credentials")
+
+// Connection and runtime states reported for protocol adapters.
+const (
+	ConnectionConnected    = "CONNECTED"
+	ConnectionDisconnected = "DISCONNECTED"
+	ConnectionStateless    = "STATELESS"
+	ConnectionError        = "ERROR"
+	RuntimeStarted         = "STARTED"
+	RuntimeStopped         = "STOPPED"
+)
+
+// Adapter is a protocol adapter (OPC UA, Modbus, S7, ...) configured on Edge.
+type Adapter struct {
+	ID     string         `json:"id"`
+	Type   string         `json:"type"`
+	Status *AdapterStatus `json:"status,omitempty"`
+}
+
+// AdapterStatus is the runtime state of an adapter.
+type AdapterStatus struct {
+	Connection string `json:"connection,omitempty"`
+	Runtime    string `json:"runtime,omitempty"`
+	Message    string `json:"message,omitempty"`
+	StartedAt  string `json:"startedAt,omitempty"`
+}
+
+// Healthy reports whether the adapter is started and, unless it is stateless, connected.
+func (a Adapter) Healthy() bool {
+	if a.Status == nil || a.Status.Runtime != RuntimeStarted {
+		return false
+	}
+	return a.Status.Connection == ConnectionConnected || a.Status.Connection == ConnectionStateless
+}
+
+type adapterList struct {
+	Items []Adapter `json:"items"`
+}
+
+type authenticateRequest struct {
+	UserName string `json:"userName"`
+	Password string `json:"password"`
+}
+
+type authenticateResponse struct {
+	Token string `json:"token"`
+}
@@ -21,6 +21,7 @@ const (
 	ErrTypeHealthCheck   ErrType = "health_check"
 	ErrTypePortforward   ErrType = "portforward"
 	ErrTypeConfiguration ErrType = "configuration"
+	ErrTypeAPI           ErrType = "api"
 )
 
 // AppError represents a domain-specific error with additional context
@@ -115,6 +116,18 @@ func NewConfigurationError(op, message string) *AppError {
 	}
 }
 
+// NewAPIError creates an error for a request the broker's management API rejected. The message
+// is the server's own description, so it reads the same with or without the taxonomy.
+func NewAPIError(op, resource string, err error) *AppError {
+	return &AppError{
+		Type:     ErrTypeAPI,
+		Op:       op,
+		Resource: resource,
+		Err:      err,
+		Message:  err.Error(),
+	}
+}
+
 // EnhanceError provides user-friendly error messages with actionable guidance
 func EnhanceError(err error, context string) error {
 	if err == nil {
@@ -207,3 +220,59 @@ func ValidatePodStatus(pod *v1.Pod) error {
 		return NewValidationError("validate_pod_status", pod.Name, message)
 	}
 }
+
+// ErrTypeUnknown is reported for errors that are not AppErrors
+const ErrTypeUnknown ErrType = "unknown"
+
+// ErrorEnvelope is the machine-readable form of a command failure
+type ErrorEnvelope struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes a failure using the AppError taxonomy
+type ErrorDetail struct {
+	Type     ErrType `json:"type"`
+	Op       string  `json:"op,omitempty"`
+	Resource string  `json:"resource,omitempty"`
+	Message  string  `json:"message"`
+	Hint     string  `json:"hint,omitempty"`
+	// RequestID is the X-Request-Id sent with the HiveMQ API requests of the failed run
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// defaultHints gives generic guidance when an error carries no hint of its own
+var defaultHints = map[ErrType]string{
+	ErrTypeKubernetes:  "Check the kubeconfig context, namespace and RBAC permissions",
+	ErrTypeNetwork:     "Check connectivity to the cluster and that the pod is running",
+	ErrTypePortforward: "Check that the pod is running and allows port-forwarding",
+	ErrTypeAPI:         "Check the broker logs for the request; the management API rejected it",
+}
+
+// NewErrorEnvelope converts an error into its machine-readable form. Guidance following a
+// blank line in the message (the "Please either:" block) is reported as the hint.
+func NewErrorEnvelope(err error) ErrorEnvelope {
+	detail := ErrorDetail{Type: ErrTypeUnknown}
+	if err == nil {
+		return ErrorEnvelope{Error: detail}
+	}
+
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		detail.Type = appErr.Type
+		detail.Op = appErr.Op
+		detail.Resource = appErr.Resource
+	}
+
+	message := strings.TrimSpace(err.Error())
+	if before, after, found := strings.Cut(message, "\n\n"); found {
+		message = strings.TrimSpace(before)
+		detail.Hint = strings.TrimSpace(after)
+	}
+	detail.Message = message
+
+	if detail.Hint == "" {
+		detail.Hint = defaultHints[detail.Type]
+	}
+
+	return ErrorEnvelope{Error: detail}
+}
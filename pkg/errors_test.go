@@ -0,0 +1,32 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNewErrorEnvelope(t *testing.T) {
+	t.Parallel()
+
+	wrapped := fmt.Errorf("status: %w", NewKubernetesError("get_pod", "broker-0", errors.New("not found")))
+	envelope := NewErrorEnvelope(wrapped)
+	if envelope.Error.Type != ErrTypeKubernetes || envelope.Error.Op != "get_pod" || envelope.Error.Resource != "broker-0" {
+		t.Fatalf("unexpected envelope for AppError: %+v", envelope.Error)
+	}
+	if envelope.Error.Hint == "" {
+		t.Fatal("expected default hint for kubernetes errors")
+	}
+
+	guided := errors.New("invalid --since value \"x\"\n\nPlease either:\n- Use a Go duration: --since 6h")
+	envelope = NewErrorEnvelope(guided)
+	if envelope.Error.Type != ErrTypeUnknown {
+		t.Fatalf("expected unknown type, got %s", envelope.Error.Type)
+	}
+	if envelope.Error.Message != "invalid --since value \"x\"" {
+		t.Fatalf("unexpected message %q", envelope.Error.Message)
+	}
+	if envelope.Error.Hint != "Please either:\n- Use a Go duration: --since 6h" {
+		t.Fatalf("unexpected hint %q", envelope.Error.Hint)
+	}
+}
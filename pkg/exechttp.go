@@ -0,0 +1,363 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"kubectl-broker/pkg/tracing"
+	"kubectl-broker/pkg/transport"
+)
+
+// HTTP clients available in broker images that the exec fallback can drive
+const (
+	execHTTPToolCurl = "curl"
+	execHTTPToolWget = "wget"
+)
+
+// isPortForwardForbidden reports whether a port-forward failed because RBAC or an admission
+// policy denies the pods/portforward subresource. The port-forward library flattens the API
+// status into a string, so the message is checked as well.
+func isPortForwardForbidden(err error) bool {
+	if err == nil {
+		return false
+	}
+	return apierrors.IsForbidden(err) || strings.Contains(strings.ToLower(err.Error()), "forbidden")
+}
+
+// errExecHTTPTLS is returned when a client speaks TLS to the exec HTTP proxy, which only serves
+// plain HTTP
+var errExecHTTPTLS = errors.New("the exec fallback only serves plain HTTP and cannot be used with --api-tls")
+
+// execHTTPProxy serves HTTP on a local port and replays every request inside the pod with curl
+// or wget through the exec subresource. Responses are buffered, so there is no streaming, and
+// only plain HTTP is supported towards the pod.
+type execHTTPProxy struct {
+	pf         *PortForwarder
+	pod        *v1.Pod
+	container  string
+	remotePort int32
+	tool       string
+
+	// sawTLS is set when a client opened a TLS handshake against the proxy
+	sawTLS atomic.Bool
+}
+
+// performWithExecHTTP runs operation against a local port that is served by an exec HTTP proxy
+func (pf *PortForwarder) performWithExecHTTP(ctx context.Context, pod *v1.Pod, remotePort int32, localPort int, operation func(localPort int) error) error {
+	proxy := &execHTTPProxy{pf: pf, pod: pod, container: BrokerContainerName(pod), remotePort: remotePort}
+
+	tool, err := proxy.detectTool(ctx)
+	if err != nil {
+		return err
+	}
+	proxy.tool = tool
+
+	listener, err := net.Listen("tcp", transport.LocalHostPort(localPort))
+	if err != nil {
+		return fmt.Errorf("failed to listen on local port %d: %w", localPort, err)
+	}
+
+	slog.Warn("Port-forward is forbidden; tunnelling HTTP through pod exec with reduced functionality (no streaming, plain HTTP only, no --api-tls)",
+		"pod", pod.Name, "tool", tool)
+
+	server := &http.Server{
+		Handler:           proxy,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		_ = server.Serve(&tlsDetectingListener{Listener: listener, proxy: proxy})
+	}()
+	defer func() { _ = server.Close() }()
+
+	err = operation(localPort)
+	if err != nil && proxy.sawTLS.Load() {
+		return fmt.Errorf("port-forward to pod %s is forbidden: %w\n\nPlease either:\n- Ask your cluster administrator to allow create on pods/portforward\n- Call the management API over plain HTTP (omit --api-tls)", pod.Name, errExecHTTPTLS)
+	}
+	return err
+}
+
+// tlsDetectingListener closes connections that open with a TLS handshake, which the plain HTTP
+// proxy would otherwise answer with an HTTP error the client cannot read
+type tlsDetectingListener struct {
+	net.Listener
+	proxy *execHTTPProxy
+}
+
+func (l *tlsDetectingListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		reader := bufio.NewReader(conn)
+		_ = conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+		first, err := reader.Peek(1)
+		_ = conn.SetReadDeadline(time.Time{})
+		// 0x16 is the record type of a TLS handshake
+		if err == nil && first[0] == 0x16 {
+			l.proxy.sawTLS.Store(true)
+			slog.Error(errExecHTTPTLS.Error(), "pod", l.proxy.pod.Name)
+			_ = conn.Close()
+			continue
+		}
+		return &peekedConn{Conn: conn, reader: reader}, nil
+	}
+}
+
+// peekedConn replays the bytes buffered while detecting TLS
+type peekedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *peekedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// detectTool finds curl or wget in the broker container
+func (p *execHTTPProxy) detectTool(ctx context.Context) (string, error) {
+	stdout, _, err := p.pf.execInPod(ctx, p.pod, p.container,
+		[]string{"sh", "-c", "command -v curl || command -v wget"}, nil)
+	output := strings.TrimSpace(string(stdout))
+	switch {
+	case strings.HasSuffix(output, "/"+execHTTPToolCurl), output == execHTTPToolCurl:
+		return execHTTPToolCurl, nil
+	case strings.HasSuffix(output, "/"+execHTTPToolWget), output == execHTTPToolWget:
+		return execHTTPToolWget, nil
+	}
+
+	if err == nil {
+		err = errors.New("neither curl nor wget is available")
+	}
+	return "", fmt.Errorf("port-forward is forbidden and the exec fallback cannot be used in pod %s: %w\n\nPlease either:\n- Ask your cluster administrator to allow create on pods/portforward\n- Use a broker image that contains curl or wget", p.pod.Name, err)
+}
+
+func (p *execHTTPProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	target := fmt.Sprintf("http://localhost:%d%s", p.remotePort, r.URL.RequestURI())
+	var resp *http.Response
+	if p.tool == execHTTPToolCurl {
+		resp, err = p.viaCurl(r, target, body)
+	} else {
+		resp, err = p.viaWget(r, target, body)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	for key, values := range resp.Header {
+		// The body is re-framed by the local server
+		if skipExecHTTPHeader(key) {
+			continue
+		}
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+// execHTTPCurlScript runs curl with the headers of a config file that is read from the first $1
+// bytes of stdin; the rest of stdin is the request body. Headers carry the management API
+// credentials and exec commands end up in audit logs and the pod's process list, so they are
+// never passed as arguments.
+const execHTTPCurlScript = `h=$(mktemp) || exit 1
+trap 'rm -f "$h"' EXIT
+dd bs=1 count="$1" of="$h" 2>/dev/null || exit 1
+shift
+curl --config "$h" "$@"`
+
+// viaCurl replays the request with curl, which prints the status line and headers with -i
+func (p *execHTTPProxy) viaCurl(r *http.Request, target string, body []byte) (*http.Response, error) {
+	config := curlHeaderConfig(r.Header)
+	command := []string{"sh", "-c", execHTTPCurlScript, "sh", strconv.Itoa(len(config)),
+		"-sS", "-i", "-X", r.Method}
+	if len(body) > 0 {
+		command = append(command, "--data-binary", "@-")
+	}
+	command = append(command, target)
+
+	stdin := io.MultiReader(strings.NewReader(config), bytes.NewReader(body))
+	stdout, stderr, err := p.pf.execInPod(r.Context(), p.pod, p.container, command, stdin)
+	if err != nil {
+		return nil, execHTTPError("curl", stderr, err)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(stdout)), r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse curl response: %w", err)
+	}
+	return resp, nil
+}
+
+// curlHeaderConfig renders request headers as a curl config file
+func curlHeaderConfig(header http.Header) string {
+	var sb strings.Builder
+	// An empty Expect header stops curl from waiting for 100-continue
+	sb.WriteString("header = \"Expect:\"\n")
+	for key, values := range header {
+		if skipExecHTTPHeader(key) {
+			continue
+		}
+		for _, value := range values {
+			sb.WriteString("header = \"")
+			sb.WriteString(curlConfigEscaper.Replace(key + ": " + value))
+			sb.WriteString("\"\n")
+		}
+	}
+	return sb.String()
+}
+
+var curlConfigEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// viaWget replays the request with wget. wget -S writes the response headers to stderr and
+// exits non-zero for error statuses, so the status is taken from the headers when present.
+// BusyBox wget reads headers only from arguments, so requests with credentials are refused.
+func (p *execHTTPProxy) viaWget(r *http.Request, target string, body []byte) (*http.Response, error) {
+	command := []string{"wget", "-q", "-S", "-O", "-"}
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		command = append(command, "--post-data", string(body))
+	default:
+		return nil, fmt.Errorf("wget in pod %s cannot send %s requests; install curl for full support", p.pod.Name, r.Method)
+	}
+	for key, values := range r.Header {
+		if skipExecHTTPHeader(key) {
+			continue
+		}
+		if sensitiveExecHTTPHeader(key) {
+			return nil, fmt.Errorf("wget in pod %s cannot send the %s header without exposing it in the pod's process list and audit logs; install curl for authenticated requests", p.pod.Name, http.CanonicalHeaderKey(key))
+		}
+		for _, value := range values {
+			command = append(command, "--header", key+": "+value)
+		}
+	}
+	command = append(command, target)
+
+	stdout, stderr, err := p.pf.execInPod(r.Context(), p.pod, p.container, command, nil)
+	resp := parseWgetHeaders(stderr)
+	if resp == nil {
+		return nil, execHTTPError("wget", stderr, err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(stdout))
+	resp.ContentLength = int64(len(stdout))
+	resp.Request = r
+	return resp, nil
+}
+
+// parseWgetHeaders builds a response from "wget -S" output, using the last status line when
+// redirects were followed. It returns nil when no status line was printed.
+func parseWgetHeaders(output []byte) *http.Response {
+	var resp *http.Response
+	for _, line := range strings.Split(string(output), "\n") {
+		// Server headers are indented; unindented lines are wget's own messages
+		if !strings.HasPrefix(line, " ") {
+			continue
+		}
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "HTTP/") {
+			fields := strings.Fields(line)
+			if len(fields) < 2 {
+				continue
+			}
+			code, err := strconv.Atoi(fields[1])
+			if err != nil {
+				continue
+			}
+			resp = &http.Response{StatusCode: code, Status: strings.Join(fields[1:], " "), Proto: fields[0], Header: make(http.Header)}
+			continue
+		}
+		if resp == nil {
+			continue
+		}
+		if key, value, found := strings.Cut(line, ":"); found {
+			resp.Header.Add(strings.TrimSpace(key), strings.TrimSpace(value))
+		}
+	}
+	return resp
+}
+
+// skipExecHTTPHeader reports hop-by-hop and framing headers that the tool in the pod and the
+// local server set themselves
+func skipExecHTTPHeader(key string) bool {
+	switch http.CanonicalHeaderKey(key) {
+	case "Connection", "Content-Length", "Transfer-Encoding", "Keep-Alive":
+		return true
+	}
+	return false
+}
+
+// sensitiveExecHTTPHeader reports headers carrying credentials
+func sensitiveExecHTTPHeader(key string) bool {
+	switch http.CanonicalHeaderKey(key) {
+	case "Authorization", "Proxy-Authorization", "Cookie":
+		return true
+	}
+	return false
+}
+
+func execHTTPError(tool string, stderr []byte, err error) error {
+	if message := strings.TrimSpace(string(stderr)); message != "" {
+		return fmt.Errorf("%s in pod failed: %s", tool, message)
+	}
+	return fmt.Errorf("%s in pod failed: %w", tool, err)
+}
+
+// execInPod runs a command in a pod container and returns its stdout and stderr. Unlike
+// K8sClient.ExecCommand, output is returned even when the command exits non-zero.
+func (pf *PortForwarder) execInPod(ctx context.Context, pod *v1.Pod, container string, command []string, stdin io.Reader) (stdoutData, stderrData []byte, err error) {
+	ctx, span := startExecSpan(ctx, pod.Namespace, pod.Name, container, command)
+	defer func() { tracing.End(span, err) }()
+
+	req := pf.restClient.Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     stdin != nil,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(pf.config, "POST", req.URL())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create SPDY executor: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	return stdout.Bytes(), stderr.Bytes(), err
+}
@@ -0,0 +1,96 @@
+package pkg
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseWgetHeadersUsesLastStatus(t *testing.T) {
+	t.Parallel()
+
+	output := []byte("  HTTP/1.1 302 Found\n  Location: /api/v1/health\nConnecting to localhost:8080\n  HTTP/1.1 503 Service Unavailable\n  Content-Type: application/json\nwget: server returned error: HTTP/1.1 503 Service Unavailable\n")
+	resp := parseWgetHeaders(output)
+	if resp == nil {
+		t.Fatal("expected a response")
+	}
+	if resp.StatusCode != 503 || resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected response: %d %v", resp.StatusCode, resp.Header)
+	}
+	if resp.Header.Get("Location") != "" {
+		t.Errorf("headers of the redirect leaked into the final response: %v", resp.Header)
+	}
+
+	if parseWgetHeaders([]byte("wget: bad address 'localhost'\n")) != nil {
+		t.Error("expected nil without a status line")
+	}
+}
+
+func TestIsPortForwardForbidden(t *testing.T) {
+	t.Parallel()
+
+	forbidden := errors.New(`error upgrading connection: pods "broker-0" is forbidden: User "dev" cannot create resource "pods/portforward"`)
+	if !isPortForwardForbidden(forbidden) {
+		t.Error("expected forbidden port-forward to be detected")
+	}
+	if isPortForwardForbidden(errors.New("error upgrading connection: dial tcp: i/o timeout")) {
+		t.Error("network errors must not trigger the exec fallback")
+	}
+}
+
+func TestCurlHeaderConfigKeepsCredentialsOutOfArguments(t *testing.T) {
+	t.Parallel()
+
+	header := http.Header{}
+	header.Set("Authorization", `Basic "quoted"\secret`)
+	header.Set("Content-Length", "12")
+
+	config := curlHeaderConfig(header)
+	if !strings.Contains(config, `header = "Authorization: Basic \"quoted\"\\secret"`) {
+		t.Errorf("Authorization missing or unescaped in config:\n%s", config)
+	}
+	if strings.Contains(config, "Content-Length") {
+		t.Errorf("framing header forwarded to curl:\n%s", config)
+	}
+	if strings.Contains(execHTTPCurlScript, "Authorization") {
+		t.Error("curl script must not carry headers")
+	}
+}
+
+func TestTLSDetectingListenerRejectsHandshake(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	proxy := &execHTTPProxy{pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "broker-0"}}}
+	server := &http.Server{
+		Handler:           http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		ReadHeaderTimeout: time.Second,
+	}
+	go func() { _ = server.Serve(&tlsDetectingListener{Listener: listener, proxy: proxy}) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", listener.Addr().String(), &tls.Config{InsecureSkipVerify: true})
+	if err == nil {
+		_ = conn.Close()
+		t.Fatal("expected the TLS handshake to fail")
+	}
+	if !proxy.sawTLS.Load() {
+		t.Error("sawTLS = false after a TLS handshake")
+	}
+
+	resp, err := http.Get("http://" + listener.Addr().String())
+	if err != nil {
+		t.Fatalf("plain HTTP request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+}
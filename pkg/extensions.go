@@ -0,0 +1,214 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// ExtensionDisabledMarker is the file that makes HiveMQ stop an extension at runtime while it
+// exists in the extension's folder; removing it starts the extension again
+const ExtensionDisabledMarker = "DISABLED"
+
+// extensionsFolderScript sets $dir to the extensions folder of the broker container
+const extensionsFolderScript = `dir="${HIVEMQ_EXTENSION_FOLDER:-/opt/hivemq/extensions}"`
+
+// Extension states
+const (
+	ExtensionEnabled  = "enabled"
+	ExtensionDisabled = "disabled"
+)
+
+// ExtensionInfo is an extension installed on a broker pod
+type ExtensionInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	State   string `json:"state"`
+}
+
+// PodExtensions lists the extensions of one broker pod
+type PodExtensions struct {
+	Pod        string          `json:"pod"`
+	Extensions []ExtensionInfo `json:"extensions"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// ExtensionPodChange is the outcome of enabling or disabling an extension on one pod
+type ExtensionPodChange struct {
+	Pod string `json:"pod"`
+	// Previous is the state before the change; pods already in the target state are unchanged
+	Previous   string `json:"previous,omitempty"`
+	Changed    bool   `json:"changed"`
+	RolledBack bool   `json:"rolledBack,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ExtensionChange is the outcome of enabling or disabling an extension across pods
+type ExtensionChange struct {
+	Extension string               `json:"extension"`
+	State     string               `json:"state"` // target state
+	Pods      []ExtensionPodChange `json:"pods"`
+}
+
+// extensionNamePattern matches extension folder names; it also keeps names safe to embed in scripts
+var extensionNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidateExtensionName rejects names that are not a single folder below the extensions folder
+func ValidateExtensionName(name string) error {
+	if !extensionNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid extension name %q: use the extension's folder name, e.g. hivemq-prometheus-extension", name)
+	}
+	return nil
+}
+
+// ListExtensionsScript prints "name<TAB>state<TAB>version" for every extension folder with a
+// hivemq-extension.xml descriptor
+func ListExtensionsScript() string {
+	return extensionsFolderScript + `; for ext in "$dir"/*/; do ` +
+		`[ -f "${ext}hivemq-extension.xml" ] || continue; ` +
+		`state=enabled; [ -e "${ext}` + ExtensionDisabledMarker + `" ] && state=disabled; ` +
+		`version=$(sed -n 's:.*<version>\(.*\)</version>.*:\1:p' "${ext}hivemq-extension.xml" | head -n 1); ` +
+		`printf '%s\t%s\t%s\n' "$(basename "$ext")" "$state" "$version"; done`
+}
+
+// SetExtensionStateScript enables or disables an extension by removing or creating its
+// DISABLED marker and prints the state it had before. The name must pass ValidateExtensionName.
+func SetExtensionStateScript(name, state string) string {
+	ext := `"$dir"/` + name
+	change := "rm -f " + ext + "/" + ExtensionDisabledMarker
+	if state == ExtensionDisabled {
+		change = "touch " + ext + "/" + ExtensionDisabledMarker
+	}
+	return extensionsFolderScript + `; [ -f ` + ext + `/hivemq-extension.xml ] || { echo "extension ` + name + ` not found in $dir" >&2; exit 3; }; ` +
+		`if [ -e ` + ext + `/` + ExtensionDisabledMarker + ` ]; then echo disabled; else echo enabled; fi; ` + change
+}
+
+// ExtensionVerb is the action that leads to state: enable or disable
+func ExtensionVerb(state string) string {
+	if state == ExtensionDisabled {
+		return "disable"
+	}
+	return "enable"
+}
+
+// ParseExtensionList parses the output of ListExtensionsScript, sorted by name
+func ParseExtensionList(output string) []ExtensionInfo {
+	extensions := []ExtensionInfo{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(strings.TrimRight(line, "\r"), "\t", 3)
+		if len(fields) < 2 || fields[0] == "" {
+			continue
+		}
+		extension := ExtensionInfo{Name: fields[0], State: fields[1]}
+		if len(fields) == 3 {
+			extension.Version = strings.TrimSpace(fields[2])
+		}
+		extensions = append(extensions, extension)
+	}
+	sort.Slice(extensions, func(i, j int) bool { return extensions[i].Name < extensions[j].Name })
+	return extensions
+}
+
+// ExtensionDrift names the extensions that are missing on some pods or whose version or state
+// differs between pods. Pods that could not be read are ignored.
+func ExtensionDrift(listing []PodExtensions) []string {
+	signatures := map[string]map[string]bool{} // extension -> "version/state" seen
+	counts := map[string]int{}
+	readable := 0
+	for _, pod := range listing {
+		if pod.Error != "" {
+			continue
+		}
+		readable++
+		for _, extension := range pod.Extensions {
+			if signatures[extension.Name] == nil {
+				signatures[extension.Name] = map[string]bool{}
+			}
+			signatures[extension.Name][extension.Version+"/"+extension.State] = true
+			counts[extension.Name]++
+		}
+	}
+
+	drift := []string{}
+	for name, seen := range signatures {
+		if len(seen) > 1 || counts[name] != readable {
+			drift = append(drift, name)
+		}
+	}
+	sort.Strings(drift)
+	return drift
+}
+
+// CollectExtensions lists the extensions of every pod. Pods that cannot be read are reported
+// with their error instead of failing the listing.
+func (k *K8sClient) CollectExtensions(ctx context.Context, pods []*v1.Pod) []PodExtensions {
+	listing := make([]PodExtensions, 0, len(pods))
+	for _, pod := range pods {
+		entry := PodExtensions{Pod: pod.Name, Extensions: []ExtensionInfo{}}
+		output, err := k.ExecCommand(ctx, pod.Namespace, pod.Name, []string{"sh", "-c", ListExtensionsScript()})
+		if err != nil {
+			entry.Error = err.Error()
+		} else {
+			entry.Extensions = ParseExtensionList(output)
+		}
+		listing = append(listing, entry)
+	}
+	return listing
+}
+
+// SetExtensionState enables or disables an extension on every pod, in order. When a pod fails,
+// the pods changed so far are set back to their previous state and the error is returned along
+// with the per-pod outcome.
+func (k *K8sClient) SetExtensionState(ctx context.Context, pods []*v1.Pod, name, state string) (*ExtensionChange, error) {
+	if err := ValidateExtensionName(name); err != nil {
+		return nil, err
+	}
+	if err := GuardMutation(ctx, ExtensionVerb(state)+" extension", name); err != nil {
+		return nil, err
+	}
+
+	change := &ExtensionChange{Extension: name, State: state, Pods: make([]ExtensionPodChange, 0, len(pods))}
+	for _, pod := range pods {
+		previous, err := k.setExtensionStateOnPod(ctx, pod, name, state)
+		if err != nil {
+			change.Pods = append(change.Pods, ExtensionPodChange{Pod: pod.Name, Error: err.Error()})
+			k.rollbackExtensionState(ctx, pods, change)
+			return change, fmt.Errorf("failed to %s extension %s on pod %s: %w", ExtensionVerb(state), name, pod.Name, err)
+		}
+		change.Pods = append(change.Pods, ExtensionPodChange{Pod: pod.Name, Previous: previous, Changed: previous != state})
+	}
+	return change, nil
+}
+
+func (k *K8sClient) setExtensionStateOnPod(ctx context.Context, pod *v1.Pod, name, state string) (string, error) {
+	output, err := k.ExecCommand(ctx, pod.Namespace, pod.Name, []string{"sh", "-c", SetExtensionStateScript(name, state)})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// rollbackExtensionState restores the previous state on the pods a failed change modified.
+// It runs detached from a cancelled context, so an interrupted change is still rolled back.
+func (k *K8sClient) rollbackExtensionState(ctx context.Context, pods []*v1.Pod, change *ExtensionChange) {
+	ctx = context.WithoutCancel(ctx)
+	byName := make(map[string]*v1.Pod, len(pods))
+	for _, pod := range pods {
+		byName[pod.Name] = pod
+	}
+	for i := range change.Pods {
+		podChange := &change.Pods[i]
+		if !podChange.Changed {
+			continue
+		}
+		if _, err := k.setExtensionStateOnPod(ctx, byName[podChange.Pod], change.Extension, podChange.Previous); err != nil {
+			podChange.Error = "rollback failed: " + err.Error()
+			continue
+		}
+		podChange.RolledBack = true
+	}
+}
@@ -0,0 +1,64 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseExtensionList(t *testing.T) {
+	t.Parallel()
+
+	output := "hivemq-prometheus-extension\tenabled\t4.0.9\n" +
+		"hivemq-allow-all-extension\tdisabled\t\n" +
+		"garbage\n\n"
+	got := ParseExtensionList(output)
+	want := []ExtensionInfo{
+		{Name: "hivemq-allow-all-extension", State: ExtensionDisabled},
+		{Name: "hivemq-prometheus-extension", State: ExtensionEnabled, Version: "4.0.9"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParseExtensionList() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("extension %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestExtensionDrift(t *testing.T) {
+	t.Parallel()
+
+	prometheus := ExtensionInfo{Name: "prometheus", Version: "4.0.9", State: ExtensionEnabled}
+	listing := []PodExtensions{
+		{Pod: "broker-0", Extensions: []ExtensionInfo{prometheus, {Name: "kafka", Version: "4.1.0", State: ExtensionEnabled}, {Name: "allow-all", State: ExtensionDisabled}}},
+		{Pod: "broker-1", Extensions: []ExtensionInfo{prometheus, {Name: "kafka", Version: "4.2.0", State: ExtensionEnabled}}},
+		{Pod: "broker-2", Error: "container not running"},
+	}
+	got := ExtensionDrift(listing)
+	if strings.Join(got, ",") != "allow-all,kafka" {
+		t.Errorf("ExtensionDrift() = %v, want [allow-all kafka]", got)
+	}
+}
+
+func TestSetExtensionStateScript(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"", "..", "a/b", "ext;rm -rf /", "$(id)", "-rf"} {
+		if err := ValidateExtensionName(name); err == nil {
+			t.Errorf("ValidateExtensionName(%q) succeeded, want an error", name)
+		}
+	}
+	if err := ValidateExtensionName("hivemq-prometheus-extension"); err != nil {
+		t.Fatalf("ValidateExtensionName() = %v", err)
+	}
+
+	disable := SetExtensionStateScript("hivemq-prometheus-extension", ExtensionDisabled)
+	if !strings.Contains(disable, `touch "$dir"/hivemq-prometheus-extension/DISABLED`) {
+		t.Errorf("disable script does not create the marker: %s", disable)
+	}
+	enable := SetExtensionStateScript("hivemq-prometheus-extension", ExtensionEnabled)
+	if !strings.Contains(enable, `rm -f "$dir"/hivemq-prometheus-extension/DISABLED`) {
+		t.Errorf("enable script does not remove the marker: %s", enable)
+	}
+}
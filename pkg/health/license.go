@@ -0,0 +1,94 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// licenseExpiryKeys are the license detail fields that may carry an expiry date
+var licenseExpiryKeys = []string{"expiry-date", "expiration-date", "valid-until", "trial-expiry-date", "expires-at"}
+
+// ExtensionLicense is the license state an extension reports in the health API below
+// extensions.<name>.internals.license
+type ExtensionLicense struct {
+	Extension    string     `json:"extension"`
+	Enterprise   bool       `json:"enterprise"`
+	Trial        bool       `json:"trial"`
+	TrialExpired bool       `json:"trialExpired"`
+	Expiry       *time.Time `json:"expiry,omitempty"`
+	Summary      string     `json:"summary"` // e.g. "Enterprise, Trial"
+}
+
+// ExtractLicenses returns the license of every extension that reports one, sorted by extension
+func ExtractLicenses(jsonData []byte) ([]ExtensionLicense, error) {
+	var healthResp HealthResponse
+	if err := json.Unmarshal(jsonData, &healthResp); err != nil {
+		return nil, fmt.Errorf("failed to parse health response JSON: %w", err)
+	}
+
+	extensions, exists := healthResp.Components["extensions"]
+	if !exists {
+		return nil, nil
+	}
+
+	var licenses []ExtensionLicense
+	for name, extension := range extensions.Components {
+		internals, exists := extension.Components["internals"]
+		if !exists {
+			continue
+		}
+		license, exists := internals.Components["license"]
+		if !exists || license.Details == nil {
+			continue
+		}
+
+		details := license.Details
+		info := ExtensionLicense{
+			Extension:    name,
+			Enterprise:   detailBool(details, "is-enterprise"),
+			Trial:        detailBool(details, "is-trial"),
+			TrialExpired: detailBool(details, "is-trial-expired"),
+			Summary:      extractLicenseInfo(details),
+		}
+		for _, key := range licenseExpiryKeys {
+			if expiry, ok := parseLicenseDate(details[key]); ok {
+				info.Expiry = &expiry
+				break
+			}
+		}
+		licenses = append(licenses, info)
+	}
+
+	sort.Slice(licenses, func(i, j int) bool { return licenses[i].Extension < licenses[j].Extension })
+	return licenses, nil
+}
+
+func detailBool(details map[string]interface{}, key string) bool {
+	value, ok := details[key].(bool)
+	return ok && value
+}
+
+// parseLicenseDate accepts RFC 3339 timestamps, plain dates and epoch milliseconds
+func parseLicenseDate(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case string:
+		v = strings.TrimSpace(v)
+		for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+			if parsed, err := time.Parse(layout, v); err == nil {
+				return parsed, true
+			}
+		}
+		if millis, err := strconv.ParseInt(v, 10, 64); err == nil && millis > 0 {
+			return time.UnixMilli(millis).UTC(), true
+		}
+	case float64:
+		if v > 0 {
+			return time.UnixMilli(int64(v)).UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
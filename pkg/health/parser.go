@@ -3,7 +3,9 @@ package health
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -36,8 +38,25 @@ func ParseHealthResponse(jsonData []byte) (*ParsedHealthData, error) {
 	return ParseHealthResponseWithPodName(jsonData, "")
 }
 
+// ErrUnrecognizedStatus is returned by strict parsing when a response contains a status outside
+// the known set
+var ErrUnrecognizedStatus = errors.New("unrecognized health status")
+
+// ParseOptions control how health responses are parsed
+type ParseOptions struct {
+	// Strict fails on statuses outside the known set instead of counting them as unhealthy
+	Strict bool
+}
+
 // ParseHealthResponseWithPodName parses a HiveMQ health API JSON response with pod name (optimized)
 func ParseHealthResponseWithPodName(jsonData []byte, podName string) (*ParsedHealthData, error) {
+	return ParseHealthResponseWithOptions(jsonData, podName, ParseOptions{})
+}
+
+// ParseHealthResponseWithOptions parses a HiveMQ health API JSON response of either schema version.
+// Nested component trees are kept as sub-components and unknown fields in Extra, so responses of
+// newer broker versions parse without losing data.
+func ParseHealthResponseWithOptions(jsonData []byte, podName string, opts ParseOptions) (*ParsedHealthData, error) {
 	if len(jsonData) == 0 {
 		return nil, fmt.Errorf("empty JSON data provided")
 	}
@@ -54,9 +73,15 @@ func ParseHealthResponseWithPodName(jsonData []byte, podName string) (*ParsedHea
 	// Set basic fields
 	parsed.PodName = podName
 	parsed.OverallStatus = healthResp.Status
+	parsed.SchemaVersion = DetectSchemaVersion(&healthResp)
+	parsed.Extra = healthResp.Extra
 	parsed.RawJSON = make([]byte, len(jsonData)) // Create a copy to avoid retaining the original slice
 	copy(parsed.RawJSON, jsonData)
 
+	if !IsRecognizedStatus(healthResp.Status) {
+		parsed.Unrecognized = append(parsed.Unrecognized, unrecognizedEntry("overall", healthResp.Status))
+	}
+
 	// Parse components if available
 	if healthResp.Components != nil {
 		parsed.ComponentCount = len(healthResp.Components)
@@ -66,10 +91,12 @@ func ParseHealthResponseWithPodName(jsonData []byte, podName string) (*ParsedHea
 			parsed.ComponentDetails = make([]ComponentStatus, 0, len(healthResp.Components))
 		}
 
-		for name, component := range healthResp.Components {
+		for _, name := range sortedComponentNames(healthResp.Components) {
+			component := healthResp.Components[name]
 			componentStatus := ComponentStatus{
 				Name:   name,
 				Status: component.Status,
+				Extra:  component.Extra,
 			}
 
 			// Extract details as a formatted string using string builder for efficiency
@@ -80,8 +107,11 @@ func ParseHealthResponseWithPodName(jsonData []byte, podName string) (*ParsedHea
 			// Special handling for extensions component - parse sub-components
 			if name == "extensions" {
 				componentStatus.SubComponents = parseExtensionsComponentsOptimized(component)
+			} else {
+				componentStatus.SubComponents = parseNestedComponents(component.Components)
 			}
 
+			collectUnrecognized(parsed, name, component)
 			parsed.ComponentDetails = append(parsed.ComponentDetails, componentStatus)
 
 			// Count component health status using method for consistency
@@ -89,9 +119,81 @@ func ParseHealthResponseWithPodName(jsonData []byte, podName string) (*ParsedHea
 		}
 	}
 
+	if opts.Strict && len(parsed.Unrecognized) > 0 {
+		unrecognized := strings.Join(parsed.Unrecognized, ", ")
+		ReleaseParsedHealthData(parsed)
+		return nil, fmt.Errorf("%w: %s", ErrUnrecognizedStatus, unrecognized)
+	}
+
 	return parsed, nil
 }
 
+// DetectSchemaVersion reports SchemaV2 when a component other than extensions nests components
+func DetectSchemaVersion(resp *HealthResponse) SchemaVersion {
+	for name, component := range resp.Components {
+		if name != "extensions" && len(component.Components) > 0 {
+			return SchemaV2
+		}
+	}
+	return SchemaV1
+}
+
+// IsRecognizedStatus reports whether status is one of the statuses HiveMQ documents
+func IsRecognizedStatus(status HealthStatus) bool {
+	switch status {
+	case StatusUP, StatusDEGRADED, StatusDOWN, StatusUNKNOWN, StatusOUTOFSERVICE:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseNestedComponents converts a component tree of any depth into sub-components
+func parseNestedComponents(components map[string]ComponentHealth) []ComponentStatus {
+	if len(components) == 0 {
+		return nil
+	}
+
+	subComponents := make([]ComponentStatus, 0, len(components))
+	for _, name := range sortedComponentNames(components) {
+		component := components[name]
+		subComponents = append(subComponents, ComponentStatus{
+			Name:          name,
+			Status:        component.Status,
+			Details:       formatComponentDetails(component.Details),
+			SubComponents: parseNestedComponents(component.Components),
+			Extra:         component.Extra,
+		})
+	}
+	return subComponents
+}
+
+// collectUnrecognized records every unrecognized status in the tree below path
+func collectUnrecognized(parsed *ParsedHealthData, path string, component ComponentHealth) {
+	if !IsRecognizedStatus(component.Status) {
+		parsed.Unrecognized = append(parsed.Unrecognized, unrecognizedEntry(path, component.Status))
+	}
+	for _, name := range sortedComponentNames(component.Components) {
+		collectUnrecognized(parsed, path+"."+name, component.Components[name])
+	}
+}
+
+func unrecognizedEntry(path string, status HealthStatus) string {
+	if status == "" {
+		return path + ": (missing)"
+	}
+	return fmt.Sprintf("%s: %s", path, status)
+}
+
+func sortedComponentNames(components map[string]ComponentHealth) []string {
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // resetParsedHealthData resets a ParsedHealthData struct for reuse
 func resetParsedHealthData(parsed *ParsedHealthData) {
 	parsed.PodName = ""
@@ -102,6 +204,9 @@ func resetParsedHealthData(parsed *ParsedHealthData) {
 	parsed.UnhealthyComponents = 0
 	parsed.ComponentDetails = parsed.ComponentDetails[:0] // Reset slice but keep capacity
 	parsed.RawJSON = nil
+	parsed.SchemaVersion = ""
+	parsed.Unrecognized = nil
+	parsed.Extra = nil
 }
 
 // formatComponentDetails formats component details efficiently using string builder
@@ -135,7 +240,8 @@ func incrementHealthCounter(parsed *ParsedHealthData, status HealthStatus) {
 		parsed.HealthyComponents++
 	case StatusDEGRADED:
 		parsed.DegradedComponents++
-	case StatusDOWN, StatusUNKNOWN, StatusOUTOFSERVICE:
+	default:
+		// DOWN, UNKNOWN, OUT_OF_SERVICE and statuses outside the known set
 		parsed.UnhealthyComponents++
 	}
 }
@@ -251,21 +357,34 @@ func extractLicenseInfo(licenseDetails map[string]interface{}) string {
 	return strings.Join(licenseInfo, ", ")
 }
 
+// DefaultHealthBasePath is the prefix of the HiveMQ health API
+const DefaultHealthBasePath = "/api/v1/health"
+
 // GetHealthEndpointPath returns the full API path for a given health endpoint
 func GetHealthEndpointPath(endpoint string) string {
+	return ResolveHealthEndpointPath(endpoint, "")
+}
+
+// ResolveHealthEndpointPath returns the full API path for a health endpoint below basePath,
+// which defaults to /api/v1/health
+func ResolveHealthEndpointPath(endpoint, basePath string) string {
+	if basePath == "" {
+		basePath = DefaultHealthBasePath
+	}
+
 	switch endpoint {
 	case "liveness":
-		return "/api/v1/health/liveness"
+		return basePath + "/liveness"
 	case "readiness":
-		return "/api/v1/health/readiness"
+		return basePath + "/readiness"
 	case "health", "":
-		return "/api/v1/health"
+		return basePath
 	default:
 		// Allow custom endpoints to be passed through
 		if strings.HasPrefix(endpoint, "/") {
 			return endpoint
 		}
-		return "/api/v1/health/" + endpoint
+		return basePath + "/" + endpoint
 	}
 }
 
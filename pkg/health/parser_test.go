@@ -0,0 +1,80 @@
+package health
+
+import (
+	"errors"
+	"testing"
+)
+
+const nestedHealthResponse = `{
+  "status": "UP",
+  "version": "2",
+  "components": {
+    "cluster": {
+      "status": "UP",
+      "components": {
+        "node-a": {"status": "UP", "details": {"replicas": 2}},
+        "node-b": {"status": "SYNCING", "lag": 12}
+      }
+    },
+    "extensions": {
+      "status": "UP",
+      "components": {
+        "kafka": {"status": "UP", "details": {"version": "4.1.0"}}
+      }
+    },
+    "mqtt": {"status": "WARMING_UP"}
+  }
+}`
+
+func TestParseHealthResponseKeepsNestedComponents(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := ParseHealthResponseWithOptions([]byte(nestedHealthResponse), "broker-0", ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseHealthResponseWithOptions returned error: %v", err)
+	}
+	defer ReleaseParsedHealthData(parsed)
+
+	if parsed.SchemaVersion != SchemaV2 {
+		t.Errorf("SchemaVersion = %q, want %q", parsed.SchemaVersion, SchemaV2)
+	}
+	if parsed.Extra["version"] != "2" {
+		t.Errorf("Extra = %v, want the unknown version field", parsed.Extra)
+	}
+	// The unrecognized mqtt status counts as unhealthy instead of vanishing from the counts
+	if parsed.ComponentCount != 3 || parsed.HealthyComponents != 2 || parsed.UnhealthyComponents != 1 {
+		t.Errorf("counts = %d total, %d healthy, %d unhealthy, want 3, 2, 1", parsed.ComponentCount, parsed.HealthyComponents, parsed.UnhealthyComponents)
+	}
+
+	cluster := parsed.ComponentDetails[0]
+	if cluster.Name != "cluster" || len(cluster.SubComponents) != 2 {
+		t.Fatalf("cluster = %+v, want two nested nodes", cluster)
+	}
+	nodeB := cluster.SubComponents[1]
+	if nodeB.Name != "node-b" || nodeB.Status != "SYNCING" || nodeB.Extra["lag"] != float64(12) {
+		t.Errorf("node-b = %+v, want status SYNCING with the unknown lag field", nodeB)
+	}
+
+	want := []string{"cluster.node-b: SYNCING", "mqtt: WARMING_UP"}
+	if len(parsed.Unrecognized) != len(want) || parsed.Unrecognized[0] != want[0] || parsed.Unrecognized[1] != want[1] {
+		t.Errorf("Unrecognized = %v, want %v", parsed.Unrecognized, want)
+	}
+}
+
+func TestParseHealthResponseStrictRejectsUnrecognizedStatus(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseHealthResponseWithOptions([]byte(nestedHealthResponse), "broker-0", ParseOptions{Strict: true})
+	if !errors.Is(err, ErrUnrecognizedStatus) {
+		t.Fatalf("error = %v, want ErrUnrecognizedStatus", err)
+	}
+
+	parsed, err := ParseHealthResponseWithOptions([]byte(`{"status":"UP","components":{"extensions":{"status":"UP"}}}`), "broker-0", ParseOptions{Strict: true})
+	if err != nil {
+		t.Fatalf("strict parsing of a known response returned error: %v", err)
+	}
+	defer ReleaseParsedHealthData(parsed)
+	if parsed.SchemaVersion != SchemaV1 {
+		t.Errorf("SchemaVersion = %q, want %q", parsed.SchemaVersion, SchemaV1)
+	}
+}
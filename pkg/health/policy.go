@@ -0,0 +1,123 @@
+package health
+
+import (
+	"fmt"
+	"path"
+)
+
+// ComponentPolicy adjusts how component health contributes to the overall status.
+// Component names are the health API keys, with extensions addressed as
+// "extensions.<extension-id>". Patterns may use shell globs, e.g. "extensions.*-metering-*".
+type ComponentPolicy struct {
+	Ignore   []string // components that never affect the overall status
+	WarnOnly []string // components that can at most degrade the overall status
+}
+
+// IsZero reports whether the policy leaves the reported status untouched
+func (p ComponentPolicy) IsZero() bool {
+	return len(p.Ignore) == 0 && len(p.WarnOnly) == 0
+}
+
+// Validate checks that all patterns are valid globs
+func (p ComponentPolicy) Validate() error {
+	for _, pattern := range append(append([]string{}, p.Ignore...), p.WarnOnly...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid component pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Label returns "ignored", "warn-only" or "" for display next to a component
+func (p ComponentPolicy) Label(name string) string {
+	switch {
+	case matchesComponent(p.Ignore, name):
+		return "ignored"
+	case matchesComponent(p.WarnOnly, name):
+		return "warn-only"
+	default:
+		return ""
+	}
+}
+
+// OverallStatus returns the overall status after applying the policy. Without a policy,
+// or when the response has no components (e.g. liveness), the reported status is returned.
+func (p ComponentPolicy) OverallStatus(parsed *ParsedHealthData) HealthStatus {
+	if parsed == nil {
+		return StatusUNKNOWN
+	}
+	if p.IsZero() || len(parsed.ComponentDetails) == 0 {
+		return parsed.OverallStatus
+	}
+
+	overall := StatusUP
+	for _, component := range parsed.ComponentDetails {
+		overall = WorseStatus(overall, p.componentStatus(component, component.Name))
+	}
+	return overall
+}
+
+// componentStatus evaluates a component by its reported status. Only when the policy covers one
+// of its sub-components (extensions) is it judged by its children instead, so single extensions
+// can be ignored without hiding the component's own status otherwise.
+func (p ComponentPolicy) componentStatus(component ComponentStatus, name string) HealthStatus {
+	if matchesComponent(p.Ignore, name) {
+		return StatusUP
+	}
+
+	status := component.Status
+	if p.coversDescendant(component, name) {
+		status = StatusUP
+		for _, sub := range component.SubComponents {
+			status = WorseStatus(status, p.componentStatus(sub, name+"."+sub.Name))
+		}
+	}
+
+	if matchesComponent(p.WarnOnly, name) && status != StatusUP {
+		return StatusDEGRADED
+	}
+	return status
+}
+
+// coversDescendant reports whether an ignore or warn-only pattern matches a sub-component
+func (p ComponentPolicy) coversDescendant(component ComponentStatus, name string) bool {
+	for _, sub := range component.SubComponents {
+		subName := name + "." + sub.Name
+		if matchesComponent(p.Ignore, subName) || matchesComponent(p.WarnOnly, subName) || p.coversDescendant(sub, subName) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesComponent(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// WorseStatus returns the more severe status, ordered UP < DEGRADED < UNKNOWN < OUT_OF_SERVICE < DOWN
+func WorseStatus(a, b HealthStatus) HealthStatus {
+	if statusSeverity(b) > statusSeverity(a) {
+		return b
+	}
+	return a
+}
+
+func statusSeverity(status HealthStatus) int {
+	switch status {
+	case StatusUP:
+		return 0
+	case StatusDEGRADED:
+		return 1
+	case StatusUNKNOWN:
+		return 2
+	case StatusOUTOFSERVICE:
+		return 3
+	default:
+		return 4
+	}
+}
@@ -0,0 +1,84 @@
+package health
+
+import "testing"
+
+func TestComponentPolicyOverallStatus(t *testing.T) {
+	t.Parallel()
+
+	extensions := func(parent, metering, bridge HealthStatus) ComponentStatus {
+		return ComponentStatus{Name: "extensions", Status: parent, SubComponents: []ComponentStatus{
+			{Name: "hivemq-metering-extension", Status: metering},
+			{Name: "hivemq-bridge-extension", Status: bridge},
+		}}
+	}
+	health := func(overall HealthStatus, components ...ComponentStatus) *ParsedHealthData {
+		return &ParsedHealthData{OverallStatus: overall, ComponentDetails: components}
+	}
+
+	cases := []struct {
+		name   string
+		policy ComponentPolicy
+		parsed *ParsedHealthData
+		want   HealthStatus
+	}{
+		{
+			name:   "no response",
+			policy: ComponentPolicy{Ignore: []string{"cluster"}},
+			want:   StatusUNKNOWN,
+		},
+		{
+			name:   "no policy keeps the reported status",
+			parsed: health(StatusDOWN, ComponentStatus{Name: "cluster", Status: StatusUP}),
+			want:   StatusDOWN,
+		},
+		{
+			name:   "ignored component",
+			policy: ComponentPolicy{Ignore: []string{"mqtt"}},
+			parsed: health(StatusDOWN, ComponentStatus{Name: "cluster", Status: StatusUP}, ComponentStatus{Name: "mqtt", Status: StatusDOWN}),
+			want:   StatusUP,
+		},
+		{
+			name:   "warn-only component",
+			policy: ComponentPolicy{WarnOnly: []string{"mqtt"}},
+			parsed: health(StatusDOWN, ComponentStatus{Name: "cluster", Status: StatusUP}, ComponentStatus{Name: "mqtt", Status: StatusDOWN}),
+			want:   StatusDEGRADED,
+		},
+		{
+			name:   "ignored extension",
+			policy: ComponentPolicy{Ignore: []string{"extensions.*-metering-*"}},
+			parsed: health(StatusDOWN, extensions(StatusDOWN, StatusDOWN, StatusUP)),
+			want:   StatusUP,
+		},
+		{
+			name:   "warn-only extension",
+			policy: ComponentPolicy{WarnOnly: []string{"extensions.hivemq-metering-extension"}},
+			parsed: health(StatusDOWN, extensions(StatusDOWN, StatusDOWN, StatusUP)),
+			want:   StatusDEGRADED,
+		},
+		{
+			name:   "ignored extension next to a failing one",
+			policy: ComponentPolicy{Ignore: []string{"extensions.hivemq-metering-extension"}},
+			parsed: health(StatusDOWN, extensions(StatusDOWN, StatusDOWN, StatusDOWN)),
+			want:   StatusDOWN,
+		},
+		{
+			name:   "down parent with up children and an unrelated policy",
+			policy: ComponentPolicy{Ignore: []string{"mqtt"}},
+			parsed: health(StatusDOWN, ComponentStatus{Name: "cluster", Status: StatusUP}, extensions(StatusDOWN, StatusUP, StatusUP)),
+			want:   StatusDOWN,
+		},
+		{
+			name:   "degraded parent with up children and an unrelated warn-only",
+			policy: ComponentPolicy{WarnOnly: []string{"cluster"}},
+			parsed: health(StatusDEGRADED, ComponentStatus{Name: "cluster", Status: StatusUP}, extensions(StatusDEGRADED, StatusUP, StatusUP)),
+			want:   StatusDEGRADED,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.policy.OverallStatus(tc.parsed); got != tc.want {
+				t.Fatalf("OverallStatus = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package health
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
+
+	"kubectl-broker/pkg/transport"
 )
 
 // HealthStatus represents the overall health status values from HiveMQ
@@ -55,11 +58,35 @@ func (hs HealthStatus) Validate() error {
 	return nil
 }
 
+// SchemaVersion identifies the shape of a health API response
+type SchemaVersion string
+
+const (
+	// SchemaV1 is the shape of HiveMQ 4.x: components with status and details, where only
+	// extensions carry nested components
+	SchemaV1 SchemaVersion = "v1"
+	// SchemaV2 nests component trees below any component, as newer broker versions do
+	SchemaV2 SchemaVersion = "v2"
+)
+
 // HealthResponse represents the structure of HiveMQ health API responses
 type HealthResponse struct {
 	Status     HealthStatus               `json:"status"`
 	Components map[string]ComponentHealth `json:"components,omitempty"`
 	Details    map[string]interface{}     `json:"details,omitempty"`
+	// Extra keeps fields this parser does not know, so newer responses lose nothing
+	Extra map[string]interface{} `json:"-"`
+}
+
+// UnmarshalJSON decodes the known fields and keeps all others in Extra
+func (r *HealthResponse) UnmarshalJSON(data []byte) error {
+	type plain HealthResponse
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data)
+	r.Extra = extra
+	return err
 }
 
 // ComponentHealth represents health information for individual components
@@ -67,6 +94,34 @@ type ComponentHealth struct {
 	Status     HealthStatus               `json:"status"`
 	Details    map[string]interface{}     `json:"details,omitempty"`
 	Components map[string]ComponentHealth `json:"components,omitempty"`
+	// Extra keeps fields this parser does not know, so newer responses lose nothing
+	Extra map[string]interface{} `json:"-"`
+}
+
+// UnmarshalJSON decodes the known fields and keeps all others in Extra
+func (c *ComponentHealth) UnmarshalJSON(data []byte) error {
+	type plain ComponentHealth
+	if err := json.Unmarshal(data, (*plain)(c)); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data)
+	c.Extra = extra
+	return err
+}
+
+// unknownFields returns the fields of a JSON object other than status, details and components
+func unknownFields(data []byte) (map[string]interface{}, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for _, known := range []string{"status", "details", "components"} {
+		delete(fields, known)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
 }
 
 // ParsedHealthData represents analyzed health information for display
@@ -79,6 +134,13 @@ type ParsedHealthData struct {
 	UnhealthyComponents int          `validate:"min=0"`
 	ComponentDetails    []ComponentStatus
 	RawJSON             []byte
+	// SchemaVersion is the response shape detected while parsing
+	SchemaVersion SchemaVersion
+	// Unrecognized lists statuses outside the known set as "component.sub-component: STATUS",
+	// named like --ignore-component; such components count as unhealthy unless parsing is strict
+	Unrecognized []string
+	// Extra holds the top-level response fields the parser does not know
+	Extra map[string]interface{}
 }
 
 // Validate validates the ParsedHealthData
@@ -139,6 +201,8 @@ type ComponentStatus struct {
 	Status        HealthStatus `validate:"required"`
 	Details       string
 	SubComponents []ComponentStatus // For nested components like individual extensions
+	// Extra holds the fields of the component the parser does not know
+	Extra map[string]interface{}
 }
 
 // Validate validates the ComponentStatus
@@ -169,12 +233,36 @@ func (cs *ComponentStatus) Validate() error {
 
 // HealthCheckOptions configures how health checks are performed and displayed
 type HealthCheckOptions struct {
-	Endpoint   string        `validate:"required,oneof=health liveness readiness"` // health endpoint to query (health, liveness, readiness)
-	OutputJSON bool          // output raw JSON instead of parsed data
-	OutputRaw  bool          // output unprocessed response
-	Detailed   bool          // show detailed component breakdown
-	Timeout    time.Duration `validate:"min=1s,max=300s"` // timeout for health check requests
-	UseColors  bool          // enable colored output for health status
+	Endpoint   string               `validate:"required,oneof=health liveness readiness"` // health endpoint to query (health, liveness, readiness)
+	OutputJSON bool                 // output raw JSON instead of parsed data
+	OutputRaw  bool                 // output unprocessed response
+	Detailed   bool                 // show detailed component breakdown
+	Timeout    time.Duration        `validate:"min=1s,max=300s"` // timeout for health check requests
+	UseColors  bool                 // enable colored output for health status
+	TLS        transport.TLSOptions // TLS settings for the health endpoint
+	Policy     ComponentPolicy      // component ignore/warn-only rules for the overall status
+	Path       string               // explicit request path, overrides Endpoint (e.g. /custom/health)
+	BasePath   string               // health API prefix discovered from the broker config (defaults to /api/v1/health)
+
+	// MaxFailures stops a concurrent check of several pods once this many checks have failed
+	// and reports the remaining pods as skipped (0 checks every pod)
+	MaxFailures int
+
+	// Strict fails a check whose response contains a status outside the known set instead of
+	// counting the component as unhealthy
+	Strict bool
+
+	// Samples is the number of health requests sent to each pod through one port-forward to
+	// measure request latency; the last response is reported (0 and 1 send a single request)
+	Samples int
+}
+
+// RequestPath returns the HTTP path to query for these options
+func (opts HealthCheckOptions) RequestPath() string {
+	if opts.Path != "" {
+		return opts.Path
+	}
+	return ResolveHealthEndpointPath(opts.Endpoint, opts.BasePath)
 }
 
 // Validate validates the HealthCheckOptions
@@ -0,0 +1,146 @@
+package pkg
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg/health"
+)
+
+// DefaultHiveMQHome is the HiveMQ installation directory of the official images
+const DefaultHiveMQHome = "/opt/hivemq"
+
+// Sources of a discovered health endpoint
+const (
+	HealthEndpointFromContainerPort = "container port"
+	HealthEndpointFromConfig        = "config.xml"
+)
+
+// HealthEndpoint is where a broker serves its health API
+type HealthEndpoint struct {
+	Port int32
+	// BasePath replaces the default /api/v1/health prefix when the config sets one
+	BasePath string
+	TLS      bool
+	Source   string
+}
+
+// Apply points health check options at this endpoint. An explicit --endpoint-path still wins
+// over a discovered base path, and HTTPS listeners switch the check to TLS.
+func (e *HealthEndpoint) Apply(options health.HealthCheckOptions) health.HealthCheckOptions {
+	if e == nil {
+		return options
+	}
+	options.BasePath = e.BasePath
+	if e.TLS {
+		options.TLS.Enabled = true
+	}
+	return options
+}
+
+// healthAPIConfig is the <health-api> section of config.xml
+type healthAPIConfig struct {
+	HealthAPI *struct {
+		Enabled   string `xml:"enabled"`
+		Path      string `xml:"path"`
+		Listeners struct {
+			HTTP  []healthListenerConfig `xml:"http"`
+			HTTPS []healthListenerConfig `xml:"https"`
+		} `xml:"listeners"`
+	} `xml:"health-api"`
+}
+
+type healthListenerConfig struct {
+	Port string `xml:"port"`
+	Path string `xml:"path"`
+}
+
+// DiscoverHealthEndpoint finds the health API of a pod. A container port named "health" (or
+// the SetPortNames override) wins; otherwise the <health-api> section of config.xml is read
+// from the broker container, which also covers images serving health on a non-standard port
+// or path.
+func (k *K8sClient) DiscoverHealthEndpoint(ctx context.Context, pod *v1.Pod) (*HealthEndpoint, error) {
+	port, portErr := k.DiscoverHealthPort(pod)
+	if portErr == nil {
+		return &HealthEndpoint{Port: port, Source: HealthEndpointFromContainerPort}, nil
+	}
+
+	config, err := k.GetBrokerConfig(ctx, pod, DefaultHiveMQHome)
+	if err != nil {
+		slog.Debug("Could not read config.xml for health port discovery", "pod", pod.Name, "error", err)
+		return nil, portErr
+	}
+	endpoint, err := ParseHealthAPIConfig(config.ConfigXML, config.Env)
+	if err != nil {
+		slog.Debug("No usable health API in config.xml", "pod", pod.Name, "error", err)
+		return nil, portErr
+	}
+	slog.Debug("Discovered health endpoint from config.xml", "pod", pod.Name, "port", endpoint.Port, "path", endpoint.BasePath)
+	return endpoint, nil
+}
+
+// ParseHealthAPIConfig extracts the health API port and path from config.xml. ${ENV:NAME} and
+// ${NAME} placeholders are resolved from env. Plain HTTP listeners are preferred over HTTPS.
+func ParseHealthAPIConfig(configXML string, env map[string]string) (*HealthEndpoint, error) {
+	var config healthAPIConfig
+	if err := xml.Unmarshal([]byte(configXML), &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config.xml: %w", err)
+	}
+
+	healthAPI := config.HealthAPI
+	if healthAPI == nil {
+		return nil, fmt.Errorf("config.xml has no <health-api> section")
+	}
+	if enabled := resolvePlaceholders(healthAPI.Enabled, env); strings.EqualFold(enabled, "false") {
+		return nil, fmt.Errorf("the health API is disabled in config.xml")
+	}
+
+	listener, tls := healthListenerConfig{}, false
+	switch {
+	case len(healthAPI.Listeners.HTTP) > 0:
+		listener = healthAPI.Listeners.HTTP[0]
+	case len(healthAPI.Listeners.HTTPS) > 0:
+		listener, tls = healthAPI.Listeners.HTTPS[0], true
+	default:
+		return nil, fmt.Errorf("the <health-api> section has no listener")
+	}
+
+	portValue := resolvePlaceholders(listener.Port, env)
+	port, err := strconv.ParseInt(portValue, 10, 32)
+	if err != nil || port <= 0 || port > 65535 {
+		return nil, fmt.Errorf("invalid health API port %q", portValue)
+	}
+
+	basePath := resolvePlaceholders(listener.Path, env)
+	if basePath == "" {
+		basePath = resolvePlaceholders(healthAPI.Path, env)
+	}
+	if basePath != "" && !strings.HasPrefix(basePath, "/") {
+		basePath = "/" + basePath
+	}
+
+	return &HealthEndpoint{
+		Port:     int32(port),
+		BasePath: strings.TrimRight(basePath, "/"),
+		TLS:      tls,
+		Source:   HealthEndpointFromConfig,
+	}, nil
+}
+
+// resolvePlaceholders substitutes environment placeholders; unknown variables stay as they are
+func resolvePlaceholders(value string, env map[string]string) string {
+	resolved := configPlaceholder.ReplaceAllStringFunc(strings.TrimSpace(value), func(match string) string {
+		name := configPlaceholder.FindStringSubmatch(match)[1]
+		if replacement, ok := env[name]; ok {
+			return replacement
+		}
+		return match
+	})
+	return strings.TrimSpace(resolved)
+}
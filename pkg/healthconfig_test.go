@@ -0,0 +1,64 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+
+	"kubectl-broker/pkg/health"
+)
+
+func TestParseHealthAPIConfig(t *testing.T) {
+	t.Parallel()
+
+	configXML := `<?xml version="1.0"?>
+<hivemq>
+  <listeners><tcp-listener><port>1883</port></tcp-listener></listeners>
+  <health-api>
+    <enabled>true</enabled>
+    <listeners>
+      <http>
+        <port>${ENV:HEALTH_PORT}</port>
+        <path>status/</path>
+      </http>
+    </listeners>
+  </health-api>
+</hivemq>`
+
+	endpoint, err := ParseHealthAPIConfig(configXML, map[string]string{"HEALTH_PORT": "9191"})
+	if err != nil {
+		t.Fatalf("ParseHealthAPIConfig returned error: %v", err)
+	}
+	if endpoint.Port != 9191 || endpoint.BasePath != "/status" || endpoint.TLS || endpoint.Source != HealthEndpointFromConfig {
+		t.Fatalf("unexpected endpoint: %+v", endpoint)
+	}
+
+	options := endpoint.Apply(health.HealthCheckOptions{Endpoint: "liveness"})
+	if got := options.RequestPath(); got != "/status/liveness" {
+		t.Fatalf("RequestPath() = %q, want /status/liveness", got)
+	}
+	options.Path = "/override"
+	if got := options.RequestPath(); got != "/override" {
+		t.Fatalf("explicit path should win, got %q", got)
+	}
+}
+
+func TestParseHealthAPIConfigRejectsUnusableSections(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"no section":     `<hivemq><listeners/></hivemq>`,
+		"disabled":       `<hivemq><health-api><enabled>false</enabled><listeners><http><port>8889</port></http></listeners></health-api></hivemq>`,
+		"no listener":    `<hivemq><health-api><enabled>true</enabled></health-api></hivemq>`,
+		"unresolved env": `<hivemq><health-api><listeners><http><port>${HEALTH_PORT}</port></http></listeners></health-api></hivemq>`,
+	}
+	for name, configXML := range cases {
+		if _, err := ParseHealthAPIConfig(configXML, nil); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	endpoint, err := ParseHealthAPIConfig(`<hivemq><health-api><listeners><https><port>8443</port></https></listeners></health-api></hivemq>`, nil)
+	if err != nil || !endpoint.TLS || endpoint.Port != 8443 || !strings.HasPrefix(health.ResolveHealthEndpointPath("health", endpoint.BasePath), "/api/v1/health") {
+		t.Fatalf("unexpected HTTPS endpoint %+v: %v", endpoint, err)
+	}
+}
@@ -0,0 +1,124 @@
+package history
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestStoreAppendAndLoadFiltersRuns(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore returned error: %v", err)
+	}
+
+	now := time.Now().UTC()
+	runs := []Run{
+		{Timestamp: now.Add(-48 * time.Hour), Namespace: "prod", StatefulSet: "broker", Pods: []PodRecord{{Pod: "broker-0", Status: "HEALTHY"}}},
+		{Timestamp: now.Add(-time.Hour), Namespace: "prod", StatefulSet: "broker", Pods: []PodRecord{{Pod: "broker-0", Status: "DEGRADED"}}},
+		{Timestamp: now.Add(-time.Hour), Namespace: "staging", StatefulSet: "broker", Pods: []PodRecord{{Pod: "broker-0", Status: "HEALTHY"}}},
+	}
+	for _, run := range runs {
+		if err := store.Append(run); err != nil {
+			t.Fatalf("Append returned error: %v", err)
+		}
+	}
+
+	// A truncated trailing line must not break loading
+	file, err := os.OpenFile(store.Path(), os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("open history file: %v", err)
+	}
+	_, _ = file.WriteString(`{"timestamp":`)
+	_ = file.Close()
+
+	loaded, err := store.Load(Filter{Since: now.Add(-24 * time.Hour), Namespace: "prod"})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].Pods[0].Status != "DEGRADED" {
+		t.Fatalf("unexpected runs loaded: %+v", loaded)
+	}
+}
+
+func TestSummarizeDetectsFlappingAndTrend(t *testing.T) {
+	t.Parallel()
+
+	statuses := []string{"HEALTHY", "HEALTHY", "DEGRADED", "HEALTHY", "DEGRADED", "DEGRADED"}
+	runs := make([]Run, 0, len(statuses))
+	for i, status := range statuses {
+		runs = append(runs, Run{
+			Timestamp: time.Unix(int64(i), 0),
+			Pods: []PodRecord{
+				{Pod: "broker-1", Status: status},
+				{Pod: "broker-0", Status: "HEALTHY"},
+			},
+		})
+	}
+
+	summaries := Summarize(runs, 3)
+	if len(summaries) != 2 || summaries[0].Pod != "broker-0" {
+		t.Fatalf("unexpected summaries: %+v", summaries)
+	}
+
+	stable, flapping := summaries[0], summaries[1]
+	if stable.Flapping || stable.Trend != TrendStable || stable.HealthyPercent() != 100 {
+		t.Fatalf("expected broker-0 to be stable, got %+v", stable)
+	}
+	if !flapping.Flapping || flapping.Transitions != 3 {
+		t.Fatalf("expected broker-1 to flap with 3 transitions, got %+v", flapping)
+	}
+	if flapping.Trend != TrendDegrading || flapping.LastStatus != "DEGRADED" {
+		t.Fatalf("expected broker-1 to be degrading, got %+v", flapping)
+	}
+}
+
+func TestStoreLoadSortsRunsByTime(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore returned error: %v", err)
+	}
+
+	now := time.Now().UTC()
+	for _, offset := range []time.Duration{-time.Minute, -3 * time.Minute, -2 * time.Minute} {
+		if err := store.Append(Run{Timestamp: now.Add(offset), Namespace: "prod"}); err != nil {
+			t.Fatalf("Append returned error: %v", err)
+		}
+	}
+
+	loaded, err := store.Load(Filter{})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	for i := 1; i < len(loaded); i++ {
+		if loaded[i].Timestamp.Before(loaded[i-1].Timestamp) {
+			t.Fatalf("runs not in chronological order: %v before %v", loaded[i-1].Timestamp, loaded[i].Timestamp)
+		}
+	}
+}
+
+func TestSummarizeSeparatesPodsByNamespace(t *testing.T) {
+	t.Parallel()
+
+	var runs []Run
+	for i := 0; i < 4; i++ {
+		runs = append(runs,
+			Run{Timestamp: time.Unix(int64(i), 0), Namespace: "prod", StatefulSet: "broker", Pods: []PodRecord{{Pod: "broker-0", Status: "HEALTHY"}}},
+			Run{Timestamp: time.Unix(int64(i), 0), Namespace: "staging", StatefulSet: "broker", Pods: []PodRecord{{Pod: "broker-0", Status: "DEGRADED"}}},
+		)
+	}
+
+	summaries := Summarize(runs, 3)
+	if len(summaries) != 2 || summaries[0].Namespace != "prod" || summaries[1].Namespace != "staging" {
+		t.Fatalf("expected one summary per namespace, got %+v", summaries)
+	}
+	for _, summary := range summaries {
+		if summary.Transitions != 0 || summary.Flapping {
+			t.Errorf("%s/%s merged with another namespace: %+v", summary.Namespace, summary.Pod, summary)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package history
+
+import (
+	"sort"
+	"time"
+)
+
+// Trend describes how a pod's health changed over the reporting window
+type Trend string
+
+const (
+	TrendStable    Trend = "STABLE"
+	TrendImproving Trend = "IMPROVING"
+	TrendDegrading Trend = "DEGRADING"
+)
+
+// HealthyStatus is the status recorded for healthy pods
+const HealthyStatus = "HEALTHY"
+
+// DefaultFlapThreshold is the number of status changes that marks a pod as flapping
+const DefaultFlapThreshold = 3
+
+// PodSummary aggregates the recorded checks of a single pod
+type PodSummary struct {
+	Namespace    string
+	StatefulSet  string
+	Pod          string
+	Checks       int
+	Healthy      int
+	Transitions  int
+	StatusCounts map[string]int
+	LastStatus   string
+	LastSeen     time.Time
+	Flapping     bool
+	Trend        Trend
+}
+
+// HealthyPercent returns the share of healthy checks
+func (p PodSummary) HealthyPercent() float64 {
+	if p.Checks == 0 {
+		return 0
+	}
+	return float64(p.Healthy) / float64(p.Checks) * 100.0
+}
+
+// podKey identifies a pod across runs; pod names such as broker-0 repeat across namespaces and
+// StatefulSets
+type podKey struct {
+	namespace   string
+	statefulSet string
+	pod         string
+}
+
+// Summarize builds per-pod summaries from chronologically ordered runs
+func Summarize(runs []Run, flapThreshold int) []PodSummary {
+	if flapThreshold <= 0 {
+		flapThreshold = DefaultFlapThreshold
+	}
+
+	statuses := make(map[podKey][]string)
+	lastSeen := make(map[podKey]time.Time)
+	for _, run := range runs {
+		for _, pod := range run.Pods {
+			key := podKey{namespace: run.Namespace, statefulSet: run.StatefulSet, pod: pod.Pod}
+			statuses[key] = append(statuses[key], pod.Status)
+			lastSeen[key] = run.Timestamp
+		}
+	}
+
+	summaries := make([]PodSummary, 0, len(statuses))
+	for key, history := range statuses {
+		summary := PodSummary{
+			Namespace:    key.namespace,
+			StatefulSet:  key.statefulSet,
+			Pod:          key.pod,
+			Checks:       len(history),
+			StatusCounts: make(map[string]int),
+			LastStatus:   history[len(history)-1],
+			LastSeen:     lastSeen[key],
+		}
+		for i, status := range history {
+			summary.StatusCounts[status]++
+			if status == HealthyStatus {
+				summary.Healthy++
+			}
+			if i > 0 && status != history[i-1] {
+				summary.Transitions++
+			}
+		}
+		summary.Flapping = summary.Transitions >= flapThreshold
+		summary.Trend = trendOf(history)
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.StatefulSet != b.StatefulSet {
+			return a.StatefulSet < b.StatefulSet
+		}
+		return a.Pod < b.Pod
+	})
+
+	return summaries
+}
+
+// trendOf compares the healthy ratio of the older and newer half of the checks
+func trendOf(history []string) Trend {
+	if len(history) < 4 {
+		return TrendStable
+	}
+
+	mid := len(history) / 2
+	older := healthyRatio(history[:mid])
+	newer := healthyRatio(history[mid:])
+
+	switch {
+	case newer-older >= 0.2:
+		return TrendImproving
+	case older-newer >= 0.2:
+		return TrendDegrading
+	default:
+		return TrendStable
+	}
+}
+
+func healthyRatio(history []string) float64 {
+	healthy := 0
+	for _, status := range history {
+		if status == HealthyStatus {
+			healthy++
+		}
+	}
+	return float64(healthy) / float64(len(history))
+}
@@ -0,0 +1,106 @@
+package history
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"kubectl-broker/pkg/statedir"
+)
+
+// DirEnvVar overrides the default history directory
+const DirEnvVar = "KUBECTL_BROKER_HISTORY_DIR"
+
+const historyFileName = "health.jsonl"
+
+// PodRecord is the outcome of a health check for a single pod
+type PodRecord struct {
+	Pod            string `json:"pod"`
+	Status         string `json:"status"`
+	ResponseTimeMs int64  `json:"responseTimeMs,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// Run is a single recorded status invocation
+type Run struct {
+	Timestamp   time.Time   `json:"timestamp"`
+	Namespace   string      `json:"namespace"`
+	StatefulSet string      `json:"statefulSet,omitempty"`
+	Endpoint    string      `json:"endpoint,omitempty"`
+	Pods        []PodRecord `json:"pods"`
+}
+
+// Filter selects runs when loading history
+type Filter struct {
+	Since       time.Time
+	Namespace   string
+	StatefulSet string
+}
+
+// Store persists status runs as JSON lines in a local directory
+type Store struct {
+	dir string
+}
+
+// DefaultDir returns the history directory, honoring KUBECTL_BROKER_HISTORY_DIR
+func DefaultDir() (string, error) {
+	return statedir.Dir(DirEnvVar, "history", "health history")
+}
+
+// NewStore creates a store rooted at dir (DefaultDir when empty)
+func NewStore(dir string) (*Store, error) {
+	if dir == "" {
+		var err error
+		dir, err = DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Path returns the file backing the store
+func (s *Store) Path() string {
+	return filepath.Join(s.dir, historyFileName)
+}
+
+// Append records a run at the end of the history file
+func (s *Store) Append(run Run) error {
+	return s.file().Append(run)
+}
+
+func (s *Store) file() statedir.JSONLines {
+	return statedir.JSONLines{Path: s.Path(), Label: "history file", DirMode: 0o755, FileMode: 0o644}
+}
+
+// Load returns the runs matching the filter in chronological order.
+// Malformed lines are skipped so a partially written record does not break reporting.
+func (s *Store) Load(filter Filter) ([]Run, error) {
+	var runs []Run
+	err := s.file().Scan(func(line []byte) {
+		var run Run
+		if err := json.Unmarshal(line, &run); err != nil {
+			return
+		}
+		if !filter.Since.IsZero() && run.Timestamp.Before(filter.Since) {
+			return
+		}
+		if filter.Namespace != "" && run.Namespace != filter.Namespace {
+			return
+		}
+		if filter.StatefulSet != "" && run.StatefulSet != filter.StatefulSet {
+			return
+		}
+		runs = append(runs, run)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Concurrent status commands append out of order
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].Timestamp.Before(runs[j].Timestamp)
+	})
+	return runs, nil
+}
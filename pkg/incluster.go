@@ -0,0 +1,90 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/rest"
+
+	"kubectl-broker/pkg/logging"
+)
+
+// serviceAccountNamespaceFile is mounted into every pod that has a service account token
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// K8sClientOptions controls how NewK8sClientWithOptions finds the cluster
+type K8sClientOptions struct {
+	ShowDebug bool          // log the kubeconfig, cluster and context in use at info instead of debug level
+	InCluster bool          // use the pod's service account instead of a kubeconfig
+	CacheTTL  time.Duration // reuse read-only StatefulSet, pod and service lookups for this long; 0 disables
+
+	Impersonate       string   // act as this user, like kubectl --as (optional)
+	ImpersonateGroups []string // act with these groups, like kubectl --as-group; requires Impersonate
+}
+
+// InClusterAvailable reports whether the process runs inside a pod with a service account token
+func InClusterAvailable() bool {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" || os.Getenv("KUBERNETES_SERVICE_PORT") == "" {
+		return false
+	}
+	_, err := os.Stat(serviceAccountNamespaceFile)
+	return err == nil
+}
+
+// InClusterNamespace returns the namespace of the pod's service account
+func InClusterNamespace() (string, error) {
+	data, err := os.ReadFile(serviceAccountNamespaceFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read service account namespace: %w", err)
+	}
+	namespace := strings.TrimSpace(string(data))
+	if namespace == "" {
+		return "", fmt.Errorf("service account namespace file %s is empty", serviceAccountNamespaceFile)
+	}
+	return namespace, nil
+}
+
+// restConfig resolves the cluster connection: the service account when requested, otherwise
+// the kubeconfig, falling back to the service account when no kubeconfig can be loaded in a pod
+func restConfig(options K8sClientOptions) (*rest.Config, error) {
+	config, err := baseRESTConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	if options.Impersonate != "" {
+		config.Impersonate = rest.ImpersonationConfig{UserName: options.Impersonate, Groups: options.ImpersonateGroups}
+		slog.Log(context.Background(), logging.DetailLevel(options.ShowDebug), "Impersonating", "user", options.Impersonate, "groups", options.ImpersonateGroups)
+	}
+	return config, nil
+}
+
+func baseRESTConfig(options K8sClientOptions) (*rest.Config, error) {
+	if options.InCluster {
+		return inClusterRESTConfig(options.ShowDebug)
+	}
+
+	config, err := kubeconfigRESTConfig(options.ShowDebug)
+	if err != nil && InClusterAvailable() && KubeContextOverride() == "" {
+		slog.Log(context.Background(), logging.DetailLevel(options.ShowDebug), "Kubeconfig unavailable, using in-cluster service account", "reason", err)
+		return inClusterRESTConfig(options.ShowDebug)
+	}
+	return config, err
+}
+
+func inClusterRESTConfig(showDebug bool) (*rest.Config, error) {
+	config, err := rest.InClusterConfig()
+	if errors.Is(err, rest.ErrNotInCluster) {
+		return nil, fmt.Errorf("in-cluster mode requested but not running inside a Kubernetes pod: %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load in-cluster config: %w", err)
+	}
+
+	slog.Log(context.Background(), logging.DetailLevel(showDebug), "Using in-cluster service account", "server", config.Host)
+	return config, nil
+}
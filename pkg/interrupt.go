@@ -0,0 +1,68 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+)
+
+// ErrInterrupted is the cancellation cause of a command context ended by SIGINT or SIGTERM
+var ErrInterrupted = errors.New("command interrupted")
+
+// interruptions lists the operations stopped by an interrupt, in the order they were stopped
+var interruptions struct {
+	sync.Mutex
+	stopped []string
+}
+
+// WithInterrupt returns a context cancelled by the first SIGINT or SIGTERM, with a cause
+// wrapping ErrInterrupted. The handler is removed on that signal, so a second one terminates
+// the process immediately. stop releases the handler and the context.
+func WithInterrupt(parent context.Context) (ctx context.Context, stop context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-signals:
+			signal.Stop(signals)
+			cancel(fmt.Errorf("%w (signal: %s)", ErrInterrupted, sig))
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel(nil)
+	}
+}
+
+// Interrupted reports whether ctx was cancelled by WithInterrupt's signal handler
+func Interrupted(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrInterrupted)
+}
+
+// RecordInterruption notes that an operation stopped because ctx was interrupted, including
+// what was cleaned up or left behind. It does nothing while ctx is not interrupted, so
+// operations call it on every failure path.
+func RecordInterruption(ctx context.Context, description string) {
+	if !Interrupted(ctx) {
+		return
+	}
+	interruptions.Lock()
+	defer interruptions.Unlock()
+	interruptions.stopped = append(interruptions.stopped, description)
+}
+
+// InterruptedOperations returns the operations recorded by RecordInterruption
+func InterruptedOperations() []string {
+	interruptions.Lock()
+	defer interruptions.Unlock()
+	return slices.Clone(interruptions.stopped)
+}
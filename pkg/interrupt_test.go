@@ -0,0 +1,45 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWithInterrupt(t *testing.T) {
+	ctx, stop := WithInterrupt(context.Background())
+	defer stop()
+
+	RecordInterruption(ctx, "ignored before the signal")
+	if Interrupted(ctx) {
+		t.Fatal("context must not be interrupted before a signal")
+	}
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatalf("failed to send SIGINT: %v", err)
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context was not cancelled by SIGINT")
+	}
+	if !Interrupted(ctx) || !errors.Is(context.Cause(ctx), ErrInterrupted) {
+		t.Fatalf("cause = %v, want ErrInterrupted", context.Cause(ctx))
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	RecordInterruption(timeout, "port-forward to broker-0:8080 closed")
+	if got := InterruptedOperations(); !slices.Equal(got, []string{"port-forward to broker-0:8080 closed"}) {
+		t.Fatalf("InterruptedOperations() = %v", got)
+	}
+
+	stopped, stopOther := WithInterrupt(context.Background())
+	stopOther()
+	if Interrupted(stopped) {
+		t.Fatal("stop must not mark the context as interrupted")
+	}
+}
@@ -0,0 +1,138 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FindHiveMQPIDScript locates the broker JVM inside the container and stores its PID in $PID
+const FindHiveMQPIDScript = `PID=$(jcmd -l | awk '/hivemq/ {print $1; exit}'); [ -n "$PID" ] || { echo "HiveMQ JVM not found (is jcmd available in the image?)" >&2; exit 1; }`
+
+// DumpType selects the JVM dump collected from a broker
+type DumpType string
+
+const (
+	DumpHeap   DumpType = "heap"
+	DumpThread DumpType = "thread"
+)
+
+// DumpTypes lists the supported dump types
+var DumpTypes = []DumpType{DumpHeap, DumpThread}
+
+// ParseDumpType validates a --type value
+func ParseDumpType(value string) (DumpType, error) {
+	for _, dumpType := range DumpTypes {
+		if strings.EqualFold(value, string(dumpType)) {
+			return dumpType, nil
+		}
+	}
+	return "", fmt.Errorf("invalid dump type %q: use heap or thread", value)
+}
+
+// Extension is the file extension of a dump of this type
+func (t DumpType) Extension() string {
+	if t == DumpHeap {
+		return ".hprof"
+	}
+	return ".txt"
+}
+
+// DumpFileName names a dump of pod taken at a point in time
+func DumpFileName(pod string, dumpType DumpType, at time.Time) string {
+	return fmt.Sprintf("%s-%s-%s%s", pod, dumpType, at.UTC().Format("20060102-150405"), dumpType.Extension())
+}
+
+// JVMDumpScript writes a dump of the broker JVM to remotePath with jcmd and prints its size
+func JVMDumpScript(dumpType DumpType, remotePath string) string {
+	quoted := shellQuote(remotePath)
+	var dump string
+	switch dumpType {
+	case DumpHeap:
+		dump = fmt.Sprintf(`jcmd "$PID" GC.heap_dump %s >&2`, quoted)
+	default:
+		dump = fmt.Sprintf(`jcmd "$PID" Thread.print > %s`, quoted)
+	}
+	return fmt.Sprintf(`%s && mkdir -p %s && %s && [ -s %s ] && wc -c < %s`,
+		FindHiveMQPIDScript, shellQuote(path.Dir(remotePath)), dump, quoted, quoted)
+}
+
+// JVMDumpOptions configures CollectJVMDump
+type JVMDumpOptions struct {
+	Namespace  string
+	Pod        string
+	Container  string
+	Type       DumpType
+	RemotePath string
+	// KeepRemote leaves the dump file in the container after the download
+	KeepRemote bool
+	// Progress is called with the bytes copied so far and the dump size
+	Progress func(written, total int64)
+}
+
+// CollectJVMDump triggers a dump of the broker JVM inside the container, streams the file to w
+// and removes it from the container again, also when the download fails. It returns the number
+// of bytes written.
+func (k *K8sClient) CollectJVMDump(ctx context.Context, options JVMDumpOptions, w io.Writer) (int64, error) {
+	output, err := k.ExecCommandInContainer(ctx, options.Namespace, options.Pod, options.Container,
+		[]string{"sh", "-c", JVMDumpScript(options.Type, options.RemotePath)})
+	if !options.KeepRemote {
+		// The dump may be partially written when jcmd fails, so clean up in any case
+		defer k.removeRemoteFile(ctx, options)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write %s dump in pod %s: %w", options.Type, options.Pod, err)
+	}
+	size, err := strconv.ParseInt(strings.TrimSpace(output), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected output of the dump command in pod %s: %q", options.Pod, strings.TrimSpace(output))
+	}
+
+	counter := &countingWriter{w: w, total: size, progress: options.Progress}
+	err = k.ExecCommandWithIO(ctx, options.Namespace, options.Pod, options.Container, []string{"cat", options.RemotePath}, nil, counter)
+	if err != nil {
+		return counter.written, fmt.Errorf("failed to copy %s from pod %s: %w", options.RemotePath, options.Pod, err)
+	}
+	if counter.written != size {
+		return counter.written, fmt.Errorf("copied %d of %d bytes of %s from pod %s", counter.written, size, options.RemotePath, options.Pod)
+	}
+	return counter.written, nil
+}
+
+func (k *K8sClient) removeRemoteFile(ctx context.Context, options JVMDumpOptions) {
+	// Clean up even when the command was interrupted, dumps can fill the volume
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+	if _, err := k.ExecCommandInContainer(ctx, options.Namespace, options.Pod, options.Container, []string{"rm", "-f", options.RemotePath}); err != nil {
+		slog.Warn("Failed to remove the dump from the pod", "pod", options.Pod, "path", options.RemotePath, "error", err)
+		return
+	}
+	slog.Debug("Removed the dump from the pod", "pod", options.Pod, "path", options.RemotePath)
+}
+
+// countingWriter reports the progress of a copy of known size
+type countingWriter struct {
+	w        io.Writer
+	written  int64
+	total    int64
+	progress func(written, total int64)
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.written += int64(n)
+	if c.progress != nil {
+		c.progress(c.written, c.total)
+	}
+	return n, err
+}
+
+// shellQuote quotes a value for POSIX sh
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,34 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJVMDumpScript(t *testing.T) {
+	t.Parallel()
+
+	if _, err := ParseDumpType("core"); err == nil {
+		t.Fatal("expected an error for an unknown dump type")
+	}
+	kind, err := ParseDumpType("HEAP")
+	if err != nil || kind != DumpHeap {
+		t.Fatalf("ParseDumpType(HEAP) = %q, %v", kind, err)
+	}
+
+	name := DumpFileName("broker-0", DumpHeap, time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC))
+	if name != "broker-0-heap-20240301-123000.hprof" {
+		t.Fatalf("unexpected file name %q", name)
+	}
+
+	script := JVMDumpScript(DumpHeap, "/opt/hivemq/data/it's.hprof")
+	for _, want := range []string{`GC.heap_dump '/opt/hivemq/data/it'\''s.hprof'`, `mkdir -p '/opt/hivemq/data'`, `wc -c < '/opt/hivemq/data/it'\''s.hprof'`} {
+		if !strings.Contains(script, want) {
+			t.Errorf("heap dump script misses %q:\n%s", want, script)
+		}
+	}
+	if script := JVMDumpScript(DumpThread, "/tmp/t.txt"); !strings.Contains(script, `Thread.print > '/tmp/t.txt'`) {
+		t.Errorf("thread dump script does not redirect to the file:\n%s", script)
+	}
+}
@@ -4,67 +4,79 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/scheme"
 	appsv1client "k8s.io/client-go/kubernetes/typed/apps/v1"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	discoveryclient "k8s.io/client-go/kubernetes/typed/discovery/v1"
+	policyv1client "k8s.io/client-go/kubernetes/typed/policy/v1"
+	storagev1client "k8s.io/client-go/kubernetes/typed/storage/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/remotecommand"
 	"k8s.io/client-go/util/homedir"
+
+	"kubectl-broker/pkg/logging"
+	"kubectl-broker/pkg/tracing"
+	"kubectl-broker/pkg/transport"
 )
 
 // K8sClient wraps specific Kubernetes client interfaces with helper methods
 type K8sClient struct {
-	coreClient *corev1client.CoreV1Client
-	appsClient *appsv1client.AppsV1Client
-	discovery  *discoveryclient.DiscoveryV1Client
+	coreClient corev1client.CoreV1Interface
+	appsClient appsv1client.AppsV1Interface
+	discovery  discoveryclient.DiscoveryV1Interface
+	storage    storagev1client.StorageV1Interface
+	policy     policyv1client.PolicyV1Interface
 	restClient rest.Interface
 	config     *rest.Config
+	cacheTTL   time.Duration // reuse read-only lookups for this long; 0 disables the cache
 }
 
-// NewK8sClient creates a new Kubernetes client using kubeconfig (supports kubie)
+// NewK8sClient creates a new Kubernetes client using kubeconfig (supports kubie),
+// falling back to the in-cluster service account when no kubeconfig is available
 func NewK8sClient(showDebug bool) (*K8sClient, error) {
-	// Check for kubie environment variables first
-	var kubeconfig string
-	if kubieConfig := os.Getenv("KUBIE_KUBECONFIG"); kubieConfig != "" {
-		kubeconfig = kubieConfig
-		if showDebug {
-			fmt.Printf("Using kubie kubeconfig: %s\n", kubeconfig)
-		}
-	} else if envConfig := os.Getenv("KUBECONFIG"); envConfig != "" {
-		kubeconfig = envConfig
-		if showDebug {
-			fmt.Printf("Using KUBECONFIG env var: %s\n", kubeconfig)
-		}
-	} else {
-		// Fall back to default kubeconfig
-		if home := homedir.HomeDir(); home != "" {
-			kubeconfig = filepath.Join(home, ".kube", "config")
-		}
-		if showDebug {
-			fmt.Printf("Using default kubeconfig: %s\n", kubeconfig)
-		}
-	}
+	return NewK8sClientWithOptions(K8sClientOptions{ShowDebug: showDebug})
+}
 
-	// Load kubeconfig with context information
-	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-	if kubeconfig != "" {
-		loadingRules.ExplicitPath = kubeconfig
+// NewK8sClientWithOptions creates a new Kubernetes client from the given options
+func NewK8sClientWithOptions(options K8sClientOptions) (*K8sClient, error) {
+	config, err := restConfig(options)
+	if err != nil {
+		return nil, err
 	}
+	client, err := newK8sClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	client.cacheTTL = options.CacheTTL
+	return client, nil
+}
 
-	configOverrides := &clientcmd.ConfigOverrides{}
-	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
+// kubeconfigRESTConfig loads the REST config of the current kubeconfig context
+func kubeconfigRESTConfig(showDebug bool) (*rest.Config, error) {
+	ctx := context.Background()
+	level := logging.DetailLevel(showDebug)
+
+	kubeconfig, source := kubeconfigPath()
+	slog.Log(ctx, level, "Using "+source, "path", kubeconfig)
+	kubeConfig := kubeconfigLoader()
 
 	// Get current context info for debugging
 	rawConfig, err := kubeConfig.RawConfig()
@@ -72,34 +84,38 @@ func NewK8sClient(showDebug bool) (*K8sClient, error) {
 		return nil, fmt.Errorf("failed to load raw kubeconfig: %w", err)
 	}
 
-	currentContext := rawConfig.CurrentContext
+	currentContext := effectiveContext(rawConfig.CurrentContext)
 	if currentContext == "" {
 		return nil, fmt.Errorf("no current context set in kubeconfig")
 	}
 
-	context, exists := rawConfig.Contexts[currentContext]
+	kubeContext, exists := rawConfig.Contexts[currentContext]
 	if !exists {
 		return nil, fmt.Errorf("current context '%s' not found in kubeconfig", currentContext)
 	}
 
-	cluster, exists := rawConfig.Clusters[context.Cluster]
+	cluster, exists := rawConfig.Clusters[kubeContext.Cluster]
 	if !exists {
-		return nil, fmt.Errorf("cluster '%s' not found in kubeconfig", context.Cluster)
+		return nil, fmt.Errorf("cluster '%s' not found in kubeconfig", kubeContext.Cluster)
 	}
 
-	if showDebug {
-		fmt.Printf("Using cluster: %s\n", context.Cluster)
-		fmt.Printf("Server: %s\n", cluster.Server)
-		fmt.Printf("Current context: %s\n", currentContext)
-		fmt.Printf("Namespace: %s\n", context.Namespace)
-		fmt.Println()
-	}
+	slog.Log(ctx, level, "Using cluster", "cluster", kubeContext.Cluster, "server", cluster.Server,
+		"context", currentContext, "namespace", kubeContext.Namespace)
 
 	// Load config
 	config, err := kubeConfig.ClientConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
 	}
+	return config, nil
+}
+
+// newK8sClientForConfig creates the typed clients shared by all commands
+func newK8sClientForConfig(config *rest.Config) (*K8sClient, error) {
+	// API requests, port-forward and exec upgrades all go through the config's transport
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return tracing.WrapTransport(logging.WrapTransport(rt, "kubernetes"), "kubernetes")
+	})
 
 	// Create specific typed clients instead of full clientset
 	coreClient, err := corev1client.NewForConfig(config)
@@ -117,6 +133,16 @@ func NewK8sClient(showDebug bool) (*K8sClient, error) {
 		return nil, fmt.Errorf("failed to create DiscoveryV1 client: %w", err)
 	}
 
+	storageClient, err := storagev1client.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create StorageV1 client: %w", err)
+	}
+
+	policyClient, err := policyv1client.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PolicyV1 client: %w", err)
+	}
+
 	// Create REST client for port-forwarding using CoreV1 configuration
 	coreConfig := *config
 	coreConfig.APIPath = "/api"
@@ -132,69 +158,70 @@ func NewK8sClient(showDebug bool) (*K8sClient, error) {
 		coreClient: coreClient,
 		appsClient: appsClient,
 		discovery:  discoveryClient,
+		storage:    storageClient,
+		policy:     policyClient,
 		restClient: restClient,
 		config:     config,
 	}, nil
 }
 
+// TypedClients is the subset of a clientset used by K8sClient; the fake clientset of
+// k8s.io/client-go/kubernetes/fake implements it
+type TypedClients interface {
+	CoreV1() corev1client.CoreV1Interface
+	AppsV1() appsv1client.AppsV1Interface
+	DiscoveryV1() discoveryclient.DiscoveryV1Interface
+	StorageV1() storagev1client.StorageV1Interface
+	PolicyV1() policyv1client.PolicyV1Interface
+}
+
+// NewK8sClientForClients wraps existing typed clients, e.g. a fake clientset in tests. Exec and
+// port-forwarding need a REST config and are not available on such a client.
+func NewK8sClientForClients(clients TypedClients) *K8sClient {
+	return &K8sClient{
+		coreClient: clients.CoreV1(),
+		appsClient: clients.AppsV1(),
+		discovery:  clients.DiscoveryV1(),
+		storage:    clients.StorageV1(),
+		policy:     clients.PolicyV1(),
+	}
+}
+
 // GetPod retrieves a pod by name and namespace
 func (k *K8sClient) GetPod(ctx context.Context, namespace, name string) (*v1.Pod, error) {
-	pod, err := k.coreClient.Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	pod, err := cachedLookup(k, []string{"pod", namespace, name}, func() (*v1.Pod, error) {
+		return k.coreClient.Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pod %s in namespace %s: %w", name, namespace, err)
 	}
 	return pod, nil
 }
 
-// DiscoverHealthPort searches for a container port named "health" in the pod
-func (k *K8sClient) DiscoverHealthPort(pod *v1.Pod) (int32, error) {
-	var availablePorts []string
-
-	for _, container := range pod.Spec.Containers {
-		for _, port := range container.Ports {
-			portInfo := fmt.Sprintf("%s(%d)", port.Name, port.ContainerPort)
-			availablePorts = append(availablePorts, portInfo)
-
-			if port.Name == "health" {
-				return port.ContainerPort, nil
-			}
-		}
+// GetContainerLogs returns the last tailLines log lines of a container; previous selects the
+// instance that ran before the last restart
+func (k *K8sClient) GetContainerLogs(ctx context.Context, namespace, podName, container string, tailLines int64, previous bool) ([]byte, error) {
+	options := &v1.PodLogOptions{Container: container, Previous: previous}
+	if tailLines > 0 {
+		options.TailLines = &tailLines
 	}
-
-	if len(availablePorts) == 0 {
-		return 0, fmt.Errorf("no container ports found in pod %s", pod.Name)
+	logs, err := k.coreClient.Pods(namespace).GetLogs(podName, options).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs of container %s in pod %s: %w", container, podName, err)
 	}
+	return logs, nil
+}
 
-	return 0, fmt.Errorf("health port not found. Available ports: %v. Use --port/-p to specify manually", availablePorts)
+// DiscoverHealthPort finds the broker health port, the container port named "health" unless
+// overridden with SetPortNames
+func (k *K8sClient) DiscoverHealthPort(pod *v1.Pod) (int32, error) {
+	return ProfileFor(ProductBroker).HealthPortFor(pod)
 }
 
-// DiscoverAPIPort searches for a container port named "api" in the pod, with fallback to port 8081
+// DiscoverAPIPort finds the broker REST API port: the container port named "api" unless
+// overridden with SetPortNames, with fallback to port 8081
 func (k *K8sClient) DiscoverAPIPort(pod *v1.Pod) (int32, error) {
-	var availablePorts []string
-
-	for _, container := range pod.Spec.Containers {
-		for _, port := range container.Ports {
-			portInfo := fmt.Sprintf("%s(%d)", port.Name, port.ContainerPort)
-			availablePorts = append(availablePorts, portInfo)
-
-			// First preference: named port "api"
-			if port.Name == "api" {
-				return port.ContainerPort, nil
-			}
-
-			// Second preference: port 8081 (common HiveMQ API port)
-			if port.ContainerPort == 8081 {
-				return port.ContainerPort, nil
-			}
-		}
-	}
-
-	if len(availablePorts) == 0 {
-		return 0, fmt.Errorf("no container ports found in pod %s", pod.Name)
-	}
-
-	// If no named "api" port or port 8081 found, return error with available ports
-	return 0, fmt.Errorf("API port not found (expected port named 'api' or port 8081). Available ports: %v", availablePorts)
+	return ProfileFor(ProductBroker).APIPortFor(pod)
 }
 
 // GetConfig returns the Kubernetes config
@@ -208,29 +235,67 @@ func (k *K8sClient) GetRESTClient() rest.Interface {
 }
 
 // GetCoreClient returns the CoreV1 client for direct API access
-func (k *K8sClient) GetCoreClient() *corev1client.CoreV1Client {
+func (k *K8sClient) GetCoreClient() corev1client.CoreV1Interface {
 	return k.coreClient
 }
 
 // GetAppsClient returns the AppsV1 client for direct API access
-func (k *K8sClient) GetAppsClient() *appsv1client.AppsV1Client {
+func (k *K8sClient) GetAppsClient() appsv1client.AppsV1Interface {
 	return k.appsClient
 }
 
 // GetDiscoveryClient returns the DiscoveryV1 client for endpoint slices
-func (k *K8sClient) GetDiscoveryClient() *discoveryclient.DiscoveryV1Client {
+func (k *K8sClient) GetDiscoveryClient() discoveryclient.DiscoveryV1Interface {
 	return k.discovery
 }
 
+// GetStorageClient returns the StorageV1 client for StorageClasses
+func (k *K8sClient) GetStorageClient() storagev1client.StorageV1Interface {
+	return k.storage
+}
+
 // GetStatefulSet retrieves a StatefulSet by name and namespace
 func (k *K8sClient) GetStatefulSet(ctx context.Context, namespace, name string) (*appsv1.StatefulSet, error) {
-	sts, err := k.appsClient.StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	sts, err := cachedLookup(k, []string{"statefulset", namespace, name}, func() (*appsv1.StatefulSet, error) {
+		return k.appsClient.StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get StatefulSet %s in namespace %s: %w", name, namespace, err)
 	}
 	return sts, nil
 }
 
+// ValidateLabelSelector checks the syntax of a label selector such as app.kubernetes.io/instance=tenant-a
+func ValidateLabelSelector(selector string) error {
+	if _, err := labels.Parse(selector); err != nil {
+		return NewValidationError("parse_selector", selector, err.Error())
+	}
+	return nil
+}
+
+// FindStatefulSetsBySelector lists the StatefulSets matching a label selector, sorted by namespace
+// and name. An empty namespace searches all namespaces.
+func (k *K8sClient) FindStatefulSetsBySelector(ctx context.Context, namespace, selector string) ([]appsv1.StatefulSet, error) {
+	if err := ValidateLabelSelector(selector); err != nil {
+		return nil, err
+	}
+
+	list, err := cachedLookup(k, []string{"statefulsets", namespace, selector}, func() (*appsv1.StatefulSetList, error) {
+		return k.appsClient.StatefulSets(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	})
+	if err != nil {
+		return nil, NewKubernetesError("list_statefulsets", namespace, err)
+	}
+
+	sort.Slice(list.Items, func(i, j int) bool {
+		if list.Items[i].Namespace != list.Items[j].Namespace {
+			return list.Items[i].Namespace < list.Items[j].Namespace
+		}
+		return list.Items[i].Name < list.Items[j].Name
+	})
+	return list.Items, nil
+}
+
 // GetPodsFromStatefulSet retrieves all pods belonging to a StatefulSet using label selectors
 func (k *K8sClient) GetPodsFromStatefulSet(ctx context.Context, namespace, statefulSetName string) ([]*v1.Pod, error) {
 	// First, get the StatefulSet to understand its selector
@@ -243,8 +308,8 @@ func (k *K8sClient) GetPodsFromStatefulSet(ctx context.Context, namespace, state
 	labelSelector := metav1.FormatLabelSelector(sts.Spec.Selector)
 
 	// Get pods matching the label selector
-	podList, err := k.coreClient.Pods(namespace).List(ctx, metav1.ListOptions{
-		LabelSelector: labelSelector,
+	podList, err := cachedLookup(k, []string{"pods", namespace, labelSelector}, func() (*v1.PodList, error) {
+		return k.coreClient.Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pods for StatefulSet %s: %w", statefulSetName, err)
@@ -259,11 +324,20 @@ func (k *K8sClient) GetPodsFromStatefulSet(ctx context.Context, namespace, state
 	return pods, nil
 }
 
+// GetService returns the named Service
+func (k *K8sClient) GetService(ctx context.Context, namespace, name string) (*v1.Service, error) {
+	return cachedLookup(k, []string{"service", namespace, name}, func() (*v1.Service, error) {
+		return k.coreClient.Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	})
+}
+
 // GetAPIServiceFromStatefulSet finds the API service for a StatefulSet
 func (k *K8sClient) GetAPIServiceFromStatefulSet(ctx context.Context, namespace, statefulSetName string) (*v1.Service, error) {
 	// First, try to find service with standard HiveMQ naming pattern: hivemq-broker-api
 	serviceName := "hivemq-broker-api"
-	service, err := k.coreClient.Services(namespace).Get(ctx, serviceName, metav1.GetOptions{})
+	service, err := cachedLookup(k, []string{"service", namespace, serviceName}, func() (*v1.Service, error) {
+		return k.coreClient.Services(namespace).Get(ctx, serviceName, metav1.GetOptions{})
+	})
 	if err == nil {
 		// Validate it has an API port
 		if hasAPIPort(service) {
@@ -281,8 +355,8 @@ func (k *K8sClient) GetAPIServiceFromStatefulSet(ctx context.Context, namespace,
 	labelSelector := metav1.FormatLabelSelector(sts.Spec.Selector)
 
 	// List services matching the label selector
-	services, err := k.coreClient.Services(namespace).List(ctx, metav1.ListOptions{
-		LabelSelector: labelSelector,
+	services, err := cachedLookup(k, []string{"services", namespace, labelSelector}, func() (*v1.ServiceList, error) {
+		return k.coreClient.Services(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list services for StatefulSet %s: %w", statefulSetName, err)
@@ -296,78 +370,36 @@ func (k *K8sClient) GetAPIServiceFromStatefulSet(ctx context.Context, namespace,
 		}
 	}
 
-	return nil, fmt.Errorf("no API service found for StatefulSet %s in namespace %s. Expected service named 'hivemq-broker-api' or service with port named 'api' or port 8081", statefulSetName, namespace)
+	return nil, fmt.Errorf("no API service found for StatefulSet %s in namespace %s. Expected service named 'hivemq-broker-api' or service with %s", statefulSetName, namespace, ProfileFor(ProductBroker).apiPortDescription())
 }
 
-// DiscoverServiceAPIPort searches for API port in a service
+// DiscoverServiceAPIPort finds the broker REST API port of a service
 func (k *K8sClient) DiscoverServiceAPIPort(service *v1.Service) (int32, error) {
-	var availablePorts []string
-
-	for _, port := range service.Spec.Ports {
-		portInfo := fmt.Sprintf("%s(%d)", port.Name, port.Port)
-		availablePorts = append(availablePorts, portInfo)
-
-		// First preference: named port "api"
-		if port.Name == "api" {
-			return port.Port, nil
-		}
-
-		// Second preference: port 8081 (common HiveMQ API port)
-		if port.Port == 8081 {
-			return port.Port, nil
-		}
-	}
-
-	if len(availablePorts) == 0 {
-		return 0, fmt.Errorf("no ports found in service %s", service.Name)
-	}
-
-	// If no named "api" port or port 8081 found, return error with available ports
-	return 0, fmt.Errorf("API port not found in service %s (expected port named 'api' or port 8081). Available ports: %v", service.Name, availablePorts)
+	return ProfileFor(ProductBroker).ServiceAPIPortFor(service)
 }
 
-// hasAPIPort checks if a service has an API port (named "api" or port 8081)
+// hasAPIPort checks if a service exposes the broker REST API port
 func hasAPIPort(service *v1.Service) bool {
-	for _, port := range service.Spec.Ports {
-		if port.Name == "api" || port.Port == 8081 {
-			return true
-		}
-	}
-	return false
+	_, err := ProfileFor(ProductBroker).ServiceAPIPortFor(service)
+	return err == nil
 }
 
 // GetDefaultNamespace extracts the default namespace from the current kubectl context
 func GetDefaultNamespace() (string, error) {
-	// Check for kubie environment variables first
-	var kubeconfig string
-	if kubieConfig := os.Getenv("KUBIE_KUBECONFIG"); kubieConfig != "" {
-		kubeconfig = kubieConfig
-	} else if envConfig := os.Getenv("KUBECONFIG"); envConfig != "" {
-		kubeconfig = envConfig
-	} else {
-		// Fall back to default kubeconfig
-		if home := homedir.HomeDir(); home != "" {
-			kubeconfig = filepath.Join(home, ".kube", "config")
-		}
-	}
-
-	// Load kubeconfig with context information
-	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-	if kubeconfig != "" {
-		loadingRules.ExplicitPath = kubeconfig
-	}
-
-	configOverrides := &clientcmd.ConfigOverrides{}
-	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
-
 	// Get current context info
-	rawConfig, err := kubeConfig.RawConfig()
+	rawConfig, err := kubeconfigLoader().RawConfig()
 	if err != nil {
+		if InClusterAvailable() {
+			return InClusterNamespace()
+		}
 		return "", fmt.Errorf("failed to load kubeconfig: %w", err)
 	}
 
-	currentContext := rawConfig.CurrentContext
+	currentContext := effectiveContext(rawConfig.CurrentContext)
 	if currentContext == "" {
+		if InClusterAvailable() {
+			return InClusterNamespace()
+		}
 		return "", fmt.Errorf("no current context set in kubeconfig. Use 'kubectl config use-context' to set a context")
 	}
 
@@ -384,9 +416,80 @@ func GetDefaultNamespace() (string, error) {
 	return "default", nil
 }
 
-// GetRandomPort returns a random available port
+// CurrentKubeIdentity returns the current kubeconfig context and its user. Inside a pod
+// without a kubeconfig the context is reported as "in-cluster" without a user.
+func CurrentKubeIdentity() (contextName, user string) {
+	rawConfig, err := kubeconfigLoader().RawConfig()
+	currentContext := effectiveContext(rawConfig.CurrentContext)
+	if err != nil || currentContext == "" {
+		if InClusterAvailable() {
+			return "in-cluster", ""
+		}
+		return "", ""
+	}
+
+	if context, exists := rawConfig.Contexts[currentContext]; exists {
+		user = context.AuthInfo
+	}
+	return currentContext, user
+}
+
+// kubeconfigLoader loads the kubeconfig from kubie, KUBECONFIG or ~/.kube/config, switched to
+// the --context override when one is set
+func kubeconfigLoader() clientcmd.ClientConfig {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig, _ := kubeconfigPath(); kubeconfig != "" {
+		loadingRules.ExplicitPath = kubeconfig
+	}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: KubeContextOverride()}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+}
+
+// kubeconfigPath picks the kubeconfig file, preferring kubie's over KUBECONFIG over the default
+func kubeconfigPath() (path, source string) {
+	if kubieConfig := os.Getenv("KUBIE_KUBECONFIG"); kubieConfig != "" {
+		return kubieConfig, "kubie kubeconfig"
+	}
+	if envConfig := os.Getenv("KUBECONFIG"); envConfig != "" {
+		return envConfig, "KUBECONFIG env var"
+	}
+	if home := homedir.HomeDir(); home != "" {
+		return filepath.Join(home, ".kube", "config"), "default kubeconfig"
+	}
+	return "", "default kubeconfig"
+}
+
+// kubeContextOverride replaces the kubeconfig's current-context for the whole process (--context)
+var kubeContextOverride atomic.Pointer[string]
+
+// SetKubeContext selects the kubeconfig context used for clients and the default namespace;
+// empty restores the kubeconfig's current-context
+func SetKubeContext(name string) {
+	kubeContextOverride.Store(&name)
+}
+
+// KubeContextOverride returns the context set with SetKubeContext, or empty
+func KubeContextOverride() string {
+	if name := kubeContextOverride.Load(); name != nil {
+		return *name
+	}
+	return ""
+}
+
+func effectiveContext(current string) string {
+	if override := KubeContextOverride(); override != "" {
+		return override
+	}
+	return current
+}
+
+// GetRandomPort returns a random port that is available on the local address port-forwards bind to
 func GetRandomPort() (int, error) {
-	listener, err := net.Listen("tcp", ":0")
+	address := ":0"
+	if transport.LocalAddress() != transport.DefaultLocalAddress {
+		address = transport.LocalHostPort(0)
+	}
+	listener, err := net.Listen("tcp", address)
 	if err != nil {
 		return 0, err
 	}
@@ -440,17 +543,47 @@ func (k *K8sClient) GetStatefulSetPods(ctx context.Context, namespace, statefulS
 	return result, nil
 }
 
+// defaultContainerAnnotation is honored by kubectl to select the container for exec and logs
+const defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+// BrokerContainerName returns the container running HiveMQ in a broker pod: the kubectl
+// default-container annotation, then the first container with a HiveMQ image, then the first container.
+func BrokerContainerName(pod *v1.Pod) string {
+	if name := pod.Annotations[defaultContainerAnnotation]; name != "" {
+		return name
+	}
+	for _, container := range pod.Spec.Containers {
+		if strings.Contains(strings.ToLower(container.Image), "hivemq") && !strings.Contains(strings.ToLower(container.Name), "sidecar") {
+			return container.Name
+		}
+	}
+	if len(pod.Spec.Containers) > 0 {
+		return pod.Spec.Containers[0].Name
+	}
+	return ""
+}
+
 // ExecCommand executes a command in a pod and returns the output
 func (k *K8sClient) ExecCommand(ctx context.Context, namespace, podName string, command []string) (string, error) {
+	return k.ExecCommandInContainer(ctx, namespace, podName, "", command)
+}
+
+// ExecCommandInContainer is ExecCommand for a specific container of a multi-container pod.
+// An empty container name lets the API server pick the pod's only container.
+func (k *K8sClient) ExecCommandInContainer(ctx context.Context, namespace, podName, container string, command []string) (output string, err error) {
+	ctx, span := startExecSpan(ctx, namespace, podName, container, command)
+	defer func() { tracing.End(span, err) }()
+
 	req := k.coreClient.RESTClient().Post().
 		Resource("pods").
 		Name(podName).
 		Namespace(namespace).
 		SubResource("exec").
 		VersionedParams(&v1.PodExecOptions{
-			Command: command,
-			Stdout:  true,
-			Stderr:  true,
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
 		}, scheme.ParameterCodec)
 
 	executor, err := remotecommand.NewSPDYExecutor(k.config, "POST", req.URL())
@@ -477,20 +610,31 @@ func (k *K8sClient) ExecCommand(ctx context.Context, namespace, podName string,
 
 // ExecCommandStream executes a command in a pod and returns a stream reader for the output
 func (k *K8sClient) ExecCommandStream(ctx context.Context, namespace, podName string, command []string) (io.ReadCloser, error) {
+	return k.ExecCommandStreamInContainer(ctx, namespace, podName, "", command)
+}
+
+// ExecCommandStreamInContainer is ExecCommandStream for a specific container of a multi-container pod.
+// An empty container name lets the API server pick the pod's only container.
+func (k *K8sClient) ExecCommandStreamInContainer(ctx context.Context, namespace, podName, container string, command []string) (io.ReadCloser, error) {
+	ctx, span := startExecSpan(ctx, namespace, podName, container, command)
+
 	req := k.coreClient.RESTClient().Post().
 		Resource("pods").
 		Name(podName).
 		Namespace(namespace).
 		SubResource("exec").
 		VersionedParams(&v1.PodExecOptions{
-			Command: command,
-			Stdout:  true,
-			Stderr:  true,
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
 		}, scheme.ParameterCodec)
 
 	executor, err := remotecommand.NewSPDYExecutor(k.config, "POST", req.URL())
 	if err != nil {
-		return nil, fmt.Errorf("failed to create SPDY executor: %w", err)
+		err = fmt.Errorf("failed to create SPDY executor: %w", err)
+		tracing.End(span, err)
+		return nil, err
 	}
 
 	// Create a pipe to stream the output
@@ -502,6 +646,7 @@ func (k *K8sClient) ExecCommandStream(ctx context.Context, namespace, podName st
 			Stdout: writer,
 			Stderr: writer,
 		})
+		tracing.End(span, err)
 		if err != nil {
 			writer.CloseWithError(fmt.Errorf("exec stream failed: %w", err))
 		}
@@ -509,3 +654,59 @@ func (k *K8sClient) ExecCommandStream(ctx context.Context, namespace, podName st
 
 	return reader, nil
 }
+
+// ExecCommandWithIO runs a command in a container of a pod, feeding it stdin (may be nil) and
+// writing its stdout to stdout. Stderr is kept apart so binary output such as tar streams stays
+// intact, and is reported in the error when the command fails.
+func (k *K8sClient) ExecCommandWithIO(ctx context.Context, namespace, podName, container string, command []string, stdin io.Reader, stdout io.Writer) (err error) {
+	ctx, span := startExecSpan(ctx, namespace, podName, container, command)
+	defer func() { tracing.End(span, err) }()
+
+	req := k.coreClient.RESTClient().Post().
+		Resource("pods").
+		Name(podName).
+		Namespace(namespace).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     stdin != nil,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(k.config, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("failed to create SPDY executor: %w", err)
+	}
+
+	var stderr strings.Builder
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		if stderr.Len() > 0 {
+			return fmt.Errorf("command failed: %s", strings.TrimSpace(stderr.String()))
+		}
+		return fmt.Errorf("exec failed: %w", err)
+	}
+	return nil
+}
+
+// startExecSpan traces an exec into a pod. Only the executable is recorded; arguments may carry
+// credentials such as management API tokens.
+func startExecSpan(ctx context.Context, namespace, podName, container string, command []string) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		attribute.String("k8s.namespace.name", namespace),
+		attribute.String("k8s.pod.name", podName),
+	}
+	if container != "" {
+		attrs = append(attrs, attribute.String("k8s.container.name", container))
+	}
+	if len(command) > 0 {
+		attrs = append(attrs, attribute.String("process.executable.name", command[0]))
+	}
+	return tracing.Start(ctx, "exec", attrs...)
+}
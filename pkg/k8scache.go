@@ -0,0 +1,54 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DefaultLookupCacheTTL is how long read-only lookups are reused by default
+const DefaultLookupCacheTTL = 3 * time.Second
+
+// lookups holds the results of read-only StatefulSet, pod, service and EndpointSlice lookups.
+// It is shared by all clients of the process, so a command that creates several clients or
+// resolves the same StatefulSet repeatedly (status then backup, watch and dashboard refreshes)
+// does not query the API server each time.
+var lookups = struct {
+	sync.Mutex
+	entries map[string]lookupEntry
+}{entries: map[string]lookupEntry{}}
+
+type lookupEntry struct {
+	object    runtime.Object
+	fetchedAt time.Time
+}
+
+// cachedLookup returns a copy of the object stored under key while it is younger than the
+// client's cache TTL and calls fetch otherwise. Failed lookups are not cached.
+func cachedLookup[T runtime.Object](k *K8sClient, key []string, fetch func() (T, error)) (T, error) {
+	if k.cacheTTL <= 0 {
+		return fetch()
+	}
+	cacheKey := fmt.Sprintf("%s|%s", k.config.Host, strings.Join(key, "|"))
+
+	lookups.Lock()
+	entry, found := lookups.entries[cacheKey]
+	lookups.Unlock()
+	if found && time.Since(entry.fetchedAt) < k.cacheTTL {
+		if object, ok := entry.object.DeepCopyObject().(T); ok {
+			return object, nil
+		}
+	}
+
+	object, err := fetch()
+	if err != nil {
+		return object, err
+	}
+	lookups.Lock()
+	lookups.entries[cacheKey] = lookupEntry{object: object.DeepCopyObject(), fetchedAt: time.Now()}
+	lookups.Unlock()
+	return object, nil
+}
@@ -0,0 +1,52 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+func TestCachedLookup(t *testing.T) {
+	t.Parallel()
+
+	client := &K8sClient{config: &rest.Config{Host: "https://cache-test"}, cacheTTL: time.Minute}
+	calls := 0
+	fetch := func() (*v1.Pod, error) {
+		calls++
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "broker-0"}}, nil
+	}
+	key := []string{"pod", "tenant-a", "broker-0"}
+
+	first, err := cachedLookup(client, key, fetch)
+	if err != nil {
+		t.Fatalf("cachedLookup() error = %v", err)
+	}
+	first.Labels = map[string]string{"changed": "by caller"}
+
+	second, err := cachedLookup(client, key, fetch)
+	if err != nil || calls != 1 {
+		t.Fatalf("cachedLookup() = %v after %d fetches, want a cached result", err, calls)
+	}
+	if second.Labels != nil {
+		t.Fatal("cached objects must be copies, not shared with callers")
+	}
+
+	uncached := &K8sClient{config: client.config}
+	if _, err := cachedLookup(uncached, key, fetch); err != nil || calls != 2 {
+		t.Fatalf("cache TTL 0 must always fetch, got %d fetches", calls)
+	}
+
+	failing := func() (*v1.Pod, error) { calls++; return nil, errors.New("not found") }
+	for range 2 {
+		if _, err := cachedLookup(client, []string{"pod", "tenant-a", "missing"}, failing); err == nil {
+			t.Fatal("expected the fetch error")
+		}
+	}
+	if calls != 4 {
+		t.Fatalf("failed lookups must not be cached, got %d fetches", calls)
+	}
+}
@@ -0,0 +1,73 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+current-context: production
+clusters:
+- name: production
+  cluster:
+    server: https://production.example.com
+- name: staging
+  cluster:
+    server: https://staging.example.com
+users:
+- name: admin
+  user:
+    token: secret
+contexts:
+- name: production
+  context:
+    cluster: production
+    user: admin
+    namespace: hivemq
+- name: staging
+  context:
+    cluster: staging
+    user: admin
+    namespace: hivemq-staging
+`
+
+// Not parallel: sets KUBECONFIG and the process-wide context override
+func TestKubeContextOverrideAndImpersonation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KUBIE_KUBECONFIG", "")
+	t.Setenv("KUBECONFIG", path)
+	t.Cleanup(func() { SetKubeContext("") })
+
+	if namespace, err := GetDefaultNamespace(); err != nil || namespace != "hivemq" {
+		t.Fatalf("GetDefaultNamespace() = %q, %v; want the current context's namespace", namespace, err)
+	}
+
+	SetKubeContext("staging")
+	if namespace, err := GetDefaultNamespace(); err != nil || namespace != "hivemq-staging" {
+		t.Fatalf("GetDefaultNamespace() = %q, %v; want the overridden context's namespace", namespace, err)
+	}
+	if contextName, user := CurrentKubeIdentity(); contextName != "staging" || user != "admin" {
+		t.Fatalf("CurrentKubeIdentity() = %q, %q", contextName, user)
+	}
+
+	config, err := restConfig(K8sClientOptions{Impersonate: "alice", ImpersonateGroups: []string{"hivemq-operators"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Host != "https://staging.example.com" {
+		t.Fatalf("restConfig() host = %s, want the staging cluster", config.Host)
+	}
+	if config.Impersonate.UserName != "alice" || len(config.Impersonate.Groups) != 1 {
+		t.Fatalf("unexpected impersonation config %+v", config.Impersonate)
+	}
+
+	SetKubeContext("missing")
+	if _, err := restConfig(K8sClientOptions{}); err == nil {
+		t.Fatal("expected an error for an unknown context")
+	}
+}
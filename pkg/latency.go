@@ -0,0 +1,47 @@
+package pkg
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// LatencyStats summarizes the request latencies of repeated health checks of a pod. Latencies
+// exclude the port-forward setup, which dominates the response time of a single check.
+type LatencyStats struct {
+	Samples int
+	P50     time.Duration
+	P95     time.Duration
+	Max     time.Duration
+}
+
+// NewLatencyStats computes nearest-rank percentiles of the samples
+func NewLatencyStats(samples []time.Duration) LatencyStats {
+	if len(samples) == 0 {
+		return LatencyStats{}
+	}
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	return LatencyStats{
+		Samples: len(sorted),
+		P50:     nearestRank(sorted, 50),
+		P95:     nearestRank(sorted, 95),
+		Max:     sorted[len(sorted)-1],
+	}
+}
+
+// ExceedsSLO reports whether the p95 latency is above slo; a zero slo is never exceeded
+func (s LatencyStats) ExceedsSLO(slo time.Duration) bool {
+	return slo > 0 && s.Samples > 0 && s.P95 > slo
+}
+
+// nearestRank returns the smallest sample that at least percentile percent of sorted are at or below
+func nearestRank(sorted []time.Duration, percentile float64) time.Duration {
+	rank := int(math.Ceil(percentile / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+// durationMilliseconds converts d to fractional milliseconds for JSON output
+func durationMilliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,35 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewLatencyStats(t *testing.T) {
+	t.Parallel()
+
+	samples := make([]time.Duration, 0, 20)
+	for i := 20; i >= 1; i-- {
+		samples = append(samples, time.Duration(i)*10*time.Millisecond)
+	}
+	stats := NewLatencyStats(samples)
+	want := LatencyStats{Samples: 20, P50: 100 * time.Millisecond, P95: 190 * time.Millisecond, Max: 200 * time.Millisecond}
+	if stats != want {
+		t.Errorf("NewLatencyStats() = %+v, want %+v", stats, want)
+	}
+	if samples[0] != 200*time.Millisecond {
+		t.Error("NewLatencyStats() reordered the samples of the caller")
+	}
+
+	if !stats.ExceedsSLO(150*time.Millisecond) || stats.ExceedsSLO(190*time.Millisecond) || stats.ExceedsSLO(0) {
+		t.Errorf("ExceedsSLO() does not compare the p95 of %+v", stats)
+	}
+
+	single := NewLatencyStats([]time.Duration{42 * time.Millisecond})
+	if single.P50 != 42*time.Millisecond || single.P95 != 42*time.Millisecond {
+		t.Errorf("NewLatencyStats() of one sample = %+v", single)
+	}
+	if (NewLatencyStats(nil) != LatencyStats{}) || (LatencyStats{}).ExceedsSLO(time.Millisecond) {
+		t.Error("empty latency stats must be zero and never exceed an SLO")
+	}
+}
@@ -0,0 +1,140 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg/health"
+)
+
+// Severities of license findings
+const (
+	LicenseWarn    = "WARN"
+	LicenseExpired = "EXPIRED"
+)
+
+// PodLicenses is the license state of the extensions of one broker pod
+type PodLicenses struct {
+	Pod      string                    `json:"pod"`
+	Licenses []health.ExtensionLicense `json:"licenses"`
+	Error    string                    `json:"error,omitempty"`
+}
+
+// LicenseFinding is a problem found in the licenses of the cluster
+type LicenseFinding struct {
+	Severity  string `json:"severity"`
+	Extension string `json:"extension,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+	Message   string `json:"message"`
+}
+
+// LicenseReport is the license state of all broker pods of a StatefulSet
+type LicenseReport struct {
+	Namespace   string           `json:"namespace"`
+	StatefulSet string           `json:"statefulSet"`
+	Pods        []PodLicenses    `json:"pods"`
+	Findings    []LicenseFinding `json:"findings"`
+}
+
+// Expired reports whether any pod runs with an expired license
+func (r *LicenseReport) Expired() bool {
+	return slices.ContainsFunc(r.Findings, func(f LicenseFinding) bool { return f.Severity == LicenseExpired })
+}
+
+// CollectLicenses queries the health API of every pod and extracts the extension licenses.
+// Pods whose health check fails are reported with their error instead of failing the report.
+func (k *K8sClient) CollectLicenses(ctx context.Context, pods []*v1.Pod, portOverride int32, options health.HealthCheckOptions) ([]PodLicenses, error) {
+	options.OutputRaw = true
+	results, err := k.CollectConcurrentHealthChecks(ctx, pods, portOverride, options)
+	if err != nil {
+		return nil, err
+	}
+
+	podLicenses := make([]PodLicenses, 0, len(results))
+	for _, result := range results {
+		entry := PodLicenses{Pod: result.PodName}
+		switch {
+		case result.Error != nil:
+			entry.Error = result.Error.Error()
+		case len(result.RawJSON) == 0:
+			entry.Error = "empty health response"
+		default:
+			if entry.Licenses, err = health.ExtractLicenses(result.RawJSON); err != nil {
+				entry.Error = err.Error()
+			}
+		}
+		podLicenses = append(podLicenses, entry)
+	}
+	return podLicenses, nil
+}
+
+// AnalyzeLicenses flags expired licenses and trials, licenses that expire within warnWithin and
+// extensions whose license differs between pods
+func AnalyzeLicenses(pods []PodLicenses, now time.Time, warnWithin time.Duration) []LicenseFinding {
+	var findings []LicenseFinding
+	byExtension := map[string]map[string][]string{} // extension -> license signature -> pods
+	var extensions []string
+	checkedPods := 0
+
+	for _, pod := range pods {
+		if pod.Error != "" {
+			findings = append(findings, LicenseFinding{Severity: LicenseWarn, Pod: pod.Pod, Message: "license state unknown: " + pod.Error})
+			continue
+		}
+		checkedPods++
+		for _, license := range pod.Licenses {
+			switch {
+			case license.TrialExpired:
+				findings = append(findings, LicenseFinding{Severity: LicenseExpired, Extension: license.Extension, Pod: pod.Pod, Message: "trial license expired"})
+			case license.Expiry != nil && !license.Expiry.After(now):
+				findings = append(findings, LicenseFinding{Severity: LicenseExpired, Extension: license.Extension, Pod: pod.Pod, Message: "license expired on " + license.Expiry.Format("2006-01-02")})
+			case license.Expiry != nil && license.Expiry.Sub(now) <= warnWithin:
+				findings = append(findings, LicenseFinding{Severity: LicenseWarn, Extension: license.Extension, Pod: pod.Pod, Message: fmt.Sprintf("license expires on %s (in %d days)", license.Expiry.Format("2006-01-02"), int(license.Expiry.Sub(now).Hours()/24))})
+			case license.Trial:
+				findings = append(findings, LicenseFinding{Severity: LicenseWarn, Extension: license.Extension, Pod: pod.Pod, Message: "running on a trial license"})
+			}
+
+			signatures, known := byExtension[license.Extension]
+			if !known {
+				signatures = map[string][]string{}
+				byExtension[license.Extension] = signatures
+				extensions = append(extensions, license.Extension)
+			}
+			signature := licenseSignature(license)
+			signatures[signature] = append(signatures[signature], pod.Pod)
+		}
+	}
+
+	slices.Sort(extensions)
+	for _, extension := range extensions {
+		signatures := byExtension[extension]
+		var licensed int
+		for _, podNames := range signatures {
+			licensed += len(podNames)
+		}
+		if len(signatures) > 1 {
+			variants := make([]string, 0, len(signatures))
+			for signature, podNames := range signatures {
+				variants = append(variants, fmt.Sprintf("%s on %s", signature, strings.Join(podNames, ",")))
+			}
+			slices.Sort(variants)
+			findings = append(findings, LicenseFinding{Severity: LicenseWarn, Extension: extension, Message: "license differs between pods: " + strings.Join(variants, "; ")})
+		} else if licensed < checkedPods {
+			findings = append(findings, LicenseFinding{Severity: LicenseWarn, Extension: extension, Message: fmt.Sprintf("license reported by %d of %d pods", licensed, checkedPods)})
+		}
+	}
+	return findings
+}
+
+// licenseSignature identifies a license for comparison between pods
+func licenseSignature(license health.ExtensionLicense) string {
+	if license.Expiry == nil {
+		return license.Summary
+	}
+	return license.Summary + " until " + license.Expiry.Format("2006-01-02")
+}
@@ -0,0 +1,54 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"kubectl-broker/pkg/health"
+)
+
+func TestAnalyzeLicenses(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	soon := now.Add(5 * 24 * time.Hour)
+	later := now.Add(90 * 24 * time.Hour)
+	enterprise := func(expiry time.Time) health.ExtensionLicense {
+		return health.ExtensionLicense{Extension: "hivemq-kafka-extension", Enterprise: true, Summary: "Enterprise, Licensed", Expiry: &expiry}
+	}
+
+	findings := AnalyzeLicenses([]PodLicenses{
+		{Pod: "broker-0", Licenses: []health.ExtensionLicense{enterprise(later)}},
+		{Pod: "broker-1", Licenses: []health.ExtensionLicense{enterprise(later)}},
+	}, now, 14*24*time.Hour)
+	if len(findings) != 0 {
+		t.Fatalf("findings = %+v, want none for matching licenses", findings)
+	}
+
+	report := LicenseReport{Findings: AnalyzeLicenses([]PodLicenses{
+		{Pod: "broker-0", Licenses: []health.ExtensionLicense{enterprise(soon)}},
+		{Pod: "broker-1", Licenses: []health.ExtensionLicense{enterprise(later)}},
+		{Pod: "broker-2", Licenses: []health.ExtensionLicense{{Extension: "hivemq-kafka-extension", Enterprise: true, Trial: true, TrialExpired: true, Summary: "Enterprise, Trial Expired"}}},
+		{Pod: "broker-3", Error: "connection refused"},
+	}, now, 14*24*time.Hour)}
+
+	if !report.Expired() {
+		t.Error("Expired() = false, want true for an expired trial")
+	}
+	var messages []string
+	for _, finding := range report.Findings {
+		messages = append(messages, finding.Severity+" "+finding.Pod+" "+finding.Message)
+	}
+	joined := strings.Join(messages, "\n")
+	for _, want := range []string{
+		"WARN broker-0 license expires on 2025-06-06 (in 5 days)",
+		"EXPIRED broker-2 trial license expired",
+		"WARN broker-3 license state unknown: connection refused",
+		"license differs between pods",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("findings missing %q:\n%s", want, joined)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package pkg
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg/transport"
+)
+
+// DefaultListenerProbeTimeout bounds connecting to and reading from one listener
+const DefaultListenerProbeTimeout = 3 * time.Second
+
+// listenerIdleWait is how long an accepted connection must stay open to count as served. A
+// forwarded port without a listener in the pod is accepted locally and closed right away.
+const listenerIdleWait = 500 * time.Millisecond
+
+// ListenerPort is a TCP container port of the broker container
+type ListenerPort struct {
+	Name string `json:"name"`
+	Port int32  `json:"port"`
+	TLS  bool   `json:"tls"`
+}
+
+// ListenerReport collects the listener checks of a StatefulSet's pods
+type ListenerReport struct {
+	Namespace   string         `json:"namespace"`
+	StatefulSet string         `json:"statefulSet"`
+	Checks      []NetworkCheck `json:"checks"`
+}
+
+// Failed counts the failed checks
+func (r *ListenerReport) Failed() int {
+	failed := 0
+	for _, check := range r.Checks {
+		if check.Status == NetworkCheckFail {
+			failed++
+		}
+	}
+	return failed
+}
+
+// ListenerPorts returns the TCP ports the broker container advertises, except the cluster
+// transport port which --check-network covers. Ports are treated as TLS when their name says
+// so (mqtts, https, tls, ssl) or they use the well-known TLS ports 8883 and 8443.
+func ListenerPorts(pod *v1.Pod) []ListenerPort {
+	container := BrokerContainerName(pod)
+	clusterPort := ClusterPort(pod)
+
+	var ports []ListenerPort
+	for _, c := range pod.Spec.Containers {
+		if c.Name != container {
+			continue
+		}
+		for _, port := range c.Ports {
+			if port.Protocol != "" && port.Protocol != v1.ProtocolTCP {
+				continue
+			}
+			if port.Name == "cluster" || port.ContainerPort == clusterPort {
+				continue
+			}
+			ports = append(ports, ListenerPort{Name: port.Name, Port: port.ContainerPort, TLS: isTLSPort(port.Name, port.ContainerPort)})
+		}
+	}
+	return ports
+}
+
+func isTLSPort(name string, port int32) bool {
+	name = strings.ToLower(name)
+	for _, marker := range []string{"mqtts", "https", "tls", "ssl"} {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return port == 8883 || port == 8443
+}
+
+// CheckListeners opens a port-forward to every advertised listener of every running pod of the
+// StatefulSet and verifies it accepts connections; TLS listeners must complete a handshake.
+// Listeners that do not answer are reported as failed checks, not as an error.
+func (k *K8sClient) CheckListeners(ctx context.Context, namespace, statefulSetName string, timeout time.Duration) (*ListenerReport, error) {
+	pods, err := k.GetPodsFromStatefulSet(ctx, namespace, statefulSetName)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ListenerReport{Namespace: namespace, StatefulSet: statefulSetName}
+	for _, pod := range pods {
+		if pod.Status.Phase != v1.PodRunning {
+			report.Checks = append(report.Checks, NetworkCheck{Name: "Listeners", Target: pod.Name, Status: NetworkCheckSkip, Details: fmt.Sprintf("pod is %s", pod.Status.Phase)})
+			continue
+		}
+		ports := ListenerPorts(pod)
+		if len(ports) == 0 {
+			report.Checks = append(report.Checks, NetworkCheck{Name: "Listeners", Target: pod.Name, Status: NetworkCheckSkip, Details: "broker container declares no TCP ports"})
+			continue
+		}
+		for _, listener := range ports {
+			check := NetworkCheck{Name: listenerName(listener), Target: pod.Name, Status: NetworkCheckPass}
+			err := NewPodDialer(k, pod, listener.Port, false).Forward(ctx, func(localPort int) error {
+				details, err := ProbeListener(ctx, localPort, listener.TLS, timeout)
+				check.Details = details
+				return err
+			})
+			if err != nil {
+				check.Status = NetworkCheckFail
+				check.Details = err.Error()
+			}
+			report.Checks = append(report.Checks, check)
+		}
+	}
+	return report, nil
+}
+
+func listenerName(listener ListenerPort) string {
+	if listener.Name == "" {
+		return fmt.Sprintf("port %d", listener.Port)
+	}
+	return fmt.Sprintf("%s %d", listener.Name, listener.Port)
+}
+
+// ProbeListener connects to a listener on localhost. A plain listener passes when the connection
+// stays open until the client would speak first; a TLS listener passes once a handshake completes.
+// Certificates are not verified, only reported, since brokers commonly use private CAs.
+func ProbeListener(ctx context.Context, localPort int, useTLS bool, timeout time.Duration) (string, error) {
+	if timeout <= 0 {
+		timeout = DefaultListenerProbeTimeout
+	}
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", transport.LocalHostPort(localPort))
+	if err != nil {
+		return "", fmt.Errorf("connection failed: %w", err)
+	}
+	defer conn.Close()
+
+	if useTLS {
+		tlsConn := tls.Client(conn, &tls.Config{InsecureSkipVerify: true})
+		handshakeCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		if err := tlsConn.HandshakeContext(handshakeCtx); err != nil {
+			return "", fmt.Errorf("TLS handshake failed: %w", err)
+		}
+		state := tlsConn.ConnectionState()
+		if len(state.PeerCertificates) == 0 {
+			return "TLS handshake completed", nil
+		}
+		cert := state.PeerCertificates[0]
+		if time.Now().After(cert.NotAfter) {
+			return "", fmt.Errorf("TLS certificate %s expired on %s", cert.Subject.CommonName, cert.NotAfter.Format(time.RFC3339))
+		}
+		return fmt.Sprintf("TLS handshake completed, certificate %s valid until %s", cert.Subject.CommonName, cert.NotAfter.Format("2006-01-02")), nil
+	}
+
+	// MQTT and HTTP servers wait for the client, so a read deadline means the listener is there
+	wait := listenerIdleWait
+	if timeout < wait {
+		wait = timeout
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(wait))
+	_, err = conn.Read(make([]byte, 1))
+	switch {
+	case err == nil, errors.Is(err, os.ErrDeadlineExceeded):
+		return "accepts connections", nil
+	default:
+		return "", fmt.Errorf("connection closed by the pod, nothing is listening: %w", err)
+	}
+}
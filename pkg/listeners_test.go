@@ -0,0 +1,95 @@
+package pkg
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestListenerPorts(t *testing.T) {
+	t.Parallel()
+
+	pod := &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{
+		{Name: "hivemq", Image: "hivemq/hivemq4", Ports: []v1.ContainerPort{
+			{Name: "mqtt", ContainerPort: 1883},
+			{Name: "mqtts", ContainerPort: 8883},
+			{Name: "cc", ContainerPort: 8080},
+			{Name: "cluster", ContainerPort: 7000},
+			{Name: "metrics", ContainerPort: 9399, Protocol: v1.ProtocolUDP},
+		}},
+		{Name: "backup-sidecar", Image: "sidecar", Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 8090}}},
+	}}}
+
+	got := ListenerPorts(pod)
+	want := []ListenerPort{{Name: "mqtt", Port: 1883}, {Name: "mqtts", Port: 8883, TLS: true}, {Name: "cc", Port: 8080}}
+	if len(got) != len(want) {
+		t.Fatalf("ListenerPorts() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ListenerPorts()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestProbeListener(t *testing.T) {
+	t.Parallel()
+
+	// A listener that waits for the client, like an MQTT broker
+	idle, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idle.Close()
+	go func() {
+		for {
+			conn, err := idle.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	// What a port-forward does when nothing listens on the remote port
+	closing, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closing.Close()
+	go func() {
+		for {
+			conn, err := closing.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	defer tlsServer.Close()
+	tlsURL, _ := url.Parse(tlsServer.URL)
+	tlsPort, _ := strconv.Atoi(tlsURL.Port())
+
+	ctx := context.Background()
+	if _, err := ProbeListener(ctx, idle.Addr().(*net.TCPAddr).Port, false, time.Second); err != nil {
+		t.Errorf("idle listener: unexpected error %v", err)
+	}
+	if _, err := ProbeListener(ctx, closing.Addr().(*net.TCPAddr).Port, false, time.Second); err == nil {
+		t.Error("closing listener: expected an error")
+	}
+	if details, err := ProbeListener(ctx, tlsPort, true, time.Second); err != nil || details == "" {
+		t.Errorf("TLS listener: details %q, error %v", details, err)
+	}
+	if _, err := ProbeListener(ctx, idle.Addr().(*net.TCPAddr).Port, true, 200*time.Millisecond); err == nil {
+		t.Error("plain listener probed as TLS: expected a handshake error")
+	}
+}
@@ -0,0 +1,209 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLoggedBody caps the bytes of a request or response body written to the HTTP debug log
+const maxLoggedBody = 4096
+
+// redacted replaces secret values in the HTTP debug log
+const redacted = "REDACTED"
+
+// HTTPDebugOptions configures the --debug-http request log
+type HTTPDebugOptions struct {
+	Logger *slog.Logger // receives one debug record per request and per logged body
+	Bodies bool         // also log text bodies, with secret values redacted
+}
+
+var httpDebug atomic.Pointer[HTTPDebugOptions]
+
+// EnableHTTPDebug logs every request sent through a WrapTransport transport from now on,
+// including transports created before the call
+func EnableHTTPDebug(options HTTPDebugOptions) {
+	httpDebug.Store(&options)
+}
+
+// DisableHTTPDebug stops the HTTP debug log
+func DisableHTTPDebug() {
+	httpDebug.Store(nil)
+}
+
+// debugTransport logs requests while the HTTP debug log is enabled
+type debugTransport struct {
+	base   http.RoundTripper
+	system string
+}
+
+// WrapTransport returns base (http.DefaultTransport when nil) logging method, URL, status and
+// duration of each request while EnableHTTPDebug is in effect. system names the remote side,
+// e.g. "kubernetes" or "hivemq". Credentials in the URL are redacted and headers never logged.
+func WrapTransport(base http.RoundTripper, system string) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &debugTransport{base: base, system: system}
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	options := httpDebug.Load()
+	if options == nil || options.Logger == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	target := redactURL(req.URL)
+	// Port-forward and exec upgrades turn the connection into a stream; their bodies are not text
+	logBodies := options.Bodies && req.Header.Get("Upgrade") == "" && !sensitivePath(req.URL.Path)
+	if logBodies && req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(io.LimitReader(body, maxLoggedBody+1))
+			body.Close()
+			options.Logger.DebugContext(ctx, "HTTP request body", "system", t.system, "method", req.Method, "url", target,
+				"bytes", req.ContentLength, "body", formatBody(data, req.Header.Get("Content-Type")))
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	duration := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		options.Logger.DebugContext(ctx, "HTTP request failed", "system", t.system, "method", req.Method, "url", target,
+			"duration", duration, "error", err)
+		return nil, err
+	}
+	options.Logger.DebugContext(ctx, "HTTP request", "system", t.system, "method", req.Method, "url", target,
+		"status", resp.StatusCode, "duration", duration)
+
+	if logBodies && resp.StatusCode != http.StatusSwitchingProtocols && resp.Body != nil {
+		// Capture the body while the caller reads it, so streams and watches are not delayed
+		resp.Body = &bodyRecorder{
+			ReadCloser: resp.Body,
+			log: func(data []byte, total int64) {
+				options.Logger.DebugContext(context.WithoutCancel(ctx), "HTTP response body", "system", t.system, "method", req.Method,
+					"url", target, "status", resp.StatusCode, "bytes", total, "body", formatBody(data, resp.Header.Get("Content-Type")))
+			},
+		}
+	}
+	return resp, nil
+}
+
+// bodyRecorder keeps the first maxLoggedBody bytes read from a response and logs them once
+// the body is closed
+type bodyRecorder struct {
+	io.ReadCloser
+	log   func(data []byte, total int64)
+	buf   bytes.Buffer
+	total int64
+	once  sync.Once
+}
+
+func (r *bodyRecorder) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if room := maxLoggedBody + 1 - r.buf.Len(); room > 0 {
+		r.buf.Write(p[:min(n, room)])
+	}
+	r.total += int64(n)
+	return n, err
+}
+
+func (r *bodyRecorder) Close() error {
+	r.once.Do(func() { r.log(r.buf.Bytes(), r.total) })
+	return r.ReadCloser.Close()
+}
+
+// sensitiveNames matches key and parameter names whose values are credentials
+const sensitiveNames = `(?i:password|passwd|secret|token|credential|signature|private_?key|access_?key|authorization|api_?key)`
+
+var (
+	sensitiveName = regexp.MustCompile(sensitiveNames)
+	// sensitiveKey matches a JSON string member with a credential-like key; a value cut off
+	// by truncation is matched up to the end of the text
+	sensitiveKey = regexp.MustCompile(`("[^"]*` + sensitiveNames + `[^"]*"\s*:\s*)"(?:[^"\\]|\\.)*(?:"|$)`)
+	// sensitiveAssignment matches key=value and key: value pairs with a credential-like key in
+	// log lines and properties files
+	sensitiveAssignment = regexp.MustCompile(`(\b[\w.-]*` + sensitiveNames + `[\w.-]*\s*[=:]\s*["']?)[^\s,;"']+`)
+)
+
+// SensitiveName reports whether a key, variable or parameter name suggests a credential value
+func SensitiveName(name string) bool {
+	return sensitiveName.MatchString(name)
+}
+
+// sensitivePath reports whether a request targets Kubernetes Secrets, whose data keys are
+// arbitrary names and cannot be redacted by key
+func sensitivePath(path string) bool {
+	return strings.Contains(path, "/secrets")
+}
+
+// redactURL returns the URL without user info passwords and with credential query values hidden
+func redactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	clean := *u
+	if clean.RawQuery != "" {
+		query := clean.Query()
+		for name := range query {
+			if sensitiveName.MatchString(name) {
+				query.Set(name, redacted)
+			}
+		}
+		clean.RawQuery = query.Encode()
+	}
+	return clean.Redacted()
+}
+
+// RedactBody hides the values of credential-like keys in a JSON (or JSON-like) body
+func RedactBody(body string) string {
+	return sensitiveKey.ReplaceAllString(body, `${1}"`+redacted+`"`)
+}
+
+// RedactText hides credential values in free text such as log output: JSON members as in
+// RedactBody and key=value or key: value pairs with a credential-like key
+func RedactText(text string) string {
+	return sensitiveAssignment.ReplaceAllString(RedactBody(text), "${1}"+redacted)
+}
+
+// formatBody renders a logged body: text content is redacted and truncated, other content is
+// omitted
+func formatBody(data []byte, contentType string) string {
+	if len(data) == 0 {
+		return ""
+	}
+	if !isTextContent(contentType, data) {
+		return "<binary body omitted>"
+	}
+	// Redact before truncating so a cut does not expose the start of a secret
+	text := RedactBody(string(data))
+	if len(data) > maxLoggedBody {
+		return text[:min(len(text), maxLoggedBody)] + "...(truncated)"
+	}
+	return text
+}
+
+// isTextContent reports whether a body can be logged as text; form bodies are never logged
+// since their credentials cannot be redacted by JSON key
+func isTextContent(contentType string, data []byte) bool {
+	if contentType == "" {
+		return utf8.Valid(data)
+	}
+	contentType = strings.ToLower(contentType)
+	for _, marker := range []string{"json", "yaml", "text/", "xml"} {
+		if strings.Contains(contentType, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	t.Parallel()
+
+	body := `{"username":"admin","password":"s3cr\"et","spec":{"accessKey":"AKIA","bucket":"backups"},"apiToken":"tok`
+	got := RedactBody(body)
+	for _, secret := range []string{`s3cr`, `AKIA`, `tok`} {
+		if strings.Contains(got, secret) {
+			t.Errorf("RedactBody() leaked %q: %s", secret, got)
+		}
+	}
+	for _, kept := range []string{`"username":"admin"`, `"bucket":"backups"`, `"password":"REDACTED"`} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("RedactBody() = %s, want it to contain %s", got, kept)
+		}
+	}
+}
+
+func TestRedactText(t *testing.T) {
+	t.Parallel()
+
+	text := "2024-05-01 INFO connecting with s3.secretKey=AKIA/x, region=eu-west-1\n" +
+		"  db.password: 'hunter2'\n" +
+		`{"event":"login","token":"abc123"}`
+	got := RedactText(text)
+	for _, secret := range []string{"AKIA", "hunter2", "abc123"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("RedactText() leaked %q: %s", secret, got)
+		}
+	}
+	for _, kept := range []string{"region=eu-west-1", "s3.secretKey=REDACTED", "db.password: 'REDACTED'", `"event":"login"`} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("RedactText() = %s, want it to contain %s", got, kept)
+		}
+	}
+}
+
+func TestDebugTransportLogsRequests(t *testing.T) {
+	// Not parallel: the HTTP debug log is process-wide
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[],"token":"abc123"}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger, err := New(&buf, slog.LevelDebug, FormatText)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	EnableHTTPDebug(HTTPDebugOptions{Logger: logger, Bodies: true})
+	defer DisableHTTPDebug()
+
+	client := &http.Client{Transport: WrapTransport(nil, "hivemq")}
+	resp, err := client.Get(server.URL + "/api/v1/management/backups?limit=5&access_token=xyz")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != `{"items":[],"token":"abc123"}` {
+		t.Fatalf("body altered by the debug transport: %s", body)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"HTTP request", "system=hivemq", "method=GET", "status=200", "limit=5", "HTTP response body"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log does not contain %q:\n%s", want, out)
+		}
+	}
+	for _, secret := range []string{"xyz", "abc123"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log leaked %q:\n%s", secret, out)
+		}
+	}
+
+	DisableHTTPDebug()
+	buf.Reset()
+	resp, err = client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+	if buf.Len() != 0 {
+		t.Errorf("disabled debug log wrote: %s", buf.String())
+	}
+}
@@ -0,0 +1,144 @@
+// Package logging configures the leveled logger used for diagnostic output.
+// Command results go to stdout; everything logged here goes to stderr so
+// structured output stays parseable.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Supported --log-format values
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// LevelFor maps -v and quiet mode to a log level: quiet shows warnings only,
+// the default shows informational messages and -v adds debug detail.
+func LevelFor(verbosity int, quiet bool) slog.Level {
+	switch {
+	case verbosity > 0:
+		return slog.LevelDebug
+	case quiet:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// DetailLevel is the level for messages that are shown when a command runs in
+// detailed mode and are debug output otherwise
+func DetailLevel(detailed bool) slog.Level {
+	if detailed {
+		return slog.LevelInfo
+	}
+	return slog.LevelDebug
+}
+
+// New creates a logger writing to w in the given format
+func New(w io.Writer, level slog.Level, format string) (*slog.Logger, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", FormatText:
+		return slog.New(&textHandler{out: w, level: level, mu: &sync.Mutex{}}), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q: use text or json", format)
+	}
+}
+
+// textHandler writes one plain line per record, "message key=value ...", without
+// timestamps so interactive output reads like the rest of the CLI.
+type textHandler struct {
+	out   io.Writer
+	level slog.Level
+	attrs []slog.Attr
+	group string
+	mu    *sync.Mutex
+}
+
+func (h *textHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *textHandler) Handle(_ context.Context, record slog.Record) error {
+	var line strings.Builder
+	switch {
+	case record.Level >= slog.LevelError:
+		line.WriteString("Error: ")
+	case record.Level >= slog.LevelWarn:
+		line.WriteString("Warning: ")
+	}
+	line.WriteString(record.Message)
+
+	for _, attr := range h.attrs {
+		writeAttr(&line, "", attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		writeAttr(&line, h.group, attr)
+		return true
+	})
+	line.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.out, line.String())
+	return err
+}
+
+func (h *textHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr{}, h.attrs...), qualify(h.group, attrs)...)
+	return &clone
+}
+
+func (h *textHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.group = qualifiedKey(h.group, name)
+	return &clone
+}
+
+func writeAttr(line *strings.Builder, group string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		for _, member := range attr.Value.Group() {
+			writeAttr(line, qualifiedKey(group, attr.Key), member)
+		}
+		return
+	}
+
+	value := attr.Value.String()
+	if value == "" || strings.ContainsAny(value, " \t\"=") {
+		value = fmt.Sprintf("%q", value)
+	}
+	fmt.Fprintf(line, " %s=%s", qualifiedKey(group, attr.Key), value)
+}
+
+func qualify(group string, attrs []slog.Attr) []slog.Attr {
+	if group == "" {
+		return attrs
+	}
+	qualified := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		qualified[i] = slog.Attr{Key: qualifiedKey(group, attr.Key), Value: attr.Value}
+	}
+	return qualified
+}
+
+func qualifiedKey(group, key string) string {
+	if group == "" || key == "" {
+		return group + key
+	}
+	return group + "." + key
+}
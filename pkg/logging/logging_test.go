@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestLevelFor(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		verbosity int
+		quiet     bool
+		want      slog.Level
+	}{
+		{0, false, slog.LevelInfo},
+		{0, true, slog.LevelWarn},
+		{1, false, slog.LevelDebug},
+		{2, true, slog.LevelDebug},
+	}
+	for _, tc := range cases {
+		if got := LevelFor(tc.verbosity, tc.quiet); got != tc.want {
+			t.Errorf("LevelFor(%d, %v) = %v, want %v", tc.verbosity, tc.quiet, got, tc.want)
+		}
+	}
+}
+
+func TestTextHandlerFormatsAndFilters(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger, err := New(&buf, slog.LevelInfo, FormatText)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	logger.Debug("hidden", "namespace", "prod")
+	logger.Info("Using namespace from context", "namespace", "prod")
+	logger.With("pod", "broker-0").Warn("slow response", "took", "2 s")
+
+	want := "Using namespace from context namespace=prod\nWarning: slow response pod=broker-0 took=\"2 s\"\n"
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestJSONHandler(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger, err := New(&buf, slog.LevelWarn, FormatJSON)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	logger.Info("suppressed")
+	logger.Warn("backup slow", "namespace", "prod")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected a single JSON record, got %q: %v", buf.String(), err)
+	}
+	if record["msg"] != "backup slow" || record["namespace"] != "prod" || record["level"] != "WARN" {
+		t.Errorf("unexpected record: %v", record)
+	}
+}
+
+func TestNewRejectsUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	if _, err := New(&bytes.Buffer{}, slog.LevelInfo, "xml"); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,163 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// metricsAPIPath is the metrics.k8s.io endpoint served by metrics-server
+const metricsAPIPath = "/apis/metrics.k8s.io/v1beta1"
+
+// ErrMetricsUnavailable is returned when the cluster does not serve the metrics.k8s.io API
+var ErrMetricsUnavailable = errors.New("metrics.k8s.io API is not available (is metrics-server installed?)")
+
+// PodResourceUsage combines live usage from metrics.k8s.io with the pod's requests and limits.
+// CPU values are in millicores, memory values in bytes. Usage covers every container, so a
+// request or limit is only reported when every container sets it; otherwise it is zero.
+type PodResourceUsage struct {
+	PodName            string `json:"podName"`
+	CPUUsageMilli      int64  `json:"cpuUsageMilli"`
+	CPURequestMilli    int64  `json:"cpuRequestMilli,omitempty"`
+	CPULimitMilli      int64  `json:"cpuLimitMilli,omitempty"`
+	MemoryUsageBytes   int64  `json:"memoryUsageBytes"`
+	MemoryRequestBytes int64  `json:"memoryRequestBytes,omitempty"`
+	MemoryLimitBytes   int64  `json:"memoryLimitBytes,omitempty"`
+	HasMetrics         bool   `json:"hasMetrics"`
+}
+
+// CPUPercent returns CPU usage relative to the limit, falling back to the request.
+// The second value is false when neither is set.
+func (u PodResourceUsage) CPUPercent() (float64, bool) {
+	return utilization(u.CPUUsageMilli, u.CPULimitMilli, u.CPURequestMilli)
+}
+
+// MemoryPercent returns memory usage relative to the limit, falling back to the request.
+// The second value is false when neither is set.
+func (u PodResourceUsage) MemoryPercent() (float64, bool) {
+	return utilization(u.MemoryUsageBytes, u.MemoryLimitBytes, u.MemoryRequestBytes)
+}
+
+// ExceedsThreshold reports whether CPU or memory utilization is at or above percent
+func (u PodResourceUsage) ExceedsThreshold(percent float64) bool {
+	if cpu, ok := u.CPUPercent(); ok && cpu >= percent {
+		return true
+	}
+	if mem, ok := u.MemoryPercent(); ok && mem >= percent {
+		return true
+	}
+	return false
+}
+
+func utilization(usage, limit, request int64) (float64, bool) {
+	switch {
+	case limit > 0:
+		return float64(usage) / float64(limit) * 100.0, true
+	case request > 0:
+		return float64(usage) / float64(request) * 100.0, true
+	default:
+		return 0, false
+	}
+}
+
+// podMetricsList mirrors the parts of metrics.k8s.io/v1beta1 PodMetricsList we need
+type podMetricsList struct {
+	Items []podMetrics `json:"items"`
+}
+
+type podMetrics struct {
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Containers []struct {
+		Name  string          `json:"name"`
+		Usage v1.ResourceList `json:"usage"`
+	} `json:"containers"`
+}
+
+// GetPodResourceUsage returns resource usage for the given pods keyed by pod name.
+// Pods without metrics (e.g. just started) are returned with HasMetrics=false.
+func (k *K8sClient) GetPodResourceUsage(ctx context.Context, namespace string, pods []*v1.Pod) (map[string]PodResourceUsage, error) {
+	raw, err := k.restClient.Get().
+		AbsPath(metricsAPIPath, "namespaces", namespace, "pods").
+		DoRaw(ctx)
+	if err != nil {
+		if apierrors.IsNotFound(err) || apierrors.IsServiceUnavailable(err) {
+			return nil, ErrMetricsUnavailable
+		}
+		return nil, NewKubernetesError("get_pod_metrics", namespace, err)
+	}
+
+	var list podMetricsList
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse pod metrics: %w", err)
+	}
+
+	byName := make(map[string]podMetrics, len(list.Items))
+	for _, item := range list.Items {
+		byName[item.Metadata.Name] = item
+	}
+
+	usage := make(map[string]PodResourceUsage, len(pods))
+	for _, pod := range pods {
+		entry := podResourceBounds(pod)
+
+		if metrics, ok := byName[pod.Name]; ok {
+			entry.HasMetrics = true
+			for _, container := range metrics.Containers {
+				entry.CPUUsageMilli += milliValue(container.Usage, v1.ResourceCPU)
+				entry.MemoryUsageBytes += byteValue(container.Usage, v1.ResourceMemory)
+			}
+		}
+
+		usage[pod.Name] = entry
+	}
+
+	return usage, nil
+}
+
+// podResourceBounds sums the requests and limits of a pod's containers. Usage is measured for
+// all containers, so a total that left out containers without the setting would overstate
+// utilization; such totals are zero instead, as for a pod without limits.
+func podResourceBounds(pod *v1.Pod) PodResourceUsage {
+	containers := pod.Spec.Containers
+	return PodResourceUsage{
+		PodName:            pod.Name,
+		CPURequestMilli:    containerTotal(containers, func(c v1.Container) v1.ResourceList { return c.Resources.Requests }, v1.ResourceCPU, milliValue),
+		CPULimitMilli:      containerTotal(containers, func(c v1.Container) v1.ResourceList { return c.Resources.Limits }, v1.ResourceCPU, milliValue),
+		MemoryRequestBytes: containerTotal(containers, func(c v1.Container) v1.ResourceList { return c.Resources.Requests }, v1.ResourceMemory, byteValue),
+		MemoryLimitBytes:   containerTotal(containers, func(c v1.Container) v1.ResourceList { return c.Resources.Limits }, v1.ResourceMemory, byteValue),
+	}
+}
+
+// containerTotal sums a resource setting over all containers, or returns 0 when any container
+// does not set it
+func containerTotal(containers []v1.Container, list func(v1.Container) v1.ResourceList, name v1.ResourceName, value func(v1.ResourceList, v1.ResourceName) int64) int64 {
+	var total int64
+	for _, container := range containers {
+		v := value(list(container), name)
+		if v <= 0 {
+			return 0
+		}
+		total += v
+	}
+	return total
+}
+
+func milliValue(resources v1.ResourceList, name v1.ResourceName) int64 {
+	if quantity, ok := resources[name]; ok {
+		return quantity.MilliValue()
+	}
+	return 0
+}
+
+func byteValue(resources v1.ResourceList, name v1.ResourceName) int64 {
+	if quantity, ok := resources[name]; ok {
+		return quantity.Value()
+	}
+	return 0
+}
@@ -0,0 +1,50 @@
+package pkg
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestPodResourceBoundsIgnoresPartialLimits(t *testing.T) {
+	t.Parallel()
+
+	broker := v1.Container{Name: "hivemq", Resources: v1.ResourceRequirements{
+		Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("2Gi")},
+		Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("2"), v1.ResourceMemory: resource.MustParse("4Gi")},
+	}}
+	sidecar := v1.Container{Name: "backup-sidecar", Resources: v1.ResourceRequirements{
+		Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m"), v1.ResourceMemory: resource.MustParse("128Mi")},
+	}}
+
+	cases := []struct {
+		name       string
+		containers []v1.Container
+		cpuLimit   int64
+		cpuRequest int64
+	}{
+		{"all containers limited", []v1.Container{broker}, 2000, 1000},
+		{"one container unlimited", []v1.Container{broker, sidecar}, 0, 1100},
+		{"no settings", []v1.Container{{Name: "hivemq"}}, 0, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			bounds := podResourceBounds(&v1.Pod{Spec: v1.PodSpec{Containers: tc.containers}})
+			if bounds.CPULimitMilli != tc.cpuLimit || bounds.CPURequestMilli != tc.cpuRequest {
+				t.Errorf("cpu request/limit = %d/%d, want %d/%d", bounds.CPURequestMilli, bounds.CPULimitMilli, tc.cpuRequest, tc.cpuLimit)
+			}
+		})
+	}
+}
+
+func TestUtilizationComparesUsageWithRequestWithoutFullLimits(t *testing.T) {
+	t.Parallel()
+
+	// The sidecar has no limit, so utilization is measured against the requests of both containers
+	usage := PodResourceUsage{CPUUsageMilli: 1000, CPURequestMilli: 1100}
+	percent, ok := usage.CPUPercent()
+	if !ok || percent > 91 || percent < 90 {
+		t.Errorf("CPUPercent() = %.1f, %v, want about 90.9", percent, ok)
+	}
+}
@@ -0,0 +1,287 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultClusterPort is the HiveMQ cluster transport port when no container port is named "cluster"
+const DefaultClusterPort int32 = 7000
+
+// Outcomes of a single network check
+const (
+	NetworkCheckPass = "PASS"
+	NetworkCheckWarn = "WARN"
+	NetworkCheckFail = "FAIL"
+	NetworkCheckSkip = "SKIP"
+)
+
+// dnsLookupScript resolves $1 with nslookup (SRV records) or getent (address records only) and
+// always exits 0, so the output survives lookup failures
+const dnsLookupScript = `if command -v nslookup >/dev/null 2>&1; then
+  echo "tool: nslookup"; nslookup -type=SRV "$1" 2>&1
+elif command -v getent >/dev/null 2>&1; then
+  echo "tool: getent"; getent hosts "$1" 2>&1
+else
+  echo "tool: none"
+fi
+exit 0`
+
+// peerProbeScript opens a TCP connection to every host:port argument with nc or bash /dev/tcp
+// and prints "<target> ok" or "<target> fail" per target
+const peerProbeScript = `if command -v nc >/dev/null 2>&1; then
+  probe() { nc -z -w 3 "$1" "$2" >/dev/null 2>&1; }
+elif command -v bash >/dev/null 2>&1 && command -v timeout >/dev/null 2>&1; then
+  probe() { timeout 3 bash -c "</dev/tcp/$1/$2" >/dev/null 2>&1; }
+else
+  echo "tool: none"; exit 0
+fi
+for target in "$@"; do
+  if probe "${target%:*}" "${target##*:}"; then echo "$target ok"; else echo "$target fail"; fi
+done
+exit 0`
+
+// NetworkCheck is the outcome of one cluster discovery prerequisite
+type NetworkCheck struct {
+	Name    string `json:"name"`
+	Target  string `json:"target"`
+	Status  string `json:"status"`
+	Details string `json:"details,omitempty"`
+}
+
+// NetworkReport collects the discovery prerequisites of a StatefulSet
+type NetworkReport struct {
+	Namespace       string         `json:"namespace"`
+	StatefulSet     string         `json:"statefulSet"`
+	HeadlessService string         `json:"headlessService"`
+	ClusterPort     int32          `json:"clusterPort"`
+	Checks          []NetworkCheck `json:"checks"`
+}
+
+// Failed counts the failed checks
+func (r *NetworkReport) Failed() int {
+	failed := 0
+	for _, check := range r.Checks {
+		if check.Status == NetworkCheckFail {
+			failed++
+		}
+	}
+	return failed
+}
+
+func (r *NetworkReport) add(name, target, status, details string) {
+	r.Checks = append(r.Checks, NetworkCheck{Name: name, Target: target, Status: status, Details: details})
+}
+
+// CheckClusterNetwork verifies what HiveMQ DNS cluster discovery depends on: the StatefulSet's
+// headless service, SRV records resolved from inside a broker pod and TCP reachability of every
+// peer on the cluster port. Failed prerequisites are reported as checks, not as an error.
+func (k *K8sClient) CheckClusterNetwork(ctx context.Context, namespace, statefulSetName string) (*NetworkReport, error) {
+	sts, err := k.GetStatefulSet(ctx, namespace, statefulSetName)
+	if err != nil {
+		return nil, NewKubernetesError("get_statefulset", statefulSetName, err)
+	}
+	pods, err := k.GetPodsFromStatefulSet(ctx, namespace, statefulSetName)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &NetworkReport{
+		Namespace:       namespace,
+		StatefulSet:     statefulSetName,
+		HeadlessService: sts.Spec.ServiceName,
+		ClusterPort:     DefaultClusterPort,
+	}
+	if len(pods) > 0 {
+		report.ClusterPort = ClusterPort(pods[0])
+	}
+
+	k.checkHeadlessService(ctx, report)
+
+	// Lookups and probes run from the broker container, where HiveMQ itself resolves its peers
+	var source *v1.Pod
+	for _, pod := range pods {
+		if pod.Status.Phase == v1.PodRunning {
+			source = pod
+			break
+		}
+	}
+	if source == nil {
+		report.add("DNS records", report.HeadlessService, NetworkCheckSkip, "no running pod to resolve from")
+		report.add("Peer connectivity", statefulSetName, NetworkCheckSkip, "no running pod to probe from")
+		return report, nil
+	}
+	if report.HeadlessService != "" {
+		k.checkDNSRecords(ctx, report, source, len(pods))
+	}
+	k.checkPeerConnectivity(ctx, report, pods)
+	return report, nil
+}
+
+// ClusterPort returns the container port named "cluster", or DefaultClusterPort
+func ClusterPort(pod *v1.Pod) int32 {
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Name == "cluster" {
+				return port.ContainerPort
+			}
+		}
+	}
+	return DefaultClusterPort
+}
+
+// checkHeadlessService verifies that spec.serviceName names an existing headless service
+func (k *K8sClient) checkHeadlessService(ctx context.Context, report *NetworkReport) {
+	const name = "Headless service"
+	if report.HeadlessService == "" {
+		report.add(name, report.StatefulSet, NetworkCheckFail, "StatefulSet has no spec.serviceName, so pods get no stable DNS names")
+		return
+	}
+
+	service, err := k.coreClient.Services(report.Namespace).Get(ctx, report.HeadlessService, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		report.add(name, report.HeadlessService, NetworkCheckFail, "service named in spec.serviceName does not exist")
+		return
+	}
+	if err != nil {
+		report.add(name, report.HeadlessService, NetworkCheckFail, err.Error())
+		return
+	}
+	if service.Spec.ClusterIP != v1.ClusterIPNone {
+		report.add(name, report.HeadlessService, NetworkCheckFail, fmt.Sprintf("service has clusterIP %s; DNS discovery needs clusterIP: None", service.Spec.ClusterIP))
+		return
+	}
+	if !service.Spec.PublishNotReadyAddresses {
+		report.add(name, report.HeadlessService, NetworkCheckWarn, "publishNotReadyAddresses is false; brokers cannot find peers that are not ready yet")
+		return
+	}
+	report.add(name, report.HeadlessService, NetworkCheckPass, "clusterIP: None, publishNotReadyAddresses: true")
+}
+
+// checkDNSRecords resolves the headless service from inside source and expects a record per pod
+func (k *K8sClient) checkDNSRecords(ctx context.Context, report *NetworkReport, source *v1.Pod, expected int) {
+	const name = "DNS records"
+	fqdn := fmt.Sprintf("%s.%s.svc", report.HeadlessService, report.Namespace)
+
+	output, err := k.ExecCommandInContainer(ctx, source.Namespace, source.Name, BrokerContainerName(source), []string{"sh", "-c", dnsLookupScript, "sh", fqdn})
+	if err != nil {
+		report.add(name, fqdn, NetworkCheckFail, fmt.Sprintf("lookup from %s failed: %v", source.Name, err))
+		return
+	}
+
+	tool, records := ParseDNSLookup(output)
+	slog.Debug("Resolved headless service", "pod", source.Name, "name", fqdn, "tool", tool, "records", records)
+	switch {
+	case tool == "none":
+		report.add(name, fqdn, NetworkCheckSkip, fmt.Sprintf("neither nslookup nor getent is available in %s", source.Name))
+	case len(records) == 0:
+		report.add(name, fqdn, NetworkCheckFail, fmt.Sprintf("no records resolved from %s", source.Name))
+	case len(records) < expected:
+		report.add(name, fqdn, NetworkCheckWarn, fmt.Sprintf("%d of %d pods resolved: %s", len(records), expected, strings.Join(records, ", ")))
+	case tool == "getent":
+		report.add(name, fqdn, NetworkCheckPass, fmt.Sprintf("%d address records (SRV not checked, nslookup missing)", len(records)))
+	default:
+		report.add(name, fqdn, NetworkCheckPass, fmt.Sprintf("%d SRV records", len(records)))
+	}
+}
+
+// ParseDNSLookup extracts the lookup tool and the resolved targets from dnsLookupScript output:
+// SRV targets for nslookup, addresses for getent
+func ParseDNSLookup(output string) (tool string, records []string) {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if value, ok := strings.CutPrefix(line, "tool: "); ok {
+			tool = value
+			continue
+		}
+		switch tool {
+		case "nslookup":
+			// e.g. "broker.ns.svc.cluster.local	service = 0 50 7000 broker-0.broker.ns.svc.cluster.local."
+			if _, srv, ok := strings.Cut(line, "service = "); ok {
+				if fields := strings.Fields(srv); len(fields) == 4 {
+					records = append(records, strings.TrimSuffix(fields[3], "."))
+				}
+			}
+		case "getent":
+			if fields := strings.Fields(line); len(fields) >= 1 {
+				records = append(records, fields[0])
+			}
+		}
+	}
+	return tool, records
+}
+
+// checkPeerConnectivity probes the cluster port of every other pod from each running pod
+func (k *K8sClient) checkPeerConnectivity(ctx context.Context, report *NetworkReport, pods []*v1.Pod) {
+	const name = "Peer connectivity"
+	port := strconv.Itoa(int(report.ClusterPort))
+
+	for _, source := range pods {
+		if source.Status.Phase != v1.PodRunning {
+			report.add(name, source.Name, NetworkCheckSkip, fmt.Sprintf("pod is %s", source.Status.Phase))
+			continue
+		}
+
+		var targets []string
+		peerByTarget := make(map[string]string)
+		for _, peer := range pods {
+			if peer.Name == source.Name || peer.Status.PodIP == "" {
+				continue
+			}
+			target := peer.Status.PodIP + ":" + port
+			targets = append(targets, target)
+			peerByTarget[target] = peer.Name
+		}
+		if len(targets) == 0 {
+			report.add(name, source.Name, NetworkCheckSkip, "no peers with an IP address")
+			continue
+		}
+
+		command := append([]string{"sh", "-c", peerProbeScript, "sh"}, targets...)
+		output, err := k.ExecCommandInContainer(ctx, source.Namespace, source.Name, BrokerContainerName(source), command)
+		if err != nil {
+			report.add(name, source.Name, NetworkCheckFail, fmt.Sprintf("probe failed: %v", err))
+			continue
+		}
+
+		reachable, ok := ParsePeerProbe(output)
+		if !ok {
+			report.add(name, source.Name, NetworkCheckSkip, "neither nc nor bash with timeout is available")
+			continue
+		}
+		var unreachable []string
+		for _, target := range targets {
+			if !reachable[target] {
+				unreachable = append(unreachable, peerByTarget[target])
+			}
+		}
+		if len(unreachable) > 0 {
+			report.add(name, source.Name, NetworkCheckFail, fmt.Sprintf("cannot reach %s on port %s", strings.Join(unreachable, ", "), port))
+			continue
+		}
+		report.add(name, source.Name, NetworkCheckPass, fmt.Sprintf("reaches %d peers on port %s", len(targets), port))
+	}
+}
+
+// ParsePeerProbe reads peerProbeScript output into reachability per target. ok is false when
+// the pod has no tool to probe with.
+func ParsePeerProbe(output string) (reachable map[string]bool, ok bool) {
+	reachable = make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "tool: none" {
+			return nil, false
+		}
+		if target, result, found := strings.Cut(line, " "); found {
+			reachable[target] = result == "ok"
+		}
+	}
+	return reachable, true
+}
@@ -0,0 +1,75 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDNSLookup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		output      string
+		wantTool    string
+		wantRecords []string
+	}{
+		{
+			name: "nslookup SRV records",
+			output: `tool: nslookup
+Server:		10.96.0.10
+Address:	10.96.0.10#53
+
+broker.prod.svc.cluster.local	service = 0 50 7000 broker-0.broker.prod.svc.cluster.local.
+broker.prod.svc.cluster.local	service = 0 50 7000 broker-1.broker.prod.svc.cluster.local.
+`,
+			wantTool:    "nslookup",
+			wantRecords: []string{"broker-0.broker.prod.svc.cluster.local", "broker-1.broker.prod.svc.cluster.local"},
+		},
+		{
+			name:     "nslookup without records",
+			output:   "tool: nslookup\n** server can't find broker.prod.svc: NXDOMAIN\n",
+			wantTool: "nslookup",
+		},
+		{
+			name:        "getent address records",
+			output:      "tool: getent\n10.0.0.4        broker.prod.svc.cluster.local\n10.0.0.5        broker.prod.svc.cluster.local\n",
+			wantTool:    "getent",
+			wantRecords: []string{"10.0.0.4", "10.0.0.5"},
+		},
+		{
+			name:     "no tool",
+			output:   "tool: none\n",
+			wantTool: "none",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tool, records := ParseDNSLookup(tt.output)
+			if tool != tt.wantTool {
+				t.Errorf("tool = %q, want %q", tool, tt.wantTool)
+			}
+			if !reflect.DeepEqual(records, tt.wantRecords) {
+				t.Errorf("records = %v, want %v", records, tt.wantRecords)
+			}
+		})
+	}
+}
+
+func TestParsePeerProbe(t *testing.T) {
+	t.Parallel()
+
+	reachable, ok := ParsePeerProbe("10.0.0.5:7000 ok\n10.0.0.6:7000 fail\n")
+	if !ok {
+		t.Fatal("expected a usable probe")
+	}
+	if !reachable["10.0.0.5:7000"] || reachable["10.0.0.6:7000"] {
+		t.Errorf("unexpected reachability: %v", reachable)
+	}
+
+	if _, ok := ParsePeerProbe("tool: none\n"); ok {
+		t.Error("expected missing probe tool to be reported")
+	}
+}
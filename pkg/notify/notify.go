@@ -0,0 +1,121 @@
+// Package notify posts a summary of a finished long-running operation to a webhook, so
+// unattended runs such as nightly backups report failures where people look.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"kubectl-broker/pkg/logging"
+)
+
+// Format selects the webhook payload
+type Format string
+
+const (
+	// FormatJSON posts the Summary as a JSON object
+	FormatJSON Format = "json"
+	// FormatSlack posts a Slack incoming-webhook message with a "text" field
+	FormatSlack Format = "slack"
+)
+
+// Results of an operation
+const (
+	ResultSucceeded   = "succeeded"
+	ResultFailed      = "failed"
+	ResultInterrupted = "interrupted"
+)
+
+// DefaultTimeout bounds a webhook delivery
+const DefaultTimeout = 10 * time.Second
+
+// Summary describes a finished operation
+type Summary struct {
+	Operation       string            `json:"operation"` // e.g. "backup create"
+	Namespace       string            `json:"namespace,omitempty"`
+	StatefulSet     string            `json:"statefulSet,omitempty"`
+	KubeContext     string            `json:"kubeContext,omitempty"`
+	Result          string            `json:"result"`
+	Error           string            `json:"error,omitempty"`
+	StartedAt       time.Time         `json:"startedAt"`
+	DurationSeconds float64           `json:"durationSeconds"`
+	Details         map[string]string `json:"details,omitempty"` // backup ID, sizes, counts
+}
+
+// Payload encodes the summary in the webhook format
+func Payload(summary Summary, format Format) ([]byte, error) {
+	switch format {
+	case FormatJSON, "":
+		return json.Marshal(summary)
+	case FormatSlack:
+		return json.Marshal(map[string]string{"text": slackText(summary)})
+	default:
+		return nil, fmt.Errorf("unknown notification format %q", format)
+	}
+}
+
+// slackText renders the summary as Slack mrkdwn
+func slackText(summary Summary) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "*kubectl broker %s %s*", summary.Operation, summary.Result)
+	target := summary.Namespace
+	if summary.StatefulSet != "" {
+		target += "/" + summary.StatefulSet
+	}
+	if target != "" {
+		fmt.Fprintf(&text, " on `%s`", target)
+	}
+	if summary.KubeContext != "" {
+		fmt.Fprintf(&text, " (context `%s`)", summary.KubeContext)
+	}
+	fmt.Fprintf(&text, " after %s", time.Duration(summary.DurationSeconds*float64(time.Second)).Round(time.Second))
+
+	keys := make([]string, 0, len(summary.Details))
+	for key := range summary.Details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&text, "\n• %s: %s", key, summary.Details[key])
+	}
+	if summary.Error != "" {
+		fmt.Fprintf(&text, "\n```%s```", summary.Error)
+	}
+	return text.String()
+}
+
+// Send posts the summary to url and fails on a non-2xx response
+func Send(ctx context.Context, url string, format Format, summary Summary) error {
+	payload, err := Payload(summary, format)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Transport: logging.WrapTransport(http.DefaultTransport, "webhook")}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
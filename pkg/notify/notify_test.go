@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendPostsSummary(t *testing.T) {
+	t.Parallel()
+
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("invalid payload %q: %v", body, err)
+		}
+	}))
+	defer server.Close()
+
+	summary := Summary{
+		Operation:       "backup create",
+		Namespace:       "production",
+		Result:          ResultFailed,
+		Error:           "management API connection failed",
+		StartedAt:       time.Date(2025, 1, 1, 2, 0, 0, 0, time.UTC),
+		DurationSeconds: 12.5,
+		Details:         map[string]string{"backupId": "abc123"},
+	}
+	if err := Send(context.Background(), server.URL, FormatJSON, summary); err != nil {
+		t.Fatalf("Send(json) error = %v", err)
+	}
+	if received["result"] != ResultFailed || received["operation"] != "backup create" {
+		t.Errorf("payload = %v", received)
+	}
+
+	if err := Send(context.Background(), server.URL, FormatSlack, summary); err != nil {
+		t.Fatalf("Send(slack) error = %v", err)
+	}
+	text, _ := received["text"].(string)
+	for _, want := range []string{"*kubectl broker backup create failed*", "`production`", "backupId: abc123", "management API connection failed"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("slack text %q does not contain %q", text, want)
+		}
+	}
+}
+
+func TestSendFailsOnErrorStatus(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no_service", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	err := Send(context.Background(), server.URL, FormatSlack, Summary{Operation: "volumes cleanup", Result: ResultSucceeded})
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("Send() error = %v, want the 404 status", err)
+	}
+}
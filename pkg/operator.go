@@ -0,0 +1,108 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// hiveMQClusterKind is the custom resource of the legacy HiveMQ operator
+const hiveMQClusterKind = "HiveMQCluster"
+
+// managedByLabel names the tool that manages a resource
+const managedByLabel = "app.kubernetes.io/managed-by"
+
+// OperatorManagedBy describes how a HiveMQ operator manages obj, e.g. "owned by
+// HiveMQPlatform/broker"; it is empty for resources no operator manages
+func OperatorManagedBy(obj metav1.Object) string {
+	for _, owner := range obj.GetOwnerReferences() {
+		if owner.Kind == hiveMQPlatformKind || owner.Kind == hiveMQClusterKind {
+			return fmt.Sprintf("owned by %s/%s", owner.Kind, owner.Name)
+		}
+	}
+	if manager := obj.GetLabels()[managedByLabel]; isHiveMQOperator(manager) {
+		return fmt.Sprintf("labeled %s=%s", managedByLabel, manager)
+	}
+	return ""
+}
+
+// isHiveMQOperator matches the managed-by values of the HiveMQ Platform and legacy operators
+func isHiveMQOperator(manager string) bool {
+	manager = strings.ToLower(manager)
+	return strings.Contains(manager, "hivemq") && strings.Contains(manager, "operator")
+}
+
+// OperatorManagedResource is a resource a HiveMQ operator manages
+type OperatorManagedResource struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Reason    string
+}
+
+func (r OperatorManagedResource) String() string {
+	name := r.Name
+	if r.Namespace != "" {
+		name = r.Namespace + "/" + r.Name
+	}
+	return fmt.Sprintf("%s %s (%s)", r.Kind, name, r.Reason)
+}
+
+// OperatorManagedError refuses to change resources a HiveMQ operator manages, since the operator
+// may reconcile manual changes away or stop working when its resources change underneath it
+type OperatorManagedError struct {
+	Operation string
+	Resources []OperatorManagedResource
+}
+
+func (e *OperatorManagedError) Error() string {
+	names := make([]string, 0, len(e.Resources))
+	for _, resource := range e.Resources {
+		names = append(names, resource.String())
+	}
+	return fmt.Sprintf("%s would change resources managed by the HiveMQ operator: %s; the operator may reconcile manual changes away or stop working as expected",
+		e.Operation, strings.Join(names, ", "))
+}
+
+// StatefulSetManagedBy describes how a HiveMQ operator manages the StatefulSet; it is empty
+// when no operator does
+func (k *K8sClient) StatefulSetManagedBy(ctx context.Context, namespace, statefulSetName string) (string, error) {
+	sts, err := k.GetStatefulSet(ctx, namespace, statefulSetName)
+	if err != nil {
+		return "", err
+	}
+	return OperatorManagedBy(sts), nil
+}
+
+// OperatorManagedStatefulSets lists the StatefulSets of the namespace a HiveMQ operator manages
+func (k *K8sClient) OperatorManagedStatefulSets(ctx context.Context, namespace string) ([]appsv1.StatefulSet, error) {
+	list, err := k.appsClient.StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, NewKubernetesError("list_statefulsets", namespace, err)
+	}
+	var managed []appsv1.StatefulSet
+	for _, sts := range list.Items {
+		if OperatorManagedBy(&sts) != "" {
+			managed = append(managed, sts)
+		}
+	}
+	return managed, nil
+}
+
+// IsStatefulSetClaim reports whether claimName is a claim created from a volume claim template
+// of sts, named <template>-<statefulset>-<ordinal>
+func IsStatefulSetClaim(claimName string, sts *appsv1.StatefulSet) bool {
+	for _, template := range sts.Spec.VolumeClaimTemplates {
+		podName, ok := strings.CutPrefix(claimName, template.Name+"-")
+		if !ok {
+			continue
+		}
+		if _, ok := statefulSetOrdinal(podName, sts.Name); ok {
+			return true
+		}
+	}
+	return false
+}
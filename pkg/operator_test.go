@@ -0,0 +1,73 @@
+package pkg
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOperatorManagedBy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		meta metav1.ObjectMeta
+		want string
+	}{
+		{
+			name: "platform owner",
+			meta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{Kind: "HiveMQPlatform", Name: "broker"}}},
+			want: "owned by HiveMQPlatform/broker",
+		},
+		{
+			name: "legacy operator owner",
+			meta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{Kind: "HiveMQCluster", Name: "hivemq"}}},
+			want: "owned by HiveMQCluster/hivemq",
+		},
+		{
+			name: "managed-by label",
+			meta: metav1.ObjectMeta{Labels: map[string]string{managedByLabel: "hivemq-platform-operator"}},
+			want: "labeled app.kubernetes.io/managed-by=hivemq-platform-operator",
+		},
+		{
+			name: "helm release",
+			meta: metav1.ObjectMeta{
+				Labels:          map[string]string{managedByLabel: "Helm"},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "broker"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := OperatorManagedBy(&tt.meta); got != tt.want {
+				t.Errorf("OperatorManagedBy() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsStatefulSetClaim(t *testing.T) {
+	t.Parallel()
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "broker"},
+		Spec: appsv1.StatefulSetSpec{VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+			{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+		}},
+	}
+	for claim, want := range map[string]bool{
+		"data-broker-0":       true,
+		"data-broker-12":      true,
+		"data-broker":         false,
+		"data-broker-canary":  false,
+		"data-other-broker-0": false,
+		"logs-broker-0":       false,
+	} {
+		if got := IsStatefulSetClaim(claim, sts); got != want {
+			t.Errorf("IsStatefulSetClaim(%q) = %v, want %v", claim, got, want)
+		}
+	}
+}
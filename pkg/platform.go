@@ -0,0 +1,124 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// PlatformResource identifies the HiveMQPlatform custom resource of the HiveMQ Platform Operator
+var PlatformResource = schema.GroupVersionResource{
+	Group:    "hivemq.com",
+	Version:  "v1",
+	Resource: "hivemq-platforms",
+}
+
+// PlatformCondition is a status condition reported on a HiveMQPlatform resource
+type PlatformCondition struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"`
+	Reason             string `json:"reason,omitempty"`
+	Message            string `json:"message,omitempty"`
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+}
+
+// Platform is the subset of a HiveMQPlatform resource needed to target its broker pods
+type Platform struct {
+	Name        string              `json:"name"`
+	Namespace   string              `json:"namespace"`
+	State       string              `json:"state,omitempty"`
+	StatefulSet string              `json:"statefulSet"`
+	Conditions  []PlatformCondition `json:"conditions,omitempty"`
+}
+
+// GetDynamicClient returns a dynamic client for custom resources
+func (k *K8sClient) GetDynamicClient() (dynamic.Interface, error) {
+	client, err := dynamic.NewForConfig(k.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	return client, nil
+}
+
+// GetPlatform loads a HiveMQPlatform resource and resolves the StatefulSet that runs its brokers
+func (k *K8sClient) GetPlatform(ctx context.Context, namespace, name string) (*Platform, error) {
+	client, err := k.GetDynamicClient()
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := client.Resource(PlatformResource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("HiveMQPlatform %s not found in namespace %s\n\nPlease either:\n- Check the name with: kubectl get hivemq-platforms -n %s\n- Verify the HiveMQ Platform Operator is installed\n- Use --statefulset to target the StatefulSet directly", name, namespace, namespace)
+		}
+		return nil, NewKubernetesError("get_hivemq_platform", name, err)
+	}
+
+	platform := &Platform{
+		Name:       name,
+		Namespace:  namespace,
+		Conditions: platformConditions(obj),
+	}
+	platform.State, _, _ = unstructured.NestedString(obj.Object, "status", "state")
+
+	statefulSet, err := k.platformStatefulSet(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	platform.StatefulSet = statefulSet
+
+	return platform, nil
+}
+
+// platformStatefulSet finds the StatefulSet owned by the platform. The operator names it after
+// the platform, which is used when no owner reference matches.
+func (k *K8sClient) platformStatefulSet(ctx context.Context, namespace, name string) (string, error) {
+	statefulSets, err := k.appsClient.StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", NewKubernetesError("list_statefulsets", namespace, err)
+	}
+
+	for _, sts := range statefulSets.Items {
+		for _, owner := range sts.OwnerReferences {
+			if owner.Kind == hiveMQPlatformKind && owner.Name == name {
+				return sts.Name, nil
+			}
+		}
+	}
+	for _, sts := range statefulSets.Items {
+		if sts.Name == name {
+			return sts.Name, nil
+		}
+	}
+
+	return "", fmt.Errorf("no StatefulSet found for HiveMQPlatform %s in namespace %s\n\nPlease either:\n- Wait for the operator to reconcile the platform\n- Use --statefulset to target the StatefulSet directly", name, namespace)
+}
+
+func platformConditions(obj *unstructured.Unstructured) []PlatformCondition {
+	raw, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil || !found {
+		return nil
+	}
+
+	conditions := make([]PlatformCondition, 0, len(raw))
+	for _, item := range raw {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condition := PlatformCondition{}
+		condition.Type, _, _ = unstructured.NestedString(fields, "type")
+		condition.Status, _, _ = unstructured.NestedString(fields, "status")
+		condition.Reason, _, _ = unstructured.NestedString(fields, "reason")
+		condition.Message, _, _ = unstructured.NestedString(fields, "message")
+		condition.LastTransitionTime, _, _ = unstructured.NestedString(fields, "lastTransitionTime")
+		conditions = append(conditions, condition)
+	}
+	return conditions
+}
@@ -0,0 +1,175 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// maxPodDiagnosticEvents limits how many recent events are attached to a failed health check
+const maxPodDiagnosticEvents = 5
+
+// PodDiagnostics explains at the Kubernetes level why a pod failed its health check
+type PodDiagnostics struct {
+	Containers []ContainerDiagnostic `json:"containers,omitempty"`
+	Events     []PodEvent            `json:"events,omitempty"`
+}
+
+// ContainerDiagnostic is the state of a single container of a pod
+type ContainerDiagnostic struct {
+	Name                  string `json:"name"`
+	State                 string `json:"state"`
+	Reason                string `json:"reason,omitempty"`
+	Ready                 bool   `json:"ready"`
+	RestartCount          int32  `json:"restartCount"`
+	LastTerminationReason string `json:"lastTerminationReason,omitempty"`
+	LastExitCode          int32  `json:"lastExitCode,omitempty"`
+}
+
+// PodEvent is a recent Kubernetes event involving a pod
+type PodEvent struct {
+	Type     string    `json:"type"`
+	Reason   string    `json:"reason"`
+	Message  string    `json:"message"`
+	Count    int32     `json:"count,omitempty"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// DiagnosePod collects container states and the most recent events of a pod.
+// Event lookup failures are logged and leave the events empty.
+func (k *K8sClient) DiagnosePod(ctx context.Context, pod *v1.Pod) *PodDiagnostics {
+	diagnostics := &PodDiagnostics{Containers: containerDiagnostics(pod)}
+
+	events, err := k.coreClient.Events(pod.Namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.Set{
+			"involvedObject.kind": "Pod",
+			"involvedObject.name": pod.Name,
+		}.AsSelector().String(),
+	})
+	if err != nil {
+		slog.Debug("Could not list pod events", "pod", pod.Name, "namespace", pod.Namespace, "error", err)
+		return diagnostics
+	}
+	diagnostics.Events = recentPodEvents(events.Items, maxPodDiagnosticEvents)
+	return diagnostics
+}
+
+// containerDiagnostics extracts init and regular container states from the pod status
+func containerDiagnostics(pod *v1.Pod) []ContainerDiagnostic {
+	statuses := append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	containers := make([]ContainerDiagnostic, 0, len(statuses))
+	for _, status := range statuses {
+		container := ContainerDiagnostic{
+			Name:         status.Name,
+			Ready:        status.Ready,
+			RestartCount: status.RestartCount,
+		}
+		switch {
+		case status.State.Waiting != nil:
+			container.State = "Waiting"
+			container.Reason = status.State.Waiting.Reason
+		case status.State.Terminated != nil:
+			container.State = "Terminated"
+			container.Reason = status.State.Terminated.Reason
+		case status.State.Running != nil:
+			container.State = "Running"
+		default:
+			container.State = "Unknown"
+		}
+		if terminated := status.LastTerminationState.Terminated; terminated != nil {
+			container.LastTerminationReason = terminated.Reason
+			container.LastExitCode = terminated.ExitCode
+		}
+		containers = append(containers, container)
+	}
+	return containers
+}
+
+// recentPodEvents returns up to limit events, most recent first
+func recentPodEvents(items []v1.Event, limit int) []PodEvent {
+	events := make([]PodEvent, 0, len(items))
+	for _, item := range items {
+		lastSeen := item.LastTimestamp.Time
+		if lastSeen.IsZero() {
+			lastSeen = item.EventTime.Time
+		}
+		if lastSeen.IsZero() {
+			lastSeen = item.CreationTimestamp.Time
+		}
+		events = append(events, PodEvent{
+			Type:     item.Type,
+			Reason:   item.Reason,
+			Message:  strings.TrimSpace(item.Message),
+			Count:    item.Count,
+			LastSeen: lastSeen,
+		})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastSeen.After(events[j].LastSeen)
+	})
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events
+}
+
+// Summary is a one-line explanation for the DETAILS column, e.g.
+// "broker: CrashLoopBackOff, 7 restarts, last exit OOMKilled (137)"
+func (d *PodDiagnostics) Summary() string {
+	if d == nil {
+		return ""
+	}
+
+	var parts []string
+	for _, container := range d.Containers {
+		if (container.Ready && container.State == "Running") || container.Reason == "Completed" {
+			continue
+		}
+		part := container.Name + ": " + container.State
+		if container.Reason != "" {
+			part = container.Name + ": " + container.Reason
+		} else if container.State == "Running" {
+			part += ", not ready"
+		}
+		if container.RestartCount > 0 {
+			part += fmt.Sprintf(", %d restarts", container.RestartCount)
+		}
+		if container.LastTerminationReason != "" {
+			part += fmt.Sprintf(", last exit %s (%d)", container.LastTerminationReason, container.LastExitCode)
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) > 0 {
+		return strings.Join(parts, "; ")
+	}
+
+	// All containers look fine, so the most recent warning is the best explanation
+	for _, event := range d.Events {
+		if event.Type == v1.EventTypeWarning {
+			return event.Reason + ": " + event.Message
+		}
+	}
+	return ""
+}
+
+// attachPodDiagnostics enriches a failed health check result with container states and events
+func (k *K8sClient) attachPodDiagnostics(ctx context.Context, pod *v1.Pod, result *HealthCheckResult) {
+	if ctx.Err() != nil {
+		// The check was cut short; a fresh context still lets the diagnosis explain why
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+	}
+
+	result.Diagnostics = k.DiagnosePod(ctx, pod)
+	if summary := result.Diagnostics.Summary(); summary != "" {
+		result.Details = summary + " - " + result.Details
+	}
+}
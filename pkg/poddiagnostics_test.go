@@ -0,0 +1,65 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodDiagnosticsSummaryExplainsCrashLoop(t *testing.T) {
+	t.Parallel()
+
+	pod := &v1.Pod{Status: v1.PodStatus{
+		InitContainerStatuses: []v1.ContainerStatus{{
+			Name:  "init",
+			State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Completed"}},
+		}},
+		ContainerStatuses: []v1.ContainerStatus{
+			{
+				Name:                 "hivemq",
+				RestartCount:         7,
+				State:                v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+			},
+			{
+				Name:  "sidecar",
+				Ready: true,
+				State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+			},
+		},
+	}}
+
+	diagnostics := &PodDiagnostics{Containers: containerDiagnostics(pod)}
+	if len(diagnostics.Containers) != 3 {
+		t.Fatalf("expected 3 containers, got %+v", diagnostics.Containers)
+	}
+
+	want := "hivemq: CrashLoopBackOff, 7 restarts, last exit OOMKilled (137)"
+	if got := diagnostics.Summary(); got != want {
+		t.Fatalf("Summary() = %q, want %q", got, want)
+	}
+}
+
+func TestPodDiagnosticsSummaryFallsBackToLatestWarning(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	events := recentPodEvents([]v1.Event{
+		{Type: v1.EventTypeWarning, Reason: "FailedMount", Message: "old", LastTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+		{Type: v1.EventTypeNormal, Reason: "Pulled", Message: "image pulled", LastTimestamp: metav1.NewTime(now)},
+		{Type: v1.EventTypeWarning, Reason: "Unhealthy", Message: "Readiness probe failed ", LastTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+	}, 2)
+	if len(events) != 2 || events[0].Reason != "Pulled" {
+		t.Fatalf("expected the two most recent events, got %+v", events)
+	}
+
+	diagnostics := &PodDiagnostics{Events: events}
+	if got := diagnostics.Summary(); got != "Unhealthy: Readiness probe failed" {
+		t.Fatalf("unexpected summary %q", got)
+	}
+	if (*PodDiagnostics)(nil).Summary() != "" {
+		t.Fatal("nil diagnostics should have an empty summary")
+	}
+}
@@ -4,20 +4,26 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
+	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	v1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/httpstream"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/portforward"
 	"k8s.io/client-go/transport/spdy"
 
 	"kubectl-broker/pkg/health"
+	"kubectl-broker/pkg/tracing"
+	"kubectl-broker/pkg/transport"
 )
 
 // PortForwarder manages port-forwarding to a Kubernetes pod
@@ -45,31 +51,21 @@ func (pf *PortForwarder) ForwardPort(ctx context.Context, pod *v1.Pod, remotePor
 		SubResource("portforward")
 
 	// Create SPDY dialer
-	transport, upgrader, err := spdy.RoundTripperFor(pf.config)
+	roundTripper, upgrader, err := spdy.RoundTripperFor(pf.config)
 	if err != nil {
 		return fmt.Errorf("failed to create SPDY round tripper: %w", err)
 	}
 
-	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: roundTripper}, "POST", req.URL())
 
 	// Set up channels for port-forward lifecycle
 	stopChan := make(chan struct{}, 1)
 	readyChan := make(chan struct{})
 	errorChan := make(chan error, 1)
 
-	// Handle interrupt signals
-	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
-
-	go func() {
-		<-signalChan
-		fmt.Println("\nReceived interrupt signal, closing port-forward...")
-		close(stopChan)
-	}()
-
 	// Create port forwarder
 	ports := []string{fmt.Sprintf("%d:%d", localPort, remotePort)}
-	fw, err := portforward.New(dialer, ports, stopChan, readyChan, io.Discard, os.Stderr)
+	fw, err := newLocalForwarder(dialer, ports, stopChan, readyChan, io.Discard, os.Stderr)
 	if err != nil {
 		return fmt.Errorf("failed to create port forwarder: %w", err)
 	}
@@ -84,7 +80,7 @@ func (pf *PortForwarder) ForwardPort(ctx context.Context, pod *v1.Pod, remotePor
 	// Wait for port-forward to be ready or fail
 	select {
 	case <-readyChan:
-		fmt.Printf("Port-forward established: localhost:%d -> %s:%d\n", localPort, pod.Name, remotePort)
+		fmt.Printf("Port-forward established: %s -> %s:%d\n", transport.LocalHostPort(localPort), pod.Name, remotePort)
 
 		// Perform health check
 		if err := pf.performHealthCheck(localPort); err != nil {
@@ -130,7 +126,7 @@ func (pf *PortForwarder) PerformHealthCheckOnly(ctx context.Context, pod *v1.Pod
 
 	// Create port forwarder
 	ports := []string{fmt.Sprintf("%d:%d", localPort, remotePort)}
-	fw, err := portforward.New(dialer, ports, stopChan, readyChan, io.Discard, io.Discard)
+	fw, err := newLocalForwarder(dialer, ports, stopChan, readyChan, io.Discard, io.Discard)
 	if err != nil {
 		return fmt.Errorf("failed to create port forwarder: %w", err)
 	}
@@ -163,7 +159,7 @@ func (pf *PortForwarder) PerformHealthCheckOnly(ctx context.Context, pod *v1.Pod
 // performHealthCheck makes an HTTP request to the health endpoint
 // Deprecated: Use performHealthCheckWithOptions instead
 func (pf *PortForwarder) performHealthCheck(localPort int) error {
-	healthURL := fmt.Sprintf("http://localhost:%d/api/v1/health", localPort)
+	healthURL := transport.LocalURL("http", localPort) + "/api/v1/health"
 
 	fmt.Printf("Performing health check: %s\n", healthURL)
 
@@ -187,7 +183,7 @@ func (pf *PortForwarder) performHealthCheck(localPort int) error {
 // performHealthCheckQuiet makes an HTTP request to the health endpoint without verbose output
 // Deprecated: Use performHealthCheckWithOptions instead
 func (pf *PortForwarder) performHealthCheckQuiet(localPort int) error {
-	healthURL := fmt.Sprintf("http://localhost:%d/api/v1/health", localPort)
+	healthURL := transport.LocalURL("http", localPort) + "/api/v1/health"
 
 	resp, err := http.Get(healthURL)
 	if err != nil {
@@ -204,69 +200,50 @@ func (pf *PortForwarder) performHealthCheckQuiet(localPort int) error {
 
 // PerformHealthCheckWithOptions performs a health check with configurable options
 func (pf *PortForwarder) PerformHealthCheckWithOptions(ctx context.Context, pod *v1.Pod, remotePort int32, localPort int, options health.HealthCheckOptions) (*health.ParsedHealthData, []byte, error) {
-	// Build the port-forward URL
-	req := pf.restClient.Post().
-		Resource("pods").
-		Namespace(pod.Namespace).
-		Name(pod.Name).
-		SubResource("portforward")
-
-	// Create SPDY dialer
-	transport, upgrader, err := spdy.RoundTripperFor(pf.config)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create SPDY round tripper: %w", err)
-	}
-
-	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
-
-	// Set up channels for port-forward lifecycle
-	readyChan := make(chan struct{})
-	stopChan := make(chan struct{})
-	errorChan := make(chan error, 1)
-
-	// Create port forwarder
-	ports := []string{fmt.Sprintf("%d:%d", localPort, remotePort)}
-	forwarder, err := portforward.New(dialer, ports, stopChan, readyChan, io.Discard, io.Discard)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create port forwarder: %w", err)
-	}
+	parsedHealth, rawJSON, _, err := pf.PerformSampledHealthCheck(ctx, pod, remotePort, localPort, options)
+	return parsedHealth, rawJSON, err
+}
 
-	// Start port forwarding in a goroutine
-	go func() {
-		if err := forwarder.ForwardPorts(); err != nil {
-			errorChan <- err
+// PerformSampledHealthCheck sends options.Samples health requests (at least one) through a single
+// port-forward and returns the last response with the latency of every request. The first failed
+// request ends the check.
+func (pf *PortForwarder) PerformSampledHealthCheck(ctx context.Context, pod *v1.Pod, remotePort int32, localPort int, options health.HealthCheckOptions) (*health.ParsedHealthData, []byte, []time.Duration, error) {
+	var (
+		parsedHealth *health.ParsedHealthData
+		rawJSON      []byte
+		latencies    []time.Duration
+	)
+	err := pf.performPortForwarding(ctx, pod, remotePort, localPort, func(localPort int) error {
+		for range max(options.Samples, 1) {
+			start := time.Now()
+			var err error
+			parsedHealth, rawJSON, err = pf.performHealthCheckWithOptions(ctx, localPort, options, pod.Name)
+			if err != nil {
+				return err
+			}
+			latencies = append(latencies, time.Since(start))
 		}
-	}()
-
-	// Wait for port-forward to be ready or error
-	select {
-	case <-readyChan:
-		// Perform health check with options
-		parsedHealth, rawJSON, err := pf.performHealthCheckWithOptions(localPort, options, pod.Name)
-		close(stopChan)
-		return parsedHealth, rawJSON, err
-
-	case err := <-errorChan:
-		close(stopChan)
-		return nil, nil, err
-
-	case <-ctx.Done():
-		close(stopChan)
-		return nil, nil, ctx.Err()
-	}
+		return nil
+	})
+	return parsedHealth, rawJSON, latencies, err
 }
 
 // performHealthCheckWithOptions makes an HTTP request to the specified health endpoint with options
-func (pf *PortForwarder) performHealthCheckWithOptions(localPort int, options health.HealthCheckOptions, podName string) (*health.ParsedHealthData, []byte, error) {
-	endpointPath := health.GetHealthEndpointPath(options.Endpoint)
-	healthURL := fmt.Sprintf("http://localhost:%d%s", localPort, endpointPath)
+func (pf *PortForwarder) performHealthCheckWithOptions(ctx context.Context, localPort int, options health.HealthCheckOptions, podName string) (*health.ParsedHealthData, []byte, error) {
+	endpointPath := options.RequestPath()
+	healthURL := options.TLS.BaseURL(localPort) + endpointPath
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: options.Timeout,
+	// Create HTTP client with timeout and TLS settings
+	client, err := transport.NewHTTPClient(options.Timeout, options.TLS)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	resp, err := client.Get(healthURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, healthURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create health request: %w", err)
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to connect to health endpoint: %w", err)
 	}
@@ -287,7 +264,7 @@ func (pf *PortForwarder) performHealthCheckWithOptions(localPort int, options he
 
 	// If JSON output is requested, parse but don't analyze
 	if options.OutputJSON {
-		parsed, err := health.ParseHealthResponseWithPodName(body, podName)
+		parsed, err := health.ParseHealthResponseWithOptions(body, podName, health.ParseOptions{Strict: options.Strict})
 		if err != nil {
 			// Still return raw JSON if parsing fails
 			return nil, rawJSON, err
@@ -296,7 +273,7 @@ func (pf *PortForwarder) performHealthCheckWithOptions(localPort int, options he
 	}
 
 	// Parse the response for analyzed output
-	parsed, err := health.ParseHealthResponseWithPodName(body, podName)
+	parsed, err := health.ParseHealthResponseWithOptions(body, podName, health.ParseOptions{Strict: options.Strict})
 	if err != nil {
 		return nil, rawJSON, fmt.Errorf("failed to parse health response: %w", err)
 	}
@@ -306,47 +283,94 @@ func (pf *PortForwarder) performHealthCheckWithOptions(localPort int, options he
 
 // PerformWithPortForwarding performs a generic operation with port forwarding established to a pod
 func (pf *PortForwarder) PerformWithPortForwarding(ctx context.Context, pod *v1.Pod, remotePort int32, localPort int, operation func(localPort int) error) error {
-	// Build the port-forward URL
-	req := pf.restClient.Post().
-		Resource("pods").
-		Namespace(pod.Namespace).
-		Name(pod.Name).
-		SubResource("portforward")
-
-	return pf.performPortForwarding(ctx, req, remotePort, localPort, operation)
+	return pf.performPortForwarding(ctx, pod, remotePort, localPort, operation)
 }
 
-// PerformWithServicePortForwarding performs a generic operation with port forwarding established to a service
-// This works by finding a ready pod behind the service and port-forwarding to it
+// PerformWithServicePortForwarding performs a generic operation with port forwarding established to a service.
+// It forwards to a ready pod behind the service, preferring Running pods. When that pod is deleted or
+// stops being ready while operation runs, as during rolling upgrades, the forward moves to another
+// ready pod on the same local port; requests in flight during the switch fail and the API clients
+// retry the read-only ones. Requests that may change state are not repeated, as the old pod may
+// have acted on them.
 func (pf *PortForwarder) PerformWithServicePortForwarding(ctx context.Context, k8sClient *K8sClient, service *v1.Service, remotePort int32, localPort int, operation func(localPort int) error) error {
-	// Use EndpointSlices to find a ready pod
-	slices, err := pf.getEndpointSlicesForService(ctx, k8sClient, service)
+	pod, err := ReadyPodForService(ctx, k8sClient, service)
 	if err != nil {
 		return err
 	}
 
-	targetPodName := selectReadyPodFromSlices(slices)
-	if targetPodName == "" {
-		return fmt.Errorf("no ready pods found for service %s", service.Name)
+	failover := func(ctx context.Context, lost *v1.Pod) *v1.Pod {
+		return failoverPod(ctx, k8sClient, service, lost)
 	}
+	return pf.performWithMaintainedPortForward(ctx, pod, remotePort, localPort, failover, operation)
+}
+
+// ReadyPodForService returns a ready pod listed in the EndpointSlices of the service, the pod a
+// service port-forward would reach. Running pods win over pods in other phases.
+func ReadyPodForService(ctx context.Context, k8sClient *K8sClient, service *v1.Service) (*v1.Pod, error) {
+	return readyPodForService(ctx, k8sClient, service, "")
+}
 
-	// Get the pod object
-	pod, err := k8sClient.coreClient.Pods(service.Namespace).Get(ctx, targetPodName, metav1.GetOptions{})
+// readyPodForService is ReadyPodForService skipping the pod named exclude
+func readyPodForService(ctx context.Context, k8sClient *K8sClient, service *v1.Service, exclude string) (*v1.Pod, error) {
+	slices, err := getEndpointSlicesForService(ctx, k8sClient, service)
 	if err != nil {
-		return fmt.Errorf("failed to get pod %s: %w", targetPodName, err)
+		return nil, err
+	}
+
+	var fallback *v1.Pod
+	var lastErr error
+	for _, name := range selectReadyPodsFromSlices(slices, exclude) {
+		pod, err := k8sClient.GetPod(ctx, service.Namespace, name)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if pod.Status.Phase == v1.PodRunning && pod.DeletionTimestamp == nil {
+			return pod, nil
+		}
+		if fallback == nil {
+			fallback = pod
+		}
 	}
 
-	// Use regular pod port-forwarding
-	return pf.PerformWithPortForwarding(ctx, pod, remotePort, localPort, operation)
+	switch {
+	case fallback != nil:
+		return fallback, nil
+	case lastErr != nil:
+		return nil, lastErr
+	default:
+		return nil, fmt.Errorf("no ready pods found for service %s", service.Name)
+	}
 }
 
-func (pf *PortForwarder) getEndpointSlicesForService(ctx context.Context, k8sClient *K8sClient, service *v1.Service) ([]discoveryv1.EndpointSlice, error) {
+// failoverPod returns the pod for the next forward session to the service after the session to
+// lost broke: lost itself while it still serves, otherwise another ready pod of the service
+func failoverPod(ctx context.Context, k8sClient *K8sClient, service *v1.Service, lost *v1.Pod) *v1.Pod {
+	// Bypass the lookup cache, the pod may have been deleted seconds ago
+	current, err := k8sClient.coreClient.Pods(lost.Namespace).Get(ctx, lost.Name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return lost
+	}
+	if err == nil && current.UID == lost.UID && current.DeletionTimestamp == nil && podReady(current) {
+		return lost
+	}
+
+	next, err := readyPodForService(ctx, k8sClient, service, lost.Name)
+	if err != nil {
+		slog.Warn("Pod behind port-forward is gone and no other pod is ready", "service", service.Name, "pod", lost.Name, "error", err)
+		return lost
+	}
+	slog.Warn("Pod behind port-forward is gone, switching to another ready pod", "service", service.Name, "from", lost.Name, "to", next.Name)
+	return next
+}
+
+func getEndpointSlicesForService(ctx context.Context, k8sClient *K8sClient, service *v1.Service) ([]discoveryv1.EndpointSlice, error) {
 	selector := labels.Set(map[string]string{
 		discoveryv1.LabelServiceName: service.Name,
 	}).AsSelector().String()
 
-	sliceList, err := k8sClient.discovery.EndpointSlices(service.Namespace).List(ctx, metav1.ListOptions{
-		LabelSelector: selector,
+	sliceList, err := cachedLookup(k8sClient, []string{"endpointslices", service.Namespace, selector}, func() (*discoveryv1.EndpointSliceList, error) {
+		return k8sClient.discovery.EndpointSlices(service.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get endpoint slices for service %s: %w", service.Name, err)
@@ -354,36 +378,64 @@ func (pf *PortForwarder) getEndpointSlicesForService(ctx context.Context, k8sCli
 	return sliceList.Items, nil
 }
 
-func selectReadyPodFromSlices(slices []discoveryv1.EndpointSlice) string {
+// selectReadyPodsFromSlices returns the pods behind ready, non-terminating endpoints in slice
+// order, each once and without exclude
+func selectReadyPodsFromSlices(slices []discoveryv1.EndpointSlice, exclude string) []string {
+	var names []string
+	seen := map[string]bool{exclude: true}
 	for _, slice := range slices {
 		for _, endpoint := range slice.Endpoints {
 			if !isEndpointReady(endpoint) {
 				continue
 			}
-			if endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" {
-				return endpoint.TargetRef.Name
+			if endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" && !seen[endpoint.TargetRef.Name] {
+				seen[endpoint.TargetRef.Name] = true
+				names = append(names, endpoint.TargetRef.Name)
 			}
 		}
 	}
-	return ""
+	return names
 }
 
 func isEndpointReady(endpoint discoveryv1.Endpoint) bool {
+	if endpoint.Conditions.Terminating != nil && *endpoint.Conditions.Terminating {
+		return false
+	}
 	if endpoint.Conditions.Ready != nil {
 		return *endpoint.Conditions.Ready
 	}
 	return true
 }
 
-// performPortForwarding is the common implementation for both pod and service port forwarding
-func (pf *PortForwarder) performPortForwarding(ctx context.Context, req *rest.Request, remotePort int32, localPort int, operation func(localPort int) error) error {
-	// Create SPDY dialer
+// podDialer creates the SPDY dialer for the pod's portforward subresource
+func (pf *PortForwarder) podDialer(pod *v1.Pod) (httpstream.Dialer, error) {
+	req := pf.restClient.Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward")
+
 	transport, upgrader, err := spdy.RoundTripperFor(pf.config)
 	if err != nil {
-		return fmt.Errorf("failed to create SPDY round tripper: %w", err)
+		return nil, fmt.Errorf("failed to create SPDY round tripper: %w", err)
 	}
+	return spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL()), nil
+}
 
-	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
+// newLocalForwarder creates a port forwarder that listens on the configured local address
+func newLocalForwarder(dialer httpstream.Dialer, ports []string, stopChan <-chan struct{}, readyChan chan struct{}, out, errOut io.Writer) (*portforward.PortForwarder, error) {
+	return portforward.NewOnAddresses(dialer, []string{transport.LocalAddress()}, ports, stopChan, readyChan, out, errOut)
+}
+
+// performPortForwarding is the common implementation for both pod and service port forwarding.
+// When the cluster forbids port-forwarding, HTTP requests are tunnelled through pod exec instead.
+func (pf *PortForwarder) performPortForwarding(ctx context.Context, pod *v1.Pod, remotePort int32, localPort int, operation func(localPort int) error) error {
+	_, span := startPortForwardSpan(ctx, pod, remotePort)
+	dialer, err := pf.podDialer(pod)
+	if err != nil {
+		tracing.End(span, err)
+		return err
+	}
 
 	// Set up channels for port-forward lifecycle
 	readyChan := make(chan struct{})
@@ -392,9 +444,11 @@ func (pf *PortForwarder) performPortForwarding(ctx context.Context, req *rest.Re
 
 	// Create port forwarder
 	ports := []string{fmt.Sprintf("%d:%d", localPort, remotePort)}
-	forwarder, err := portforward.New(dialer, ports, stopChan, readyChan, io.Discard, io.Discard)
+	forwarder, err := newLocalForwarder(dialer, ports, stopChan, readyChan, io.Discard, io.Discard)
 	if err != nil {
-		return fmt.Errorf("failed to create port forwarder: %w", err)
+		err = fmt.Errorf("failed to create port forwarder: %w", err)
+		tracing.End(span, err)
+		return err
 	}
 
 	// Start port forwarding in a goroutine
@@ -408,16 +462,34 @@ func (pf *PortForwarder) performPortForwarding(ctx context.Context, req *rest.Re
 	select {
 	case <-readyChan:
 		// Port forwarding is ready, perform the operation
+		tracing.End(span, nil)
 		err := operation(localPort)
 		close(stopChan)
+		if err != nil {
+			RecordInterruption(ctx, fmt.Sprintf("port-forward to %s:%d closed", pod.Name, remotePort))
+		}
 		return err
 
 	case err := <-errorChan:
 		close(stopChan)
+		tracing.End(span, err)
+		if isPortForwardForbidden(err) {
+			return pf.performWithExecHTTP(ctx, pod, remotePort, localPort, operation)
+		}
 		return err
 
 	case <-ctx.Done():
 		close(stopChan)
+		tracing.End(span, ctx.Err())
+		RecordInterruption(ctx, fmt.Sprintf("port-forward to %s:%d cancelled while connecting", pod.Name, remotePort))
 		return ctx.Err()
 	}
 }
+
+// startPortForwardSpan traces establishing a port-forward session up to the point it is ready
+func startPortForwardSpan(ctx context.Context, pod *v1.Pod, remotePort int32) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "portforward.setup",
+		attribute.String("k8s.namespace.name", pod.Namespace),
+		attribute.String("k8s.pod.name", pod.Name),
+		attribute.Int("kubectl_broker.remote_port", int(remotePort)))
+}
@@ -0,0 +1,59 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// PortForwardDialer makes a remote API reachable on a local port while fn runs. Backup and
+// sidecar engines only depend on this interface, so tools embedding them can supply their own
+// tunnel and tests can point them at a local server.
+type PortForwardDialer interface {
+	Forward(ctx context.Context, fn func(localPort int) error) error
+}
+
+// PortForwardDialerFunc adapts a function to the PortForwardDialer interface
+type PortForwardDialerFunc func(ctx context.Context, fn func(localPort int) error) error
+
+// Forward calls f(ctx, fn)
+func (f PortForwardDialerFunc) Forward(ctx context.Context, fn func(localPort int) error) error {
+	return f(ctx, fn)
+}
+
+// LocalDialer is a PortForwardDialer for an API that already listens on localhost, e.g. when
+// running next to the broker or behind an existing kubectl port-forward
+func LocalDialer(localPort int) PortForwardDialer {
+	return PortForwardDialerFunc(func(_ context.Context, fn func(localPort int) error) error {
+		return fn(localPort)
+	})
+}
+
+// NewServiceDialer forwards a random local port to remotePort of a ready pod behind the Service
+func NewServiceDialer(k8sClient *K8sClient, service *v1.Service, remotePort int32) PortForwardDialer {
+	return PortForwardDialerFunc(func(ctx context.Context, fn func(localPort int) error) error {
+		localPort, err := GetRandomPort()
+		if err != nil {
+			return fmt.Errorf("failed to get random port: %w", err)
+		}
+		pf := NewPortForwarder(k8sClient.GetConfig(), k8sClient.GetRESTClient())
+		return pf.PerformWithServicePortForwarding(ctx, k8sClient, service, remotePort, localPort, fn)
+	})
+}
+
+// NewPodDialer forwards a random local port to remotePort of the pod. With reconnect, dropped
+// forward sessions are re-established for as long as fn runs.
+func NewPodDialer(k8sClient *K8sClient, pod *v1.Pod, remotePort int32, reconnect bool) PortForwardDialer {
+	return PortForwardDialerFunc(func(ctx context.Context, fn func(localPort int) error) error {
+		localPort, err := GetRandomPort()
+		if err != nil {
+			return fmt.Errorf("failed to get random port: %w", err)
+		}
+		pf := NewPortForwarder(k8sClient.GetConfig(), k8sClient.GetRESTClient())
+		if reconnect {
+			return pf.PerformWithReconnectingPortForwarding(ctx, pod, remotePort, localPort, fn)
+		}
+		return pf.PerformWithPortForwarding(ctx, pod, remotePort, localPort, fn)
+	})
+}
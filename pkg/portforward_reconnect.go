@@ -0,0 +1,149 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg/tracing"
+)
+
+// Backoff between attempts to re-establish a lost port-forward session
+const (
+	reconnectInitialBackoff = 500 * time.Millisecond
+	reconnectMaxBackoff     = 15 * time.Second
+)
+
+// PerformWithReconnectingPortForwarding is PerformWithPortForwarding for long-running operations:
+// when intermediaries close the forward session, it is re-established on the same local port until
+// operation returns. Requests in flight while the session is down fail and must be retried by the
+// caller; only the first session has to come up for operation to start.
+func (pf *PortForwarder) PerformWithReconnectingPortForwarding(ctx context.Context, pod *v1.Pod, remotePort int32, localPort int, operation func(localPort int) error) error {
+	return pf.performWithMaintainedPortForward(ctx, pod, remotePort, localPort, nil, operation)
+}
+
+// performWithMaintainedPortForward runs operation while forward sessions are re-established on
+// localPort. After a session is lost, failover (if set) picks the pod for the next session.
+func (pf *PortForwarder) performWithMaintainedPortForward(ctx context.Context, pod *v1.Pod, remotePort int32, localPort int, failover func(ctx context.Context, lost *v1.Pod) *v1.Pod, operation func(localPort int) error) error {
+	sessionCtx, cancel := context.WithCancel(ctx)
+	sessionDone := make(chan struct{})
+	defer func() {
+		cancel()
+		<-sessionDone
+	}()
+
+	ready := make(chan struct{})
+	failed := make(chan error, 1)
+	go func() {
+		defer close(sessionDone)
+		pf.maintainPortForward(sessionCtx, pod, remotePort, localPort, failover, ready, failed)
+	}()
+
+	select {
+	case <-ready:
+		return operation(localPort)
+	case err := <-failed:
+		if isPortForwardForbidden(err) {
+			return pf.performWithExecHTTP(ctx, pod, remotePort, localPort, operation)
+		}
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// maintainPortForward keeps a forward session running until ctx ends. It closes ready once the
+// first session is up; if that first session cannot be established, the error goes to failed.
+func (pf *PortForwarder) maintainPortForward(ctx context.Context, pod *v1.Pod, remotePort int32, localPort int, failover func(ctx context.Context, lost *v1.Pod) *v1.Pod, ready chan<- struct{}, failed chan<- error) {
+	established := false
+	backoff := reconnectInitialBackoff
+	for {
+		err := pf.forwardSession(ctx, pod, remotePort, localPort, func() {
+			backoff = reconnectInitialBackoff
+			if !established {
+				established = true
+				close(ready)
+				return
+			}
+			slog.Info("Port-forward re-established", "pod", pod.Name, "localPort", localPort)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if !established {
+			failed <- err
+			return
+		}
+
+		slog.Warn("Port-forward lost, reconnecting", "pod", pod.Name, "retryIn", backoff, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > reconnectMaxBackoff {
+			backoff = reconnectMaxBackoff
+		}
+		if failover != nil {
+			if next := failover(ctx, pod); next.UID != pod.UID {
+				pod, backoff = next, reconnectInitialBackoff
+			}
+		}
+	}
+}
+
+// forwardSession runs a single port-forward session until it is lost or ctx ends
+func (pf *PortForwarder) forwardSession(ctx context.Context, pod *v1.Pod, remotePort int32, localPort int, onReady func()) error {
+	_, span := startPortForwardSpan(ctx, pod, remotePort)
+	dialer, err := pf.podDialer(pod)
+	if err != nil {
+		tracing.End(span, err)
+		return err
+	}
+
+	readyChan := make(chan struct{})
+	stopChan := make(chan struct{})
+	ports := []string{fmt.Sprintf("%d:%d", localPort, remotePort)}
+	forwarder, err := newLocalForwarder(dialer, ports, stopChan, readyChan, io.Discard, io.Discard)
+	if err != nil {
+		err = fmt.Errorf("failed to create port forwarder: %w", err)
+		tracing.End(span, err)
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- forwarder.ForwardPorts()
+	}()
+
+	ready := readyChan
+	for {
+		select {
+		case <-ready:
+			ready = nil
+			tracing.End(span, nil)
+			onReady()
+		case err := <-done:
+			if err == nil {
+				err = errors.New("port-forward closed")
+			}
+			if ready != nil {
+				tracing.End(span, err)
+			}
+			return err
+		case <-ctx.Done():
+			// Closing stopChan ends ForwardPorts in the background once it has dialed
+			close(stopChan)
+			if ready != nil {
+				tracing.End(span, ctx.Err())
+			}
+			RecordInterruption(ctx, fmt.Sprintf("port-forward to %s:%d closed", pod.Name, remotePort))
+			return ctx.Err()
+		}
+	}
+}
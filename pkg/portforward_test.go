@@ -0,0 +1,40 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+)
+
+func TestSelectReadyPodsFromSlices(t *testing.T) {
+	t.Parallel()
+
+	ready, notReady := true, false
+	endpoint := func(pod string, ready, terminating *bool) discoveryv1.Endpoint {
+		return discoveryv1.Endpoint{
+			TargetRef:  &v1.ObjectReference{Kind: "Pod", Name: pod},
+			Conditions: discoveryv1.EndpointConditions{Ready: ready, Terminating: terminating},
+		}
+	}
+	slices := []discoveryv1.EndpointSlice{
+		{Endpoints: []discoveryv1.Endpoint{
+			endpoint("broker-0", &notReady, nil),
+			endpoint("broker-1", &ready, &ready),
+			endpoint("broker-2", &ready, &notReady),
+		}},
+		{Endpoints: []discoveryv1.Endpoint{
+			endpoint("broker-2", &ready, nil),
+			endpoint("broker-3", nil, nil),
+			{Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+		}},
+	}
+
+	if got := strings.Join(selectReadyPodsFromSlices(slices, ""), ","); got != "broker-2,broker-3" {
+		t.Errorf("selectReadyPodsFromSlices() = %s, want broker-2,broker-3", got)
+	}
+	if got := strings.Join(selectReadyPodsFromSlices(slices, "broker-2"), ","); got != "broker-3" {
+		t.Errorf("selectReadyPodsFromSlices() excluding broker-2 = %s, want broker-3", got)
+	}
+}
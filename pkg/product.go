@@ -0,0 +1,348 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Products with a profile
+const (
+	ProductBroker = "broker"
+	ProductPulse  = "pulse"
+	ProductEdge   = "edge"
+)
+
+// EdgeSelector selects HiveMQ Edge pods
+const EdgeSelector = "app.kubernetes.io/name=hivemq-edge"
+
+// ProductProfile holds the per-product defaults health checks and API calls start from
+type ProductProfile struct {
+	Product     string
+	DisplayName string
+	// Selector finds the product's pods; empty for products discovered through their StatefulSet
+	Selector string
+	// HealthPortName is the container port serving the health API; HealthPort is used when
+	// no port carries that name (0 when there is no fallback)
+	HealthPortName string
+	HealthPort     int32
+	// HealthEndpoints are the endpoint names accepted by --endpoint, the first being the default
+	HealthEndpoints []string
+	// APIPortName and APIPort locate the product's REST API the same way
+	APIPortName string
+	APIPort     int32
+	// Workload is the default name of the StatefulSet or Deployment (WorkloadKind) running the
+	// product in a standard Helm install; empty when the product has no default workload
+	Workload     string
+	WorkloadKind string
+}
+
+// Workload kinds of ProductProfile.WorkloadKind
+const (
+	WorkloadStatefulSet = "StatefulSet"
+	WorkloadDeployment  = "Deployment"
+)
+
+// productProfiles is the profile table; ports are the defaults of the official images and charts
+var productProfiles = map[string]ProductProfile{
+	ProductBroker: {
+		Product:         ProductBroker,
+		DisplayName:     "HiveMQ Broker",
+		HealthPortName:  "health",
+		HealthEndpoints: []string{"health", "liveness", "readiness"},
+		APIPortName:     "api",
+		APIPort:         8081,
+		Workload:        "broker",
+		WorkloadKind:    WorkloadStatefulSet,
+	},
+	ProductPulse: {
+		Product:         ProductPulse,
+		DisplayName:     "HiveMQ Pulse",
+		Selector:        PulseServerSelector,
+		HealthPortName:  "internal-http",
+		HealthEndpoints: []string{"liveness", "readiness"},
+		// The admin API shares the internal HTTP server with the health endpoints
+		APIPortName:  "internal-http",
+		Workload:     "hivemq-pulse-server",
+		WorkloadKind: WorkloadDeployment,
+	},
+	ProductEdge: {
+		Product:         ProductEdge,
+		DisplayName:     "HiveMQ Edge",
+		Selector:        EdgeSelector,
+		HealthPortName:  "http",
+		HealthPort:      8080,
+		HealthEndpoints: []string{"liveness", "readiness"},
+		APIPortName:     "http",
+		APIPort:         8080,
+	},
+}
+
+// PortNames replaces the port names of every profile, for charts that name their ports
+// differently; empty fields keep the profile's names. The numeric fallbacks still apply.
+type PortNames struct {
+	Health string
+	API    string
+}
+
+var portNameOverrides atomic.Pointer[PortNames]
+
+// SetPortNames installs port name overrides for all later port lookups
+func SetPortNames(names PortNames) {
+	portNameOverrides.Store(&names)
+}
+
+// ProductDefaults replaces defaults of a product profile, for installs that do not follow the
+// standard Helm chart; empty fields keep the profile's values
+type ProductDefaults struct {
+	Workload   string
+	HealthPort int32
+	Endpoint   string
+	Selector   string
+}
+
+var (
+	productDefaultsMu        sync.RWMutex
+	productDefaultsOverrides = map[string]ProductDefaults{}
+)
+
+// SetProductDefaults installs default overrides of a product for all later profile lookups
+func SetProductDefaults(product string, defaults ProductDefaults) error {
+	profile, ok := productProfiles[product]
+	if !ok {
+		return fmt.Errorf("unknown product %q", product)
+	}
+	if defaults.Endpoint != "" && !profile.SupportsEndpoint(defaults.Endpoint) {
+		return NewValidationError("product_defaults", product, fmt.Sprintf("endpoint %q is not one of %s", defaults.Endpoint, strings.Join(profile.HealthEndpoints, ", ")))
+	}
+	if defaults.Selector != "" {
+		if err := ValidateLabelSelector(defaults.Selector); err != nil {
+			return err
+		}
+	}
+
+	productDefaultsMu.Lock()
+	defer productDefaultsMu.Unlock()
+	productDefaultsOverrides[product] = defaults
+	return nil
+}
+
+// ProfileFor returns the profile of a product, falling back to the broker profile, with the
+// overrides of SetProductDefaults applied
+func ProfileFor(product string) ProductProfile {
+	profile, ok := productProfiles[product]
+	if !ok {
+		profile = productProfiles[ProductBroker]
+	}
+
+	productDefaultsMu.RLock()
+	defaults := productDefaultsOverrides[profile.Product]
+	productDefaultsMu.RUnlock()
+
+	if defaults.Workload != "" {
+		profile.Workload = defaults.Workload
+	}
+	if defaults.HealthPort > 0 {
+		profile.HealthPort = defaults.HealthPort
+	}
+	if defaults.Endpoint != "" {
+		// The default endpoint is the first one; keep the others accepted
+		endpoints := []string{defaults.Endpoint}
+		for _, endpoint := range profile.HealthEndpoints {
+			if endpoint != defaults.Endpoint {
+				endpoints = append(endpoints, endpoint)
+			}
+		}
+		profile.HealthEndpoints = endpoints
+	}
+	if defaults.Selector != "" {
+		profile.Selector = defaults.Selector
+	}
+	return profile
+}
+
+// ProductDefaultsFromEnv reads the default overrides of a product from KUBECTL_<PRODUCT>_<KIND>
+// (e.g. KUBECTL_PULSE_DEPLOYMENT), _PORT, _ENDPOINT and, for products found by label, _SELECTOR
+func ProductDefaultsFromEnv(product string, getenv func(string) string) (ProductDefaults, error) {
+	profile := ProfileFor(product)
+	lookup := func(setting string) string {
+		return strings.TrimSpace(getenv(ProductEnvVar(product, setting)))
+	}
+
+	var defaults ProductDefaults
+	if profile.WorkloadKind != "" {
+		defaults.Workload = lookup(strings.ToUpper(profile.WorkloadKind))
+	}
+	if value := lookup("PORT"); value != "" {
+		port, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || port < 1 || port > 65535 {
+			return ProductDefaults{}, NewValidationError("product_defaults", ProductEnvVar(product, "PORT"), fmt.Sprintf("invalid port %q, must be between 1 and 65535", value))
+		}
+		defaults.HealthPort = int32(port)
+	}
+	defaults.Endpoint = lookup("ENDPOINT")
+	if profile.Selector != "" {
+		defaults.Selector = lookup("SELECTOR")
+	}
+	return defaults, nil
+}
+
+// ProductEnvVar is the environment variable overriding a default of a product, e.g.
+// KUBECTL_PULSE_PORT
+func ProductEnvVar(product, setting string) string {
+	return "KUBECTL_" + strings.ToUpper(product) + "_" + setting
+}
+
+// DefaultEndpoint is the health endpoint checked when --endpoint is not set
+func (p ProductProfile) DefaultEndpoint() string {
+	if len(p.HealthEndpoints) == 0 {
+		return "health"
+	}
+	return p.HealthEndpoints[0]
+}
+
+// SupportsEndpoint reports whether endpoint is one of the product's health endpoints
+func (p ProductProfile) SupportsEndpoint(endpoint string) bool {
+	for _, candidate := range p.HealthEndpoints {
+		if candidate == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
+// HealthPortFor finds the health port of a pod of this product
+func (p ProductProfile) HealthPortFor(pod *v1.Pod) (int32, error) {
+	return findContainerPort(pod, p.EffectiveHealthPortName(), p.HealthPort)
+}
+
+// APIPortFor finds the REST API port of a pod of this product
+func (p ProductProfile) APIPortFor(pod *v1.Pod) (int32, error) {
+	return findContainerPort(pod, p.EffectiveAPIPortName(), p.APIPort)
+}
+
+// ServiceAPIPortFor finds the REST API port of a service in front of this product's pods
+func (p ProductProfile) ServiceAPIPortFor(service *v1.Service) (int32, error) {
+	name := p.EffectiveAPIPortName()
+	var availablePorts []string
+	for _, port := range service.Spec.Ports {
+		if port.Name == name || (p.APIPort > 0 && port.Port == p.APIPort) {
+			return port.Port, nil
+		}
+		availablePorts = append(availablePorts, fmt.Sprintf("%s(%d)", port.Name, port.Port))
+	}
+	if len(availablePorts) == 0 {
+		return 0, fmt.Errorf("no ports found in service %s", service.Name)
+	}
+	return 0, fmt.Errorf("API port not found in service %s (expected %s). Available ports: %v", service.Name, p.apiPortDescription(), availablePorts)
+}
+
+// EffectiveHealthPortName is the health port name after --health-port-name
+func (p ProductProfile) EffectiveHealthPortName() string {
+	if names := portNameOverrides.Load(); names != nil && names.Health != "" {
+		return names.Health
+	}
+	return p.HealthPortName
+}
+
+// EffectiveAPIPortName is the API port name after --api-port-name
+func (p ProductProfile) EffectiveAPIPortName() string {
+	if names := portNameOverrides.Load(); names != nil && names.API != "" {
+		return names.API
+	}
+	return p.APIPortName
+}
+
+// apiPortDescription describes the API port lookup for error messages
+func (p ProductProfile) apiPortDescription() string {
+	if p.APIPort > 0 {
+		return fmt.Sprintf("port named '%s' or port %d", p.EffectiveAPIPortName(), p.APIPort)
+	}
+	return fmt.Sprintf("port named '%s'", p.EffectiveAPIPortName())
+}
+
+// findContainerPort prefers a port named name and falls back to a container exposing fallback
+func findContainerPort(pod *v1.Pod, name string, fallback int32) (int32, error) {
+	var availablePorts []string
+	fallbackFound := false
+
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if name != "" && port.Name == name {
+				return port.ContainerPort, nil
+			}
+			if fallback > 0 && port.ContainerPort == fallback {
+				fallbackFound = true
+			}
+			availablePorts = append(availablePorts, fmt.Sprintf("%s(%d)", port.Name, port.ContainerPort))
+		}
+	}
+
+	// Images often skip declaring ports, so the product default is tried even when undeclared
+	if fallbackFound || (fallback > 0 && len(availablePorts) == 0) {
+		return fallback, nil
+	}
+	if len(availablePorts) == 0 {
+		return 0, fmt.Errorf("could not find port named '%s' in pod %s\n\nNo container ports found. Use --port/-p to specify manually", name, pod.Name)
+	}
+	return 0, fmt.Errorf("could not find port named '%s' in pod %s\n\nAvailable ports: %v\nUse --port/-p to specify manually", name, pod.Name, availablePorts)
+}
+
+// GetProductPods lists the pods of a profile's selector, skipping pods that are terminating.
+// When the selector matches nothing, the pods of the profile's default workload are used, so
+// installs labelling their pods differently are still found.
+func (k *K8sClient) GetProductPods(ctx context.Context, namespace string, profile ProductProfile) ([]*v1.Pod, error) {
+	podList, err := k.coreClient.Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: profile.Selector})
+	if err != nil {
+		return nil, NewKubernetesError("list_pods", namespace, err)
+	}
+	if len(podList.Items) == 0 && profile.Workload != "" {
+		if selector, ok := k.workloadSelector(ctx, namespace, profile); ok {
+			podList, err = k.coreClient.Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+			if err != nil {
+				return nil, NewKubernetesError("list_pods", namespace, err)
+			}
+		}
+	}
+
+	pods := make([]*v1.Pod, 0, len(podList.Items))
+	for i := range podList.Items {
+		if podList.Items[i].DeletionTimestamp != nil {
+			continue
+		}
+		pods = append(pods, &podList.Items[i])
+	}
+	return pods, nil
+}
+
+// workloadSelector returns the pod selector of the profile's default workload; a missing
+// workload is not an error, the product is then simply not found
+func (k *K8sClient) workloadSelector(ctx context.Context, namespace string, profile ProductProfile) (string, bool) {
+	var selector *metav1.LabelSelector
+	switch profile.WorkloadKind {
+	case WorkloadDeployment:
+		deployment, err := k.appsClient.Deployments(namespace).Get(ctx, profile.Workload, metav1.GetOptions{})
+		if err != nil {
+			return "", false
+		}
+		selector = deployment.Spec.Selector
+	case WorkloadStatefulSet:
+		sts, err := k.GetStatefulSet(ctx, namespace, profile.Workload)
+		if err != nil {
+			return "", false
+		}
+		selector = sts.Spec.Selector
+	}
+	if selector == nil {
+		return "", false
+	}
+	slog.Debug("Using pods of the default workload", "kind", profile.WorkloadKind, "name", profile.Workload, "namespace", namespace)
+	return metav1.FormatLabelSelector(selector), true
+}
@@ -0,0 +1,124 @@
+package pkg
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestProductProfileHealthPortFor(t *testing.T) {
+	t.Parallel()
+
+	podWithPorts := func(ports ...v1.ContainerPort) *v1.Pod {
+		pod := &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "edge", Ports: ports}}}}
+		pod.Name = "edge-0"
+		return pod
+	}
+	edgeProfile := ProfileFor(ProductEdge)
+
+	tests := []struct {
+		name    string
+		profile ProductProfile
+		pod     *v1.Pod
+		want    int32
+		wantErr bool
+	}{
+		{"named port wins", edgeProfile, podWithPorts(v1.ContainerPort{Name: "mqtt", ContainerPort: 1883}, v1.ContainerPort{Name: "http", ContainerPort: 9080}), 9080, false},
+		{"default port by number", edgeProfile, podWithPorts(v1.ContainerPort{Name: "web", ContainerPort: 8080}), 8080, false},
+		{"default port when none declared", edgeProfile, podWithPorts(), 8080, false},
+		{"other ports only", edgeProfile, podWithPorts(v1.ContainerPort{Name: "mqtt", ContainerPort: 1883}), 0, true},
+		{"no fallback without named port", ProfileFor(ProductPulse), podWithPorts(), 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.profile.HealthPortFor(tt.pod)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HealthPortFor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("HealthPortFor() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfileForFallsBackToBroker(t *testing.T) {
+	t.Parallel()
+
+	if got := ProfileFor("unknown").Product; got != ProductBroker {
+		t.Fatalf("ProfileFor(unknown) = %s, want %s", got, ProductBroker)
+	}
+	if endpoint := ProfileFor(ProductEdge).DefaultEndpoint(); endpoint != "liveness" {
+		t.Fatalf("Edge default endpoint = %s, want liveness", endpoint)
+	}
+}
+
+func TestPortNameOverrides(t *testing.T) {
+	// Not parallel: port name overrides are process-wide
+	defer SetPortNames(PortNames{})
+
+	pod := &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "hivemq", Ports: []v1.ContainerPort{
+		{Name: "mgmt", ContainerPort: 9090},
+		{Name: "rest", ContainerPort: 8888},
+	}}}}}
+	service := &v1.Service{Spec: v1.ServiceSpec{Ports: []v1.ServicePort{{Name: "rest", Port: 80}}}}
+	broker := ProfileFor(ProductBroker)
+
+	if _, err := broker.HealthPortFor(pod); err == nil {
+		t.Fatal("expected no health port without an override")
+	}
+	if _, err := broker.ServiceAPIPortFor(service); err == nil {
+		t.Fatal("expected no service API port without an override")
+	}
+
+	SetPortNames(PortNames{Health: "mgmt", API: "rest"})
+	if port, err := broker.HealthPortFor(pod); err != nil || port != 9090 {
+		t.Fatalf("HealthPortFor() = %d, %v, want 9090", port, err)
+	}
+	if port, err := broker.APIPortFor(pod); err != nil || port != 8888 {
+		t.Fatalf("APIPortFor() = %d, %v, want 8888", port, err)
+	}
+	if port, err := broker.ServiceAPIPortFor(service); err != nil || port != 80 {
+		t.Fatalf("ServiceAPIPortFor() = %d, %v, want 80", port, err)
+	}
+}
+
+func TestProductDefaultsFromEnv(t *testing.T) {
+	// Not parallel: product defaults are process-wide
+	defer func() { _ = SetProductDefaults(ProductPulse, ProductDefaults{}) }()
+
+	env := map[string]string{
+		"KUBECTL_PULSE_DEPLOYMENT": "pulse",
+		"KUBECTL_PULSE_PORT":       "9000",
+		"KUBECTL_PULSE_ENDPOINT":   "readiness",
+		"KUBECTL_PULSE_SELECTOR":   "app=pulse",
+	}
+	defaults, err := ProductDefaultsFromEnv(ProductPulse, func(name string) string { return env[name] })
+	if err != nil {
+		t.Fatalf("ProductDefaultsFromEnv returned error: %v", err)
+	}
+	if err := SetProductDefaults(ProductPulse, defaults); err != nil {
+		t.Fatalf("SetProductDefaults returned error: %v", err)
+	}
+
+	pulse := ProfileFor(ProductPulse)
+	if pulse.Workload != "pulse" || pulse.HealthPort != 9000 || pulse.Selector != "app=pulse" {
+		t.Errorf("ProfileFor(pulse) = %+v, want workload pulse, port 9000, selector app=pulse", pulse)
+	}
+	if pulse.DefaultEndpoint() != "readiness" || !pulse.SupportsEndpoint("liveness") {
+		t.Errorf("endpoints = %v, want readiness first and liveness still accepted", pulse.HealthEndpoints)
+	}
+	if ProfileFor(ProductBroker).Workload != "broker" {
+		t.Error("pulse overrides changed the broker profile")
+	}
+
+	env = map[string]string{"KUBECTL_PULSE_PORT": "http"}
+	if _, err := ProductDefaultsFromEnv(ProductPulse, func(name string) string { return env[name] }); err == nil {
+		t.Error("expected an error for a non-numeric port")
+	}
+	if err := SetProductDefaults(ProductPulse, ProductDefaults{Endpoint: "health"}); err == nil {
+		t.Error("expected an error for an endpoint Pulse does not serve")
+	}
+}
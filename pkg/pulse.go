@@ -0,0 +1,130 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"kubectl-broker/pkg/health"
+)
+
+// PulseServerSelector selects HiveMQ Pulse server pods
+const PulseServerSelector = "app.kubernetes.io/name=hivemq-pulse-server"
+
+// PulseReadiness is one observation of the Pulse replicas while waiting for them to become ready
+type PulseReadiness struct {
+	Desired int                 // replicas requested by the owning Deployment/StatefulSet
+	Ready   int                 // pods whose readiness endpoint reported healthy
+	Results []HealthCheckResult // readiness results of the current pods
+}
+
+// AllReady reports whether every desired replica answered its readiness check
+func (r PulseReadiness) AllReady() bool {
+	return r.Desired > 0 && r.Ready >= r.Desired && r.Ready == len(r.Results)
+}
+
+// GetPulseServerPods lists the Pulse server pods of a namespace, skipping pods that are terminating
+func (k *K8sClient) GetPulseServerPods(ctx context.Context, namespace string) ([]*v1.Pod, error) {
+	return k.GetProductPods(ctx, namespace, ProfileFor(ProductPulse))
+}
+
+// DesiredReplicas sums the replica counts of the workloads owning the pods. Pods of a
+// Deployment are owned by a ReplicaSet; pods without a known owner count as one replica each.
+func (k *K8sClient) DesiredReplicas(ctx context.Context, pods []*v1.Pod) (int, error) {
+	seen := make(map[string]bool)
+	desired := 0
+	for _, pod := range pods {
+		owner := metav1.GetControllerOf(pod)
+		if owner == nil || (owner.Kind != "ReplicaSet" && owner.Kind != "StatefulSet") {
+			desired++
+			continue
+		}
+		key := owner.Kind + "/" + owner.Name
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		var replicas *int32
+		switch owner.Kind {
+		case "ReplicaSet":
+			rs, err := k.appsClient.ReplicaSets(pod.Namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+			if err != nil {
+				return 0, NewKubernetesError("get_replicaset", owner.Name, err)
+			}
+			replicas = rs.Spec.Replicas
+		case "StatefulSet":
+			sts, err := k.appsClient.StatefulSets(pod.Namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+			if err != nil {
+				return 0, NewKubernetesError("get_statefulset", owner.Name, err)
+			}
+			replicas = sts.Spec.Replicas
+		}
+
+		if replicas == nil {
+			desired++
+		} else {
+			desired += int(*replicas)
+		}
+	}
+	return desired, nil
+}
+
+// CheckPulseReadiness runs concurrent readiness checks against all current Pulse server pods
+func (k *K8sClient) CheckPulseReadiness(ctx context.Context, namespace string, port int32, options health.HealthCheckOptions) (PulseReadiness, error) {
+	pods, err := k.GetPulseServerPods(ctx, namespace)
+	if err != nil {
+		return PulseReadiness{}, err
+	}
+	if len(pods) == 0 {
+		return PulseReadiness{}, nil
+	}
+
+	desired, err := k.DesiredReplicas(ctx, pods)
+	if err != nil {
+		return PulseReadiness{}, err
+	}
+
+	options.Endpoint = "readiness"
+	results, err := k.CollectConcurrentHealthChecks(ctx, pods, port, options)
+	if err != nil {
+		return PulseReadiness{}, err
+	}
+
+	readiness := PulseReadiness{Desired: desired, Results: results}
+	for _, result := range results {
+		if result.Status == "HEALTHY" {
+			readiness.Ready++
+		}
+	}
+	return readiness, nil
+}
+
+// WaitForPulseReady polls the Pulse readiness endpoints until all desired replicas are ready or
+// ctx ends. onPoll, when set, receives every observation so callers can report progress.
+func (k *K8sClient) WaitForPulseReady(ctx context.Context, namespace string, port int32, options health.HealthCheckOptions, interval time.Duration, onPoll func(PulseReadiness)) (PulseReadiness, error) {
+	var last PulseReadiness
+	for {
+		readiness, err := k.CheckPulseReadiness(ctx, namespace, port, options)
+		// Transient failures while pods restart are expected; only the deadline ends the wait
+		if err == nil {
+			last = readiness
+			if onPoll != nil {
+				onPoll(readiness)
+			}
+			if readiness.AllReady() {
+				return readiness, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, NewHealthCheckError("wait_pulse_ready", namespace,
+				fmt.Errorf("%d/%d Pulse replicas ready: %w", last.Ready, last.Desired, ctx.Err()))
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package pulse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"kubectl-broker/pkg/transport"
+)
+
+const (
+	defaultTimeout  = 30 * time.Second
+	statusPath      = "/admin/v1/status"
+	configPath      = "/admin/v1/config"
+	diagnosticsPath = "/admin/v1/diagnostics"
+	// maxErrorBody bounds how much of an error response is quoted in the error message
+	maxErrorBody = 4096
+)
+
+// ClientOptions configure the HTTP client.
+type ClientOptions struct {
+	Timeout time.Duration
+	TLS     transport.TLSOptions
+	// Auth supplies a bearer token; nil sends requests without credentials
+	Auth TokenSource
+}
+
+// Client talks to the admin API of a HiveMQ Pulse server.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	auth       TokenSource
+}
+
+// NewClient builds a Client for the provided base URL.
+func NewClient(baseURL string, opts ClientOptions) (*Client, error) {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	httpClient, err := transport.NewHTTPClient(timeout, opts.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure TLS: %w", err)
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+		auth:       opts.Auth,
+	}, nil
+}
+
+// Status returns the runtime state of the server.
+func (c *Client) Status(ctx context.Context) (*Status, error) {
+	body, err := c.get(ctx, statusPath, "application/json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server status: %w", err)
+	}
+	defer body.Close()
+
+	var status Status
+	if err := json.NewDecoder(body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to decode server status: %w", err)
+	}
+	return &status, nil
+}
+
+// ExportConfig copies the effective server configuration, as YAML, to w.
+func (c *Client) ExportConfig(ctx context.Context, w io.Writer) (int64, error) {
+	body, err := c.get(ctx, configPath, "application/yaml")
+	if err != nil {
+		return 0, fmt.Errorf("failed to export configuration: %w", err)
+	}
+	defer body.Close()
+
+	written, err := io.Copy(w, body)
+	if err != nil {
+		return written, fmt.Errorf("failed to read configuration: %w", err)
+	}
+	return written, nil
+}
+
+// DiagnosticsBundle streams the zip archive with logs, thread dumps and configuration the
+// server assembles for support cases to w.
+func (c *Client) DiagnosticsBundle(ctx context.Context, w io.Writer) (int64, error) {
+	body, err := c.get(ctx, diagnosticsPath, "application/zip")
+	if err != nil {
+		return 0, fmt.Errorf("failed to download diagnostics bundle: %w", err)
+	}
+	defer body.Close()
+
+	written, err := io.Copy(w, body)
+	if err != nil {
+		return written, fmt.Errorf("failed to read diagnostics bundle: %w", err)
+	}
+	return written, nil
+}
+
+// get issues a GET request and returns the body of a successful response, which the
+// caller closes
+func (c *Client) get(ctx context.Context, path, accept string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", accept)
+	if c.auth != nil {
+		token, err := c.auth.Token(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.Body, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, fmt.Errorf("%w (HTTP %d)", ErrUnauthorized, resp.StatusCode)
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+}
@@ -0,0 +1,99 @@
+package pulse
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type staticToken string
+
+func (t staticToken) Token(context.Context) (string, error) { return string(t), nil }
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t0ken" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case statusPath:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"version":"1.2.0","nodeId":"pulse-0","state":"RUNNING","leader":true,"connectedAgents":3,
+				"components":[{"name":"agent-gateway","state":"RUNNING"},{"name":"storage","state":"DEGRADED","message":"disk 91% full"}]}`))
+		case configPath:
+			if r.Header.Get("Accept") != "application/yaml" {
+				t.Errorf("unexpected Accept header %q", r.Header.Get("Accept"))
+			}
+			_, _ = w.Write([]byte("server:\n  port: 8080\n"))
+		case diagnosticsPath:
+			http.Error(w, "bundle generation in progress", http.StatusConflict)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestStatusAndConfigExport(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+	client, err := NewClient(server.URL, ClientOptions{Auth: staticToken("t0ken")})
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	status, err := client.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status returned error: %v", err)
+	}
+	if status.NodeID != "pulse-0" || !status.Leader || status.ConnectedAgents != 3 || len(status.Components) != 2 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+	if status.Running() {
+		t.Fatal("expected a server with a degraded component not to be running")
+	}
+
+	var config bytes.Buffer
+	written, err := client.ExportConfig(context.Background(), &config)
+	if err != nil {
+		t.Fatalf("ExportConfig returned error: %v", err)
+	}
+	if written != int64(config.Len()) || config.String() != "server:\n  port: 8080\n" {
+		t.Fatalf("unexpected config export (%d bytes): %q", written, config.String())
+	}
+}
+
+func TestErrorsCarryStatusAndCredentials(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+
+	unauthenticated, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	if _, err := unauthenticated.Status(context.Background()); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized without a token, got %v", err)
+	}
+
+	client, err := NewClient(server.URL, ClientOptions{Auth: staticToken("t0ken")})
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	var bundle bytes.Buffer
+	_, err = client.DiagnosticsBundle(context.Background(), &bundle)
+	if err == nil || !strings.Contains(err.Error(), "HTTP 409: bundle generation in progress") {
+		t.Fatalf("expected the HTTP status in the error, got %v", err)
+	}
+	if bundle.Len() != 0 {
+		t.Fatalf("expected nothing to be written for a failed download, got %d bytes", bundle.Len())
+	}
+}
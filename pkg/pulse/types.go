@@ -0,0 +1,54 @@
+package pulse
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUnauthorized indicates the Pulse admin API rejected the request's credentials.
+var ErrUnauthorized = errors.New("the Pulse admin API rejected the credentials")
+
+// Server states reported by the status endpoint.
+const (
+	StateRunning  = "RUNNING"
+	StateStarting = "STARTING"
+	StateDegraded = "DEGRADED"
+	StateStopping = "STOPPING"
+)
+
+// TokenSource supplies the bearer token sent with admin requests. The management API token
+// sources of the backup package satisfy it.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// Status is the runtime state a Pulse server reports about itself.
+type Status struct {
+	Version         string      `json:"version"`
+	NodeID          string      `json:"nodeId"`
+	State           string      `json:"state"`
+	StartedAt       string      `json:"startedAt,omitempty"`
+	Leader          bool        `json:"leader"`
+	ConnectedAgents int         `json:"connectedAgents"`
+	Components      []Component `json:"components,omitempty"`
+}
+
+// Running reports whether the server is up and all of its components are running.
+func (s Status) Running() bool {
+	if s.State != StateRunning {
+		return false
+	}
+	for _, component := range s.Components {
+		if component.State != StateRunning {
+			return false
+		}
+	}
+	return true
+}
+
+// Component is an internal subsystem of the server (agent gateway, storage, ...).
+type Component struct {
+	Name    string `json:"name"`
+	State   string `json:"state"`
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,124 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// QuiescedLabel is added to the selector of a quiesced client Service. No pod carries it, so
+// the Service loses its endpoints and new client connections are refused until it is resumed.
+const QuiescedLabel = "kubectl-broker.hivemq.com/quiesced"
+
+// QuiescedReasonAnnotation records on a quiesced Service why its traffic is held
+const QuiescedReasonAnnotation = "kubectl-broker.hivemq.com/quiesced-reason"
+
+// ClientServices are the Services routing client traffic to the brokers of a StatefulSet
+type ClientServices struct {
+	// Client Services select the broker pods and do not serve the management API
+	Client []v1.Service
+	// Shared Services serve the management API and client listeners; they keep their traffic
+	Shared []v1.Service
+}
+
+// FindClientServices returns the non-headless Services in the namespace that select the pods
+// of the StatefulSet, including Services already quiesced by an earlier, interrupted run
+func (k *K8sClient) FindClientServices(ctx context.Context, namespace, statefulSetName string) (*ClientServices, error) {
+	sts, err := k.GetStatefulSet(ctx, namespace, statefulSetName)
+	if err != nil {
+		return nil, err
+	}
+	list, err := k.coreClient.Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, NewKubernetesError("list_services", namespace, err)
+	}
+	return classifyClientServices(sts, list.Items), nil
+}
+
+// classifyClientServices picks the client Services of a StatefulSet from the Services of its namespace
+func classifyClientServices(sts *appsv1.StatefulSet, services []v1.Service) *ClientServices {
+	podLabels := labels.Set(sts.Spec.Template.Labels)
+	result := &ClientServices{}
+	for _, service := range services {
+		if service.Spec.ClusterIP == v1.ClusterIPNone || service.Spec.Type == v1.ServiceTypeExternalName || len(service.Spec.Selector) == 0 {
+			continue
+		}
+		selector := make(map[string]string, len(service.Spec.Selector))
+		for key, value := range service.Spec.Selector {
+			if key != QuiescedLabel {
+				selector[key] = value
+			}
+		}
+		if len(selector) == 0 || !labels.SelectorFromSet(selector).Matches(podLabels) {
+			continue
+		}
+		if hasAPIPort(&service) {
+			if len(service.Spec.Ports) > 1 {
+				result.Shared = append(result.Shared, service)
+			}
+			continue
+		}
+		result.Client = append(result.Client, service)
+	}
+	sort.Slice(result.Client, func(i, j int) bool { return result.Client[i].Name < result.Client[j].Name })
+	sort.Slice(result.Shared, func(i, j int) bool { return result.Shared[i].Name < result.Shared[j].Name })
+	return result
+}
+
+// IsQuiesced reports whether a Service's traffic is held by QuiesceService
+func IsQuiesced(service *v1.Service) bool {
+	_, ok := service.Spec.Selector[QuiescedLabel]
+	return ok
+}
+
+// QuiesceService removes the endpoints of a client Service by adding QuiescedLabel to its
+// selector. Connections already established are not closed.
+func (k *K8sClient) QuiesceService(ctx context.Context, service *v1.Service, reason string) error {
+	if IsQuiesced(service) {
+		return nil
+	}
+	if err := GuardMutation(ctx, "quiesce", "service "+service.Name); err != nil {
+		return err
+	}
+	return k.patchServiceSelector(ctx, service, "true", &reason)
+}
+
+// ResumeService removes QuiescedLabel from the selector so the Service routes to the brokers again
+func (k *K8sClient) ResumeService(ctx context.Context, service *v1.Service) error {
+	if !IsQuiesced(service) {
+		return nil
+	}
+	if err := GuardMutation(ctx, "resume", "service "+service.Name); err != nil {
+		return err
+	}
+	return k.patchServiceSelector(ctx, service, nil, nil)
+}
+
+// patchServiceSelector sets or, with nil values, removes the quiesce label and annotation and
+// updates service to the patched state
+func (k *K8sClient) patchServiceSelector(ctx context.Context, service *v1.Service, label any, reason *string) error {
+	var annotation any
+	if reason != nil {
+		annotation = *reason
+	}
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{"annotations": map[string]any{QuiescedReasonAnnotation: annotation}},
+		"spec":     map[string]any{"selector": map[string]any{QuiescedLabel: label}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build service patch: %w", err)
+	}
+	updated, err := k.coreClient.Services(service.Namespace).Patch(ctx, service.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return NewKubernetesError("patch_service", service.Name, err)
+	}
+	*service = *updated
+	return nil
+}
@@ -0,0 +1,56 @@
+package pkg
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClassifyClientServices(t *testing.T) {
+	t.Parallel()
+
+	sts := &appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{
+		Template: v1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "hivemq", "tier": "broker"}}},
+	}}
+	service := func(name string, selector map[string]string, ports ...v1.ServicePort) v1.Service {
+		return v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1.ServiceSpec{Selector: selector, Ports: ports},
+		}
+	}
+	mqtt := v1.ServicePort{Name: "mqtt", Port: 1883}
+	api := v1.ServicePort{Name: "api", Port: 8081}
+
+	headless := service("broker-headless", map[string]string{"app": "hivemq"}, mqtt)
+	headless.Spec.ClusterIP = v1.ClusterIPNone
+	services := []v1.Service{
+		service("mqtt", map[string]string{"app": "hivemq"}, mqtt),
+		service("mqtt-held", map[string]string{"app": "hivemq", QuiescedLabel: "true"}, mqtt),
+		service("hivemq-broker-api", map[string]string{"app": "hivemq"}, api),
+		service("all-in-one", map[string]string{"tier": "broker"}, mqtt, api),
+		service("other-app", map[string]string{"app": "postgres"}, mqtt),
+		service("external", nil, mqtt),
+		headless,
+	}
+
+	got := classifyClientServices(sts, services)
+	if len(got.Client) != 2 || got.Client[0].Name != "mqtt" || got.Client[1].Name != "mqtt-held" {
+		t.Errorf("client services = %v, want mqtt and mqtt-held", serviceNames(got.Client))
+	}
+	if len(got.Shared) != 1 || got.Shared[0].Name != "all-in-one" {
+		t.Errorf("shared services = %v, want all-in-one", serviceNames(got.Shared))
+	}
+	if !IsQuiesced(&got.Client[1]) || IsQuiesced(&got.Client[0]) {
+		t.Error("IsQuiesced() does not detect the quiesce label")
+	}
+}
+
+func serviceNames(services []v1.Service) []string {
+	names := make([]string, 0, len(services))
+	for _, service := range services {
+		names = append(names, service.Name)
+	}
+	return names
+}
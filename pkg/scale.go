@@ -0,0 +1,213 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"kubectl-broker/pkg/health"
+)
+
+// ScaleReport is the preflight of changing the replica count of a broker StatefulSet
+type ScaleReport struct {
+	Namespace       string          `json:"namespace"`
+	StatefulSet     string          `json:"statefulSet"`
+	CurrentReplicas int32           `json:"currentReplicas"`
+	TargetReplicas  int32           `json:"targetReplicas"`
+	Steps           []int32         `json:"steps"` // replica counts applied in order
+	Checks          []CapacityCheck `json:"checks"`
+}
+
+// NewScaleReport plans the steps from current to target replicas; stepwise changes one replica
+// at a time so the cluster can rebalance in between
+func NewScaleReport(namespace, statefulSetName string, current, target int32, stepwise bool) *ScaleReport {
+	return &ScaleReport{
+		Namespace:       namespace,
+		StatefulSet:     statefulSetName,
+		CurrentReplicas: current,
+		TargetReplicas:  target,
+		Steps:           ScaleSteps(current, target, stepwise),
+	}
+}
+
+// Verdict is CapacityNoGo as soon as one check failed
+func (r *ScaleReport) Verdict() string {
+	for _, check := range r.Checks {
+		if check.Status == CapacityCheckFail {
+			return CapacityNoGo
+		}
+	}
+	return CapacityGo
+}
+
+func (r *ScaleReport) add(name, status, details string) {
+	r.Checks = append(r.Checks, CapacityCheck{Name: name, Status: status, Details: details})
+}
+
+// ScalingDown reports whether the scale removes brokers
+func (r *ScaleReport) ScalingDown() bool {
+	return r.TargetReplicas < r.CurrentReplicas
+}
+
+// RemovedPods names the pods a scale-down terminates; StatefulSets remove the highest ordinals
+func (r *ScaleReport) RemovedPods() []string {
+	var names []string
+	for ordinal := r.CurrentReplicas - 1; ordinal >= r.TargetReplicas; ordinal-- {
+		names = append(names, fmt.Sprintf("%s-%d", r.StatefulSet, ordinal))
+	}
+	return names
+}
+
+// ScaleSteps lists the replica counts to apply in order: only the target, or every count in
+// between when stepwise
+func ScaleSteps(current, target int32, stepwise bool) []int32 {
+	if !stepwise || current == target {
+		return []int32{target}
+	}
+	var steps []int32
+	for replicas := current; replicas != target; {
+		if target > current {
+			replicas++
+		} else {
+			replicas--
+		}
+		steps = append(steps, replicas)
+	}
+	return steps
+}
+
+// QuorumSize is the majority of a cluster with the given number of members
+func QuorumSize(members int32) int32 {
+	return members/2 + 1
+}
+
+// CheckClusterHealth requires every current broker to be running and healthy, since a cluster
+// that is already degraded may lose data when its membership changes
+func (r *ScaleReport) CheckClusterHealth(results []HealthCheckResult) {
+	var unhealthy []string
+	for _, result := range results {
+		if result.Status != "HEALTHY" {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (%s)", result.PodName, result.Status))
+		}
+	}
+	healthy := int32(len(results) - len(unhealthy))
+
+	switch {
+	case len(unhealthy) > 0:
+		r.add("Cluster health", CapacityCheckFail, "not healthy: "+strings.Join(unhealthy, ", "))
+	case healthy < r.CurrentReplicas:
+		r.add("Cluster health", CapacityCheckFail, fmt.Sprintf("only %d of %d brokers are running", healthy, r.CurrentReplicas))
+	default:
+		r.add("Cluster health", CapacityCheckPass, fmt.Sprintf("all %d brokers are healthy", healthy))
+	}
+}
+
+// CheckQuorum warns when a scale-down removes the majority of the cluster in one step or
+// leaves a single broker without replicas
+func (r *ScaleReport) CheckQuorum() {
+	if !r.ScalingDown() {
+		return
+	}
+	removedAtOnce := r.CurrentReplicas - r.Steps[0]
+
+	switch {
+	case r.TargetReplicas == 1:
+		r.add("Quorum", CapacityCheckWarn, "a single broker keeps no replicas of sessions, retained and queued messages")
+	case r.Steps[0] < QuorumSize(r.CurrentReplicas):
+		r.add("Quorum", CapacityCheckWarn, fmt.Sprintf("removing %d of %d brokers at once leaves no majority of the current cluster; use --wait to remove one broker at a time", removedAtOnce, r.CurrentReplicas))
+	case len(r.Steps) > 1:
+		r.add("Quorum", CapacityCheckPass, "one broker is removed at a time")
+	default:
+		r.add("Quorum", CapacityCheckPass, fmt.Sprintf("%d of %d brokers remain, a majority is %d", r.Steps[0], r.CurrentReplicas, QuorumSize(r.CurrentReplicas)))
+	}
+}
+
+// ScaleStatefulSet sets the replicas of a StatefulSet
+func (k *K8sClient) ScaleStatefulSet(ctx context.Context, namespace, name string, replicas int32) error {
+	if err := GuardMutation(ctx, "scale", "statefulset "+name); err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{"replicas": replicas},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build scale patch: %w", err)
+	}
+	if _, err := k.appsClient.StatefulSets(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return NewKubernetesError("patch_statefulset", name, err)
+	}
+	return nil
+}
+
+// WaitForClusterReady blocks until the StatefulSet runs exactly replicas ready pods and every
+// broker reports healthy, which is when the cluster finished rebalancing after a scale step.
+// It polls every interval until ctx is done.
+func (k *K8sClient) WaitForClusterReady(ctx context.Context, namespace, name string, replicas int32, options health.HealthCheckOptions, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		pending, err := k.clusterNotReady(ctx, namespace, name, replicas, options)
+		if err == nil && pending == "" {
+			return nil
+		}
+		if err != nil {
+			pending = err.Error()
+		}
+		slog.Info("Waiting for the cluster to rebalance", "statefulset", name, "replicas", replicas, "pending", pending)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("cluster did not become ready with %d replicas (%s): %w", replicas, pending, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// clusterNotReady describes what the cluster still waits for, or returns "" when it is ready
+func (k *K8sClient) clusterNotReady(ctx context.Context, namespace, name string, replicas int32, options health.HealthCheckOptions) (string, error) {
+	sts, err := k.appsClient.StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", NewKubernetesError("get_statefulset", name, err)
+	}
+	status := sts.Status
+	if status.ObservedGeneration < sts.Generation || status.Replicas != replicas || status.ReadyReplicas != replicas {
+		return fmt.Sprintf("%d of %d pods ready, %d running", status.ReadyReplicas, replicas, status.Replicas), nil
+	}
+
+	podList, err := k.coreClient.Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: metav1.FormatLabelSelector(sts.Spec.Selector)})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods for StatefulSet %s: %w", name, err)
+	}
+	pods := make([]*v1.Pod, 0, len(podList.Items))
+	for i := range podList.Items {
+		if podList.Items[i].DeletionTimestamp == nil {
+			pods = append(pods, &podList.Items[i])
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	results, err := k.CollectConcurrentHealthChecks(ctx, pods, 0, options)
+	if results == nil {
+		return "", err
+	}
+	var unhealthy []string
+	for _, result := range results {
+		if result.Status != "HEALTHY" {
+			unhealthy = append(unhealthy, result.PodName)
+		}
+	}
+	if len(unhealthy) > 0 {
+		return "not healthy: " + strings.Join(unhealthy, ", "), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,82 @@
+package pkg
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScaleSteps(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		current, target int32
+		stepwise        bool
+		want            []int32
+	}{
+		{current: 3, target: 5, want: []int32{5}},
+		{current: 3, target: 5, stepwise: true, want: []int32{4, 5}},
+		{current: 5, target: 2, stepwise: true, want: []int32{4, 3, 2}},
+		{current: 3, target: 3, stepwise: true, want: []int32{3}},
+	}
+	for _, tc := range cases {
+		if got := ScaleSteps(tc.current, tc.target, tc.stepwise); !slices.Equal(got, tc.want) {
+			t.Errorf("ScaleSteps(%d, %d, %t) = %v, want %v", tc.current, tc.target, tc.stepwise, got, tc.want)
+		}
+	}
+}
+
+func TestScaleReportCheckQuorum(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name            string
+		current, target int32
+		stepwise        bool
+		want            string
+	}{
+		{name: "majority remains", current: 5, target: 3, want: CapacityCheckPass},
+		{name: "majority removed at once", current: 5, target: 2, want: CapacityCheckWarn},
+		{name: "majority removed stepwise", current: 5, target: 2, stepwise: true, want: CapacityCheckPass},
+		{name: "single broker", current: 3, target: 1, stepwise: true, want: CapacityCheckWarn},
+	}
+	for _, tc := range cases {
+		report := NewScaleReport("prod", "broker", tc.current, tc.target, tc.stepwise)
+		report.CheckQuorum()
+		if len(report.Checks) != 1 || report.Checks[0].Status != tc.want {
+			t.Errorf("%s: checks = %+v, want one %s", tc.name, report.Checks, tc.want)
+		}
+	}
+
+	scaleUp := NewScaleReport("prod", "broker", 3, 5, false)
+	scaleUp.CheckQuorum()
+	if len(scaleUp.Checks) != 0 {
+		t.Errorf("scale-up should not check the quorum, got %+v", scaleUp.Checks)
+	}
+}
+
+func TestScaleReportRemovedPodsAndHealth(t *testing.T) {
+	t.Parallel()
+
+	report := NewScaleReport("prod", "broker", 4, 2, false)
+	if got, want := report.RemovedPods(), []string{"broker-3", "broker-2"}; !slices.Equal(got, want) {
+		t.Errorf("RemovedPods() = %v, want %v", got, want)
+	}
+
+	report.CheckClusterHealth([]HealthCheckResult{
+		{PodName: "broker-0", Status: "HEALTHY"},
+		{PodName: "broker-1", Status: "HEALTHY"},
+		{PodName: "broker-2", Status: "HEALTHY"},
+	})
+	if report.Verdict() != CapacityNoGo {
+		t.Errorf("three of four running brokers should fail the health check, got %+v", report.Checks)
+	}
+
+	healthy := NewScaleReport("prod", "broker", 2, 3, false)
+	healthy.CheckClusterHealth([]HealthCheckResult{
+		{PodName: "broker-0", Status: "HEALTHY"},
+		{PodName: "broker-1", Status: "HEALTHY"},
+	})
+	if healthy.Verdict() != CapacityGo {
+		t.Errorf("healthy cluster should pass, got %+v", healthy.Checks)
+	}
+}
@@ -3,7 +3,9 @@ package sidecar
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,6 +14,10 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/logging"
+	"kubectl-broker/pkg/tracing"
 )
 
 const (
@@ -21,15 +27,28 @@ const (
 	remoteListPath    = "/v1/backup/list-remote"
 	purgePath         = "/v1/backup/purge"
 	forceUploadPath   = "/v1/backup/upload"
+	syncPath          = "/v1/backup/sync"
+	remotePresignPath = "/v1/backup/presign"
+	remoteDownload    = "/v1/backup/download"
 	metricsPath       = "/metrics"
+	statusPath        = "/v1/status"
+	livenessPath      = "/healthz"
+	readinessPath     = "/readyz"
+	remoteKeyHeader   = "X-Backup-Key"
 	authHeader        = "Authorization"
 	bearerTokenPrefix = "Bearer "
 )
 
+// ErrNotSupported indicates the sidecar does not implement the requested endpoint.
+var ErrNotSupported = errors.New("sidecar endpoint not supported")
+
 // ClientOptions configure the HTTP client.
 type ClientOptions struct {
 	Timeout  time.Duration
 	APIToken string
+	Retries  int // retries of idempotent requests after transport errors
+	// TLS is used for https base URLs; nil keeps Go's defaults
+	TLS *tls.Config
 }
 
 // Client wraps HTTP operations against the sidecar API.
@@ -37,6 +56,7 @@ type Client struct {
 	baseURL    string
 	httpClient *http.Client
 	apiToken   string
+	retries    int
 }
 
 // NewClient builds a Client for the provided base URL.
@@ -45,12 +65,20 @@ func NewClient(baseURL string, opts ClientOptions) *Client {
 	if timeout <= 0 {
 		timeout = defaultTimeout
 	}
+	var base http.RoundTripper
+	if opts.TLS != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = opts.TLS
+		base = transport
+	}
 	return &Client{
 		baseURL: strings.TrimRight(baseURL, "/"),
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: tracing.WrapTransport(logging.WrapTransport(base, "sidecar"), "sidecar"),
 		},
 		apiToken: strings.TrimSpace(opts.APIToken),
+		retries:  opts.Retries,
 	}
 }
 
@@ -79,8 +107,13 @@ func (c *Client) ListRemoteBackups(ctx context.Context, limit int) ([]RemoteBack
 	return resp.Backups, nil
 }
 
-// Restore triggers POST /v1/restore.
+// Restore triggers POST /v1/restore. Only restores with req.DryRun set run in a dry-run context.
 func (c *Client) Restore(ctx context.Context, req RestoreRequest) (*RestoreResult, error) {
+	if !req.DryRun {
+		if err := pkg.GuardMutation(ctx, "restore", "remote backup"); err != nil {
+			return nil, err
+		}
+	}
 	var result RestoreResult
 	if err := c.postJSON(ctx, restoreEndpoint, req, &result); err != nil {
 		return nil, err
@@ -94,6 +127,9 @@ func (c *Client) PurgeBackup(ctx context.Context, name string) error {
 	if payload.Name == "" {
 		return fmt.Errorf("purge name is required")
 	}
+	if err := pkg.GuardMutation(ctx, "purge", "backup "+payload.Name); err != nil {
+		return err
+	}
 	return c.postJSON(ctx, purgePath, payload, nil)
 }
 
@@ -102,21 +138,112 @@ func (c *Client) TriggerUpload(ctx context.Context, req UploadRequest) error {
 	req.Type = strings.TrimSpace(req.Type)
 	req.Name = strings.TrimSpace(req.Name)
 	if req.Type == "" {
-		req.Type = "backup"
+		req.Type = UploadTypeBackup
 	}
 	if req.Name == "" {
 		return fmt.Errorf("upload name is required")
 	}
+	if err := pkg.GuardMutation(ctx, "upload", req.Type+" "+req.Name); err != nil {
+		return err
+	}
 	return c.postJSON(ctx, forceUploadPath, req, nil)
 }
 
+// TriggerSync asks the sidecar to reconcile its remote inventory against the on-pod backups now
+// instead of on its watch cadence. Returns ErrNotSupported if the sidecar has no sync endpoint.
+func (c *Client) TriggerSync(ctx context.Context) error {
+	req, err := c.newRequest(ctx, http.MethodPost, syncPath, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusNoContent:
+		return nil
+	case http.StatusNotFound, http.StatusNotImplemented, http.StatusMethodNotAllowed:
+		return fmt.Errorf("%w: %v", ErrNotSupported, c.errorFromResponse(resp))
+	default:
+		return c.errorFromResponse(resp)
+	}
+}
+
+// PresignRemoteBackup asks the sidecar for a presigned URL for a remote backup object.
+// Version is an object key or "latest". Returns ErrNotSupported if the sidecar cannot presign.
+func (c *Client) PresignRemoteBackup(ctx context.Context, version string) (*PresignedObject, error) {
+	query := url.Values{}
+	query.Set("version", remoteVersionOrLatest(version))
+
+	req, err := c.newRequest(ctx, http.MethodGet, remotePresignPath, nil, query)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.doIdempotent(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusNotImplemented, http.StatusMethodNotAllowed:
+		// Older sidecars have no presign endpoint; callers fall back to streaming
+		return nil, fmt.Errorf("%w: %v", ErrNotSupported, c.errorFromResponse(resp))
+	default:
+		return nil, c.errorFromResponse(resp)
+	}
+
+	var out PresignedObject
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode presign response: %w", err)
+	}
+	if out.URL == "" {
+		return nil, fmt.Errorf("sidecar returned an empty presigned URL for %s", query.Get("version"))
+	}
+	return &out, nil
+}
+
+// DownloadRemoteBackup streams a remote backup object through the sidecar.
+// The caller must close the returned body.
+func (c *Client) DownloadRemoteBackup(ctx context.Context, version string) (*RemoteObjectStream, error) {
+	query := url.Values{}
+	query.Set("version", remoteVersionOrLatest(version))
+
+	req, err := c.newRequest(ctx, http.MethodGet, remoteDownload, nil, query)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.doIdempotent(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, c.errorFromResponse(resp)
+	}
+
+	key := resp.Header.Get(remoteKeyHeader)
+	if key == "" {
+		key = query.Get("version")
+	}
+	return &RemoteObjectStream{
+		Key:       key,
+		SizeBytes: resp.ContentLength,
+		Body:      resp.Body,
+	}, nil
+}
+
 // FetchMetrics retrieves the Prometheus metrics payload.
 func (c *Client) FetchMetrics(ctx context.Context) ([]byte, error) {
 	req, err := c.newRequest(ctx, http.MethodGet, metricsPath, nil, nil)
 	if err != nil {
 		return nil, err
 	}
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doIdempotent(req)
 	if err != nil {
 		return nil, err
 	}
@@ -127,12 +254,76 @@ func (c *Client) FetchMetrics(ctx context.Context) ([]byte, error) {
 	return io.ReadAll(resp.Body)
 }
 
+// Status returns the sidecar's self-reported configuration and sync state.
+// Returns ErrNotSupported when the sidecar predates the status endpoint.
+func (c *Client) Status(ctx context.Context) (*Status, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, statusPath, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.doIdempotent(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusNotImplemented, http.StatusMethodNotAllowed:
+		return nil, fmt.Errorf("%w: %v", ErrNotSupported, c.errorFromResponse(resp))
+	default:
+		return nil, c.errorFromResponse(resp)
+	}
+
+	var out Status
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode status response: %w", err)
+	}
+	return &out, nil
+}
+
+// Liveness probes the sidecar liveness endpoint.
+func (c *Client) Liveness(ctx context.Context) ProbeResult {
+	return c.probe(ctx, livenessPath)
+}
+
+// Readiness probes the sidecar readiness endpoint.
+func (c *Client) Readiness(ctx context.Context) ProbeResult {
+	return c.probe(ctx, readinessPath)
+}
+
+func (c *Client) probe(ctx context.Context, p string) ProbeResult {
+	result := ProbeResult{Endpoint: p}
+
+	req, err := c.newRequest(ctx, http.MethodGet, p, nil, nil)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	result.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	result.OK = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !result.OK {
+		result.Error = c.errorFromResponse(resp).Error()
+	}
+	return result
+}
+
 func (c *Client) getJSON(ctx context.Context, p string, dest any, query url.Values) error {
 	req, err := c.newRequest(ctx, http.MethodGet, p, nil, query)
 	if err != nil {
 		return err
 	}
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doIdempotent(req)
 	if err != nil {
 		return err
 	}
@@ -197,10 +388,24 @@ func (c *Client) newRequest(ctx context.Context, method, p string, body io.Reade
 	return req, nil
 }
 
+func remoteVersionOrLatest(version string) string {
+	version = strings.TrimSpace(version)
+	if version == "" {
+		return "latest"
+	}
+	return version
+}
+
 func (c *Client) errorFromResponse(resp *http.Response) error {
 	body, _ := io.ReadAll(io.LimitReader(resp.Body, 8<<10))
-	if len(body) == 0 {
-		return fmt.Errorf("sidecar API returned %s", resp.Status)
+	message := "sidecar API returned " + resp.Status
+	if len(body) > 0 {
+		message += ": " + strings.TrimSpace(string(body))
+	}
+	if resp.Request != nil {
+		if requestID := resp.Request.Header.Get(tracing.RequestIDHeader); requestID != "" {
+			message += fmt.Sprintf(" (request ID %s)", requestID)
+		}
 	}
-	return fmt.Errorf("sidecar API returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	return errors.New(message)
 }
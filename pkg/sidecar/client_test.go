@@ -2,13 +2,19 @@ package sidecar
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"kubectl-broker/pkg"
 )
 
 func TestListRemoteBackups(t *testing.T) {
@@ -255,3 +261,195 @@ func TestPurgeBackupRequiresName(t *testing.T) {
 		t.Fatalf("expected name validation error, got: %v", err)
 	}
 }
+
+func TestDryRunContextBlocksMutations(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("dry run must not reach the sidecar: %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	ctx := pkg.WithDryRun(context.Background())
+	client := NewClient(server.URL, ClientOptions{})
+	if _, err := client.Restore(ctx, RestoreRequest{Version: "latest"}); !errors.Is(err, pkg.ErrDryRun) {
+		t.Errorf("Restore() = %v, want ErrDryRun", err)
+	}
+	if err := client.PurgeBackup(ctx, "backup-1"); !errors.Is(err, pkg.ErrDryRun) {
+		t.Errorf("PurgeBackup() = %v, want ErrDryRun", err)
+	}
+	if err := client.TriggerUpload(ctx, UploadRequest{Name: "backup-1"}); !errors.Is(err, pkg.ErrDryRun) {
+		t.Errorf("TriggerUpload() = %v, want ErrDryRun", err)
+	}
+}
+
+func TestPresignRemoteBackupReportsUnsupported(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, ClientOptions{})
+	_, err := client.PresignRemoteBackup(context.Background(), "")
+	if !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("expected ErrNotSupported, got %v", err)
+	}
+}
+
+func TestDownloadRemoteBackupStreamsObject(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != remoteDownload {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("version"); got != "ns/backup/20250102.backup" {
+			t.Fatalf("unexpected version: %q", got)
+		}
+		w.Header().Set(remoteKeyHeader, "ns/backup/20250102.backup")
+		_, _ = w.Write([]byte("payload"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, ClientOptions{})
+	object, err := client.DownloadRemoteBackup(context.Background(), "ns/backup/20250102.backup")
+	if err != nil {
+		t.Fatalf("DownloadRemoteBackup returned error: %v", err)
+	}
+	defer object.Body.Close()
+
+	body, _ := io.ReadAll(object.Body)
+	if string(body) != "payload" || object.Key != "ns/backup/20250102.backup" || object.SizeBytes != 7 {
+		t.Fatalf("unexpected object: key=%q size=%d body=%q", object.Key, object.SizeBytes, body)
+	}
+}
+
+func newProbeServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case livenessPath:
+			w.WriteHeader(http.StatusOK)
+		case readinessPath:
+			http.Error(w, "bucket not reachable", http.StatusServiceUnavailable)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestStatusReturnsNotSupportedWithoutEndpoint(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient(newProbeServer(t).URL, ClientOptions{})
+	if _, err := client.Status(context.Background()); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("expected ErrNotSupported, got %v", err)
+	}
+}
+
+func TestProbesReportOutcome(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient(newProbeServer(t).URL, ClientOptions{})
+	if probe := client.Liveness(context.Background()); !probe.OK || probe.StatusCode != http.StatusOK {
+		t.Fatalf("expected liveness to pass: %+v", probe)
+	}
+	probe := client.Readiness(context.Background())
+	if probe.OK || probe.StatusCode != http.StatusServiceUnavailable || !strings.Contains(probe.Error, "bucket not reachable") {
+		t.Fatalf("unexpected readiness result: %+v", probe)
+	}
+}
+
+func TestProbeResultReportsLatencyInMilliseconds(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(ProbeResult{Endpoint: livenessPath, OK: true, LatencyMS: 42})
+	if err != nil {
+		t.Fatalf("marshal probe result: %v", err)
+	}
+	if !strings.Contains(string(data), `"latency_ms":42`) {
+		t.Fatalf("expected latency_ms in milliseconds, got %s", data)
+	}
+}
+
+func TestStatusFromInventory(t *testing.T) {
+	t.Parallel()
+
+	synced := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	status := StatusFromInventory(Inventory{
+		Backups: []BackupInfo{
+			{Name: "old", Status: BackupStateCompleted, LastModified: synced.Add(-time.Hour)},
+			{Name: "new", Status: BackupStateCompleted, LastModified: synced},
+			{Name: "broken", Status: BackupStateFailed, LastModified: synced, Error: "access denied"},
+		},
+	})
+	if !status.LastSync.Equal(synced) {
+		t.Fatalf("expected last sync %v, got %v", synced, status.LastSync)
+	}
+	if len(status.RecentErrors) != 1 || status.RecentErrors[0].Message != "broken: access denied" {
+		t.Fatalf("unexpected recent errors: %+v", status.RecentErrors)
+	}
+}
+
+func TestIdempotentRequestsRetryDroppedConnections(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			// Simulate a forward session dropping mid-request
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("hijack: %v", err)
+				return
+			}
+			_ = conn.Close()
+			return
+		}
+		_, _ = io.WriteString(w, "sidecar_up 1\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, ClientOptions{Retries: 1})
+	payload, err := client.FetchMetrics(context.Background())
+	if err != nil {
+		t.Fatalf("FetchMetrics returned error: %v", err)
+	}
+	if string(payload) != "sidecar_up 1\n" || calls.Load() != 2 {
+		t.Errorf("unexpected result after %d calls: %q", calls.Load(), payload)
+	}
+
+	calls.Store(0)
+	if err := client.PurgeBackup(context.Background(), "b1"); err == nil {
+		t.Error("expected POST requests not to be retried")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected a single POST attempt, got %d", calls.Load())
+	}
+}
+
+func TestClientUsesTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	client := NewClient(server.URL, ClientOptions{TLS: &tls.Config{RootCAs: pool}})
+	if result := client.Liveness(context.Background()); !result.OK {
+		t.Fatalf("liveness over TLS failed: %+v", result)
+	}
+
+	untrusted := NewClient(server.URL, ClientOptions{})
+	if result := untrusted.Liveness(context.Background()); result.OK {
+		t.Fatal("expected certificate verification to fail without the CA")
+	}
+}
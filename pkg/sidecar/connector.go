@@ -4,43 +4,60 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/transport"
 )
 
 // DefaultPort is the REST port exposed by the sidecar.
 const DefaultPort int32 = 8085
 
+// DefaultKeepAlive is the interval of keepalive probes on reconnecting connections.
+const DefaultKeepAlive = 30 * time.Second
+
+// reconnectRetries is how often idempotent requests are retried on reconnecting connections.
+const reconnectRetries = 4
+
 // ErrUnavailable indicates the sidecar connector could not establish a connection.
 var ErrUnavailable = errors.New("sidecar unavailable")
 
 // ConnectOptions control how the connector discovers a pod and establishes port-forwarding.
 type ConnectOptions struct {
-	Namespace      string
-	StatefulSet    string
-	Pod            string
-	RemotePort     int32
+	Namespace   string
+	StatefulSet string
+	Pod         string
+	// Service reaches the sidecar through a ready pod behind this Service instead of a pod of
+	// the StatefulSet, for sidecars deployed on their own. RemotePort is then the service port.
+	Service    string
+	RemotePort int32
+	// TLS talks HTTPS to sidecars that terminate TLS themselves. With a Service the certificate
+	// is verified against its cluster DNS name unless TLS.ServerName is set.
+	TLS            transport.TLSOptions
 	Timeout        time.Duration
 	APIToken       string
 	SkipValidation bool
+	// Reconnect re-establishes a dropped port-forward, probes the sidecar every KeepAlive
+	// (DefaultKeepAlive when zero) so idle sessions stay open, and retries idempotent requests.
+	Reconnect bool
+	KeepAlive time.Duration
 }
 
 // Connector wires Kubernetes port-forwarding with the HTTP client.
 type Connector struct {
-	k8sClient     *pkg.K8sClient
-	portForwarder *pkg.PortForwarder
+	k8sClient *pkg.K8sClient
 }
 
 // NewConnector builds a Connector using the provided Kubernetes client.
 func NewConnector(k8sClient *pkg.K8sClient) *Connector {
-	return &Connector{
-		k8sClient:     k8sClient,
-		portForwarder: pkg.NewPortForwarder(k8sClient.GetConfig(), k8sClient.GetRESTClient()),
-	}
+	return &Connector{k8sClient: k8sClient}
 }
 
 // WithConnection establishes port-forwarding to the sidecar and invokes fn with a configured Client.
@@ -54,15 +71,31 @@ func (c *Connector) WithConnection(ctx context.Context, opts ConnectOptions, fn
 	if opts.Namespace == "" {
 		return fmt.Errorf("%w: namespace is required", ErrUnavailable)
 	}
-	if opts.Pod == "" && opts.StatefulSet == "" {
-		return fmt.Errorf("%w: statefulset is required when pod is not specified", ErrUnavailable)
+	if opts.Pod == "" && opts.StatefulSet == "" && opts.Service == "" {
+		return fmt.Errorf("%w: statefulset is required when neither pod nor service is specified", ErrUnavailable)
+	}
+	if opts.Pod != "" && opts.Service != "" {
+		return fmt.Errorf("%w: pod and service are mutually exclusive", ErrUnavailable)
 	}
 	remotePort := opts.RemotePort
 	if remotePort == 0 {
 		remotePort = DefaultPort
 	}
 
-	pod, err := ResolveSidecarPod(ctx, c.k8sClient, opts.Namespace, opts.StatefulSet, opts.Pod)
+	var pod *v1.Pod
+	var err error
+	if opts.Service != "" {
+		pod, remotePort, err = resolveServiceTarget(ctx, c.k8sClient, opts.Namespace, opts.Service, remotePort)
+		if opts.TLS.IsEnabled() && opts.TLS.ServerName == "" {
+			opts.TLS.ServerName = fmt.Sprintf("%s.%s.svc", opts.Service, opts.Namespace)
+		}
+	} else {
+		pod, err = ResolveSidecarPod(ctx, c.k8sClient, opts.Namespace, opts.StatefulSet, opts.Pod)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	tlsConfig, err := opts.TLS.Config()
 	if err != nil {
 		return fmt.Errorf("%w: %v", ErrUnavailable, err)
 	}
@@ -73,30 +106,70 @@ func (c *Connector) WithConnection(ctx context.Context, opts ConnectOptions, fn
 		}
 	}
 
-	localPort, err := pkg.GetRandomPort()
-	if err != nil {
-		return fmt.Errorf("allocate local port: %w", err)
-	}
-
-	err = c.portForwarder.PerformWithPortForwarding(ctx, pod, remotePort, localPort, func(localPort int) error {
-		baseURL := fmt.Sprintf("http://localhost:%d", localPort)
-		client := NewClient(baseURL, ClientOptions{
+	dialer := pkg.NewPodDialer(c.k8sClient, pod, remotePort, opts.Reconnect)
+	err = dialer.Forward(ctx, func(localPort int) error {
+		baseURL := opts.TLS.BaseURL(localPort)
+		clientOptions := ClientOptions{
 			Timeout:  opts.Timeout,
 			APIToken: opts.APIToken,
-		})
+			TLS:      tlsConfig,
+		}
+		if opts.Reconnect {
+			clientOptions.Retries = reconnectRetries
+			stop := keepAlive(ctx, NewClient(baseURL, clientOptions), opts.KeepAlive)
+			defer stop()
+		}
+		client := NewClient(baseURL, clientOptions)
 		if fnErr := fn(client); fnErr != nil {
 			return clientFnError{err: fnErr}
 		}
 		return nil
 	})
-	if err != nil {
-		var fnErr clientFnError
-		if errors.As(err, &fnErr) {
-			return fnErr.err
+	return unwrapConnectionError(err)
+}
+
+// unwrapConnectionError returns errors of the client callback as-is and marks everything else,
+// i.e. failures of the forward session itself, as ErrUnavailable
+func unwrapConnectionError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var fnErr clientFnError
+	if errors.As(err, &fnErr) {
+		return fnErr.err
+	}
+	return fmt.Errorf("%w: %v", ErrUnavailable, err)
+}
+
+// keepAlive probes the sidecar liveness endpoint every interval until the returned stop function
+// is called. The traffic keeps intermediaries from closing an idle forward session.
+func keepAlive(ctx context.Context, client *Client, interval time.Duration) func() {
+	if interval <= 0 {
+		interval = DefaultKeepAlive
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				probeCtx, probeCancel := context.WithTimeout(ctx, interval)
+				if result := client.Liveness(probeCtx); !result.OK && ctx.Err() == nil {
+					slog.Debug("Sidecar keepalive probe failed", "error", result.Error)
+				}
+				probeCancel()
+			}
 		}
-		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}()
+	return func() {
+		cancel()
+		<-done
 	}
-	return nil
 }
 
 // ResolveSidecarPod picks the pod that hosts the sidecar.
@@ -141,6 +214,65 @@ func ResolveSidecarPod(ctx context.Context, k8sClient *pkg.K8sClient, namespace,
 	return pods[0], nil
 }
 
+// resolveServiceTarget picks a ready pod behind the Service and maps the service port to the
+// container port it targets
+func resolveServiceTarget(ctx context.Context, k8sClient *pkg.K8sClient, namespace, serviceName string, servicePort int32) (*v1.Pod, int32, error) {
+	service, err := k8sClient.GetService(ctx, namespace, serviceName)
+	if err != nil {
+		return nil, 0, pkg.EnhanceError(err, fmt.Sprintf("Service %s in namespace %s", serviceName, namespace))
+	}
+	pod, err := pkg.ReadyPodForService(ctx, k8sClient, service)
+	if err != nil {
+		return nil, 0, err
+	}
+	port, err := ServiceTargetPort(service, pod, servicePort)
+	if err != nil {
+		return nil, 0, err
+	}
+	slog.Debug("Resolved sidecar service", "service", serviceName, "pod", pod.Name, "servicePort", servicePort, "targetPort", port)
+	return pod, port, nil
+}
+
+// ServiceTargetPort returns the container port of pod that servicePort of service forwards to.
+// A service with a single port is used whatever its number, so --sidecar-port may stay at the
+// default for services that expose the sidecar on 80 or 443.
+func ServiceTargetPort(service *v1.Service, pod *v1.Pod, servicePort int32) (int32, error) {
+	var match *v1.ServicePort
+	for i := range service.Spec.Ports {
+		if service.Spec.Ports[i].Port == servicePort {
+			match = &service.Spec.Ports[i]
+			break
+		}
+	}
+	if match == nil && len(service.Spec.Ports) == 1 {
+		match = &service.Spec.Ports[0]
+	}
+	if match == nil {
+		ports := make([]string, 0, len(service.Spec.Ports))
+		for _, port := range service.Spec.Ports {
+			ports = append(ports, strconv.Itoa(int(port.Port)))
+		}
+		return 0, fmt.Errorf("service %s has no port %d (ports: %s)", service.Name, servicePort, strings.Join(ports, ", "))
+	}
+
+	switch {
+	case match.TargetPort.Type == intstr.String && match.TargetPort.StrVal != "":
+		for _, container := range pod.Spec.Containers {
+			for _, port := range container.Ports {
+				if port.Name == match.TargetPort.StrVal {
+					return port.ContainerPort, nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("pod %s declares no container port named %q targeted by service %s", pod.Name, match.TargetPort.StrVal, service.Name)
+	case match.TargetPort.IntVal != 0:
+		return match.TargetPort.IntVal, nil
+	default:
+		// An unset targetPort defaults to the service port
+		return match.Port, nil
+	}
+}
+
 type clientFnError struct {
 	err error
 }
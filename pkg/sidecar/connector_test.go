@@ -0,0 +1,48 @@
+package sidecar
+
+import (
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestServiceTargetPort(t *testing.T) {
+	t.Parallel()
+
+	pod := &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{
+		{Name: "backup-sidecar", Ports: []v1.ContainerPort{{Name: "https", ContainerPort: 8443}}},
+	}}}
+	service := func(ports ...v1.ServicePort) *v1.Service {
+		return &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "backup-sidecar"}, Spec: v1.ServiceSpec{Ports: ports}}
+	}
+
+	tests := []struct {
+		name    string
+		service *v1.Service
+		port    int32
+		want    int32
+		wantErr string
+	}{
+		{"named target port", service(v1.ServicePort{Port: 443, TargetPort: intstr.FromString("https")}), 443, 8443, ""},
+		{"numeric target port", service(v1.ServicePort{Port: 80, TargetPort: intstr.FromInt32(8085)}, v1.ServicePort{Port: 443}), 80, 8085, ""},
+		{"unset target port", service(v1.ServicePort{Port: 8085}, v1.ServicePort{Port: 9090}), 8085, 8085, ""},
+		{"single port ignores the number", service(v1.ServicePort{Port: 443, TargetPort: intstr.FromInt32(8443)}), DefaultPort, 8443, ""},
+		{"unknown port", service(v1.ServicePort{Port: 80}, v1.ServicePort{Port: 443}), DefaultPort, 0, "has no port 8085 (ports: 80, 443)"},
+		{"unknown port name", service(v1.ServicePort{Port: 443, TargetPort: intstr.FromString("api")}), 443, 0, `no container port named "api"`},
+	}
+	for _, tt := range tests {
+		got, err := ServiceTargetPort(tt.service, pod, tt.port)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: ServiceTargetPort() = %d, %v, want %d", tt.name, got, err, tt.want)
+		}
+	}
+}
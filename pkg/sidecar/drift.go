@@ -0,0 +1,126 @@
+package sidecar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DriftItem is an on-pod backup whose remote copy is missing.
+type DriftItem struct {
+	Name         string      `json:"name"`
+	Type         string      `json:"type"`
+	SizeBytes    int64       `json:"sizeBytes"`
+	LastModified time.Time   `json:"lastModified"`
+	Status       BackupState `json:"status"`
+	Error        string      `json:"error,omitempty"`
+	// UploadTriggered is set once --fix asked the sidecar to upload the backup
+	UploadTriggered bool `json:"uploadTriggered,omitempty"`
+}
+
+// DriftReport compares the on-pod backups of a sidecar with the objects in remote storage.
+type DriftReport struct {
+	// SyncTriggered is false for sidecars without the sync endpoint; the report then reflects
+	// the state of their last scheduled sync
+	SyncTriggered bool `json:"syncTriggered"`
+	// InSync counts on-pod backups with a remote copy
+	InSync int `json:"inSync"`
+	// Uploading are on-pod backups the sidecar is still uploading; they are not drift
+	Uploading []DriftItem `json:"uploading,omitempty"`
+	// NotUploaded are on-pod backups without a remote copy, including those the sidecar reports
+	// as uploaded although no remote object exists
+	NotUploaded []DriftItem `json:"notUploaded,omitempty"`
+	// MissingLocally are remote objects without an on-pod backup, e.g. after --delete-local
+	MissingLocally []RemoteBackupInfo `json:"missingLocally,omitempty"`
+}
+
+// HasDrift reports whether on-pod backups are missing from remote storage.
+func (r *DriftReport) HasDrift() bool {
+	return len(r.NotUploaded) > 0
+}
+
+// DriftOptions control CheckDrift.
+type DriftOptions struct {
+	// Fix triggers an upload of every on-pod backup without a remote copy
+	Fix bool
+}
+
+// CheckDrift asks the sidecar to reconcile its remote inventory and compares the on-pod backups
+// with the remote objects. Sidecars without the sync endpoint are compared as they are.
+func CheckDrift(ctx context.Context, engine RemoteEngine, opts DriftOptions) (*DriftReport, error) {
+	syncErr := engine.TriggerSync(ctx)
+	if syncErr != nil && !errors.Is(syncErr, ErrNotSupported) {
+		return nil, fmt.Errorf("failed to trigger sidecar sync: %w", syncErr)
+	}
+
+	inventory, err := engine.ListInventory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sidecar inventory: %w", err)
+	}
+	remote, err := engine.ListRemoteBackups(ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remote backups: %w", err)
+	}
+
+	report := CompareInventory(inventory, remote)
+	report.SyncTriggered = syncErr == nil
+
+	if opts.Fix {
+		for i := range report.NotUploaded {
+			item := &report.NotUploaded[i]
+			if err := engine.TriggerUpload(ctx, UploadRequest{Type: item.Type, Name: item.Name}); err != nil {
+				return report, fmt.Errorf("failed to trigger upload of %s: %w", item.Name, err)
+			}
+			item.UploadTriggered = true
+		}
+	}
+	return report, nil
+}
+
+// CompareInventory matches on-pod backups to remote objects whose key contains the backup name,
+// the same way WaitForRemoteCopy finds the remote copy of a fresh backup.
+func CompareInventory(inventory Inventory, remote []RemoteBackupInfo) *DriftReport {
+	report := &DriftReport{}
+	matched := make([]bool, len(remote))
+
+	check := func(info BackupInfo, uploadType string) {
+		found := false
+		for i, object := range remote {
+			if strings.Contains(object.Key, info.Name) {
+				matched[i] = true
+				found = true
+			}
+		}
+		item := DriftItem{
+			Name:         info.Name,
+			Type:         uploadType,
+			SizeBytes:    info.SizeBytes,
+			LastModified: info.LastModified,
+			Status:       info.Status,
+			Error:        info.Error,
+		}
+		switch {
+		case found:
+			report.InSync++
+		case info.Status == BackupStatePending || info.Status == BackupStateUploading:
+			report.Uploading = append(report.Uploading, item)
+		default:
+			report.NotUploaded = append(report.NotUploaded, item)
+		}
+	}
+
+	for _, item := range inventory.ClusterBackups {
+		check(BackupInfo(item), UploadTypeCluster)
+	}
+	for _, item := range inventory.Backups {
+		check(item, UploadTypeBackup)
+	}
+	for i, object := range remote {
+		if !matched[i] {
+			report.MissingLocally = append(report.MissingLocally, object)
+		}
+	}
+	return report
+}
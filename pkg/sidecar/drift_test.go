@@ -0,0 +1,88 @@
+package sidecar
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestCompareInventoryClassifiesDrift(t *testing.T) {
+	t.Parallel()
+
+	inventory := Inventory{
+		ClusterBackups: []ClusterBackupInfo{{Name: "cluster-1", Status: BackupStateCompleted}},
+		Backups: []BackupInfo{
+			{Name: "backup-a", Status: BackupStateCompleted},
+			{Name: "backup-b", Status: BackupStateUploading},
+			{Name: "backup-c", Status: BackupStateFailed, Error: "bucket denied"},
+		},
+	}
+	remote := []RemoteBackupInfo{
+		{Key: "tenant/backup-a.tgz"},
+		{Key: "tenant/backup-old.tgz"},
+	}
+
+	report := CompareInventory(inventory, remote)
+	if report.InSync != 1 {
+		t.Errorf("InSync = %d, want 1", report.InSync)
+	}
+	if len(report.Uploading) != 1 || report.Uploading[0].Name != "backup-b" {
+		t.Errorf("Uploading = %+v, want backup-b", report.Uploading)
+	}
+	// cluster-1 claims to be uploaded but has no remote object: a silent sync failure
+	if len(report.NotUploaded) != 2 || report.NotUploaded[0].Name != "cluster-1" || report.NotUploaded[0].Type != UploadTypeCluster || report.NotUploaded[1].Error != "bucket denied" {
+		t.Errorf("NotUploaded = %+v, want cluster-1 and backup-c", report.NotUploaded)
+	}
+	if len(report.MissingLocally) != 1 || report.MissingLocally[0].Key != "tenant/backup-old.tgz" {
+		t.Errorf("MissingLocally = %+v, want backup-old", report.MissingLocally)
+	}
+	if !report.HasDrift() {
+		t.Error("HasDrift() = false, want true")
+	}
+}
+
+func TestCheckDriftFixesWithoutSyncEndpoint(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var uploads []UploadRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case syncPath:
+			http.NotFound(w, r)
+		case localListPath:
+			_ = json.NewEncoder(w).Encode(Inventory{Backups: []BackupInfo{{Name: "backup-a", Status: BackupStateFailed}}})
+		case remoteListPath:
+			_ = json.NewEncoder(w).Encode(map[string]any{"backups": []RemoteBackupInfo{}})
+		case forceUploadPath:
+			var upload UploadRequest
+			_ = json.NewDecoder(r.Body).Decode(&upload)
+			uploads = append(uploads, upload)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	report, err := CheckDrift(context.Background(), NewClient(server.URL, ClientOptions{}), DriftOptions{Fix: true})
+	if err != nil {
+		t.Fatalf("CheckDrift returned error: %v", err)
+	}
+	if report.SyncTriggered {
+		t.Error("SyncTriggered = true for a sidecar without the sync endpoint")
+	}
+	if len(report.NotUploaded) != 1 || !report.NotUploaded[0].UploadTriggered {
+		t.Errorf("NotUploaded = %+v, want backup-a with a triggered upload", report.NotUploaded)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(uploads) != 1 || uploads[0] != (UploadRequest{Type: UploadTypeBackup, Name: "backup-a"}) {
+		t.Errorf("uploads = %+v, want one backup upload of backup-a", uploads)
+	}
+}
@@ -0,0 +1,195 @@
+package sidecar
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/transport"
+)
+
+// RemoteEngine is the sidecar API used by remote backup workflows. *Client implements it for an
+// established connection; NewRemoteEngine returns one that connects through a PortForwardDialer.
+type RemoteEngine interface {
+	ListInventory(ctx context.Context) (Inventory, error)
+	ListRemoteBackups(ctx context.Context, limit int) ([]RemoteBackupInfo, error)
+	Restore(ctx context.Context, req RestoreRequest) (*RestoreResult, error)
+	PurgeBackup(ctx context.Context, name string) error
+	TriggerUpload(ctx context.Context, req UploadRequest) error
+	TriggerSync(ctx context.Context) error
+	PresignRemoteBackup(ctx context.Context, version string) (*PresignedObject, error)
+	DownloadRemoteBackup(ctx context.Context, version string) (*RemoteObjectStream, error)
+	FetchMetrics(ctx context.Context) ([]byte, error)
+	Status(ctx context.Context) (*Status, error)
+	Liveness(ctx context.Context) ProbeResult
+	Readiness(ctx context.Context) ProbeResult
+}
+
+var _ RemoteEngine = (*Client)(nil)
+
+// NewRemoteEngine returns a RemoteEngine that reaches the sidecar through dialer. Every call opens
+// its own connection; a streamed download keeps its connection until the body is closed.
+func NewRemoteEngine(dialer pkg.PortForwardDialer, opts ClientOptions) RemoteEngine {
+	return &dialedEngine{dialer: dialer, options: opts}
+}
+
+// dialedEngine implements RemoteEngine with one forward session per call
+type dialedEngine struct {
+	dialer  pkg.PortForwardDialer
+	options ClientOptions
+}
+
+// do opens a connection and invokes fn with a client bound to it
+func (e *dialedEngine) do(ctx context.Context, fn func(*Client) error) error {
+	err := e.dialer.Forward(ctx, func(localPort int) error {
+		if fnErr := fn(e.client(localPort)); fnErr != nil {
+			return clientFnError{err: fnErr}
+		}
+		return nil
+	})
+	return unwrapConnectionError(err)
+}
+
+func (e *dialedEngine) client(localPort int) *Client {
+	return NewClient(transport.LocalURL("http", localPort), e.options)
+}
+
+func (e *dialedEngine) ListInventory(ctx context.Context) (inventory Inventory, err error) {
+	err = e.do(ctx, func(client *Client) error {
+		inventory, err = client.ListInventory(ctx)
+		return err
+	})
+	return inventory, err
+}
+
+func (e *dialedEngine) ListRemoteBackups(ctx context.Context, limit int) (backups []RemoteBackupInfo, err error) {
+	err = e.do(ctx, func(client *Client) error {
+		backups, err = client.ListRemoteBackups(ctx, limit)
+		return err
+	})
+	return backups, err
+}
+
+func (e *dialedEngine) Restore(ctx context.Context, req RestoreRequest) (result *RestoreResult, err error) {
+	err = e.do(ctx, func(client *Client) error {
+		result, err = client.Restore(ctx, req)
+		return err
+	})
+	return result, err
+}
+
+func (e *dialedEngine) PurgeBackup(ctx context.Context, name string) error {
+	return e.do(ctx, func(client *Client) error {
+		return client.PurgeBackup(ctx, name)
+	})
+}
+
+func (e *dialedEngine) TriggerUpload(ctx context.Context, req UploadRequest) error {
+	return e.do(ctx, func(client *Client) error {
+		return client.TriggerUpload(ctx, req)
+	})
+}
+
+func (e *dialedEngine) TriggerSync(ctx context.Context) error {
+	return e.do(ctx, func(client *Client) error {
+		return client.TriggerSync(ctx)
+	})
+}
+
+func (e *dialedEngine) PresignRemoteBackup(ctx context.Context, version string) (object *PresignedObject, err error) {
+	err = e.do(ctx, func(client *Client) error {
+		object, err = client.PresignRemoteBackup(ctx, version)
+		return err
+	})
+	return object, err
+}
+
+func (e *dialedEngine) FetchMetrics(ctx context.Context) (payload []byte, err error) {
+	err = e.do(ctx, func(client *Client) error {
+		payload, err = client.FetchMetrics(ctx)
+		return err
+	})
+	return payload, err
+}
+
+func (e *dialedEngine) Status(ctx context.Context) (status *Status, err error) {
+	err = e.do(ctx, func(client *Client) error {
+		status, err = client.Status(ctx)
+		return err
+	})
+	return status, err
+}
+
+func (e *dialedEngine) Liveness(ctx context.Context) ProbeResult {
+	return e.probe(ctx, livenessPath, (*Client).Liveness)
+}
+
+func (e *dialedEngine) Readiness(ctx context.Context) ProbeResult {
+	return e.probe(ctx, readinessPath, (*Client).Readiness)
+}
+
+// probe reports a connection failure as a failed probe, like the client does for HTTP errors
+func (e *dialedEngine) probe(ctx context.Context, endpoint string, fn func(*Client, context.Context) ProbeResult) ProbeResult {
+	var result ProbeResult
+	err := e.do(ctx, func(client *Client) error {
+		result = fn(client, ctx)
+		return nil
+	})
+	if err != nil {
+		return ProbeResult{Endpoint: endpoint, Error: err.Error()}
+	}
+	return result
+}
+
+// DownloadRemoteBackup keeps the forward session open in the background until the returned body
+// is closed or ctx is cancelled
+func (e *dialedEngine) DownloadRemoteBackup(ctx context.Context, version string) (*RemoteObjectStream, error) {
+	opened := make(chan *RemoteObjectStream, 1)
+	release := make(chan struct{})
+	done := make(chan error, 1)
+
+	go func() {
+		done <- e.do(ctx, func(client *Client) error {
+			stream, err := client.DownloadRemoteBackup(ctx, version)
+			if err != nil {
+				return err
+			}
+			opened <- stream
+			select {
+			case <-release:
+			case <-ctx.Done():
+			}
+			return nil
+		})
+	}()
+
+	select {
+	case stream := <-opened:
+		stream.Body = &forwardedBody{ReadCloser: stream.Body, release: release, done: done}
+		return stream, nil
+	case err := <-done:
+		if err == nil {
+			err = fmt.Errorf("%w: connection closed before the download started", ErrUnavailable)
+		}
+		return nil, err
+	}
+}
+
+// forwardedBody closes the forward session together with the streamed body
+type forwardedBody struct {
+	io.ReadCloser
+	release chan struct{}
+	done    chan error
+	once    sync.Once
+}
+
+func (b *forwardedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		close(b.release)
+		<-b.done
+	})
+	return err
+}
@@ -0,0 +1,83 @@
+package sidecar
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"kubectl-broker/pkg"
+)
+
+func serverDialer(t *testing.T, serverURL string) pkg.PortForwardDialer {
+	t.Helper()
+	parsed, err := url.Parse(serverURL)
+	if err != nil {
+		t.Fatalf("invalid server URL: %v", err)
+	}
+	port, err := strconv.Atoi(parsed.Port())
+	if err != nil {
+		t.Fatalf("invalid server port: %v", err)
+	}
+	return pkg.LocalDialer(port)
+}
+
+func TestRemoteEngineKeepsConnectionForDownload(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(remoteKeyHeader, "snapshot.backup")
+		_, _ = w.Write([]byte("payload"))
+	}))
+	defer server.Close()
+
+	closed := make(chan struct{})
+	base := serverDialer(t, server.URL)
+	dialer := pkg.PortForwardDialerFunc(func(ctx context.Context, fn func(localPort int) error) error {
+		defer close(closed)
+		return base.Forward(ctx, fn)
+	})
+
+	stream, err := NewRemoteEngine(dialer, ClientOptions{}).DownloadRemoteBackup(context.Background(), "latest")
+	if err != nil {
+		t.Fatalf("DownloadRemoteBackup returned error: %v", err)
+	}
+	select {
+	case <-closed:
+		t.Fatal("connection closed before the body was read")
+	default:
+	}
+
+	body, err := io.ReadAll(stream.Body)
+	if err != nil || string(body) != "payload" || stream.Key != "snapshot.backup" {
+		t.Fatalf("unexpected stream %q key=%q err=%v", body, stream.Key, err)
+	}
+	if err := stream.Body.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	select {
+	case <-closed:
+	default:
+		t.Fatal("connection still open after the body was closed")
+	}
+}
+
+func TestRemoteEngineMarksDialFailuresUnavailable(t *testing.T) {
+	t.Parallel()
+
+	dialer := pkg.PortForwardDialerFunc(func(ctx context.Context, fn func(localPort int) error) error {
+		return errors.New("pod not ready")
+	})
+	engine := NewRemoteEngine(dialer, ClientOptions{})
+
+	if _, err := engine.ListInventory(context.Background()); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("expected ErrUnavailable, got %v", err)
+	}
+	if result := engine.Liveness(context.Background()); result.OK || result.Endpoint != livenessPath {
+		t.Fatalf("expected failed liveness probe, got %+v", result)
+	}
+}
@@ -0,0 +1,42 @@
+package sidecar
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// retryBackoff is the delay before the first retry of an idempotent request; it doubles per attempt
+const retryBackoff = time.Second
+
+// doIdempotent sends a GET or HEAD request and retries it on transport errors, which occur while
+// a dropped port-forward is being re-established. Responses with an HTTP status are not retried.
+func (c *Client) doIdempotent(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return c.httpClient.Do(req)
+	}
+
+	delay := retryBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err := c.httpClient.Do(req)
+		if err == nil || attempt >= c.retries || req.Context().Err() != nil || isTimeout(err) {
+			return resp, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// isTimeout reports whether the client timeout expired; such requests already waited their full budget
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -1,6 +1,9 @@
 package sidecar
 
-import "time"
+import (
+	"io"
+	"time"
+)
 
 // BackupState mirrors the sidecar backup status strings.
 type BackupState string
@@ -20,22 +23,24 @@ type Inventory struct {
 
 // ClusterBackupInfo describes a directory under the cluster backup path.
 type ClusterBackupInfo struct {
-	Name         string      `json:"name"`
-	Path         string      `json:"path"`
-	SizeBytes    int64       `json:"size_bytes"`
-	LastModified time.Time   `json:"last_modified"`
-	Status       BackupState `json:"status"`
-	Error        string      `json:"error,omitempty"`
+	Name          string      `json:"name"`
+	Path          string      `json:"path"`
+	SizeBytes     int64       `json:"size_bytes"`
+	LastModified  time.Time   `json:"last_modified"`
+	Status        BackupState `json:"status"`
+	Error         string      `json:"error,omitempty"`
+	UploadedBytes int64       `json:"uploaded_bytes,omitempty"` // only reported by sidecars that track upload progress
 }
 
 // BackupInfo describes a HiveMQ backup folder in BACKUP_DIR.
 type BackupInfo struct {
-	Name         string      `json:"name"`
-	Path         string      `json:"path"`
-	SizeBytes    int64       `json:"size_bytes"`
-	LastModified time.Time   `json:"last_modified"`
-	Status       BackupState `json:"status"`
-	Error        string      `json:"error,omitempty"`
+	Name          string      `json:"name"`
+	Path          string      `json:"path"`
+	SizeBytes     int64       `json:"size_bytes"`
+	LastModified  time.Time   `json:"last_modified"`
+	Status        BackupState `json:"status"`
+	Error         string      `json:"error,omitempty"`
+	UploadedBytes int64       `json:"uploaded_bytes,omitempty"` // only reported by sidecars that track upload progress
 }
 
 // RemoteBackupInfo represents a backup object stored in S3.
@@ -45,6 +50,48 @@ type RemoteBackupInfo struct {
 	SizeBytes    int64     `json:"size_bytes"`
 }
 
+// PresignedObject is returned by GET /v1/backup/presign.
+type PresignedObject struct {
+	Key       string    `json:"key"`
+	URL       string    `json:"url"`
+	SizeBytes int64     `json:"size_bytes"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// RemoteObjectStream is a remote backup object streamed through the sidecar.
+type RemoteObjectStream struct {
+	Key       string
+	SizeBytes int64
+	Body      io.ReadCloser
+}
+
+// Status is returned by GET /v1/status.
+type Status struct {
+	Version      string        `json:"version"`
+	Bucket       string        `json:"bucket"`
+	Prefix       string        `json:"prefix,omitempty"`
+	Region       string        `json:"region,omitempty"`
+	Endpoint     string        `json:"endpoint,omitempty"`
+	BackupDir    string        `json:"backup_dir,omitempty"`
+	LastSync     time.Time     `json:"last_sync"`
+	RecentErrors []StatusError `json:"recent_errors,omitempty"`
+}
+
+// StatusError is an error the sidecar recorded while watching or uploading.
+type StatusError struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+// ProbeResult is the outcome of a liveness or readiness probe.
+type ProbeResult struct {
+	Endpoint   string `json:"endpoint"`
+	OK         bool   `json:"ok"`
+	StatusCode int    `json:"status_code,omitempty"`
+	LatencyMS  int64  `json:"latency_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
 // RestoreRequest mirrors the /v1/restore payload.
 type RestoreRequest struct {
 	Version string `json:"version,omitempty"`
@@ -60,6 +107,12 @@ type RestoreResult struct {
 	LastChecked time.Time `json:"last_checked"`
 }
 
+// Upload types accepted by POST /v1/backup/upload.
+const (
+	UploadTypeBackup  = "backup"
+	UploadTypeCluster = "cluster"
+)
+
 // UploadRequest maps to POST /v1/backup/upload.
 type UploadRequest struct {
 	Type string `json:"type"`
@@ -70,3 +123,49 @@ type UploadRequest struct {
 type PurgeRequest struct {
 	Name string `json:"name"`
 }
+
+// Find returns the local backup with the given name and the upload type it needs.
+// Cluster backups are reported with the BackupInfo fields of their directory.
+func (inv Inventory) Find(name string) (BackupInfo, string, bool) {
+	for _, item := range inv.Backups {
+		if item.Name == name {
+			return item, UploadTypeBackup, true
+		}
+	}
+	for _, item := range inv.ClusterBackups {
+		if item.Name == name {
+			return BackupInfo(item), UploadTypeCluster, true
+		}
+	}
+	return BackupInfo{}, "", false
+}
+
+// StatusFromInventory approximates Status for sidecars without the status endpoint:
+// the newest uploaded backup is used as the last sync and failed entries as recent errors.
+func StatusFromInventory(inv Inventory) *Status {
+	status := &Status{}
+
+	record := func(state BackupState, modified time.Time, name, errMsg string) {
+		switch state {
+		case BackupStateCompleted:
+			if modified.After(status.LastSync) {
+				status.LastSync = modified
+			}
+		case BackupStateFailed:
+			message := name
+			if errMsg != "" {
+				message = name + ": " + errMsg
+			}
+			status.RecentErrors = append(status.RecentErrors, StatusError{Time: modified, Message: message})
+		}
+	}
+
+	for _, item := range inv.ClusterBackups {
+		record(item.Status, item.LastModified, item.Name, item.Error)
+	}
+	for _, item := range inv.Backups {
+		record(item.Status, item.LastModified, item.Name, item.Error)
+	}
+
+	return status
+}
@@ -0,0 +1,176 @@
+package sidecar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const defaultPushPollInterval = 2 * time.Second
+
+// ErrBackupNotFound indicates the sidecar has no local backup with the requested name.
+var ErrBackupNotFound = errors.New("backup not found on the sidecar")
+
+// PushOptions control an on-demand upload.
+type PushOptions struct {
+	// DeleteLocal purges the on-pod copy once the upload completed
+	DeleteLocal bool
+	// PollInterval between inventory checks while the upload runs
+	PollInterval time.Duration
+	// Progress is called with every polled state of the backup
+	Progress func(BackupInfo)
+}
+
+// PushResult describes a finished upload.
+type PushResult struct {
+	Name            string        `json:"name"`
+	Type            string        `json:"type"`
+	SizeBytes       int64         `json:"sizeBytes"`
+	AlreadyUploaded bool          `json:"alreadyUploaded"`
+	DeletedLocal    bool          `json:"deletedLocal"`
+	Duration        time.Duration `json:"duration"`
+}
+
+// PushBackup asks the sidecar to upload a local backup to object storage right away instead of
+// on its own schedule, and waits until the inventory reports the upload as finished.
+func PushBackup(ctx context.Context, engine RemoteEngine, name string, opts PushOptions) (*PushResult, error) {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = defaultPushPollInterval
+	}
+	start := time.Now()
+
+	inventory, err := engine.ListInventory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sidecar inventory: %w", err)
+	}
+	info, uploadType, ok := inventory.Find(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrBackupNotFound, name)
+	}
+	result := &PushResult{Name: name, Type: uploadType, SizeBytes: info.SizeBytes}
+
+	if info.Status == BackupStateCompleted {
+		result.AlreadyUploaded = true
+	} else {
+		if err := engine.TriggerUpload(ctx, UploadRequest{Type: uploadType, Name: name}); err != nil {
+			return nil, fmt.Errorf("failed to trigger upload: %w", err)
+		}
+		if info, err = waitForUpload(ctx, engine, name, interval, opts.Progress); err != nil {
+			return nil, err
+		}
+		result.SizeBytes = info.SizeBytes
+	}
+
+	if opts.DeleteLocal {
+		if err := engine.PurgeBackup(ctx, name); err != nil {
+			return result, fmt.Errorf("backup was uploaded but deleting the local copy failed: %w", err)
+		}
+		result.DeletedLocal = true
+	}
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// waitForUpload polls the inventory until the backup is uploaded or failed
+func waitForUpload(ctx context.Context, engine RemoteEngine, name string, interval time.Duration, progress func(BackupInfo)) (BackupInfo, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return BackupInfo{}, fmt.Errorf("waiting for upload of %s: %w", name, ctx.Err())
+		case <-ticker.C:
+		}
+
+		inventory, err := engine.ListInventory(ctx)
+		if err != nil {
+			return BackupInfo{}, fmt.Errorf("failed to poll upload state: %w", err)
+		}
+		info, _, ok := inventory.Find(name)
+		if !ok {
+			return BackupInfo{}, fmt.Errorf("%w: %s disappeared while uploading", ErrBackupNotFound, name)
+		}
+		if progress != nil {
+			progress(info)
+		}
+
+		switch info.Status {
+		case BackupStateCompleted:
+			return info, nil
+		case BackupStateFailed:
+			if info.Error != "" {
+				return info, fmt.Errorf("upload of %s failed: %s", name, info.Error)
+			}
+			return info, fmt.Errorf("upload of %s failed", name)
+		}
+	}
+}
+
+// RemoteWaitOptions control WaitForRemoteCopy.
+type RemoteWaitOptions struct {
+	// PollInterval between inventory checks
+	PollInterval time.Duration
+	// Progress is called with every polled state of the backup once the sidecar lists it
+	Progress func(BackupInfo)
+}
+
+// RemoteCopy describes a backup that reached object storage.
+type RemoteCopy struct {
+	Name      string        `json:"name"`
+	Key       string        `json:"key"`
+	SizeBytes int64         `json:"sizeBytes"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// WaitForRemoteCopy waits until the sidecar has uploaded a freshly created backup on its own
+// schedule and the object shows up in the remote inventory. The backup may not be in the
+// sidecar's local inventory yet when the wait starts; the wait is bounded by ctx.
+func WaitForRemoteCopy(ctx context.Context, engine RemoteEngine, name string, opts RemoteWaitOptions) (*RemoteCopy, error) {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = defaultPushPollInterval
+	}
+	start := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		inventory, err := engine.ListInventory(ctx)
+		if err != nil && ctx.Err() == nil {
+			return nil, fmt.Errorf("failed to poll upload state: %w", err)
+		}
+		if info, _, ok := inventory.Find(name); ok && err == nil {
+			if opts.Progress != nil {
+				opts.Progress(info)
+			}
+			switch info.Status {
+			case BackupStateFailed:
+				if info.Error != "" {
+					return nil, fmt.Errorf("upload of %s failed: %s", name, info.Error)
+				}
+				return nil, fmt.Errorf("upload of %s failed", name)
+			case BackupStateCompleted:
+				remote, err := engine.ListRemoteBackups(ctx, 0)
+				if err != nil && ctx.Err() == nil {
+					return nil, fmt.Errorf("failed to list remote backups: %w", err)
+				}
+				for _, object := range remote {
+					if strings.Contains(object.Key, name) {
+						return &RemoteCopy{Name: name, Key: object.Key, SizeBytes: object.SizeBytes, Duration: time.Since(start)}, nil
+					}
+				}
+				// The remote listing can lag behind the upload; keep polling
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for remote copy of %s: %w", name, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
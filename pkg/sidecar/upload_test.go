@@ -0,0 +1,163 @@
+package sidecar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// uploadServer serves an inventory that advances one state per poll after the upload was triggered
+func uploadServer(t *testing.T, states ...BackupState) (*httptest.Server, func() (UploadRequest, bool)) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var upload UploadRequest
+	var purged bool
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case localListPath:
+			state := BackupStatePending
+			if upload.Name != "" {
+				state = states[min(polls, len(states)-1)]
+				polls++
+			}
+			_ = json.NewEncoder(w).Encode(Inventory{ClusterBackups: []ClusterBackupInfo{{
+				Name: "cluster-1", SizeBytes: 100, UploadedBytes: 40, Status: state, Error: "bucket denied",
+			}}})
+		case forceUploadPath:
+			_ = json.NewDecoder(r.Body).Decode(&upload)
+		case purgePath:
+			purged = true
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() (UploadRequest, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		return upload, purged
+	}
+}
+
+func TestPushBackupWaitsForUploadAndDeletesLocal(t *testing.T) {
+	t.Parallel()
+
+	server, recorded := uploadServer(t, BackupStateUploading, BackupStateCompleted)
+	var seen []BackupState
+	result, err := PushBackup(context.Background(), NewClient(server.URL, ClientOptions{}), "cluster-1", PushOptions{
+		DeleteLocal:  true,
+		PollInterval: time.Millisecond,
+		Progress:     func(info BackupInfo) { seen = append(seen, info.Status) },
+	})
+	if err != nil {
+		t.Fatalf("PushBackup returned error: %v", err)
+	}
+
+	upload, purged := recorded()
+	if upload.Type != UploadTypeCluster || upload.Name != "cluster-1" || !purged {
+		t.Fatalf("unexpected requests: upload=%+v purged=%t", upload, purged)
+	}
+	if !result.DeletedLocal || result.AlreadyUploaded || result.SizeBytes != 100 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if len(seen) != 2 || seen[1] != BackupStateCompleted {
+		t.Fatalf("unexpected progress updates: %v", seen)
+	}
+}
+
+func TestPushBackupReportsFailedUpload(t *testing.T) {
+	t.Parallel()
+
+	server, recorded := uploadServer(t, BackupStateFailed)
+	_, err := PushBackup(context.Background(), NewClient(server.URL, ClientOptions{}), "cluster-1", PushOptions{
+		DeleteLocal:  true,
+		PollInterval: time.Millisecond,
+	})
+	if err == nil || err.Error() != "upload of cluster-1 failed: bucket denied" {
+		t.Fatalf("expected upload failure, got %v", err)
+	}
+	if _, purged := recorded(); purged {
+		t.Fatal("local copy must be kept when the upload failed")
+	}
+
+	_, err = PushBackup(context.Background(), NewClient(server.URL, ClientOptions{}), "missing", PushOptions{})
+	if !errors.Is(err, ErrBackupNotFound) {
+		t.Fatalf("expected ErrBackupNotFound, got %v", err)
+	}
+}
+
+func TestWaitForRemoteCopyWaitsForInventoryAndRemoteListing(t *testing.T) {
+	t.Parallel()
+
+	// The backup is not listed at first, then uploads, and reaches the remote listing one poll later
+	var mu sync.Mutex
+	inventoryPolls, remotePolls := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case localListPath:
+			var inventory Inventory
+			switch inventoryPolls {
+			case 0:
+			case 1:
+				inventory.Backups = []BackupInfo{{Name: "20240501-120000", Status: BackupStateUploading}}
+			default:
+				inventory.Backups = []BackupInfo{{Name: "20240501-120000", Status: BackupStateCompleted}}
+			}
+			inventoryPolls++
+			_ = json.NewEncoder(w).Encode(inventory)
+		case remoteListPath:
+			var backups []RemoteBackupInfo
+			if remotePolls > 0 {
+				backups = []RemoteBackupInfo{{Key: "prod/20240501-120000.tar.gz", SizeBytes: 42}}
+			}
+			remotePolls++
+			_ = json.NewEncoder(w).Encode(map[string]any{"backups": backups})
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	var seen []BackupState
+	remote, err := WaitForRemoteCopy(context.Background(), NewClient(server.URL, ClientOptions{}), "20240501-120000", RemoteWaitOptions{
+		PollInterval: time.Millisecond,
+		Progress:     func(info BackupInfo) { seen = append(seen, info.Status) },
+	})
+	if err != nil {
+		t.Fatalf("WaitForRemoteCopy returned error: %v", err)
+	}
+	if remote.Key != "prod/20240501-120000.tar.gz" || remote.SizeBytes != 42 {
+		t.Fatalf("unexpected remote copy: %+v", remote)
+	}
+	if len(seen) != 3 || seen[0] != BackupStateUploading || seen[2] != BackupStateCompleted {
+		t.Fatalf("unexpected progress updates: %v", seen)
+	}
+}
+
+func TestWaitForRemoteCopyHonoursDeadline(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(Inventory{})
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := WaitForRemoteCopy(ctx, NewClient(server.URL, ClientOptions{}), "never-created", RemoteWaitOptions{PollInterval: time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline error, got %v", err)
+	}
+}
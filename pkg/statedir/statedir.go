@@ -0,0 +1,85 @@
+// Package statedir locates the plugin's local state below ~/.kubectl-broker and persists
+// append-only JSON lines files there, shared by the health history, the audit trail and caches.
+package statedir
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"k8s.io/client-go/util/homedir"
+)
+
+// rootName is the directory in the home directory that holds all local state
+const rootName = ".kubectl-broker"
+
+// Dir returns ~/.kubectl-broker/sub, or the value of envVar when it is set. purpose names the
+// state in the error returned when the home directory is unknown.
+func Dir(envVar, sub, purpose string) (string, error) {
+	if dir := os.Getenv(envVar); dir != "" {
+		return dir, nil
+	}
+	home := homedir.HomeDir()
+	if home == "" {
+		return "", fmt.Errorf("cannot determine home directory for %s; set %s", purpose, envVar)
+	}
+	return filepath.Join(home, rootName, sub), nil
+}
+
+// JSONLines is an append-only file with one JSON record per line
+type JSONLines struct {
+	Path     string
+	Label    string      // names the file in errors, e.g. "audit log"
+	DirMode  os.FileMode // permissions of a newly created directory
+	FileMode os.FileMode // permissions of a newly created file
+}
+
+// Append writes record as a new line, creating the directory and file when needed
+func (f JSONLines) Append(record any) error {
+	dir := filepath.Dir(f.Path)
+	if err := os.MkdirAll(dir, f.DirMode); err != nil {
+		return fmt.Errorf("failed to create %s directory %s: %w", f.Label, dir, err)
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s record: %w", f.Label, err)
+	}
+
+	file, err := os.OpenFile(f.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, f.FileMode)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", f.Label, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write %s record: %w", f.Label, err)
+	}
+	return nil
+}
+
+// Scan calls fn with every line of the file in order; a missing file has no lines. The line is
+// only valid during the call.
+func (f JSONLines) Scan(fn func(line []byte)) error {
+	file, err := os.Open(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", f.Label, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		fn(scanner.Bytes())
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", f.Label, err)
+	}
+	return nil
+}
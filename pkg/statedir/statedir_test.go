@@ -0,0 +1,50 @@
+package statedir
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirHonorsEnvOverride(t *testing.T) {
+	t.Setenv("KUBECTL_BROKER_TEST_DIR", "/tmp/override")
+
+	dir, err := Dir("KUBECTL_BROKER_TEST_DIR", "history", "tests")
+	if err != nil {
+		t.Fatalf("Dir returned error: %v", err)
+	}
+	if dir != "/tmp/override" {
+		t.Fatalf("Dir = %q, want the override", dir)
+	}
+}
+
+func TestJSONLinesAppendAndScan(t *testing.T) {
+	t.Parallel()
+
+	file := JSONLines{Path: filepath.Join(t.TempDir(), "state", "records.jsonl"), Label: "test log", DirMode: 0o700, FileMode: 0o600}
+
+	var lines []string
+	if err := file.Scan(func(line []byte) { lines = append(lines, string(line)) }); err != nil || len(lines) != 0 {
+		t.Fatalf("Scan of a missing file = %v, %v; want no lines and no error", lines, err)
+	}
+
+	for _, record := range []map[string]int{{"n": 1}, {"n": 2}} {
+		if err := file.Append(record); err != nil {
+			t.Fatalf("Append returned error: %v", err)
+		}
+	}
+	if err := file.Scan(func(line []byte) { lines = append(lines, string(line)) }); err != nil {
+		t.Fatalf("Scan returned error: %v", err)
+	}
+	if len(lines) != 2 || lines[0] != `{"n":1}` || lines[1] != `{"n":2}` {
+		t.Fatalf("lines = %q, want both records in order", lines)
+	}
+
+	info, err := os.Stat(file.Path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("file mode = %v, want 0600", info.Mode().Perm())
+	}
+}
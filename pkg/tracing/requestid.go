@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// RequestIDHeader carries the correlation ID of an invocation on every HiveMQ management and
+// sidecar API request, so support can find a failed run in the broker-side logs
+const RequestIDHeader = "X-Request-Id"
+
+// correlatedSystems are the remote sides that receive the request ID
+var correlatedSystems = map[string]bool{"hivemq": true, "sidecar": true}
+
+var (
+	requestID     = sync.OnceValue(newRequestID)
+	requestIDSent atomic.Bool
+)
+
+// RequestID returns the correlation ID of this invocation, a random UUID generated on first use
+func RequestID() string {
+	return requestID()
+}
+
+// RequestIDSent reports whether the request ID was attached to at least one API request, so
+// there is something on the broker side to correlate it with
+func RequestIDSent() bool {
+	return requestIDSent.Load()
+}
+
+// setRequestID attaches the request ID to a request to a correlated system unless the caller
+// already set one. req must be a clone owned by the transport.
+func setRequestID(req *http.Request, system string) {
+	if !correlatedSystems[system] || req.Header.Get(RequestIDHeader) != "" {
+		return
+	}
+	req.Header.Set(RequestIDHeader, RequestID())
+	requestIDSent.Store(true)
+}
+
+// newRequestID returns a random version 4 UUID
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:]) // never fails; see crypto/rand.Read
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,104 @@
+// Package tracing wires OpenTelemetry spans into command execution. Spans are exported via
+// OTLP/HTTP when one of the standard OTEL_EXPORTER_OTLP_* endpoint variables is set; otherwise
+// every helper is a cheap no-op.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies spans created by the plugin
+const instrumentationName = "kubectl-broker"
+
+// ParentEnvVar carries a W3C traceparent, e.g. from a CI pipeline, that becomes the parent of
+// the command span
+const ParentEnvVar = "TRACEPARENT"
+
+// Enabled reports whether the environment configures an OTLP trace exporter
+func Enabled() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") || strings.EqualFold(os.Getenv("OTEL_TRACES_EXPORTER"), "none") {
+		return false
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Setup installs an OTLP/HTTP tracer provider when Enabled. The exporter reads its endpoint,
+// headers and TLS settings from the standard OTEL_EXPORTER_OTLP_* variables, and the resource
+// honors OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES. The returned function flushes and
+// stops the provider; it is a no-op when tracing is disabled.
+func Setup(ctx context.Context, serviceName string) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if !Enabled() {
+		return noop, nil
+	}
+
+	if protocol := otlpProtocol(); protocol != "" && protocol != "http/protobuf" {
+		return noop, fmt.Errorf("unsupported OTLP protocol %q\n\nPlease either:\n- Use the HTTP exporter: OTEL_EXPORTER_OTLP_PROTOCOL=http/protobuf\n- Point the endpoint at the collector's OTLP/HTTP port (usually 4318)", protocol)
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return noop, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", serviceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return noop, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return provider.Shutdown, nil
+}
+
+// otlpProtocol returns the configured OTLP protocol for traces
+func otlpProtocol() string {
+	if protocol := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL"); protocol != "" {
+		return protocol
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+}
+
+// ContextWithEnvParent returns ctx carrying the remote span from TRACEPARENT, if set
+func ContextWithEnvParent(ctx context.Context) context.Context {
+	parent := os.Getenv(ParentEnvVar)
+	if parent == "" {
+		return ctx
+	}
+	return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{"traceparent": parent})
+}
+
+// Start begins a span as child of the span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on the span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWrapTransportRecordsSpanAndPropagates(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: WrapTransport(nil, "hivemq")}
+	resp, err := client.Get(server.URL + "/api/v1/management/backups?cursor=secret")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if traceparent == "" {
+		t.Fatal("expected traceparent header to be propagated")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "hivemq GET" {
+		t.Errorf("unexpected span name %q", span.Name())
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("expected error status for 503, got %v", span.Status().Code)
+	}
+	for _, attr := range span.Attributes() {
+		switch attr.Key {
+		case "url.path":
+			if attr.Value.AsString() != "/api/v1/management/backups" {
+				t.Errorf("unexpected url.path %q", attr.Value.AsString())
+			}
+		case "http.response.status_code":
+			if attr.Value.AsInt64() != http.StatusServiceUnavailable {
+				t.Errorf("unexpected status code attribute %d", attr.Value.AsInt64())
+			}
+		}
+	}
+}
+
+func TestWrapTransportSetsRequestID(t *testing.T) {
+	headers := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers[r.URL.Path] = r.Header.Get(RequestIDHeader)
+	}))
+	defer server.Close()
+
+	for _, system := range []string{"kubernetes", "hivemq", "sidecar"} {
+		client := &http.Client{Transport: WrapTransport(nil, system)}
+		resp, err := client.Get(server.URL + "/" + system)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if headers["/kubernetes"] != "" {
+		t.Errorf("Kubernetes request carries request ID %q", headers["/kubernetes"])
+	}
+	if headers["/hivemq"] == "" || headers["/hivemq"] != headers["/sidecar"] || headers["/hivemq"] != RequestID() {
+		t.Errorf("request IDs = %v, want %s on hivemq and sidecar", headers, RequestID())
+	}
+	if !RequestIDSent() {
+		t.Error("RequestIDSent() = false after API requests")
+	}
+}
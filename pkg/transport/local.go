@@ -0,0 +1,55 @@
+package transport
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocalAddress binds forwarded ports on every loopback address localhost resolves to
+const DefaultLocalAddress = "localhost"
+
+// localAddress is the address forwarded ports are bound to and dialed on. Binding and dialing
+// must agree, otherwise clients connect to ::1 while the forward listens on 127.0.0.1 or vice versa.
+var localAddress = DefaultLocalAddress
+
+// SetLocalAddress selects the loopback address for port-forwards: "localhost" or a loopback IP
+// such as 127.0.0.1 or ::1. It is meant to be called once during startup.
+func SetLocalAddress(address string) error {
+	parsed, err := parseLocalAddress(address)
+	if err != nil {
+		return err
+	}
+	localAddress = parsed
+	return nil
+}
+
+// parseLocalAddress normalizes a local address and rejects anything but loopback, so forwarded
+// management APIs are never exposed to the network
+func parseLocalAddress(address string) (string, error) {
+	address = strings.Trim(strings.TrimSpace(address), "[]")
+	if address == "" || address == DefaultLocalAddress {
+		return DefaultLocalAddress, nil
+	}
+	ip := net.ParseIP(address)
+	if ip == nil || !ip.IsLoopback() {
+		return "", fmt.Errorf("local address %q is not a loopback address", address)
+	}
+	return ip.String(), nil
+}
+
+// LocalAddress returns the address forwarded ports are bound to
+func LocalAddress() string {
+	return localAddress
+}
+
+// LocalHostPort joins the local address with port, bracketing IPv6 addresses
+func LocalHostPort(port int) string {
+	return net.JoinHostPort(localAddress, strconv.Itoa(port))
+}
+
+// LocalURL returns the base URL of a forwarded local port for the given scheme
+func LocalURL(scheme string, port int) string {
+	return fmt.Sprintf("%s://%s", scheme, LocalHostPort(port))
+}
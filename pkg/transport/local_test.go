@@ -0,0 +1,28 @@
+package transport
+
+import "testing"
+
+func TestParseLocalAddress(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "", want: "localhost"},
+		{in: "localhost", want: "localhost"},
+		{in: "127.0.0.1", want: "127.0.0.1"},
+		{in: "::1", want: "::1"},
+		{in: "[::1]", want: "::1"},
+		{in: "0.0.0.0", wantErr: true},
+		{in: "10.0.0.5", wantErr: true},
+		{in: "example.com", wantErr: true},
+	}
+	for _, tc := range cases {
+		got, err := parseLocalAddress(tc.in)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Fatalf("parseLocalAddress(%q) = %q, %v; want %q, error %v", tc.in, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"kubectl-broker/pkg/logging"
+	"kubectl-broker/pkg/tracing"
+)
+
+// TLSOptions configure TLS for HTTP calls made over forwarded ports
+type TLSOptions struct {
+	Enabled            bool   // use https instead of http
+	InsecureSkipVerify bool   // skip server certificate verification
+	CACertFile         string // PEM bundle used to verify the server certificate
+	ClientCertFile     string // PEM client certificate for mutual TLS (with ClientKeyFile)
+	ClientKeyFile      string // PEM private key of ClientCertFile
+	ServerName         string // name the server certificate is verified against instead of localhost
+}
+
+// IsEnabled reports whether TLS should be negotiated. Setting a CA bundle, a client
+// certificate or skipping verification implies TLS even without the explicit toggle.
+func (o TLSOptions) IsEnabled() bool {
+	return o.Enabled || o.InsecureSkipVerify || o.CACertFile != "" || o.ClientCertFile != ""
+}
+
+// Scheme returns the URL scheme matching the TLS settings
+func (o TLSOptions) Scheme() string {
+	if o.IsEnabled() {
+		return "https"
+	}
+	return "http"
+}
+
+// BaseURL returns the base URL for a port-forwarded local port
+func (o TLSOptions) BaseURL(localPort int) string {
+	return LocalURL(o.Scheme(), localPort)
+}
+
+// Config builds a tls.Config for the options, or nil when TLS is disabled
+func (o TLSOptions) Config() (*tls.Config, error) {
+	if !o.IsEnabled() {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: o.InsecureSkipVerify,
+		ServerName:         o.ServerName,
+	}
+
+	if o.CACertFile != "" {
+		pem, err := os.ReadFile(o.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate %s: %w", o.CACertFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid PEM certificates found in %s", o.CACertFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if (o.ClientCertFile == "") != (o.ClientKeyFile == "") {
+		return nil, fmt.Errorf("a client certificate needs both a certificate and a key file")
+	}
+	if o.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.ClientCertFile, o.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate %s: %w", o.ClientCertFile, err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// NewHTTPClient creates an HTTP client with the given timeout and TLS settings
+func NewHTTPClient(timeout time.Duration, opts TLSOptions) (*http.Client, error) {
+	tlsConfig, err := opts.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	var base http.RoundTripper = http.DefaultTransport
+	if tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		base = transport
+	}
+
+	return &http.Client{Timeout: timeout, Transport: tracing.WrapTransport(logging.WrapTransport(base, "hivemq"), "hivemq")}, nil
+}
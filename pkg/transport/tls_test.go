@@ -0,0 +1,114 @@
+package transport
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSchemeFollowsTLSSettings(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		opts TLSOptions
+		want string
+	}{
+		{TLSOptions{}, "http://localhost:8081"},
+		{TLSOptions{Enabled: true}, "https://localhost:8081"},
+		{TLSOptions{InsecureSkipVerify: true}, "https://localhost:8081"},
+		{TLSOptions{CACertFile: "ca.pem"}, "https://localhost:8081"},
+	}
+	for _, tc := range cases {
+		if got := tc.opts.BaseURL(8081); got != tc.want {
+			t.Fatalf("BaseURL(%+v) = %q, want %q", tc.opts, got, tc.want)
+		}
+	}
+}
+
+func TestNewHTTPClientTrustsCustomCA(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certPEM, 0o600); err != nil {
+		t.Fatalf("write CA file: %v", err)
+	}
+
+	client, err := NewHTTPClient(5*time.Second, TLSOptions{CACertFile: caFile})
+	if err != nil {
+		t.Fatalf("NewHTTPClient returned error: %v", err)
+	}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request with custom CA failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	untrusted, err := NewHTTPClient(5*time.Second, TLSOptions{Enabled: true})
+	if err != nil {
+		t.Fatalf("NewHTTPClient returned error: %v", err)
+	}
+	if _, err := untrusted.Get(server.URL); err == nil {
+		t.Fatal("expected certificate verification failure without CA")
+	}
+}
+
+func TestServerNameVerifiesCertificateBehindLocalhost(t *testing.T) {
+	t.Parallel()
+
+	// The test certificate is issued for example.com and 127.0.0.1, not localhost, like a broker
+	// certificate issued for its service name and reached through a port-forward
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	localURL := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certPEM, 0o600); err != nil {
+		t.Fatalf("write CA file: %v", err)
+	}
+
+	withoutName, err := NewHTTPClient(5*time.Second, TLSOptions{CACertFile: caFile})
+	if err != nil {
+		t.Fatalf("NewHTTPClient returned error: %v", err)
+	}
+	if _, err := withoutName.Get(localURL); err == nil {
+		t.Fatal("expected hostname verification to fail for localhost")
+	}
+
+	withName, err := NewHTTPClient(5*time.Second, TLSOptions{CACertFile: caFile, ServerName: "example.com"})
+	if err != nil {
+		t.Fatalf("NewHTTPClient returned error: %v", err)
+	}
+	resp, err := withName.Get(localURL)
+	if err != nil {
+		t.Fatalf("request with server name failed: %v", err)
+	}
+	_ = resp.Body.Close()
+}
+
+func TestConfigRejectsInvalidCABundle(t *testing.T) {
+	t.Parallel()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("write CA file: %v", err)
+	}
+
+	_, err := TLSOptions{CACertFile: caFile}.Config()
+	if err == nil || !strings.Contains(err.Error(), "no valid PEM certificates") {
+		t.Fatalf("expected invalid PEM error, got: %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+// Package units parses the durations, sizes and percentages accepted by command-line flags.
+// Decimal numbers may use a dot or a comma, units are case-insensitive where that is
+// unambiguous, and errors list the accepted forms. Numbers such as 1,000 or 1.000 are rejected
+// because the separator may group thousands as well as start the fraction.
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Accepted forms, shown in parse errors
+const (
+	DurationForms = "a number with a unit (s, m, h, d, w), optionally compound, e.g. 90m, 36h, 7d or 1w2d"
+	SizeForms     = "a number with an optional unit, binary (Ki, Mi, Gi, Ti, Pi, also KiB) or decimal (K, M, G, T, P, also KB), e.g. 500Mi, 1Gi or 1.5GB"
+	PercentForms  = "a number between 0 and 100 with an optional %, e.g. 80 or 80%"
+)
+
+// errAmbiguousNumber is returned for numbers whose separator may group thousands
+var errAmbiguousNumber = errors.New("ambiguous separator")
+
+var durationUnits = map[string]time.Duration{
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+}
+
+// ParseDuration parses durations such as 36h, 7d, 1w2d or 1d12h30m. Components may be
+// fractional (1.5d or 1,5d) and separated by spaces; a bare 0 is accepted.
+func ParseDuration(value string) (time.Duration, error) {
+	input := strings.ToLower(strings.TrimSpace(value))
+	if input == "0" {
+		return 0, nil
+	}
+	if input == "" {
+		return 0, fmt.Errorf("empty duration: use %s", DurationForms)
+	}
+
+	var total float64
+	rest := input
+	for rest != "" {
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		number, afterNumber := splitNumber(rest)
+		unit, afterUnit := splitUnit(afterNumber)
+		scale, ok := durationUnits[unit]
+		if number == "" || !ok {
+			return 0, fmt.Errorf("invalid duration %q: use %s", value, DurationForms)
+		}
+		n, err := parseDecimal(number)
+		if err != nil {
+			return 0, numberError("duration", value, DurationForms, err)
+		}
+		total += n * float64(scale)
+		rest = afterUnit
+	}
+
+	if total > math.MaxInt64 {
+		return 0, fmt.Errorf("duration %q is too long", value)
+	}
+	return time.Duration(total), nil
+}
+
+var sizeUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"m":   1e6,
+	"mb":  1e6,
+	"g":   1e9,
+	"gb":  1e9,
+	"t":   1e12,
+	"tb":  1e12,
+	"p":   1e15,
+	"pb":  1e15,
+	"ki":  1 << 10,
+	"kib": 1 << 10,
+	"mi":  1 << 20,
+	"mib": 1 << 20,
+	"gi":  1 << 30,
+	"gib": 1 << 30,
+	"ti":  1 << 40,
+	"tib": 1 << 40,
+	"pi":  1 << 50,
+	"pib": 1 << 50,
+}
+
+// ParseSize parses byte sizes such as 500Mi, 1Gi, 1.5GB or "2 GiB". Kubernetes quantity
+// suffixes are accepted; since a size has no fraction of a byte, m means mega rather than milli.
+func ParseSize(value string) (resource.Quantity, error) {
+	input := strings.TrimSpace(value)
+	number, rest := splitNumber(input)
+	unit := strings.ToLower(strings.TrimSpace(rest))
+	scale, ok := sizeUnits[unit]
+	if number == "" || !ok {
+		return resource.Quantity{}, fmt.Errorf("invalid size %q: use %s", value, SizeForms)
+	}
+	n, err := parseDecimal(number)
+	if err != nil {
+		return resource.Quantity{}, numberError("size", value, SizeForms, err)
+	}
+
+	bytes := n * float64(scale)
+	if bytes > math.MaxInt64 {
+		return resource.Quantity{}, fmt.Errorf("size %q is too large", value)
+	}
+	format := resource.DecimalSI
+	if strings.Contains(unit, "i") {
+		format = resource.BinarySI
+	}
+	return *resource.NewQuantity(int64(math.Round(bytes)), format), nil
+}
+
+// ParsePercent parses a percentage threshold such as 80, 80% or 92,5 %
+func ParsePercent(value string) (float64, error) {
+	input := strings.TrimSpace(value)
+	input = strings.TrimSpace(strings.TrimSuffix(input, "%"))
+	percent, err := parseDecimal(input)
+	if err != nil {
+		return 0, numberError("percentage", value, PercentForms, err)
+	}
+	if percent > 100 {
+		return 0, fmt.Errorf("invalid percentage %q: use %s", value, PercentForms)
+	}
+	return percent, nil
+}
+
+// Percent is a percentage flag value that accepts the forms of ParsePercent
+type Percent float64
+
+// Set implements pflag.Value
+func (p *Percent) Set(value string) error {
+	percent, err := ParsePercent(value)
+	if err != nil {
+		return err
+	}
+	*p = Percent(percent)
+	return nil
+}
+
+// String implements pflag.Value
+func (p *Percent) String() string {
+	return strconv.FormatFloat(float64(*p), 'f', -1, 64)
+}
+
+// Type implements pflag.Value
+func (p *Percent) Type() string {
+	return "percent"
+}
+
+// splitNumber splits a leading unsigned decimal number with a dot or comma separator
+func splitNumber(value string) (string, string) {
+	end := 0
+	for end < len(value) && (value[end] >= '0' && value[end] <= '9' || value[end] == '.' || value[end] == ',') {
+		end++
+	}
+	return value[:end], value[end:]
+}
+
+// splitUnit splits a leading run of letters
+func splitUnit(value string) (string, string) {
+	end := 0
+	for end < len(value) && value[end] >= 'a' && value[end] <= 'z' {
+		end++
+	}
+	return value[:end], value[end:]
+}
+
+// parseDecimal parses an unsigned decimal number that uses either a dot or a comma. A single
+// separator followed by exactly three digits, as in 1,000 or 1.000, may group thousands and is
+// rejected with errAmbiguousNumber; a leading 0 such as 0.125 cannot and is accepted.
+func parseDecimal(value string) (float64, error) {
+	if strings.Count(value, ".")+strings.Count(value, ",") > 1 {
+		return 0, fmt.Errorf("invalid number %q", value)
+	}
+	if i := strings.IndexAny(value, ".,"); i > 0 && len(value)-i-1 == 3 && value[0] != '0' {
+		return 0, fmt.Errorf("number %q: %w", value, errAmbiguousNumber)
+	}
+	n, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", "."), 64)
+	if err != nil || n < 0 || math.IsInf(n, 0) || math.IsNaN(n) {
+		return 0, fmt.Errorf("invalid number %q", value)
+	}
+	return n, nil
+}
+
+// numberError reports an unparsable number of a flag value along with the accepted forms
+func numberError(kind, value, forms string, err error) error {
+	if errors.Is(err, errAmbiguousNumber) {
+		return fmt.Errorf("ambiguous %s %q: the separator may group thousands or start the fraction; write 1000 or 1.5 instead and use %s", kind, value, forms)
+	}
+	return fmt.Errorf("invalid %s %q: use %s", kind, value, forms)
+}
@@ -0,0 +1,99 @@
+package units
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	t.Parallel()
+
+	day := 24 * time.Hour
+	cases := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"36h", 36 * time.Hour},
+		{"7d", 7 * day},
+		{"1w2d", 9 * day},
+		{"1d12h30m", day + 12*time.Hour + 30*time.Minute},
+		{"1w 2d", 9 * day},
+		{"1.5d", 36 * time.Hour},
+		{"1,5d", 36 * time.Hour},
+		{"90M", 90 * time.Minute},
+		{"500ms", 500 * time.Millisecond},
+		{"0", 0},
+	}
+	for _, tc := range cases {
+		got, err := ParseDuration(tc.value)
+		if err != nil || got != tc.want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", tc.value, got, err, tc.want)
+		}
+	}
+
+	for _, value := range []string{"", "7", "7days", "-1d", "1d-", "1.2.3h", "d"} {
+		_, err := ParseDuration(value)
+		if err == nil {
+			t.Errorf("ParseDuration(%q) succeeded, want an error", value)
+			continue
+		}
+		if value != "" && !strings.Contains(err.Error(), DurationForms) {
+			t.Errorf("ParseDuration(%q) error %q does not list the accepted forms", value, err)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		value string
+		want  int64
+	}{
+		{"1Gi", 1 << 30},
+		{"500Mi", 500 << 20},
+		{"2 GiB", 2 << 30},
+		{"1.5GB", 1_500_000_000},
+		{"1,5G", 1_500_000_000},
+		{"0.125Gi", 1 << 27},
+		{"100mb", 100_000_000},
+		{"100m", 100_000_000},
+		{"4096", 4096},
+		{"10B", 10},
+	}
+	for _, tc := range cases {
+		got, err := ParseSize(tc.value)
+		if err != nil || got.Value() != tc.want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", tc.value, got.Value(), err, tc.want)
+		}
+	}
+
+	for _, value := range []string{"", "Gi", "1Gx", "-1Gi", "1e9", "1,000.5Gi", "1,000Gi", "1.000Gi"} {
+		if _, err := ParseSize(value); err == nil || !strings.Contains(err.Error(), SizeForms) {
+			t.Errorf("ParseSize(%q) error = %v, want one listing the accepted forms", value, err)
+		}
+	}
+}
+
+func TestPercentFlag(t *testing.T) {
+	t.Parallel()
+
+	for value, want := range map[string]float64{"80": 80, "80%": 80, "92,5 %": 92.5, "0": 0, "100%": 100} {
+		var p Percent
+		if err := p.Set(value); err != nil || float64(p) != want {
+			t.Errorf("Set(%q) = %v, %v; want %v", value, p, err, want)
+		}
+	}
+	for _, value := range []string{"", "101", "-5", "80%%", "high"} {
+		var p Percent
+		if err := p.Set(value); err == nil {
+			t.Errorf("Set(%q) succeeded, want an error", value)
+		}
+	}
+
+	p := Percent(92.5)
+	if p.String() != "92.5" {
+		t.Errorf("String() = %q, want 92.5", p.String())
+	}
+}
@@ -0,0 +1,230 @@
+// Package update finds published plugin releases on GitHub, verifies downloaded binaries against
+// the release checksums and replaces the running executable.
+package update
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"kubectl-broker/pkg/transport"
+)
+
+// Release source defaults
+const (
+	DefaultRepository = "schmitz-chris/kubectl-broker-extension"
+	DefaultAPIURL     = "https://api.github.com"
+
+	// ChecksumsAsset lists the SHA-256 of every release asset in sha256sum format
+	ChecksumsAsset = "checksums.txt"
+)
+
+// ErrNoRelease is returned when the repository has no matching published release
+var ErrNoRelease = errors.New("release not found")
+
+// Release is a published GitHub release
+type Release struct {
+	TagName     string    `json:"tag_name"`
+	HTMLURL     string    `json:"html_url"`
+	PublishedAt time.Time `json:"published_at"`
+	Assets      []Asset   `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name        string `json:"name"`
+	DownloadURL string `json:"browser_download_url"`
+	Size        int64  `json:"size"`
+}
+
+// Asset returns the asset called name
+func (r *Release) Asset(name string) (*Asset, bool) {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i], true
+		}
+	}
+	return nil, false
+}
+
+// AssetName is the name of the raw plugin binary for a platform, as built by 'make cross-compile'
+func AssetName(goos, goarch string) string {
+	name := fmt.Sprintf("kubectl-broker-%s-%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Client reads releases of one GitHub repository
+type Client struct {
+	baseURL    string
+	repository string
+	http       *http.Client
+}
+
+// NewClient creates a client for repository ("owner/name") against the GitHub API at baseURL
+func NewClient(baseURL, repository string, timeout time.Duration) (*Client, error) {
+	httpClient, err := transport.NewHTTPClient(timeout, transport.TLSOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), repository: repository, http: httpClient}, nil
+}
+
+// LatestRelease returns the newest published release that is not a pre-release
+func (c *Client) LatestRelease(ctx context.Context) (*Release, error) {
+	return c.release(ctx, "latest")
+}
+
+// ReleaseByTag returns the release tagged tag
+func (c *Client) ReleaseByTag(ctx context.Context, tag string) (*Release, error) {
+	return c.release(ctx, "tags/"+tag)
+}
+
+func (c *Client) release(ctx context.Context, selector string) (*Release, error) {
+	resp, err := c.get(ctx, fmt.Sprintf("%s/repos/%s/releases/%s", c.baseURL, c.repository, selector), "application/vnd.github+json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+	return &release, nil
+}
+
+// Checksums downloads and parses the release's checksums file
+func (c *Client) Checksums(ctx context.Context, release *Release) (map[string]string, error) {
+	asset, ok := release.Asset(ChecksumsAsset)
+	if !ok {
+		return nil, fmt.Errorf("release %s publishes no %s, so downloads cannot be verified", release.TagName, ChecksumsAsset)
+	}
+	resp, err := c.get(ctx, asset.DownloadURL, "application/octet-stream")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return ParseChecksums(io.LimitReader(resp.Body, 1<<20))
+}
+
+// Download streams asset to w and fails unless its SHA-256 equals wantSHA256
+func (c *Client) Download(ctx context.Context, asset *Asset, wantSHA256 string, w io.Writer) error {
+	resp, err := c.get(ctx, asset.DownloadURL, "application/octet-stream")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(w, hash), resp.Body); err != nil {
+		return fmt.Errorf("failed to download %s: %w", asset.Name, err)
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(got, wantSHA256) {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", asset.Name, wantSHA256, got)
+	}
+	return nil
+}
+
+func (c *Client) get(ctx context.Context, url, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to %s failed: %w", url, err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNoRelease
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("request to %s failed with status %d: %s", url, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// ParseChecksums reads sha256sum output ("<hex>  <name>" per line; "*" marks binary mode)
+func ParseChecksums(r io.Reader) (map[string]string, error) {
+	checksums := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if _, err := hex.DecodeString(fields[0]); err != nil || len(fields[0]) != sha256.Size*2 {
+			continue
+		}
+		checksums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read checksums: %w", err)
+	}
+	return checksums, nil
+}
+
+// Newer reports whether version latest is newer than current. Both may carry a "v" prefix;
+// a pre-release (v1.2.0-rc.1) is older than its release. Unparsable versions are never newer.
+func Newer(current, latest string) bool {
+	c, okCurrent := parseVersion(current)
+	l, okLatest := parseVersion(latest)
+	if !okCurrent || !okLatest {
+		return false
+	}
+	for i := range 3 {
+		if l.parts[i] != c.parts[i] {
+			return l.parts[i] > c.parts[i]
+		}
+	}
+	return c.preRelease != "" && (l.preRelease == "" || l.preRelease > c.preRelease)
+}
+
+// gitDescribeSuffix matches the commits-since-tag suffix of 'git describe --tags --dirty'
+var gitDescribeSuffix = regexp.MustCompile(`^\d+-g[0-9a-f]+(-dirty)?$|^dirty$`)
+
+type parsedVersion struct {
+	parts      [3]int
+	preRelease string
+}
+
+func parseVersion(value string) (parsedVersion, bool) {
+	var v parsedVersion
+	value = strings.TrimPrefix(strings.TrimSpace(value), "v")
+	value, _, _ = strings.Cut(value, "+")
+	value, v.preRelease, _ = strings.Cut(value, "-")
+	if gitDescribeSuffix.MatchString(v.preRelease) {
+		// Builds after a tag (git describe: v1.3.0-4-gabc1234) are not pre-releases of it
+		v.preRelease = ""
+	}
+
+	parts := strings.Split(value, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return v, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v.parts[i] = n
+	}
+	return v, true
+}
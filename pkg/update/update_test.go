@@ -0,0 +1,126 @@
+package update
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"v1.2.0", "v1.3.0", true},
+		{"1.2.9", "v1.10.0", true},
+		{"v1.3.0", "v1.3.0", false},
+		{"v1.3.1", "v1.3.0", false},
+		{"v1.3.0-rc.1", "v1.3.0", true},
+		{"v1.3.0", "v1.3.0-rc.1", false},
+		{"v1.3.0-rc.1", "v1.3.0-rc.2", true},
+		{"dev", "v1.3.0", false},
+		{"v1.3.0-4-gabc1234-dirty", "v1.3.1", true},
+		{"v1.3.0-4-gabc1234", "v1.3.0", false},
+	}
+	for _, tt := range tests {
+		if got := Newer(tt.current, tt.latest); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
+
+func TestClientDownloadVerifiesChecksum(t *testing.T) {
+	t.Parallel()
+
+	binary := []byte("#!/bin/sh\necho new\n")
+	sum := sha256.Sum256(binary)
+	asset := AssetName("linux", "amd64")
+
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	mux.HandleFunc("/repos/owner/plugin/releases/latest", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(Release{TagName: "v1.4.0", Assets: []Asset{
+			{Name: asset, DownloadURL: server.URL + "/download/" + asset},
+			{Name: ChecksumsAsset, DownloadURL: server.URL + "/download/" + ChecksumsAsset},
+		}})
+	})
+	mux.HandleFunc("/download/"+asset, func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write(binary) })
+	mux.HandleFunc("/download/"+ChecksumsAsset, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, hex.EncodeToString(sum[:])+"  "+asset+"\nnot a checksum line\n")
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "owner/plugin", 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	release, err := client.LatestRelease(ctx)
+	if err != nil || release.TagName != "v1.4.0" {
+		t.Fatalf("LatestRelease() = %+v, %v", release, err)
+	}
+	checksums, err := client.Checksums(ctx, release)
+	if err != nil || len(checksums) != 1 {
+		t.Fatalf("Checksums() = %v, %v", checksums, err)
+	}
+	binaryAsset, _ := release.Asset(asset)
+
+	var got bytes.Buffer
+	if err := client.Download(ctx, binaryAsset, checksums[asset], &got); err != nil || !bytes.Equal(got.Bytes(), binary) {
+		t.Fatalf("Download() = %q, %v", got.String(), err)
+	}
+	if err := client.Download(ctx, binaryAsset, strings.Repeat("0", 64), io.Discard); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+	if _, err := client.ReleaseByTag(ctx, "v9.9.9"); !errors.Is(err, ErrNoRelease) {
+		t.Fatalf("expected ErrNoRelease, got %v", err)
+	}
+}
+
+func TestReplaceExecutableKeepsOriginalOnFailure(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "kubectl-broker")
+	if err := os.WriteFile(path, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := ReplaceExecutable(path, func(w io.Writer) error {
+		_, _ = io.WriteString(w, "partial")
+		return errors.New("connection reset")
+	})
+	if err == nil {
+		t.Fatal("expected the write error")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "old" {
+		t.Fatalf("original executable changed to %q", data)
+	}
+
+	if err := ReplaceExecutable(path, func(w io.Writer) error {
+		_, err := io.WriteString(w, "new")
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "new" {
+		t.Fatalf("executable not replaced, contains %q", data)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Fatalf("temporary files left behind: %v", entries)
+	}
+}
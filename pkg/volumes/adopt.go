@@ -0,0 +1,248 @@
+package volumes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"kubectl-broker/pkg"
+)
+
+// DefaultAdoptBindTimeout is how long AdoptVolume waits for the new claim to bind
+const DefaultAdoptBindTimeout = 2 * time.Minute
+
+// adoptPollInterval is the delay between binding checks
+const adoptPollInterval = 2 * time.Second
+
+// AdoptOptions contains options for rebinding a released PV to a new PVC
+type AdoptOptions struct {
+	PVName      string        // Released persistent volume holding the data
+	Namespace   string        // Namespace of the new claim
+	PVCName     string        // Name of the new claim, e.g. data-broker-0
+	DryRun      bool          // Validate and show the plan without changing anything
+	BindTimeout time.Duration // How long to wait for the claim to bind
+}
+
+// AdoptResult describes the outcome of a volume adoption
+type AdoptResult struct {
+	PVName        string
+	Namespace     string
+	PVCName       string
+	PreviousClaim string // namespace/name of the claim the PV was released from
+	Size          resource.Quantity
+	StorageClass  string
+	AccessModes   []v1.PersistentVolumeAccessMode
+	DryRun        bool
+	Bound         bool
+}
+
+// Adopter rebinds released persistent volumes to new claims, the recovery counterpart of Cleaner
+type Adopter struct {
+	k8sClient *pkg.K8sClient
+}
+
+// NewAdopter creates a new volume adopter
+func NewAdopter(k8sClient *pkg.K8sClient) *Adopter {
+	return &Adopter{k8sClient: k8sClient}
+}
+
+// AdoptVolume points the PV's claimRef at the new claim, creates a PVC with a matching spec
+// bound to the PV by name, and waits until Kubernetes reports the claim as Bound. If the claim
+// cannot be created, the PV's previous claimRef is restored.
+func (a *Adopter) AdoptVolume(ctx context.Context, options AdoptOptions) (*AdoptResult, error) {
+	core := a.k8sClient.GetCoreClient()
+
+	pv, err := core.PersistentVolumes().Get(ctx, options.PVName, metav1.GetOptions{})
+	if err != nil {
+		return nil, pkg.NewKubernetesError("get_persistent_volume", options.PVName, err)
+	}
+	if err := ValidateAdoptable(pv, options.Namespace, options.PVCName); err != nil {
+		return nil, err
+	}
+
+	_, err = core.PersistentVolumeClaims(options.Namespace).Get(ctx, options.PVCName, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		return nil, fmt.Errorf("persistent volume claim %s/%s already exists\n\nPlease either:\n- Delete or rename the existing claim first\n- Choose another name: --pvc-name <name>", options.Namespace, options.PVCName)
+	case !apierrors.IsNotFound(err):
+		return nil, pkg.NewKubernetesError("get_persistent_volume_claim", options.PVCName, err)
+	}
+
+	claim := BuildAdoptionClaim(pv, options.Namespace, options.PVCName)
+	result := &AdoptResult{
+		PVName:       pv.Name,
+		Namespace:    options.Namespace,
+		PVCName:      options.PVCName,
+		Size:         claim.Spec.Resources.Requests[v1.ResourceStorage],
+		StorageClass: pv.Spec.StorageClassName,
+		AccessModes:  pv.Spec.AccessModes,
+		DryRun:       options.DryRun,
+	}
+	if ref := pv.Spec.ClaimRef; ref != nil {
+		result.PreviousClaim = ref.Namespace + "/" + ref.Name
+	}
+	if options.DryRun {
+		return result, nil
+	}
+
+	if err := pkg.GuardMutation(ctx, "adopt", pv.Name); err != nil {
+		return nil, err
+	}
+
+	// Pre-bind the PV to the new claim; dropping uid and resourceVersion releases the old claim.
+	// The PV's resourceVersion makes the patch fail if the volume was rebound since the checks.
+	patch, err := claimRefPatch(pv.ResourceVersion, &v1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "PersistentVolumeClaim",
+		Namespace:  options.Namespace,
+		Name:       options.PVCName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	patched, err := core.PersistentVolumes().Patch(ctx, pv.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return nil, pkg.NewKubernetesError("patch_persistent_volume", pv.Name, err)
+	}
+
+	if _, err := core.PersistentVolumeClaims(options.Namespace).Create(ctx, claim, metav1.CreateOptions{}); err != nil {
+		createErr := pkg.NewKubernetesError("create_persistent_volume_claim", options.PVCName, err)
+		if restoreErr := a.restoreClaimRef(ctx, patched, pv.Spec.ClaimRef); restoreErr != nil {
+			return nil, fmt.Errorf("%w\n\npersistent volume %s stays pre-bound to the missing claim %s/%s because restoring its claimRef failed: %v\n\nPlease either:\n- Retry the adoption once the claim can be created\n- Release the volume: kubectl patch pv %s --type json -p '[{\"op\":\"remove\",\"path\":\"/spec/claimRef\"}]'", createErr, pv.Name, options.Namespace, options.PVCName, restoreErr, pv.Name)
+		}
+		return nil, fmt.Errorf("%w; the claimRef of persistent volume %s was restored", createErr, pv.Name)
+	}
+
+	bound, err := a.waitForBinding(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	result.Bound = bound
+	return result, nil
+}
+
+// restoreClaimRef points the PV back at the claim it referenced before the adoption. It runs on
+// a context without cancellation, so an interrupted adoption still restores the volume.
+func (a *Adopter) restoreClaimRef(ctx context.Context, pv *v1.PersistentVolume, previous *v1.ObjectReference) error {
+	patch, err := claimRefPatch(pv.ResourceVersion, previous)
+	if err != nil {
+		return err
+	}
+	_, err = a.k8sClient.GetCoreClient().PersistentVolumes().Patch(context.WithoutCancel(ctx), pv.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// claimRefPatch builds a merge patch that sets the PV's claimRef to ref, or removes it when ref is
+// nil. Empty fields are sent as null so no field of the current claimRef survives. The
+// resourceVersion is a precondition: the API server rejects the patch if the PV changed.
+func claimRefPatch(resourceVersion string, ref *v1.ObjectReference) ([]byte, error) {
+	var claimRef any
+	if ref != nil {
+		fields := map[string]any{}
+		for key, value := range map[string]string{
+			"apiVersion":      ref.APIVersion,
+			"kind":            ref.Kind,
+			"namespace":       ref.Namespace,
+			"name":            ref.Name,
+			"uid":             string(ref.UID),
+			"resourceVersion": ref.ResourceVersion,
+			"fieldPath":       ref.FieldPath,
+		} {
+			if value == "" {
+				fields[key] = nil
+			} else {
+				fields[key] = value
+			}
+		}
+		claimRef = fields
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{"resourceVersion": resourceVersion},
+		"spec":     map[string]any{"claimRef": claimRef},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build claimRef patch: %w", err)
+	}
+	return patch, nil
+}
+
+// ValidateAdoptable checks that a PV holds data that can be claimed by a new PVC
+func ValidateAdoptable(pv *v1.PersistentVolume, namespace, pvcName string) error {
+	switch pv.Status.Phase {
+	case v1.VolumeReleased, v1.VolumeAvailable:
+	case v1.VolumeBound:
+		if ref := pv.Spec.ClaimRef; ref != nil && ref.Namespace == namespace && ref.Name == pvcName {
+			return fmt.Errorf("persistent volume %s is already bound to %s/%s", pv.Name, namespace, pvcName)
+		}
+		return fmt.Errorf("persistent volume %s is bound to a live claim and cannot be adopted", pv.Name)
+	default:
+		return fmt.Errorf("persistent volume %s is %s; only Released or Available volumes can be adopted", pv.Name, pv.Status.Phase)
+	}
+
+	if pv.Spec.PersistentVolumeReclaimPolicy == v1.PersistentVolumeReclaimDelete {
+		return fmt.Errorf("persistent volume %s has reclaim policy Delete and may be removed while it is being adopted\n\nPlease either:\n- Retain it first: kubectl patch pv %s -p '{\"spec\":{\"persistentVolumeReclaimPolicy\":\"Retain\"}}'\n- Restore the data from a backup instead", pv.Name, pv.Name)
+	}
+
+	if _, ok := pv.Spec.Capacity[v1.ResourceStorage]; !ok {
+		return fmt.Errorf("persistent volume %s has no storage capacity", pv.Name)
+	}
+	return nil
+}
+
+// BuildAdoptionClaim returns a PVC whose spec matches the PV and which binds to it by name
+func BuildAdoptionClaim(pv *v1.PersistentVolume, namespace, name string) *v1.PersistentVolumeClaim {
+	storageClass := pv.Spec.StorageClassName
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: pv.Spec.AccessModes,
+			Resources: v1.VolumeResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: pv.Spec.Capacity[v1.ResourceStorage]},
+			},
+			// An empty class must stay an explicit "" so the default class is not applied
+			StorageClassName: &storageClass,
+			VolumeMode:       pv.Spec.VolumeMode,
+			VolumeName:       pv.Name,
+		},
+	}
+}
+
+// waitForBinding polls the new claim until it is Bound or the bind timeout expires
+func (a *Adopter) waitForBinding(ctx context.Context, options AdoptOptions) (bool, error) {
+	timeout := options.BindTimeout
+	if timeout <= 0 {
+		timeout = DefaultAdoptBindTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(adoptPollInterval)
+	defer ticker.Stop()
+
+	for {
+		claim, err := a.k8sClient.GetCoreClient().PersistentVolumeClaims(options.Namespace).Get(ctx, options.PVCName, metav1.GetOptions{})
+		if err == nil && claim.Status.Phase == v1.ClaimBound {
+			if claim.Spec.VolumeName != options.PVName {
+				return false, fmt.Errorf("claim %s/%s bound to %s instead of %s", options.Namespace, options.PVCName, claim.Spec.VolumeName, options.PVName)
+			}
+			return true, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, fmt.Errorf("claim %s/%s did not bind to %s within %s\n\nPlease either:\n- Check events: kubectl describe pvc %s -n %s\n- Retry with a longer wait: --wait 5m", options.Namespace, options.PVCName, options.PVName, timeout, options.PVCName, options.Namespace)
+		case <-ticker.C:
+		}
+	}
+}
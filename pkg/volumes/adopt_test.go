@@ -0,0 +1,155 @@
+package volumes
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"kubectl-broker/pkg"
+)
+
+func adoptablePV(phase v1.PersistentVolumePhase, policy v1.PersistentVolumeReclaimPolicy) *v1.PersistentVolume {
+	return &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+		Spec: v1.PersistentVolumeSpec{
+			Capacity:                      v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")},
+			AccessModes:                   []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			PersistentVolumeReclaimPolicy: policy,
+			ClaimRef:                      &v1.ObjectReference{Namespace: "prod", Name: "data-broker-0"},
+		},
+		Status: v1.PersistentVolumeStatus{Phase: phase},
+	}
+}
+
+func TestValidateAdoptable(t *testing.T) {
+	t.Parallel()
+
+	noCapacity := adoptablePV(v1.VolumeReleased, v1.PersistentVolumeReclaimRetain)
+	noCapacity.Spec.Capacity = nil
+
+	cases := []struct {
+		name    string
+		pv      *v1.PersistentVolume
+		pvcName string
+		wantErr string
+	}{
+		{"released and retained", adoptablePV(v1.VolumeReleased, v1.PersistentVolumeReclaimRetain), "data-broker-0", ""},
+		{"available and retained", adoptablePV(v1.VolumeAvailable, v1.PersistentVolumeReclaimRetain), "data-broker-0", ""},
+		{"bound to the target claim", adoptablePV(v1.VolumeBound, v1.PersistentVolumeReclaimRetain), "data-broker-0", "already bound to prod/data-broker-0"},
+		{"bound to a live claim", adoptablePV(v1.VolumeBound, v1.PersistentVolumeReclaimRetain), "data-broker-1", "bound to a live claim"},
+		{"failed", adoptablePV(v1.VolumeFailed, v1.PersistentVolumeReclaimRetain), "data-broker-0", "only Released or Available"},
+		{"pending", adoptablePV(v1.VolumePending, v1.PersistentVolumeReclaimRetain), "data-broker-0", "only Released or Available"},
+		{"reclaim policy delete", adoptablePV(v1.VolumeReleased, v1.PersistentVolumeReclaimDelete), "data-broker-0", "reclaim policy Delete"},
+		{"reclaim policy recycle", adoptablePV(v1.VolumeReleased, v1.PersistentVolumeReclaimRecycle), "data-broker-0", ""},
+		{"no capacity", noCapacity, "data-broker-0", "no storage capacity"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateAdoptable(tc.pv, "prod", tc.pvcName)
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Fatalf("error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildAdoptionClaim(t *testing.T) {
+	t.Parallel()
+
+	block := v1.PersistentVolumeBlock
+	cases := []struct {
+		name         string
+		storageClass string
+		volumeMode   *v1.PersistentVolumeMode
+	}{
+		{"named storage class", "fast-ssd", nil},
+		// An explicit "" keeps the default StorageClass from being applied to the claim
+		{"empty storage class", "", nil},
+		{"block volume", "fast-ssd", &block},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pv := adoptablePV(v1.VolumeReleased, v1.PersistentVolumeReclaimRetain)
+			pv.Spec.StorageClassName = tc.storageClass
+			pv.Spec.VolumeMode = tc.volumeMode
+
+			claim := BuildAdoptionClaim(pv, "prod", "data-broker-0")
+			if claim.Namespace != "prod" || claim.Name != "data-broker-0" || claim.Spec.VolumeName != "pv-1" {
+				t.Fatalf("claim %s/%s binds to %q, want prod/data-broker-0 bound to pv-1", claim.Namespace, claim.Name, claim.Spec.VolumeName)
+			}
+			if claim.Spec.StorageClassName == nil || *claim.Spec.StorageClassName != tc.storageClass {
+				t.Fatalf("StorageClassName = %v, want explicit %q", claim.Spec.StorageClassName, tc.storageClass)
+			}
+			if size := claim.Spec.Resources.Requests[v1.ResourceStorage]; size.Cmp(resource.MustParse("10Gi")) != 0 {
+				t.Errorf("requested size = %s, want 10Gi", size.String())
+			}
+			if len(claim.Spec.AccessModes) != 1 || claim.Spec.AccessModes[0] != v1.ReadWriteOnce {
+				t.Errorf("AccessModes = %v, want [ReadWriteOnce]", claim.Spec.AccessModes)
+			}
+			if claim.Spec.VolumeMode != tc.volumeMode {
+				t.Errorf("VolumeMode = %v, want %v", claim.Spec.VolumeMode, tc.volumeMode)
+			}
+		})
+	}
+}
+
+func TestAdoptVolumeRestoresClaimRefWhenCreateFails(t *testing.T) {
+	t.Parallel()
+
+	pv := adoptablePV(v1.VolumeReleased, v1.PersistentVolumeReclaimRetain)
+	pv.ResourceVersion = "12"
+	pv.Spec.ClaimRef.UID = "old-uid"
+	pv.Spec.ClaimRef.ResourceVersion = "3"
+	previous := *pv.Spec.ClaimRef
+
+	clientset := fake.NewClientset(pv)
+	var patches []map[string]any
+	clientset.PrependReactor("patch", "persistentvolumes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		var patch map[string]any
+		if err := json.Unmarshal(action.(k8stesting.PatchAction).GetPatch(), &patch); err != nil {
+			t.Errorf("patch is not JSON: %v", err)
+		}
+		patches = append(patches, patch)
+		return false, nil, nil
+	})
+	clientset.PrependReactor("create", "persistentvolumeclaims", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(v1.Resource("persistentvolumeclaims"), "data-broker-1", nil)
+	})
+
+	_, err := NewAdopter(pkg.NewK8sClientForClients(clientset)).AdoptVolume(context.Background(), AdoptOptions{PVName: "pv-1", Namespace: "prod", PVCName: "data-broker-1"})
+	if err == nil || !strings.Contains(err.Error(), "claimRef of persistent volume pv-1 was restored") {
+		t.Fatalf("error = %v, want the failed create with the restored claimRef", err)
+	}
+
+	if len(patches) != 2 {
+		t.Fatalf("expected the pre-bind and the restore patch, got %d", len(patches))
+	}
+	preBind := patches[0]
+	if rv := preBind["metadata"].(map[string]any)["resourceVersion"]; rv != "12" {
+		t.Errorf("pre-bind patch resourceVersion = %v, want the fetched 12", rv)
+	}
+	claimRef := preBind["spec"].(map[string]any)["claimRef"].(map[string]any)
+	if claimRef["name"] != "data-broker-1" || claimRef["uid"] != nil {
+		t.Errorf("pre-bind claimRef = %v, want data-broker-1 without uid", claimRef)
+	}
+
+	restored, err := clientset.CoreV1().PersistentVolumes().Get(context.Background(), "pv-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get volume: %v", err)
+	}
+	if restored.Spec.ClaimRef == nil || *restored.Spec.ClaimRef != previous {
+		t.Fatalf("claimRef = %+v, want the previous %+v", restored.Spec.ClaimRef, previous)
+	}
+}
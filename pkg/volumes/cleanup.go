@@ -3,13 +3,14 @@ package volumes
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"kubectl-broker/pkg"
@@ -31,6 +32,10 @@ func NewCleaner(k8sClient *pkg.K8sClient) *Cleaner {
 
 // CleanupVolumes performs volume cleanup based on the provided options
 func (c *Cleaner) CleanupVolumes(ctx context.Context, options CleanupOptions) (*CleanupResult, error) {
+	if err := ValidatePatterns(append(append([]string{}, options.Include...), options.Exclude...)); err != nil {
+		return nil, err
+	}
+
 	// First, analyze volumes to find candidates for cleanup
 	analysisOptions := AnalysisOptions{
 		Namespace:     options.Namespace,
@@ -40,6 +45,7 @@ func (c *Cleaner) CleanupVolumes(ctx context.Context, options CleanupOptions) (*
 		ShowReleased:  true,
 		ShowOrphaned:  true,
 		UseColors:     options.UseColors,
+		Selector:      options.Selector,
 	}
 
 	analysisResult, err := c.analyzer.AnalyzeVolumes(ctx, analysisOptions)
@@ -56,27 +62,44 @@ func (c *Cleaner) CleanupVolumes(ctx context.Context, options CleanupOptions) (*
 	// Filter volumes by cleanup criteria
 	pvCandidates := c.filterPVsForCleanup(analysisResult.ReleasedPVs, options)
 	pvcCandidates := c.filterPVCsForCleanup(analysisResult.OrphanedPVCs, options)
+
+	// Keep volumes of running HiveMQ installations unless --force overrides the protection
+	if options.ProtectHiveMQ && !options.Force {
+		protected, err := c.runningHiveMQNamespaces(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover running HiveMQ installations: %w", err)
+		}
+		pvCandidates, pvcCandidates = c.excludeProtected(result, pvCandidates, pvcCandidates, protected)
+	}
+
 	result.PlannedReleasedPVs = len(pvCandidates)
 	result.PlannedOrphanedPVCs = len(pvcCandidates)
 
 	if len(pvCandidates) == 0 && len(pvcCandidates) == 0 {
-		if options.UseColors {
+		if options.UseColors && len(result.Protected) > 0 {
+			fmt.Printf("No volumes found matching cleanup criteria (%d protected by --protect-hivemq).\n", len(result.Protected))
+		} else if options.UseColors {
 			fmt.Println("No volumes found matching cleanup criteria.")
 		}
 		return result, nil
 	}
 
+	if err := c.checkOperatorManaged(ctx, pvCandidates, pvcCandidates, options); err != nil {
+		return nil, err
+	}
+
 	// Create cleanup plan
 	c.createCleanupPlan(result, pvCandidates, pvcCandidates)
 
-	// If dry-run, just return the preview
+	// If dry-run, just return the preview and the plan it can be exported as
 	if options.DryRun {
+		result.Plan = c.newCleanupPlan(ctx, pvCandidates, pvcCandidates, options)
 		c.displayDryRunPreview(result, options)
 		return result, nil
 	}
 
-	// Show cleanup plan and ask for confirmation unless forced
-	if !options.Force {
+	// Show cleanup plan and ask for confirmation unless forced or confirmed up front
+	if !options.Force && !options.Confirmed {
 		confirmed, err := c.confirmCleanup(result, options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get confirmation: %w", err)
@@ -88,7 +111,7 @@ func (c *Cleaner) CleanupVolumes(ctx context.Context, options CleanupOptions) (*
 	}
 
 	// Perform actual cleanup
-	if err := c.performCleanup(ctx, result, pvCandidates, pvcCandidates, options); err != nil {
+	if err := c.performCleanup(ctx, result, pvCandidates, pvcCandidates, nil); err != nil {
 		return result, fmt.Errorf("cleanup failed: %w", err)
 	}
 
@@ -128,6 +151,10 @@ func (c *Cleaner) shouldCleanupPV(pv *v1.PersistentVolume, options CleanupOption
 		return false
 	}
 
+	if !passesPatterns(pv.Name, pvClaimNamespace(pv), options) {
+		return false
+	}
+
 	// Check age requirement
 	if options.MinAge > 0 {
 		age := time.Since(pv.CreationTimestamp.Time)
@@ -137,16 +164,8 @@ func (c *Cleaner) shouldCleanupPV(pv *v1.PersistentVolume, options CleanupOption
 	}
 
 	// Check size requirement
-	if options.MinSize != "" {
-		minSize, err := resource.ParseQuantity(options.MinSize)
-		if err != nil {
-			return false // Skip if size parsing fails
-		}
-		if storage, ok := pv.Spec.Capacity[v1.ResourceStorage]; ok {
-			if storage.Cmp(minSize) < 0 {
-				return false
-			}
-		}
+	if storage, ok := pv.Spec.Capacity[v1.ResourceStorage]; ok && belowMinSize(storage, options.MinSize) {
+		return false
 	}
 
 	return true
@@ -154,6 +173,10 @@ func (c *Cleaner) shouldCleanupPV(pv *v1.PersistentVolume, options CleanupOption
 
 // shouldCleanupPVC determines if a persistent volume claim should be cleaned up
 func (c *Cleaner) shouldCleanupPVC(pvc *v1.PersistentVolumeClaim, options CleanupOptions) bool {
+	if !passesPatterns(pvc.Name, pvc.Namespace, options) {
+		return false
+	}
+
 	// Check age requirement
 	if options.MinAge > 0 {
 		age := time.Since(pvc.CreationTimestamp.Time)
@@ -163,16 +186,8 @@ func (c *Cleaner) shouldCleanupPVC(pvc *v1.PersistentVolumeClaim, options Cleanu
 	}
 
 	// Check size requirement
-	if options.MinSize != "" {
-		minSize, err := resource.ParseQuantity(options.MinSize)
-		if err != nil {
-			return false // Skip if size parsing fails
-		}
-		if storage, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]; ok {
-			if storage.Cmp(minSize) < 0 {
-				return false
-			}
-		}
+	if storage, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]; ok && belowMinSize(storage, options.MinSize) {
+		return false
 	}
 
 	return true
@@ -182,55 +197,87 @@ func (c *Cleaner) shouldCleanupPVC(pvc *v1.PersistentVolumeClaim, options Cleanu
 func (c *Cleaner) createCleanupPlan(result *CleanupResult, pvs []*v1.PersistentVolume, pvcs []*v1.PersistentVolumeClaim) {
 	result.DryRunPreview = []CleanupAction{}
 
-	// Add PV cleanup actions
 	for _, pv := range pvs {
-		var size int64
-		if storage, ok := pv.Spec.Capacity[v1.ResourceStorage]; ok {
-			size = storage.Value()
-		}
+		action := pvCleanupAction(pv)
+		result.DryRunPreview = append(result.DryRunPreview, action)
+		result.PlannedReclaimedStorage += action.Size
+	}
 
-		age := time.Since(pv.CreationTimestamp.Time)
-		namespace := ""
-		if pv.Spec.ClaimRef != nil {
-			namespace = pv.Spec.ClaimRef.Namespace
-		}
+	for _, pvc := range pvcs {
+		action := pvcCleanupAction(pvc)
+		result.DryRunPreview = append(result.DryRunPreview, action)
+		result.PlannedReclaimedStorage += action.Size
+	}
+}
 
-		action := CleanupAction{
-			Type:      "PersistentVolume",
-			Name:      pv.Name,
-			Namespace: namespace,
-			Size:      size,
-			Age:       age,
-			Reason:    "Volume is in Released state and can be reclaimed",
+// excludeProtected drops candidates in protected namespaces and records them in result.Protected
+func (c *Cleaner) excludeProtected(result *CleanupResult, pvs []*v1.PersistentVolume, pvcs []*v1.PersistentVolumeClaim, protected map[string]string) ([]*v1.PersistentVolume, []*v1.PersistentVolumeClaim) {
+	var keptPVs []*v1.PersistentVolume
+	for _, pv := range pvs {
+		namespace := pvClaimNamespace(pv)
+		if isProtectedNamespace(protected, namespace) {
+			action := pvCleanupAction(pv)
+			action.Reason = protectedReason(protected, namespace)
+			result.Protected = append(result.Protected, action)
+			continue
 		}
-
-		result.DryRunPreview = append(result.DryRunPreview, action)
-		result.PlannedReclaimedStorage += size
+		keptPVs = append(keptPVs, pv)
 	}
 
-	// Add PVC cleanup actions
+	var keptPVCs []*v1.PersistentVolumeClaim
 	for _, pvc := range pvcs {
-		var size int64
-		if storage, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]; ok {
-			size = storage.Value()
+		if isProtectedNamespace(protected, pvc.Namespace) {
+			action := pvcCleanupAction(pvc)
+			action.Reason = protectedReason(protected, pvc.Namespace)
+			result.Protected = append(result.Protected, action)
+			continue
 		}
+		keptPVCs = append(keptPVCs, pvc)
+	}
 
-		age := time.Since(pvc.CreationTimestamp.Time)
+	return keptPVs, keptPVCs
+}
 
-		action := CleanupAction{
-			Type:      "PersistentVolumeClaim",
-			Name:      pvc.Name,
-			Namespace: pvc.Namespace,
-			Size:      size,
-			Age:       age,
-			Reason:    "PVC is not mounted by any running pods",
-		}
+func pvCleanupAction(pv *v1.PersistentVolume) CleanupAction {
+	var size int64
+	if storage, ok := pv.Spec.Capacity[v1.ResourceStorage]; ok {
+		size = storage.Value()
+	}
 
-		result.DryRunPreview = append(result.DryRunPreview, action)
-		result.PlannedReclaimedStorage += size
+	return CleanupAction{
+		Type:      "PersistentVolume",
+		Name:      pv.Name,
+		Namespace: pvClaimNamespace(pv),
+		Size:      size,
+		Age:       time.Since(pv.CreationTimestamp.Time),
+		Reason:    "Volume is in Released state and can be reclaimed",
+	}
+}
+
+func pvcCleanupAction(pvc *v1.PersistentVolumeClaim) CleanupAction {
+	var size int64
+	if storage, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]; ok {
+		size = storage.Value()
+	}
+
+	return CleanupAction{
+		Type:      "PersistentVolumeClaim",
+		Name:      pvc.Name,
+		Namespace: pvc.Namespace,
+		Size:      size,
+		Age:       time.Since(pvc.CreationTimestamp.Time),
+		Reason:    "PVC is not mounted by any running pods",
 	}
 }
 
+// pvClaimNamespace returns the namespace of the claim a PV was bound to, if any
+func pvClaimNamespace(pv *v1.PersistentVolume) string {
+	if pv.Spec.ClaimRef != nil {
+		return pv.Spec.ClaimRef.Namespace
+	}
+	return ""
+}
+
 // displayDryRunPreview shows what would be deleted in dry-run mode
 func (c *Cleaner) displayDryRunPreview(result *CleanupResult, options CleanupOptions) {
 	if len(result.DryRunPreview) == 0 {
@@ -260,9 +307,23 @@ func (c *Cleaner) displayDryRunPreview(result *CleanupResult, options CleanupOpt
 	fmt.Printf("- %d PersistentVolumeClaims would be deleted\n", countActionsByType(result.DryRunPreview, "PersistentVolumeClaim"))
 	fmt.Printf("- Total storage to be reclaimed: %s\n", formatSize(result.PlannedReclaimedStorage))
 
+	displayProtectedVolumes(result)
+
 	fmt.Printf("\nTo proceed with deletion, run the command again with --confirm flag.\n")
 }
 
+// displayProtectedVolumes lists volumes kept by --protect-hivemq
+func displayProtectedVolumes(result *CleanupResult) {
+	if len(result.Protected) == 0 {
+		return
+	}
+
+	fmt.Printf("\nProtected (skipped, use --force to override):\n")
+	for _, action := range result.Protected {
+		fmt.Printf("- %s %s/%s: %s\n", action.Type, action.Namespace, action.Name, action.Reason)
+	}
+}
+
 // confirmCleanup asks user for confirmation before proceeding with cleanup
 func (c *Cleaner) confirmCleanup(result *CleanupResult, options CleanupOptions) (bool, error) {
 	if len(result.DryRunPreview) == 0 {
@@ -292,6 +353,9 @@ func (c *Cleaner) confirmCleanup(result *CleanupResult, options CleanupOptions)
 
 	reader := bufio.NewReader(os.Stdin)
 	response, err := reader.ReadString('\n')
+	if errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("no confirmation received: stdin was closed\n\nPlease either:\n- Run the command in an interactive terminal\n- Confirm without a prompt: --confirm-phrase <namespace>")
+	}
 	if err != nil {
 		return false, fmt.Errorf("failed to read user input: %w", err)
 	}
@@ -300,8 +364,12 @@ func (c *Cleaner) confirmCleanup(result *CleanupResult, options CleanupOptions)
 	return response == "y" || response == "yes", nil
 }
 
-// performCleanup executes the actual volume deletion
-func (c *Cleaner) performCleanup(ctx context.Context, result *CleanupResult, pvs []*v1.PersistentVolume, pvcs []*v1.PersistentVolumeClaim, options CleanupOptions) error {
+// performCleanup executes the actual volume deletion. Deletes are preconditioned on the UID and
+// resourceVersion seen during analysis; with a plan, only volumes bound to claims it lists are cascaded.
+func (c *Cleaner) performCleanup(ctx context.Context, result *CleanupResult, pvs []*v1.PersistentVolume, pvcs []*v1.PersistentVolumeClaim, plan *CleanupPlan) error {
+	if err := pkg.GuardMutation(ctx, "delete", "volumes"); err != nil {
+		return err
+	}
 	coreClient := c.k8sClient.GetCoreClient()
 	result.TotalReclaimedStorage = 0
 	result.DeletedReleasedPVs = 0
@@ -314,7 +382,7 @@ func (c *Cleaner) performCleanup(ctx context.Context, result *CleanupResult, pvs
 	for i, pv := range pvs {
 		fmt.Printf("[%d/%d] Deleting PV %s...", i+1, len(pvs), pv.Name)
 
-		err := coreClient.PersistentVolumes().Delete(ctx, pv.Name, metav1.DeleteOptions{})
+		err := coreClient.PersistentVolumes().Delete(ctx, pv.Name, unchangedSince(pv.ObjectMeta))
 		if err != nil {
 			fmt.Printf(" FAILED\n")
 			result.FailedDeletions = append(result.FailedDeletions, CleanupError{
@@ -326,6 +394,7 @@ func (c *Cleaner) performCleanup(ctx context.Context, result *CleanupResult, pvs
 		} else {
 			fmt.Printf(" OK\n")
 			result.DeletedPVs = append(result.DeletedPVs, pv.Name)
+			result.Deleted = append(result.Deleted, pvCleanupAction(pv))
 			result.DeletedReleasedPVs++
 
 			// Add to reclaimed storage
@@ -342,11 +411,15 @@ func (c *Cleaner) performCleanup(ctx context.Context, result *CleanupResult, pvs
 		if err != nil {
 			fmt.Printf("[%d/%d] Warning: Could not find PV for PVC %s: %v\n", i+1, len(pvcs), pvc.Name, err)
 		}
+		if associatedPV != nil && plan != nil && !plan.plannedAssociatedPV(pvc, associatedPV) {
+			fmt.Printf("[%d/%d] Warning: PV %s of PVC %s is not in the plan and is kept\n", i+1, len(pvcs), associatedPV.Name, pvc.Name)
+			associatedPV = nil
+		}
 
 		// Delete PVC first
 		fmt.Printf("[%d/%d] Deleting PVC %s in namespace %s...", i+1, len(pvcs), pvc.Name, pvc.Namespace)
 
-		err = coreClient.PersistentVolumeClaims(pvc.Namespace).Delete(ctx, pvc.Name, metav1.DeleteOptions{})
+		err = coreClient.PersistentVolumeClaims(pvc.Namespace).Delete(ctx, pvc.Name, unchangedSince(pvc.ObjectMeta))
 		if err != nil {
 			fmt.Printf(" FAILED\n")
 			result.FailedDeletions = append(result.FailedDeletions, CleanupError{
@@ -359,6 +432,7 @@ func (c *Cleaner) performCleanup(ctx context.Context, result *CleanupResult, pvs
 		} else {
 			fmt.Printf(" OK")
 			result.DeletedPVCs = append(result.DeletedPVCs, pvc.Name)
+			result.Deleted = append(result.Deleted, pvcCleanupAction(pvc))
 			result.DeletedOrphanedPVCs++
 
 			// Add to reclaimed storage
@@ -371,7 +445,7 @@ func (c *Cleaner) performCleanup(ctx context.Context, result *CleanupResult, pvs
 		if associatedPV != nil {
 			fmt.Printf(" + Deleting associated PV %s...", associatedPV.Name)
 
-			err = coreClient.PersistentVolumes().Delete(ctx, associatedPV.Name, metav1.DeleteOptions{})
+			err = coreClient.PersistentVolumes().Delete(ctx, associatedPV.Name, unchangedSince(associatedPV.ObjectMeta))
 			if err != nil {
 				fmt.Printf(" FAILED\n")
 				result.FailedDeletions = append(result.FailedDeletions, CleanupError{
@@ -383,6 +457,7 @@ func (c *Cleaner) performCleanup(ctx context.Context, result *CleanupResult, pvs
 			} else {
 				fmt.Printf(" OK\n")
 				result.DeletedPVs = append(result.DeletedPVs, associatedPV.Name)
+				result.Deleted = append(result.Deleted, pvCleanupAction(associatedPV))
 				result.AssociatedPVsDeleted++
 
 				// Add PV storage to reclaimed total (avoid double counting)
@@ -407,6 +482,12 @@ func (c *Cleaner) performCleanup(ctx context.Context, result *CleanupResult, pvs
 
 // Utility functions
 
+// unchangedSince makes a delete fail with a conflict when the object was replaced or modified
+// after it was read
+func unchangedSince(meta metav1.ObjectMeta) metav1.DeleteOptions {
+	return metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &meta.UID, ResourceVersion: &meta.ResourceVersion}}
+}
+
 func countActionsByType(actions []CleanupAction, actionType string) int {
 	count := 0
 	for _, action := range actions {
@@ -0,0 +1,166 @@
+package volumes
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// Placeholder StorageClass names for volumes without an explicit class
+const (
+	NoStorageClass      = "(none)"    // storageClassName is set to ""
+	DefaultStorageClass = "(default)" // PVC without storageClassName, provisioned by the cluster default
+)
+
+// FallbackCostRateKey prices every StorageClass without its own rate
+const FallbackCostRateKey = "*"
+
+const bytesPerGiB = 1024 * 1024 * 1024
+
+// CostRates maps StorageClass names to a price per GiB and month. The currency is whatever
+// the rates are given in.
+type CostRates map[string]float64
+
+// ParseCostRates converts class=price pairs such as gp3=0.08 into cost rates
+func ParseCostRates(values map[string]string) (CostRates, error) {
+	rates := make(CostRates, len(values))
+	for class, value := range values {
+		class = strings.TrimSpace(class)
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if class == "" || err != nil || rate < 0 {
+			return nil, fmt.Errorf("invalid cost rate %q for StorageClass %q\n\nPlease either:\n- Use a non-negative price per GB and month: --cost-per-gb-month gp3=0.08\n- Price all other classes with a fallback: --cost-per-gb-month '*=0.10'", value, class)
+		}
+		rates[class] = rate
+	}
+	return rates, nil
+}
+
+// RateFor returns the price of a StorageClass, falling back to the "*" rate
+func (r CostRates) RateFor(storageClass string) (float64, bool) {
+	if rate, ok := r[storageClass]; ok {
+		return rate, true
+	}
+	rate, ok := r[FallbackCostRateKey]
+	return rate, ok
+}
+
+// StorageClassStats aggregates the analyzed volumes of a single StorageClass
+type StorageClassStats struct {
+	StorageClass       string
+	Volumes            int
+	TotalBytes         int64
+	ReclaimableVolumes int
+	ReclaimableBytes   int64
+	Priced             bool    // a cost rate applies to this class
+	CostPerGBMonth     float64 // rate used for the estimates
+	MonthlyCost        float64 // estimated cost of all analyzed volumes
+	MonthlySavings     float64 // estimated savings from cleaning up the reclaimable volumes
+}
+
+// aggregateStorageClasses groups released, orphaned and bound volumes by StorageClass and
+// prices them with the given rates
+func aggregateStorageClasses(result *AnalysisResult, rates CostRates) {
+	stats := make(map[string]*StorageClassStats)
+	add := func(storageClass string, bytes int64, reclaimable bool) {
+		entry := stats[storageClass]
+		if entry == nil {
+			entry = &StorageClassStats{StorageClass: storageClass}
+			stats[storageClass] = entry
+		}
+		entry.Volumes++
+		entry.TotalBytes += bytes
+		if reclaimable {
+			entry.ReclaimableVolumes++
+			entry.ReclaimableBytes += bytes
+		}
+	}
+
+	for _, pv := range result.ReleasedPVs {
+		add(pvStorageClass(pv), quantityBytes(pv.Spec.Capacity), true)
+	}
+	for _, pvc := range result.OrphanedPVCs {
+		add(pvcStorageClass(pvc), quantityBytes(pvc.Spec.Resources.Requests), true)
+	}
+	for _, volume := range result.BoundVolumes {
+		add(pvcStorageClass(volume.PVC), volume.Size.Value(), false)
+	}
+
+	result.StorageClasses = make([]StorageClassStats, 0, len(stats))
+	result.EstimatedMonthlyCost, result.EstimatedMonthlySavings = 0, 0
+	for _, entry := range stats {
+		if rate, ok := rates.RateFor(entry.StorageClass); ok {
+			entry.Priced = true
+			entry.CostPerGBMonth = rate
+			entry.MonthlyCost = float64(entry.TotalBytes) / bytesPerGiB * rate
+			entry.MonthlySavings = float64(entry.ReclaimableBytes) / bytesPerGiB * rate
+			result.EstimatedMonthlyCost += entry.MonthlyCost
+			result.EstimatedMonthlySavings += entry.MonthlySavings
+		}
+		result.StorageClasses = append(result.StorageClasses, *entry)
+	}
+
+	// Classes with the most reclaimable storage first
+	sort.Slice(result.StorageClasses, func(i, j int) bool {
+		a, b := result.StorageClasses[i], result.StorageClasses[j]
+		if a.ReclaimableBytes != b.ReclaimableBytes {
+			return a.ReclaimableBytes > b.ReclaimableBytes
+		}
+		return a.StorageClass < b.StorageClass
+	})
+}
+
+// costRecommendations summarizes the estimated savings and names reclaimable classes without a rate
+func costRecommendations(result *AnalysisResult, rates CostRates) []string {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	var recommendations, breakdown, unpriced []string
+	for _, entry := range result.StorageClasses {
+		switch {
+		case entry.ReclaimableBytes == 0:
+		case entry.Priced:
+			breakdown = append(breakdown, fmt.Sprintf("%s: %.2f", entry.StorageClass, entry.MonthlySavings))
+		default:
+			unpriced = append(unpriced, entry.StorageClass)
+		}
+	}
+
+	if len(breakdown) > 0 {
+		recommendations = append(recommendations, fmt.Sprintf("Estimated monthly savings from cleanup: %.2f (%s)",
+			result.EstimatedMonthlySavings, strings.Join(breakdown, ", ")))
+	}
+	if len(unpriced) > 0 {
+		recommendations = append(recommendations, fmt.Sprintf("No cost rate for StorageClass %s; add it to --cost-per-gb-month or set a %q rate",
+			strings.Join(unpriced, ", "), FallbackCostRateKey))
+	}
+	return recommendations
+}
+
+func pvStorageClass(pv *v1.PersistentVolume) string {
+	if pv.Spec.StorageClassName == "" {
+		return NoStorageClass
+	}
+	return pv.Spec.StorageClassName
+}
+
+func pvcStorageClass(pvc *v1.PersistentVolumeClaim) string {
+	switch {
+	case pvc == nil || pvc.Spec.StorageClassName == nil:
+		return DefaultStorageClass
+	case *pvc.Spec.StorageClassName == "":
+		return NoStorageClass
+	default:
+		return *pvc.Spec.StorageClassName
+	}
+}
+
+func quantityBytes(resources v1.ResourceList) int64 {
+	if storage, ok := resources[v1.ResourceStorage]; ok {
+		return storage.Value()
+	}
+	return 0
+}
@@ -11,6 +11,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"kubectl-broker/pkg"
+	"kubectl-broker/pkg/units"
 )
 
 // Analyzer provides volume analysis functionality
@@ -34,7 +35,12 @@ func (a *Analyzer) AnalyzeVolumes(ctx context.Context, options AnalysisOptions)
 	// Initialize usage collector only if detailed mode is enabled
 	var usageCollector *VolumeUsageCollector
 	if options.ShowDetailed {
-		usageCollector = NewVolumeUsageCollector(a.k8sClient)
+		collectorOptions := DefaultUsageCollectorOptions
+		collectorOptions.Concurrency = options.UsageConcurrency
+		if options.UsageTimeout > 0 {
+			collectorOptions.Timeout = options.UsageTimeout
+		}
+		usageCollector = NewVolumeUsageCollectorWithOptions(a.k8sClient, collectorOptions)
 	}
 
 	if options.AllNamespaces {
@@ -47,10 +53,11 @@ func (a *Analyzer) AnalyzeVolumes(ctx context.Context, options AnalysisOptions)
 // analyzeClusterWide performs cluster-wide volume analysis
 func (a *Analyzer) analyzeClusterWide(ctx context.Context, options AnalysisOptions, result *AnalysisResult, usageCollector *VolumeUsageCollector) (*AnalysisResult, error) {
 	// Get all PVs in cluster
-	pvs, err := a.getAllPersistentVolumes(ctx)
+	pvs, err := a.getAllPersistentVolumes(ctx, options.Selector)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get persistent volumes: %w", err)
 	}
+	pvs = filterPersistentVolumes(pvs, options)
 	result.TotalPVs = len(pvs)
 
 	// Get all namespaces to check which ones exist
@@ -71,10 +78,11 @@ func (a *Analyzer) analyzeClusterWide(ctx context.Context, options AnalysisOptio
 	}
 
 	// Get all PVCs across all namespaces
-	allPVCs, err := a.getAllPersistentVolumeClaims(ctx)
+	allPVCs, err := a.getAllPersistentVolumeClaims(ctx, options.Selector)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get persistent volume claims: %w", err)
 	}
+	allPVCs = filterPersistentVolumeClaims(allPVCs, options)
 	result.TotalPVCs = len(allPVCs)
 
 	// Collect volume usage statistics for all namespaces
@@ -95,8 +103,13 @@ func (a *Analyzer) analyzeClusterWide(ctx context.Context, options AnalysisOptio
 		}
 	}
 
+	if err := a.diagnoseProblems(ctx, "", allPVCs, pvs, result); err != nil {
+		return nil, fmt.Errorf("failed to diagnose stuck volumes: %w", err)
+	}
+
 	a.calculateTotalReclaimableStorage(result)
-	a.generateRecommendations(result)
+	aggregateStorageClasses(result, options.CostRates)
+	a.generateRecommendations(result, options)
 
 	return result, nil
 }
@@ -104,10 +117,11 @@ func (a *Analyzer) analyzeClusterWide(ctx context.Context, options AnalysisOptio
 // analyzeNamespace performs namespace-specific volume analysis
 func (a *Analyzer) analyzeNamespace(ctx context.Context, namespace string, options AnalysisOptions, result *AnalysisResult, usageCollector *VolumeUsageCollector) (*AnalysisResult, error) {
 	// Get PVCs in the specific namespace
-	pvcs, err := a.getPersistentVolumeClaimsInNamespace(ctx, namespace)
+	pvcs, err := a.getPersistentVolumeClaimsInNamespace(ctx, namespace, options.Selector)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get PVCs in namespace %s: %w", namespace, err)
 	}
+	pvcs = filterPersistentVolumeClaims(pvcs, options)
 	result.TotalPVCs = len(pvcs)
 
 	// Collect volume usage statistics for this namespace
@@ -129,10 +143,11 @@ func (a *Analyzer) analyzeNamespace(ctx context.Context, namespace string, optio
 	}
 
 	// Get all PVs and check which ones belong to this namespace
-	allPVs, err := a.getAllPersistentVolumes(ctx)
+	allPVs, err := a.getAllPersistentVolumes(ctx, options.Selector)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get persistent volumes: %w", err)
 	}
+	allPVs = filterPersistentVolumes(allPVs, options)
 
 	namespaceMap := map[string]bool{namespace: true}
 
@@ -147,8 +162,13 @@ func (a *Analyzer) analyzeNamespace(ctx context.Context, namespace string, optio
 
 	result.TotalPVs = len(allPVs) // Total cluster PVs for context
 
+	if err := a.diagnoseProblems(ctx, namespace, pvcs, allPVs, result); err != nil {
+		return nil, fmt.Errorf("failed to diagnose stuck volumes: %w", err)
+	}
+
 	a.calculateTotalReclaimableStorage(result)
-	a.generateRecommendations(result)
+	aggregateStorageClasses(result, options.CostRates)
+	a.generateRecommendations(result, options)
 
 	return result, nil
 }
@@ -162,6 +182,9 @@ func (a *Analyzer) analyzePersistentVolume(ctx context.Context, pv *v1.Persisten
 		if options.MinAge > 0 && age < options.MinAge {
 			return nil
 		}
+		if storage, ok := pv.Spec.Capacity[v1.ResourceStorage]; ok && belowMinSize(storage, options.MinSize) {
+			return nil
+		}
 
 		// Check if this PV was from a deleted namespace
 		var isFromDeletedNamespace bool
@@ -172,7 +195,7 @@ func (a *Analyzer) analyzePersistentVolume(ctx context.Context, pv *v1.Persisten
 			}
 
 			// Update namespace statistics
-			a.updateNamespaceStats(result, claimNamespace, pv, isFromDeletedNamespace)
+			a.updateNamespaceStats(result, claimNamespace, pv, !isFromDeletedNamespace)
 		}
 
 		result.ReleasedPVs = append(result.ReleasedPVs, pv)
@@ -192,12 +215,16 @@ func (a *Analyzer) analyzePersistentVolumeClaim(ctx context.Context, pvc *v1.Per
 
 	// Check if PVC is bound - if not bound, it might be orphaned
 	if pvc.Status.Phase != v1.ClaimBound {
-		// Check age requirements
+		// Check age and size requirements
 		if options.MinAge > 0 && age < options.MinAge {
 			return nil
 		}
+		if storage, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]; ok && belowMinSize(storage, options.MinSize) {
+			return nil
+		}
 
 		result.OrphanedPVCs = append(result.OrphanedPVCs, pvc)
+		updateClaimStats(result, pvc, true)
 
 		// Add storage to reclaimable total
 		if storage, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]; ok {
@@ -214,18 +241,24 @@ func (a *Analyzer) analyzePersistentVolumeClaim(ctx context.Context, pvc *v1.Per
 	}
 
 	if isOrphaned {
-		// Check age requirements
+		// Check age and size requirements
 		if options.MinAge > 0 && age < options.MinAge {
 			return nil
 		}
+		if storage, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]; ok && belowMinSize(storage, options.MinSize) {
+			return nil
+		}
 
 		result.OrphanedPVCs = append(result.OrphanedPVCs, pvc)
+		updateClaimStats(result, pvc, true)
 
 		// Add storage to reclaimable total
 		if storage, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]; ok {
 			result.TotalReclaimableStorage += storage.Value()
 		}
 	} else {
+		updateClaimStats(result, pvc, false)
+
 		// PVC is bound and being used - add to bound volumes list
 		var size resource.Quantity
 		if storage, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]; ok {
@@ -280,8 +313,8 @@ func (a *Analyzer) isPVCOrphaned(ctx context.Context, pvc *v1.PersistentVolumeCl
 	return true, nil // No pods are using this PVC
 }
 
-// updateNamespaceStats updates namespace statistics for volume analysis
-func (a *Analyzer) updateNamespaceStats(result *AnalysisResult, namespace string, pv *v1.PersistentVolume, namespaceExists bool) {
+// namespaceStats returns the statistics of a namespace, creating them on first use
+func namespaceStats(result *AnalysisResult, namespace string, namespaceExists bool) *NamespaceVolumeStats {
 	if result.NamespaceStats[namespace] == nil {
 		result.NamespaceStats[namespace] = &NamespaceVolumeStats{
 			Namespace:         namespace,
@@ -289,8 +322,29 @@ func (a *Analyzer) updateNamespaceStats(result *AnalysisResult, namespace string
 			IsHiveMQNamespace: IsHiveMQVolume("", namespace),
 		}
 	}
+	return result.NamespaceStats[namespace]
+}
 
-	stats := result.NamespaceStats[namespace]
+// updateClaimStats counts an analyzed PVC in the statistics of its namespace
+func updateClaimStats(result *AnalysisResult, pvc *v1.PersistentVolumeClaim, orphaned bool) {
+	stats := namespaceStats(result, pvc.Namespace, true)
+	stats.TotalPVCs++
+	if orphaned {
+		stats.OrphanedPVCs++
+		if storage, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]; ok {
+			stats.TotalReclaimable += storage.Value()
+		}
+	} else {
+		stats.BoundPVCs++
+	}
+	if IsHiveMQVolume(pvc.Name, pvc.Namespace) {
+		stats.HiveMQVolumes++
+	}
+}
+
+// updateNamespaceStats updates namespace statistics for volume analysis
+func (a *Analyzer) updateNamespaceStats(result *AnalysisResult, namespace string, pv *v1.PersistentVolume, namespaceExists bool) {
+	stats := namespaceStats(result, namespace, namespaceExists)
 	stats.ReleasedPVs++
 
 	if storage, ok := pv.Spec.Capacity[v1.ResourceStorage]; ok {
@@ -323,7 +377,7 @@ func (a *Analyzer) calculateTotalReclaimableStorage(result *AnalysisResult) {
 }
 
 // generateRecommendations generates cleanup recommendations based on analysis
-func (a *Analyzer) generateRecommendations(result *AnalysisResult) {
+func (a *Analyzer) generateRecommendations(result *AnalysisResult, options AnalysisOptions) {
 	result.Recommendations = []string{}
 
 	if len(result.ReleasedPVs) > 0 {
@@ -340,6 +394,12 @@ func (a *Analyzer) generateRecommendations(result *AnalysisResult) {
 		storageGB := float64(result.TotalReclaimableStorage) / (1024 * 1024 * 1024)
 		result.Recommendations = append(result.Recommendations,
 			fmt.Sprintf("Total reclaimable storage: %.1f GB", storageGB))
+		result.Recommendations = append(result.Recommendations, costRecommendations(result, options.CostRates)...)
+	}
+
+	if len(result.Problems) > 0 {
+		result.Recommendations = append(result.Recommendations,
+			fmt.Sprintf("Found %d volumes stuck in Pending, Terminating or Failed; see the problems for root cause and remediation", len(result.Problems)))
 	}
 
 	if result.HiveMQVolumeCount > 0 {
@@ -356,8 +416,8 @@ func (a *Analyzer) generateRecommendations(result *AnalysisResult) {
 
 // Kubernetes client wrapper methods
 
-func (a *Analyzer) getAllPersistentVolumes(ctx context.Context) ([]*v1.PersistentVolume, error) {
-	pvList, err := a.k8sClient.GetCoreClient().PersistentVolumes().List(ctx, metav1.ListOptions{})
+func (a *Analyzer) getAllPersistentVolumes(ctx context.Context, selector string) ([]*v1.PersistentVolume, error) {
+	pvList, err := a.k8sClient.GetCoreClient().PersistentVolumes().List(ctx, metav1.ListOptions{LabelSelector: selector})
 	if err != nil {
 		return nil, err
 	}
@@ -384,8 +444,8 @@ func (a *Analyzer) getAllNamespaces(ctx context.Context) ([]*v1.Namespace, error
 	return namespaces, nil
 }
 
-func (a *Analyzer) getAllPersistentVolumeClaims(ctx context.Context) ([]*v1.PersistentVolumeClaim, error) {
-	pvcList, err := a.k8sClient.GetCoreClient().PersistentVolumeClaims("").List(ctx, metav1.ListOptions{})
+func (a *Analyzer) getAllPersistentVolumeClaims(ctx context.Context, selector string) ([]*v1.PersistentVolumeClaim, error) {
+	pvcList, err := a.k8sClient.GetCoreClient().PersistentVolumeClaims("").List(ctx, metav1.ListOptions{LabelSelector: selector})
 	if err != nil {
 		return nil, err
 	}
@@ -398,8 +458,8 @@ func (a *Analyzer) getAllPersistentVolumeClaims(ctx context.Context) ([]*v1.Pers
 	return pvcs, nil
 }
 
-func (a *Analyzer) getPersistentVolumeClaimsInNamespace(ctx context.Context, namespace string) ([]*v1.PersistentVolumeClaim, error) {
-	pvcList, err := a.k8sClient.GetCoreClient().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+func (a *Analyzer) getPersistentVolumeClaimsInNamespace(ctx context.Context, namespace, selector string) ([]*v1.PersistentVolumeClaim, error) {
+	pvcList, err := a.k8sClient.GetCoreClient().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
 	if err != nil {
 		return nil, err
 	}
@@ -432,9 +492,9 @@ func FilterVolumesBySize(pvs []*v1.PersistentVolume, minSize string) ([]*v1.Pers
 		return pvs, nil
 	}
 
-	minQuantity, err := resource.ParseQuantity(minSize)
+	minQuantity, err := units.ParseSize(minSize)
 	if err != nil {
-		return nil, fmt.Errorf("invalid size format %s: %w", minSize, err)
+		return nil, err
 	}
 
 	var filtered []*v1.PersistentVolume
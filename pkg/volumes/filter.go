@@ -0,0 +1,70 @@
+package volumes
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// accessModeAliases maps the kubectl short names to Kubernetes access modes
+var accessModeAliases = map[string]v1.PersistentVolumeAccessMode{
+	"RWO":  v1.ReadWriteOnce,
+	"ROX":  v1.ReadOnlyMany,
+	"RWX":  v1.ReadWriteMany,
+	"RWOP": v1.ReadWriteOncePod,
+}
+
+// ParseAccessMode accepts the short (RWO, RWX, ROX, RWOP) or full (ReadWriteOnce, ...) name of
+// an access mode. An empty value disables the filter.
+func ParseAccessMode(value string) (v1.PersistentVolumeAccessMode, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	if mode, ok := accessModeAliases[strings.ToUpper(value)]; ok {
+		return mode, nil
+	}
+	for _, mode := range accessModeAliases {
+		if strings.EqualFold(value, string(mode)) {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("invalid access mode %q\n\nPlease either:\n- Use a short name: --access-mode RWO (also RWX, ROX, RWOP)\n- Use the Kubernetes name: --access-mode ReadWriteOnce", value)
+}
+
+// filterPersistentVolumes keeps the PVs matching the StorageClass and access mode filters
+func filterPersistentVolumes(pvs []*v1.PersistentVolume, options AnalysisOptions) []*v1.PersistentVolume {
+	if options.StorageClass == "" && options.AccessMode == "" {
+		return pvs
+	}
+	filtered := make([]*v1.PersistentVolume, 0, len(pvs))
+	for _, pv := range pvs {
+		if matchesVolumeFilters(pvStorageClass(pv), pv.Spec.AccessModes, options) {
+			filtered = append(filtered, pv)
+		}
+	}
+	return filtered
+}
+
+// filterPersistentVolumeClaims keeps the PVCs matching the StorageClass and access mode filters
+func filterPersistentVolumeClaims(pvcs []*v1.PersistentVolumeClaim, options AnalysisOptions) []*v1.PersistentVolumeClaim {
+	if options.StorageClass == "" && options.AccessMode == "" {
+		return pvcs
+	}
+	filtered := make([]*v1.PersistentVolumeClaim, 0, len(pvcs))
+	for _, pvc := range pvcs {
+		if matchesVolumeFilters(pvcStorageClass(pvc), pvc.Spec.AccessModes, options) {
+			filtered = append(filtered, pvc)
+		}
+	}
+	return filtered
+}
+
+func matchesVolumeFilters(storageClass string, accessModes []v1.PersistentVolumeAccessMode, options AnalysisOptions) bool {
+	if options.StorageClass != "" && storageClass != options.StorageClass {
+		return false
+	}
+	return options.AccessMode == "" || slices.Contains(accessModes, options.AccessMode)
+}
@@ -0,0 +1,79 @@
+package volumes
+
+import (
+	"context"
+	"log/slog"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+)
+
+// checkOperatorManaged refuses to delete volumes a HiveMQ operator manages unless
+// options.ForceOperatorManaged is set, in which case it only warns about them
+func (c *Cleaner) checkOperatorManaged(ctx context.Context, pvs []*v1.PersistentVolume, pvcs []*v1.PersistentVolumeClaim, options CleanupOptions) error {
+	managed, err := c.operatorManagedVolumes(ctx, pvs, pvcs)
+	if err != nil || len(managed) == 0 {
+		return err
+	}
+	if !options.ForceOperatorManaged {
+		return &pkg.OperatorManagedError{Operation: "volume cleanup", Resources: managed}
+	}
+	for _, resource := range managed {
+		slog.Warn("Deleting a volume managed by the HiveMQ operator", "volume", resource.String())
+	}
+	return nil
+}
+
+// operatorManagedVolumes lists the volumes an operator owns or labels and the claims, or the
+// volumes of claims, of StatefulSets an operator manages
+func (c *Cleaner) operatorManagedVolumes(ctx context.Context, pvs []*v1.PersistentVolume, pvcs []*v1.PersistentVolumeClaim) ([]pkg.OperatorManagedResource, error) {
+	statefulSets := make(map[string][]appsv1.StatefulSet)
+	claimManagedBy := func(namespace, claim string) (string, error) {
+		if namespace == "" || claim == "" {
+			return "", nil
+		}
+		managed, ok := statefulSets[namespace]
+		if !ok {
+			var err error
+			if managed, err = c.k8sClient.OperatorManagedStatefulSets(ctx, namespace); err != nil {
+				return "", err
+			}
+			statefulSets[namespace] = managed
+		}
+		for i := range managed {
+			if pkg.IsStatefulSetClaim(claim, &managed[i]) {
+				return "claim of StatefulSet " + managed[i].Name + ", " + pkg.OperatorManagedBy(&managed[i]), nil
+			}
+		}
+		return "", nil
+	}
+
+	var resources []pkg.OperatorManagedResource
+	for _, pv := range pvs {
+		reason := pkg.OperatorManagedBy(pv)
+		if reason == "" && pv.Spec.ClaimRef != nil {
+			var err error
+			if reason, err = claimManagedBy(pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name); err != nil {
+				return nil, err
+			}
+		}
+		if reason != "" {
+			resources = append(resources, pkg.OperatorManagedResource{Kind: "PersistentVolume", Name: pv.Name, Reason: reason})
+		}
+	}
+	for _, pvc := range pvcs {
+		reason := pkg.OperatorManagedBy(pvc)
+		if reason == "" {
+			var err error
+			if reason, err = claimManagedBy(pvc.Namespace, pvc.Name); err != nil {
+				return nil, err
+			}
+		}
+		if reason != "" {
+			resources = append(resources, pkg.OperatorManagedResource{Kind: "PersistentVolumeClaim", Namespace: pvc.Namespace, Name: pvc.Name, Reason: reason})
+		}
+	}
+	return resources, nil
+}
@@ -0,0 +1,307 @@
+package volumes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Identification of exported cleanup plan files
+const (
+	CleanupPlanAPIVersion = "kubectl-broker/v1"
+	CleanupPlanKind       = "VolumeCleanupPlan"
+)
+
+// CleanupPlan is an exported dry-run result. Applying it deletes exactly the listed volumes and
+// nothing else, so the plan can be reviewed before someone else executes it.
+type CleanupPlan struct {
+	APIVersion    string          `json:"apiVersion"`
+	Kind          string          `json:"kind"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	Namespace     string          `json:"namespace,omitempty"`
+	AllNamespaces bool            `json:"allNamespaces,omitempty"`
+	ProtectHiveMQ bool            `json:"protectHiveMQ,omitempty"`
+	Volumes       []PlannedVolume `json:"volumes"`
+}
+
+// PlannedVolume pins a volume by UID and resourceVersion, so any change after planning is detected
+type PlannedVolume struct {
+	Type            string    `json:"type"`
+	Name            string    `json:"name"`
+	Namespace       string    `json:"namespace,omitempty"`
+	UID             types.UID `json:"uid"`
+	ResourceVersion string    `json:"resourceVersion"`
+	SizeBytes       int64     `json:"sizeBytes"`
+	// AssociatedPV is the volume bound to a planned claim, deleted together with the claim
+	AssociatedPV    string    `json:"associatedPV,omitempty"`
+	AssociatedPVUID types.UID `json:"associatedPVUid,omitempty"`
+}
+
+// PlanDrift describes a planned volume that no longer matches the cluster
+type PlanDrift struct {
+	Volume PlannedVolume
+	Reason string
+}
+
+// PlanDriftError lists every planned volume that changed since planning; nothing was deleted
+type PlanDriftError struct {
+	Drift []PlanDrift
+}
+
+func (e *PlanDriftError) Error() string {
+	lines := make([]string, 0, len(e.Drift))
+	for _, drift := range e.Drift {
+		lines = append(lines, fmt.Sprintf("- %s %s: %s", drift.Volume.Type, plannedVolumeName(drift.Volume), drift.Reason))
+	}
+	return fmt.Sprintf("%d planned volumes changed since the plan was created, nothing was deleted:\n%s", len(e.Drift), strings.Join(lines, "\n"))
+}
+
+// WriteCleanupPlan stores a plan as JSON when the path ends in .json and as YAML otherwise
+func WriteCleanupPlan(path string, plan *CleanupPlan) error {
+	var data []byte
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		data, err = json.MarshalIndent(plan, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(plan)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode cleanup plan: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cleanup plan: %w", err)
+	}
+	return nil
+}
+
+// ReadCleanupPlan loads a plan written by WriteCleanupPlan; YAML and JSON are both accepted
+func ReadCleanupPlan(path string) (*CleanupPlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cleanup plan: %w", err)
+	}
+
+	var plan CleanupPlan
+	if err := yaml.UnmarshalStrict(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse cleanup plan %s: %w", path, err)
+	}
+	if err := plan.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid cleanup plan %s: %w", path, err)
+	}
+	return &plan, nil
+}
+
+// Validate checks that the plan was produced by this tool and pins every volume
+func (p *CleanupPlan) Validate() error {
+	if p.APIVersion != CleanupPlanAPIVersion || p.Kind != CleanupPlanKind {
+		return fmt.Errorf("expected %s %s, got %s %s", CleanupPlanAPIVersion, CleanupPlanKind, p.APIVersion, p.Kind)
+	}
+	if p.Namespace == "" && !p.AllNamespaces {
+		return fmt.Errorf("plan has neither a namespace nor allNamespaces")
+	}
+	for _, volume := range p.Volumes {
+		switch volume.Type {
+		case "PersistentVolume", "PersistentVolumeClaim":
+		default:
+			return fmt.Errorf("volume %s has unsupported type %q", volume.Name, volume.Type)
+		}
+		if volume.Name == "" || volume.UID == "" || volume.ResourceVersion == "" {
+			return fmt.Errorf("%s %s lacks a name, uid or resourceVersion", volume.Type, volume.Name)
+		}
+		if volume.Type == "PersistentVolumeClaim" && volume.Namespace == "" {
+			return fmt.Errorf("PersistentVolumeClaim %s lacks a namespace", volume.Name)
+		}
+	}
+	return nil
+}
+
+// newCleanupPlan pins the candidates of a dry run, including the volumes bound to orphaned claims
+func (c *Cleaner) newCleanupPlan(ctx context.Context, pvs []*v1.PersistentVolume, pvcs []*v1.PersistentVolumeClaim, options CleanupOptions) *CleanupPlan {
+	plan := &CleanupPlan{
+		APIVersion:    CleanupPlanAPIVersion,
+		Kind:          CleanupPlanKind,
+		CreatedAt:     time.Now().UTC().Truncate(time.Second),
+		Namespace:     options.Namespace,
+		AllNamespaces: options.AllNamespaces,
+		ProtectHiveMQ: options.ProtectHiveMQ && !options.Force,
+		Volumes:       []PlannedVolume{},
+	}
+	if options.AllNamespaces {
+		plan.Namespace = ""
+	}
+
+	for _, pv := range pvs {
+		action := pvCleanupAction(pv)
+		plan.Volumes = append(plan.Volumes, PlannedVolume{
+			Type:            action.Type,
+			Name:            pv.Name,
+			Namespace:       action.Namespace,
+			UID:             pv.UID,
+			ResourceVersion: pv.ResourceVersion,
+			SizeBytes:       action.Size,
+		})
+	}
+
+	for _, pvc := range pvcs {
+		action := pvcCleanupAction(pvc)
+		planned := PlannedVolume{
+			Type:            action.Type,
+			Name:            pvc.Name,
+			Namespace:       pvc.Namespace,
+			UID:             pvc.UID,
+			ResourceVersion: pvc.ResourceVersion,
+			SizeBytes:       action.Size,
+		}
+		// A claim without a bound volume is still planned; applying it then keeps any volume bound later
+		if pv, err := c.findAssociatedPV(ctx, pvc); err == nil {
+			planned.AssociatedPV = pv.Name
+			planned.AssociatedPVUID = pv.UID
+		}
+		plan.Volumes = append(plan.Volumes, planned)
+	}
+
+	return plan
+}
+
+// ApplyCleanupPlan deletes exactly the volumes of a plan. It refuses to delete anything when a
+// planned volume is gone, was recreated, changed, or is no longer released or orphaned.
+func (c *Cleaner) ApplyCleanupPlan(ctx context.Context, plan *CleanupPlan, options CleanupOptions) (*CleanupResult, error) {
+	if err := plan.Validate(); err != nil {
+		return nil, err
+	}
+
+	analysisResult, err := c.analyzer.AnalyzeVolumes(ctx, AnalysisOptions{
+		Namespace:     plan.Namespace,
+		AllNamespaces: plan.AllNamespaces,
+		ShowReleased:  true,
+		ShowOrphaned:  true,
+		UseColors:     options.UseColors,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze volumes for cleanup: %w", err)
+	}
+
+	var protected map[string]string
+	if (plan.ProtectHiveMQ || options.ProtectHiveMQ) && !options.Force {
+		if protected, err = c.runningHiveMQNamespaces(ctx); err != nil {
+			return nil, fmt.Errorf("failed to discover running HiveMQ installations: %w", err)
+		}
+	}
+
+	releasedPVs := make(map[string]*v1.PersistentVolume, len(analysisResult.ReleasedPVs))
+	for _, pv := range analysisResult.ReleasedPVs {
+		releasedPVs[pv.Name] = pv
+	}
+	orphanedPVCs := make(map[string]*v1.PersistentVolumeClaim, len(analysisResult.OrphanedPVCs))
+	for _, pvc := range analysisResult.OrphanedPVCs {
+		orphanedPVCs[pvc.Namespace+"/"+pvc.Name] = pvc
+	}
+
+	var pvs []*v1.PersistentVolume
+	var pvcs []*v1.PersistentVolumeClaim
+	var drift []PlanDrift
+	for _, planned := range plan.Volumes {
+		var uid types.UID
+		var resourceVersion, namespace, missing string
+		if planned.Type == "PersistentVolume" {
+			pv, ok := releasedPVs[planned.Name]
+			if ok {
+				uid, resourceVersion, namespace = pv.UID, pv.ResourceVersion, pvClaimNamespace(pv)
+				pvs = append(pvs, pv)
+			}
+			missing = "no longer exists or is no longer Released"
+		} else {
+			pvc, ok := orphanedPVCs[planned.Namespace+"/"+planned.Name]
+			if ok {
+				uid, resourceVersion, namespace = pvc.UID, pvc.ResourceVersion, pvc.Namespace
+				pvcs = append(pvcs, pvc)
+			}
+			missing = "no longer exists or is mounted by a pod again"
+		}
+
+		switch {
+		case uid == "":
+			drift = append(drift, PlanDrift{Volume: planned, Reason: missing})
+		case uid != planned.UID:
+			drift = append(drift, PlanDrift{Volume: planned, Reason: "was deleted and recreated"})
+		case resourceVersion != planned.ResourceVersion:
+			drift = append(drift, PlanDrift{Volume: planned, Reason: fmt.Sprintf("was modified (resourceVersion %s, planned %s)", resourceVersion, planned.ResourceVersion)})
+		case isProtectedNamespace(protected, namespace):
+			drift = append(drift, PlanDrift{Volume: planned, Reason: protectedReason(protected, namespace)})
+		}
+	}
+	if len(drift) > 0 {
+		sort.SliceStable(drift, func(i, j int) bool {
+			return plannedVolumeName(drift[i].Volume) < plannedVolumeName(drift[j].Volume)
+		})
+		return nil, &PlanDriftError{Drift: drift}
+	}
+
+	result := &CleanupResult{
+		DeletedPVs:          []string{},
+		DeletedPVCs:         []string{},
+		FailedDeletions:     []CleanupError{},
+		PlannedReleasedPVs:  len(pvs),
+		PlannedOrphanedPVCs: len(pvcs),
+	}
+	if len(pvs) == 0 && len(pvcs) == 0 {
+		if options.UseColors {
+			fmt.Println("The cleanup plan lists no volumes.")
+		}
+		return result, nil
+	}
+
+	if err := c.checkOperatorManaged(ctx, pvs, pvcs, options); err != nil {
+		return nil, err
+	}
+
+	c.createCleanupPlan(result, pvs, pvcs)
+
+	if options.DryRun {
+		c.displayDryRunPreview(result, options)
+		return result, nil
+	}
+
+	if !options.Force && !options.Confirmed {
+		confirmed, err := c.confirmCleanup(result, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get confirmation: %w", err)
+		}
+		if !confirmed {
+			fmt.Println("Cleanup cancelled by user.")
+			return result, nil
+		}
+	}
+
+	if err := c.performCleanup(ctx, result, pvs, pvcs, plan); err != nil {
+		return result, fmt.Errorf("cleanup failed: %w", err)
+	}
+	return result, nil
+}
+
+// plannedAssociatedPV reports whether the plan lists pv as the volume bound to the claim
+func (p *CleanupPlan) plannedAssociatedPV(pvc *v1.PersistentVolumeClaim, pv *v1.PersistentVolume) bool {
+	for _, planned := range p.Volumes {
+		if planned.Type == "PersistentVolumeClaim" && planned.Namespace == pvc.Namespace && planned.Name == pvc.Name {
+			return planned.AssociatedPV == pv.Name && planned.AssociatedPVUID == pv.UID
+		}
+	}
+	return false
+}
+
+func plannedVolumeName(volume PlannedVolume) string {
+	if volume.Type == "PersistentVolumeClaim" {
+		return volume.Namespace + "/" + volume.Name
+	}
+	return volume.Name
+}
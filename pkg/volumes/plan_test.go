@@ -0,0 +1,232 @@
+package volumes
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"kubectl-broker/pkg"
+)
+
+func releasedPV(name string, uid types.UID, resourceVersion string) *v1.PersistentVolume {
+	return &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name, UID: uid, ResourceVersion: resourceVersion},
+		Spec: v1.PersistentVolumeSpec{
+			Capacity:                      v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")},
+			PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimRetain,
+			ClaimRef:                      &v1.ObjectReference{Namespace: "prod", Name: "data-" + name},
+		},
+		Status: v1.PersistentVolumeStatus{Phase: v1.VolumeReleased},
+	}
+}
+
+// orphanedClaim returns a bound claim of prod; it is orphaned as long as no pod mounts it
+func orphanedClaim(name string, uid types.UID, resourceVersion, volume string) *v1.PersistentVolumeClaim {
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: name, UID: uid, ResourceVersion: resourceVersion},
+		Spec: v1.PersistentVolumeClaimSpec{
+			VolumeName: volume,
+			Resources:  v1.VolumeResourceRequirements{Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")}},
+		},
+		Status: v1.PersistentVolumeClaimStatus{Phase: v1.ClaimBound},
+	}
+}
+
+func boundPV(name string, uid types.UID, claim *v1.PersistentVolumeClaim) *v1.PersistentVolume {
+	pv := releasedPV(name, uid, "1")
+	pv.Spec.ClaimRef = &v1.ObjectReference{Namespace: claim.Namespace, Name: claim.Name, UID: claim.UID}
+	pv.Status.Phase = v1.VolumeBound
+	return pv
+}
+
+// runningHiveMQ returns the namespace and a running HiveMQ StatefulSet that --protect-hivemq protects
+func runningHiveMQ(namespace string) []runtime.Object {
+	replicas := int32(3)
+	return []runtime.Object{
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "broker"},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: &replicas,
+				Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "hivemq", Image: "hivemq/hivemq4:4.30.0"}}}},
+			},
+		},
+	}
+}
+
+func cleanupPlan(volumes ...PlannedVolume) *CleanupPlan {
+	return &CleanupPlan{APIVersion: CleanupPlanAPIVersion, Kind: CleanupPlanKind, Namespace: "prod", Volumes: volumes}
+}
+
+func plannedPV(name string, uid types.UID, resourceVersion string) PlannedVolume {
+	return PlannedVolume{Type: "PersistentVolume", Name: name, Namespace: "prod", UID: uid, ResourceVersion: resourceVersion}
+}
+
+func plannedClaim(name string, uid types.UID, resourceVersion string) PlannedVolume {
+	return PlannedVolume{Type: "PersistentVolumeClaim", Name: name, Namespace: "prod", UID: uid, ResourceVersion: resourceVersion}
+}
+
+func TestApplyCleanupPlanRefusesDrift(t *testing.T) {
+	t.Parallel()
+
+	claim := orphanedClaim("data-broker-0", "claim-uid", "5", "")
+	cases := []struct {
+		name       string
+		objects    []runtime.Object
+		planned    PlannedVolume
+		protect    bool
+		wantReason string
+	}{
+		{
+			name:       "volume missing",
+			planned:    plannedPV("pv-1", "pv-uid", "7"),
+			wantReason: "no longer exists or is no longer Released",
+		},
+		{
+			name:       "claim mounted again",
+			objects:    []runtime.Object{claim, podMountingClaim("broker-0", "data-broker-0", v1.PodRunning)},
+			planned:    plannedClaim("data-broker-0", "claim-uid", "5"),
+			wantReason: "no longer exists or is mounted by a pod again",
+		},
+		{
+			name:       "UID replaced",
+			objects:    []runtime.Object{releasedPV("pv-1", "recreated-uid", "7")},
+			planned:    plannedPV("pv-1", "pv-uid", "7"),
+			wantReason: "was deleted and recreated",
+		},
+		{
+			name:       "resourceVersion changed",
+			objects:    []runtime.Object{claim},
+			planned:    plannedClaim("data-broker-0", "claim-uid", "4"),
+			wantReason: "was modified (resourceVersion 5, planned 4)",
+		},
+		{
+			name:       "protected namespace",
+			objects:    append(runningHiveMQ("prod"), releasedPV("pv-1", "pv-uid", "7")),
+			planned:    plannedPV("pv-1", "pv-uid", "7"),
+			protect:    true,
+			wantReason: "Namespace runs HiveMQ StatefulSet broker",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			clientset := fake.NewClientset(tc.objects...)
+			plan := cleanupPlan(tc.planned)
+			plan.ProtectHiveMQ = tc.protect
+
+			_, err := NewCleaner(pkg.NewK8sClientForClients(clientset)).ApplyCleanupPlan(context.Background(), plan, CleanupOptions{Confirmed: true})
+			var driftErr *PlanDriftError
+			if !errors.As(err, &driftErr) {
+				t.Fatalf("expected a PlanDriftError, got %v", err)
+			}
+			if len(driftErr.Drift) != 1 || !strings.Contains(driftErr.Drift[0].Reason, tc.wantReason) {
+				t.Fatalf("drift = %+v, want one entry with %q", driftErr.Drift, tc.wantReason)
+			}
+			for _, action := range clientset.Actions() {
+				if action.GetVerb() == "delete" {
+					t.Fatalf("drifted plan deleted %s", action.GetResource().Resource)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyCleanupPlanKeepsUnplannedAssociatedPV(t *testing.T) {
+	t.Parallel()
+
+	claim := orphanedClaim("data-broker-0", "claim-uid", "5", "pv-late")
+	clientset := fake.NewClientset(claim, boundPV("pv-late", "late-uid", claim))
+
+	// The claim was planned before pv-late was bound to it
+	plan := cleanupPlan(plannedClaim("data-broker-0", "claim-uid", "5"))
+	result, err := NewCleaner(pkg.NewK8sClientForClients(clientset)).ApplyCleanupPlan(context.Background(), plan, CleanupOptions{Confirmed: true})
+	if err != nil {
+		t.Fatalf("ApplyCleanupPlan returned error: %v", err)
+	}
+	if len(result.DeletedPVCs) != 1 || len(result.DeletedPVs) != 0 {
+		t.Fatalf("deleted claims %v and volumes %v, want only the claim", result.DeletedPVCs, result.DeletedPVs)
+	}
+
+	ctx := context.Background()
+	if _, err := clientset.CoreV1().PersistentVolumeClaims("prod").Get(ctx, "data-broker-0", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the planned claim to be deleted, got %v", err)
+	}
+	if _, err := clientset.CoreV1().PersistentVolumes().Get(ctx, "pv-late", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the unplanned volume to be kept: %v", err)
+	}
+}
+
+func TestPlannedAssociatedPV(t *testing.T) {
+	t.Parallel()
+
+	claim := orphanedClaim("data-broker-0", "claim-uid", "5", "pv-1")
+	planned := plannedClaim("data-broker-0", "claim-uid", "5")
+	planned.AssociatedPV, planned.AssociatedPVUID = "pv-1", "pv-uid"
+	plan := cleanupPlan(planned)
+
+	otherClaim := orphanedClaim("data-broker-1", "other-uid", "5", "pv-1")
+	cases := []struct {
+		name  string
+		claim *v1.PersistentVolumeClaim
+		pv    *v1.PersistentVolume
+		want  bool
+	}{
+		{"planned volume", claim, boundPV("pv-1", "pv-uid", claim), true},
+		{"volume recreated", claim, boundPV("pv-1", "recreated-uid", claim), false},
+		{"other volume", claim, boundPV("pv-2", "pv-uid", claim), false},
+		{"claim not planned", otherClaim, boundPV("pv-1", "pv-uid", otherClaim), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := plan.plannedAssociatedPV(tc.claim, tc.pv); got != tc.want {
+				t.Fatalf("plannedAssociatedPV = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCleanupPlanValidate(t *testing.T) {
+	t.Parallel()
+
+	withoutNamespace := plannedClaim("data-broker-0", "claim-uid", "5")
+	withoutNamespace.Namespace = ""
+	unscoped := cleanupPlan()
+	unscoped.Namespace = ""
+	foreign := cleanupPlan()
+	foreign.Kind = "ConfigMap"
+
+	cases := []struct {
+		name    string
+		plan    *CleanupPlan
+		wantErr string
+	}{
+		{"valid", cleanupPlan(plannedPV("pv-1", "pv-uid", "7"), plannedClaim("data-broker-0", "claim-uid", "5")), ""},
+		{"volume without uid", cleanupPlan(plannedPV("pv-1", "", "7")), "lacks a name, uid or resourceVersion"},
+		{"claim without resourceVersion", cleanupPlan(plannedClaim("data-broker-0", "claim-uid", "")), "lacks a name, uid or resourceVersion"},
+		{"claim without namespace", cleanupPlan(withoutNamespace), "lacks a namespace"},
+		{"unsupported type", cleanupPlan(PlannedVolume{Type: "Secret", Name: "s", UID: "u", ResourceVersion: "1"}), "unsupported type"},
+		{"no scope", unscoped, "neither a namespace nor allNamespaces"},
+		{"other kind", foreign, "expected " + CleanupPlanAPIVersion},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.plan.Validate()
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Fatalf("error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
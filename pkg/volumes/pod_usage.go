@@ -0,0 +1,205 @@
+package volumes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"kubectl-broker/pkg"
+)
+
+// DefaultHiveMQDataPath is the broker data directory used when no PVC mount can be detected
+const DefaultHiveMQDataPath = "/opt/hivemq/data"
+
+// Pod disk usage states
+const (
+	PodUsageOK       = "OK"
+	PodUsageWarning  = "WARNING"
+	PodUsageCritical = "CRITICAL"
+	PodUsageError    = "ERROR"
+)
+
+// DefaultPodUsageOptions provides sensible thresholds for pod usage collection
+var DefaultPodUsageOptions = PodUsageOptions{
+	StatefulSet:       HiveMQStatefulSetName,
+	WarnThreshold:     80,
+	CriticalThreshold: 90,
+	Timeout:           30 * time.Second,
+}
+
+// PodUsageCollector collects live disk usage by exec'ing df/du inside broker pods
+type PodUsageCollector struct {
+	k8sClient *pkg.K8sClient
+}
+
+// NewPodUsageCollector creates a new pod usage collector
+func NewPodUsageCollector(k8sClient *pkg.K8sClient) *PodUsageCollector {
+	return &PodUsageCollector{
+		k8sClient: k8sClient,
+	}
+}
+
+// CollectStatefulSetUsage collects disk usage for every pod of the StatefulSet concurrently.
+// Per-pod failures are reported in the result rather than failing the whole collection.
+func (c *PodUsageCollector) CollectStatefulSetUsage(ctx context.Context, options PodUsageOptions) ([]PodDiskUsage, error) {
+	pods, err := c.k8sClient.GetPodsFromStatefulSet(ctx, options.Namespace, options.StatefulSet)
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no pods found for StatefulSet %s in namespace %s", options.StatefulSet, options.Namespace)
+	}
+
+	config := usageWorkerPoolConfig(len(pods), options.Concurrency, options.Timeout)
+	results := make([]PodDiskUsage, len(pods))
+	err = fanOut(ctx, c.k8sClient, config, len(pods), func(taskCtx context.Context, i int) error {
+		results[i] = c.collectPodUsage(taskCtx, pods[i], options)
+		return results[i].Error
+	}, func(i int, err error) {
+		results[i] = PodDiskUsage{PodName: pods[i].Name, Namespace: pods[i].Namespace, Status: PodUsageError, Error: err, DataBytes: -1}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].PodName < results[j].PodName
+	})
+
+	return results, nil
+}
+
+// collectPodUsage runs df (and optionally du) against the data mount of a single pod
+func (c *PodUsageCollector) collectPodUsage(ctx context.Context, pod *v1.Pod, options PodUsageOptions) PodDiskUsage {
+	usage := PodDiskUsage{
+		PodName:   pod.Name,
+		Namespace: pod.Namespace,
+		MountPath: options.MountPath,
+		DataBytes: -1,
+	}
+	if usage.MountPath == "" {
+		usage.MountPath = DetectDataMountPath(pod)
+	}
+
+	if pod.Status.Phase != v1.PodRunning {
+		usage.Status = PodUsageError
+		usage.Error = fmt.Errorf("pod is %s", pod.Status.Phase)
+		return usage
+	}
+
+	output, err := c.k8sClient.ExecCommand(ctx, pod.Namespace, pod.Name, []string{"df", "-Pk", usage.MountPath})
+	if err != nil {
+		usage.Status = PodUsageError
+		usage.Error = fmt.Errorf("df failed: %w", err)
+		return usage
+	}
+
+	if err := parseDfOutput(output, &usage); err != nil {
+		usage.Status = PodUsageError
+		usage.Error = err
+		return usage
+	}
+
+	if !options.SkipDu {
+		// du may hit unreadable files; keep whatever total it manages to report
+		duOutput, err := c.k8sClient.ExecCommand(ctx, pod.Namespace, pod.Name,
+			[]string{"sh", "-c", `du -sk "$1" 2>/dev/null || true`, "sh", usage.MountPath})
+		if err == nil {
+			if kb, ok := parseDuOutput(duOutput); ok {
+				usage.DataBytes = kb * 1024
+			}
+		}
+	}
+
+	usage.Status = classifyUsage(usage.UsagePercent, options.WarnThreshold, options.CriticalThreshold)
+	return usage
+}
+
+// DetectDataMountPath returns the mount path of the first PVC-backed volume in the pod,
+// falling back to the default HiveMQ data directory.
+func DetectDataMountPath(pod *v1.Pod) string {
+	claimVolumes := make(map[string]bool)
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			claimVolumes[volume.Name] = true
+		}
+	}
+
+	for _, container := range pod.Spec.Containers {
+		for _, mount := range container.VolumeMounts {
+			if claimVolumes[mount.Name] {
+				return mount.MountPath
+			}
+		}
+	}
+
+	return DefaultHiveMQDataPath
+}
+
+// parseDfOutput parses POSIX `df -Pk` output into the usage struct
+func parseDfOutput(output string, usage *PodDiskUsage) error {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 2 {
+		return fmt.Errorf("unexpected df output: %q", strings.TrimSpace(output))
+	}
+
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 5 {
+		return fmt.Errorf("unexpected df output: %q", lines[len(lines)-1])
+	}
+
+	total, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse df size: %w", err)
+	}
+	used, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse df used: %w", err)
+	}
+	available, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse df available: %w", err)
+	}
+
+	usage.Filesystem = fields[0]
+	usage.SizeBytes = total * 1024
+	usage.UsedBytes = used * 1024
+	usage.AvailableBytes = available * 1024
+
+	// Match df's own percentage, which is relative to space usable by non-root users
+	if used+available > 0 {
+		usage.UsagePercent = float64(used) / float64(used+available) * 100.0
+	}
+
+	return nil
+}
+
+// parseDuOutput extracts the kilobyte total from `du -sk` output
+func parseDuOutput(output string) (int64, bool) {
+	fields := strings.Fields(strings.TrimSpace(output))
+	if len(fields) == 0 {
+		return 0, false
+	}
+	kb, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return kb, true
+}
+
+// classifyUsage maps a usage percentage onto the configured thresholds
+func classifyUsage(percent, warn, critical float64) string {
+	switch {
+	case critical > 0 && percent >= critical:
+		return PodUsageCritical
+	case warn > 0 && percent >= warn:
+		return PodUsageWarning
+	default:
+		return PodUsageOK
+	}
+}
@@ -0,0 +1,278 @@
+package volumes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// PVCProtectionFinalizer keeps a claim from being deleted while pods still use it
+const PVCProtectionFinalizer = "kubernetes.io/pvc-protection"
+
+// defaultStorageClassAnnotation marks the cluster default StorageClass
+const defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+// PendingGracePeriod is how long a claim may wait for its provisioner before it is reported
+const PendingGracePeriod = 5 * time.Minute
+
+// TerminatingGracePeriod is how long a deleted claim may wait for its finalizers before it is
+// reported; controllers usually release them within seconds
+const TerminatingGracePeriod = 5 * time.Minute
+
+// Problem states reported by the analysis
+const (
+	ProblemPending     = "PENDING"
+	ProblemTerminating = "TERMINATING"
+	ProblemFailed      = "FAILED"
+)
+
+// VolumeProblem is a PVC or PV stuck in a state that needs operator attention
+type VolumeProblem struct {
+	Kind        string // PersistentVolumeClaim or PersistentVolume
+	Name        string
+	Namespace   string // namespace of the claim; the former claim's namespace for PVs
+	State       string // ProblemPending, ProblemTerminating or ProblemFailed
+	Reason      string // root cause
+	Remediation string // next step for the operator
+	Age         time.Duration
+}
+
+// diagnoseProblems reports pending and terminating claims and failed volumes with their root
+// cause. A non-empty namespace limits failed volumes to those last claimed in it.
+func (a *Analyzer) diagnoseProblems(ctx context.Context, namespace string, pvcs []*v1.PersistentVolumeClaim, pvs []*v1.PersistentVolume, result *AnalysisResult) error {
+	var classes map[string]*storagev1.StorageClass
+	for _, pvc := range pvcs {
+		switch {
+		case pvc.DeletionTimestamp != nil:
+			problem, err := a.diagnoseTerminatingClaim(ctx, pvc)
+			if err != nil {
+				return err
+			}
+			if problem != nil {
+				result.Problems = append(result.Problems, *problem)
+			}
+		case pvc.Status.Phase == v1.ClaimPending:
+			if classes == nil {
+				classes = a.getStorageClasses(ctx)
+			}
+			if problem := a.diagnosePendingClaim(ctx, pvc, pvs, classes); problem != nil {
+				result.Problems = append(result.Problems, *problem)
+			}
+		}
+	}
+
+	for _, pv := range pvs {
+		if pv.Status.Phase != v1.VolumeFailed {
+			continue
+		}
+		if namespace != "" && (pv.Spec.ClaimRef == nil || pv.Spec.ClaimRef.Namespace != namespace) {
+			continue
+		}
+		reason := pv.Status.Message
+		if reason == "" {
+			reason = fmt.Sprintf("reclaiming the volume with policy %s failed", pv.Spec.PersistentVolumeReclaimPolicy)
+		}
+		problem := VolumeProblem{
+			Kind:        "PersistentVolume",
+			Name:        pv.Name,
+			State:       ProblemFailed,
+			Reason:      reason,
+			Remediation: fmt.Sprintf("Check the backing disk (kubectl describe pv %s), then delete the volume: kubectl delete pv %s", pv.Name, pv.Name),
+			Age:         time.Since(pv.CreationTimestamp.Time),
+		}
+		if pv.Spec.ClaimRef != nil {
+			problem.Namespace = pv.Spec.ClaimRef.Namespace
+		}
+		result.Problems = append(result.Problems, problem)
+	}
+	return nil
+}
+
+// diagnoseTerminatingClaim explains which finalizer holds a deleted claim; claims are only
+// reported after TerminatingGracePeriod
+func (a *Analyzer) diagnoseTerminatingClaim(ctx context.Context, pvc *v1.PersistentVolumeClaim) (*VolumeProblem, error) {
+	if len(pvc.Finalizers) == 0 || time.Since(pvc.DeletionTimestamp.Time) < TerminatingGracePeriod {
+		return nil, nil
+	}
+	problem := &VolumeProblem{
+		Kind:      "PersistentVolumeClaim",
+		Name:      pvc.Name,
+		Namespace: pvc.Namespace,
+		State:     ProblemTerminating,
+		Age:       time.Since(pvc.DeletionTimestamp.Time),
+	}
+
+	pods, err := activePodsUsingPVC(ctx, a.k8sClient.GetCoreClient(), pvc)
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) > 0 && slices.Contains(pvc.Finalizers, PVCProtectionFinalizer) {
+		problem.Reason = fmt.Sprintf("%s finalizer waits for pods still using the claim: %s", PVCProtectionFinalizer, strings.Join(pods, ", "))
+		problem.Remediation = fmt.Sprintf("Delete the pods or scale down their StatefulSet; the claim is removed once they are gone: kubectl delete pod %s -n %s", strings.Join(pods, " "), pvc.Namespace)
+		return problem, nil
+	}
+
+	problem.Reason = fmt.Sprintf("finalizers %s remain although no running pod uses the claim", strings.Join(pvc.Finalizers, ", "))
+	removed, remaining := splitFinalizers(pvc.Finalizers)
+	switch {
+	case len(removed) == 0:
+		problem.Remediation = fmt.Sprintf("Check the controllers that own the finalizers; they may still be releasing snapshots or storage: kubectl describe pvc %s -n %s", pvc.Name, pvc.Namespace)
+	case len(remaining) > 0:
+		problem.Remediation = fmt.Sprintf("Check the controllers that own %s first, then remove %s: kubectl broker volumes unstick --pvc %s -n %s --force", strings.Join(remaining, ", "), PVCProtectionFinalizer, pvc.Name, pvc.Namespace)
+	default:
+		problem.Remediation = fmt.Sprintf("Remove the finalizer: kubectl broker volumes unstick --pvc %s -n %s", pvc.Name, pvc.Namespace)
+	}
+	return problem, nil
+}
+
+// diagnosePendingClaim finds why a claim is not bound; claims waiting for their provisioner are
+// only reported after PendingGracePeriod
+func (a *Analyzer) diagnosePendingClaim(ctx context.Context, pvc *v1.PersistentVolumeClaim, pvs []*v1.PersistentVolume, classes map[string]*storagev1.StorageClass) *VolumeProblem {
+	problem := &VolumeProblem{
+		Kind:      "PersistentVolumeClaim",
+		Name:      pvc.Name,
+		Namespace: pvc.Namespace,
+		State:     ProblemPending,
+		Age:       time.Since(pvc.CreationTimestamp.Time),
+	}
+
+	if name := pvc.Spec.VolumeName; name != "" {
+		pv := findVolume(pvs, name)
+		switch {
+		case pv == nil:
+			problem.Reason = fmt.Sprintf("requested volume %s does not exist", name)
+			problem.Remediation = "Recreate the claim without spec.volumeName or restore the volume"
+			return problem
+		case pv.Spec.ClaimRef != nil && (pv.Spec.ClaimRef.Namespace != pvc.Namespace || pv.Spec.ClaimRef.Name != pvc.Name):
+			problem.Reason = fmt.Sprintf("requested volume %s is claimed by %s/%s", name, pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name)
+			problem.Remediation = fmt.Sprintf("Release the volume or rebind it: kubectl broker volumes adopt --pv %s -n %s --pvc-name %s", name, pvc.Namespace, pvc.Name)
+			return problem
+		}
+	}
+
+	// classes is nil when StorageClasses cannot be listed; the class checks are skipped then
+	var class *storagev1.StorageClass
+	if classes != nil {
+		switch {
+		case pvc.Spec.StorageClassName == nil:
+			class = defaultStorageClass(classes)
+			if class == nil {
+				problem.Reason = "claim has no StorageClass and the cluster has no default StorageClass"
+				problem.Remediation = "Set spec.storageClassName on the claim or mark a StorageClass as default"
+				return problem
+			}
+		case *pvc.Spec.StorageClassName != "":
+			class = classes[*pvc.Spec.StorageClassName]
+			if class == nil {
+				problem.Reason = fmt.Sprintf("StorageClass %s does not exist", *pvc.Spec.StorageClassName)
+				problem.Remediation = "Create the StorageClass or recreate the claim with an existing one (kubectl get storageclass)"
+				return problem
+			}
+		}
+	}
+
+	// Claims with a selector or with storageClassName "" only bind to existing volumes
+	staticOnly := pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName == ""
+	if (pvc.Spec.Selector != nil || staticOnly) && (pvc.Spec.StorageClassName != nil || class != nil) {
+		var className string
+		if pvc.Spec.StorageClassName != nil {
+			className = *pvc.Spec.StorageClassName
+		} else {
+			className = class.Name
+		}
+		if !hasMatchingAvailableVolume(pvc, className, pvs) {
+			if pvc.Spec.Selector != nil {
+				problem.Reason = fmt.Sprintf("no available volume matches selector %s; claims with a selector are never provisioned dynamically", metav1.FormatLabelSelector(pvc.Spec.Selector))
+				problem.Remediation = "Create a matching volume or drop spec.selector from the claim"
+			} else {
+				problem.Reason = "no available volume without a StorageClass fits the claim; claims with storageClassName \"\" are never provisioned dynamically"
+				problem.Remediation = "Create a matching volume or set a StorageClass on the claim"
+			}
+			return problem
+		}
+	}
+
+	if class != nil && class.VolumeBindingMode != nil && *class.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer {
+		if pods, err := activePodsUsingPVC(ctx, a.k8sClient.GetCoreClient(), pvc); err == nil && len(pods) == 0 {
+			problem.Reason = fmt.Sprintf("StorageClass %s waits for the first pod using the claim (WaitForFirstConsumer)", class.Name)
+			problem.Remediation = "Start the workload that mounts the claim, or delete the claim if it is no longer needed"
+			return problem
+		}
+	}
+
+	if problem.Age < PendingGracePeriod {
+		return nil
+	}
+	problem.Reason = fmt.Sprintf("pending for %s", problem.Age.Round(time.Minute))
+	if class != nil {
+		problem.Reason = fmt.Sprintf("provisioner %s has not provisioned a volume for %s", class.Provisioner, problem.Age.Round(time.Minute))
+	}
+	problem.Remediation = fmt.Sprintf("Check the claim's events and the provisioner logs: kubectl describe pvc %s -n %s", pvc.Name, pvc.Namespace)
+	return problem
+}
+
+// getStorageClasses returns the StorageClasses by name, or nil when they cannot be listed
+func (a *Analyzer) getStorageClasses(ctx context.Context) map[string]*storagev1.StorageClass {
+	list, err := a.k8sClient.GetStorageClient().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		slog.Debug("Skipping StorageClass checks", "error", err)
+		return nil
+	}
+	classes := make(map[string]*storagev1.StorageClass, len(list.Items))
+	for i := range list.Items {
+		classes[list.Items[i].Name] = &list.Items[i]
+	}
+	return classes
+}
+
+func defaultStorageClass(classes map[string]*storagev1.StorageClass) *storagev1.StorageClass {
+	for _, class := range classes {
+		if class.Annotations[defaultStorageClassAnnotation] == "true" {
+			return class
+		}
+	}
+	return nil
+}
+
+// hasMatchingAvailableVolume reports whether an Available PV of className could statically bind the claim
+func hasMatchingAvailableVolume(pvc *v1.PersistentVolumeClaim, className string, pvs []*v1.PersistentVolume) bool {
+	selector := labels.Everything()
+	if pvc.Spec.Selector != nil {
+		parsed, err := metav1.LabelSelectorAsSelector(pvc.Spec.Selector)
+		if err != nil {
+			return false
+		}
+		selector = parsed
+	}
+	requested := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+
+	for _, pv := range pvs {
+		if pv.Status.Phase != v1.VolumeAvailable || pv.Spec.StorageClassName != className {
+			continue
+		}
+		if !selector.Matches(labels.Set(pv.Labels)) {
+			continue
+		}
+		if capacity, ok := pv.Spec.Capacity[v1.ResourceStorage]; ok && capacity.Cmp(requested) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func findVolume(pvs []*v1.PersistentVolume, name string) *v1.PersistentVolume {
+	for _, pv := range pvs {
+		if pv.Name == name {
+			return pv
+		}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package volumes
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"kubectl-broker/pkg"
+)
+
+const snapshotFinalizer = "snapshot.storage.kubernetes.io/pvc-as-source-protection"
+
+func fakeAnalyzer(objects ...runtime.Object) *Analyzer {
+	return NewAnalyzer(pkg.NewK8sClientForClients(fake.NewClientset(objects...)))
+}
+
+func pendingClaim(age time.Duration, className *string) *v1.PersistentVolumeClaim {
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "data-broker-0", CreationTimestamp: metav1.NewTime(time.Now().Add(-age))},
+		Spec: v1.PersistentVolumeClaimSpec{
+			StorageClassName: className,
+			Resources:        v1.VolumeResourceRequirements{Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")}},
+		},
+		Status: v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending},
+	}
+}
+
+func terminatingClaim(age time.Duration, finalizers ...string) *v1.PersistentVolumeClaim {
+	deleted := metav1.NewTime(time.Now().Add(-age))
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "data-broker-0", DeletionTimestamp: &deleted, Finalizers: finalizers},
+		Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimBound},
+	}
+}
+
+func storageClass(name string, mode storagev1.VolumeBindingMode) *storagev1.StorageClass {
+	return &storagev1.StorageClass{
+		ObjectMeta:        metav1.ObjectMeta{Name: name},
+		Provisioner:       "ebs.csi.aws.com",
+		VolumeBindingMode: &mode,
+	}
+}
+
+func podMountingClaim(name, claim string, phase v1.PodPhase) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: name},
+		Spec: v1.PodSpec{Volumes: []v1.Volume{{
+			Name:         "data",
+			VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: claim}},
+		}}},
+		Status: v1.PodStatus{Phase: phase},
+	}
+}
+
+func failedVolume(claimNamespace, message string) *v1.PersistentVolume {
+	return &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-failed"},
+		Spec: v1.PersistentVolumeSpec{
+			PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimDelete,
+			ClaimRef:                      &v1.ObjectReference{Namespace: claimNamespace, Name: "data-broker-0"},
+		},
+		Status: v1.PersistentVolumeStatus{Phase: v1.VolumeFailed, Message: message},
+	}
+}
+
+func TestDiagnoseProblems(t *testing.T) {
+	t.Parallel()
+
+	fast, missing := "fast", "missing"
+	requestsVolume := pendingClaim(time.Minute, &fast)
+	requestsVolume.Spec.VolumeName = "pv-gone"
+
+	cases := []struct {
+		name            string
+		namespace       string
+		pvc             *v1.PersistentVolumeClaim
+		pv              *v1.PersistentVolume
+		objects         []runtime.Object
+		wantState       string // empty when nothing should be reported
+		wantReason      string
+		wantRemediation string
+	}{
+		{
+			name:    "pending within the grace period",
+			pvc:     pendingClaim(time.Minute, &fast),
+			objects: []runtime.Object{storageClass("fast", storagev1.VolumeBindingImmediate)},
+		},
+		{
+			name:            "pending past the grace period",
+			pvc:             pendingClaim(time.Hour, &fast),
+			objects:         []runtime.Object{storageClass("fast", storagev1.VolumeBindingImmediate)},
+			wantState:       ProblemPending,
+			wantReason:      "provisioner ebs.csi.aws.com has not provisioned a volume",
+			wantRemediation: "kubectl describe pvc data-broker-0 -n prod",
+		},
+		{
+			name:       "pending on a missing StorageClass",
+			pvc:        pendingClaim(time.Minute, &missing),
+			objects:    []runtime.Object{storageClass("fast", storagev1.VolumeBindingImmediate)},
+			wantState:  ProblemPending,
+			wantReason: "StorageClass missing does not exist",
+		},
+		{
+			name:       "pending on a missing volume",
+			pvc:        requestsVolume,
+			wantState:  ProblemPending,
+			wantReason: "requested volume pv-gone does not exist",
+		},
+		{
+			name:       "pending until the first consumer starts",
+			pvc:        pendingClaim(time.Minute, &fast),
+			objects:    []runtime.Object{storageClass("fast", storagev1.VolumeBindingWaitForFirstConsumer)},
+			wantState:  ProblemPending,
+			wantReason: "waits for the first pod using the claim",
+		},
+		{
+			name: "terminating within the grace period",
+			pvc:  terminatingClaim(time.Minute, PVCProtectionFinalizer),
+		},
+		{
+			name:            "terminating while a pod still mounts the claim",
+			pvc:             terminatingClaim(time.Hour, PVCProtectionFinalizer),
+			objects:         []runtime.Object{podMountingClaim("broker-0", "data-broker-0", v1.PodRunning)},
+			wantState:       ProblemTerminating,
+			wantReason:      "waits for pods still using the claim: broker-0",
+			wantRemediation: "kubectl delete pod broker-0 -n prod",
+		},
+		{
+			name:            "terminating with only the protection finalizer",
+			pvc:             terminatingClaim(time.Hour, PVCProtectionFinalizer),
+			objects:         []runtime.Object{podMountingClaim("broker-0", "data-broker-0", v1.PodSucceeded)},
+			wantState:       ProblemTerminating,
+			wantReason:      "no running pod uses the claim",
+			wantRemediation: "Remove the finalizer: kubectl broker volumes unstick --pvc data-broker-0 -n prod",
+		},
+		{
+			name:            "terminating with a snapshot finalizer as well",
+			pvc:             terminatingClaim(time.Hour, PVCProtectionFinalizer, snapshotFinalizer),
+			wantState:       ProblemTerminating,
+			wantRemediation: "Check the controllers that own " + snapshotFinalizer + " first",
+		},
+		{
+			name:            "terminating with only a snapshot finalizer",
+			pvc:             terminatingClaim(time.Hour, snapshotFinalizer),
+			wantState:       ProblemTerminating,
+			wantRemediation: "Check the controllers that own the finalizers",
+		},
+		{
+			name:            "failed volume",
+			pv:              failedVolume("prod", "disk detach failed"),
+			wantState:       ProblemFailed,
+			wantReason:      "disk detach failed",
+			wantRemediation: "kubectl delete pv pv-failed",
+		},
+		{
+			name:      "failed volume of another namespace",
+			namespace: "prod",
+			pv:        failedVolume("staging", "disk detach failed"),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				pvcs []*v1.PersistentVolumeClaim
+				pvs  []*v1.PersistentVolume
+			)
+			if tc.pvc != nil {
+				pvcs = append(pvcs, tc.pvc)
+			}
+			if tc.pv != nil {
+				pvs = append(pvs, tc.pv)
+			}
+
+			result := &AnalysisResult{}
+			if err := fakeAnalyzer(tc.objects...).diagnoseProblems(context.Background(), tc.namespace, pvcs, pvs, result); err != nil {
+				t.Fatalf("diagnoseProblems returned error: %v", err)
+			}
+			if tc.wantState == "" {
+				if len(result.Problems) != 0 {
+					t.Fatalf("expected no problems, got %+v", result.Problems)
+				}
+				return
+			}
+			if len(result.Problems) != 1 {
+				t.Fatalf("expected one problem, got %+v", result.Problems)
+			}
+			problem := result.Problems[0]
+			if problem.State != tc.wantState || problem.Namespace != "prod" {
+				t.Errorf("problem = %s in %q, want %s in prod", problem.State, problem.Namespace, tc.wantState)
+			}
+			if !strings.Contains(problem.Reason, tc.wantReason) {
+				t.Errorf("reason = %q, want it to contain %q", problem.Reason, tc.wantReason)
+			}
+			if !strings.Contains(problem.Remediation, tc.wantRemediation) {
+				t.Errorf("remediation = %q, want it to contain %q", problem.Remediation, tc.wantRemediation)
+			}
+		})
+	}
+}
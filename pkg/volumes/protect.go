@@ -0,0 +1,68 @@
+package volumes
+
+import (
+	"context"
+	"fmt"
+	"path"
+)
+
+// ValidatePatterns checks that all include/exclude patterns are valid globs
+func ValidatePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w\n\nPlease either:\n- Use shell glob syntax: *, ?, [a-z]\n- Example: --exclude 'prod-*'", pattern, err)
+		}
+	}
+	return nil
+}
+
+// matchesAny reports whether any pattern matches any of the given values (name, namespace)
+func matchesAny(patterns []string, values ...string) bool {
+	for _, pattern := range patterns {
+		for _, value := range values {
+			if value == "" {
+				continue
+			}
+			if ok, _ := path.Match(pattern, value); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// passesPatterns applies the --include and --exclude filters to a volume
+func passesPatterns(name, namespace string, options CleanupOptions) bool {
+	if len(options.Include) > 0 && !matchesAny(options.Include, name, namespace) {
+		return false
+	}
+	return !matchesAny(options.Exclude, name, namespace)
+}
+
+// runningHiveMQNamespaces returns namespaces with HiveMQ StatefulSets that are not scaled
+// down, mapped to the StatefulSet name. Scaled-down installations are not protected.
+func (c *Cleaner) runningHiveMQNamespaces(ctx context.Context) (map[string]string, error) {
+	installations, err := c.k8sClient.DiscoverInstallations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make(map[string]string)
+	for _, installation := range installations {
+		if installation.Replicas > 0 {
+			namespaces[installation.Namespace] = installation.StatefulSet
+		}
+	}
+	return namespaces, nil
+}
+
+// isProtectedNamespace reports whether a namespace is in the protected set
+func isProtectedNamespace(protected map[string]string, namespace string) bool {
+	_, ok := protected[namespace]
+	return ok && namespace != ""
+}
+
+// protectedReason explains why a planned action was skipped
+func protectedReason(protected map[string]string, namespace string) string {
+	return fmt.Sprintf("Namespace runs HiveMQ StatefulSet %s", protected[namespace])
+}
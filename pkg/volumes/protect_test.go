@@ -0,0 +1,131 @@
+package volumes
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"kubectl-broker/pkg"
+)
+
+func TestPassesPatterns(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		volume    string
+		namespace string
+		include   []string
+		exclude   []string
+		want      bool
+	}{
+		{"no patterns", "data-broker-0", "prod", nil, nil, true},
+		{"include by name", "data-broker-0", "prod", []string{"data-broker-*"}, nil, true},
+		{"include by namespace", "pvc-1234", "prod-eu", []string{"prod-*"}, nil, true},
+		{"include matches neither", "pvc-1234", "staging", []string{"prod-*", "data-*"}, nil, false},
+		{"include without a namespace", "pvc-1234", "", []string{"prod"}, nil, false},
+		{"exclude by name", "data-broker-0", "staging", nil, []string{"data-broker-*"}, false},
+		{"exclude by namespace", "pvc-1234", "prod-eu", nil, []string{"prod-*"}, false},
+		{"exclude wins over include", "data-broker-0", "prod", []string{"data-*"}, []string{"prod"}, false},
+		{"exclude matches neither", "pvc-1234", "staging", nil, []string{"prod-*"}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			options := CleanupOptions{Include: tc.include, Exclude: tc.exclude}
+			if got := passesPatterns(tc.volume, tc.namespace, options); got != tc.want {
+				t.Fatalf("passesPatterns(%q, %q) = %v, want %v", tc.volume, tc.namespace, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReleasedPVUsesFormerClaimNamespace(t *testing.T) {
+	t.Parallel()
+
+	pv := releasedPV("pv-1", "pv-uid", "7")
+	unclaimed := releasedPV("pv-2", "pv-uid-2", "7")
+	unclaimed.Spec.ClaimRef = nil
+
+	cleaner := &Cleaner{}
+	if cleaner.shouldCleanupPV(pv, CleanupOptions{Exclude: []string{"prod"}}) {
+		t.Error("--exclude prod kept a volume released by a claim in prod")
+	}
+	if !cleaner.shouldCleanupPV(pv, CleanupOptions{Include: []string{"prod"}}) {
+		t.Error("--include prod skipped a volume released by a claim in prod")
+	}
+
+	result := &CleanupResult{}
+	kept, _ := cleaner.excludeProtected(result, []*v1.PersistentVolume{pv, unclaimed}, nil, map[string]string{"prod": "broker"})
+	if len(kept) != 1 || kept[0].Name != "pv-2" {
+		t.Fatalf("kept %v, want only the volume without a former claim", kept)
+	}
+	if len(result.Protected) != 1 || result.Protected[0].Name != "pv-1" || result.Protected[0].Namespace != "prod" {
+		t.Fatalf("protected = %+v, want pv-1 attributed to prod", result.Protected)
+	}
+}
+
+func TestCleanupVolumesProtectHiveMQ(t *testing.T) {
+	t.Parallel()
+
+	stagingPV := releasedPV("pv-staging", "staging-uid", "3")
+	stagingPV.Spec.ClaimRef.Namespace = "staging"
+	objects := append(runningHiveMQ("prod"),
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "staging"}},
+		releasedPV("pv-prod", "prod-uid", "3"),
+		stagingPV,
+		orphanedClaim("data-broker-9", "claim-uid", "5", ""),
+	)
+
+	cases := []struct {
+		name          string
+		protect       bool
+		force         bool
+		wantCleaned   []string
+		wantProtected []string
+	}{
+		{"without protection", false, false, []string{"pv-prod", "pv-staging", "data-broker-9"}, nil},
+		{"protect-hivemq", true, false, []string{"pv-staging"}, []string{"pv-prod", "data-broker-9"}},
+		{"protect-hivemq with force", true, true, []string{"pv-prod", "pv-staging", "data-broker-9"}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cleaner := NewCleaner(pkg.NewK8sClientForClients(fake.NewClientset(slices.Clone(objects)...)))
+			result, err := cleaner.CleanupVolumes(context.Background(), CleanupOptions{
+				AllNamespaces: true,
+				DryRun:        true,
+				ProtectHiveMQ: tc.protect,
+				Force:         tc.force,
+			})
+			if err != nil {
+				t.Fatalf("CleanupVolumes returned error: %v", err)
+			}
+
+			if got := actionNames(result.DryRunPreview); !slices.Equal(got, tc.wantCleaned) {
+				t.Errorf("cleanup candidates = %v, want %v", got, tc.wantCleaned)
+			}
+			if got := actionNames(result.Protected); !slices.Equal(got, tc.wantProtected) {
+				t.Errorf("protected = %v, want %v", got, tc.wantProtected)
+			}
+			for _, action := range result.Protected {
+				if action.Namespace != "prod" {
+					t.Errorf("%s %s protected in namespace %q, want prod", action.Type, action.Name, action.Namespace)
+				}
+			}
+			if result.Plan.ProtectHiveMQ != (tc.protect && !tc.force) {
+				t.Errorf("plan ProtectHiveMQ = %v, want %v", result.Plan.ProtectHiveMQ, tc.protect && !tc.force)
+			}
+		})
+	}
+}
+
+func actionNames(actions []CleanupAction) []string {
+	var names []string
+	for _, action := range actions {
+		names = append(names, action.Name)
+	}
+	return names
+}
@@ -0,0 +1,36 @@
+package volumes
+
+import "sort"
+
+// HasHiveMQ reports whether the namespace looks like a HiveMQ installation: a HiveMQ Cloud
+// namespace or one holding volumes that follow the HiveMQ naming patterns
+func (s *NamespaceVolumeStats) HasHiveMQ() bool {
+	return s.IsHiveMQNamespace || s.HiveMQVolumes > 0
+}
+
+// NamespaceReport returns the per-namespace statistics of an analysis, the namespaces with the
+// most reclaimable storage first
+func NamespaceReport(result *AnalysisResult) []NamespaceVolumeStats {
+	report := make([]NamespaceVolumeStats, 0, len(result.NamespaceStats))
+	for _, stats := range result.NamespaceStats {
+		report = append(report, *stats)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].TotalReclaimable != report[j].TotalReclaimable {
+			return report[i].TotalReclaimable > report[j].TotalReclaimable
+		}
+		return report[i].Namespace < report[j].Namespace
+	})
+	return report
+}
+
+// ReclaimableNamespaces counts the namespaces with released or orphaned volumes
+func ReclaimableNamespaces(result *AnalysisResult) int {
+	count := 0
+	for _, stats := range result.NamespaceStats {
+		if stats.ReleasedPVs > 0 || stats.OrphanedPVCs > 0 {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,93 @@
+package volumes
+
+import (
+	"math"
+	"time"
+)
+
+// UsageTrend is the growth of a pod's data volume across repeated usage samples
+type UsageTrend struct {
+	PodName string
+	// Samples is the number of successful samples the trend is based on
+	Samples int
+	// DeltaBytes is the change of the used bytes since the previous sample
+	DeltaBytes int64
+	// BytesPerHour is the average growth since the first sample
+	BytesPerHour float64
+	// DaysUntilFull projects when the volume runs out of space at BytesPerHour; -1 when the
+	// volume is not growing or there are not yet two samples
+	DaysUntilFull float64
+}
+
+// Growing reports whether the trend has a projection to a full volume
+func (t UsageTrend) Growing() bool {
+	return t.DaysUntilFull >= 0
+}
+
+type usageSample struct {
+	at   time.Time
+	used int64
+}
+
+// UsageTracker derives usage trends from repeated CollectStatefulSetUsage results
+type UsageTracker struct {
+	first    map[string]usageSample
+	previous map[string]usageSample
+	samples  map[string]int
+}
+
+// NewUsageTracker creates an empty tracker
+func NewUsageTracker() *UsageTracker {
+	return &UsageTracker{
+		first:    make(map[string]usageSample),
+		previous: make(map[string]usageSample),
+		samples:  make(map[string]int),
+	}
+}
+
+// Observe records the usage sampled at a point in time and returns the trend of every pod in
+// results, in the same order. Failed samples are skipped, so a pod keeps its trend across a
+// temporary exec error.
+func (t *UsageTracker) Observe(at time.Time, results []PodDiskUsage) []UsageTrend {
+	trends := make([]UsageTrend, len(results))
+	for i, usage := range results {
+		trend := UsageTrend{PodName: usage.PodName, DaysUntilFull: -1}
+		if usage.Error == nil {
+			trend = t.observe(at, usage)
+		} else {
+			trend.Samples = t.samples[usage.PodName]
+		}
+		trends[i] = trend
+	}
+	return trends
+}
+
+func (t *UsageTracker) observe(at time.Time, usage PodDiskUsage) UsageTrend {
+	sample := usageSample{at: at, used: usage.UsedBytes}
+	first, seen := t.first[usage.PodName]
+	if !seen {
+		first = sample
+		t.first[usage.PodName] = sample
+	}
+	previous, hasPrevious := t.previous[usage.PodName]
+	t.previous[usage.PodName] = sample
+	t.samples[usage.PodName]++
+
+	trend := UsageTrend{PodName: usage.PodName, Samples: t.samples[usage.PodName], DaysUntilFull: -1}
+	if hasPrevious {
+		trend.DeltaBytes = sample.used - previous.used
+	}
+	if hours := at.Sub(first.at).Hours(); hours > 0 {
+		trend.BytesPerHour = float64(sample.used-first.used) / hours
+	}
+	trend.DaysUntilFull = daysUntilFull(usage.AvailableBytes, trend.BytesPerHour)
+	return trend
+}
+
+// daysUntilFull projects how long the available space lasts at a growth rate
+func daysUntilFull(available int64, bytesPerHour float64) float64 {
+	if bytesPerHour <= 0 {
+		return -1
+	}
+	return math.Max(float64(available), 0) / bytesPerHour / 24
+}
@@ -5,30 +5,74 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+
+	"kubectl-broker/pkg"
 )
 
 // AnalysisOptions contains options for volume analysis
 type AnalysisOptions struct {
-	Namespace     string        // Target namespace (empty for current context)
-	AllNamespaces bool          // Analyze across all namespaces
-	MinAge        time.Duration // Only include volumes older than this
-	MinSize       string        // Only include volumes larger than this
-	ShowReleased  bool          // Show only released PVs
-	ShowOrphaned  bool          // Show only orphaned PVCs
-	ShowAll       bool          // Show all volumes including bound ones
-	ShowDetailed  bool          // Show detailed usage information (enables Node Stats API)
-	UseColors     bool          // Use color output
+	Namespace     string            // Target namespace (empty for current context)
+	AllNamespaces bool              // Analyze across all namespaces
+	MinAge        time.Duration     // Only include volumes older than this
+	MinSize       resource.Quantity // Only include volumes at least this large (zero for all)
+	ShowReleased  bool              // Show only released PVs
+	ShowOrphaned  bool              // Show only orphaned PVCs
+	ShowAll       bool              // Show all volumes including bound ones
+	ShowDetailed  bool              // Show detailed usage information (enables Node Stats API)
+	UseColors     bool              // Use color output
+	Selector      string            // Only include PVs and PVCs matching this label selector
+	StorageClass  string            // Only include PVs and PVCs of this StorageClass
+	CostRates     CostRates         // Price per GB and month by StorageClass for savings estimates
+
+	AccessMode v1.PersistentVolumeAccessMode // Only include PVs and PVCs with this access mode (empty for all)
+
+	UsageConcurrency int           // Maximum parallel node stats requests in detailed mode (0 uses the default)
+	UsageTimeout     time.Duration // Timeout of each node stats request in detailed mode
 }
 
 // CleanupOptions contains options for volume cleanup
 type CleanupOptions struct {
-	Namespace     string        // Target namespace (empty for current context)
-	AllNamespaces bool          // Cleanup across all namespaces
-	MinAge        time.Duration // Only delete volumes older than this
-	MinSize       string        // Only delete volumes larger than this
-	DryRun        bool          // Preview only, don't actually delete
-	Force         bool          // Skip confirmation prompts
-	UseColors     bool          // Use color output
+	Namespace     string            // Target namespace (empty for current context)
+	AllNamespaces bool              // Cleanup across all namespaces
+	MinAge        time.Duration     // Only delete volumes older than this
+	MinSize       resource.Quantity // Only delete volumes at least this large (zero for all)
+	DryRun        bool              // Preview only, don't actually delete
+	Force         bool              // Skip confirmation prompts and HiveMQ protection
+	UseColors     bool              // Use color output
+	Include       []string          // Only delete volumes whose name or namespace matches one of these globs
+	Exclude       []string          // Never delete volumes whose name or namespace matches one of these globs
+	ProtectHiveMQ bool              // Keep volumes in namespaces with running HiveMQ StatefulSets
+	Confirmed     bool              // Deletion already confirmed non-interactively (--confirm-phrase)
+	Selector      string            // Only delete PVs and PVCs matching this label selector
+
+	ForceOperatorManaged bool // Delete volumes managed by the HiveMQ operator instead of refusing
+}
+
+// PodUsageOptions contains options for live disk usage collection inside broker pods
+type PodUsageOptions struct {
+	Namespace         string        // Target namespace
+	StatefulSet       string        // Broker StatefulSet whose pods are inspected
+	MountPath         string        // Data mount path (empty to detect from the pod spec)
+	WarnThreshold     float64       // Usage percentage that triggers WARNING
+	CriticalThreshold float64       // Usage percentage that triggers CRITICAL
+	Timeout           time.Duration // Per-pod exec timeout
+	Concurrency       int           // Maximum parallel pod execs (0 uses the worker pool default)
+	SkipDu            bool          // Skip the du scan of the data directory
+}
+
+// PodDiskUsage represents df/du results collected from inside a broker pod
+type PodDiskUsage struct {
+	PodName        string
+	Namespace      string
+	MountPath      string
+	Filesystem     string
+	SizeBytes      int64
+	UsedBytes      int64
+	AvailableBytes int64
+	UsagePercent   float64
+	DataBytes      int64 // du of the mount path, -1 when not collected
+	Status         string
+	Error          error
 }
 
 // VolumeInfo represents a volume with analysis metadata
@@ -115,11 +159,17 @@ type AnalysisResult struct {
 	NamespaceStats          map[string]*NamespaceVolumeStats
 	HiveMQVolumeCount       int
 	Recommendations         []string
+	StorageClasses          []StorageClassStats // per-class aggregation, most reclaimable first
+	EstimatedMonthlyCost    float64             // priced classes only, see AnalysisOptions.CostRates
+	EstimatedMonthlySavings float64
+	Problems                []VolumeProblem // pending, terminating and failed volumes with their root cause
 }
 
 // NamespaceVolumeStats contains volume statistics for a namespace
 type NamespaceVolumeStats struct {
 	Namespace         string
+	TotalPVCs         int
+	BoundPVCs         int
 	ReleasedPVs       int
 	OrphanedPVCs      int
 	TotalReclaimable  int64
@@ -136,11 +186,14 @@ type CleanupResult struct {
 	TotalReclaimedStorage   int64
 	PlannedReclaimedStorage int64
 	DryRunPreview           []CleanupAction
+	Deleted                 []CleanupAction // every deleted volume with its namespace, for the audit trail
+	Protected               []CleanupAction
 	PlannedReleasedPVs      int
 	PlannedOrphanedPVCs     int
 	DeletedReleasedPVs      int
 	DeletedOrphanedPVCs     int
 	AssociatedPVsDeleted    int
+	Plan                    *CleanupPlan // dry runs only: the candidates pinned for --export-plan
 }
 
 // CleanupAction represents an action that would be taken during cleanup
@@ -186,12 +239,7 @@ func IsHiveMQVolume(name, namespace string) bool {
 
 // isUUIDNamespace checks if namespace follows UUID pattern (HiveMQ Cloud)
 func isUUIDNamespace(namespace string) bool {
-	// HiveMQ Cloud uses UUID namespaces like: 07379b05-4e05-46bf-b5d3-b4441252a8d1
-	if len(namespace) == 36 && namespace[8] == '-' && namespace[13] == '-' &&
-		namespace[18] == '-' && namespace[23] == '-' {
-		return true
-	}
-	return false
+	return pkg.IsCloudNamespace(namespace)
 }
 
 // ShouldDeleteVolume determines if a volume should be deleted based on criteria
@@ -207,12 +255,14 @@ func ShouldDeleteVolume(info VolumeInfo, options CleanupOptions) bool {
 	}
 
 	// Check minimum size requirement
-	if options.MinSize != "" {
-		minSize, err := resource.ParseQuantity(options.MinSize)
-		if err == nil && info.Size.Cmp(minSize) < 0 {
-			return false
-		}
+	if belowMinSize(info.Size, options.MinSize) {
+		return false
 	}
 
 	return true
 }
+
+// belowMinSize reports whether a volume of the given size is excluded by a minimum size
+func belowMinSize(size, minSize resource.Quantity) bool {
+	return !minSize.IsZero() && size.Cmp(minSize) < 0
+}
@@ -0,0 +1,156 @@
+package volumes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"kubectl-broker/pkg"
+)
+
+// UnstickOptions contains options for releasing a claim stuck in Terminating
+type UnstickOptions struct {
+	Namespace string // Namespace of the claim
+	PVCName   string // Claim stuck in Terminating
+	DryRun    bool   // Run the checks without removing finalizers
+	Force     bool   // Release the claim although finalizers of other controllers remain
+}
+
+// UnstickResult describes the finalizers removed from a terminating claim
+type UnstickResult struct {
+	Namespace  string
+	PVCName    string
+	VolumeName string   // bound volume, reclaimed according to its policy once the claim is gone
+	Finalizers []string // finalizers removed (or to be removed with DryRun)
+	Remaining  []string // finalizers of other controllers left on the claim with Force
+	DryRun     bool
+}
+
+// Unsticker removes finalizers from claims whose deletion can no longer make progress
+type Unsticker struct {
+	k8sClient *pkg.K8sClient
+}
+
+// NewUnsticker creates a new claim unsticker
+func NewUnsticker(k8sClient *pkg.K8sClient) *Unsticker {
+	return &Unsticker{k8sClient: k8sClient}
+}
+
+// Unstick removes the kubernetes.io/pvc-protection finalizer from a terminating claim once no
+// running pod uses it. Finalizers of other controllers are never removed; claims carrying them
+// are refused unless Force is set. The patch carries the claim's resourceVersion, so it fails if
+// the claim changed meanwhile. A failed patch returns the result along with the error.
+func (u *Unsticker) Unstick(ctx context.Context, options UnstickOptions) (*UnstickResult, error) {
+	core := u.k8sClient.GetCoreClient()
+
+	pvc, err := core.PersistentVolumeClaims(options.Namespace).Get(ctx, options.PVCName, metav1.GetOptions{})
+	if err != nil {
+		return nil, pkg.NewKubernetesError("get_persistent_volume_claim", options.PVCName, err)
+	}
+	if err := ValidateUnstickable(pvc, options.Force); err != nil {
+		return nil, err
+	}
+
+	pods, err := activePodsUsingPVC(ctx, core, pvc)
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) > 0 {
+		return nil, fmt.Errorf("claim %s/%s is still used by pods %s; removing its finalizers could lose data that is still being written\n\nPlease either:\n- Delete the pods first: kubectl delete pod %s -n %s\n- Scale down the StatefulSet that owns them", pvc.Namespace, pvc.Name, strings.Join(pods, ", "), strings.Join(pods, " "), pvc.Namespace)
+	}
+
+	removed, remaining := splitFinalizers(pvc.Finalizers)
+	result := &UnstickResult{
+		Namespace:  pvc.Namespace,
+		PVCName:    pvc.Name,
+		VolumeName: pvc.Spec.VolumeName,
+		Finalizers: removed,
+		Remaining:  remaining,
+		DryRun:     options.DryRun,
+	}
+	if options.DryRun {
+		return result, nil
+	}
+
+	if err := pkg.GuardMutation(ctx, "unstick", pvc.Name); err != nil {
+		return nil, err
+	}
+
+	// A merge patch replaces the whole list; an empty list rather than null keeps the field valid
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"finalizers":      append([]string{}, remaining...),
+			"resourceVersion": pvc.ResourceVersion,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build finalizer patch: %w", err)
+	}
+	if _, err := core.PersistentVolumeClaims(pvc.Namespace).Patch(ctx, pvc.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return result, pkg.NewKubernetesError("patch_persistent_volume_claim", pvc.Name, err)
+	}
+	return result, nil
+}
+
+// ValidateUnstickable checks that a claim is being deleted and held by the
+// kubernetes.io/pvc-protection finalizer. Finalizers of other controllers guard snapshots or
+// backend storage, so claims carrying them are refused unless force is set.
+func ValidateUnstickable(pvc *v1.PersistentVolumeClaim, force bool) error {
+	if pvc.DeletionTimestamp == nil {
+		return fmt.Errorf("claim %s/%s is not being deleted; finalizers only hold claims that are Terminating\n\nPlease either:\n- Delete the claim first: kubectl delete pvc %s -n %s\n- Clean up orphaned claims: kubectl broker volumes cleanup --dry-run", pvc.Namespace, pvc.Name, pvc.Name, pvc.Namespace)
+	}
+	if len(pvc.Finalizers) == 0 {
+		return fmt.Errorf("claim %s/%s has no finalizers left and will be removed by Kubernetes", pvc.Namespace, pvc.Name)
+	}
+
+	removed, remaining := splitFinalizers(pvc.Finalizers)
+	if len(removed) == 0 {
+		return fmt.Errorf("claim %s/%s is held by finalizers %s of other controllers; unstick only removes %s\n\nPlease either:\n- Check the controllers that own the finalizers: kubectl describe pvc %s -n %s\n- Wait until they have released the snapshots or storage that depend on the claim", pvc.Namespace, pvc.Name, strings.Join(remaining, ", "), PVCProtectionFinalizer, pvc.Name, pvc.Namespace)
+	}
+	if len(remaining) > 0 && !force {
+		return fmt.Errorf("claim %s/%s also carries finalizers %s of other controllers; they may still guard snapshots or backend storage\n\nPlease either:\n- Check the controllers that own them: kubectl describe pvc %s -n %s\n- Remove only %s and leave the others in place: --force", pvc.Namespace, pvc.Name, strings.Join(remaining, ", "), pvc.Name, pvc.Namespace, PVCProtectionFinalizer)
+	}
+	return nil
+}
+
+// splitFinalizers separates the kubernetes.io/pvc-protection finalizer, which unstick removes,
+// from the finalizers of other controllers, which stay on the claim
+func splitFinalizers(finalizers []string) (removed, remaining []string) {
+	for _, finalizer := range finalizers {
+		if finalizer == PVCProtectionFinalizer {
+			removed = append(removed, finalizer)
+		} else {
+			remaining = append(remaining, finalizer)
+		}
+	}
+	return removed, remaining
+}
+
+// activePodsUsingPVC lists the pods mounting the claim that have not terminated; these are the
+// pods the kubernetes.io/pvc-protection finalizer waits for
+func activePodsUsingPVC(ctx context.Context, core corev1client.PodsGetter, pvc *v1.PersistentVolumeClaim) ([]string, error) {
+	podList, err := core.Pods(pvc.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods in namespace %s: %w", pvc.Namespace, err)
+	}
+
+	var podNames []string
+	for _, pod := range podList.Items {
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == pvc.Name {
+				podNames = append(podNames, pod.Name)
+				break
+			}
+		}
+	}
+	return podNames, nil
+}
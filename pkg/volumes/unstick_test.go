@@ -0,0 +1,95 @@
+package volumes
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"kubectl-broker/pkg"
+)
+
+func TestValidateUnstickable(t *testing.T) {
+	t.Parallel()
+
+	notDeleted := terminatingClaim(time.Hour, PVCProtectionFinalizer)
+	notDeleted.DeletionTimestamp = nil
+
+	cases := []struct {
+		name    string
+		pvc     *v1.PersistentVolumeClaim
+		force   bool
+		wantErr string
+	}{
+		{"only the protection finalizer", terminatingClaim(time.Hour, PVCProtectionFinalizer), false, ""},
+		{"not being deleted", notDeleted, false, "is not being deleted"},
+		{"no finalizers left", terminatingClaim(time.Hour), false, "has no finalizers left"},
+		{"other finalizers without force", terminatingClaim(time.Hour, PVCProtectionFinalizer, snapshotFinalizer), false, "--force"},
+		{"other finalizers with force", terminatingClaim(time.Hour, PVCProtectionFinalizer, snapshotFinalizer), true, ""},
+		{"only other finalizers", terminatingClaim(time.Hour, snapshotFinalizer), true, "unstick only removes " + PVCProtectionFinalizer},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateUnstickable(tc.pvc, tc.force)
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Fatalf("error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestSplitFinalizers(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name          string
+		finalizers    []string
+		wantRemoved   []string
+		wantRemaining []string
+	}{
+		{"protection only", []string{PVCProtectionFinalizer}, []string{PVCProtectionFinalizer}, nil},
+		{"protection and snapshot", []string{snapshotFinalizer, PVCProtectionFinalizer, "example.com/backup"}, []string{PVCProtectionFinalizer}, []string{snapshotFinalizer, "example.com/backup"}},
+		{"third-party only", []string{snapshotFinalizer}, nil, []string{snapshotFinalizer}},
+		{"none", nil, nil, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			removed, remaining := splitFinalizers(tc.finalizers)
+			if !slices.Equal(removed, tc.wantRemoved) || !slices.Equal(remaining, tc.wantRemaining) {
+				t.Fatalf("splitFinalizers(%v) = %v, %v; want %v, %v", tc.finalizers, removed, remaining, tc.wantRemoved, tc.wantRemaining)
+			}
+		})
+	}
+}
+
+func TestUnstickKeepsOtherFinalizers(t *testing.T) {
+	t.Parallel()
+
+	claim := terminatingClaim(time.Hour, PVCProtectionFinalizer, snapshotFinalizer)
+	claim.ResourceVersion = "7"
+	clientset := fake.NewClientset(claim)
+
+	result, err := NewUnsticker(pkg.NewK8sClientForClients(clientset)).Unstick(context.Background(), UnstickOptions{Namespace: "prod", PVCName: "data-broker-0", Force: true})
+	if err != nil {
+		t.Fatalf("Unstick returned error: %v", err)
+	}
+	if !slices.Equal(result.Finalizers, []string{PVCProtectionFinalizer}) || !slices.Equal(result.Remaining, []string{snapshotFinalizer}) {
+		t.Fatalf("result removed %v and kept %v", result.Finalizers, result.Remaining)
+	}
+
+	patched, err := clientset.CoreV1().PersistentVolumeClaims("prod").Get(context.Background(), "data-broker-0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get claim: %v", err)
+	}
+	if !slices.Equal(patched.Finalizers, []string{snapshotFinalizer}) {
+		t.Fatalf("claim finalizers = %v, want only %s", patched.Finalizers, snapshotFinalizer)
+	}
+}
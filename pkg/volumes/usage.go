@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -11,6 +14,17 @@ import (
 	"kubectl-broker/pkg"
 )
 
+// DefaultUsageCollectorOptions query up to the worker pool's default number of nodes at once
+var DefaultUsageCollectorOptions = UsageCollectorOptions{
+	Timeout: 30 * time.Second,
+}
+
+// UsageCollectorOptions bound the fan-out of node stats requests
+type UsageCollectorOptions struct {
+	Concurrency int           // Maximum parallel requests (0 uses the worker pool default)
+	Timeout     time.Duration // Timeout of each node stats request
+}
+
 // VolumeUsage represents the usage statistics for a volume
 type VolumeUsage struct {
 	VolumeName     string
@@ -139,43 +153,87 @@ type RuntimeUsage struct {
 // VolumeUsageCollector collects volume usage statistics using Node Stats API
 type VolumeUsageCollector struct {
 	k8sClient *pkg.K8sClient
+	options   UsageCollectorOptions
 }
 
 // NewVolumeUsageCollector creates a new volume usage collector
 func NewVolumeUsageCollector(k8sClient *pkg.K8sClient) *VolumeUsageCollector {
+	return NewVolumeUsageCollectorWithOptions(k8sClient, DefaultUsageCollectorOptions)
+}
+
+// NewVolumeUsageCollectorWithOptions creates a volume usage collector with custom concurrency and timeout
+func NewVolumeUsageCollectorWithOptions(k8sClient *pkg.K8sClient, options UsageCollectorOptions) *VolumeUsageCollector {
 	return &VolumeUsageCollector{
 		k8sClient: k8sClient,
+		options:   options,
 	}
 }
 
-// GetVolumeUsage retrieves volume usage statistics for PVCs in a namespace
+// GetVolumeUsage retrieves volume usage statistics for PVCs in a namespace.
+// Nodes are queried concurrently; failing nodes are logged and skipped.
 func (c *VolumeUsageCollector) GetVolumeUsage(ctx context.Context, namespace string) (map[string]*VolumeUsage, error) {
-	usage := make(map[string]*VolumeUsage)
-
-	// Get all nodes in the cluster
-	nodeList, err := c.k8sClient.GetCoreClient().Nodes().List(ctx, metav1.ListOptions{})
+	nodeNames, err := c.nodesToQuery(ctx, namespace)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list nodes: %w", err)
+		return nil, err
 	}
 
-	// Get stats from each node
-	for _, node := range nodeList.Items {
-		nodeUsage, err := c.getNodeVolumeStats(ctx, node.Name, namespace)
+	usage := make(map[string]*VolumeUsage)
+	var mu sync.Mutex
+	config := usageWorkerPoolConfig(len(nodeNames), c.options.Concurrency, c.options.Timeout)
+	err = fanOut(ctx, c.k8sClient, config, len(nodeNames), func(taskCtx context.Context, i int) error {
+		nodeUsage, err := c.getNodeVolumeStats(taskCtx, nodeNames[i], namespace)
 		if err != nil {
-			// Log error but continue with other nodes
-			fmt.Printf("Warning: failed to get stats from node %s: %v\n", node.Name, err)
-			continue
+			slog.Warn("Failed to get volume stats from node", "node", nodeNames[i], "error", err)
+			return err
 		}
 
-		// Merge node usage data
+		mu.Lock()
+		defer mu.Unlock()
 		for pvcName, volumeUsage := range nodeUsage {
 			usage[pvcName] = volumeUsage
 		}
+		return nil
+	}, func(i int, err error) {
+		slog.Warn("Failed to schedule volume stats request", "node", nodeNames[i], "error", err)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return usage, nil
 }
 
+// nodesToQuery returns the nodes whose kubelet may report volumes of the namespace:
+// only nodes running its pods for a single namespace, every node otherwise
+func (c *VolumeUsageCollector) nodesToQuery(ctx context.Context, namespace string) ([]string, error) {
+	if namespace == "" {
+		nodeList, err := c.k8sClient.GetCoreClient().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list nodes: %w", err)
+		}
+		names := make([]string, 0, len(nodeList.Items))
+		for _, node := range nodeList.Items {
+			names = append(names, node.Name)
+		}
+		return names, nil
+	}
+
+	podList, err := c.k8sClient.GetCoreClient().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+	}
+	seen := make(map[string]bool)
+	var names []string
+	for _, pod := range podList.Items {
+		if pod.Spec.NodeName != "" && !seen[pod.Spec.NodeName] {
+			seen[pod.Spec.NodeName] = true
+			names = append(names, pod.Spec.NodeName)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
 // getNodeVolumeStats retrieves volume statistics from a specific node
 func (c *VolumeUsageCollector) getNodeVolumeStats(ctx context.Context, nodeName, namespace string) (map[string]*VolumeUsage, error) {
 	usage := make(map[string]*VolumeUsage)
@@ -248,6 +306,64 @@ func (c *VolumeUsageCollector) GetAllVolumeUsage(ctx context.Context) (map[strin
 	return c.GetVolumeUsage(ctx, "")
 }
 
+// usageWorkerPoolConfig sizes a worker pool for count requests, capped at concurrency when set
+func usageWorkerPoolConfig(count, concurrency int, timeout time.Duration) pkg.WorkerPoolConfig {
+	config := pkg.DefaultWorkerPoolConfig()
+	if concurrency > 0 {
+		config.MaxWorkers = concurrency
+	}
+	if count > 0 && count < config.MaxWorkers {
+		config.MaxWorkers = count
+	}
+	if config.QueueSize < count {
+		config.QueueSize = count
+	}
+	if timeout > 0 {
+		config.RequestTimeout = timeout
+	}
+	return config
+}
+
+// fanOut runs task for indexes 0..count-1 through a worker pool and waits for all of them.
+// Tasks that cannot be scheduled are reported to onSubmitErr; only cancellation fails the run.
+func fanOut(ctx context.Context, k8sClient *pkg.K8sClient, config pkg.WorkerPoolConfig, count int, task func(ctx context.Context, i int) error, onSubmitErr func(i int, err error)) error {
+	if count == 0 {
+		return nil
+	}
+
+	wp := pkg.NewWorkerPoolWithContext(ctx, k8sClient, config)
+	wp.Start()
+	defer func() {
+		_ = wp.Stop()
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		err := wp.SubmitTask(func(taskCtx context.Context) error {
+			defer wg.Done()
+			return task(taskCtx, i)
+		})
+		if err != nil {
+			wg.Done()
+			onSubmitErr(i, err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // formatBytes formats bytes into human-readable format
 func formatBytes(bytes int64) string {
 	const unit = 1024